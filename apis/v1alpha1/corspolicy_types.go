@@ -0,0 +1,72 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CORSPolicyKind is the kind of the CORSPolicy resource.
+const CORSPolicyKind = "CORSPolicy"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced
+
+// CORSPolicy configures Cross-Origin Resource Sharing (CORS) for the rules of an HTTPRoute.
+// An HTTPRoute rule references a CORSPolicy in the same namespace through an ExtensionRef filter.
+type CORSPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the CORSPolicy.
+	Spec CORSPolicySpec `json:"spec"`
+}
+
+// CORSPolicySpec defines the desired state of the CORSPolicy.
+type CORSPolicySpec struct {
+	// AllowOrigins are the origins that are allowed to make cross-origin requests.
+	// An origin is either "*", which allows any origin, or of the form scheme://host[:port].
+	// For example, "https://example.com".
+	//
+	// +kubebuilder:validation:MinItems=1
+	AllowOrigins []string `json:"allowOrigins"`
+
+	// AllowMethods are the HTTP methods that are allowed in cross-origin requests.
+	// Configures the Access-Control-Allow-Methods header of the preflight responses.
+	//
+	// +optional
+	AllowMethods []string `json:"allowMethods,omitempty"`
+
+	// AllowHeaders are the HTTP request headers that are allowed in cross-origin requests.
+	// Configures the Access-Control-Allow-Headers header of the preflight responses.
+	//
+	// +optional
+	AllowHeaders []string `json:"allowHeaders,omitempty"`
+
+	// ExposeHeaders are the HTTP response headers that the browsers expose to the cross-origin requests.
+	// Configures the Access-Control-Expose-Headers header of the responses.
+	//
+	// +optional
+	ExposeHeaders []string `json:"exposeHeaders,omitempty"`
+
+	// MaxAge is the time in seconds that the browsers can cache the preflight responses.
+	// Configures the Access-Control-Max-Age header of the preflight responses.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxAge *int32 `json:"maxAge,omitempty"`
+
+	// AllowCredentials allows the cross-origin requests to include credentials.
+	// Configures the Access-Control-Allow-Credentials header of the responses.
+	// Cannot be used together with the "*" origin.
+	//
+	// +optional
+	AllowCredentials bool `json:"allowCredentials,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CORSPolicyList contains a list of CORSPolicies.
+type CORSPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CORSPolicy `json:"items"`
+}
@@ -0,0 +1,53 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DirectResponseKind is the kind of the DirectResponse resource.
+const DirectResponseKind = "DirectResponse"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced
+
+// DirectResponse configures a fixed response that NGINX returns for the requests of the rules of an HTTPRoute
+// without proxying them to the backends. An HTTPRoute rule references a DirectResponse in the same namespace
+// through an ExtensionRef filter.
+type DirectResponse struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the DirectResponse.
+	Spec DirectResponseSpec `json:"spec"`
+}
+
+// DirectResponseSpec defines the desired state of the DirectResponse.
+type DirectResponseSpec struct {
+	// StatusCode is the status code of the response. Redirect status codes (3xx) are not supported:
+	// use the RequestRedirect filter instead.
+	//
+	// +kubebuilder:validation:Minimum=200
+	// +kubebuilder:validation:Maximum=599
+	StatusCode int32 `json:"statusCode"`
+
+	// Body is the body of the response. It cannot include the "$" character.
+	//
+	// +optional
+	// +kubebuilder:validation:MaxLength=4096
+	Body string `json:"body,omitempty"`
+
+	// ContentType is the value of the Content-Type header of the response. For example, "text/html".
+	// Defaults to "text/plain".
+	//
+	// +optional
+	ContentType string `json:"contentType,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DirectResponseList contains a list of DirectResponses.
+type DirectResponseList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DirectResponse `json:"items"`
+}
@@ -0,0 +1,5 @@
+// Package v1alpha1 contains API Schema definitions for the gateway.nginx.org API group.
+//
+// +kubebuilder:object:generate=true
+// +groupName=gateway.nginx.org
+package v1alpha1
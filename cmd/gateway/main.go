@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
 	flag "github.com/spf13/pflag"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -17,6 +18,132 @@ const (
 		`Every NGINX Gateway must have a unique corresponding GatewayClass resource.`
 	gatewayCtrlNameUsageFmt = `The name of the Gateway controller. ` +
 		`The controller name must be of the form: DOMAIN/PATH. The controller's domain is '%s'`
+	nginxConfigRootUsage = `The root directory of the NGINX configuration. ` +
+		`The generated configuration files are written to its conf.d subdirectory, and the TLS secrets to its secrets ` +
+		`subdirectory.`
+	nginxConfigFilenameFormatUsage = `The format of the names of the generated configuration files. ` +
+		`It must include exactly one %s, which is replaced with the name of the config. ` +
+		`NGINX must include the files with the resulting names.`
+	nginxPIDFileUsage = `The path of the PID file of the NGINX main process, which is rendered into the generated ` +
+		`main NGINX configuration with the pid directive. NGINX Kubernetes Gateway reads it to reload NGINX.`
+	nginxTempPathUsage = `The directory under which NGINX stores the temporary files, such as the buffered request ` +
+		`bodies and proxied responses. NGINX must be able to write to it.`
+	nginxAccessLogUsage     = `The destination of the NGINX access log: /dev/stdout, off, or the absolute path of a file.`
+	nginxErrorLogUsage      = `The destination of the NGINX error log: stderr or the absolute path of a file.`
+	nginxErrorLogLevelUsage = `The level of the NGINX error log. ` +
+		`Must be one of: debug, info, notice, warn, error, crit.`
+	nginxServerTokensUsage = `Enable emitting the NGINX version in the error pages and the Server response header ` +
+		`of the generated configuration.`
+	nginxMergeSlashesUsage = `Merge two or more adjacent slashes in the URIs of the requests into a single slash ` +
+		`before NGINX matches the locations of the generated configuration.`
+	nginxAbsoluteRedirectUsage = `Make the redirects that NGINX issues, for example, when it adds a trailing slash ` +
+		`to a URI, use absolute URLs instead of relative ones.`
+	nginxHTTP3Usage = `Enable HTTP/3 over QUIC for the HTTPS listeners. NGINX must be built with the ` +
+		`ngx_http_v3_module module, and UDP port 443 of NGINX must be exposed.`
+	nginxRequestIDUsage = `Pass the X-Request-ID header of the client requests or, without it, a generated request ID ` +
+		`to the backends and return it to the clients in the X-Request-ID response header. HTTPRoutes can override it ` +
+		`with the k8s-gateway.nginx.org/request-id annotation.`
+	nginxForwardedHeadersUsage = `Pass the X-Forwarded-For, X-Forwarded-Proto, X-Forwarded-Host and X-Forwarded-Port ` +
+		`headers to the backends.`
+	nginxTrustedProxiesUsage = `The comma-separated list of the IP addresses and CIDRs of the trusted proxies, ` +
+		`such as load balancers in front of NGINX. NGINX takes the address of the client from the X-Forwarded-For ` +
+		`header of the requests from the trusted proxies. If empty, the address of the client is the address of ` +
+		`the connection.`
+	nginxResolverUsage = `The space-separated addresses (IP addresses or domain names with an optional port) ` +
+		`of the DNS servers that NGINX uses to resolve the hostnames of the ExternalName Services at run time. ` +
+		`Requires NGINX Plus or NGINX 1.27.3 or later. If empty, the ExternalName Services are not supported.`
+	nginxGeoIP2DatabaseUsage = `The absolute path of a GeoIP2 or GeoLite2 Country database in the MaxMind DB format. ` +
+		`If set, NGINX looks up the country and the continent of the clients in the database, so that HTTPRoutes ` +
+		`can match them with the k8s-gateway.nginx.org/geo-match annotation, and exposes them in the ` +
+		`$geoip2_country_code and $geoip2_continent_code variables. NGINX must be built with the ` +
+		`ngx_http_geoip2_module module, and the database must exist in the NGINX container. ` +
+		`If empty, the lookups are disabled.`
+	nginxPlusUsage = `Enable the features of the generated configuration that require NGINX Plus, such as the ` +
+		`k8s-gateway.nginx.org/slow-start annotation of the Services. NGINX must be NGINX Plus. If disabled, ` +
+		`the annotations of these features are ignored.`
+	nginxListenBacklogUsage = `The maximum length of the queue of the pending connections of the listening sockets ` +
+		`of NGINX for the HTTP and HTTPS listeners (backlog). Must be a positive integer, or 0 to use the default ` +
+		`of NGINX.`
+	nginxListenReusePortUsage = `Make NGINX create a listening socket for each worker process for the HTTP and ` +
+		`HTTPS listeners (reuseport), so that the kernel distributes the incoming connections between the worker ` +
+		`processes.`
+	nginxConfigCommentsUsage = `Emit comments above the server, location and upstream blocks of the generated ` +
+		`configuration that name the Gateway, Listener, HTTPRoute and Service that each block is generated from.`
+	requeueJitterFactorUsage = `The maximum fraction of the delay of a requeue of a failed reconciliation ` +
+		`that is randomly added to the delay, so that the requeues of resources that failed at the same time ` +
+		`are spread out. Must be in the range [0, 1]. 0 disables the jitter.`
+	nginxConfigExportAddressUsage = `The address (host:port) of the read-only HTTP endpoint ` +
+		`that serves the generated NGINX configuration at /nginx-config. If empty, the endpoint is disabled.`
+	endpointRemovalGracePeriodUsage = `The period during which the endpoints removed from an upstream stay ` +
+		`in the upstream marked as down, so that NGINX doesn't send new requests to them. 0 removes the endpoints ` +
+		`right away.`
+	maxRoutesPerListenerUsage = `The maximum number of HTTPRoutes that can attach to a listener. ` +
+		`The HTTPRoutes over the limit are not accepted. 0 means no limit.`
+	externalNameAllowlistUsage = `The comma-separated list of the external names of the ExternalName Services ` +
+		`that HTTPRoutes can reference as backends. An entry is a hostname or a wildcard hostname like *.example.com. ` +
+		`The backends with other external names are blocked. If empty, all external names are allowed.`
+	unixSocketBackendsDirUsage = `The absolute path of the directory of the Unix domain sockets that Services can ` +
+		`configure as their backends with the k8s-gateway.nginx.org/unix-socket annotation, for example, ` +
+		`a directory of a volume shared with sidecar containers. The sockets outside the directory are not allowed. ` +
+		`The directory must not overlap with /var/lib/nginx, where NGINX keeps its own sockets. ` +
+		`If empty, the Unix socket backends are disabled.`
+	nginxMaxConfigSizeUsage = `The maximum size of the generated NGINX configuration in bytes. ` +
+		`A larger configuration is not applied, and NGINX keeps running with the last applied configuration. ` +
+		`0 means no limit.`
+	noAutoReloadUsage = `Write the generated NGINX configuration without reloading NGINX. NGINX is reloaded ` +
+		`on the POST requests to the /nginx-reload endpoint at the address of the nginx-reload-address flag, ` +
+		`and the ReloadPending condition of the Gateway reports whether a reload is pending.`
+	nginxReloadAddressUsage = `The address (host:port) of the HTTP endpoint that reloads NGINX at /nginx-reload. ` +
+		`Must be set if and only if no-auto-reload is enabled.`
+	nginxWorkerShutdownTimeoutUsage = `The timeout for the graceful shutdown of the NGINX workers ` +
+		`(worker_shutdown_timeout). When set, on shutdown, NGINX Kubernetes Gateway quits NGINX gracefully ` +
+		`and waits up to the timeout for the in-flight requests to complete before it exits. 0 disables the timeout.`
+	nginxWorkerRlimitNofileUsage = `The limit of the number of open files of the NGINX worker processes ` +
+		`(worker_rlimit_nofile), rendered into the main NGINX configuration. 0 means the limit of the container.`
+	experimentalServiceImportBackendsUsage = `Experimental. Enable the backendRefs of HTTPRoutes that reference ` +
+		`multi-cluster ServiceImports (multicluster.x-k8s.io). NGINX proxies the requests to the endpoints ` +
+		`imported from other clusters. Only ServiceImports with a single port are supported.`
+	nginxSecurityHeadersUsage = `The comma-separated list of the response headers in the name=value form, ` +
+		`for example, X-Content-Type-Options=nosniff, that NGINX adds to all responses, including the error responses.`
+	healthProbeAddressUsage = `The address (host:port) of the HTTP endpoint of the readiness probe at /readyz. ` +
+		`NGINX Kubernetes Gateway is ready if the NGINX main process is running with at least one worker process. ` +
+		`If empty, the endpoint is disabled.`
+	waitForCRDsUsage = `Wait for the Gateway API and NGINX Kubernetes Gateway CRDs to be installed at startup ` +
+		`instead of exiting with an error that names the missing CRDs.`
+	nginxConfigConfigMapUsage = `The ConfigMap, in the namespace/name form, that NGINX Kubernetes Gateway writes ` +
+		`the generated NGINX configuration into instead of the conf.d and main.d subdirectories of the ` +
+		`nginx-config-root, so that NGINX that runs in a separate pod can consume it. NGINX Kubernetes Gateway ` +
+		`creates the ConfigMap if it doesn't exist and doesn't reload NGINX. If empty, the configuration is written ` +
+		`to the file system.`
+	nginxSplitClientsKeyUsage = `The NGINX variables whose values NGINX hashes to split the requests across ` +
+		`the backends of a rule by their weights (split_clients). Set a key that identifies the client, for example, ` +
+		`$remote_addr or $cookie_session, so that the requests of a client consistently go to the same backend, ` +
+		`also across reloads.`
+	informerResyncPeriodUsage = `The period of the full resyncs of the informers, which redeliver all resources ` +
+		`of the informer cache to the controllers. A resync doesn't relist the resources from the API server. ` +
+		`0 disables the periodic resyncs.`
+	conformanceModeUsage = `Reject the rules of HTTPRoutes that use unsupported features, such as unsupported match ` +
+		`types or filters, with the Accepted/False/UnsupportedValue condition instead of ignoring the features, ` +
+		`as the Gateway API conformance tests expect.`
+	auditLogUsage = `The destination of the audit log, which records the decision about every reconciled ` +
+		`resource (Upserted, Deleted, Rejected or Filtered) with its reason as a JSON object per line: ` +
+		`/dev/stdout, or the absolute path of a file, which is appended to. If empty, the audit log is disabled.`
+	reconcileCoalescingWindowUsage = `The time within which a controller skips the change of a resource if it finds ` +
+		`the resource in the same state (the same version, or deleted) as the previous reconciliation that ` +
+		`sent the change to the event loop. A newer change of the resource is never skipped. ` +
+		`0 disables the coalescing.`
+	eventSendTimeoutUsage = `The maximum time a controller waits for the event loop to receive the change of ` +
+		`a resource. If the event loop is busy or stuck for longer, the controller logs an error and requeues ` +
+		`the resource. 0 means the controller waits indefinitely.`
+	nginxStatusPortUsage = `The port on the loopback address of the NGINX server that exposes the basic status ` +
+		`of NGINX (stub_status) at /stub_status. Only the requests from the loopback address are allowed. ` +
+		`Must not be 80, 443, the port of the metrics endpoint or the port of health-probe-address. ` +
+		`If 0, the server is not generated.`
+	nginxStatusMetricsUsage = `Scrape the basic status of NGINX on every collection of the metrics and expose it ` +
+		`as Prometheus metrics. Requires nginx-status-port and NGINX running in the same pod.`
+	annotationFilterUsage = `For debugging only. Process only the HTTPRoutes with the annotation in the key=value ` +
+		`form, and ignore all other HTTPRoutes. The GatewayClass and Gateways are not filtered. ` +
+		`If empty, the filter is disabled.`
 )
 
 var (
@@ -33,6 +160,108 @@ var (
 	)
 
 	gatewayClassName = flag.String("gatewayclass", "", gatewayClassNameUsage)
+
+	nginxConfigRoot = flag.String("nginx-config-root", "/etc/nginx", nginxConfigRootUsage)
+
+	nginxConfigFilenameFormat = flag.String(
+		"nginx-config-filename-format",
+		"%s.conf",
+		nginxConfigFilenameFormatUsage,
+	)
+
+	nginxPIDFile = flag.String("nginx-pid-file", "/etc/nginx/nginx.pid", nginxPIDFileUsage)
+
+	nginxTempPath = flag.String("nginx-temp-path", "/var/lib/nginx", nginxTempPathUsage)
+
+	nginxAccessLog = flag.String("nginx-access-log", "/dev/stdout", nginxAccessLogUsage)
+
+	nginxErrorLog = flag.String("nginx-error-log", "stderr", nginxErrorLogUsage)
+
+	nginxErrorLogLevel = flag.String("nginx-error-log-level", "info", nginxErrorLogLevelUsage)
+
+	nginxServerTokens = flag.Bool("nginx-server-tokens", false, nginxServerTokensUsage)
+
+	nginxMergeSlashes = flag.Bool("nginx-merge-slashes", true, nginxMergeSlashesUsage)
+
+	nginxAbsoluteRedirect = flag.Bool("nginx-absolute-redirect", true, nginxAbsoluteRedirectUsage)
+
+	nginxHTTP3 = flag.Bool("nginx-http3", false, nginxHTTP3Usage)
+
+	nginxRequestID = flag.Bool("nginx-request-id", false, nginxRequestIDUsage)
+
+	nginxForwardedHeaders = flag.Bool("nginx-forwarded-headers", true, nginxForwardedHeadersUsage)
+
+	nginxTrustedProxies = flag.StringSlice("nginx-trusted-proxies", nil, nginxTrustedProxiesUsage)
+
+	nginxResolver = flag.String("nginx-resolver", "", nginxResolverUsage)
+
+	nginxGeoIP2Database = flag.String("nginx-geoip2-database", "", nginxGeoIP2DatabaseUsage)
+
+	nginxPlus = flag.Bool("nginx-plus", false, nginxPlusUsage)
+
+	nginxListenBacklog = flag.Int("nginx-listen-backlog", 0, nginxListenBacklogUsage)
+
+	nginxListenReusePort = flag.Bool("nginx-listen-reuseport", false, nginxListenReusePortUsage)
+
+	nginxConfigComments = flag.Bool("nginx-config-comments", false, nginxConfigCommentsUsage)
+
+	requeueJitterFactor = flag.Float64("requeue-jitter-factor", 0.1, requeueJitterFactorUsage)
+
+	nginxConfigExportAddress = flag.String("nginx-config-export-address", "", nginxConfigExportAddressUsage)
+
+	endpointRemovalGracePeriod = flag.Duration("endpoint-removal-grace-period", 0, endpointRemovalGracePeriodUsage)
+
+	maxRoutesPerListener = flag.Int("max-routes-per-listener", 0, maxRoutesPerListenerUsage)
+
+	externalNameAllowlist = flag.StringSlice("external-name-allowlist", nil, externalNameAllowlistUsage)
+
+	unixSocketBackendsDir = flag.String("unix-socket-backends-dir", "", unixSocketBackendsDirUsage)
+
+	nginxMaxConfigSize = flag.Int("nginx-max-config-size", 0, nginxMaxConfigSizeUsage)
+
+	noAutoReload = flag.Bool("no-auto-reload", false, noAutoReloadUsage)
+
+	nginxReloadAddress = flag.String("nginx-reload-address", "", nginxReloadAddressUsage)
+
+	nginxWorkerShutdownTimeout = flag.Duration(
+		"nginx-worker-shutdown-timeout",
+		0,
+		nginxWorkerShutdownTimeoutUsage,
+	)
+
+	nginxWorkerRlimitNofile = flag.Int("nginx-worker-rlimit-nofile", 0, nginxWorkerRlimitNofileUsage)
+
+	experimentalServiceImportBackends = flag.Bool(
+		"experimental-service-import-backends",
+		false,
+		experimentalServiceImportBackendsUsage,
+	)
+
+	nginxSecurityHeaders = flag.StringToString("nginx-security-headers", nil, nginxSecurityHeadersUsage)
+
+	healthProbeAddress = flag.String("health-probe-address", "", healthProbeAddressUsage)
+
+	waitForCRDs = flag.Bool("wait-for-crds", false, waitForCRDsUsage)
+
+	nginxConfigConfigMap = flag.String("nginx-config-configmap", "", nginxConfigConfigMapUsage)
+
+	annotationFilter = flag.String("annotation-filter", "", annotationFilterUsage)
+
+	nginxSplitClientsKey = flag.String("nginx-split-clients-key", "$request_id", nginxSplitClientsKeyUsage)
+
+	nginxStatusPort = flag.Int("nginx-status-port", 0, nginxStatusPortUsage)
+
+	nginxStatusMetrics = flag.Bool("nginx-status-metrics", false, nginxStatusMetricsUsage)
+
+	informerResyncPeriod = flag.Duration("informer-resync-period", 10*time.Hour, informerResyncPeriodUsage)
+
+	eventSendTimeout = flag.Duration("event-send-timeout", 0, eventSendTimeoutUsage)
+
+	conformanceMode = flag.Bool("conformance-mode", false, conformanceModeUsage)
+
+	auditLog = flag.String("audit-log", "", auditLogUsage)
+
+	reconcileCoalescingWindow = flag.Duration("reconcile-coalescing-window", 0, reconcileCoalescingWindowUsage)
 )
 
 func main() {
@@ -40,15 +269,92 @@ func main() {
 
 	logger := zap.New()
 	conf := config.Config{
-		GatewayCtlrName:  *gatewayCtlrName,
-		Logger:           logger,
-		GatewayClassName: *gatewayClassName,
+		GatewayCtlrName:                   *gatewayCtlrName,
+		Logger:                            logger,
+		GatewayClassName:                  *gatewayClassName,
+		NginxConfigRoot:                   *nginxConfigRoot,
+		NginxConfigFilenameFormat:         *nginxConfigFilenameFormat,
+		NginxPIDFile:                      *nginxPIDFile,
+		NginxTempPath:                     *nginxTempPath,
+		NginxAccessLog:                    *nginxAccessLog,
+		NginxErrorLog:                     *nginxErrorLog,
+		NginxErrorLogLevel:                *nginxErrorLogLevel,
+		NginxServerTokens:                 *nginxServerTokens,
+		NginxMergeSlashes:                 *nginxMergeSlashes,
+		NginxAbsoluteRedirect:             *nginxAbsoluteRedirect,
+		NginxHTTP3:                        *nginxHTTP3,
+		NginxRequestID:                    *nginxRequestID,
+		NginxForwardedHeaders:             *nginxForwardedHeaders,
+		NginxTrustedProxies:               *nginxTrustedProxies,
+		NginxResolver:                     *nginxResolver,
+		NginxGeoIP2Database:               *nginxGeoIP2Database,
+		NginxPlus:                         *nginxPlus,
+		NginxListenBacklog:                *nginxListenBacklog,
+		NginxListenReusePort:              *nginxListenReusePort,
+		NginxConfigComments:               *nginxConfigComments,
+		RequeueJitterFactor:               *requeueJitterFactor,
+		NginxConfigExportAddress:          *nginxConfigExportAddress,
+		EndpointRemovalGracePeriod:        *endpointRemovalGracePeriod,
+		MaxRoutesPerListener:              *maxRoutesPerListener,
+		ExternalNameAllowlist:             *externalNameAllowlist,
+		UnixSocketBackendsDir:             *unixSocketBackendsDir,
+		NginxMaxConfigSize:                *nginxMaxConfigSize,
+		NoAutoReload:                      *noAutoReload,
+		NginxReloadAddress:                *nginxReloadAddress,
+		NginxWorkerShutdownTimeout:        *nginxWorkerShutdownTimeout,
+		NginxWorkerRlimitNofile:           *nginxWorkerRlimitNofile,
+		ExperimentalServiceImportBackends: *experimentalServiceImportBackends,
+		NginxSecurityHeaders:              *nginxSecurityHeaders,
+		HealthProbeAddress:                *healthProbeAddress,
+		WaitForCRDs:                       *waitForCRDs,
+		NginxConfigConfigMap:              *nginxConfigConfigMap,
+		AnnotationFilter:                  *annotationFilter,
+		NginxSplitClientsKey:              *nginxSplitClientsKey,
+		InformerResyncPeriod:              *informerResyncPeriod,
+		NginxStatusPort:                   *nginxStatusPort,
+		NginxStatusMetrics:                *nginxStatusMetrics,
+		EventSendTimeout:                  *eventSendTimeout,
+		ConformanceMode:                   *conformanceMode,
+		AuditLog:                          *auditLog,
+		ReconcileCoalescingWindow:         *reconcileCoalescingWindow,
 	}
 
 	MustValidateArguments(
 		flag.CommandLine,
 		GatewayControllerParam(domain),
 		GatewayClassParam(),
+		NginxConfigRootParam(),
+		NginxConfigFilenameFormatParam(),
+		NginxPIDFileParam(),
+		NginxTempPathParam(),
+		NginxAccessLogParam(),
+		NginxErrorLogParam(),
+		NginxErrorLogLevelParam(),
+		NginxResolverParam(),
+		NginxGeoIP2DatabaseParam(),
+		NginxTrustedProxiesParam(),
+		RequeueJitterFactorParam(),
+		NginxConfigExportAddressParam(),
+		EndpointRemovalGracePeriodParam(),
+		MaxRoutesPerListenerParam(),
+		ExternalNameAllowlistParam(),
+		UnixSocketBackendsDirParam(),
+		NginxMaxConfigSizeParam(),
+		NginxReloadAddressParam(),
+		NginxWorkerShutdownTimeoutParam(),
+		NginxWorkerRlimitNofileParam(),
+		NginxSecurityHeadersParam(),
+		HealthProbeAddressParam(),
+		NginxConfigConfigMapParam(),
+		AnnotationFilterParam(),
+		NginxSplitClientsKeyParam(),
+		InformerResyncPeriodParam(),
+		NginxStatusPortParam(),
+		NginxStatusMetricsParam(),
+		EventSendTimeoutParam(),
+		NginxListenBacklogParam(),
+		AuditLogParam(),
+		ReconcileCoalescingWindowParam(),
 	)
 
 	logger.Info("Starting NGINX Kubernetes Gateway",
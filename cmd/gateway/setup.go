@@ -3,9 +3,13 @@ package main
 import (
 	"errors"
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	flag "github.com/spf13/pflag"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -19,6 +23,12 @@ const (
 	// nolint:lll
 	// Regex from: https://github.com/kubernetes-sigs/gateway-api/blob/e9e04e498c566021c9d30ce4dbe0863894c7d7e1/apis/v1beta1/shared_types.go#L494
 	controllerNameRegex = `^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*\/[A-Za-z0-9\/\-._~%!$&'()*+,;=:]+$` //nolint:lll
+	headerNameRegex     = `^[A-Za-z0-9-]+$`
+	// headerValueRegex matches printable ASCII characters except the ones that would break out of or be interpolated
+	// in the quoted value of the NGINX add_header directive: '"', '\' and '$'.
+	headerValueRegex = `^[ !#%-\[\]-~]+$`
+	// splitClientsKeyRegex matches a concatenation of NGINX variables.
+	splitClientsKeyRegex = `^(\$[A-Za-z0-9_]+)+$`
 )
 
 type (
@@ -92,6 +102,669 @@ func GatewayClassParam() ValidatorContext {
 	}
 }
 
+func NginxConfigRootParam() ValidatorContext {
+	name := "nginx-config-root"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetString(name)
+			if err != nil {
+				return err
+			}
+
+			if len(param) == 0 {
+				return errors.New("flag must be set")
+			}
+
+			if !filepath.IsAbs(param) {
+				return fmt.Errorf("invalid path: %s; must be an absolute path", param)
+			}
+
+			return nil
+		},
+	}
+}
+
+func NginxConfigFilenameFormatParam() ValidatorContext {
+	name := "nginx-config-filename-format"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetString(name)
+			if err != nil {
+				return err
+			}
+
+			if len(param) == 0 {
+				return errors.New("flag must be set")
+			}
+
+			// the format is used with fmt.Sprintf, so it must not include any other verbs
+			if strings.Count(param, "%s") != 1 || strings.Count(param, "%") != 1 {
+				return fmt.Errorf("invalid format: %s; must include exactly one %%s and no other %% characters", param)
+			}
+
+			if strings.ContainsRune(param, filepath.Separator) {
+				return fmt.Errorf("invalid format: %s; must not include a path separator", param)
+			}
+
+			return nil
+		},
+	}
+}
+
+func NginxAccessLogParam() ValidatorContext {
+	name := "nginx-access-log"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetString(name)
+			if err != nil {
+				return err
+			}
+
+			if param == "off" {
+				return nil
+			}
+
+			return validateRenderedPath(param)
+		},
+	}
+}
+
+func NginxErrorLogParam() ValidatorContext {
+	name := "nginx-error-log"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetString(name)
+			if err != nil {
+				return err
+			}
+
+			if param == "stderr" {
+				return nil
+			}
+
+			return validateRenderedPath(param)
+		},
+	}
+}
+
+// validateRenderedPath validates a path that is rendered into the NGINX configuration.
+func validateRenderedPath(path string) error {
+	if len(path) == 0 {
+		return errors.New("flag must be set")
+	}
+
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("invalid path: %s; must be an absolute path", path)
+	}
+
+	// the path is rendered into the NGINX configuration as is
+	if strings.ContainsAny(path, " \t\n;{}") {
+		return fmt.Errorf("invalid path: %s; must not include whitespace, ';', '{' or '}' characters", path)
+	}
+
+	return nil
+}
+
+func NginxErrorLogLevelParam() ValidatorContext {
+	name := "nginx-error-log-level"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetString(name)
+			if err != nil {
+				return err
+			}
+
+			switch param {
+			case "debug", "info", "notice", "warn", "error", "crit":
+				return nil
+			}
+
+			return fmt.Errorf("invalid level: %s; must be one of: debug, info, notice, warn, error, crit", param)
+		},
+	}
+}
+
+func NginxResolverParam() ValidatorContext {
+	name := "nginx-resolver"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetString(name)
+			if err != nil {
+				return err
+			}
+
+			for _, addr := range strings.Fields(param) {
+				if err := validateResolverAddress(addr); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+// validateResolverAddress validates an address of the NGINX resolver directive: an IP address or a domain name
+// with an optional port. IPv6 addresses must be enclosed in square brackets.
+func validateResolverAddress(addr string) error {
+	host := addr
+	if h, port, err := net.SplitHostPort(addr); err == nil {
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return fmt.Errorf("invalid address: %s; invalid port %s", addr, port)
+		}
+		host = h
+	} else if strings.HasPrefix(addr, "[") && strings.HasSuffix(addr, "]") {
+		host = strings.Trim(addr, "[]")
+	} else if strings.Contains(addr, ":") {
+		return fmt.Errorf("invalid address: %s; IPv6 addresses must be enclosed in square brackets", addr)
+	}
+
+	if net.ParseIP(host) != nil || len(validation.IsDNS1123Subdomain(host)) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("invalid address: %s; must be an IP address or a domain name with an optional port", addr)
+}
+
+func RequeueJitterFactorParam() ValidatorContext {
+	name := "requeue-jitter-factor"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetFloat64(name)
+			if err != nil {
+				return err
+			}
+
+			if param < 0 || param > 1 {
+				return fmt.Errorf("invalid factor: %v; must be in the range [0, 1]", param)
+			}
+
+			return nil
+		},
+	}
+}
+
+func NginxConfigExportAddressParam() ValidatorContext {
+	name := "nginx-config-export-address"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetString(name)
+			if err != nil {
+				return err
+			}
+
+			if param == "" {
+				return nil
+			}
+
+			return validateHostPort(param)
+		},
+	}
+}
+
+func NginxSecurityHeadersParam() ValidatorContext {
+	name := "nginx-security-headers"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetStringToString(name)
+			if err != nil {
+				return err
+			}
+
+			nameRe := regexp.MustCompile(headerNameRegex)
+			valueRe := regexp.MustCompile(headerValueRegex)
+
+			for headerName, value := range param {
+				if !nameRe.MatchString(headerName) {
+					return fmt.Errorf("invalid header name: %q; must consist of alphanumeric characters or '-'", headerName)
+				}
+
+				if !valueRe.MatchString(value) {
+					return fmt.Errorf(
+						"invalid value of header %s: %q; must be non-empty and consist of printable ASCII characters "+
+							`except '"', '\' and '$'`,
+						headerName,
+						value,
+					)
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+func HealthProbeAddressParam() ValidatorContext {
+	name := "health-probe-address"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetString(name)
+			if err != nil {
+				return err
+			}
+
+			if param == "" {
+				return nil
+			}
+
+			return validateHostPort(param)
+		},
+	}
+}
+
+func NginxReloadAddressParam() ValidatorContext {
+	name := "nginx-reload-address"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetString(name)
+			if err != nil {
+				return err
+			}
+
+			noAutoReload, err := flagset.GetBool("no-auto-reload")
+			if err != nil {
+				return err
+			}
+
+			if !noAutoReload {
+				if param != "" {
+					return errors.New("flag can only be set if --no-auto-reload is enabled")
+				}
+				return nil
+			}
+
+			if param == "" {
+				return errors.New("flag must be set if --no-auto-reload is enabled")
+			}
+
+			return validateHostPort(param)
+		},
+	}
+}
+
+func validateHostPort(addr string) error {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address: %s; must be host:port", addr)
+	}
+
+	if port == "" {
+		return fmt.Errorf("invalid address: %s; port must be set", addr)
+	}
+
+	return nil
+}
+
+func NginxWorkerRlimitNofileParam() ValidatorContext {
+	name := "nginx-worker-rlimit-nofile"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetInt(name)
+			if err != nil {
+				return err
+			}
+
+			if param < 0 {
+				return fmt.Errorf("invalid limit: %d; must be a positive integer, or 0", param)
+			}
+
+			return nil
+		},
+	}
+}
+
+func NginxWorkerShutdownTimeoutParam() ValidatorContext {
+	name := "nginx-worker-shutdown-timeout"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetDuration(name)
+			if err != nil {
+				return err
+			}
+
+			if param < 0 {
+				return fmt.Errorf("invalid timeout: %v; must not be negative", param)
+			}
+
+			// NGINX doesn't support time units smaller than milliseconds.
+			if param%time.Millisecond != 0 {
+				return fmt.Errorf("invalid timeout: %v; must be a whole number of milliseconds", param)
+			}
+
+			return nil
+		},
+	}
+}
+
+func EndpointRemovalGracePeriodParam() ValidatorContext {
+	name := "endpoint-removal-grace-period"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetDuration(name)
+			if err != nil {
+				return err
+			}
+
+			if param < 0 {
+				return fmt.Errorf("invalid grace period: %v; must not be negative", param)
+			}
+
+			return nil
+		},
+	}
+}
+
+func MaxRoutesPerListenerParam() ValidatorContext {
+	name := "max-routes-per-listener"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetInt(name)
+			if err != nil {
+				return err
+			}
+
+			if param < 0 {
+				return fmt.Errorf("invalid maximum number of routes: %d; must not be negative", param)
+			}
+
+			return nil
+		},
+	}
+}
+
+func ExternalNameAllowlistParam() ValidatorContext {
+	name := "external-name-allowlist"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetStringSlice(name)
+			if err != nil {
+				return err
+			}
+
+			for _, entry := range param {
+				var msgs []string
+				if strings.HasPrefix(entry, "*") {
+					msgs = validation.IsWildcardDNS1123Subdomain(entry)
+				} else {
+					msgs = validation.IsDNS1123Subdomain(entry)
+				}
+
+				if len(msgs) > 0 {
+					return fmt.Errorf("invalid external name: %s; %s", entry, strings.Join(msgs, "; "))
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+func NginxMaxConfigSizeParam() ValidatorContext {
+	name := "nginx-max-config-size"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetInt(name)
+			if err != nil {
+				return err
+			}
+
+			if param < 0 {
+				return fmt.Errorf("invalid maximum size: %d; must not be negative", param)
+			}
+
+			return nil
+		},
+	}
+}
+
+func NginxPIDFileParam() ValidatorContext {
+	name := "nginx-pid-file"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetString(name)
+			if err != nil {
+				return err
+			}
+
+			if len(param) == 0 {
+				return errors.New("flag must be set")
+			}
+
+			if !filepath.IsAbs(param) {
+				return fmt.Errorf("invalid path: %s; must be an absolute path", param)
+			}
+
+			return nil
+		},
+	}
+}
+
+func NginxTempPathParam() ValidatorContext {
+	name := "nginx-temp-path"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetString(name)
+			if err != nil {
+				return err
+			}
+
+			return validateRenderedPath(param)
+		},
+	}
+}
+
+func AnnotationFilterParam() ValidatorContext {
+	name := "annotation-filter"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetString(name)
+			if err != nil {
+				return err
+			}
+
+			if param == "" {
+				return nil
+			}
+
+			key, value, found := strings.Cut(param, "=")
+			if !found {
+				return fmt.Errorf("invalid annotation: %s; must be of the form key=value", param)
+			}
+
+			if msgs := validation.IsQualifiedName(key); len(msgs) > 0 {
+				return fmt.Errorf("invalid annotation key: %s; %s", key, strings.Join(msgs, "; "))
+			}
+
+			if value == "" {
+				return fmt.Errorf("invalid annotation: %s; value must be set", param)
+			}
+
+			return nil
+		},
+	}
+}
+
+func NginxConfigConfigMapParam() ValidatorContext {
+	name := "nginx-config-configmap"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetString(name)
+			if err != nil {
+				return err
+			}
+
+			if param == "" {
+				return nil
+			}
+
+			ns, cmName, found := strings.Cut(param, "/")
+			if !found {
+				return fmt.Errorf("invalid ConfigMap: %s; must be of the form namespace/name", param)
+			}
+
+			if msgs := validation.IsDNS1123Label(ns); len(msgs) > 0 {
+				return fmt.Errorf("invalid ConfigMap namespace: %s; %s", ns, strings.Join(msgs, "; "))
+			}
+
+			if msgs := validation.IsDNS1123Subdomain(cmName); len(msgs) > 0 {
+				return fmt.Errorf("invalid ConfigMap name: %s; %s", cmName, strings.Join(msgs, "; "))
+			}
+
+			// the names of the configuration files become the keys of the ConfigMap
+			if flagset.Lookup("nginx-config-filename-format") != nil {
+				format, err := flagset.GetString("nginx-config-filename-format")
+				if err != nil {
+					return err
+				}
+
+				key := fmt.Sprintf(format, "http")
+				if msgs := validation.IsConfigMapKey(key); len(msgs) > 0 {
+					return fmt.Errorf("invalid ConfigMap key: %s; the nginx-config-filename-format must result in "+
+						"valid ConfigMap keys; %s", key, strings.Join(msgs, "; "))
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+func NginxSplitClientsKeyParam() ValidatorContext {
+	name := "nginx-split-clients-key"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetString(name)
+			if err != nil {
+				return err
+			}
+
+			if !regexp.MustCompile(splitClientsKeyRegex).MatchString(param) {
+				return fmt.Errorf("invalid key: %q; must be a concatenation of NGINX variables, "+
+					"for example, $remote_addr or $cookie_session$remote_addr", param)
+			}
+
+			return nil
+		},
+	}
+}
+
+func InformerResyncPeriodParam() ValidatorContext {
+	name := "informer-resync-period"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetDuration(name)
+			if err != nil {
+				return err
+			}
+
+			if param < 0 {
+				return fmt.Errorf("invalid resync period: %v; must not be negative", param)
+			}
+
+			return nil
+		},
+	}
+}
+
+// metricsPort is the port of the metrics endpoint of NKG: the default of the runtime manager.
+const metricsPort = 8080
+
+func NginxStatusPortParam() ValidatorContext {
+	name := "nginx-status-port"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetInt(name)
+			if err != nil {
+				return err
+			}
+
+			if param == 0 {
+				return nil
+			}
+
+			if param < 1 || param > 65535 {
+				return fmt.Errorf("invalid port: %d; must be between 1 and 65535, or 0", param)
+			}
+
+			// NGINX listens on these ports for the listeners of the Gateway on all addresses.
+			if param == 80 || param == 443 {
+				return fmt.Errorf("invalid port: %d; must not be 80 or 443", param)
+			}
+
+			// NGINX runs in the same pod as NKG, so it can't listen on the ports of the endpoints of NKG.
+			if param == metricsPort {
+				return fmt.Errorf("invalid port: %d; must not be the port of the metrics endpoint", param)
+			}
+
+			healthProbeAddress, err := flagset.GetString("health-probe-address")
+			if err != nil {
+				return err
+			}
+
+			if healthProbeAddress != "" {
+				_, healthProbePort, err := net.SplitHostPort(healthProbeAddress)
+				if err == nil && healthProbePort == strconv.Itoa(param) {
+					return fmt.Errorf("invalid port: %d; must not be the port of the health-probe-address", param)
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+func NginxStatusMetricsParam() ValidatorContext {
+	name := "nginx-status-metrics"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetBool(name)
+			if err != nil {
+				return err
+			}
+
+			if !param {
+				return nil
+			}
+
+			port, err := flagset.GetInt("nginx-status-port")
+			if err != nil {
+				return err
+			}
+
+			if port == 0 {
+				return errors.New("flag can only be enabled if --nginx-status-port is set")
+			}
+
+			return nil
+		},
+	}
+}
+
 func ValidateArguments(flagset *flag.FlagSet, validators ...ValidatorContext) []string {
 	var msgs []string
 	for _, v := range validators {
@@ -120,3 +793,162 @@ func MustValidateArguments(flagset *flag.FlagSet, validators ...ValidatorContext
 		os.Exit(1)
 	}
 }
+
+func EventSendTimeoutParam() ValidatorContext {
+	name := "event-send-timeout"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetDuration(name)
+			if err != nil {
+				return err
+			}
+
+			if param < 0 {
+				return fmt.Errorf("invalid timeout: %v; must not be negative", param)
+			}
+
+			return nil
+		},
+	}
+}
+
+func NginxTrustedProxiesParam() ValidatorContext {
+	name := "nginx-trusted-proxies"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetStringSlice(name)
+			if err != nil {
+				return err
+			}
+
+			for _, entry := range param {
+				if net.ParseIP(entry) != nil {
+					continue
+				}
+
+				if _, _, err := net.ParseCIDR(entry); err != nil {
+					return fmt.Errorf("invalid trusted proxy: %q; must be an IP address or a CIDR", entry)
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+func NginxGeoIP2DatabaseParam() ValidatorContext {
+	name := "nginx-geoip2-database"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetString(name)
+			if err != nil {
+				return err
+			}
+
+			if param == "" {
+				return nil
+			}
+
+			// The database is loaded by NGINX, so NKG doesn't check that it exists: the file doesn't have to be
+			// mounted into the NKG container.
+			return validateRenderedPath(param)
+		},
+	}
+}
+
+func NginxListenBacklogParam() ValidatorContext {
+	name := "nginx-listen-backlog"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetInt(name)
+			if err != nil {
+				return err
+			}
+
+			if param < 0 {
+				return fmt.Errorf("invalid listen backlog: %d; must be a positive integer, or 0", param)
+			}
+
+			return nil
+		},
+	}
+}
+
+func AuditLogParam() ValidatorContext {
+	name := "audit-log"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetString(name)
+			if err != nil {
+				return err
+			}
+
+			if param == "" {
+				return nil
+			}
+
+			if !filepath.IsAbs(param) {
+				return fmt.Errorf("invalid path: %s; must be an absolute path", param)
+			}
+
+			return nil
+		},
+	}
+}
+
+func ReconcileCoalescingWindowParam() ValidatorContext {
+	name := "reconcile-coalescing-window"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetDuration(name)
+			if err != nil {
+				return err
+			}
+
+			if param < 0 {
+				return fmt.Errorf("invalid window: %v; must not be negative", param)
+			}
+
+			return nil
+		},
+	}
+}
+
+// nginxSocketsDir is the directory where NGINX keeps its own Unix domain sockets.
+const nginxSocketsDir = "/var/lib/nginx"
+
+func UnixSocketBackendsDirParam() ValidatorContext {
+	name := "unix-socket-backends-dir"
+	return ValidatorContext{
+		Key: name,
+		V: func(flagset *flag.FlagSet) error {
+			param, err := flagset.GetString(name)
+			if err != nil {
+				return err
+			}
+
+			if param == "" {
+				return nil
+			}
+
+			if !filepath.IsAbs(param) || filepath.Clean(param) != param {
+				return fmt.Errorf("invalid path: %s; must be a clean absolute path", param)
+			}
+
+			dir := strings.TrimSuffix(param, "/") + "/"
+			nginxDir := nginxSocketsDir + "/"
+
+			if strings.HasPrefix(nginxDir, dir) || strings.HasPrefix(dir, nginxDir) {
+				return fmt.Errorf("invalid path: %s; must not overlap with %s", param, nginxSocketsDir)
+			}
+
+			return nil
+		},
+	}
+}
@@ -262,5 +262,1338 @@ var _ = Describe("Main", func() {
 				tester(t)
 			}) // should fail with invalid name
 		}) // gatewayclass validation
+
+		Describe("nginx-config-root validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "nginx-config-root",
+					Value:            value,
+					ValidatorContext: NginxConfigRootParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.String("nginx-config-root", "", "mock nginx-config-root")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on absolute path", func() {
+				table := []testCase{
+					prepareTestCase("/etc/nginx", expectSuccess),
+					prepareTestCase("/opt/nginx/config", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on absolute path
+
+			It("should fail with empty or relative path", func() {
+				table := []testCase{
+					prepareTestCase("", expectError),
+					prepareTestCase("etc/nginx", expectError),
+				}
+				runner(table)
+			}) // should fail with empty or relative path
+		}) // nginx-config-root validation
+
+		Describe("nginx-config-filename-format validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "nginx-config-filename-format",
+					Value:            value,
+					ValidatorContext: NginxConfigFilenameFormatParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.String("nginx-config-filename-format", "", "mock nginx-config-filename-format")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid format", func() {
+				table := []testCase{
+					prepareTestCase("%s.conf", expectSuccess),
+					prepareTestCase("nkg-%s.include", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid format
+
+			It("should fail with invalid format", func() {
+				table := []testCase{
+					prepareTestCase("", expectError),
+					prepareTestCase("http.conf", expectError),
+					prepareTestCase("%s-%s.conf", expectError),
+					prepareTestCase("%s-%d.conf", expectError),
+					prepareTestCase("nkg/%s.conf", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid format
+		}) // nginx-config-filename-format validation
+
+		Describe("nginx-access-log validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "nginx-access-log",
+					Value:            value,
+					ValidatorContext: NginxAccessLogParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.String("nginx-access-log", "", "mock nginx-access-log")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid destination", func() {
+				table := []testCase{
+					prepareTestCase("/dev/stdout", expectSuccess),
+					prepareTestCase("off", expectSuccess),
+					prepareTestCase("/var/log/nginx/access.log", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid destination
+
+			It("should fail with invalid destination", func() {
+				table := []testCase{
+					prepareTestCase("", expectError),
+					prepareTestCase("stdout", expectError),
+					prepareTestCase("var/log/nginx/access.log", expectError),
+					prepareTestCase("/var/log/nginx/access.log;", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid destination
+		}) // nginx-access-log validation
+
+		Describe("nginx-error-log validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "nginx-error-log",
+					Value:            value,
+					ValidatorContext: NginxErrorLogParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.String("nginx-error-log", "", "mock nginx-error-log")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid destination", func() {
+				table := []testCase{
+					prepareTestCase("stderr", expectSuccess),
+					prepareTestCase("/var/log/nginx/error.log", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid destination
+
+			It("should fail with invalid destination", func() {
+				table := []testCase{
+					prepareTestCase("", expectError),
+					prepareTestCase("off", expectError),
+					prepareTestCase("var/log/nginx/error.log", expectError),
+					prepareTestCase("/var/log/nginx/error log", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid destination
+		}) // nginx-error-log validation
+
+		Describe("nginx-error-log-level validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "nginx-error-log-level",
+					Value:            value,
+					ValidatorContext: NginxErrorLogLevelParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.String("nginx-error-log-level", "", "mock nginx-error-log-level")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid level", func() {
+				table := []testCase{
+					prepareTestCase("debug", expectSuccess),
+					prepareTestCase("info", expectSuccess),
+					prepareTestCase("notice", expectSuccess),
+					prepareTestCase("warn", expectSuccess),
+					prepareTestCase("error", expectSuccess),
+					prepareTestCase("crit", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid level
+
+			It("should fail with invalid level", func() {
+				table := []testCase{
+					prepareTestCase("", expectError),
+					prepareTestCase("warning", expectError),
+					prepareTestCase("DEBUG", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid level
+		}) // nginx-error-log-level validation
+
+		Describe("nginx-resolver validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "nginx-resolver",
+					Value:            value,
+					ValidatorContext: NginxResolverParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.String("nginx-resolver", "", "mock nginx-resolver")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid addresses", func() {
+				table := []testCase{
+					prepareTestCase("", expectSuccess),
+					prepareTestCase("10.96.0.10", expectSuccess),
+					prepareTestCase("10.96.0.10:53", expectSuccess),
+					prepareTestCase("[::1]", expectSuccess),
+					prepareTestCase("[::1]:5353", expectSuccess),
+					prepareTestCase("kube-dns.kube-system.svc.cluster.local", expectSuccess),
+					prepareTestCase("10.96.0.10 kube-dns.kube-system.svc.cluster.local:53", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid addresses
+
+			It("should fail with invalid addresses", func() {
+				table := []testCase{
+					prepareTestCase("::1", expectError),
+					prepareTestCase("10.96.0.10:dns", expectError),
+					prepareTestCase("10.96.0.10:65536", expectError),
+					prepareTestCase("kube_dns", expectError),
+					prepareTestCase("10.96.0.10;", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid addresses
+		}) // nginx-resolver validation
+
+		Describe("requeue-jitter-factor validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "requeue-jitter-factor",
+					Value:            value,
+					ValidatorContext: RequeueJitterFactorParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.Float64("requeue-jitter-factor", 0, "mock requeue-jitter-factor")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid factor", func() {
+				table := []testCase{
+					prepareTestCase("0", expectSuccess),
+					prepareTestCase("0.1", expectSuccess),
+					prepareTestCase("1", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid factor
+
+			It("should fail with invalid factor", func() {
+				table := []testCase{
+					prepareTestCase("-0.1", expectError),
+					prepareTestCase("1.5", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid factor
+		}) // requeue-jitter-factor validation
+
+		Describe("nginx-config-export-address validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "nginx-config-export-address",
+					Value:            value,
+					ValidatorContext: NginxConfigExportAddressParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.String("nginx-config-export-address", "", "mock nginx-config-export-address")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid address", func() {
+				table := []testCase{
+					prepareTestCase("", expectSuccess),
+					prepareTestCase(":8081", expectSuccess),
+					prepareTestCase("127.0.0.1:8081", expectSuccess),
+					prepareTestCase("localhost:8081", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid address
+
+			It("should fail with invalid address", func() {
+				table := []testCase{
+					prepareTestCase("8081", expectError),
+					prepareTestCase("127.0.0.1", expectError),
+					prepareTestCase("127.0.0.1:", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid address
+		}) // nginx-config-export-address validation
+
+		Describe("nginx-security-headers validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "nginx-security-headers",
+					Value:            value,
+					ValidatorContext: NginxSecurityHeadersParam(),
+					ExpError:         expError,
+				}
+			}
+
+			// Setting a string to string flag more than once merges the values, so every case needs new flags.
+			resetFlags := func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.StringToString("nginx-security-headers", nil, "mock nginx-security-headers")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid headers", func() {
+				for _, value := range []string{
+					"X-Content-Type-Options=nosniff",
+					"X-Frame-Options=DENY,Strict-Transport-Security=max-age=31536000; includeSubDomains",
+					`Content-Security-Policy=default-src 'self'`,
+				} {
+					resetFlags()
+					runner([]testCase{prepareTestCase(value, expectSuccess)})
+				}
+			}) // should succeed on valid headers
+
+			It("should fail with invalid headers", func() {
+				for _, value := range []string{
+					"X-Frame-Options=",
+					"X_Frame_Options=DENY",
+					"X-Frame Options=DENY",
+					`X-Custom=a"b`,
+					`X-Custom=$host`,
+					`X-Custom=a\b`,
+				} {
+					resetFlags()
+					runner([]testCase{prepareTestCase(value, expectError)})
+				}
+			}) // should fail with invalid headers
+		}) // nginx-security-headers validation
+
+		Describe("health-probe-address validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "health-probe-address",
+					Value:            value,
+					ValidatorContext: HealthProbeAddressParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.String("health-probe-address", "", "mock health-probe-address")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid address", func() {
+				table := []testCase{
+					prepareTestCase("", expectSuccess),
+					prepareTestCase(":8081", expectSuccess),
+					prepareTestCase("127.0.0.1:8081", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid address
+
+			It("should fail with invalid address", func() {
+				table := []testCase{
+					prepareTestCase("8081", expectError),
+					prepareTestCase("127.0.0.1:", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid address
+		}) // health-probe-address validation
+
+		Describe("nginx-reload-address validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "nginx-reload-address",
+					Value:            value,
+					ValidatorContext: NginxReloadAddressParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.String("nginx-reload-address", "", "mock nginx-reload-address")
+				_ = mockFlags.Bool("no-auto-reload", false, "mock no-auto-reload")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			When("no-auto-reload is disabled", func() {
+				It("should succeed on empty address", func() {
+					table := []testCase{
+						prepareTestCase("", expectSuccess),
+					}
+					runner(table)
+				}) // should succeed on empty address
+
+				It("should fail with address", func() {
+					table := []testCase{
+						prepareTestCase(":8082", expectError),
+					}
+					runner(table)
+				}) // should fail with address
+			}) // no-auto-reload is disabled
+
+			When("no-auto-reload is enabled", func() {
+				BeforeEach(func() {
+					err := mockFlags.Set("no-auto-reload", "true")
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("should succeed on valid address", func() {
+					table := []testCase{
+						prepareTestCase(":8082", expectSuccess),
+						prepareTestCase("127.0.0.1:8082", expectSuccess),
+						prepareTestCase("localhost:8082", expectSuccess),
+					}
+					runner(table)
+				}) // should succeed on valid address
+
+				It("should fail with empty or invalid address", func() {
+					table := []testCase{
+						prepareTestCase("", expectError),
+						prepareTestCase("8082", expectError),
+						prepareTestCase("127.0.0.1", expectError),
+						prepareTestCase("127.0.0.1:", expectError),
+					}
+					runner(table)
+				}) // should fail with empty or invalid address
+			}) // no-auto-reload is enabled
+		}) // nginx-reload-address validation
+
+		Describe("nginx-worker-shutdown-timeout validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "nginx-worker-shutdown-timeout",
+					Value:            value,
+					ValidatorContext: NginxWorkerShutdownTimeoutParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.Duration("nginx-worker-shutdown-timeout", 0, "mock nginx-worker-shutdown-timeout")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid timeout", func() {
+				table := []testCase{
+					prepareTestCase("0", expectSuccess),
+					prepareTestCase("30s", expectSuccess),
+					prepareTestCase("1500ms", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid timeout
+
+			It("should fail with invalid timeout", func() {
+				table := []testCase{
+					prepareTestCase("-1s", expectError),
+					prepareTestCase("1500us", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid timeout
+		}) // nginx-worker-shutdown-timeout validation
+
+		Describe("nginx-worker-rlimit-nofile validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "nginx-worker-rlimit-nofile",
+					Value:            value,
+					ValidatorContext: NginxWorkerRlimitNofileParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.Int("nginx-worker-rlimit-nofile", 0, "mock nginx-worker-rlimit-nofile")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid limit", func() {
+				table := []testCase{
+					prepareTestCase("0", expectSuccess),
+					prepareTestCase("65536", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid limit
+
+			It("should fail with invalid limit", func() {
+				table := []testCase{
+					prepareTestCase("-1", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid limit
+		}) // nginx-worker-rlimit-nofile validation
+
+		Describe("endpoint-removal-grace-period validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "endpoint-removal-grace-period",
+					Value:            value,
+					ValidatorContext: EndpointRemovalGracePeriodParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.Duration("endpoint-removal-grace-period", 0, "mock endpoint-removal-grace-period")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid grace period", func() {
+				table := []testCase{
+					prepareTestCase("0", expectSuccess),
+					prepareTestCase("30s", expectSuccess),
+					prepareTestCase("1m", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid grace period
+
+			It("should fail with invalid grace period", func() {
+				table := []testCase{
+					prepareTestCase("-1s", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid grace period
+		}) // endpoint-removal-grace-period validation
+
+		Describe("max-routes-per-listener validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "max-routes-per-listener",
+					Value:            value,
+					ValidatorContext: MaxRoutesPerListenerParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.Int("max-routes-per-listener", 0, "mock max-routes-per-listener")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid maximum", func() {
+				table := []testCase{
+					prepareTestCase("0", expectSuccess),
+					prepareTestCase("1", expectSuccess),
+					prepareTestCase("100", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid maximum
+
+			It("should fail with invalid maximum", func() {
+				table := []testCase{
+					prepareTestCase("-1", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid maximum
+		}) // max-routes-per-listener validation
+
+		Describe("external-name-allowlist validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "external-name-allowlist",
+					Value:            value,
+					ValidatorContext: ExternalNameAllowlistParam(),
+					ExpError:         expError,
+				}
+			}
+
+			// Setting a string slice flag more than once appends the values, so every case needs new flags.
+			resetFlags := func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.StringSlice("external-name-allowlist", nil, "mock external-name-allowlist")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid external names", func() {
+				for _, value := range []string{
+					"api.example.com",
+					"api.example.com,*.internal.example.org",
+				} {
+					resetFlags()
+					runner([]testCase{prepareTestCase(value, expectSuccess)})
+				}
+			}) // should succeed on valid external names
+
+			It("should fail with invalid external names", func() {
+				for _, value := range []string{
+					"api.example.com,",
+					"api_example.com",
+					"*example.com",
+					"api.*.example.com",
+				} {
+					resetFlags()
+					runner([]testCase{prepareTestCase(value, expectError)})
+				}
+			}) // should fail with invalid external names
+		}) // external-name-allowlist validation
+
+		Describe("nginx-max-config-size validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "nginx-max-config-size",
+					Value:            value,
+					ValidatorContext: NginxMaxConfigSizeParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.Int("nginx-max-config-size", 0, "mock nginx-max-config-size")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid maximum size", func() {
+				table := []testCase{
+					prepareTestCase("0", expectSuccess),
+					prepareTestCase("1048576", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid maximum size
+
+			It("should fail with invalid maximum size", func() {
+				table := []testCase{
+					prepareTestCase("-1", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid maximum size
+		}) // nginx-max-config-size validation
+
+		Describe("nginx-pid-file validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "nginx-pid-file",
+					Value:            value,
+					ValidatorContext: NginxPIDFileParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.String("nginx-pid-file", "", "mock nginx-pid-file")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on absolute path", func() {
+				table := []testCase{
+					prepareTestCase("/etc/nginx/nginx.pid", expectSuccess),
+					prepareTestCase("/var/run/nginx/nginx.pid", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on absolute path
+
+			It("should fail with empty or relative path", func() {
+				table := []testCase{
+					prepareTestCase("", expectError),
+					prepareTestCase("nginx.pid", expectError),
+				}
+				runner(table)
+			}) // should fail with empty or relative path
+		}) // nginx-pid-file validation
+
+		Describe("nginx-temp-path validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "nginx-temp-path",
+					Value:            value,
+					ValidatorContext: NginxTempPathParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.String("nginx-temp-path", "", "mock nginx-temp-path")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on absolute path", func() {
+				table := []testCase{
+					prepareTestCase("/var/lib/nginx", expectSuccess),
+					prepareTestCase("/tmp/nginx", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on absolute path
+
+			It("should fail with invalid path", func() {
+				table := []testCase{
+					prepareTestCase("", expectError),
+					prepareTestCase("var/lib/nginx", expectError),
+					prepareTestCase("/var/lib/nginx;", expectError),
+					prepareTestCase("/var/lib/my nginx", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid path
+		}) // nginx-temp-path validation
+
+		Describe("annotation-filter validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "annotation-filter",
+					Value:            value,
+					ValidatorContext: AnnotationFilterParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.String("annotation-filter", "", "mock annotation-filter")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid annotation", func() {
+				table := []testCase{
+					prepareTestCase("", expectSuccess),
+					prepareTestCase("debug=true", expectSuccess),
+					prepareTestCase("example.com/debug=route-1", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid annotation
+
+			It("should fail with invalid annotation", func() {
+				table := []testCase{
+					prepareTestCase("debug", expectError),
+					prepareTestCase("=true", expectError),
+					prepareTestCase("debug=", expectError),
+					prepareTestCase("invalid key=true", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid annotation
+		}) // annotation-filter validation
+
+		Describe("nginx-config-configmap validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "nginx-config-configmap",
+					Value:            value,
+					ValidatorContext: NginxConfigConfigMapParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.String("nginx-config-configmap", "", "mock nginx-config-configmap")
+				_ = mockFlags.String("nginx-config-filename-format", "%s.conf", "mock nginx-config-filename-format")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid ConfigMap", func() {
+				table := []testCase{
+					prepareTestCase("", expectSuccess),
+					prepareTestCase("nginx-gateway/nginx-config", expectSuccess),
+					prepareTestCase("default/nginx.config", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid ConfigMap
+
+			It("should fail with invalid ConfigMap", func() {
+				table := []testCase{
+					prepareTestCase("nginx-config", expectError),
+					prepareTestCase("/nginx-config", expectError),
+					prepareTestCase("nginx-gateway/", expectError),
+					prepareTestCase("nginx.gateway/nginx-config", expectError),
+					prepareTestCase("nginx-gateway/Nginx_Config", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid ConfigMap
+
+			It("should fail with a filename format that results in invalid keys", func() {
+				err := mockFlags.Set("nginx-config-filename-format", "nkg %s.conf")
+				Expect(err).ToNot(HaveOccurred())
+
+				runner([]testCase{prepareTestCase("nginx-gateway/nginx-config", expectError)})
+			}) // should fail with a filename format that results in invalid keys
+		}) // nginx-config-configmap validation
+
+		Describe("nginx-split-clients-key validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "nginx-split-clients-key",
+					Value:            value,
+					ValidatorContext: NginxSplitClientsKeyParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.String("nginx-split-clients-key", "$request_id", "mock nginx-split-clients-key")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid keys", func() {
+				table := []testCase{
+					prepareTestCase("$request_id", expectSuccess),
+					prepareTestCase("$remote_addr", expectSuccess),
+					prepareTestCase("$cookie_session$remote_addr", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid keys
+
+			It("should fail with invalid keys", func() {
+				table := []testCase{
+					prepareTestCase("", expectError),
+					prepareTestCase("remote_addr", expectError),
+					prepareTestCase("$remote_addr client", expectError),
+					prepareTestCase("$remote_addr;", expectError),
+					prepareTestCase("${remote_addr}", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid keys
+		}) // nginx-split-clients-key validation
+
+		Describe("informer-resync-period validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "informer-resync-period",
+					Value:            value,
+					ValidatorContext: InformerResyncPeriodParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.Duration("informer-resync-period", 0, "mock informer-resync-period")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid resync period", func() {
+				table := []testCase{
+					prepareTestCase("0", expectSuccess),
+					prepareTestCase("30m", expectSuccess),
+					prepareTestCase("10h", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid resync period
+
+			It("should fail with invalid resync period", func() {
+				table := []testCase{
+					prepareTestCase("-1m", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid resync period
+		}) // informer-resync-period validation
+
+		Describe("nginx-status-port validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "nginx-status-port",
+					Value:            value,
+					ValidatorContext: NginxStatusPortParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.Int("nginx-status-port", 0, "mock nginx-status-port")
+				_ = mockFlags.String("health-probe-address", "", "mock health-probe-address")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid port", func() {
+				table := []testCase{
+					prepareTestCase("0", expectSuccess),
+					prepareTestCase("8081", expectSuccess),
+					prepareTestCase("65535", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid port
+
+			It("should fail with invalid port", func() {
+				table := []testCase{
+					prepareTestCase("-1", expectError),
+					prepareTestCase("65536", expectError),
+					prepareTestCase("80", expectError),
+					prepareTestCase("443", expectError),
+					prepareTestCase("8080", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid port
+
+			When("health-probe-address is set", func() {
+				BeforeEach(func() {
+					err := mockFlags.Set("health-probe-address", ":8081")
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("should succeed on another port", func() {
+					table := []testCase{
+						prepareTestCase("8082", expectSuccess),
+					}
+					runner(table)
+				}) // should succeed on another port
+
+				It("should fail on the port of the health probe", func() {
+					table := []testCase{
+						prepareTestCase("8081", expectError),
+					}
+					runner(table)
+				}) // should fail on the port of the health probe
+			}) // health-probe-address is set
+		}) // nginx-status-port validation
+
+		Describe("nginx-status-metrics validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "nginx-status-metrics",
+					Value:            value,
+					ValidatorContext: NginxStatusMetricsParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.Bool("nginx-status-metrics", false, "mock nginx-status-metrics")
+				_ = mockFlags.Int("nginx-status-port", 0, "mock nginx-status-port")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			When("nginx-status-port is not set", func() {
+				It("should succeed when disabled", func() {
+					table := []testCase{
+						prepareTestCase("false", expectSuccess),
+					}
+					runner(table)
+				}) // should succeed when disabled
+
+				It("should fail when enabled", func() {
+					table := []testCase{
+						prepareTestCase("true", expectError),
+					}
+					runner(table)
+				}) // should fail when enabled
+			}) // nginx-status-port is not set
+
+			When("nginx-status-port is set", func() {
+				BeforeEach(func() {
+					err := mockFlags.Set("nginx-status-port", "8081")
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("should succeed", func() {
+					table := []testCase{
+						prepareTestCase("false", expectSuccess),
+						prepareTestCase("true", expectSuccess),
+					}
+					runner(table)
+				}) // should succeed
+			}) // nginx-status-port is set
+		}) // nginx-status-metrics validation
+
+		Describe("event-send-timeout validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "event-send-timeout",
+					Value:            value,
+					ValidatorContext: EventSendTimeoutParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.Duration("event-send-timeout", 0, "mock event-send-timeout")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid timeout", func() {
+				table := []testCase{
+					prepareTestCase("0", expectSuccess),
+					prepareTestCase("30s", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid timeout
+
+			It("should fail with invalid timeout", func() {
+				table := []testCase{
+					prepareTestCase("-1s", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid timeout
+		}) // event-send-timeout validation
+
+		Describe("nginx-trusted-proxies validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "nginx-trusted-proxies",
+					Value:            value,
+					ValidatorContext: NginxTrustedProxiesParam(),
+					ExpError:         expError,
+				}
+			}
+
+			// Setting a string slice flag more than once appends the values, so every case needs new flags.
+			resetFlags := func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.StringSlice("nginx-trusted-proxies", nil, "mock nginx-trusted-proxies")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid trusted proxies", func() {
+				for _, value := range []string{
+					"10.0.0.1",
+					"10.0.0.0/8,192.168.1.10",
+					"2001:db8::/32,::1",
+				} {
+					resetFlags()
+					runner([]testCase{prepareTestCase(value, expectSuccess)})
+				}
+			}) // should succeed on valid trusted proxies
+
+			It("should fail with invalid trusted proxies", func() {
+				for _, value := range []string{
+					"10.0.0.0/8,",
+					"10.0.0.0/33",
+					"lb.example.com",
+					"10.0.0.1;",
+				} {
+					resetFlags()
+					runner([]testCase{prepareTestCase(value, expectError)})
+				}
+			}) // should fail with invalid trusted proxies
+		}) // nginx-trusted-proxies validation
+
+		Describe("nginx-geoip2-database validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "nginx-geoip2-database",
+					Value:            value,
+					ValidatorContext: NginxGeoIP2DatabaseParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.String("nginx-geoip2-database", "", "mock nginx-geoip2-database")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid database path", func() {
+				table := []testCase{
+					prepareTestCase("", expectSuccess),
+					// the database is loaded by NGINX, so it doesn't have to exist in the NKG container
+					prepareTestCase("/etc/nginx/geoip2/GeoLite2-Country.mmdb", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid database path
+
+			It("should fail with invalid database path", func() {
+				table := []testCase{
+					prepareTestCase("GeoLite2-Country.mmdb", expectError),
+					prepareTestCase("/etc/nginx/geoip2/GeoLite2 Country.mmdb", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid database path
+		}) // nginx-geoip2-database validation
+
+		Describe("nginx-listen-backlog validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "nginx-listen-backlog",
+					Value:            value,
+					ValidatorContext: NginxListenBacklogParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.Int("nginx-listen-backlog", 0, "mock nginx-listen-backlog")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid backlog", func() {
+				table := []testCase{
+					prepareTestCase("0", expectSuccess),
+					prepareTestCase("1", expectSuccess),
+					prepareTestCase("4096", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid backlog
+
+			It("should fail with invalid backlog", func() {
+				table := []testCase{
+					prepareTestCase("-1", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid backlog
+		}) // nginx-listen-backlog validation
+
+		Describe("audit-log validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "audit-log",
+					Value:            value,
+					ValidatorContext: AuditLogParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.String("audit-log", "", "mock audit-log")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid destinations", func() {
+				table := []testCase{
+					prepareTestCase("", expectSuccess),
+					prepareTestCase("/dev/stdout", expectSuccess),
+					prepareTestCase("/var/log/nkg/audit.log", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid destinations
+
+			It("should fail with relative paths", func() {
+				table := []testCase{
+					prepareTestCase("audit.log", expectError),
+					prepareTestCase("./audit.log", expectError),
+				}
+				runner(table)
+			}) // should fail with relative paths
+		}) // audit-log validation
+
+		Describe("reconcile-coalescing-window validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "reconcile-coalescing-window",
+					Value:            value,
+					ValidatorContext: ReconcileCoalescingWindowParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.Duration("reconcile-coalescing-window", 0, "mock reconcile-coalescing-window")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid window", func() {
+				table := []testCase{
+					prepareTestCase("0", expectSuccess),
+					prepareTestCase("500ms", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid window
+
+			It("should fail with invalid window", func() {
+				table := []testCase{
+					prepareTestCase("-1s", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid window
+		}) // reconcile-coalescing-window validation
+
+		Describe("unix-socket-backends-dir validation", func() {
+			prepareTestCase := func(value string, expError bool) testCase {
+				return testCase{
+					Flag:             "unix-socket-backends-dir",
+					Value:            value,
+					ValidatorContext: UnixSocketBackendsDirParam(),
+					ExpError:         expError,
+				}
+			}
+
+			BeforeEach(func() {
+				mockFlags = flag.NewFlagSet("mock", flag.PanicOnError)
+				_ = mockFlags.String("unix-socket-backends-dir", "", "mock unix-socket-backends-dir")
+				err := mockFlags.Parse([]string{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+			AfterEach(func() {
+				mockFlags = nil
+			})
+
+			It("should succeed on valid directories", func() {
+				table := []testCase{
+					prepareTestCase("", expectSuccess),
+					prepareTestCase("/var/run/sidecars", expectSuccess),
+					prepareTestCase("/var/lib/nginx-sidecars", expectSuccess),
+				}
+				runner(table)
+			}) // should succeed on valid directories
+
+			It("should fail with invalid directories", func() {
+				table := []testCase{
+					prepareTestCase("sidecars", expectError),
+					prepareTestCase("/var/run/../sidecars", expectError),
+					prepareTestCase("/var/run/sidecars/", expectError),
+					prepareTestCase("/", expectError),
+					prepareTestCase("/var/lib", expectError),
+					prepareTestCase("/var/lib/nginx", expectError),
+					prepareTestCase("/var/lib/nginx/sidecars", expectError),
+				}
+				runner(table)
+			}) // should fail with invalid directories
+		}) // unix-socket-backends-dir validation
 	}) // CLI argument validation
 }) // end Main
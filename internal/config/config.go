@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -13,4 +15,124 @@ type Config struct {
 	GatewayNsName types.NamespacedName
 	// GatewayClassName is the name of the GatewayClass resource that the Gateway will use.
 	GatewayClassName string
+	// NginxConfigRoot is the root directory of the NGINX configuration. The generated configuration files and
+	// the TLS secrets are located under it.
+	NginxConfigRoot string
+	// NginxConfigFilenameFormat is the format of the names of the generated configuration files.
+	// It includes exactly one %s verb, which is replaced with the name of the config.
+	NginxConfigFilenameFormat string
+	// NginxPIDFile is the path of the PID file of the NGINX main process.
+	NginxPIDFile string
+	// NginxTempPath is the directory under which NGINX stores the temporary files.
+	NginxTempPath string
+	// NginxAccessLog is the destination of the NGINX access log for the generated configuration.
+	NginxAccessLog string
+	// NginxErrorLog is the destination of the NGINX error log for the generated configuration.
+	NginxErrorLog string
+	// NginxErrorLogLevel is the level of the NGINX error log for the generated configuration.
+	NginxErrorLogLevel string
+	// NginxServerTokens enables emitting the NGINX version in the error pages and the Server response header.
+	NginxServerTokens bool
+	// NginxMergeSlashes enables merging adjacent slashes in the URIs of the requests before the location matching.
+	NginxMergeSlashes bool
+	// NginxAbsoluteRedirect makes the redirects that NGINX issues use absolute URLs.
+	NginxAbsoluteRedirect bool
+	// NginxHTTP3 enables HTTP/3 over QUIC for the HTTPS listeners.
+	NginxHTTP3 bool
+	// NginxRequestID enables the propagation of the request ID in the X-Request-ID header to the backends and
+	// the clients.
+	NginxRequestID bool
+	// NginxForwardedHeaders enables passing the X-Forwarded-For, X-Forwarded-Proto, X-Forwarded-Host and
+	// X-Forwarded-Port headers to the backends.
+	NginxForwardedHeaders bool
+	// NginxTrustedProxies are the addresses and CIDRs of the trusted proxies, whose X-Forwarded-For header NGINX uses
+	// to determine the address of the client. Empty means no trusted proxies.
+	NginxTrustedProxies []string
+	// NginxResolver is the space-separated addresses of the DNS servers that NGINX uses to resolve the hostnames of
+	// the ExternalName Services. If empty, the upstreams of the ExternalName Services fail the requests with 502.
+	NginxResolver string
+	// NginxPlus enables the features of the generated configuration that require NGINX Plus.
+	NginxPlus bool
+	// NginxListenBacklog is the maximum length of the queue of the pending connections of the listening sockets
+	// of NGINX. 0 means the default of NGINX.
+	NginxListenBacklog int
+	// NginxListenReusePort enables the reuseport parameter of the listening sockets of NGINX.
+	NginxListenReusePort bool
+	// NginxGeoIP2Database is the path of the GeoIP2 database in which NGINX looks up the country and the continent
+	// of the clients. Empty means the lookups are disabled.
+	NginxGeoIP2Database string
+	// NginxConfigComments enables emitting the comments that map the blocks of the generated configuration back to
+	// the resources that they are generated from.
+	NginxConfigComments bool
+	// RequeueJitterFactor is the maximum fraction of the delay of a requeue of a failed reconciliation that is
+	// randomly added to the delay.
+	RequeueJitterFactor float64
+	// NginxConfigExportAddress is the address of the endpoint that serves the generated NGINX configuration.
+	// If empty, the endpoint is disabled.
+	NginxConfigExportAddress string
+	// EndpointRemovalGracePeriod is the period during which the endpoints removed from an upstream stay in the upstream
+	// marked as down. 0 means the endpoints are removed right away.
+	EndpointRemovalGracePeriod time.Duration
+	// MaxRoutesPerListener is the maximum number of HTTPRoutes that can attach to a listener. 0 means no limit.
+	MaxRoutesPerListener int
+	// ExternalNameAllowlist is the list of the external names of the ExternalName Services that HTTPRoutes can
+	// reference as backends. An entry is a hostname or a wildcard hostname like *.example.com. Empty means no limit.
+	ExternalNameAllowlist []string
+	// UnixSocketBackendsDir is the directory of the Unix domain sockets that Services can configure as their
+	// backends. Empty means the Unix socket backends are disabled.
+	UnixSocketBackendsDir string
+	// NginxMaxConfigSize is the maximum size of the generated NGINX configuration in bytes. A larger configuration
+	// isn't applied, and NGINX keeps running with the last applied one. 0 means no limit.
+	NginxMaxConfigSize int
+	// NoAutoReload disables reloading NGINX after writing the generated configuration. NGINX is reloaded on the requests
+	// to the reload endpoint at NginxReloadAddress.
+	NoAutoReload bool
+	// NginxReloadAddress is the address of the endpoint that reloads NGINX. Only used if NoAutoReload is true.
+	NginxReloadAddress string
+	// NginxWorkerShutdownTimeout is the timeout for the graceful shutdown of the NGINX workers. When set, NKG renders it
+	// into the main NGINX configuration, and on shutdown, quits NGINX gracefully and waits for it to exit.
+	// 0 means no timeout and no waiting.
+	NginxWorkerShutdownTimeout time.Duration
+	// NginxWorkerRlimitNofile is the limit of the number of open files of the NGINX workers, rendered into the main
+	// NGINX configuration. 0 means the limit of the container.
+	NginxWorkerRlimitNofile int
+	// ExperimentalServiceImportBackends enables the backendRefs of multi-cluster ServiceImports.
+	ExperimentalServiceImportBackends bool
+	// NginxSecurityHeaders are the names and values of the response headers that NGINX adds to all responses,
+	// including the error responses. Empty means no headers.
+	NginxSecurityHeaders map[string]string
+	// HealthProbeAddress is the address of the endpoint of the readiness probe, which checks the health of the NGINX
+	// processes. If empty, the endpoint is disabled.
+	HealthProbeAddress string
+	// WaitForCRDs makes NKG wait for the CRDs of the watched resources to be installed at startup instead of exiting.
+	WaitForCRDs bool
+	// NginxConfigConfigMap is the ConfigMap, in the namespace/name form, that the generated NGINX configuration is
+	// written into instead of the file system. NKG doesn't reload NGINX in that case. If empty, the configuration is
+	// written to the file system.
+	NginxConfigConfigMap string
+	// AnnotationFilter is the annotation, in the key=value form, that the HTTPRoutes must have to be processed.
+	// Meant for debugging only. If empty, all HTTPRoutes are processed.
+	AnnotationFilter string
+	// NginxSplitClientsKey is the NGINX variables whose values NGINX hashes to split the requests across the backends
+	// by their weights.
+	NginxSplitClientsKey string
+	// NginxStatusPort is the port on the loopback address of the NGINX server that exposes the basic status of NGINX.
+	// 0 means the server is not generated.
+	NginxStatusPort int
+	// NginxStatusMetrics enables scraping the basic status of NGINX and exposing it as metrics.
+	NginxStatusMetrics bool
+	// InformerResyncPeriod is the period of the full resyncs of the informers. 0 disables the periodic resyncs.
+	InformerResyncPeriod time.Duration
+	// EventSendTimeout is the maximum time a reconciler waits for the event loop to receive an event before it
+	// requeues the resource. 0 means no timeout.
+	EventSendTimeout time.Duration
+	// ConformanceMode makes NKG reject the rules of HTTPRoutes that use unsupported features instead of ignoring
+	// the features, as the Gateway API conformance tests expect.
+	ConformanceMode bool
+	// AuditLog is the destination of the audit log of the decisions of the reconcilers: /dev/stdout or the path
+	// of a file. Empty means the audit log is disabled.
+	AuditLog string
+	// ReconcileCoalescingWindow is the time within which a reconciler skips the events of the reconciliations that
+	// find a resource in the same state as the previous reconciliation that sent an event. 0 disables the coalescing.
+	ReconcileCoalescingWindow time.Duration
 }
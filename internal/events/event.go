@@ -22,3 +22,14 @@ type DeleteEvent struct {
 	// NamespacedName is the namespace & name of the deleted resource.
 	NamespacedName types.NamespacedName
 }
+
+// ProcessEvent represents a request to process the changes even if no resources changed.
+// For example, the ChangeProcessor requests processing when the grace period of a draining endpoint expires.
+type ProcessEvent struct{}
+
+// ReloadEvent represents a request to reload NGINX, so that it applies the written configuration, when automatic
+// reloading is disabled.
+type ReloadEvent struct {
+	// ResultCh receives the result of the reload. It must be buffered, so that sending the result doesn't block.
+	ResultCh chan<- error
+}
@@ -3,16 +3,21 @@ package events
 import (
 	"context"
 	"fmt"
+	"regexp"
 
 	"github.com/go-logr/logr"
 	apiv1 "k8s.io/api/core/v1"
 	discoveryV1 "k8s.io/api/discovery/v1"
 	"sigs.k8s.io/gateway-api/apis/v1beta1"
 
+	"github.com/nginxinc/nginx-kubernetes-gateway/apis/v1alpha1"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/metrics"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/export"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/file"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/runtime"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/conditions"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/secrets"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/status"
@@ -45,6 +50,21 @@ type EventHandlerConfig struct {
 	StatusUpdater status.Updater
 	// Logger is the logger to be used by the EventHandler.
 	Logger logr.Logger
+	// ConfigStore stores the NGINX configuration after NGINX successfully reloads it. Can be nil.
+	ConfigStore *export.Store
+	// MetricsCollector collects the size and the numbers of the blocks of the generated NGINX configuration.
+	// Can be nil.
+	MetricsCollector *metrics.ConfigCollector
+	// MaxConfigSize is the maximum size of the generated NGINX configuration in bytes. The EventHandler doesn't
+	// apply a larger configuration, so that NGINX keeps running with the last applied one. 0 means no limit.
+	MaxConfigSize int
+	// ManualReload disables reloading NGINX after writing the configuration. Instead, NGINX is reloaded when
+	// the EventHandler handles a ReloadEvent, and the status of the Gateway reports whether a reload is pending.
+	ManualReload bool
+	// NoReload disables reloading NGINX after writing the configuration, because the NGINX that consumes
+	// the configuration runs elsewhere, for example, in a separate pod that reads it from a ConfigMap.
+	// The EventHandler doesn't coordinate the reloads of such NGINX.
+	NoReload bool
 }
 
 // EventHandlerImpl implements EventHandler.
@@ -52,6 +72,11 @@ type EventHandlerConfig struct {
 // (1) Reconciling the Gateway API and Kubernetes built-in resources with the NGINX configuration.
 // (2) Keeping the statuses of the Gateway API resources updated.
 type EventHandlerImpl struct {
+	// pendingCfg is the configuration that is written but not yet applied by NGINX. Only used for the manual reload.
+	pendingCfg []byte
+	// lastStatuses are the latest statuses, which the EventHandler updates after a manual reload.
+	lastStatuses *state.Statuses
+
 	cfg EventHandlerConfig
 }
 
@@ -63,34 +88,137 @@ func NewEventHandlerImpl(cfg EventHandlerConfig) *EventHandlerImpl {
 }
 
 func (h *EventHandlerImpl) HandleEventBatch(ctx context.Context, batch EventBatch) {
+	var reloadEvents []*ReloadEvent
+
 	for _, event := range batch {
 		switch e := event.(type) {
 		case *UpsertEvent:
 			h.propagateUpsert(e)
 		case *DeleteEvent:
 			h.propagateDelete(e)
+		case *ProcessEvent:
+			// Nothing to propagate: the Processor knows what to process.
+		case *ReloadEvent:
+			reloadEvents = append(reloadEvents, e)
 		default:
 			panic(fmt.Errorf("unknown event type %T", e))
 		}
 	}
 
 	changed, conf, statuses := h.cfg.Processor.Process(ctx)
-	if !changed {
+	if changed {
+		h.handleChanges(ctx, conf, statuses)
+	} else {
 		h.cfg.Logger.Info("Handling events didn't result into NGINX configuration changes")
-		return
 	}
 
+	// We reload NGINX after handling the changes, so that NGINX applies the latest written configuration.
+	if len(reloadEvents) > 0 {
+		err := h.reloadNginx(ctx)
+
+		for _, e := range reloadEvents {
+			e.ResultCh <- err
+		}
+	}
+}
+
+func (h *EventHandlerImpl) handleChanges(ctx context.Context, conf dataplane.Configuration, statuses state.Statuses) {
 	err := h.updateNginx(ctx, conf)
-	if err != nil {
+
+	switch {
+	case err != nil:
 		h.cfg.Logger.Error(err, "Failed to update NGINX configuration")
-	} else {
+	case h.cfg.ManualReload:
+		h.cfg.Logger.Info("NGINX configuration was successfully written; NGINX must be reloaded manually to apply it")
+	default:
 		h.cfg.Logger.Info("NGINX configuration was successfully updated")
 	}
 
+	h.updateStatuses(ctx, statuses)
+}
+
+// updateStatuses updates the statuses. For the manual reload, it also reports in the status of the Gateway
+// whether a reload is pending.
+func (h *EventHandlerImpl) updateStatuses(ctx context.Context, statuses state.Statuses) {
+	if h.cfg.ManualReload {
+		// The stored statuses must not include the reload condition, which is added anew after every reload.
+		last := statuses
+		h.lastStatuses = &last
+		statuses = withReloadCondition(statuses, h.pendingCfg != nil)
+	}
+
 	h.cfg.StatusUpdater.Update(ctx, statuses)
 }
 
+func withReloadCondition(statuses state.Statuses, pending bool) state.Statuses {
+	if statuses.GatewayStatus == nil {
+		return statuses
+	}
+
+	cond := conditions.NewGatewayReloaded()
+	if pending {
+		cond = conditions.NewGatewayReloadPending()
+	}
+
+	gs := *statuses.GatewayStatus
+	gs.Conditions = make([]conditions.Condition, 0, len(statuses.GatewayStatus.Conditions)+1)
+	gs.Conditions = append(gs.Conditions, statuses.GatewayStatus.Conditions...)
+	gs.Conditions = append(gs.Conditions, cond)
+
+	statuses.GatewayStatus = &gs
+
+	return statuses
+}
+
+// reloadNginx reloads NGINX on a ReloadEvent. If a written configuration was pending, it updates the statuses,
+// so that the status of the Gateway no longer reports a pending reload.
+func (h *EventHandlerImpl) reloadNginx(ctx context.Context) error {
+	if err := h.cfg.NginxRuntimeMgr.Reload(ctx); err != nil {
+		h.cfg.Logger.Error(err, "Failed to reload NGINX")
+		return err
+	}
+
+	h.cfg.Logger.Info("NGINX was successfully reloaded")
+
+	if h.pendingCfg == nil {
+		return nil
+	}
+
+	if h.cfg.ConfigStore != nil {
+		h.cfg.ConfigStore.Update(h.pendingCfg)
+	}
+
+	h.pendingCfg = nil
+
+	if h.lastStatuses != nil {
+		h.updateStatuses(ctx, *h.lastStatuses)
+	}
+
+	return nil
+}
+
 func (h *EventHandlerImpl) updateNginx(ctx context.Context, conf dataplane.Configuration) error {
+	cfg := h.cfg.Generator.Generate(conf)
+
+	if h.cfg.MetricsCollector != nil {
+		h.cfg.MetricsCollector.Update(buildConfigCounts(cfg))
+	}
+
+	// We check the size before writing the secrets, because the last applied configuration can reference
+	// the secrets that the new one doesn't.
+	if h.cfg.MaxConfigSize > 0 && len(cfg) > h.cfg.MaxConfigSize {
+		if h.cfg.MetricsCollector != nil {
+			h.cfg.MetricsCollector.IncOversizedConfigs()
+		}
+
+		return fmt.Errorf(
+			"the size of the generated NGINX configuration %d bytes exceeds the maximum size %d bytes; "+
+				"NGINX keeps running with the last applied configuration",
+			len(cfg),
+			h.cfg.MaxConfigSize,
+		)
+	}
+
 	// Write all secrets (nuke and pave).
 	// This will remove all secrets in the secrets directory before writing the requested secrets.
 	// FIXME(kate-osborn): We may want to rethink this approach in the future and write and remove secrets individually.
@@ -99,8 +227,6 @@ func (h *EventHandlerImpl) updateNginx(ctx context.Context, conf dataplane.Confi
 		return err
 	}
 
-	cfg := h.cfg.Generator.Generate(conf)
-
 	// For now, we keep all http servers and upstreams in one config file.
 	// We might rethink that. For example, we can write each server to its file
 	// or group servers in some way.
@@ -109,7 +235,43 @@ func (h *EventHandlerImpl) updateNginx(ctx context.Context, conf dataplane.Confi
 		return err
 	}
 
-	return h.cfg.NginxRuntimeMgr.Reload(ctx)
+	if h.cfg.NoReload {
+		if h.cfg.ConfigStore != nil {
+			h.cfg.ConfigStore.Update(cfg)
+		}
+
+		return nil
+	}
+
+	if h.cfg.ManualReload {
+		h.pendingCfg = cfg
+		return nil
+	}
+
+	err = h.cfg.NginxRuntimeMgr.Reload(ctx)
+	if err != nil {
+		return err
+	}
+
+	if h.cfg.ConfigStore != nil {
+		h.cfg.ConfigStore.Update(cfg)
+	}
+
+	return nil
+}
+
+var (
+	serverBlockRegexp   = regexp.MustCompile(`(?m)^\s*server {`)
+	locationBlockRegexp = regexp.MustCompile(`(?m)^\s*location [^{]+{`)
+)
+
+// buildConfigCounts counts the size and the server and location blocks of the generated NGINX configuration.
+func buildConfigCounts(cfg []byte) metrics.ConfigCounts {
+	return metrics.ConfigCounts{
+		SizeBytes: len(cfg),
+		Servers:   len(serverBlockRegexp.FindAllIndex(cfg, -1)),
+		Locations: len(locationBlockRegexp.FindAllIndex(cfg, -1)),
+	}
 }
 
 func (h *EventHandlerImpl) propagateUpsert(e *UpsertEvent) {
@@ -123,10 +285,18 @@ func (h *EventHandlerImpl) propagateUpsert(e *UpsertEvent) {
 	case *apiv1.Service:
 		h.cfg.Processor.CaptureUpsertChange(r)
 	case *apiv1.Secret:
-		// FIXME(kate-osborn): need to handle certificate rotation
+		// The Secret must be in the SecretStore before the Processor rebuilds the Graph,
+		// so that the rebuilt configuration includes the rotated certificate.
 		h.cfg.SecretStore.Upsert(r)
+		h.cfg.Processor.CaptureUpsertChange(r)
 	case *discoveryV1.EndpointSlice:
 		h.cfg.Processor.CaptureUpsertChange(r)
+	case *v1alpha1.CORSPolicy:
+		h.cfg.Processor.CaptureUpsertChange(r)
+	case *v1alpha1.DirectResponse:
+		h.cfg.Processor.CaptureUpsertChange(r)
+	case *apiv1.Namespace:
+		h.cfg.Processor.CaptureUpsertChange(r)
 	default:
 		panic(fmt.Errorf("unknown resource type %T", e.Resource))
 	}
@@ -143,10 +313,16 @@ func (h *EventHandlerImpl) propagateDelete(e *DeleteEvent) {
 	case *apiv1.Service:
 		h.cfg.Processor.CaptureDeleteChange(e.Type, e.NamespacedName)
 	case *apiv1.Secret:
-		// FIXME(kate-osborn): make sure that affected servers are updated
 		h.cfg.SecretStore.Delete(e.NamespacedName)
+		h.cfg.Processor.CaptureDeleteChange(e.Type, e.NamespacedName)
 	case *discoveryV1.EndpointSlice:
 		h.cfg.Processor.CaptureDeleteChange(e.Type, e.NamespacedName)
+	case *v1alpha1.CORSPolicy:
+		h.cfg.Processor.CaptureDeleteChange(e.Type, e.NamespacedName)
+	case *v1alpha1.DirectResponse:
+		h.cfg.Processor.CaptureDeleteChange(e.Type, e.NamespacedName)
+	case *apiv1.Namespace:
+		h.cfg.Processor.CaptureDeleteChange(e.Type, e.NamespacedName)
 	default:
 		panic(fmt.Errorf("unknown resource type %T", e.Type))
 	}
@@ -2,10 +2,13 @@ package events_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
 	apiv1 "k8s.io/api/core/v1"
 	discoveryV1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -15,11 +18,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/gateway-api/apis/v1beta1"
 
+	"github.com/nginxinc/nginx-kubernetes-gateway/apis/v1alpha1"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/events"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/metrics"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/configfakes"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/export"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/file/filefakes"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/runtime/runtimefakes"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/conditions"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/secrets/secretsfakes"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/statefakes"
@@ -142,6 +149,18 @@ var _ = Describe("EventHandler", func() {
 				"EndpointSlice upsert",
 				&events.UpsertEvent{Resource: &discoveryV1.EndpointSlice{}},
 			),
+			Entry(
+				"CORSPolicy upsert",
+				&events.UpsertEvent{Resource: &v1alpha1.CORSPolicy{}},
+			),
+			Entry(
+				"DirectResponse upsert",
+				&events.UpsertEvent{Resource: &v1alpha1.DirectResponse{}},
+			),
+			Entry(
+				"Namespace upsert",
+				&events.UpsertEvent{Resource: &apiv1.Namespace{}},
+			),
 
 			Entry(
 				"HTTPRoute delete",
@@ -175,6 +194,24 @@ var _ = Describe("EventHandler", func() {
 					NamespacedName: types.NamespacedName{Namespace: "test", Name: "endpointslice"},
 				},
 			),
+			Entry(
+				"CORSPolicy delete",
+				&events.DeleteEvent{
+					Type:           &v1alpha1.CORSPolicy{},
+					NamespacedName: types.NamespacedName{Namespace: "test", Name: "cors"},
+				},
+			),
+			Entry(
+				"DirectResponse delete",
+				&events.DeleteEvent{
+					Type:           &v1alpha1.DirectResponse{},
+					NamespacedName: types.NamespacedName{Namespace: "test", Name: "response"},
+				},
+			),
+			Entry(
+				"Namespace delete",
+				&events.DeleteEvent{Type: &apiv1.Namespace{}, NamespacedName: types.NamespacedName{Name: "test"}},
+			),
 		)
 	})
 
@@ -200,9 +237,36 @@ var _ = Describe("EventHandler", func() {
 			Expect(fakeSecretStore.UpsertCallCount()).Should(Equal(1))
 			Expect(fakeSecretStore.UpsertArgsForCall(0)).Should(Equal(secret))
 
+			Expect(fakeProcessor.CaptureUpsertChangeCallCount()).Should(Equal(1))
+			Expect(fakeProcessor.CaptureUpsertChangeArgsForCall(0)).Should(Equal(secret))
+
 			expectNoReconfig()
 		})
 
+		It("should reconfigure NGINX when a Secret referenced by a listener is updated", func() {
+			secret := &apiv1.Secret{}
+
+			batch := []interface{}{
+				&events.UpsertEvent{
+					Resource: secret,
+				},
+			}
+
+			fakeConf := dataplane.Configuration{}
+			fakeStatuses := state.Statuses{}
+			fakeProcessor.ProcessReturns(true /* changed */, fakeConf, fakeStatuses)
+
+			fakeCfg := []byte("fake")
+			fakeGenerator.GenerateReturns(fakeCfg)
+
+			handler.HandleEventBatch(context.TODO(), batch)
+
+			Expect(fakeSecretStore.UpsertCallCount()).Should(Equal(1))
+			Expect(fakeSecretMemoryManager.WriteAllRequestedSecretsCallCount()).Should(Equal(1))
+
+			expectReconfig(fakeConf, fakeCfg, fakeStatuses)
+		})
+
 		It("should process delete event", func() {
 			nsname := types.NamespacedName{Namespace: "test", Name: "secret"}
 
@@ -218,10 +282,31 @@ var _ = Describe("EventHandler", func() {
 			Expect(fakeSecretStore.DeleteCallCount()).Should(Equal(1))
 			Expect(fakeSecretStore.DeleteArgsForCall(0)).Should(Equal(nsname))
 
+			Expect(fakeProcessor.CaptureDeleteChangeCallCount()).Should(Equal(1))
+			passedObj, passedNsName := fakeProcessor.CaptureDeleteChangeArgsForCall(0)
+			Expect(passedObj).Should(Equal(&apiv1.Secret{}))
+			Expect(passedNsName).Should(Equal(nsname))
+
 			expectNoReconfig()
 		})
 	})
 
+	It("should process the changes for a process event", func() {
+		fakeConf := dataplane.Configuration{}
+		fakeStatuses := state.Statuses{}
+		fakeProcessor.ProcessReturns(true /* changed */, fakeConf, fakeStatuses)
+
+		fakeCfg := []byte("fake")
+		fakeGenerator.GenerateReturns(fakeCfg)
+
+		handler.HandleEventBatch(context.TODO(), []interface{}{&events.ProcessEvent{}})
+
+		Expect(fakeProcessor.CaptureUpsertChangeCallCount()).Should(Equal(0))
+		Expect(fakeProcessor.CaptureDeleteChangeCallCount()).Should(Equal(0))
+
+		expectReconfig(fakeConf, fakeCfg, fakeStatuses)
+	})
+
 	It("should process a batch with upsert and delete events for every supported resource", func() {
 		svc := &apiv1.Service{}
 		svcNsName := types.NamespacedName{Namespace: "test", Name: "service"}
@@ -268,18 +353,16 @@ var _ = Describe("EventHandler", func() {
 
 		handler.HandleEventBatch(context.TODO(), batch)
 
-		// Check that the events for Gateway API resources were captured
+		// Check that the events were captured
 
-		// 5, not 6, because secret upsert events do not result into CaptureUpsertChange() call
-		Expect(fakeProcessor.CaptureUpsertChangeCallCount()).Should(Equal(5))
-		for i := 0; i < 5; i++ {
+		Expect(fakeProcessor.CaptureUpsertChangeCallCount()).Should(Equal(len(upserts)))
+		for i := range upserts {
 			Expect(fakeProcessor.CaptureUpsertChangeArgsForCall(i)).
 				Should(Equal(upserts[i].(*events.UpsertEvent).Resource))
 		}
 
-		// 5, not 6, because secret delete events do not result into CaptureDeleteChange() call
-		Expect(fakeProcessor.CaptureDeleteChangeCallCount()).Should(Equal(5))
-		for i := 0; i < 5; i++ {
+		Expect(fakeProcessor.CaptureDeleteChangeCallCount()).Should(Equal(len(deletes)))
+		for i := range deletes {
 			d := deletes[i].(*events.DeleteEvent)
 			passedObj, passedNsName := fakeProcessor.CaptureDeleteChangeArgsForCall(i)
 			Expect(passedObj).Should(Equal(d.Type))
@@ -297,6 +380,272 @@ var _ = Describe("EventHandler", func() {
 		expectReconfig(fakeConf, fakeCfg, fakeStatuses)
 	})
 
+	Describe("Store the NGINX configuration", func() {
+		var configStore *export.Store
+
+		BeforeEach(func() {
+			configStore = export.NewStore()
+
+			handler = events.NewEventHandlerImpl(events.EventHandlerConfig{
+				Processor:           fakeProcessor,
+				SecretStore:         fakeSecretStore,
+				SecretMemoryManager: fakeSecretMemoryManager,
+				Generator:           fakeGenerator,
+				Logger:              zap.New(),
+				NginxFileMgr:        fakeNginxFileMgr,
+				NginxRuntimeMgr:     fakeNginxRuntimeMgr,
+				StatusUpdater:       fakeStatusUpdater,
+				ConfigStore:         configStore,
+			})
+
+			fakeProcessor.ProcessReturns(true, dataplane.Configuration{}, state.Statuses{})
+			fakeGenerator.GenerateReturns([]byte("fake"))
+		})
+
+		It("should store the configuration after a successful reload", func() {
+			handler.HandleEventBatch(context.TODO(), []interface{}{&events.UpsertEvent{Resource: &v1beta1.HTTPRoute{}}})
+
+			snapshot, exists := configStore.Get()
+			Expect(exists).To(BeTrue())
+			Expect(snapshot.Config).To(Equal([]byte("fake")))
+		})
+
+		It("should not store the configuration if the reload fails", func() {
+			fakeNginxRuntimeMgr.ReloadReturns(errors.New("reload error"))
+
+			handler.HandleEventBatch(context.TODO(), []interface{}{&events.UpsertEvent{Resource: &v1beta1.HTTPRoute{}}})
+
+			_, exists := configStore.Get()
+			Expect(exists).To(BeFalse())
+		})
+	})
+
+	Describe("Limit the size of the NGINX configuration", func() {
+		var (
+			configStore      *export.Store
+			metricsCollector *metrics.ConfigCollector
+			registry         *prometheus.Registry
+		)
+
+		getMetricValue := func(name string) float64 {
+			families, err := registry.Gather()
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, f := range families {
+				if f.GetName() != name {
+					continue
+				}
+
+				m := f.GetMetric()[0]
+				if m.GetCounter() != nil {
+					return m.GetCounter().GetValue()
+				}
+				return m.GetGauge().GetValue()
+			}
+
+			Fail(fmt.Sprintf("metric %s not found", name))
+			return 0
+		}
+
+		BeforeEach(func() {
+			configStore = export.NewStore()
+			metricsCollector = metrics.NewConfigCollector()
+			registry = prometheus.NewPedanticRegistry()
+			Expect(registry.Register(metricsCollector)).To(Succeed())
+
+			handler = events.NewEventHandlerImpl(events.EventHandlerConfig{
+				Processor:           fakeProcessor,
+				SecretStore:         fakeSecretStore,
+				SecretMemoryManager: fakeSecretMemoryManager,
+				Generator:           fakeGenerator,
+				Logger:              zap.New(),
+				NginxFileMgr:        fakeNginxFileMgr,
+				NginxRuntimeMgr:     fakeNginxRuntimeMgr,
+				StatusUpdater:       fakeStatusUpdater,
+				ConfigStore:         configStore,
+				MetricsCollector:    metricsCollector,
+				MaxConfigSize:       64,
+			})
+
+			fakeProcessor.ProcessReturns(true, dataplane.Configuration{}, state.Statuses{})
+		})
+
+		It("should apply the configuration within the maximum size and collect its metrics", func() {
+			cfg := []byte("server {\n\tlocation / {\n\t}\n\tlocation = /exact {\n\t}\n}\n")
+			fakeGenerator.GenerateReturns(cfg)
+
+			handler.HandleEventBatch(context.TODO(), []interface{}{&events.UpsertEvent{Resource: &v1beta1.HTTPRoute{}}})
+
+			Expect(fakeSecretMemoryManager.WriteAllRequestedSecretsCallCount()).Should(Equal(1))
+			Expect(fakeNginxFileMgr.WriteHTTPConfigCallCount()).Should(Equal(1))
+			Expect(fakeNginxRuntimeMgr.ReloadCallCount()).Should(Equal(1))
+
+			snapshot, exists := configStore.Get()
+			Expect(exists).To(BeTrue())
+			Expect(snapshot.Config).To(Equal(cfg))
+
+			Expect(getMetricValue("nginx_kubernetes_gateway_nginx_config_size_bytes")).To(Equal(float64(len(cfg))))
+			Expect(getMetricValue("nginx_kubernetes_gateway_nginx_config_servers")).To(Equal(1.0))
+			Expect(getMetricValue("nginx_kubernetes_gateway_nginx_config_locations")).To(Equal(2.0))
+			Expect(getMetricValue("nginx_kubernetes_gateway_nginx_config_oversized_total")).To(BeZero())
+		})
+
+		It("should refuse to apply the configuration that exceeds the maximum size", func() {
+			fakeGenerator.GenerateReturns([]byte(strings.Repeat("a", 65)))
+
+			handler.HandleEventBatch(context.TODO(), []interface{}{&events.UpsertEvent{Resource: &v1beta1.HTTPRoute{}}})
+
+			Expect(fakeSecretMemoryManager.WriteAllRequestedSecretsCallCount()).Should(Equal(0))
+			Expect(fakeNginxFileMgr.WriteHTTPConfigCallCount()).Should(Equal(0))
+			Expect(fakeNginxRuntimeMgr.ReloadCallCount()).Should(Equal(0))
+			Expect(fakeStatusUpdater.UpdateCallCount()).Should(Equal(1))
+
+			_, exists := configStore.Get()
+			Expect(exists).To(BeFalse())
+
+			Expect(getMetricValue("nginx_kubernetes_gateway_nginx_config_size_bytes")).To(Equal(65.0))
+			Expect(getMetricValue("nginx_kubernetes_gateway_nginx_config_oversized_total")).To(Equal(1.0))
+		})
+
+		It("should keep the last applied configuration after refusing an oversized one", func() {
+			fakeGenerator.GenerateReturnsOnCall(0, []byte("fake"))
+			fakeGenerator.GenerateReturnsOnCall(1, []byte(strings.Repeat("a", 65)))
+
+			batch := []interface{}{&events.UpsertEvent{Resource: &v1beta1.HTTPRoute{}}}
+			handler.HandleEventBatch(context.TODO(), batch)
+			handler.HandleEventBatch(context.TODO(), batch)
+
+			Expect(fakeNginxFileMgr.WriteHTTPConfigCallCount()).Should(Equal(1))
+			Expect(fakeNginxRuntimeMgr.ReloadCallCount()).Should(Equal(1))
+
+			snapshot, exists := configStore.Get()
+			Expect(exists).To(BeTrue())
+			Expect(snapshot.Config).To(Equal([]byte("fake")))
+		})
+	})
+
+	Describe("Write the NGINX configuration for an external NGINX", func() {
+		var configStore *export.Store
+
+		BeforeEach(func() {
+			configStore = export.NewStore()
+
+			handler = events.NewEventHandlerImpl(events.EventHandlerConfig{
+				Processor:           fakeProcessor,
+				SecretStore:         fakeSecretStore,
+				SecretMemoryManager: fakeSecretMemoryManager,
+				Generator:           fakeGenerator,
+				Logger:              zap.New(),
+				NginxFileMgr:        fakeNginxFileMgr,
+				NginxRuntimeMgr:     fakeNginxRuntimeMgr,
+				StatusUpdater:       fakeStatusUpdater,
+				ConfigStore:         configStore,
+				NoReload:            true,
+			})
+
+			fakeProcessor.ProcessReturns(true, dataplane.Configuration{}, state.Statuses{})
+			fakeGenerator.GenerateReturns([]byte("fake"))
+		})
+
+		It("should write and store the configuration without reloading NGINX", func() {
+			handler.HandleEventBatch(context.TODO(), []interface{}{&events.UpsertEvent{Resource: &v1beta1.HTTPRoute{}}})
+
+			Expect(fakeNginxFileMgr.WriteHTTPConfigCallCount()).Should(Equal(1))
+			Expect(fakeNginxRuntimeMgr.ReloadCallCount()).Should(Equal(0))
+
+			snapshot, exists := configStore.Get()
+			Expect(exists).To(BeTrue())
+			Expect(snapshot.Config).To(Equal([]byte("fake")))
+		})
+
+		It("should not store the configuration if writing it fails", func() {
+			fakeNginxFileMgr.WriteHTTPConfigReturns(errors.New("write error"))
+
+			handler.HandleEventBatch(context.TODO(), []interface{}{&events.UpsertEvent{Resource: &v1beta1.HTTPRoute{}}})
+
+			_, exists := configStore.Get()
+			Expect(exists).To(BeFalse())
+		})
+	})
+
+	Describe("Reload NGINX manually", func() {
+		var configStore *export.Store
+
+		BeforeEach(func() {
+			configStore = export.NewStore()
+
+			handler = events.NewEventHandlerImpl(events.EventHandlerConfig{
+				Processor:           fakeProcessor,
+				SecretStore:         fakeSecretStore,
+				SecretMemoryManager: fakeSecretMemoryManager,
+				Generator:           fakeGenerator,
+				Logger:              zap.New(),
+				NginxFileMgr:        fakeNginxFileMgr,
+				NginxRuntimeMgr:     fakeNginxRuntimeMgr,
+				StatusUpdater:       fakeStatusUpdater,
+				ConfigStore:         configStore,
+				ManualReload:        true,
+			})
+
+			statuses := state.Statuses{
+				GatewayStatus: &state.GatewayStatus{
+					NsName: types.NamespacedName{Namespace: "test", Name: "gateway"},
+				},
+			}
+
+			fakeProcessor.ProcessReturnsOnCall(0, true, dataplane.Configuration{}, statuses)
+			fakeGenerator.GenerateReturns([]byte("fake"))
+		})
+
+		It("should write the configuration without reloading NGINX", func() {
+			handler.HandleEventBatch(context.TODO(), []interface{}{&events.UpsertEvent{Resource: &v1beta1.HTTPRoute{}}})
+
+			Expect(fakeNginxFileMgr.WriteHTTPConfigCallCount()).Should(Equal(1))
+			Expect(fakeNginxRuntimeMgr.ReloadCallCount()).Should(Equal(0))
+
+			_, exists := configStore.Get()
+			Expect(exists).To(BeFalse())
+
+			Expect(fakeStatusUpdater.UpdateCallCount()).Should(Equal(1))
+			_, statuses := fakeStatusUpdater.UpdateArgsForCall(0)
+			Expect(statuses.GatewayStatus.Conditions).To(ConsistOf(conditions.NewGatewayReloadPending()))
+		})
+
+		It("should reload NGINX on a reload event", func() {
+			handler.HandleEventBatch(context.TODO(), []interface{}{&events.UpsertEvent{Resource: &v1beta1.HTTPRoute{}}})
+
+			resultCh := make(chan error, 1)
+			handler.HandleEventBatch(context.TODO(), []interface{}{&events.ReloadEvent{ResultCh: resultCh}})
+
+			Expect(<-resultCh).ToNot(HaveOccurred())
+			Expect(fakeNginxRuntimeMgr.ReloadCallCount()).Should(Equal(1))
+
+			snapshot, exists := configStore.Get()
+			Expect(exists).To(BeTrue())
+			Expect(snapshot.Config).To(Equal([]byte("fake")))
+
+			Expect(fakeStatusUpdater.UpdateCallCount()).Should(Equal(2))
+			_, statuses := fakeStatusUpdater.UpdateArgsForCall(1)
+			Expect(statuses.GatewayStatus.Conditions).To(ConsistOf(conditions.NewGatewayReloaded()))
+		})
+
+		It("should report the error if the reload fails", func() {
+			fakeNginxRuntimeMgr.ReloadReturns(errors.New("reload error"))
+
+			handler.HandleEventBatch(context.TODO(), []interface{}{&events.UpsertEvent{Resource: &v1beta1.HTTPRoute{}}})
+
+			resultCh := make(chan error, 1)
+			handler.HandleEventBatch(context.TODO(), []interface{}{&events.ReloadEvent{ResultCh: resultCh}})
+
+			Expect(<-resultCh).To(MatchError("reload error"))
+
+			_, exists := configStore.Get()
+			Expect(exists).To(BeFalse())
+
+			Expect(fakeStatusUpdater.UpdateCallCount()).Should(Equal(1))
+		})
+	})
+
 	Describe("Edge cases", func() {
 		DescribeTable("Edge cases for events",
 			func(e interface{}) {
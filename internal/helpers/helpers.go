@@ -2,6 +2,8 @@
 package helpers
 
 import (
+	"time"
+
 	"github.com/google/go-cmp/cmp"
 	"sigs.k8s.io/gateway-api/apis/v1beta1"
 )
@@ -57,3 +59,8 @@ func GetTLSModePointer(t v1beta1.TLSModeType) *v1beta1.TLSModeType {
 func GetBoolPointer(b bool) *bool {
 	return &b
 }
+
+// GetDurationPointer takes a Duration and returns a pointer to it.
+func GetDurationPointer(d time.Duration) *time.Duration {
+	return &d
+}
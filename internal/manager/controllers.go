@@ -7,6 +7,7 @@ import (
 
 	ctlr "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
@@ -23,10 +24,17 @@ type newReconcilerFunc func(cfg reconciler.Config) *reconciler.Implementation
 
 type controllerConfig struct {
 	namespacedNameFilter reconciler.NamespacedNameFilterFunc
+	objectFilter         reconciler.ObjectFilterFunc
 	k8sPredicate         predicate.Predicate
 	fieldIndices         index.FieldIndices
 	newReconciler        newReconcilerFunc
 	webhookValidator     reconciler.ValidatorFunc
+	auditLogger          reconciler.AuditLogger
+	ignoredStatusUpdater reconciler.IgnoredStatusUpdaterFunc
+	ignoreAnnotation     bool
+	requeueJitterFactor  float64
+	eventSendTimeout     time.Duration
+	coalescingWindow     time.Duration
 }
 
 type controllerOption func(*controllerConfig)
@@ -37,6 +45,12 @@ func withNamespacedNameFilter(filter reconciler.NamespacedNameFilterFunc) contro
 	}
 }
 
+func withObjectFilter(filter reconciler.ObjectFilterFunc) controllerOption {
+	return func(cfg *controllerConfig) {
+		cfg.objectFilter = filter
+	}
+}
+
 func withK8sPredicate(p predicate.Predicate) controllerOption {
 	return func(cfg *controllerConfig) {
 		cfg.k8sPredicate = p
@@ -62,6 +76,52 @@ func withWebhookValidator(validator reconciler.ValidatorFunc) controllerOption {
 	}
 }
 
+// withIgnoreAnnotation makes the reconciler handle resources with the reconciler.IgnoreAnnotation as deleted.
+func withIgnoreAnnotation() controllerOption {
+	return func(cfg *controllerConfig) {
+		cfg.ignoreAnnotation = true
+	}
+}
+
+// withIgnoredStatusUpdater makes the reconciler report in the statuses of the resources with
+// the reconciler.IgnoreAnnotation that they are ignored.
+func withIgnoredStatusUpdater(updater reconciler.IgnoredStatusUpdaterFunc) controllerOption {
+	return func(cfg *controllerConfig) {
+		cfg.ignoredStatusUpdater = updater
+	}
+}
+
+// withRequeueJitter adds a random jitter of up to jitterFactor * delay to the delays of the requeues of failed
+// reconciliations.
+func withRequeueJitter(jitterFactor float64) controllerOption {
+	return func(cfg *controllerConfig) {
+		cfg.requeueJitterFactor = jitterFactor
+	}
+}
+
+// withEventSendTimeout makes the reconciler requeue the resources whose events the event loop doesn't receive
+// within the timeout.
+func withEventSendTimeout(timeout time.Duration) controllerOption {
+	return func(cfg *controllerConfig) {
+		cfg.eventSendTimeout = timeout
+	}
+}
+
+// withCoalescingWindow makes the reconciler skip the events of the back-to-back reconciliations that find
+// a resource in the same state within the window.
+func withCoalescingWindow(window time.Duration) controllerOption {
+	return func(cfg *controllerConfig) {
+		cfg.coalescingWindow = window
+	}
+}
+
+// withAuditLogger makes the reconciler log its decisions about the resources with the auditLogger.
+func withAuditLogger(auditLogger reconciler.AuditLogger) controllerOption {
+	return func(cfg *controllerConfig) {
+		cfg.auditLogger = auditLogger
+	}
+}
+
 func defaultControllerConfig() controllerConfig {
 	return controllerConfig{
 		newReconciler: reconciler.NewImplementation,
@@ -89,19 +149,29 @@ func registerController(
 		}
 	}
 
-	builder := ctlr.NewControllerManagedBy(mgr).For(objectType)
+	builder := ctlr.NewControllerManagedBy(mgr).
+		For(objectType).
+		WithOptions(controller.Options{
+			RateLimiter: newJitterRateLimiter(cfg.requeueJitterFactor),
+		})
 
 	if cfg.k8sPredicate != nil {
 		builder = builder.WithEventFilter(cfg.k8sPredicate)
 	}
 
 	recCfg := reconciler.Config{
-		Getter:               mgr.GetClient(),
-		ObjectType:           objectType,
-		EventCh:              eventCh,
-		NamespacedNameFilter: cfg.namespacedNameFilter,
-		WebhookValidator:     cfg.webhookValidator,
-		EventRecorder:        recorder,
+		Getter:                mgr.GetClient(),
+		ObjectType:            objectType,
+		EventCh:               eventCh,
+		NamespacedNameFilter:  cfg.namespacedNameFilter,
+		ObjectFilter:          cfg.objectFilter,
+		WebhookValidator:      cfg.webhookValidator,
+		EventRecorder:         recorder,
+		HonorIgnoreAnnotation: cfg.ignoreAnnotation,
+		IgnoredStatusUpdater:  cfg.ignoredStatusUpdater,
+		EventSendTimeout:      cfg.eventSendTimeout,
+		AuditLogger:           cfg.auditLogger,
+		CoalescingWindow:      cfg.coalescingWindow,
 	}
 
 	err := builder.Complete(cfg.newReconciler(recCfg))
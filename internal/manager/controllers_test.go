@@ -84,6 +84,7 @@ func TestRegisterController(t *testing.T) {
 
 	objectType := &v1beta1.HTTPRoute{}
 	namespacedNameFilter := filter.CreateFilterForGatewayClass("test")
+	objectFilter := filter.CreateAnnotationFilter("debug", "true")
 	fieldIndexes := index.CreateEndpointSliceFieldIndices()
 
 	webhookValidator := createValidator(func(_ *v1beta1.HTTPRoute) field.ErrorList {
@@ -112,6 +113,8 @@ func TestRegisterController(t *testing.T) {
 				g.Expect(c.EventRecorder).To(BeIdenticalTo(eventRecorder))
 				g.Expect(c.WebhookValidator).Should(beSameFunctionPointer(webhookValidator))
 				g.Expect(c.NamespacedNameFilter).Should(beSameFunctionPointer(namespacedNameFilter))
+				g.Expect(c.ObjectFilter).Should(beSameFunctionPointer(objectFilter))
+				g.Expect(c.HonorIgnoreAnnotation).To(BeTrue())
 
 				return reconciler.NewImplementation(c)
 			}
@@ -123,10 +126,12 @@ func TestRegisterController(t *testing.T) {
 				eventCh,
 				eventRecorder,
 				withNamespacedNameFilter(namespacedNameFilter),
+				withObjectFilter(objectFilter),
 				withK8sPredicate(predicate.ServicePortsChangedPredicate{}),
 				withFieldIndices(fieldIndexes),
 				withNewReconciler(newReconciler),
 				withWebhookValidator(webhookValidator),
+				withIgnoreAnnotation(),
 			)
 
 			if test.expectedErr == nil {
@@ -0,0 +1,120 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// crdCheckInterval is the interval between the checks of the CRDs when NKG waits for them to be installed.
+const crdCheckInterval = 10 * time.Second
+
+// getRequiredKinds returns the kinds of the objects that NKG watches, which the Kubernetes API must serve.
+func getRequiredKinds(objs []client.Object, scheme *runtime.Scheme) ([]schema.GroupVersionKind, error) {
+	gvks := make([]schema.GroupVersionKind, 0, len(objs))
+
+	for _, obj := range objs {
+		gvk, err := apiutil.GVKForObject(obj, scheme)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get the kind of %T: %w", obj, err)
+		}
+
+		gvks = append(gvks, gvk)
+	}
+
+	return gvks, nil
+}
+
+// findMissingKinds returns the kinds that the Kubernetes API doesn't serve, which means their CRDs are not
+// installed.
+func findMissingKinds(
+	discoveryClient discovery.ServerResourcesInterface,
+	gvks []schema.GroupVersionKind,
+) ([]schema.GroupVersionKind, error) {
+	var missing []schema.GroupVersionKind
+
+	servedKinds := make(map[schema.GroupVersion]map[string]struct{})
+
+	for _, gvk := range gvks {
+		gv := gvk.GroupVersion()
+
+		kinds, checked := servedKinds[gv]
+		if !checked {
+			list, err := discoveryClient.ServerResourcesForGroupVersion(gv.String())
+			if err != nil && !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("cannot get the resources of %s: %w", gv, err)
+			}
+
+			kinds = make(map[string]struct{})
+			if list != nil {
+				for _, r := range list.APIResources {
+					kinds[r.Kind] = struct{}{}
+				}
+			}
+
+			servedKinds[gv] = kinds
+		}
+
+		if _, served := kinds[gvk.Kind]; !served {
+			missing = append(missing, gvk)
+		}
+	}
+
+	return missing, nil
+}
+
+// createMissingCRDsMessage returns a message that names the kinds whose CRDs are not installed.
+func createMissingCRDsMessage(missing []schema.GroupVersionKind) string {
+	names := make([]string, 0, len(missing))
+	for _, gvk := range missing {
+		names = append(names, fmt.Sprintf("%s (%s)", gvk.Kind, gvk.GroupVersion()))
+	}
+
+	return fmt.Sprintf("the CRDs of the following kinds are not installed: %s; "+
+		"install the Gateway API CRDs and the NGINX Kubernetes Gateway CRDs", strings.Join(names, ", "))
+}
+
+// ensureCRDs checks that the CRDs of the kinds are installed. If some are not, ensureCRDs returns an error that
+// names them, unless waitForCRDs is true. In that case, ensureCRDs checks the CRDs every interval until they're
+// installed or the context is canceled.
+func ensureCRDs(
+	ctx context.Context,
+	discoveryClient discovery.ServerResourcesInterface,
+	gvks []schema.GroupVersionKind,
+	waitForCRDs bool,
+	interval time.Duration,
+	logger logr.Logger,
+) error {
+	check := func(ctx context.Context) (bool, error) {
+		missing, err := findMissingKinds(discoveryClient, gvks)
+		if err != nil {
+			return false, err
+		}
+
+		if len(missing) == 0 {
+			return true, nil
+		}
+
+		msg := createMissingCRDsMessage(missing)
+		if !waitForCRDs {
+			return false, errors.New(msg)
+		}
+
+		logger.Info("Waiting for the CRDs to be installed", "reason", msg)
+
+		return false, nil
+	}
+
+	return wait.PollImmediateUntilWithContext(ctx, interval, check)
+}
@@ -0,0 +1,209 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/apis/v1alpha1"
+)
+
+func TestGetRequiredKinds(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	testScheme := runtime.NewScheme()
+	utilruntime.Must(gatewayv1beta1.AddToScheme(testScheme))
+	utilruntime.Must(apiv1.AddToScheme(testScheme))
+	utilruntime.Must(v1alpha1.AddToScheme(testScheme))
+
+	gvks, err := getRequiredKinds(
+		[]client.Object{&gatewayv1beta1.HTTPRoute{}, &apiv1.Service{}, &v1alpha1.CORSPolicy{}},
+		testScheme,
+	)
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(gvks).To(Equal([]schema.GroupVersionKind{
+		gatewayv1beta1.SchemeGroupVersion.WithKind("HTTPRoute"),
+		apiv1.SchemeGroupVersion.WithKind("Service"),
+		v1alpha1.SchemeGroupVersion.WithKind("CORSPolicy"),
+	}))
+}
+
+func TestFindMissingKinds(t *testing.T) {
+	gatewayKind := gatewayv1beta1.SchemeGroupVersion.WithKind("Gateway")
+	httpRouteKind := gatewayv1beta1.SchemeGroupVersion.WithKind("HTTPRoute")
+	serviceKind := apiv1.SchemeGroupVersion.WithKind("Service")
+	corsPolicyKind := v1alpha1.SchemeGroupVersion.WithKind("CORSPolicy")
+
+	coreResources := &metav1.APIResourceList{
+		GroupVersion: apiv1.SchemeGroupVersion.String(),
+		APIResources: []metav1.APIResource{{Kind: "Service"}},
+	}
+
+	tests := []struct {
+		msg        string
+		resources  []*metav1.APIResourceList
+		expMissing []schema.GroupVersionKind
+	}{
+		{
+			msg: "all CRDs installed",
+			resources: []*metav1.APIResourceList{
+				coreResources,
+				{
+					GroupVersion: gatewayv1beta1.SchemeGroupVersion.String(),
+					APIResources: []metav1.APIResource{{Kind: "Gateway"}, {Kind: "HTTPRoute"}},
+				},
+				{
+					GroupVersion: v1alpha1.SchemeGroupVersion.String(),
+					APIResources: []metav1.APIResource{{Kind: "CORSPolicy"}},
+				},
+			},
+			expMissing: nil,
+		},
+		{
+			msg: "a CRD of an installed group is missing",
+			resources: []*metav1.APIResourceList{
+				coreResources,
+				{
+					GroupVersion: gatewayv1beta1.SchemeGroupVersion.String(),
+					APIResources: []metav1.APIResource{{Kind: "Gateway"}},
+				},
+				{
+					GroupVersion: v1alpha1.SchemeGroupVersion.String(),
+					APIResources: []metav1.APIResource{{Kind: "CORSPolicy"}},
+				},
+			},
+			expMissing: []schema.GroupVersionKind{httpRouteKind},
+		},
+		{
+			msg:        "groups are not installed",
+			resources:  []*metav1.APIResourceList{coreResources},
+			expMissing: []schema.GroupVersionKind{gatewayKind, httpRouteKind, corsPolicyKind},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			discoveryClient := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: test.resources}}
+
+			missing, err := findMissingKinds(
+				discoveryClient,
+				[]schema.GroupVersionKind{gatewayKind, httpRouteKind, serviceKind, corsPolicyKind},
+			)
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(missing).To(Equal(test.expMissing))
+		})
+	}
+}
+
+func TestEnsureCRDs(t *testing.T) {
+	httpRouteKind := gatewayv1beta1.SchemeGroupVersion.WithKind("HTTPRoute")
+
+	gatewayResources := &metav1.APIResourceList{
+		GroupVersion: gatewayv1beta1.SchemeGroupVersion.String(),
+		APIResources: []metav1.APIResource{{Kind: "HTTPRoute"}},
+	}
+
+	t.Run("installed", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		discoveryClient := &fakediscovery.FakeDiscovery{
+			Fake: &k8stesting.Fake{Resources: []*metav1.APIResourceList{gatewayResources}},
+		}
+
+		err := ensureCRDs(
+			context.Background(),
+			discoveryClient,
+			[]schema.GroupVersionKind{httpRouteKind},
+			false,
+			time.Millisecond,
+			logr.Discard(),
+		)
+
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		discoveryClient := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{}}
+
+		err := ensureCRDs(
+			context.Background(),
+			discoveryClient,
+			[]schema.GroupVersionKind{httpRouteKind},
+			false,
+			time.Millisecond,
+			logr.Discard(),
+		)
+
+		g.Expect(err).To(MatchError(
+			"the CRDs of the following kinds are not installed: HTTPRoute (gateway.networking.k8s.io/v1beta1); " +
+				"install the Gateway API CRDs and the NGINX Kubernetes Gateway CRDs",
+		))
+	})
+
+	t.Run("waits until installed", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		fake := &k8stesting.Fake{}
+		discoveryClient := &fakediscovery.FakeDiscovery{Fake: fake}
+
+		// the CRD is installed after the third check
+		checks := 0
+		fake.AddReactor("get", "resource", func(k8stesting.Action) (bool, runtime.Object, error) {
+			checks++
+			if checks == 3 {
+				fake.Resources = []*metav1.APIResourceList{gatewayResources}
+			}
+			return false, nil, nil
+		})
+
+		err := ensureCRDs(
+			context.Background(),
+			discoveryClient,
+			[]schema.GroupVersionKind{httpRouteKind},
+			true,
+			time.Millisecond,
+			logr.Discard(),
+		)
+
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(checks).To(Equal(3))
+	})
+
+	t.Run("stops waiting when the context is canceled", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		discoveryClient := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{}}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := ensureCRDs(
+			ctx,
+			discoveryClient,
+			[]schema.GroupVersionKind{httpRouteKind},
+			true,
+			time.Millisecond,
+			logr.Discard(),
+		)
+
+		g.Expect(err).To(HaveOccurred())
+	})
+}
@@ -0,0 +1,24 @@
+package filter
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/reconciler"
+)
+
+// CreateAnnotationFilter creates a filter function that filters out all resources except the ones with
+// the annotation set to the value. It is meant for debugging a few resources in a cluster with many.
+func CreateAnnotationFilter(key, value string) reconciler.ObjectFilterFunc {
+	return func(obj client.Object) (bool, string) {
+		if v, exists := obj.GetAnnotations()[key]; !exists || v != value {
+			return false, fmt.Sprintf(
+				"Resource is ignored because this controller only processes the resources with the annotation %s=%s",
+				key,
+				value,
+			)
+		}
+		return true, ""
+	}
+}
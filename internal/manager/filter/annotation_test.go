@@ -0,0 +1,70 @@
+package filter
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func TestCreateAnnotationFilter(t *testing.T) {
+	filter := CreateAnnotationFilter("debug", "true")
+	if filter == nil {
+		t.Fatal("CreateAnnotationFilter() returned nil")
+	}
+
+	tests := []struct {
+		annotations map[string]string
+		msg         string
+		expected    bool
+	}{
+		{
+			annotations: map[string]string{"debug": "true"},
+			expected:    true,
+			msg:         "annotation with the value",
+		},
+		{
+			annotations: map[string]string{"debug": "true", "other": "value"},
+			expected:    true,
+			msg:         "annotation with the value and other annotations",
+		},
+		{
+			annotations: map[string]string{"debug": "false"},
+			expected:    false,
+			msg:         "annotation with another value",
+		},
+		{
+			annotations: map[string]string{"other": "true"},
+			expected:    false,
+			msg:         "other annotation",
+		},
+		{
+			annotations: nil,
+			expected:    false,
+			msg:         "no annotations",
+		},
+	}
+
+	for _, test := range tests {
+		hr := &v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "test",
+				Name:        "hr",
+				Annotations: test.annotations,
+			},
+		}
+
+		result, msg := filter(hr)
+
+		if result != test.expected {
+			t.Errorf("filter() returned %v but expected %v for the case %q", result, test.expected, test.msg)
+		}
+
+		if result && msg != "" {
+			t.Errorf("filter() returned a non-empty message %q for the case %q", msg, test.msg)
+		}
+		if !result && msg == "" {
+			t.Errorf("filter() returned an empty message for the case %q", test.msg)
+		}
+	}
+}
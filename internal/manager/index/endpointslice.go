@@ -13,6 +13,9 @@ const (
 	KubernetesServiceNameIndexField = "k8sServiceName"
 	// KubernetesServiceNameLabel is the label used to identify the Kubernetes service name on an EndpointSlice.
 	KubernetesServiceNameLabel = "kubernetes.io/service-name"
+	// MulticlusterServiceNameLabel is the label used to identify the name of the multi-cluster ServiceImport
+	// on an EndpointSlice of the endpoints imported from other clusters.
+	MulticlusterServiceNameLabel = "multicluster.kubernetes.io/service-name"
 )
 
 // CreateEndpointSliceFieldIndices creates a FieldIndices map for the EndpointSlice resource.
@@ -47,3 +50,13 @@ func GetServiceNameFromEndpointSlice(slice *discoveryV1.EndpointSlice) string {
 
 	return slice.Labels[KubernetesServiceNameLabel]
 }
+
+// GetServiceImportNameFromEndpointSlice returns the name of the ServiceImport of an EndpointSlice of imported
+// endpoints. It returns an empty string for other EndpointSlices.
+func GetServiceImportNameFromEndpointSlice(slice *discoveryV1.EndpointSlice) string {
+	if slice.Labels == nil {
+		return ""
+	}
+
+	return slice.Labels[MulticlusterServiceNameLabel]
+}
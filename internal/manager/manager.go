@@ -1,30 +1,44 @@
 package manager
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	apiv1 "k8s.io/api/core/v1"
 	discoveryV1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	ctlr "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	ctlrmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	k8spredicate "sigs.k8s.io/controller-runtime/pkg/predicate"
 	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 	"sigs.k8s.io/gateway-api/apis/v1beta1/validation"
 
+	"github.com/nginxinc/nginx-kubernetes-gateway/apis/v1alpha1"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/config"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/events"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/manager/filter"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/manager/index"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/manager/predicate"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/metrics"
 	ngxcfg "github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/export"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/file"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/reload"
 	ngxruntime "github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/runtime"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/reconciler"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/relationship"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/resolver"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/secrets"
@@ -34,9 +48,18 @@ import (
 const (
 	// clusterTimeout is a timeout for connections to the Kubernetes API
 	clusterTimeout = 10 * time.Second
-	// secretsFolder is the folder that holds all the secrets for NGINX servers.
+	// secretsFolder is the folder under the NGINX config root that holds all the secrets for NGINX servers.
 	// nolint:gosec
-	secretsFolder = "/etc/nginx/secrets"
+	secretsFolder = "secrets"
+	// confdFolder is the folder under the NGINX config root that holds the generated configuration files.
+	confdFolder = "conf.d"
+	// mainFolder is the folder under the NGINX config root that holds the generated configuration files
+	// of the main context.
+	mainFolder = "main.d"
+	// procRoot is the mount point of the proc filesystem, through which NKG inspects the NGINX processes.
+	procRoot = "/proc"
+	// nginxQuitMargin is the time that NKG waits for NGINX to exit on shutdown on top of the worker shutdown timeout.
+	nginxQuitMargin = 5 * time.Second
 )
 
 var scheme = runtime.NewScheme()
@@ -45,16 +68,35 @@ func init() {
 	utilruntime.Must(gatewayv1beta1.AddToScheme(scheme))
 	utilruntime.Must(apiv1.AddToScheme(scheme))
 	utilruntime.Must(discoveryV1.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
 }
 
-func Start(cfg config.Config) error {
-	logger := cfg.Logger
+// createManagerOptions creates the options of the runtime manager.
+func createManagerOptions(cfg config.Config) manager.Options {
+	// The informers of the cache of the manager resync with the configured period. 0 disables the periodic resyncs.
+	syncPeriod := cfg.InformerResyncPeriod
 
 	options := manager.Options{
-		Scheme: scheme,
-		Logger: logger,
+		Scheme:                 scheme,
+		Logger:                 cfg.Logger,
+		HealthProbeBindAddress: cfg.HealthProbeAddress,
+		SyncPeriod:             &syncPeriod,
+	}
+
+	if cfg.NginxWorkerShutdownTimeout > 0 {
+		// The manager must wait for NGINX to quit before it exits.
+		gracefulShutdownTimeout := cfg.NginxWorkerShutdownTimeout + nginxQuitMargin
+		options.GracefulShutdownTimeout = &gracefulShutdownTimeout
 	}
 
+	return options
+}
+
+func Start(cfg config.Config) error {
+	logger := cfg.Logger
+
+	options := createManagerOptions(cfg)
+
 	eventCh := make(chan interface{})
 
 	clusterCfg := ctlr.GetConfigOrDie()
@@ -65,6 +107,24 @@ func Start(cfg config.Config) error {
 		return fmt.Errorf("cannot build runtime manager: %w", err)
 	}
 
+	// The annotation filter is meant for debugging only. It applies to the HTTPRoutes only, so that a filtered route
+	// still has its Gateway and GatewayClass. The backends of the filtered out routes are not tracked.
+	var annotationFilter reconciler.ObjectFilterFunc
+	if cfg.AnnotationFilter != "" {
+		key, value, _ := strings.Cut(cfg.AnnotationFilter, "=")
+		annotationFilter = filter.CreateAnnotationFilter(key, value)
+
+		logger.Info("Processing only the HTTPRoutes with the annotation; use it only for debugging",
+			"annotation", cfg.AnnotationFilter)
+	}
+
+	ignoredStatusUpdater := status.NewIgnoredStatusUpdater(status.UpdaterConfig{
+		GatewayCtlrName: cfg.GatewayCtlrName,
+		Client:          mgr.GetClient(),
+		Logger:          cfg.Logger.WithName("ignoredStatusUpdater"),
+		Clock:           status.NewRealClock(),
+	})
+
 	controllerRegCfgs := []struct {
 		objectType client.Object
 		options    []controllerOption
@@ -73,6 +133,8 @@ func Start(cfg config.Config) error {
 			objectType: &gatewayv1beta1.GatewayClass{},
 			options: []controllerOption{
 				withNamespacedNameFilter(filter.CreateFilterForGatewayClass(cfg.GatewayClassName)),
+				withIgnoreAnnotation(),
+				withIgnoredStatusUpdater(ignoredStatusUpdater.Update),
 				// as of v0.6.0, the Gateway API Webhook doesn't include a validation function
 				// for the GatewayClass resource
 			},
@@ -81,12 +143,17 @@ func Start(cfg config.Config) error {
 			objectType: &gatewayv1beta1.Gateway{},
 			options: []controllerOption{
 				withWebhookValidator(createValidator(validation.ValidateGateway)),
+				withIgnoreAnnotation(),
+				withIgnoredStatusUpdater(ignoredStatusUpdater.Update),
 			},
 		},
 		{
 			objectType: &gatewayv1beta1.HTTPRoute{},
 			options: []controllerOption{
 				withWebhookValidator(createValidator(validation.ValidateHTTPRoute)),
+				withObjectFilter(annotationFilter),
+				withIgnoreAnnotation(),
+				withIgnoredStatusUpdater(ignoredStatusUpdater.Update),
 			},
 		},
 		{
@@ -105,35 +172,231 @@ func Start(cfg config.Config) error {
 				withFieldIndices(index.CreateEndpointSliceFieldIndices()),
 			},
 		},
+		{
+			objectType: &v1alpha1.CORSPolicy{},
+		},
+		{
+			objectType: &v1alpha1.DirectResponse{},
+		},
+		{
+			objectType: &apiv1.Namespace{},
+			options: []controllerOption{
+				// only the labels of Namespaces matter: the namespace selectors of the listeners match them
+				withK8sPredicate(k8spredicate.LabelChangedPredicate{}),
+			},
+		},
 	}
 
 	ctx := ctlr.SetupSignalHandler()
 
+	// Without the CRDs of the watched resources, the controllers fail to start with errors that don't name
+	// the missing CRDs.
+	objectTypes := make([]client.Object, 0, len(controllerRegCfgs))
+	for _, regCfg := range controllerRegCfgs {
+		objectTypes = append(objectTypes, regCfg.objectType)
+	}
+
+	requiredKinds, err := getRequiredKinds(objectTypes, scheme)
+	if err != nil {
+		return err
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(clusterCfg)
+	if err != nil {
+		return fmt.Errorf("cannot build discovery client: %w", err)
+	}
+
+	err = ensureCRDs(ctx, discoveryClient, requiredKinds, cfg.WaitForCRDs, crdCheckInterval, logger)
+	if err != nil {
+		return fmt.Errorf("cannot find the required CRDs: %w", err)
+	}
+
 	recorderName := fmt.Sprintf("nginx-kubernetes-gateway-%s", cfg.GatewayClassName)
 	recorder := mgr.GetEventRecorderFor(recorderName)
 
+	var auditLogger reconciler.AuditLogger
+	if cfg.AuditLog != "" {
+		auditLogFile, err := os.OpenFile(cfg.AuditLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640)
+		if err != nil {
+			return fmt.Errorf("cannot open the audit log: %w", err)
+		}
+		defer auditLogFile.Close()
+
+		auditLogger = reconciler.NewJSONAuditLogger(auditLogFile, cfg.Logger.WithName("auditLogger"))
+	}
+
 	for _, regCfg := range controllerRegCfgs {
-		err := registerController(ctx, regCfg.objectType, mgr, eventCh, recorder, regCfg.options...)
+		options := append(
+			regCfg.options,
+			withRequeueJitter(cfg.RequeueJitterFactor),
+			withEventSendTimeout(cfg.EventSendTimeout),
+			withCoalescingWindow(cfg.ReconcileCoalescingWindow),
+		)
+
+		if auditLogger != nil {
+			options = append(options, withAuditLogger(auditLogger))
+		}
+
+		err := registerController(ctx, regCfg.objectType, mgr, eventCh, recorder, options...)
 		if err != nil {
 			return fmt.Errorf("cannot register controller for %T: %w", regCfg.objectType, err)
 		}
 	}
 
 	secretStore := secrets.NewSecretStore()
-	secretMemoryMgr := secrets.NewSecretDiskMemoryManager(secretsFolder, secretStore)
+	secretMemoryMgr := secrets.NewSecretDiskMemoryManager(
+		filepath.Join(cfg.NginxConfigRoot, secretsFolder),
+		secretStore,
+	)
+
+	// The manager serves the metrics of its registry on the metrics endpoint.
+	metricsCollector := metrics.NewGraphCollector()
+	err = ctlrmetrics.Registry.Register(metricsCollector)
+	if err != nil {
+		return fmt.Errorf("cannot register metrics collector: %w", err)
+	}
+
+	configMetricsCollector := metrics.NewConfigCollector()
+	err = ctlrmetrics.Registry.Register(configMetricsCollector)
+	if err != nil {
+		return fmt.Errorf("cannot register config metrics collector: %w", err)
+	}
+
+	var backendResolvers graph.BackendResolvers
+	if cfg.ExperimentalServiceImportBackends {
+		backendResolvers = graph.BackendResolvers{
+			graph.ServiceImportBackendKind: graph.ResolveServiceImport,
+		}
+	}
 
 	processor := state.NewChangeProcessorImpl(state.ChangeProcessorConfig{
 		GatewayCtlrName:      cfg.GatewayCtlrName,
 		GatewayClassName:     cfg.GatewayClassName,
 		SecretMemoryManager:  secretMemoryMgr,
-		ServiceResolver:      resolver.NewServiceResolverImpl(mgr.GetClient()),
-		RelationshipCapturer: relationship.NewCapturerImpl(),
+		ServiceResolver:      resolver.NewServiceResolverImpl(mgr.GetClient(), cfg.ExperimentalServiceImportBackends),
+		RelationshipCapturer: relationship.NewCapturerImpl(cfg.ExperimentalServiceImportBackends),
 		Logger:               cfg.Logger.WithName("changeProcessor"),
+		RequestProcessing: func() {
+			select {
+			case eventCh <- &events.ProcessEvent{}:
+			case <-ctx.Done():
+			}
+		},
+		EndpointRemovalGracePeriod: cfg.EndpointRemovalGracePeriod,
+		MaxRoutesPerListener:       cfg.MaxRoutesPerListener,
+		ExternalNameAllowlist:      cfg.ExternalNameAllowlist,
+		UnixSocketBackendsDir:      cfg.UnixSocketBackendsDir,
+		ConformanceMode:            cfg.ConformanceMode,
+		BackendResolvers:           backendResolvers,
+		MetricsCollector:           metricsCollector,
 	})
 
-	configGenerator := ngxcfg.NewGeneratorImpl()
-	nginxFileMgr := file.NewManagerImpl()
-	nginxRuntimeMgr := ngxruntime.NewManagerImpl()
+	configGenerator := ngxcfg.NewGeneratorImpl(ngxcfg.GeneratorConfig{
+		AccessLog:             cfg.NginxAccessLog,
+		ErrorLog:              cfg.NginxErrorLog,
+		ErrorLogLevel:         cfg.NginxErrorLogLevel,
+		ServerTokens:          cfg.NginxServerTokens,
+		MergeSlashes:          cfg.NginxMergeSlashes,
+		AbsoluteRedirect:      cfg.NginxAbsoluteRedirect,
+		TempPath:              cfg.NginxTempPath,
+		HTTP3:                 cfg.NginxHTTP3,
+		RequestID:             cfg.NginxRequestID,
+		ForwardedHeaders:      cfg.NginxForwardedHeaders,
+		TrustedProxies:        cfg.NginxTrustedProxies,
+		Comments:              cfg.NginxConfigComments,
+		Resolver:              cfg.NginxResolver,
+		GeoIP2Database:        cfg.NginxGeoIP2Database,
+		Plus:                  cfg.NginxPlus,
+		ListenBacklog:         cfg.NginxListenBacklog,
+		ListenReusePort:       cfg.NginxListenReusePort,
+		PIDFile:               cfg.NginxPIDFile,
+		WorkerShutdownTimeout: cfg.NginxWorkerShutdownTimeout,
+		WorkerRlimitNofile:    cfg.NginxWorkerRlimitNofile,
+		SecurityHeaders:       cfg.NginxSecurityHeaders,
+		SplitClientsKey:       cfg.NginxSplitClientsKey,
+		StatusPort:            cfg.NginxStatusPort,
+	})
+	var nginxFileMgr file.Manager = file.NewManagerImpl(
+		filepath.Join(cfg.NginxConfigRoot, confdFolder),
+		filepath.Join(cfg.NginxConfigRoot, mainFolder),
+		cfg.NginxConfigFilenameFormat,
+	)
+
+	if cfg.NginxConfigConfigMap != "" {
+		ns, name, _ := strings.Cut(cfg.NginxConfigConfigMap, "/")
+
+		// The main config is written before the manager starts, so the ConfigMap can't be read through its cache.
+		var k8sClient client.Client
+		k8sClient, err = client.New(clusterCfg, client.Options{Scheme: scheme})
+		if err != nil {
+			return fmt.Errorf("cannot build client for the NGINX config ConfigMap: %w", err)
+		}
+
+		nginxFileMgr = file.NewConfigMapManager(
+			k8sClient,
+			types.NamespacedName{Namespace: ns, Name: name},
+			cfg.NginxConfigFilenameFormat,
+		)
+	}
+	nginxRuntimeMgr := ngxruntime.NewManagerImpl(cfg.NginxPIDFile)
+
+	if cfg.NginxStatusMetrics {
+		statusCollector := metrics.NewNginxStatusCollector(
+			fmt.Sprintf("http://127.0.0.1:%d%s", cfg.NginxStatusPort, ngxcfg.StatusPath),
+		)
+		err = ctlrmetrics.Registry.Register(statusCollector)
+		if err != nil {
+			return fmt.Errorf("cannot register NGINX status metrics collector: %w", err)
+		}
+	}
+
+	if cfg.HealthProbeAddress != "" {
+		healthCollector := metrics.NewNginxHealthCollector()
+		err = ctlrmetrics.Registry.Register(healthCollector)
+		if err != nil {
+			return fmt.Errorf("cannot register NGINX health metrics collector: %w", err)
+		}
+
+		healthChecker := ngxruntime.NewHealthChecker(cfg.NginxPIDFile, ngxruntime.NewProcFSInspector(procRoot))
+
+		// The check runs on every readiness probe, which also keeps the health metrics up to date.
+		err = mgr.AddReadyzCheck("nginx", func(*http.Request) error {
+			workers, err := healthChecker.Check()
+			healthCollector.Update(err == nil, workers)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("cannot register NGINX readiness check: %w", err)
+		}
+	}
+
+	// The main config doesn't depend on the cluster resources, so it is written once. NGINX applies it
+	// with the first reload.
+	err = nginxFileMgr.WriteMainConfig("main", configGenerator.GenerateMain())
+	if err != nil {
+		return fmt.Errorf("cannot write main NGINX config: %w", err)
+	}
+
+	if cfg.NginxWorkerShutdownTimeout > 0 {
+		quitLogger := cfg.Logger.WithName("nginxQuitter")
+
+		err = mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			<-ctx.Done()
+
+			quitLogger.Info("Quitting NGINX gracefully", "timeout", cfg.NginxWorkerShutdownTimeout)
+
+			// ctx is already canceled, so the waiting can't depend on it.
+			err := nginxRuntimeMgr.Quit(context.Background(), cfg.NginxWorkerShutdownTimeout+nginxQuitMargin)
+			if err != nil {
+				quitLogger.Error(err, "Failed to quit NGINX gracefully")
+			}
+
+			return nil
+		}))
+		if err != nil {
+			return fmt.Errorf("cannot register NGINX quitter: %w", err)
+		}
+	}
 	statusUpdater := status.NewUpdater(status.UpdaterConfig{
 		GatewayCtlrName:  cfg.GatewayCtlrName,
 		GatewayClassName: cfg.GatewayClassName,
@@ -145,6 +408,20 @@ func Start(cfg config.Config) error {
 		Clock:  status.NewRealClock(),
 	})
 
+	var configStore *export.Store
+	if cfg.NginxConfigExportAddress != "" {
+		configStore = export.NewStore()
+
+		err = mgr.Add(export.NewServer(
+			cfg.NginxConfigExportAddress,
+			configStore,
+			cfg.Logger.WithName("configExportServer"),
+		))
+		if err != nil {
+			return fmt.Errorf("cannot register config export server: %w", err)
+		}
+	}
+
 	eventHandler := events.NewEventHandlerImpl(events.EventHandlerConfig{
 		Processor:           processor,
 		SecretStore:         secretStore,
@@ -154,8 +431,38 @@ func Start(cfg config.Config) error {
 		NginxFileMgr:        nginxFileMgr,
 		NginxRuntimeMgr:     nginxRuntimeMgr,
 		StatusUpdater:       statusUpdater,
+		ConfigStore:         configStore,
+		MetricsCollector:    configMetricsCollector,
+		MaxConfigSize:       cfg.NginxMaxConfigSize,
+		ManualReload:        cfg.NoAutoReload,
+		NoReload:            cfg.NginxConfigConfigMap != "",
 	})
 
+	if cfg.NoAutoReload {
+		// The reload goes through the event loop, so that it doesn't race with writing the configuration.
+		trigger := func(ctx context.Context) error {
+			resultCh := make(chan error, 1)
+
+			select {
+			case eventCh <- &events.ReloadEvent{ResultCh: resultCh}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			select {
+			case err := <-resultCh:
+				return err
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		err = mgr.Add(reload.NewServer(cfg.NginxReloadAddress, trigger, cfg.Logger.WithName("reloadServer")))
+		if err != nil {
+			return fmt.Errorf("cannot register reload server: %w", err)
+		}
+	}
+
 	firstBatchPreparer := events.NewFirstEventBatchPreparerImpl(
 		mgr.GetCache(),
 		[]client.Object{
@@ -167,6 +474,9 @@ func Start(cfg config.Config) error {
 			&discoveryV1.EndpointSliceList{},
 			&gatewayv1beta1.GatewayList{},
 			&gatewayv1beta1.HTTPRouteList{},
+			&v1alpha1.CORSPolicyList{},
+			&v1alpha1.DirectResponseList{},
+			&apiv1.NamespaceList{},
 		},
 	)
 
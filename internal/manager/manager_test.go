@@ -0,0 +1,52 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/config"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
+)
+
+func TestCreateManagerOptions(t *testing.T) {
+	tests := []struct {
+		msg                        string
+		cfg                        config.Config
+		expSyncPeriod              time.Duration
+		expGracefulShutdownTimeout *time.Duration
+	}{
+		{
+			msg:           "default resync period",
+			cfg:           config.Config{InformerResyncPeriod: 10 * time.Hour},
+			expSyncPeriod: 10 * time.Hour,
+		},
+		{
+			msg:           "resync disabled",
+			cfg:           config.Config{InformerResyncPeriod: 0},
+			expSyncPeriod: 0,
+		},
+		{
+			msg: "worker shutdown timeout",
+			cfg: config.Config{
+				InformerResyncPeriod:       time.Minute,
+				NginxWorkerShutdownTimeout: 30 * time.Second,
+			},
+			expSyncPeriod:              time.Minute,
+			expGracefulShutdownTimeout: helpers.GetDurationPointer(35 * time.Second),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			options := createManagerOptions(test.cfg)
+
+			g.Expect(options.SyncPeriod).ToNot(BeNil())
+			g.Expect(*options.SyncPeriod).To(Equal(test.expSyncPeriod))
+			g.Expect(options.GracefulShutdownTimeout).To(Equal(test.expGracefulShutdownTimeout))
+		})
+	}
+}
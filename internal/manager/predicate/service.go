@@ -5,17 +5,33 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
 )
 
 // ServicePortsChangedPredicate implements an update predicate function based on the Ports of a Service.
-// This predicate will skip update events that have no change in the Service Ports and TargetPorts.
+// This predicate will skip update events that have no change in the Service Ports, TargetPorts and AppProtocols,
+// in the type and the external name of the Service, and in the annotations of the Service that configure
+// its upstream.
 type ServicePortsChangedPredicate struct {
 	predicate.Funcs
 }
 
-// ports contains the ports that the Gateway cares about.
+// upstreamAnnotations are the annotations of a Service that configure its upstream.
+var upstreamAnnotations = []string{
+	dataplane.LBHashKeyAnnotation,
+	dataplane.MaxFailsAnnotation,
+	dataplane.FailTimeoutAnnotation,
+	dataplane.SlowStartAnnotation,
+	dataplane.UnixSocketAnnotation,
+}
+
+// ports contains the ports that the Gateway cares about. The AppProtocol of a port determines the protocol of its
+// upstream. A change of the name of a port is not included, because it also changes the ports of the EndpointSlices
+// of the Service, which trigger an update of the upstream.
 type ports struct {
 	targetPort  intstr.IntOrString
+	appProtocol string
 	servicePort int32
 }
 
@@ -38,6 +54,18 @@ func (ServicePortsChangedPredicate) Update(e event.UpdateEvent) bool {
 		return false
 	}
 
+	// The type and the external name determine whether the upstream of an ExternalName Service resolves the external
+	// name and whether the external name is allowed.
+	if oldSvc.Spec.Type != newSvc.Spec.Type || oldSvc.Spec.ExternalName != newSvc.Spec.ExternalName {
+		return true
+	}
+
+	for _, a := range upstreamAnnotations {
+		if oldSvc.Annotations[a] != newSvc.Annotations[a] {
+			return true
+		}
+	}
+
 	oldPorts := oldSvc.Spec.Ports
 	newPorts := newSvc.Spec.Ports
 
@@ -49,8 +77,8 @@ func (ServicePortsChangedPredicate) Update(e event.UpdateEvent) bool {
 	newPortSet := make(map[ports]struct{})
 
 	for i := 0; i < len(oldSvc.Spec.Ports); i++ {
-		oldPortSet[ports{servicePort: oldPorts[i].Port, targetPort: oldPorts[i].TargetPort}] = struct{}{}
-		newPortSet[ports{servicePort: newPorts[i].Port, targetPort: newPorts[i].TargetPort}] = struct{}{}
+		oldPortSet[createPorts(oldPorts[i])] = struct{}{}
+		newPortSet[createPorts(newPorts[i])] = struct{}{}
 	}
 
 	for pd := range oldPortSet {
@@ -63,3 +91,16 @@ func (ServicePortsChangedPredicate) Update(e event.UpdateEvent) bool {
 
 	return len(newPortSet) > 0
 }
+
+func createPorts(p apiv1.ServicePort) ports {
+	var appProtocol string
+	if p.AppProtocol != nil {
+		appProtocol = *p.AppProtocol
+	}
+
+	return ports{
+		targetPort:  p.TargetPort,
+		appProtocol: appProtocol,
+		servicePort: p.Port,
+	}
+}
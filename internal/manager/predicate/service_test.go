@@ -4,9 +4,13 @@ import (
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
 )
 
 func TestServicePortsChangedPredicate_Update(t *testing.T) {
@@ -205,8 +209,47 @@ func TestServicePortsChangedPredicate_Update(t *testing.T) {
 			},
 			expUpdate: false,
 		},
+		{
+			msg: "app protocol changed",
+			objectOld: &v1.Service{
+				Spec: v1.ServiceSpec{
+					Ports: []v1.ServicePort{
+						{
+							Port:       80,
+							TargetPort: intstr.FromInt(80),
+						},
+					},
+				},
+			},
+			objectNew: &v1.Service{
+				Spec: v1.ServiceSpec{
+					Ports: []v1.ServicePort{
+						{
+							Port:        80,
+							TargetPort:  intstr.FromInt(80),
+							AppProtocol: helpers.GetStringPointer("kubernetes.io/h2c"),
+						},
+					},
+				},
+			},
+			expUpdate: true,
+		},
 		{
 			msg: "spec changed but ports are the same",
+			objectOld: &v1.Service{
+				Spec: v1.ServiceSpec{
+					SessionAffinity: v1.ServiceAffinityNone,
+				},
+			},
+			objectNew: &v1.Service{
+				Spec: v1.ServiceSpec{
+					SessionAffinity: v1.ServiceAffinityClientIP,
+				},
+			},
+			expUpdate: false,
+		},
+		{
+			msg: "type changed",
 			objectOld: &v1.Service{
 				Spec: v1.ServiceSpec{
 					Type: v1.ServiceTypeClusterIP,
@@ -214,7 +257,100 @@ func TestServicePortsChangedPredicate_Update(t *testing.T) {
 			},
 			objectNew: &v1.Service{
 				Spec: v1.ServiceSpec{
-					Type: v1.ServiceTypeNodePort,
+					Type:         v1.ServiceTypeExternalName,
+					ExternalName: "api.example.com",
+				},
+			},
+			expUpdate: true,
+		},
+		{
+			msg: "external name changed",
+			objectOld: &v1.Service{
+				Spec: v1.ServiceSpec{
+					Type:         v1.ServiceTypeExternalName,
+					ExternalName: "api.example.com",
+				},
+			},
+			objectNew: &v1.Service{
+				Spec: v1.ServiceSpec{
+					Type:         v1.ServiceTypeExternalName,
+					ExternalName: "other.example.com",
+				},
+			},
+			expUpdate: true,
+		},
+		{
+			msg: "lb hash key annotation changed",
+			objectOld: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{dataplane.LBHashKeyAnnotation: "$remote_addr"},
+				},
+			},
+			objectNew: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{dataplane.LBHashKeyAnnotation: "$http_x_session"},
+				},
+			},
+			expUpdate: true,
+		},
+		{
+			msg:       "max fails annotation added",
+			objectOld: &v1.Service{},
+			objectNew: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{dataplane.MaxFailsAnnotation: "5"},
+				},
+			},
+			expUpdate: true,
+		},
+		{
+			msg: "fail timeout annotation changed",
+			objectOld: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{dataplane.FailTimeoutAnnotation: "10s"},
+				},
+			},
+			objectNew: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{dataplane.FailTimeoutAnnotation: "30s"},
+				},
+			},
+			expUpdate: true,
+		},
+		{
+			msg:       "slow start annotation added",
+			objectOld: &v1.Service{},
+			objectNew: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{dataplane.SlowStartAnnotation: "30s"},
+				},
+			},
+			expUpdate: true,
+		},
+		{
+			msg: "unix socket annotation changed",
+			objectOld: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{dataplane.UnixSocketAnnotation: "/var/run/app.sock"},
+				},
+			},
+			objectNew: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{dataplane.UnixSocketAnnotation: "/var/run/other.sock"},
+				},
+			},
+			expUpdate: true,
+		},
+		{
+			msg: "other annotation changed",
+			objectOld: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"foo": "bar"},
+				},
+			},
+			objectNew: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"foo": "baz"},
 				},
 			},
 			expUpdate: false,
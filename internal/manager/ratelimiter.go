@@ -0,0 +1,44 @@
+package manager
+
+import (
+	"math/rand"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// jitterRateLimiter is a workqueue.RateLimiter that adds a random jitter to the delays of another RateLimiter,
+// so that the requeues of the items that failed at the same time (for example, because of an unavailable
+// Kubernetes API) are spread out.
+// The jitter is in the range [0, jitterFactor * delay), so the delays still grow according to the backoff
+// policy of the wrapped RateLimiter.
+type jitterRateLimiter struct {
+	workqueue.RateLimiter
+	// random returns a pseudo-random number in the range [0.0, 1.0). It allows us to mock the randomness in the
+	// unit tests.
+	random       func() float64
+	jitterFactor float64
+}
+
+var _ workqueue.RateLimiter = &jitterRateLimiter{}
+
+// newJitterRateLimiter creates a jitterRateLimiter that wraps the default controller RateLimiter of
+// the controller-runtime.
+func newJitterRateLimiter(jitterFactor float64) *jitterRateLimiter {
+	return &jitterRateLimiter{
+		RateLimiter:  workqueue.DefaultControllerRateLimiter(),
+		random:       rand.Float64, //nolint:gosec // the jitter doesn't need a cryptographically secure random number
+		jitterFactor: jitterFactor,
+	}
+}
+
+// When returns the delay of the wrapped RateLimiter with the jitter added.
+func (l *jitterRateLimiter) When(item interface{}) time.Duration {
+	delay := l.RateLimiter.When(item)
+
+	if l.jitterFactor <= 0 {
+		return delay
+	}
+
+	return delay + time.Duration(l.random()*l.jitterFactor*float64(delay))
+}
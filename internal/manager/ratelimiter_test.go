@@ -0,0 +1,84 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func TestJitterRateLimiterWhen(t *testing.T) {
+	const (
+		baseDelay = 10 * time.Millisecond
+		maxDelay  = time.Second
+	)
+
+	tests := []struct {
+		random       func() float64
+		msg          string
+		expected     []time.Duration
+		jitterFactor float64
+	}{
+		{
+			random:       func() float64 { return 0.5 },
+			jitterFactor: 0,
+			expected:     []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond},
+			msg:          "no jitter",
+		},
+		{
+			random:       func() float64 { return 0 },
+			jitterFactor: 0.5,
+			expected:     []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond},
+			msg:          "min jitter",
+		},
+		{
+			random:       func() float64 { return 0.5 },
+			jitterFactor: 0.5,
+			expected:     []time.Duration{12500 * time.Microsecond, 25 * time.Millisecond, 50 * time.Millisecond},
+			msg:          "half jitter",
+		},
+		{
+			random:       func() float64 { return 0.99 },
+			jitterFactor: 1,
+			expected:     []time.Duration{19900 * time.Microsecond, 39800 * time.Microsecond, 79600 * time.Microsecond},
+			msg:          "almost max jitter",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			limiter := &jitterRateLimiter{
+				RateLimiter:  workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay),
+				random:       test.random,
+				jitterFactor: test.jitterFactor,
+			}
+
+			for _, exp := range test.expected {
+				g.Expect(limiter.When("item")).To(Equal(exp))
+			}
+
+			limiter.Forget("item")
+			g.Expect(limiter.NumRequeues("item")).To(BeZero())
+		})
+	}
+}
+
+func TestJitterRateLimiterWhenRange(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	const jitterFactor = 0.2
+
+	limiter := newJitterRateLimiter(jitterFactor)
+	reference := workqueue.DefaultControllerRateLimiter()
+
+	for i := 0; i < 10; i++ {
+		delay := limiter.When("item")
+		expected := reference.When("item")
+
+		g.Expect(delay).To(BeNumerically(">=", expected))
+		g.Expect(delay).To(BeNumerically("<", expected+time.Duration(jitterFactor*float64(expected))))
+	}
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ConfigCounts holds the size and the numbers of the blocks of the generated NGINX configuration.
+type ConfigCounts struct {
+	// SizeBytes is the size of the generated configuration in bytes.
+	SizeBytes int
+	// Servers is the number of the server blocks of the generated configuration.
+	Servers int
+	// Locations is the number of the location blocks of the generated configuration.
+	Locations int
+}
+
+// ConfigCollector collects the gauges of the size and the numbers of the blocks of the generated NGINX configuration
+// and the counter of the configurations that weren't applied because they exceeded the maximum size.
+// It implements the prometheus.Collector interface.
+type ConfigCollector struct {
+	sizeBytes        prometheus.Gauge
+	servers          prometheus.Gauge
+	locations        prometheus.Gauge
+	oversizedConfigs prometheus.Counter
+}
+
+// NewConfigCollector creates a new ConfigCollector.
+func NewConfigCollector() *ConfigCollector {
+	return &ConfigCollector{
+		sizeBytes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "nginx_config_size_bytes",
+			Help:      "Size of the last generated NGINX configuration in bytes",
+		}),
+		servers: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "nginx_config_servers",
+			Help:      "Number of the server blocks of the last generated NGINX configuration",
+		}),
+		locations: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "nginx_config_locations",
+			Help:      "Number of the location blocks of the last generated NGINX configuration",
+		}),
+		oversizedConfigs: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "nginx_config_oversized_total",
+			Help:      "Number of the generated NGINX configurations not applied because they exceeded the maximum size",
+		}),
+	}
+}
+
+// Update sets the gauges to the counts.
+func (c *ConfigCollector) Update(counts ConfigCounts) {
+	c.sizeBytes.Set(float64(counts.SizeBytes))
+	c.servers.Set(float64(counts.Servers))
+	c.locations.Set(float64(counts.Locations))
+}
+
+// IncOversizedConfigs increments the counter of the configurations that weren't applied because they exceeded
+// the maximum size.
+func (c *ConfigCollector) IncOversizedConfigs() {
+	c.oversizedConfigs.Inc()
+}
+
+// Describe implements prometheus.Collector.
+func (c *ConfigCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.sizeBytes.Describe(ch)
+	c.servers.Describe(ch)
+	c.locations.Describe(ch)
+	c.oversizedConfigs.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *ConfigCollector) Collect(ch chan<- prometheus.Metric) {
+	c.sizeBytes.Collect(ch)
+	c.servers.Collect(ch)
+	c.locations.Collect(ch)
+	c.oversizedConfigs.Collect(ch)
+}
@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestConfigCollectorUpdate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	c := NewConfigCollector()
+
+	c.Update(ConfigCounts{SizeBytes: 1024, Servers: 2, Locations: 3})
+
+	g.Expect(testutil.ToFloat64(c.sizeBytes)).To(Equal(1024.0))
+	g.Expect(testutil.ToFloat64(c.servers)).To(Equal(2.0))
+	g.Expect(testutil.ToFloat64(c.locations)).To(Equal(3.0))
+	g.Expect(testutil.ToFloat64(c.oversizedConfigs)).To(BeZero())
+	g.Expect(testutil.CollectAndCount(c)).To(Equal(4))
+
+	c.IncOversizedConfigs()
+	c.IncOversizedConfigs()
+
+	g.Expect(testutil.ToFloat64(c.oversizedConfigs)).To(Equal(2.0))
+
+	c.Update(ConfigCounts{})
+
+	g.Expect(testutil.ToFloat64(c.sizeBytes)).To(BeZero())
+	g.Expect(testutil.ToFloat64(c.servers)).To(BeZero())
+	g.Expect(testutil.ToFloat64(c.locations)).To(BeZero())
+	g.Expect(testutil.ToFloat64(c.oversizedConfigs)).To(Equal(2.0))
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "nginx_kubernetes_gateway"
+
+// GraphCounts holds the numbers of the resources that NGINX is configured for.
+type GraphCounts struct {
+	// Gateways is the number of the programmed Gateways.
+	Gateways int
+	// Listeners is the number of the programmed listeners of the Gateways.
+	Listeners int
+	// HTTPRoutes is the number of the HTTPRoutes attached to the programmed listeners.
+	HTTPRoutes int
+	// Upstreams is the number of the upstreams whose Services are resolved.
+	Upstreams int
+}
+
+// GraphCollector collects the gauges of the numbers of the resources that NGINX is configured for.
+// It implements the prometheus.Collector interface.
+type GraphCollector struct {
+	gateways   prometheus.Gauge
+	listeners  prometheus.Gauge
+	httpRoutes prometheus.Gauge
+	upstreams  prometheus.Gauge
+}
+
+// NewGraphCollector creates a new GraphCollector.
+func NewGraphCollector() *GraphCollector {
+	return &GraphCollector{
+		gateways: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "gateways",
+			Help:      "Number of the programmed Gateways",
+		}),
+		listeners: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "listeners",
+			Help:      "Number of the programmed listeners of the Gateways",
+		}),
+		httpRoutes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "httproutes",
+			Help:      "Number of the HTTPRoutes attached to the programmed listeners",
+		}),
+		upstreams: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "upstreams",
+			Help:      "Number of the upstreams whose Services are resolved",
+		}),
+	}
+}
+
+// Update sets the gauges to the counts.
+func (c *GraphCollector) Update(counts GraphCounts) {
+	c.gateways.Set(float64(counts.Gateways))
+	c.listeners.Set(float64(counts.Listeners))
+	c.httpRoutes.Set(float64(counts.HTTPRoutes))
+	c.upstreams.Set(float64(counts.Upstreams))
+}
+
+// Describe implements prometheus.Collector.
+func (c *GraphCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.gateways.Describe(ch)
+	c.listeners.Describe(ch)
+	c.httpRoutes.Describe(ch)
+	c.upstreams.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *GraphCollector) Collect(ch chan<- prometheus.Metric) {
+	c.gateways.Collect(ch)
+	c.listeners.Collect(ch)
+	c.httpRoutes.Collect(ch)
+	c.upstreams.Collect(ch)
+}
@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestGraphCollectorUpdate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	c := NewGraphCollector()
+
+	c.Update(GraphCounts{Gateways: 1, Listeners: 2, HTTPRoutes: 3, Upstreams: 4})
+
+	g.Expect(testutil.ToFloat64(c.gateways)).To(Equal(1.0))
+	g.Expect(testutil.ToFloat64(c.listeners)).To(Equal(2.0))
+	g.Expect(testutil.ToFloat64(c.httpRoutes)).To(Equal(3.0))
+	g.Expect(testutil.ToFloat64(c.upstreams)).To(Equal(4.0))
+	g.Expect(testutil.CollectAndCount(c)).To(Equal(4))
+
+	c.Update(GraphCounts{})
+
+	g.Expect(testutil.ToFloat64(c.gateways)).To(BeZero())
+	g.Expect(testutil.ToFloat64(c.listeners)).To(BeZero())
+	g.Expect(testutil.ToFloat64(c.httpRoutes)).To(BeZero())
+	g.Expect(testutil.ToFloat64(c.upstreams)).To(BeZero())
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// NginxHealthCollector collects the gauges of the health of the NGINX processes.
+// It implements the prometheus.Collector interface.
+type NginxHealthCollector struct {
+	healthy prometheus.Gauge
+	workers prometheus.Gauge
+}
+
+// NewNginxHealthCollector creates a new NginxHealthCollector.
+func NewNginxHealthCollector() *NginxHealthCollector {
+	return &NginxHealthCollector{
+		healthy: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "nginx_healthy",
+			Help:      "Whether the NGINX main process is running with at least one worker process (1) or not (0)",
+		}),
+		workers: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "nginx_worker_processes",
+			Help:      "Number of the running worker processes of NGINX",
+		}),
+	}
+}
+
+// Update sets the gauges to the result of the last health check.
+func (c *NginxHealthCollector) Update(healthy bool, workers int) {
+	if healthy {
+		c.healthy.Set(1)
+	} else {
+		c.healthy.Set(0)
+	}
+
+	c.workers.Set(float64(workers))
+}
+
+// Describe implements prometheus.Collector.
+func (c *NginxHealthCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.healthy.Describe(ch)
+	c.workers.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *NginxHealthCollector) Collect(ch chan<- prometheus.Metric) {
+	c.healthy.Collect(ch)
+	c.workers.Collect(ch)
+}
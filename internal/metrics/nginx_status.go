@@ -0,0 +1,155 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// nginxStatusTimeout limits the time of a scrape of the basic status of NGINX.
+const nginxStatusTimeout = 5 * time.Second
+
+// stubStatusRegexp matches the response of the NGINX stub_status location. For example:
+//
+//	Active connections: 291
+//	server accepts handled requests
+//	 16630948 16630948 31070465
+//	Reading: 6 Writing: 179 Waiting: 106
+var stubStatusRegexp = regexp.MustCompile(`^Active connections: (\d+)\s+server accepts handled requests\s+` +
+	`(\d+) (\d+) (\d+)\s+Reading: (\d+) Writing: (\d+) Waiting: (\d+)\s*$`)
+
+// stubStatus is the basic status of NGINX.
+type stubStatus struct {
+	active   float64
+	accepted float64
+	handled  float64
+	requests float64
+	reading  float64
+	writing  float64
+	waiting  float64
+}
+
+// parseStubStatus parses the response of the NGINX stub_status location.
+func parseStubStatus(body []byte) (stubStatus, error) {
+	matches := stubStatusRegexp.FindSubmatch(body)
+	if matches == nil {
+		return stubStatus{}, fmt.Errorf("unexpected stub_status response: %q", body)
+	}
+
+	values := make([]float64, 0, len(matches)-1)
+	for _, m := range matches[1:] {
+		v, err := strconv.ParseFloat(string(m), 64)
+		if err != nil {
+			return stubStatus{}, fmt.Errorf("invalid stub_status value %q: %w", m, err)
+		}
+		values = append(values, v)
+	}
+
+	return stubStatus{
+		active:   values[0],
+		accepted: values[1],
+		handled:  values[2],
+		requests: values[3],
+		reading:  values[4],
+		writing:  values[5],
+		waiting:  values[6],
+	}, nil
+}
+
+// NginxStatusCollector collects the metrics of the basic status of NGINX by scraping its stub_status location
+// on every collection. If the scrape fails, it only collects the nginx_status_up gauge with the value 0.
+// It implements the prometheus.Collector interface.
+type NginxStatusCollector struct {
+	client   *http.Client
+	url      string
+	up       *prometheus.Desc
+	active   *prometheus.Desc
+	accepted *prometheus.Desc
+	handled  *prometheus.Desc
+	requests *prometheus.Desc
+	reading  *prometheus.Desc
+	writing  *prometheus.Desc
+	waiting  *prometheus.Desc
+}
+
+// NewNginxStatusCollector creates a new NginxStatusCollector that scrapes the stub_status location at the url.
+func NewNginxStatusCollector(url string) *NginxStatusCollector {
+	newDesc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "", name), help, nil, nil)
+	}
+
+	return &NginxStatusCollector{
+		client:   &http.Client{Timeout: nginxStatusTimeout},
+		url:      url,
+		up:       newDesc("nginx_status_up", "Whether the last scrape of the status of NGINX succeeded (1) or not (0)"),
+		active:   newDesc("nginx_connections_active", "Number of the active client connections of NGINX"),
+		accepted: newDesc("nginx_connections_accepted_total", "Number of the accepted client connections of NGINX"),
+		handled:  newDesc("nginx_connections_handled_total", "Number of the handled client connections of NGINX"),
+		requests: newDesc("nginx_http_requests_total", "Number of the client requests of NGINX"),
+		reading: newDesc("nginx_connections_reading",
+			"Number of the client connections where NGINX is reading the request header"),
+		writing: newDesc("nginx_connections_writing",
+			"Number of the client connections where NGINX is writing the response back to the client"),
+		waiting: newDesc("nginx_connections_waiting", "Number of the idle client connections of NGINX"),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *NginxStatusCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		c.up, c.active, c.accepted, c.handled, c.requests, c.reading, c.writing, c.waiting,
+	} {
+		ch <- d
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *NginxStatusCollector) Collect(ch chan<- prometheus.Metric) {
+	status, err := c.scrape()
+	if err != nil {
+		ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, 0)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, 1)
+	ch <- prometheus.MustNewConstMetric(c.active, prometheus.GaugeValue, status.active)
+	ch <- prometheus.MustNewConstMetric(c.accepted, prometheus.CounterValue, status.accepted)
+	ch <- prometheus.MustNewConstMetric(c.handled, prometheus.CounterValue, status.handled)
+	ch <- prometheus.MustNewConstMetric(c.requests, prometheus.CounterValue, status.requests)
+	ch <- prometheus.MustNewConstMetric(c.reading, prometheus.GaugeValue, status.reading)
+	ch <- prometheus.MustNewConstMetric(c.writing, prometheus.GaugeValue, status.writing)
+	ch <- prometheus.MustNewConstMetric(c.waiting, prometheus.GaugeValue, status.waiting)
+}
+
+func (c *NginxStatusCollector) scrape() (stubStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), nginxStatusTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return stubStatus{}, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return stubStatus{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return stubStatus{}, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return stubStatus{}, err
+	}
+
+	return parseStubStatus(body)
+}
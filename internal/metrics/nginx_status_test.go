@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+const stubStatusResponse = `Active connections: 291 
+server accepts handled requests
+ 16630948 16630947 31070465 
+Reading: 6 Writing: 179 Waiting: 106 
+`
+
+func TestParseStubStatus(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	status, err := parseStubStatus([]byte(stubStatusResponse))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(status).To(Equal(stubStatus{
+		active:   291,
+		accepted: 16630948,
+		handled:  16630947,
+		requests: 31070465,
+		reading:  6,
+		writing:  179,
+		waiting:  106,
+	}))
+
+	_, err = parseStubStatus([]byte("<html>not found</html>"))
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestNginxStatusCollector(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	statusCode := http.StatusOK
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/stub_status" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(stubStatusResponse))
+	}))
+	defer server.Close()
+
+	c := NewNginxStatusCollector(server.URL + "/stub_status")
+
+	expected := `
+# HELP nginx_kubernetes_gateway_nginx_connections_accepted_total Number of the accepted client connections of NGINX
+# TYPE nginx_kubernetes_gateway_nginx_connections_accepted_total counter
+nginx_kubernetes_gateway_nginx_connections_accepted_total 1.6630948e+07
+# HELP nginx_kubernetes_gateway_nginx_connections_active Number of the active client connections of NGINX
+# TYPE nginx_kubernetes_gateway_nginx_connections_active gauge
+nginx_kubernetes_gateway_nginx_connections_active 291
+# HELP nginx_kubernetes_gateway_nginx_http_requests_total Number of the client requests of NGINX
+# TYPE nginx_kubernetes_gateway_nginx_http_requests_total counter
+nginx_kubernetes_gateway_nginx_http_requests_total 3.1070465e+07
+# HELP nginx_kubernetes_gateway_nginx_status_up Whether the last scrape of the status of NGINX succeeded (1) or not (0)
+# TYPE nginx_kubernetes_gateway_nginx_status_up gauge
+nginx_kubernetes_gateway_nginx_status_up 1
+`
+
+	err := testutil.CollectAndCompare(
+		c,
+		strings.NewReader(expected),
+		"nginx_kubernetes_gateway_nginx_connections_accepted_total",
+		"nginx_kubernetes_gateway_nginx_connections_active",
+		"nginx_kubernetes_gateway_nginx_http_requests_total",
+		"nginx_kubernetes_gateway_nginx_status_up",
+	)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(testutil.CollectAndCount(c)).To(Equal(8))
+
+	// a failed scrape only reports that NGINX is down
+	statusCode = http.StatusForbidden
+
+	g.Expect(testutil.CollectAndCount(c)).To(Equal(1))
+	g.Expect(testutil.CollectAndCount(NewNginxStatusCollector(server.URL + "/missing"))).To(Equal(1))
+}
@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestNginxHealthCollectorUpdate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	c := NewNginxHealthCollector()
+
+	g.Expect(testutil.CollectAndCount(c)).To(Equal(2))
+
+	c.Update(true, 4)
+
+	g.Expect(testutil.ToFloat64(c.healthy)).To(Equal(1.0))
+	g.Expect(testutil.ToFloat64(c.workers)).To(Equal(4.0))
+
+	c.Update(false, 0)
+
+	g.Expect(testutil.ToFloat64(c.healthy)).To(BeZero())
+	g.Expect(testutil.ToFloat64(c.workers)).To(BeZero())
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+	"sort"
+	gotemplate "text/template"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
+)
+
+var cachesTemplate = gotemplate.Must(gotemplate.New("caches").Parse(cachesTemplateText))
+
+// cacheRoot is the directory under which NGINX stores the cached responses, in a subdirectory per cache zone.
+// NGINX must be able to write to /var/lib/nginx, because it also creates the sockets of the 500 and 502 servers there.
+const cacheRoot = "/var/lib/nginx/cache"
+
+func executeCaches(conf dataplane.Configuration) []byte {
+	zones := createCacheZones(conf.HTTPServers, conf.SSLServers)
+
+	return execute(cachesTemplate, zones)
+}
+
+// createCacheZones creates the cache zones of the HTTPRoutes that enable caching. Every HTTPRoute gets a single
+// zone, which is shared by all its rules. The zones are sorted by their names.
+func createCacheZones(httpServers, sslServers []dataplane.VirtualServer) []http.CacheZone {
+	processed := make(map[types.NamespacedName]struct{})
+
+	var zones []http.CacheZone
+
+	for _, servers := range [][]dataplane.VirtualServer{httpServers, sslServers} {
+		for _, s := range servers {
+			for _, pr := range s.PathRules {
+				for _, mr := range pr.MatchRules {
+					if mr.Options.Cache == nil {
+						continue
+					}
+
+					nsname := client.ObjectKeyFromObject(mr.Source)
+					if _, exist := processed[nsname]; exist {
+						continue
+					}
+					processed[nsname] = struct{}{}
+
+					name := createCacheZoneName(nsname)
+
+					zones = append(zones, http.CacheZone{
+						Name:    name,
+						Path:    path.Join(cacheRoot, name),
+						MaxSize: mr.Options.Cache.MaxSize,
+					})
+				}
+			}
+		}
+	}
+
+	sort.Slice(zones, func(i, j int) bool {
+		return zones[i].Name < zones[j].Name
+	})
+
+	return zones
+}
+
+// createCacheZoneName returns the name of the cache zone of an HTTPRoute. The name is derived from the namespace
+// and name of the HTTPRoute, so that it is unique among the HTTPRoutes and stable across the reloads, which keeps
+// the cached responses.
+func createCacheZoneName(route types.NamespacedName) string {
+	sum := sha256.Sum256([]byte(route.String()))
+	return "cache_" + hex.EncodeToString(sum[:10])
+}
+
+// createProxyCache creates the cache configuration of the location of a MatchRule.
+// It returns nil if the HTTPRoute of the MatchRule doesn't enable caching.
+func createProxyCache(r dataplane.MatchRule) *http.ProxyCache {
+	if r.Options.Cache == nil {
+		return nil
+	}
+
+	return &http.ProxyCache{
+		Zone:  createCacheZoneName(client.ObjectKeyFromObject(r.Source)),
+		Key:   r.Options.Cache.Key,
+		Valid: r.Options.Cache.Valid,
+	}
+}
@@ -0,0 +1,7 @@
+package config
+
+var cachesTemplateText = `
+{{ range $z := . }}
+proxy_cache_path {{ $z.Path }} levels=1:2 keys_zone={{ $z.Name }}:10m{{ if $z.MaxSize }} max_size={{ $z.MaxSize }}{{ end }};
+{{ end }}
+`
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
+)
+
+func TestExecuteCaches(t *testing.T) {
+	zones := []http.CacheZone{
+		{
+			Name: "cache_1",
+			Path: "/var/lib/nginx/cache/cache_1",
+		},
+		{
+			Name:    "cache_2",
+			Path:    "/var/lib/nginx/cache/cache_2",
+			MaxSize: "100m",
+		},
+	}
+
+	expSubStrings := map[string]int{
+		"proxy_cache_path /var/lib/nginx/cache/cache_1 levels=1:2 keys_zone=cache_1:10m;":               1,
+		"proxy_cache_path /var/lib/nginx/cache/cache_2 levels=1:2 keys_zone=cache_2:10m max_size=100m;": 1,
+	}
+
+	caches := string(execute(cachesTemplate, zones))
+	for expSubStr, expCount := range expSubStrings {
+		if expCount != strings.Count(caches, expSubStr) {
+			t.Errorf(
+				"execute() did not generate caches with substring %q %d times. Caches: %v",
+				expSubStr,
+				expCount,
+				caches,
+			)
+		}
+	}
+}
+
+func TestCreateCacheZones(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	createRoute := func(name string) *v1beta1.HTTPRoute {
+		return &v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: name},
+		}
+	}
+
+	hr1 := createRoute("hr1")
+	hr2 := createRoute("hr2")
+	hr3 := createRoute("hr3")
+
+	createMatchRule := func(hr *v1beta1.HTTPRoute, cache *dataplane.CacheOptions) dataplane.MatchRule {
+		return dataplane.MatchRule{
+			Source:  hr,
+			Options: dataplane.RouteOptions{Cache: cache},
+		}
+	}
+
+	createServer := func(matchRules ...dataplane.MatchRule) dataplane.VirtualServer {
+		return dataplane.VirtualServer{
+			Hostname:  "example.com",
+			PathRules: []dataplane.PathRule{{Path: "/", MatchRules: matchRules}},
+		}
+	}
+
+	hr1Cache := &dataplane.CacheOptions{Valid: "10m"}
+	hr2Cache := &dataplane.CacheOptions{Valid: "1h", MaxSize: "100m"}
+
+	httpServers := []dataplane.VirtualServer{
+		createServer(createMatchRule(hr1, hr1Cache), createMatchRule(hr1, hr1Cache), createMatchRule(hr3, nil)),
+	}
+	sslServers := []dataplane.VirtualServer{
+		createServer(createMatchRule(hr2, hr2Cache), createMatchRule(hr1, hr1Cache)),
+	}
+
+	expZones := []http.CacheZone{
+		{
+			Name: "cache_3f7af9d50d38ab5d0a23",
+			Path: "/var/lib/nginx/cache/cache_3f7af9d50d38ab5d0a23",
+		},
+		{
+			Name:    "cache_69fb792b7cf4880f75cd",
+			Path:    "/var/lib/nginx/cache/cache_69fb792b7cf4880f75cd",
+			MaxSize: "100m",
+		},
+	}
+
+	g.Expect(createCacheZones(httpServers, sslServers)).To(Equal(expZones))
+	g.Expect(createCacheZones(nil, nil)).To(BeEmpty())
+}
+
+func TestCreateCacheZoneName(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(createCacheZoneName(types.NamespacedName{Namespace: "test", Name: "hr1"})).
+		To(Equal("cache_3f7af9d50d38ab5d0a23"))
+	// the namespace and name are separated, so that the different HTTPRoutes don't share a zone
+	g.Expect(createCacheZoneName(types.NamespacedName{Namespace: "test-a", Name: "b"})).
+		ToNot(Equal(createCacheZoneName(types.NamespacedName{Namespace: "test", Name: "a-b"})))
+}
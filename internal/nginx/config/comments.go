@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
+)
+
+// addServerComments adds the comments that name the Gateway and the Listener to the servers created
+// by createServers for the HTTP and SSL servers of the configuration.
+func addServerComments(servers []http.Server, conf dataplane.Configuration) {
+	virtualServers := make([]dataplane.VirtualServer, 0, len(conf.HTTPServers)+len(conf.SSLServers))
+	virtualServers = append(virtualServers, conf.HTTPServers...)
+	virtualServers = append(virtualServers, conf.SSLServers...)
+
+	for i, vs := range virtualServers {
+		if vs.IsDefault {
+			continue
+		}
+
+		servers[i].Comment = fmt.Sprintf("Gateway %s, listener %s", conf.Gateway, vs.Listener)
+	}
+}
+
+// createMatchRuleComment names the HTTPRoute, the rule, the match and the backends of the match rule.
+func createMatchRuleComment(r dataplane.MatchRule) string {
+	route := types.NamespacedName{Namespace: r.Source.Namespace, Name: r.Source.Name}
+
+	comment := fmt.Sprintf("HTTPRoute %s, rule %d, match %d", route, r.RuleIdx, r.MatchIdx)
+
+	backends := make([]string, 0, len(r.BackendGroup.Backends))
+	for _, b := range r.BackendGroup.Backends {
+		if b.Svc == nil {
+			continue
+		}
+
+		backends = append(backends, fmt.Sprintf("%s/%s:%d", b.Svc.Namespace, b.Svc.Name, b.Port))
+	}
+
+	if len(backends) > 0 {
+		comment += ", backends " + strings.Join(backends, ", ")
+	}
+
+	return comment
+}
+
+// createPathRuleComment names the HTTPRoutes of the path rule, whose matches are evaluated in the location
+// for the path.
+func createPathRuleComment(rule dataplane.PathRule) string {
+	routes := make([]string, 0, len(rule.MatchRules))
+	seen := make(map[types.NamespacedName]struct{}, len(rule.MatchRules))
+
+	for _, r := range rule.MatchRules {
+		route := types.NamespacedName{Namespace: r.Source.Namespace, Name: r.Source.Name}
+
+		if _, exists := seen[route]; exists {
+			continue
+		}
+
+		seen[route] = struct{}{}
+		routes = append(routes, route.String())
+	}
+
+	return "matches of HTTPRoutes " + strings.Join(routes, ", ")
+}
+
+func createDefaultBackendComment(upstreamName string) string {
+	return "default backend, upstream " + upstreamName
+}
+
+// createUpstreamComment names the Service and the port of the upstream.
+func createUpstreamComment(up dataplane.Upstream) string {
+	return fmt.Sprintf("Service %s, port %d", up.Service, up.Port)
+}
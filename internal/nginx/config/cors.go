@@ -0,0 +1,85 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/apis/v1alpha1"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
+)
+
+const wildcardCORSOrigin = "*"
+
+// createCORS creates the CORS headers of a location from a CORSPolicy.
+// The CORSPolicy is expected to be valid.
+func createCORS(policy *v1alpha1.CORSPolicy) *http.CORS {
+	spec := policy.Spec
+
+	cors := &http.CORS{
+		AllowMethods:     strings.Join(spec.AllowMethods, ", "),
+		AllowHeaders:     strings.Join(spec.AllowHeaders, ", "),
+		ExposeHeaders:    strings.Join(spec.ExposeHeaders, ", "),
+		AllowCredentials: spec.AllowCredentials,
+	}
+
+	if spec.MaxAge != nil {
+		cors.MaxAge = fmt.Sprint(*spec.MaxAge)
+	}
+
+	if allowsAnyOrigin(spec.AllowOrigins) {
+		cors.AllowOrigin = wildcardCORSOrigin
+	} else {
+		cors.AllowOrigin = "$" + createCORSOriginVariableName(spec.AllowOrigins)
+	}
+
+	return cors
+}
+
+// createCORSOriginMap creates a map that evaluates to the origin of the request, if the CORSPolicy allows it,
+// or to an empty string otherwise, so that NGINX doesn't add the Access-Control-Allow-Origin header.
+// It returns false if the CORSPolicy allows any origin, so that the map is not needed.
+func createCORSOriginMap(policy *v1alpha1.CORSPolicy) (http.Map, bool) {
+	if allowsAnyOrigin(policy.Spec.AllowOrigins) {
+		return http.Map{}, false
+	}
+
+	params := make([]http.MapParameter, 0, len(policy.Spec.AllowOrigins)+1)
+
+	for _, o := range policy.Spec.AllowOrigins {
+		params = append(params, http.MapParameter{
+			Value:  fmt.Sprintf("%q", o),
+			Result: "$http_origin",
+		})
+	}
+
+	params = append(params, http.MapParameter{
+		Value:  "default",
+		Result: `""`,
+	})
+
+	return http.Map{
+		Source:     "$http_origin",
+		Variable:   createCORSOriginVariableName(policy.Spec.AllowOrigins),
+		Parameters: params,
+	}, true
+}
+
+// createCORSOriginVariableName returns the name of the variable of the map for the allowed origins.
+// The name is derived from the origins in their order, so that the CORSPolicies with the same origins share
+// a single map. The origins are validated and cannot include spaces.
+func createCORSOriginVariableName(origins []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(origins, " ")))
+	return "cors_origin_" + hex.EncodeToString(sum[:10])
+}
+
+func allowsAnyOrigin(origins []string) bool {
+	for _, o := range origins {
+		if o == wildcardCORSOrigin {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/apis/v1alpha1"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
+)
+
+func TestCreateCORS(t *testing.T) {
+	tests := []struct {
+		spec     v1alpha1.CORSPolicySpec
+		expected *http.CORS
+		msg      string
+	}{
+		{
+			spec: v1alpha1.CORSPolicySpec{
+				AllowOrigins:     []string{"https://example.com", "https://foo.example.com"},
+				AllowMethods:     []string{"GET", "POST"},
+				AllowHeaders:     []string{"Content-Type", "X-Request-Id"},
+				ExposeHeaders:    []string{"X-Trace-Id"},
+				MaxAge:           helpers.GetInt32Pointer(0),
+				AllowCredentials: true,
+			},
+			expected: &http.CORS{
+				AllowOrigin:      "$cors_origin_2d7a0a24bfaef3a1a836",
+				AllowMethods:     "GET, POST",
+				AllowHeaders:     "Content-Type, X-Request-Id",
+				ExposeHeaders:    "X-Trace-Id",
+				MaxAge:           "0",
+				AllowCredentials: true,
+			},
+			msg: "specific origins",
+		},
+		{
+			spec: v1alpha1.CORSPolicySpec{
+				AllowOrigins: []string{"https://example.com", "*"},
+			},
+			expected: &http.CORS{
+				AllowOrigin: "*",
+			},
+			msg: "any origin",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			policy := &v1alpha1.CORSPolicy{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "cors-policy.v1"},
+				Spec:       test.spec,
+			}
+
+			g.Expect(createCORS(policy)).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestCreateCORSOriginMap(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	policy := &v1alpha1.CORSPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "cors"},
+		Spec: v1alpha1.CORSPolicySpec{
+			AllowOrigins: []string{"https://example.com", "http://foo.example.com:8080"},
+		},
+	}
+
+	expected := http.Map{
+		Source:   "$http_origin",
+		Variable: "cors_origin_897ba2ae9545b171d424",
+		Parameters: []http.MapParameter{
+			{Value: `"https://example.com"`, Result: "$http_origin"},
+			{Value: `"http://foo.example.com:8080"`, Result: "$http_origin"},
+			{Value: "default", Result: `""`},
+		},
+	}
+
+	m, needed := createCORSOriginMap(policy)
+	g.Expect(needed).To(BeTrue())
+	g.Expect(m).To(Equal(expected))
+
+	policy.Spec.AllowOrigins = []string{"*"}
+
+	_, needed = createCORSOriginMap(policy)
+	g.Expect(needed).To(BeFalse())
+}
+
+func TestCreateCORSOriginVariableName(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	name := createCORSOriginVariableName([]string{"https://example.com", "https://foo.example.com"})
+	g.Expect(name).To(Equal("cors_origin_2d7a0a24bfaef3a1a836"))
+
+	sameName := createCORSOriginVariableName([]string{"https://example.com", "https://foo.example.com"})
+	g.Expect(sameName).To(Equal(name))
+
+	// the order of the origins matters, so that only identical maps share the variable
+	reorderedName := createCORSOriginVariableName([]string{"https://foo.example.com", "https://example.com"})
+	g.Expect(reorderedName).To(Equal("cors_origin_aff9cf3167a06c7d7072"))
+
+	otherName := createCORSOriginVariableName([]string{"https://example.com"})
+	g.Expect(otherName).To(Equal("cors_origin_100680ad546ce6a577f4"))
+}
@@ -2,17 +2,34 @@ package config
 
 import (
 	"bytes"
+	"sync"
 	"text/template"
 )
 
+// bufferPool holds the buffers that the templates are executed into. The configuration is generated on every
+// change of the cluster resources, so reusing the buffers saves growing a new buffer for each template.
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
 // executes the template with the given data.
 func execute(template *template.Template, data interface{}) []byte {
-	var buf bytes.Buffer
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer func() {
+		buf.Reset()
+		bufferPool.Put(buf)
+	}()
 
-	err := template.Execute(&buf, data)
+	err := template.Execute(buf, data)
 	if err != nil {
 		panic(err)
 	}
 
-	return buf.Bytes()
+	// The result is copied, because the buffer is reused.
+	result := make([]byte, buf.Len())
+	copy(result, buf.Bytes())
+
+	return result
 }
@@ -1,6 +1,11 @@
 package config
 
 import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
 )
 
@@ -13,30 +18,181 @@ type Generator interface {
 	Generate(configuration dataplane.Configuration) []byte
 }
 
+// GeneratorConfig holds the configuration of the GeneratorImpl that doesn't depend on the cluster resources.
+type GeneratorConfig struct {
+	// AccessLog is the destination of the NGINX access log.
+	AccessLog string
+	// ErrorLog is the destination of the NGINX error log.
+	ErrorLog string
+	// ErrorLogLevel is the level of the NGINX error log.
+	ErrorLogLevel string
+	// ServerTokens enables emitting the NGINX version in the error pages and the Server response header.
+	ServerTokens bool
+	// MergeSlashes enables merging adjacent slashes in the URIs of the requests before the location matching.
+	MergeSlashes bool
+	// AbsoluteRedirect makes the redirects that NGINX issues use absolute URLs.
+	AbsoluteRedirect bool
+	// TempPath is the directory under which NGINX stores the temporary files.
+	TempPath string
+	// HTTP3 enables HTTP/3 over QUIC for the HTTPS servers.
+	HTTP3 bool
+	// Comments enables emitting the comments that map the server, location and upstream blocks back to
+	// the Gateway API resources and Services that they are generated from.
+	Comments bool
+	// Resolver is the space-separated addresses of the DNS servers that NGINX uses to resolve the hostnames
+	// of the ExternalName Services. If empty, the upstreams of the ExternalName Services fail the requests with 502.
+	Resolver string
+	// Plus enables the features of the configuration that require NGINX Plus, such as the slow start of
+	// the upstream servers. Without it, the options of these features are ignored.
+	Plus bool
+	// GeoIP2Database is the path of the GeoIP2 database in which NGINX looks up the country and the continent of
+	// the clients. Empty means the lookups are disabled.
+	GeoIP2Database string
+	// PIDFile is the path of the PID file of the NGINX main process, rendered into the main configuration.
+	// Empty means the default of NGINX.
+	PIDFile string
+	// WorkerShutdownTimeout is the timeout for the graceful shutdown of the NGINX workers. 0 means no timeout.
+	WorkerShutdownTimeout time.Duration
+	// WorkerRlimitNofile is the limit of the number of open files of the NGINX workers. 0 means no limit is set.
+	WorkerRlimitNofile int
+	// SecurityHeaders are the names and values of the response headers that all servers add to their responses,
+	// including the error responses.
+	SecurityHeaders map[string]string
+	// ForwardedHeaders makes NGINX pass the X-Forwarded-For, X-Forwarded-Proto, X-Forwarded-Host and X-Forwarded-Port
+	// headers to the backends.
+	ForwardedHeaders bool
+	// TrustedProxies are the addresses and CIDRs of the trusted proxies, whose X-Forwarded-For header NGINX uses to
+	// determine the address of the client.
+	TrustedProxies []string
+	// RequestID enables the propagation of the request ID: NGINX passes the X-Request-ID header of the client request
+	// or, without it, a generated request ID to the backends and returns it to the clients. The routes can override it.
+	RequestID bool
+	// StatusPort is the port on the loopback address of the server that exposes the basic status of NGINX
+	// at StatusPath. 0 means the server is not generated.
+	StatusPort int
+	// SplitClientsKey is the NGINX variables whose values are hashed to split the requests across the backends
+	// by their weights. If empty, $request_id is used.
+	SplitClientsKey string
+	// ListenBacklog is the maximum length of the queue of the pending connections of the listening sockets.
+	// 0 means the default of NGINX.
+	ListenBacklog int
+	// ListenReusePort enables the reuseport parameter of the listening sockets.
+	ListenReusePort bool
+}
+
+// StatusPath is the path of the basic status of NGINX on the server of the GeneratorConfig.StatusPort.
+const StatusPath = "/stub_status"
+
 // GeneratorImpl is an implementation of Generator.
-type GeneratorImpl struct{}
+type GeneratorImpl struct {
+	cfg GeneratorConfig
+}
 
 // NewGeneratorImpl creates a new GeneratorImpl.
-func NewGeneratorImpl() GeneratorImpl {
-	return GeneratorImpl{}
+func NewGeneratorImpl(cfg GeneratorConfig) GeneratorImpl {
+	return GeneratorImpl{
+		cfg: cfg,
+	}
 }
 
 // executeFunc is a function that generates NGINX configuration from internal representation.
 type executeFunc func(configuration dataplane.Configuration) []byte
 
 func (g GeneratorImpl) Generate(conf dataplane.Configuration) []byte {
-	var generated []byte
-	for _, execute := range getExecuteFuncs() {
-		generated = append(generated, execute(conf)...)
+	var parts [][]byte
+
+	parts = append(parts, executeLogging(http.Logging{
+		AccessLog:     g.cfg.AccessLog,
+		ErrorLog:      g.cfg.ErrorLog,
+		ErrorLogLevel: g.cfg.ErrorLogLevel,
+	}))
+
+	parts = append(parts, executeSettings(http.Settings{
+		ServerTokens:     g.cfg.ServerTokens,
+		MergeSlashes:     g.cfg.MergeSlashes,
+		AbsoluteRedirect: g.cfg.AbsoluteRedirect,
+		TempPath:         g.cfg.TempPath,
+		Resolver:         g.cfg.Resolver,
+		TrustedProxies:   g.cfg.TrustedProxies,
+		GeoIP2Database:   g.cfg.GeoIP2Database,
+	}))
+
+	parts = append(parts, executeStatus(http.Status{
+		Path: StatusPath,
+		Port: g.cfg.StatusPort,
+	}))
+
+	securityHeaders := createSecurityHeaders(g.cfg.SecurityHeaders)
+
+	executeFuncs := getExecuteFuncs(
+		g.cfg.Comments,
+		g.cfg.HTTP3,
+		g.cfg.RequestID,
+		g.cfg.ForwardedHeaders,
+		len(g.cfg.TrustedProxies) > 0,
+		getRouteAccessLog(g.cfg.AccessLog),
+		g.cfg.Resolver != "",
+		g.cfg.Plus,
+		securityHeaders,
+		g.cfg.SplitClientsKey,
+		http.ListenOptions{
+			Backlog:   g.cfg.ListenBacklog,
+			ReusePort: g.cfg.ListenReusePort,
+		},
+	)
+
+	for _, execute := range executeFuncs {
+		parts = append(parts, execute(conf))
+	}
+
+	// The parts are joined into a single allocation instead of growing the result with each part.
+	return bytes.Join(parts, nil)
+}
+
+// GenerateMain generates the NGINX configuration of the main context, which doesn't depend on the cluster resources.
+func (g GeneratorImpl) GenerateMain() []byte {
+	main := http.Main{
+		PIDFile:            g.cfg.PIDFile,
+		WorkerRlimitNofile: g.cfg.WorkerRlimitNofile,
+	}
+
+	if g.cfg.WorkerShutdownTimeout > 0 {
+		main.WorkerShutdownTimeout = fmt.Sprintf("%dms", g.cfg.WorkerShutdownTimeout.Milliseconds())
 	}
 
-	return generated
+	return executeMain(main)
 }
 
-func getExecuteFuncs() []executeFunc {
+func getExecuteFuncs(
+	comments, http3, requestID, forwardedHeaders, forwardedProto bool,
+	routeAccessLog string,
+	resolve, plus bool,
+	securityHeaders []http.Header,
+	splitClientsKey string,
+	listen http.ListenOptions,
+) []executeFunc {
 	return []executeFunc{
-		executeUpstreams,
-		executeSplitClients,
-		executeServers,
+		func(conf dataplane.Configuration) []byte {
+			return executeUpstreams(conf, comments, resolve, plus)
+		},
+		func(conf dataplane.Configuration) []byte {
+			return executeSplitClients(conf, splitClientsKey)
+		},
+		executeMaps,
+		executeCaches,
+		executeRateLimits,
+		func(conf dataplane.Configuration) []byte {
+			return executeServers(
+				conf,
+				comments,
+				http3,
+				requestID,
+				forwardedHeaders,
+				forwardedProto,
+				routeAccessLog,
+				securityHeaders,
+				listen,
+			)
+		},
 	}
 }
@@ -1,17 +1,28 @@
 package config_test
 
 import (
+	"context"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
 
+	"github.com/nginxinc/nginx-kubernetes-gateway/apis/v1alpha1"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/resolver"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/resolver/resolverfakes"
 )
 
-// Note: this test only verifies that Generate() returns a byte array with upstream, server, and split_client blocks.
+// Note: this test only verifies that Generate() returns a byte array with logging, upstream, server, and split_client
+// blocks.
 // It does not test the correctness of those blocks. That functionality is covered by other tests in this package.
 func TestGenerate(t *testing.T) {
 	bg := graph.BackendGroup{
@@ -51,9 +62,25 @@ func TestGenerate(t *testing.T) {
 		},
 		BackendGroups: []graph.BackendGroup{bg},
 	}
-	generator := config.NewGeneratorImpl()
+	generator := config.NewGeneratorImpl(config.GeneratorConfig{
+		AccessLog:     "/dev/stdout",
+		ErrorLog:      "stderr",
+		ErrorLogLevel: "info",
+	})
 	cfg := string(generator.Generate(conf))
 
+	if !strings.Contains(cfg, "access_log /dev/stdout;") {
+		t.Errorf("Generate() did not generate a config with the access log; config: %s", cfg)
+	}
+
+	if !strings.Contains(cfg, "error_log stderr info;") {
+		t.Errorf("Generate() did not generate a config with the error log; config: %s", cfg)
+	}
+
+	if !strings.Contains(cfg, "server_tokens off;") {
+		t.Errorf("Generate() did not generate a config with server tokens disabled; config: %s", cfg)
+	}
+
 	if !strings.Contains(cfg, "listen 80") {
 		t.Errorf("Generate() did not generate a config with a default HTTP server; config: %s", cfg)
 	}
@@ -70,3 +97,359 @@ func TestGenerate(t *testing.T) {
 		t.Errorf("Generate() did not generate a config with an split_clients block; config: %s", cfg)
 	}
 }
+
+// TestGenerateRuleWithoutMatches verifies that a rule without matches serves all paths, as the Gateway API implies
+// a PathPrefix / match for such rules.
+func TestGenerateRuleWithoutMatches(t *testing.T) {
+	const gcName = "nginx"
+
+	store := graph.ClusterStore{
+		GatewayClass: &v1beta1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: gcName},
+			Spec: v1beta1.GatewayClassSpec{
+				ControllerName: "test.example.com/gateway",
+			},
+		},
+		Gateways: map[types.NamespacedName]*v1beta1.Gateway{
+			{Namespace: "test", Name: "gateway"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "gateway"},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: gcName,
+					Listeners: []v1beta1.Listener{
+						{
+							Name:     "http",
+							Port:     80,
+							Protocol: v1beta1.HTTPProtocolType,
+						},
+					},
+				},
+			},
+		},
+		HTTPRoutes: map[types.NamespacedName]*v1beta1.HTTPRoute{
+			{Namespace: "test", Name: "hr"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "hr"},
+				Spec: v1beta1.HTTPRouteSpec{
+					CommonRouteSpec: v1beta1.CommonRouteSpec{
+						ParentRefs: []v1beta1.ParentReference{
+							{
+								Name:        "gateway",
+								SectionName: (*v1beta1.SectionName)(helpers.GetStringPointer("http")),
+							},
+						},
+					},
+					Hostnames: []v1beta1.Hostname{"example.com"},
+					Rules: []v1beta1.HTTPRouteRule{
+						{
+							BackendRefs: []v1beta1.HTTPBackendRef{
+								{
+									BackendRef: v1beta1.BackendRef{
+										BackendObjectReference: v1beta1.BackendObjectReference{
+											Kind: (*v1beta1.Kind)(helpers.GetStringPointer("Service")),
+											Name: "coffee",
+											Port: (*v1beta1.PortNumber)(helpers.GetInt32Pointer(80)),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Services: map[types.NamespacedName]*v1.Service{
+			{Namespace: "test", Name: "coffee"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "coffee"},
+			},
+		},
+	}
+
+	g := graph.BuildGraph(store, "test.example.com/gateway", gcName, nil, 0, nil, nil, false)
+	conf, _ := dataplane.BuildConfiguration(context.TODO(), g, &resolverfakes.FakeServiceResolver{}, "")
+
+	generator := config.NewGeneratorImpl(config.GeneratorConfig{})
+	cfg := string(generator.Generate(conf))
+
+	if !strings.Contains(cfg, "server_name example.com;") {
+		t.Errorf("Generate() did not generate a config with a server for the HTTPRoute; config: %s", cfg)
+	}
+
+	// Without the rule, NGINX would respond with 404 in the default location /.
+	if !strings.Contains(cfg, "proxy_pass http://test_coffee_80$request_uri;") {
+		t.Errorf("Generate() did not generate a config with the location / for the rule; config: %s", cfg)
+	}
+}
+
+// TestGenerateFilterOrder verifies that the filters of a rule apply in the order they are listed: the CORSPolicy
+// adds its headers to the redirect responses only if its ExtensionRef filter is listed before the RequestRedirect
+// filter.
+func TestGenerateFilterOrder(t *testing.T) {
+	const gcName = "nginx"
+
+	corsFilter := v1beta1.HTTPRouteFilter{
+		Type: v1beta1.HTTPRouteFilterExtensionRef,
+		ExtensionRef: &v1beta1.LocalObjectReference{
+			Group: v1alpha1.GroupName,
+			Kind:  v1alpha1.CORSPolicyKind,
+			Name:  "cors",
+		},
+	}
+	redirectFilter := v1beta1.HTTPRouteFilter{
+		Type: v1beta1.HTTPRouteFilterRequestRedirect,
+		RequestRedirect: &v1beta1.HTTPRequestRedirectFilter{
+			Hostname: (*v1beta1.PreciseHostname)(helpers.GetStringPointer("foo.example.com")),
+		},
+	}
+
+	generate := func(filters []v1beta1.HTTPRouteFilter) string {
+		store := graph.ClusterStore{
+			GatewayClass: &v1beta1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{Name: gcName},
+				Spec: v1beta1.GatewayClassSpec{
+					ControllerName: "test.example.com/gateway",
+				},
+			},
+			Gateways: map[types.NamespacedName]*v1beta1.Gateway{
+				{Namespace: "test", Name: "gateway"}: {
+					ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "gateway"},
+					Spec: v1beta1.GatewaySpec{
+						GatewayClassName: gcName,
+						Listeners: []v1beta1.Listener{
+							{
+								Name:     "http",
+								Port:     80,
+								Protocol: v1beta1.HTTPProtocolType,
+							},
+						},
+					},
+				},
+			},
+			HTTPRoutes: map[types.NamespacedName]*v1beta1.HTTPRoute{
+				{Namespace: "test", Name: "hr"}: {
+					ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "hr"},
+					Spec: v1beta1.HTTPRouteSpec{
+						CommonRouteSpec: v1beta1.CommonRouteSpec{
+							ParentRefs: []v1beta1.ParentReference{
+								{
+									Name:        "gateway",
+									SectionName: (*v1beta1.SectionName)(helpers.GetStringPointer("http")),
+								},
+							},
+						},
+						Hostnames: []v1beta1.Hostname{"example.com"},
+						Rules: []v1beta1.HTTPRouteRule{
+							{
+								Filters: filters,
+							},
+						},
+					},
+				},
+			},
+			CORSPolicies: map[types.NamespacedName]*v1alpha1.CORSPolicy{
+				{Namespace: "test", Name: "cors"}: {
+					ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "cors"},
+					Spec: v1alpha1.CORSPolicySpec{
+						AllowOrigins: []string{"*"},
+					},
+				},
+			},
+		}
+
+		g := graph.BuildGraph(store, "test.example.com/gateway", gcName, nil, 0, nil, nil, false)
+		conf, _ := dataplane.BuildConfiguration(context.TODO(), g, &resolverfakes.FakeServiceResolver{}, "")
+
+		return string(config.NewGeneratorImpl(config.GeneratorConfig{}).Generate(conf))
+	}
+
+	const (
+		corsHeader = "add_header Access-Control-Allow-Origin"
+		redirect   = "return 302 $scheme://foo.example.com:80$request_uri;"
+	)
+
+	corsFirst := generate([]v1beta1.HTTPRouteFilter{corsFilter, redirectFilter})
+
+	if !strings.Contains(corsFirst, corsHeader) || !strings.Contains(corsFirst, redirect) {
+		t.Errorf(
+			"Generate() did not generate a config with the CORS headers and the redirect "+
+				"for the CORSPolicy listed before the redirect; config: %s",
+			corsFirst,
+		)
+	}
+	if strings.Index(corsFirst, corsHeader) > strings.Index(corsFirst, redirect) {
+		t.Errorf("Generate() did not generate the CORS headers before the redirect; config: %s", corsFirst)
+	}
+
+	redirectFirst := generate([]v1beta1.HTTPRouteFilter{redirectFilter, corsFilter})
+
+	if strings.Contains(redirectFirst, corsHeader) || !strings.Contains(redirectFirst, redirect) {
+		t.Errorf(
+			"Generate() did not generate a config with only the redirect "+
+				"for the CORSPolicy listed after the redirect; config: %s",
+			redirectFirst,
+		)
+	}
+}
+
+func TestGenerateMain(t *testing.T) {
+	tests := []struct {
+		msg      string
+		expected string
+		cfg      config.GeneratorConfig
+	}{
+		{
+			cfg:      config.GeneratorConfig{},
+			expected: "",
+			msg:      "no directives",
+		},
+		{
+			cfg:      config.GeneratorConfig{WorkerShutdownTimeout: 30 * time.Second},
+			expected: "worker_shutdown_timeout 30000ms;",
+			msg:      "worker shutdown timeout in seconds",
+		},
+		{
+			cfg:      config.GeneratorConfig{WorkerShutdownTimeout: 1500 * time.Millisecond},
+			expected: "worker_shutdown_timeout 1500ms;",
+			msg:      "worker shutdown timeout in milliseconds",
+		},
+		{
+			cfg:      config.GeneratorConfig{PIDFile: "/var/run/nginx/nginx.pid"},
+			expected: "pid /var/run/nginx/nginx.pid;",
+			msg:      "pid file",
+		},
+		{
+			cfg: config.GeneratorConfig{
+				PIDFile:               "/etc/nginx/nginx.pid",
+				WorkerShutdownTimeout: 30 * time.Second,
+			},
+			expected: "pid /etc/nginx/nginx.pid;\nworker_shutdown_timeout 30000ms;",
+			msg:      "pid file and worker shutdown timeout",
+		},
+		{
+			cfg:      config.GeneratorConfig{WorkerRlimitNofile: 65536},
+			expected: "worker_rlimit_nofile 65536;",
+			msg:      "worker rlimit nofile",
+		},
+	}
+
+	for _, test := range tests {
+		generator := config.NewGeneratorImpl(test.cfg)
+
+		result := strings.TrimSpace(string(generator.GenerateMain()))
+		if result != test.expected {
+			t.Errorf("GenerateMain() returned %q but expected %q for case %q", result, test.expected, test.msg)
+		}
+	}
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	const gcName = "nginx"
+
+	createStore := func(routeCount int) graph.ClusterStore {
+		store := graph.ClusterStore{
+			GatewayClass: &v1beta1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{Name: gcName},
+				Spec: v1beta1.GatewayClassSpec{
+					ControllerName: "test.example.com/gateway",
+				},
+			},
+			Gateways: map[types.NamespacedName]*v1beta1.Gateway{
+				{Namespace: "test", Name: "gateway"}: {
+					ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "gateway"},
+					Spec: v1beta1.GatewaySpec{
+						GatewayClassName: gcName,
+						Listeners: []v1beta1.Listener{
+							{
+								Name:     "http",
+								Port:     80,
+								Protocol: v1beta1.HTTPProtocolType,
+							},
+						},
+					},
+				},
+			},
+			HTTPRoutes: make(map[types.NamespacedName]*v1beta1.HTTPRoute, routeCount),
+			Services:   make(map[types.NamespacedName]*v1.Service, routeCount),
+		}
+
+		for i := 0; i < routeCount; i++ {
+			name := fmt.Sprintf("app-%d", i)
+
+			createBackendRefs := func(port int32) []v1beta1.HTTPBackendRef {
+				return []v1beta1.HTTPBackendRef{
+					{
+						BackendRef: v1beta1.BackendRef{
+							BackendObjectReference: v1beta1.BackendObjectReference{
+								Name: v1beta1.ObjectName(name),
+								Port: (*v1beta1.PortNumber)(helpers.GetInt32Pointer(port)),
+							},
+						},
+					},
+				}
+			}
+
+			store.HTTPRoutes[types.NamespacedName{Namespace: "test", Name: name}] = &v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: name},
+				Spec: v1beta1.HTTPRouteSpec{
+					CommonRouteSpec: v1beta1.CommonRouteSpec{
+						ParentRefs: []v1beta1.ParentReference{
+							{
+								Name:        "gateway",
+								SectionName: (*v1beta1.SectionName)(helpers.GetStringPointer("http")),
+							},
+						},
+					},
+					Hostnames: []v1beta1.Hostname{v1beta1.Hostname(name + ".example.com")},
+					Rules: []v1beta1.HTTPRouteRule{
+						{
+							BackendRefs: createBackendRefs(80),
+						},
+						{
+							Matches: []v1beta1.HTTPRouteMatch{
+								{
+									Path: &v1beta1.HTTPPathMatch{Value: helpers.GetStringPointer("/api")},
+									Headers: []v1beta1.HTTPHeaderMatch{
+										{
+											Type:  helpers.GetHeaderMatchTypePointer(v1beta1.HeaderMatchExact),
+											Name:  "version",
+											Value: "v2",
+										},
+									},
+								},
+							},
+							BackendRefs: createBackendRefs(8080),
+						},
+					},
+				},
+			}
+
+			store.Services[types.NamespacedName{Namespace: "test", Name: name}] = &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: name},
+			}
+		}
+
+		return store
+	}
+
+	fakeResolver := &resolverfakes.FakeServiceResolver{}
+	fakeResolver.ResolveReturns([]resolver.Endpoint{
+		{Address: "10.0.0.1", Port: 8080},
+		{Address: "10.0.0.2", Port: 8080},
+		{Address: "10.0.0.3", Port: 8080},
+	}, nil)
+
+	generator := config.NewGeneratorImpl(config.GeneratorConfig{})
+
+	for _, count := range []int{10, 100, 1000} {
+		g := graph.BuildGraph(createStore(count), "test.example.com/gateway", gcName, nil, 0, nil, nil, false)
+		conf, _ := dataplane.BuildConfiguration(context.TODO(), g, fakeResolver, "")
+
+		b.Run(fmt.Sprintf("%d routes", count), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				if len(generator.Generate(conf)) == 0 {
+					b.Fatal("Generate() generated an empty config")
+				}
+			}
+		})
+	}
+}
@@ -1,33 +1,253 @@
 package http
 
+// Settings holds the general settings of the http context.
+type Settings struct {
+	// ServerTokens enables emitting the NGINX version in the error pages and the Server response header.
+	ServerTokens bool
+	// MergeSlashes enables merging two or more adjacent slashes in the URI of a request into a single slash
+	// before the location matching.
+	MergeSlashes bool
+	// AbsoluteRedirect makes the redirects that NGINX issues use absolute URLs instead of relative ones.
+	AbsoluteRedirect bool
+	// TempPath is the directory under which NGINX stores the temporary files. If empty, NGINX uses
+	// its compiled-in temporary paths.
+	TempPath string
+	// Resolver is the space-separated addresses of the DNS servers that NGINX uses to resolve the hostnames
+	// of the upstream servers at run time. Empty means no resolver.
+	Resolver string
+	// TrustedProxies are the addresses and CIDRs of the trusted proxies, such as load balancers, whose
+	// X-Forwarded-For header NGINX uses to determine the address of the client. Empty means no trusted proxies.
+	TrustedProxies []string
+	// GeoIP2Database is the path of the GeoIP2 database in which NGINX looks up the country and the continent of
+	// the clients, which are exposed in the $geoip2_country_code and $geoip2_continent_code variables.
+	// Empty means the lookups are disabled.
+	GeoIP2Database string
+}
+
+// Status holds the configuration of the server that exposes the basic status of NGINX (stub_status).
+type Status struct {
+	// Path is the path of the location of the status.
+	Path string
+	// Port is the port of the server on the loopback address. 0 means the server is not generated.
+	Port int
+}
+
+// Main holds the settings of the main context.
+type Main struct {
+	// PIDFile is the path of the PID file of the NGINX main process. Empty means the default of NGINX.
+	PIDFile string
+	// WorkerShutdownTimeout is the timeout for the graceful shutdown of the worker processes in the NGINX time format.
+	// Empty means no timeout.
+	WorkerShutdownTimeout string
+	// WorkerRlimitNofile is the limit of the number of open files of the worker processes. 0 means no limit is set.
+	WorkerRlimitNofile int
+}
+
+// Logging holds the logging configuration of the http context.
+type Logging struct {
+	// AccessLog is the destination of the access log: a file, /dev/stdout or off.
+	AccessLog string
+	// ErrorLog is the destination of the error log: a file or stderr.
+	ErrorLog string
+	// ErrorLogLevel is the level of the error log.
+	ErrorLogLevel string
+}
+
 // Server holds all configuration for an HTTP server.
 type Server struct {
-	SSL           *SSL
-	ServerName    string
+	SSL        *SSL
+	ServerName string
+	// Addresses holds the addresses that the server listens on. IPv6 addresses are enclosed in square brackets.
+	// Empty means all addresses.
+	Addresses     []string
 	Locations     []Location
 	IsDefaultHTTP bool
 	IsDefaultSSL  bool
+	// HTTP2 enables HTTP/2 for the clients of the server. It is required to proxy gRPC requests.
+	// Because NGINX enables HTTP/2 for the address and port rather than the server, it must be the same for all
+	// servers that listen on the same address and port.
+	HTTP2 bool
+	// HTTP3 makes the HTTPS server also accept HTTP/3 connections over QUIC and advertise HTTP/3 to the clients.
+	HTTP3 bool
+	// Comment is emitted above the server block. Empty means no comment.
+	Comment string
+	// SecurityHeaders are added to all responses of the server, including the error responses.
+	// The locations with CORS headers add them too, because they don't inherit the headers of the server.
+	SecurityHeaders []Header
+	// RateLimit limits the rate of the requests to all locations of the server. Nil means no limit.
+	RateLimit *RateLimit
+	// ForwardedHeaders makes the locations that proxy requests pass the X-Forwarded-For, X-Forwarded-Proto,
+	// X-Forwarded-Host and X-Forwarded-Port headers to the backends.
+	ForwardedHeaders bool
+	// Listen holds the parameters of the listening sockets of the server. Only the default servers have them,
+	// because NGINX only allows them in one listen directive per address and port.
+	Listen ListenOptions
+	// Maintenance makes the server respond to all requests with 503 instead of the Locations. Nil means
+	// the server uses the Locations.
+	Maintenance *Maintenance
+	// ClientHeaderBuffers configures the buffers for reading the headers of the client requests. Nil means
+	// the defaults of NGINX.
+	ClientHeaderBuffers *ClientHeaderBuffers
+}
+
+// ClientHeaderBuffers holds the configuration of the buffers for reading the headers of the client requests.
+type ClientHeaderBuffers struct {
+	// Size is the size of the client header buffer. Empty means the default of NGINX.
+	Size string
+	// Large is the number and the size of the large client header buffers, for example, 4 16k. Empty means
+	// the default of NGINX.
+	Large string
+}
+
+// ListenOptions holds the parameters of the listening sockets of a server.
+type ListenOptions struct {
+	// Backlog is the maximum length of the queue of the pending connections (backlog). 0 means the default of NGINX.
+	Backlog int
+	// ReusePort makes NGINX create a listening socket for each worker process (reuseport), so that the kernel
+	// distributes the incoming connections between the worker processes.
+	ReusePort bool
+}
+
+// Maintenance holds the configuration of the maintenance response of a server.
+type Maintenance struct {
+	// Page is the HTML page of the response, escaped for a double-quoted NGINX string. Empty means the 503 error page
+	// of NGINX.
+	Page string
+}
+
+// RateLimit holds the configuration of the limit of the rate of the requests of a server.
+type RateLimit struct {
+	// Zone is the name of the RateLimitZone.
+	Zone string
+	// Burst is the number of the requests in excess of the rate of the zone that NGINX accepts without delay.
+	Burst int32
+}
+
+// RateLimitZone holds the configuration of a shared memory zone of a rate limit declared in the http context.
+type RateLimitZone struct {
+	// Name is the name of the zone, which is unique among the RateLimitZones.
+	Name string
+	// Rate is the rate of the requests per client address, for example, 10r/s.
+	Rate string
+}
+
+// Header is an HTTP header.
+type Header struct {
+	Name  string
+	Value string
 }
 
 // Location holds all configuration for an HTTP location.
 type Location struct {
-	Return       *Return
-	Path         string
-	ProxyPass    string
+	Return    *Return
+	Path      string
+	ProxyPass string
+	// GRPCPass is the address of the backend that NGINX proxies the requests to over HTTP/2 using the gRPC module.
+	// It is set instead of ProxyPass for the backends that use HTTP/2 or gRPC.
+	GRPCPass     string
 	HTTPMatchVar string
 	Internal     bool
+	// CORS holds the CORS headers of the location. Nil means CORS is not configured.
+	CORS *CORS
+	// Streaming disables buffering of requests and responses, so that they are streamed to and from the backend.
+	Streaming bool
+	// UpstreamHost sets the Host header of the proxied requests to the name of the upstream instead of the Host header
+	// of the client request. It only applies to ProxyPass.
+	UpstreamHost bool
+	// ProxyCache caches the responses of the backend. Nil means caching is disabled. It only applies to ProxyPass.
+	ProxyCache *ProxyCache
+	// ProxySSLName is the server name that NGINX sends with SNI to the HTTPS backends. NGINX doesn't verify
+	// the certificates of the backends. It can be a variable. Empty means SNI is disabled. It only applies to ProxyPass.
+	ProxySSLName string
+	// RequestID passes the request ID to the backend and returns it to the client in the X-Request-ID header.
+	RequestID bool
+	// ProxySetHeaders are the request headers that the location passes to the backends. The values are escaped
+	// for the double-quoted NGINX strings and can include variables. An empty value removes the header.
+	ProxySetHeaders []Header
+	// AccessLog overrides the access log of the http context. Nil means the access log of the http context applies.
+	AccessLog *AccessLog
+	// DefaultType is the content type of the responses that the location returns. Empty means the NGINX default.
+	DefaultType string
+	// Comment is emitted above the location block. Empty means no comment.
+	Comment string
+}
+
+// AccessLog holds the configuration of the access log of a location.
+type AccessLog struct {
+	// Path is the destination of the access log: a file, /dev/stdout or off.
+	Path string
+	// Format is the name of the log format. Empty means the NGINX default, combined.
+	Format string
+}
+
+// CORS holds the values of the CORS headers of a location. Empty values mean the corresponding headers
+// are not added.
+type CORS struct {
+	// AllowOrigin is either "*" or a variable that evaluates to the origin of the request, if it is allowed,
+	// or to an empty string otherwise.
+	AllowOrigin   string
+	AllowMethods  string
+	AllowHeaders  string
+	ExposeHeaders string
+	MaxAge        string
+	// AllowCredentials adds the Access-Control-Allow-Credentials header.
+	AllowCredentials bool
+}
+
+// ProxyCache holds the cache configuration of a location.
+type ProxyCache struct {
+	// Zone is the name of the CacheZone.
+	Zone string
+	// Key is the key of the cached responses. Empty means the NGINX default.
+	Key string
+	// Valid is the NGINX time for which the responses are cached.
+	Valid string
+}
+
+// CacheZone holds the configuration of a cache declared in the http context.
+type CacheZone struct {
+	// Name is the name of the shared memory zone of the cache, which is unique among the CacheZones.
+	Name string
+	// Path is the directory of the cached responses.
+	Path string
+	// MaxSize is the maximum size of the cache. Empty means no limit.
+	MaxSize string
 }
 
 // Return represents an HTTP return.
 type Return struct {
+	// RequestURIRegex, if set, makes NGINX return URL only for the requests whose $request_uri matches the regex,
+	// so that URL can reference the captures of the regex. NGINX returns FallbackURL for the other requests.
+	RequestURIRegex string
+	// FallbackURL is the URL for the requests whose $request_uri doesn't match RequestURIRegex.
+	FallbackURL string
+	// URL is the URL of a redirect or the quoted text of the body of other responses.
 	URL  string
 	Code StatusCode
+	// SkipForwardedHTTPS makes NGINX skip the return for the requests that the trusted proxies forwarded from
+	// HTTPS, so that the location handles them as if it had no return.
+	SkipForwardedHTTPS bool
 }
 
 // SSL holds all SSL related configuration.
 type SSL struct {
 	Certificate    string
 	CertificateKey string
+	// SecondaryCertificate and SecondaryCertificateKey are set when the server has a second certificate
+	// with a different key type.
+	SecondaryCertificate    string
+	SecondaryCertificateKey string
+	// Protocols and Ciphers are the enabled TLS protocols and ciphers. Empty means the NGINX defaults.
+	Protocols string
+	Ciphers   string
+	// ClientCertificate is the path to the bundle of the CA certificates for verifying the client certificates.
+	// Empty means the client certificates are not verified.
+	ClientCertificate string
+	// VerifyClient is the verification mode of the client certificates (ssl_verify_client).
+	VerifyClient string
+	// VerifyClientErrorStatus is the status code of the response when the verification of a client certificate
+	// fails.
+	VerifyClientErrorStatus int
 }
 
 // StatusCode is an HTTP status code.
@@ -38,21 +258,49 @@ const (
 	StatusFound StatusCode = 302
 	// StatusNotFound is the HTTP 404 status code.
 	StatusNotFound StatusCode = 404
+	// StatusInternalServerError is the HTTP 500 status code.
+	StatusInternalServerError StatusCode = 500
 )
 
 // Upstream holds all configuration for an HTTP upstream.
 type Upstream struct {
-	Name    string
-	Servers []UpstreamServer
+	Name string
+	// HashKey is the key for consistent hashing load balancing. Empty means the default load balancing method.
+	HashKey string
+	// LeastConn makes the upstream use the least_conn load balancing method instead of the default one, which
+	// doesn't support the slow start of the servers. It is ignored if HashKey is set.
+	LeastConn bool
+	// ZoneSize is the size of the shared memory zone of the upstream, which is named after the upstream.
+	// Empty means the upstream has no zone.
+	ZoneSize string
+	Servers  []UpstreamServer
+	// Comment is emitted above the upstream block. Empty means no comment.
+	Comment string
 }
 
 // UpstreamServer holds all configuration for an HTTP upstream server.
 type UpstreamServer struct {
 	Address string
+	// FailTimeout is the fail timeout of the server. Empty means the max_fails and fail_timeout parameters
+	// are not set.
+	FailTimeout string
+	// MaxFails is the number of unsuccessful attempts during FailTimeout, after which the server is considered
+	// unavailable for the duration of FailTimeout.
+	MaxFails int32
+	// SlowStart is the time during which the share of the requests of the server increases to the normal share
+	// after the server recovers or is added. Empty means no slow start. Requires NGINX Plus.
+	SlowStart string
+	// Down marks the server as permanently unavailable, so that NGINX doesn't send new requests to it.
+	Down bool
+	// Resolve makes NGINX resolve the hostname in Address using the resolver of the http context and re-resolve it
+	// when its DNS record expires. It requires the upstream to have a zone.
+	Resolve bool
 }
 
 // SplitClient holds all configuration for an HTTP split client.
 type SplitClient struct {
+	// Key is the string with NGINX variables whose hash assigns the requests to the distributions.
+	Key           string
 	VariableName  string
 	Distributions []SplitClientDistribution
 }
@@ -62,3 +310,18 @@ type SplitClientDistribution struct {
 	Percent string
 	Value   string
 }
+
+// Map holds all configuration for an HTTP map.
+type Map struct {
+	// Source is the source string of the map. For example, $http_origin.
+	Source string
+	// Variable is the name of the variable that the map creates, without the $ prefix.
+	Variable   string
+	Parameters []MapParameter
+}
+
+// MapParameter maps a Value of the Map Source to a Result. The Value "default" sets the default Result.
+type MapParameter struct {
+	Value  string
+	Result string
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	gotemplate "text/template"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
+)
+
+var loggingTemplate = gotemplate.Must(gotemplate.New("logging").Parse(loggingTemplateText))
+
+// accessLogOff is the destination that disables the access log.
+const accessLogOff = "off"
+
+// defaultRouteAccessLog is the destination of the access log of the routes that enable it when the access log
+// of the http context is off.
+const defaultRouteAccessLog = "/dev/stdout"
+
+func executeLogging(logging http.Logging) []byte {
+	return execute(loggingTemplate, logging)
+}
+
+// getRouteAccessLog returns the destination of the access log of the routes that enable it: the destination of
+// the access log of the http context or, if it is off, defaultRouteAccessLog.
+func getRouteAccessLog(accessLog string) string {
+	if accessLog == accessLogOff {
+		return defaultRouteAccessLog
+	}
+
+	return accessLog
+}
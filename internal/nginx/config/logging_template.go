@@ -0,0 +1,11 @@
+package config
+
+var loggingTemplateText = `
+log_format verbose '$remote_addr - $remote_user [$time_local] "$request" $status $body_bytes_sent '
+                   '"$http_referer" "$http_user_agent" request_time=$request_time request_id=$request_id '
+                   'upstream_addr=$upstream_addr upstream_status=$upstream_status '
+                   'upstream_connect_time=$upstream_connect_time upstream_response_time=$upstream_response_time';
+
+access_log {{ .AccessLog }};
+error_log {{ .ErrorLog }} {{ .ErrorLogLevel }};
+`
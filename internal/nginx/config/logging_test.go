@@ -0,0 +1,67 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
+)
+
+func TestExecuteLogging(t *testing.T) {
+	tests := []struct {
+		logging       http.Logging
+		msg           string
+		expSubStrings []string
+	}{
+		{
+			logging: http.Logging{
+				AccessLog:     "/dev/stdout",
+				ErrorLog:      "stderr",
+				ErrorLogLevel: "debug",
+			},
+			expSubStrings: []string{
+				"log_format verbose ",
+				"access_log /dev/stdout;",
+				"error_log stderr debug;",
+			},
+			msg: "stdout and stderr",
+		},
+		{
+			logging: http.Logging{
+				AccessLog:     "/var/log/nginx/access.log",
+				ErrorLog:      "/var/log/nginx/error.log",
+				ErrorLogLevel: "info",
+			},
+			expSubStrings: []string{
+				"access_log /var/log/nginx/access.log;",
+				"error_log /var/log/nginx/error.log info;",
+			},
+			msg: "files",
+		},
+	}
+
+	for _, test := range tests {
+		logging := string(executeLogging(test.logging))
+
+		for _, expSubStr := range test.expSubStrings {
+			if !strings.Contains(logging, expSubStr) {
+				t.Errorf(
+					"executeLogging() %q did not generate logging with substring %q. Logging: %v",
+					test.msg,
+					expSubStr,
+					logging,
+				)
+			}
+		}
+	}
+}
+
+func TestGetRouteAccessLog(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(getRouteAccessLog("/var/log/nginx/access.log")).To(Equal("/var/log/nginx/access.log"))
+	g.Expect(getRouteAccessLog("/dev/stdout")).To(Equal("/dev/stdout"))
+	g.Expect(getRouteAccessLog("off")).To(Equal("/dev/stdout"))
+}
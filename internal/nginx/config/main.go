@@ -0,0 +1,13 @@
+package config
+
+import (
+	gotemplate "text/template"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
+)
+
+var mainTemplate = gotemplate.Must(gotemplate.New("main").Parse(mainTemplateText))
+
+func executeMain(main http.Main) []byte {
+	return execute(mainTemplate, main)
+}
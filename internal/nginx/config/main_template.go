@@ -0,0 +1,13 @@
+package config
+
+var mainTemplateText = `
+{{- if .PIDFile }}
+pid {{ .PIDFile }};
+{{- end }}
+{{- if .WorkerShutdownTimeout }}
+worker_shutdown_timeout {{ .WorkerShutdownTimeout }};
+{{- end }}
+{{- if .WorkerRlimitNofile }}
+worker_rlimit_nofile {{ .WorkerRlimitNofile }};
+{{- end }}
+`
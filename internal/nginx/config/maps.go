@@ -0,0 +1,86 @@
+package config
+
+import (
+	"sort"
+	gotemplate "text/template"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
+)
+
+var mapsTemplate = gotemplate.Must(gotemplate.New("maps").Parse(mapsTemplateText))
+
+func executeMaps(conf dataplane.Configuration) []byte {
+	maps := createMaps(conf.HTTPServers, conf.SSLServers, conf.BackendGroups)
+
+	return execute(mapsTemplate, maps)
+}
+
+// createMaps creates the maps for the CORSPolicies referenced by the servers, for the server names of the split
+// HTTPS backends of the servers and for the RequestHeaderModifiers of the backends of the backendGroups.
+// Identical maps are generated only once: the maps are deduplicated by their variable names, which are derived
+// from the contents of the maps. The maps are sorted by their variable names.
+func createMaps(httpServers, sslServers []dataplane.VirtualServer, backendGroups []graph.BackendGroup) []http.Map {
+	processed := make(map[types.NamespacedName]struct{})
+	variables := make(map[string]struct{})
+
+	var maps []http.Map
+
+	for _, servers := range [][]dataplane.VirtualServer{httpServers, sslServers} {
+		for _, s := range servers {
+			for _, pr := range s.PathRules {
+				for _, mr := range pr.MatchRules {
+					if mr.BackendProtocol == dataplane.BackendProtocolHTTPS && backendGroupNeedsSplit(mr.BackendGroup) {
+						m := createProxySSLNameMap(mr.BackendGroup)
+						if _, exist := variables[m.Variable]; !exist {
+							variables[m.Variable] = struct{}{}
+							maps = append(maps, m)
+						}
+					}
+
+					policy := mr.Filters.CORSPolicy
+					if policy == nil {
+						continue
+					}
+
+					nsname := client.ObjectKeyFromObject(policy)
+					if _, exist := processed[nsname]; exist {
+						continue
+					}
+					processed[nsname] = struct{}{}
+
+					m, needed := createCORSOriginMap(policy)
+					if !needed {
+						continue
+					}
+
+					if _, exist := variables[m.Variable]; exist {
+						continue
+					}
+					variables[m.Variable] = struct{}{}
+
+					maps = append(maps, m)
+				}
+			}
+		}
+	}
+
+	for _, m := range createRequestHeaderMaps(backendGroups) {
+		if _, exist := variables[m.Variable]; exist {
+			continue
+		}
+		variables[m.Variable] = struct{}{}
+
+		maps = append(maps, m)
+	}
+
+	sort.Slice(maps, func(i, j int) bool {
+		return maps[i].Variable < maps[j].Variable
+	})
+
+	return maps
+}
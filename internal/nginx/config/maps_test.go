@@ -0,0 +1,138 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/apis/v1alpha1"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
+)
+
+func TestExecuteMaps(t *testing.T) {
+	createPolicy := func(name string, origins ...string) *v1alpha1.CORSPolicy {
+		return &v1alpha1.CORSPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: name},
+			Spec:       v1alpha1.CORSPolicySpec{AllowOrigins: origins},
+		}
+	}
+
+	cors1 := createPolicy("cors-1", "https://example.com", "https://foo.example.com")
+	cors2 := createPolicy("cors-2", "https://bar.example.com")
+	anyOrigin := createPolicy("any", "*")
+
+	createServer := func(policies ...*v1alpha1.CORSPolicy) dataplane.VirtualServer {
+		matchRules := make([]dataplane.MatchRule, 0, len(policies)+1)
+		for _, p := range policies {
+			matchRules = append(matchRules, dataplane.MatchRule{Filters: dataplane.Filters{CORSPolicy: p}})
+		}
+		// a rule without a policy
+		matchRules = append(matchRules, dataplane.MatchRule{})
+
+		return dataplane.VirtualServer{
+			Hostname:  "example.com",
+			PathRules: []dataplane.PathRule{{Path: "/", MatchRules: matchRules}},
+		}
+	}
+
+	conf := dataplane.Configuration{
+		HTTPServers: []dataplane.VirtualServer{createServer(cors2, cors1, anyOrigin)},
+		SSLServers:  []dataplane.VirtualServer{createServer(cors1)},
+	}
+
+	expSubStrings := map[string]int{
+		"map $http_origin $cors_origin_2d7a0a24bfaef3a1a836 {": 1,
+		"map $http_origin $cors_origin_d9afe51ede77751aa8ad {": 1,
+		`"https://example.com" $http_origin;`:                  1,
+		`"https://foo.example.com" $http_origin;`:              1,
+		`"https://bar.example.com" $http_origin;`:              1,
+		`default "";`: 2,
+		// no map for the policy that allows any origin
+		"map $http_origin": 2,
+	}
+
+	maps := string(executeMaps(conf))
+	for expSubStr, expCount := range expSubStrings {
+		if expCount != strings.Count(maps, expSubStr) {
+			t.Errorf(
+				"executeMaps() did not generate maps with substring %q %d times. Maps: %v",
+				expSubStr,
+				expCount,
+				maps,
+			)
+		}
+	}
+
+	g := NewGomegaWithT(t)
+	idx1 := strings.Index(maps, "cors_origin_2d7a0a24bfaef3a1a836")
+	idx2 := strings.Index(maps, "cors_origin_d9afe51ede77751aa8ad")
+	g.Expect(idx1).To(BeNumerically("<", idx2))
+}
+
+func TestExecuteMapsDeduplicated(t *testing.T) {
+	createPolicy := func(name string, origins ...string) *v1alpha1.CORSPolicy {
+		return &v1alpha1.CORSPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: name},
+			Spec:       v1alpha1.CORSPolicySpec{AllowOrigins: origins},
+		}
+	}
+
+	createMatchRule := func(route string, policy *v1alpha1.CORSPolicy) dataplane.MatchRule {
+		return dataplane.MatchRule{
+			Source: &v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: route},
+			},
+			Filters: dataplane.Filters{CORSPolicy: policy},
+		}
+	}
+
+	// two routes reference different CORSPolicies with the same origins
+	cors1 := createPolicy("cors-1", "https://example.com", "https://foo.example.com")
+	cors2 := createPolicy("cors-2", "https://example.com", "https://foo.example.com")
+	// the same origins in a different order result in a different map
+	reordered := createPolicy("reordered", "https://foo.example.com", "https://example.com")
+
+	conf := dataplane.Configuration{
+		HTTPServers: []dataplane.VirtualServer{
+			{
+				Hostname: "foo.example.com",
+				PathRules: []dataplane.PathRule{
+					{Path: "/", MatchRules: []dataplane.MatchRule{createMatchRule("hr-1", cors1)}},
+				},
+			},
+			{
+				Hostname: "bar.example.com",
+				PathRules: []dataplane.PathRule{
+					{Path: "/", MatchRules: []dataplane.MatchRule{createMatchRule("hr-2", cors2)}},
+					{Path: "/reordered", MatchRules: []dataplane.MatchRule{createMatchRule("hr-2", reordered)}},
+				},
+			},
+		},
+	}
+
+	expSubStrings := map[string]int{
+		"map $http_origin $cors_origin_2d7a0a24bfaef3a1a836 {": 1,
+		"map $http_origin $cors_origin_aff9cf3167a06c7d7072 {": 1,
+		"map $http_origin": 2,
+	}
+
+	maps := string(executeMaps(conf))
+	for expSubStr, expCount := range expSubStrings {
+		if expCount != strings.Count(maps, expSubStr) {
+			t.Errorf(
+				"executeMaps() did not generate maps with substring %q %d times. Maps: %v",
+				expSubStr,
+				expCount,
+				maps,
+			)
+		}
+	}
+
+	// the locations of both routes reference the single map
+	g := NewGomegaWithT(t)
+	g.Expect(createCORS(cors1).AllowOrigin).To(Equal("$cors_origin_2d7a0a24bfaef3a1a836"))
+	g.Expect(createCORS(cors2).AllowOrigin).To(Equal(createCORS(cors1).AllowOrigin))
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
+)
+
+// createProxySSLName creates the server name that a location sends with SNI to the HTTPS backends of a group.
+// If the group doesn't need a split, it is the name of its backend. Otherwise, it is the variable of the map created
+// by createProxySSLNameMap, which evaluates to the name of the backend selected by the split clients.
+func createProxySSLName(group graph.BackendGroup) string {
+	if backendGroupNeedsSplit(group) {
+		return "$" + createProxySSLNameVariableName(group)
+	}
+
+	if len(group.Backends) == 0 || !group.Backends[0].Valid {
+		return ""
+	}
+
+	return dataplane.TLSServerName(group.Backends[0].Svc)
+}
+
+// createProxySSLNameMap creates the map for a group that needs a split, which maps the backend selected by the split
+// clients to its server name. If multiple backends of the group have the same upstream, the first of them determines
+// the name. For the other values, such as the invalid backend ref upstream, the map evaluates to $proxy_host,
+// the default server name of NGINX.
+func createProxySSLNameMap(group graph.BackendGroup) http.Map {
+	params := make([]http.MapParameter, 0, len(group.Backends)+1)
+	upstreams := make(map[string]struct{})
+
+	for _, b := range group.Backends {
+		if !b.Valid {
+			continue
+		}
+
+		if _, exist := upstreams[b.Name]; exist {
+			continue
+		}
+		upstreams[b.Name] = struct{}{}
+
+		params = append(params, http.MapParameter{
+			Value:  `"` + b.Name + `"`,
+			Result: `"` + dataplane.TLSServerName(b.Svc) + `"`,
+		})
+	}
+
+	params = append(params, http.MapParameter{
+		Value:  "default",
+		Result: "$proxy_host",
+	})
+
+	return http.Map{
+		Source:     "$" + convertStringToSafeVariableName(group.GroupName()),
+		Variable:   createProxySSLNameVariableName(group),
+		Parameters: params,
+	}
+}
+
+func createProxySSLNameVariableName(group graph.BackendGroup) string {
+	return convertStringToSafeVariableName(group.GroupName()) + "_ssl_name"
+}
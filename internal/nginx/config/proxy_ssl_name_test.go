@@ -0,0 +1,167 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
+)
+
+// createHTTPSGroup creates a group that splits the requests between a Service in the cluster and an ExternalName
+// Service.
+func createHTTPSGroup() graph.BackendGroup {
+	return graph.BackendGroup{
+		Source:  types.NamespacedName{Namespace: "test", Name: "hr"},
+		RuleIdx: 0,
+		Backends: []graph.BackendRef{
+			{
+				Name: "test_secure_443",
+				Svc: &v1.Service{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "secure"},
+				},
+				Valid:  true,
+				Weight: 90,
+			},
+			{
+				Name: "test_external_443",
+				Svc: &v1.Service{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "external"},
+					Spec: v1.ServiceSpec{
+						Type:         v1.ServiceTypeExternalName,
+						ExternalName: "api.example.com",
+					},
+				},
+				Valid:  true,
+				Weight: 10,
+			},
+			{
+				Weight: 1,
+			},
+		},
+	}
+}
+
+func TestCreateProxySSLName(t *testing.T) {
+	split := createHTTPSGroup()
+
+	single := split
+	single.Backends = split.Backends[1:2]
+
+	invalid := split
+	invalid.Backends = []graph.BackendRef{{Weight: 1}}
+
+	tests := []struct {
+		msg      string
+		expected string
+		group    graph.BackendGroup
+	}{
+		{
+			group:    single,
+			expected: "api.example.com",
+			msg:      "single backend",
+		},
+		{
+			group:    split,
+			expected: "$test__hr_rule0_ssl_name",
+			msg:      "split backends",
+		},
+		{
+			group:    invalid,
+			expected: "",
+			msg:      "invalid backend",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			g.Expect(createProxySSLName(test.group)).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestCreateProxySSLNameMap(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	group := createHTTPSGroup()
+	// the same upstream as the first backend
+	group.Backends = append(group.Backends, graph.BackendRef{
+		Name:   "test_secure_443",
+		Svc:    &v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "other"}},
+		Valid:  true,
+		Weight: 1,
+	})
+
+	expected := http.Map{
+		Source:   "$test__hr_rule0",
+		Variable: "test__hr_rule0_ssl_name",
+		Parameters: []http.MapParameter{
+			{
+				Value:  `"test_secure_443"`,
+				Result: `"secure.test.svc"`,
+			},
+			{
+				Value:  `"test_external_443"`,
+				Result: `"api.example.com"`,
+			},
+			{
+				Value:  "default",
+				Result: "$proxy_host",
+			},
+		},
+	}
+
+	g.Expect(createProxySSLNameMap(group)).To(Equal(expected))
+}
+
+func TestExecuteMapsProxySSLName(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	group := createHTTPSGroup()
+
+	single := group
+	single.RuleIdx = 1
+	single.Backends = group.Backends[:1]
+
+	createServer := func(groups ...graph.BackendGroup) dataplane.VirtualServer {
+		rules := make([]dataplane.MatchRule, 0, len(groups))
+		for _, group := range groups {
+			rules = append(rules, dataplane.MatchRule{
+				BackendGroup:    group,
+				BackendProtocol: dataplane.BackendProtocolHTTPS,
+			})
+		}
+
+		return dataplane.VirtualServer{
+			PathRules: []dataplane.PathRule{{Path: "/", MatchRules: rules}},
+		}
+	}
+
+	conf := dataplane.Configuration{
+		// the map of the group is generated once
+		HTTPServers: []dataplane.VirtualServer{createServer(group, single)},
+		SSLServers:  []dataplane.VirtualServer{createServer(group)},
+	}
+
+	maps := string(executeMaps(conf))
+
+	expSubStrings := map[string]int{
+		"map $test__hr_rule0 $test__hr_rule0_ssl_name {": 1,
+		`"test_secure_443" "secure.test.svc";`:           1,
+		`"test_external_443" "api.example.com";`:         1,
+		"default $proxy_host;":                           1,
+		"_ssl_name {":                                    1,
+	}
+
+	for expSubStr, expCount := range expSubStrings {
+		g.Expect(strings.Count(maps, expSubStr)).To(Equal(expCount), expSubStr)
+	}
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"sort"
+	gotemplate "text/template"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
+)
+
+var rateLimitsTemplate = gotemplate.Must(gotemplate.New("rateLimits").Parse(rateLimitsTemplateText))
+
+func executeRateLimits(conf dataplane.Configuration) []byte {
+	zones := createRateLimitZones(conf.HTTPServers, conf.SSLServers)
+
+	return execute(rateLimitsTemplate, zones)
+}
+
+// createRateLimitZones creates the rate limit zones of the Listeners that have a rate limit. Every such Listener
+// gets a single zone, which is shared by all its servers, so that the limit applies to all hostnames
+// of the Listener. The zones are sorted by their names.
+func createRateLimitZones(httpServers, sslServers []dataplane.VirtualServer) []http.RateLimitZone {
+	processed := make(map[string]struct{})
+
+	var zones []http.RateLimitZone
+
+	for _, servers := range [][]dataplane.VirtualServer{httpServers, sslServers} {
+		for _, s := range servers {
+			if s.RateLimit == nil {
+				continue
+			}
+
+			if _, exist := processed[s.Listener]; exist {
+				continue
+			}
+			processed[s.Listener] = struct{}{}
+
+			zones = append(zones, http.RateLimitZone{
+				Name: createRateLimitZoneName(s.Listener),
+				Rate: s.RateLimit.Rate,
+			})
+		}
+	}
+
+	sort.Slice(zones, func(i, j int) bool {
+		return zones[i].Name < zones[j].Name
+	})
+
+	return zones
+}
+
+// createRateLimitZoneName returns the name of the rate limit zone of a Listener. The names of the Listeners
+// are unique within the Gateway and only consist of lowercase alphanumeric characters, '-' and '.',
+// which NGINX accepts in the names of the zones.
+func createRateLimitZoneName(listener string) string {
+	return "listener_" + listener
+}
+
+// createRateLimit creates the rate limit of a server. It returns nil if the Listener of the server doesn't have
+// a rate limit.
+func createRateLimit(s dataplane.VirtualServer) *http.RateLimit {
+	if s.RateLimit == nil {
+		return nil
+	}
+
+	return &http.RateLimit{
+		Zone:  createRateLimitZoneName(s.Listener),
+		Burst: s.RateLimit.Burst,
+	}
+}
@@ -0,0 +1,7 @@
+package config
+
+var rateLimitsTemplateText = `
+{{ range $z := . }}
+limit_req_zone $binary_remote_addr zone={{ $z.Name }}:10m rate={{ $z.Rate }};
+{{ end }}
+`
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
+)
+
+func TestExecuteRateLimits(t *testing.T) {
+	conf := dataplane.Configuration{
+		HTTPServers: []dataplane.VirtualServer{
+			{
+				IsDefault: true,
+			},
+			{
+				Hostname:  "example.com",
+				Listener:  "http",
+				RateLimit: &dataplane.RateLimit{Rate: "10r/s", Burst: 20},
+			},
+		},
+		SSLServers: []dataplane.VirtualServer{
+			{
+				Hostname:  "example.com",
+				Listener:  "https",
+				RateLimit: &dataplane.RateLimit{Rate: "600r/m"},
+			},
+		},
+	}
+
+	expSubStrings := map[string]int{
+		"limit_req_zone $binary_remote_addr zone=listener_http:10m rate=10r/s;":   1,
+		"limit_req_zone $binary_remote_addr zone=listener_https:10m rate=600r/m;": 1,
+	}
+
+	rateLimits := string(executeRateLimits(conf))
+	for expSubStr, expCount := range expSubStrings {
+		if expCount != strings.Count(rateLimits, expSubStr) {
+			t.Errorf(
+				"executeRateLimits() did not generate rate limits with substring %q %d times. Rate limits: %v",
+				expSubStr,
+				expCount,
+				rateLimits,
+			)
+		}
+	}
+}
+
+func TestCreateRateLimitZones(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	createServer := func(hostname, listener string, rateLimit *dataplane.RateLimit) dataplane.VirtualServer {
+		return dataplane.VirtualServer{
+			Hostname:  hostname,
+			Listener:  listener,
+			RateLimit: rateLimit,
+		}
+	}
+
+	httpLimit := &dataplane.RateLimit{Rate: "10r/s", Burst: 20}
+
+	httpServers := []dataplane.VirtualServer{
+		{IsDefault: true},
+		// the servers of a listener share the zone
+		createServer("bar.example.com", "http", httpLimit),
+		createServer("foo.example.com", "http", httpLimit),
+		createServer("baz.example.com", "no-limit", nil),
+	}
+	sslServers := []dataplane.VirtualServer{
+		{IsDefault: true},
+		createServer("foo.example.com", "https", &dataplane.RateLimit{Rate: "600r/m"}),
+	}
+
+	expZones := []http.RateLimitZone{
+		{
+			Name: "listener_http",
+			Rate: "10r/s",
+		},
+		{
+			Name: "listener_https",
+			Rate: "600r/m",
+		},
+	}
+
+	g.Expect(createRateLimitZones(httpServers, sslServers)).To(Equal(expZones))
+	g.Expect(createRateLimitZones(nil, nil)).To(BeEmpty())
+}
+
+func TestCreateRateLimit(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(createRateLimit(dataplane.VirtualServer{Listener: "http"})).To(BeNil())
+
+	s := dataplane.VirtualServer{
+		Listener:  "http",
+		RateLimit: &dataplane.RateLimit{Rate: "10r/s", Burst: 20},
+	}
+	g.Expect(createRateLimit(s)).To(Equal(&http.RateLimit{Zone: "listener_http", Burst: 20}))
+}
@@ -0,0 +1,227 @@
+package config
+
+import (
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
+)
+
+// createProxySetHeaders creates the request headers that a location passes to the backends of a group according to
+// the RequestHeaderModifiers of the backends. If the group doesn't need a split, the headers have the values of
+// its backend. Otherwise, the value of each header is the variable of the map created by createRequestHeaderMaps,
+// which evaluates to the value of the backend selected by the split clients.
+func createProxySetHeaders(group graph.BackendGroup) []http.Header {
+	if !backendGroupNeedsSplit(group) {
+		if len(group.Backends) == 0 || !group.Backends[0].Valid {
+			return nil
+		}
+
+		values := createRequestHeaderValues(group.Backends[0].RequestHeaderModifier)
+		if len(values) == 0 {
+			return nil
+		}
+
+		headers := make([]http.Header, 0, len(values))
+		for _, name := range sortedHeaderNames(values) {
+			headers = append(headers, values[name])
+		}
+
+		return headers
+	}
+
+	names := collectRequestHeaders(group)
+	if len(names) == 0 {
+		return nil
+	}
+
+	headers := make([]http.Header, 0, len(names))
+	for _, name := range sortedHeaderNames(names) {
+		headers = append(headers, http.Header{
+			Name:  names[name].Name,
+			Value: "$" + createRequestHeaderVariableName(group, name),
+		})
+	}
+
+	return headers
+}
+
+// createRequestHeaderMaps creates the maps for the RequestHeaderModifiers of the backends of the groups.
+// For a group that needs a split, it creates a map for each modified header, which maps the backend selected by
+// the split clients to the value of the header for that backend. The backends that don't modify the header
+// pass the header of the client request. If multiple backends of a group have the same upstream, the first
+// of them determines the value.
+// For a header that a backend adds, it creates a map that evaluates to the value of the header of the client
+// request followed by a comma, so that the added value is appended to it, or to an empty string if the client
+// request doesn't have the header.
+func createRequestHeaderMaps(groups []graph.BackendGroup) []http.Map {
+	var maps []http.Map
+
+	addedHeaders := make(map[string]struct{})
+
+	for _, group := range groups {
+		for _, b := range group.Backends {
+			if !b.Valid || b.RequestHeaderModifier == nil {
+				continue
+			}
+
+			for _, h := range b.RequestHeaderModifier.Add {
+				name := strings.ToLower(string(h.Name))
+				if _, exist := addedHeaders[name]; exist {
+					continue
+				}
+				addedHeaders[name] = struct{}{}
+
+				maps = append(maps, createAddedRequestHeaderMap(name))
+			}
+		}
+
+		if !backendGroupNeedsSplit(group) {
+			continue
+		}
+
+		names := collectRequestHeaders(group)
+
+		for _, name := range sortedHeaderNames(names) {
+			maps = append(maps, createSplitRequestHeaderMap(group, name))
+		}
+	}
+
+	return maps
+}
+
+func createSplitRequestHeaderMap(group graph.BackendGroup, name string) http.Map {
+	params := make([]http.MapParameter, 0, len(group.Backends)+1)
+	upstreams := make(map[string]struct{})
+
+	for _, b := range group.Backends {
+		if !b.Valid {
+			continue
+		}
+
+		if _, exist := upstreams[b.Name]; exist {
+			continue
+		}
+		upstreams[b.Name] = struct{}{}
+
+		h, modified := createRequestHeaderValues(b.RequestHeaderModifier)[name]
+		if !modified {
+			continue
+		}
+
+		params = append(params, http.MapParameter{
+			Value:  `"` + b.Name + `"`,
+			Result: `"` + h.Value + `"`,
+		})
+	}
+
+	params = append(params, http.MapParameter{
+		Value:  "default",
+		Result: `"$` + createClientRequestHeaderVariableName(name) + `"`,
+	})
+
+	return http.Map{
+		Source:     "$" + convertStringToSafeVariableName(group.GroupName()),
+		Variable:   createRequestHeaderVariableName(group, name),
+		Parameters: params,
+	}
+}
+
+func createAddedRequestHeaderMap(name string) http.Map {
+	clientVariable := "$" + createClientRequestHeaderVariableName(name)
+
+	return http.Map{
+		Source:   clientVariable,
+		Variable: createAddedRequestHeaderVariableName(name),
+		Parameters: []http.MapParameter{
+			{
+				Value:  `""`,
+				Result: `""`,
+			},
+			{
+				Value:  "default",
+				Result: `"` + clientVariable + `,"`,
+			},
+		},
+	}
+}
+
+// createRequestHeaderValues creates the headers that a RequestHeaderModifier modifies, keyed by their lowercase
+// names. The values are escaped for the double-quoted NGINX strings and can include variables.
+// A removed header has an empty value, so that NGINX doesn't pass it. The names and the values are expected to be
+// validated.
+func createRequestHeaderValues(modifier *v1beta1.HTTPHeaderFilter) map[string]http.Header {
+	if modifier == nil {
+		return nil
+	}
+
+	values := make(map[string]http.Header)
+
+	for _, h := range modifier.Add {
+		name := strings.ToLower(string(h.Name))
+		values[name] = http.Header{
+			Name:  string(h.Name),
+			Value: "${" + createAddedRequestHeaderVariableName(name) + "}" + escapeNGINXString(h.Value),
+		}
+	}
+
+	for _, h := range modifier.Set {
+		values[strings.ToLower(string(h.Name))] = http.Header{
+			Name:  string(h.Name),
+			Value: escapeNGINXString(h.Value),
+		}
+	}
+
+	for _, name := range modifier.Remove {
+		values[strings.ToLower(name)] = http.Header{Name: name}
+	}
+
+	return values
+}
+
+// collectRequestHeaders returns the headers that the backends of the group modify, keyed by their lowercase names.
+// For each name, it returns the header of the first backend that modifies it.
+func collectRequestHeaders(group graph.BackendGroup) map[string]http.Header {
+	names := make(map[string]http.Header)
+
+	for _, b := range group.Backends {
+		if !b.Valid {
+			continue
+		}
+
+		for name, h := range createRequestHeaderValues(b.RequestHeaderModifier) {
+			if _, exist := names[name]; !exist {
+				names[name] = h
+			}
+		}
+	}
+
+	return names
+}
+
+func sortedHeaderNames(headers map[string]http.Header) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// createClientRequestHeaderVariableName returns the name of the NGINX variable of the header of the client request.
+func createClientRequestHeaderVariableName(name string) string {
+	return "http_" + convertStringToSafeVariableName(name)
+}
+
+func createAddedRequestHeaderVariableName(name string) string {
+	return "request_header_add_" + convertStringToSafeVariableName(name)
+}
+
+func createRequestHeaderVariableName(group graph.BackendGroup, name string) string {
+	return convertStringToSafeVariableName(group.GroupName()) + "_header_" + convertStringToSafeVariableName(name)
+}
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
+)
+
+// createWeightedGroupWithHeaderModifier creates a group that splits the requests between a stable backend
+// without modifications and a canary backend that modifies the headers.
+func createWeightedGroupWithHeaderModifier() graph.BackendGroup {
+	return graph.BackendGroup{
+		Source:  types.NamespacedName{Namespace: "test", Name: "hr"},
+		RuleIdx: 0,
+		Backends: []graph.BackendRef{
+			{
+				Name:   "test_stable_80",
+				Valid:  true,
+				Weight: 90,
+			},
+			{
+				Name:   "test_canary_80",
+				Valid:  true,
+				Weight: 10,
+				RequestHeaderModifier: &v1beta1.HTTPHeaderFilter{
+					Set:    []v1beta1.HTTPHeader{{Name: "X-Version", Value: `canary "v2"`}},
+					Add:    []v1beta1.HTTPHeader{{Name: "X-Env", Value: "canary"}},
+					Remove: []string{"X-Debug"},
+				},
+			},
+		},
+	}
+}
+
+func TestCreateProxySetHeaders(t *testing.T) {
+	single := graph.BackendGroup{
+		Source: types.NamespacedName{Namespace: "test", Name: "hr"},
+		Backends: []graph.BackendRef{
+			{
+				Name:   "test_foo_80",
+				Valid:  true,
+				Weight: 1,
+				RequestHeaderModifier: &v1beta1.HTTPHeaderFilter{
+					Set:    []v1beta1.HTTPHeader{{Name: "X-Version", Value: "v1"}},
+					Add:    []v1beta1.HTTPHeader{{Name: "X-Env", Value: "prod"}},
+					Remove: []string{"X-Debug"},
+				},
+			},
+		},
+	}
+
+	invalid := single
+	invalid.Backends = []graph.BackendRef{{Weight: 1}}
+
+	tests := []struct {
+		msg      string
+		expected []http.Header
+		group    graph.BackendGroup
+	}{
+		{
+			msg:   "no backends",
+			group: graph.BackendGroup{},
+		},
+		{
+			msg:   "invalid backend",
+			group: invalid,
+		},
+		{
+			msg:   "single backend",
+			group: single,
+			expected: []http.Header{
+				{Name: "X-Debug", Value: ""},
+				{Name: "X-Env", Value: "${request_header_add_x_env}prod"},
+				{Name: "X-Version", Value: "v1"},
+			},
+		},
+		{
+			msg:   "weighted split",
+			group: createWeightedGroupWithHeaderModifier(),
+			expected: []http.Header{
+				{Name: "X-Debug", Value: "$test__hr_rule0_header_x_debug"},
+				{Name: "X-Env", Value: "$test__hr_rule0_header_x_env"},
+				{Name: "X-Version", Value: "$test__hr_rule0_header_x_version"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			headers := createProxySetHeaders(test.group)
+			if test.expected == nil {
+				g.Expect(headers).To(BeEmpty())
+				return
+			}
+			g.Expect(headers).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestCreateRequestHeaderMaps(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	group := createWeightedGroupWithHeaderModifier()
+
+	// the backend of another group adds the same header, so the map of the added header is created once
+	other := graph.BackendGroup{
+		Source: types.NamespacedName{Namespace: "test", Name: "other"},
+		Backends: []graph.BackendRef{
+			{
+				Name:   "test_foo_80",
+				Valid:  true,
+				Weight: 1,
+				RequestHeaderModifier: &v1beta1.HTTPHeaderFilter{
+					Add: []v1beta1.HTTPHeader{{Name: "x-env", Value: "prod"}},
+				},
+			},
+		},
+	}
+
+	expected := []http.Map{
+		{
+			Source:   "$http_x_env",
+			Variable: "request_header_add_x_env",
+			Parameters: []http.MapParameter{
+				{Value: `""`, Result: `""`},
+				{Value: "default", Result: `"$http_x_env,"`},
+			},
+		},
+		{
+			Source:   "$test__hr_rule0",
+			Variable: "test__hr_rule0_header_x_debug",
+			Parameters: []http.MapParameter{
+				{Value: `"test_canary_80"`, Result: `""`},
+				{Value: "default", Result: `"$http_x_debug"`},
+			},
+		},
+		{
+			Source:   "$test__hr_rule0",
+			Variable: "test__hr_rule0_header_x_env",
+			Parameters: []http.MapParameter{
+				{Value: `"test_canary_80"`, Result: `"${request_header_add_x_env}canary"`},
+				{Value: "default", Result: `"$http_x_env"`},
+			},
+		},
+		{
+			Source:   "$test__hr_rule0",
+			Variable: "test__hr_rule0_header_x_version",
+			Parameters: []http.MapParameter{
+				{Value: `"test_canary_80"`, Result: `"canary \"v2\""`},
+				{Value: "default", Result: `"$http_x_version"`},
+			},
+		},
+	}
+
+	g.Expect(createRequestHeaderMaps([]graph.BackendGroup{group, other})).To(Equal(expected))
+}
+
+func TestExecuteMapsRequestHeaders(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	conf := dataplane.Configuration{
+		BackendGroups: []graph.BackendGroup{createWeightedGroupWithHeaderModifier()},
+	}
+
+	maps := string(executeMaps(conf))
+
+	expSubStrings := map[string]int{
+		"map $http_x_env $request_header_add_x_env {":            1,
+		"map $test__hr_rule0 $test__hr_rule0_header_x_version {": 1,
+		`"test_canary_80" "canary \"v2\"";`:                      1,
+		`"test_canary_80" "${request_header_add_x_env}canary";`:  1,
+		`"test_canary_80" "";`:                                   1,
+		"test_stable_80":                                         0,
+		`default "$http_x_version";`:                             1,
+		"map $test__hr_rule0 ":                                   3,
+	}
+
+	for expSubStr, expCount := range expSubStrings {
+		g.Expect(strings.Count(maps, expSubStr)).To(Equal(expCount), expSubStr)
+	}
+}
@@ -3,87 +3,315 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	gotemplate "text/template"
 
 	"sigs.k8s.io/gateway-api/apis/v1beta1"
 
+	"github.com/nginxinc/nginx-kubernetes-gateway/apis/v1alpha1"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
 )
 
 var serversTemplate = gotemplate.Must(gotemplate.New("servers").Parse(serversTemplateText))
 
 const rootPath = "/"
 
-func executeServers(conf dataplane.Configuration) []byte {
-	servers := createServers(conf.HTTPServers, conf.SSLServers)
+func executeServers(
+	conf dataplane.Configuration,
+	comments, http3, requestID, forwardedHeaders, forwardedProto bool,
+	accessLog string,
+	securityHeaders []http.Header,
+	listen http.ListenOptions,
+) []byte {
+	servers := createServers(
+		conf.HTTPServers,
+		conf.SSLServers,
+		conf.Addresses,
+		comments,
+		http3,
+		requestID,
+		forwardedHeaders,
+		forwardedProto,
+		accessLog,
+		securityHeaders,
+	)
+
+	if comments {
+		addServerComments(servers, conf)
+	}
+
+	for i := range servers {
+		if servers[i].IsDefaultHTTP || servers[i].IsDefaultSSL {
+			servers[i].Listen = listen
+		}
+	}
+
+	if conf.Maintenance != nil {
+		applyMaintenance(servers, conf.Maintenance)
+	}
+
+	if conf.ClientHeaderBuffers != nil {
+		buffers := createClientHeaderBuffers(*conf.ClientHeaderBuffers)
+		for i := range servers {
+			servers[i].ClientHeaderBuffers = buffers
+		}
+	}
 
 	return execute(serversTemplate, servers)
 }
 
-func createServers(httpServers, sslServers []dataplane.VirtualServer) []http.Server {
+// createClientHeaderBuffers creates the client header buffers of all servers, including the default ones, because
+// NGINX reads the headers of a request before it selects a server for the request, using the buffers of
+// the default server.
+func createClientHeaderBuffers(buffers dataplane.ClientHeaderBuffers) *http.ClientHeaderBuffers {
+	b := &http.ClientHeaderBuffers{
+		Size: buffers.Size,
+	}
+
+	if buffers.LargeSize != "" {
+		b.Large = strconv.FormatInt(int64(buffers.LargeNumber), 10) + " " + buffers.LargeSize
+	}
+
+	return b
+}
+
+// applyMaintenance makes the servers, except the default ones, respond to all requests with the maintenance response
+// instead of their locations. The other configuration of the servers, such as TLS, is preserved.
+func applyMaintenance(servers []http.Server, maintenance *dataplane.Maintenance) {
+	for i := range servers {
+		if servers[i].IsDefaultHTTP || servers[i].IsDefaultSSL {
+			continue
+		}
+
+		servers[i].Locations = nil
+		servers[i].Maintenance = &http.Maintenance{
+			Page: escapeNGINXString(maintenance.Page),
+		}
+	}
+}
+
+// createServers creates the HTTP and HTTPS servers. If http3 is true, the HTTPS servers also accept HTTP/3
+// connections over QUIC. If requestID is true, the locations propagate the request ID, unless their routes
+// disable it. If forwardedHeaders is true, the locations that proxy requests pass the X-Forwarded-* headers.
+// If forwardedProto is true, the HTTP servers don't redirect the requests to HTTPS that the trusted proxies
+// forwarded from HTTPS, as reported by the X-Forwarded-Proto header, and proxy them instead.
+// accessLog is the destination of the access log of the locations whose routes enable it.
+// All servers add the securityHeaders to their responses.
+func createServers(
+	httpServers, sslServers []dataplane.VirtualServer,
+	addresses []string,
+	comments bool,
+	http3 bool,
+	requestID bool,
+	forwardedHeaders bool,
+	forwardedProto bool,
+	accessLog string,
+	securityHeaders []http.Header,
+) []http.Server {
 	servers := make([]http.Server, 0, len(httpServers)+len(sslServers))
 
 	for _, s := range httpServers {
-		servers = append(servers, createServer(s))
+		servers = append(servers, createServer(s, comments, requestID, forwardedProto, accessLog))
 	}
 
 	for _, s := range sslServers {
-		servers = append(servers, createSSLServer(s))
+		server := createSSLServer(s, comments, requestID, accessLog)
+		server.HTTP3 = http3
+		servers = append(servers, server)
+	}
+
+	// http2 is a parameter of the listening socket rather than of a server, so NGINX enables HTTP/2 for all servers
+	// on the same address and port if one of them enables it. Because all HTTPS servers listen on the same addresses
+	// and port, they all enable HTTP/2 if one of them needs it, so that their listen directives are the same.
+	httpsServers := servers[len(httpServers):]
+	if hasHTTP2Servers(httpsServers) {
+		for i := range httpsServers {
+			httpsServers[i].HTTP2 = true
+		}
+	}
+
+	listenAddresses := createListenAddresses(addresses)
+	for i := range servers {
+		servers[i].Addresses = listenAddresses
+		servers[i].SecurityHeaders = securityHeaders
+		servers[i].ForwardedHeaders = forwardedHeaders
 	}
 
 	return servers
 }
 
-func createSSLServer(virtualServer dataplane.VirtualServer) http.Server {
+// createSecurityHeaders converts the security headers to the response headers of the servers,
+// sorted by name so that the generated configuration is stable.
+func createSecurityHeaders(headers map[string]string) []http.Header {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	result := make([]http.Header, 0, len(headers))
+
+	for name, value := range headers {
+		result = append(result, http.Header{Name: name, Value: value})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// createListenAddresses converts the IP addresses to the addresses of the listen directive,
+// which requires IPv6 addresses to be enclosed in square brackets.
+func createListenAddresses(addresses []string) []string {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	listenAddresses := make([]string, 0, len(addresses))
+
+	for _, a := range addresses {
+		if strings.Contains(a, ":") {
+			a = "[" + a + "]"
+		}
+
+		listenAddresses = append(listenAddresses, a)
+	}
+
+	return listenAddresses
+}
+
+func createSSLServer(virtualServer dataplane.VirtualServer, comments, requestID bool, accessLog string) http.Server {
 	if virtualServer.IsDefault {
-		return createDefaultSSLServer()
+		return createDefaultSSLServer(virtualServer.SSL)
 	}
 
+	locs := createLocations(
+		virtualServer.PathRules,
+		443,
+		virtualServer.DefaultBackend,
+		comments,
+		requestID,
+		false,
+		accessLog,
+	)
+
 	return http.Server{
 		ServerName: virtualServer.Hostname,
+		HTTP2:      hasGRPCLocations(locs),
 		SSL: &http.SSL{
-			Certificate:    virtualServer.SSL.CertificatePath,
-			CertificateKey: virtualServer.SSL.CertificatePath,
+			Certificate:             virtualServer.SSL.CertificatePath,
+			CertificateKey:          virtualServer.SSL.CertificatePath,
+			SecondaryCertificate:    virtualServer.SSL.SecondaryCertificatePath,
+			SecondaryCertificateKey: virtualServer.SSL.SecondaryCertificatePath,
+			Protocols:               virtualServer.SSL.Options.Protocols,
+			Ciphers:                 virtualServer.SSL.Options.Ciphers,
+			ClientCertificate:       virtualServer.SSL.ClientCertificatePath,
+			VerifyClient:            virtualServer.SSL.Options.VerifyClient,
+			VerifyClientErrorStatus: virtualServer.SSL.Options.VerifyClientErrorStatus,
 		},
-		Locations: createLocations(virtualServer.PathRules, 443),
+		Locations: locs,
+		RateLimit: createRateLimit(virtualServer),
+	}
+}
+
+// hasHTTP2Servers returns true if any of the servers enables HTTP/2.
+func hasHTTP2Servers(servers []http.Server) bool {
+	for _, s := range servers {
+		if s.HTTP2 {
+			return true
+		}
 	}
+
+	return false
+}
+
+// hasGRPCLocations returns true if any of the locations proxies requests using the gRPC module.
+// gRPC clients require HTTP/2, which NGINX negotiates with the clients of the HTTPS servers through ALPN.
+func hasGRPCLocations(locs []http.Location) bool {
+	for _, l := range locs {
+		if l.GRPCPass != "" {
+			return true
+		}
+	}
+
+	return false
 }
 
-func createServer(virtualServer dataplane.VirtualServer) http.Server {
+func createServer(
+	virtualServer dataplane.VirtualServer,
+	comments, requestID, forwardedProto bool,
+	accessLog string,
+) http.Server {
 	if virtualServer.IsDefault {
 		return createDefaultHTTPServer()
 	}
 
+	locs := createLocations(
+		virtualServer.PathRules,
+		80,
+		virtualServer.DefaultBackend,
+		comments,
+		requestID,
+		forwardedProto,
+		accessLog,
+	)
+
 	return http.Server{
 		ServerName: virtualServer.Hostname,
-		Locations:  createLocations(virtualServer.PathRules, 80),
+		Locations:  locs,
+		RateLimit:  createRateLimit(virtualServer),
 	}
 }
 
-func createLocations(pathRules []dataplane.PathRule, listenerPort int) []http.Location {
+// createLocations creates the locations of a server. If comments is true, the locations have comments that name
+// the HTTPRoutes and backends that they are generated from. requestID is the default of the propagation
+// of the request ID by the locations that proxy requests, which the options of their routes override.
+// If forwardedProto is true, the locations of the redirects to HTTPS don't redirect the requests forwarded from HTTPS
+// by the trusted proxies and proxy them to the backends of the rule instead, so that the redirects don't loop
+// behind a proxy that terminates TLS.
+// accessLog is the destination of the access log of the locations whose routes enable it.
+func createLocations(
+	pathRules []dataplane.PathRule,
+	listenerPort int,
+	defaultBackend *dataplane.DefaultBackend,
+	comments bool,
+	requestID bool,
+	forwardedProto bool,
+	accessLog string,
+) []http.Location {
 	lenPathRules := len(pathRules)
 
 	if lenPathRules == 0 {
-		return []http.Location{createDefaultRootLocation()}
+		return []http.Location{createDefaultRootLocation(defaultBackend, comments)}
 	}
 
 	// To calculate the maximum number of locations, we need to take into account the following:
 	// 1. Each match rule for a path rule will have one location.
 	// 2. Each path rule may have an additional location if it contains non-path-only matches.
-	// 3. There may be an additional location for the default root path.
+	// 3. The location of a path-only match or the additional location is split into two locations for a path
+	// other than the root path. See createPrefixPaths.
+	// 4. There may be an additional location for the default root path.
 	maxLocs := 1
 	for _, rules := range pathRules {
-		maxLocs += len(rules.MatchRules) + 1
+		maxLocs += len(rules.MatchRules) + 2
 	}
 
 	locs := make([]http.Location, 0, maxLocs)
 
 	rootPathExists := false
 
+	// The matches and the locations of a path rule are collected into the same slices for all rules.
+	var matches []httpMatch
+	var ruleLocs []http.Location
+
 	for _, rule := range pathRules {
-		matches := make([]httpMatch, 0, len(rule.MatchRules))
+		matches = matches[:0]
+		ruleLocs = ruleLocs[:0]
 
 		if rule.Path == rootPath {
 			rootPathExists = true
@@ -96,39 +324,95 @@ func createLocations(pathRules []dataplane.PathRule, listenerPort int) []http.Lo
 
 			// handle case where the only route is a path-only match
 			// generate a standard location block without http_matches.
-			if len(rule.MatchRules) == 1 && isPathOnlyMatch(m) {
+			if len(rule.MatchRules) == 1 && isPathOnlyMatch(m) && r.Options.GeoMatch == nil {
 				loc = http.Location{
 					Path: rule.Path,
 				}
 			} else {
 				path := createPathForMatch(rule.Path, matchRuleIdx)
 				loc = createMatchLocation(path)
-				matches = append(matches, createHTTPMatch(m, path))
+				matches = append(matches, createHTTPMatch(m, path, r.Options.GeoMatch))
+			}
+
+			if comments {
+				loc.Comment = createMatchRuleComment(r)
 			}
 
+			loc.AccessLog = createAccessLog(r.Options, accessLog)
+
 			// FIXME(pleshakov): There could be a case when the filter has the type set but not the corresponding field.
 			// For example, type is v1beta1.HTTPRouteFilterRequestRedirect, but RequestRedirect field is nil.
 			// The validation webhook catches that.
 			// If it doesn't work as expected, such situation is silently handled below in findFirstFilters.
 			// Consider reporting an error. But that should be done in a separate validation layer.
 
-			// RequestRedirect and proxying are mutually exclusive.
+			// Requests must receive an error response if an ExtensionRef filter cannot be resolved.
+			if r.Filters.InvalidExtensionRef {
+				loc.Return = &http.Return{Code: http.StatusInternalServerError}
+
+				ruleLocs = append(ruleLocs, loc)
+				continue
+			}
+
+			if r.Filters.CORSPolicy != nil {
+				loc.CORS = createCORS(r.Filters.CORSPolicy)
+			}
+
+			// RequestRedirect and proxying are mutually exclusive, except for the redirects to HTTPS of the requests
+			// that the trusted proxies forwarded from HTTPS, which are proxied instead.
 			if r.Filters.RequestRedirect != nil {
-				loc.Return = createReturnValForRedirectFilter(r.Filters.RequestRedirect, listenerPort)
+				loc.Return = createReturnValForRedirectFilter(r.Filters.RequestRedirect, listenerPort, rule.Path)
+
+				if !forwardedProto || !isHTTPSRedirect(r.Filters.RequestRedirect) || r.Filters.DirectResponse != nil {
+					ruleLocs = append(ruleLocs, loc)
+					continue
+				}
+
+				loc.Return.SkipForwardedHTTPS = true
+			}
+
+			// A DirectResponse ignores the backends of the rule.
+			if r.Filters.DirectResponse != nil {
+				loc.Return = createReturnValForDirectResponse(r.Filters.DirectResponse)
+				loc.DefaultType = getDirectResponseContentType(r.Filters.DirectResponse)
 
-				locs = append(locs, loc)
+				ruleLocs = append(ruleLocs, loc)
 				continue
 			}
 
 			backendName := backendGroupName(r.BackendGroup)
+			loc.ProxySetHeaders = createProxySetHeaders(r.BackendGroup)
 
-			if backendGroupNeedsSplit(r.BackendGroup) {
+			loc.RequestID = requestID
+			if r.Options.RequestID != nil {
+				loc.RequestID = *r.Options.RequestID
+			}
+
+			// NGINX proxies requests to HTTP/2 and gRPC backends using the gRPC module, which always streams them.
+			switch {
+			case r.BackendProtocol.UsesGRPCModule() && backendGroupNeedsSplit(r.BackendGroup):
+				loc.GRPCPass = createGRPCPassForVar(backendName)
+			case r.BackendProtocol.UsesGRPCModule():
+				loc.GRPCPass = createGRPCPass(backendName)
+			case r.BackendProtocol == dataplane.BackendProtocolHTTPS && backendGroupNeedsSplit(r.BackendGroup):
+				loc.ProxyPass = createHTTPSProxyPassForVar(backendName)
+				loc.ProxySSLName = createProxySSLName(r.BackendGroup)
+			case r.BackendProtocol == dataplane.BackendProtocolHTTPS:
+				loc.ProxyPass = createHTTPSProxyPass(backendName)
+				loc.ProxySSLName = createProxySSLName(r.BackendGroup)
+			case backendGroupNeedsSplit(r.BackendGroup):
 				loc.ProxyPass = createProxyPassForVar(backendName)
-			} else {
+			default:
 				loc.ProxyPass = createProxyPass(backendName)
 			}
 
-			locs = append(locs, loc)
+			if loc.ProxyPass != "" {
+				loc.Streaming = r.Options.Streaming
+				loc.UpstreamHost = r.Options.UpstreamHost
+				loc.ProxyCache = createProxyCache(r)
+			}
+
+			ruleLocs = append(ruleLocs, loc)
 		}
 
 		if len(matches) > 0 {
@@ -143,26 +427,101 @@ func createLocations(pathRules []dataplane.PathRule, listenerPort int) []http.Lo
 				HTTPMatchVar: string(b),
 			}
 
-			locs = append(locs, pathLoc)
+			if comments {
+				pathLoc.Comment = createPathRuleComment(rule)
+			}
+
+			ruleLocs = append(ruleLocs, pathLoc)
 		}
+
+		locs = appendPrefixLocations(locs, ruleLocs, rule.Path)
 	}
 
 	if !rootPathExists {
-		locs = append(locs, createDefaultRootLocation())
+		locs = append(locs, createDefaultRootLocation(defaultBackend, comments))
 	}
 
 	return locs
 }
 
-func createDefaultSSLServer() http.Server {
-	return http.Server{IsDefaultSSL: true}
+// createAccessLog creates the access log of the location of a route. It returns nil if the route doesn't override
+// the access log of the http context.
+func createAccessLog(opts dataplane.RouteOptions, path string) *http.AccessLog {
+	if opts.AccessLog == nil {
+		return nil
+	}
+
+	if !*opts.AccessLog {
+		return &http.AccessLog{Path: accessLogOff}
+	}
+
+	return &http.AccessLog{
+		Path:   path,
+		Format: opts.AccessLogFormat,
+	}
+}
+
+// createDefaultSSLServer creates the default HTTPS server. Without ssl, it rejects the TLS handshakes.
+func createDefaultSSLServer(ssl *dataplane.SSL) http.Server {
+	s := http.Server{IsDefaultSSL: true}
+
+	if ssl != nil {
+		s.SSL = &http.SSL{
+			Certificate:             ssl.CertificatePath,
+			CertificateKey:          ssl.CertificatePath,
+			SecondaryCertificate:    ssl.SecondaryCertificatePath,
+			SecondaryCertificateKey: ssl.SecondaryCertificatePath,
+		}
+	}
+
+	return s
 }
 
 func createDefaultHTTPServer() http.Server {
 	return http.Server{IsDefaultHTTP: true}
 }
 
-func createReturnValForRedirectFilter(filter *v1beta1.HTTPRequestRedirectFilter, listenerPort int) *http.Return {
+// defaultDirectResponseContentType is the content type of a DirectResponse that doesn't specify one.
+const defaultDirectResponseContentType = "text/plain"
+
+// createReturnValForDirectResponse creates the return for the DirectResponse. The body is always quoted,
+// so that an empty body results in a response without a body rather than in a redirect.
+func createReturnValForDirectResponse(response *v1alpha1.DirectResponse) *http.Return {
+	return &http.Return{
+		Code: http.StatusCode(response.Spec.StatusCode),
+		URL:  quoteNGINXString(response.Spec.Body),
+	}
+}
+
+func getDirectResponseContentType(response *v1alpha1.DirectResponse) string {
+	if response.Spec.ContentType == "" {
+		return defaultDirectResponseContentType
+	}
+
+	return response.Spec.ContentType
+}
+
+// quoteNGINXString quotes the string for the NGINX configuration. Unlike Go quoting, it keeps non-printable
+// and non-ASCII characters as is, because NGINX doesn't support Go escape sequences.
+// The string must not contain variables.
+func quoteNGINXString(s string) string {
+	return `"` + escapeNGINXString(s) + `"`
+}
+
+// escapeNGINXString escapes the backslashes and the double quotes of the string, so that it can be used
+// in a double-quoted NGINX string.
+func escapeNGINXString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, `"`, `\"`)
+}
+
+// createReturnValForRedirectFilter creates the return for the redirect filter.
+// pathPrefix is the PathPrefix match of the rule, which the ReplacePrefixMatch path modifier replaces.
+func createReturnValForRedirectFilter(
+	filter *v1beta1.HTTPRequestRedirectFilter,
+	listenerPort int,
+	pathPrefix string,
+) *http.Return {
 	if filter == nil {
 		return nil
 	}
@@ -191,12 +550,72 @@ func createReturnValForRedirectFilter(filter *v1beta1.HTTPRequestRedirectFilter,
 		scheme = *filter.Scheme
 	}
 
+	base := fmt.Sprintf("%s://%s:%d", scheme, hostname, port)
+
+	if filter.Path == nil {
+		return &http.Return{
+			Code: code,
+			URL:  base + "$request_uri",
+		}
+	}
+
+	switch filter.Path.Type {
+	case v1beta1.FullPathHTTPPathModifier:
+		if filter.Path.ReplaceFullPath == nil {
+			break
+		}
+
+		return &http.Return{
+			Code: code,
+			URL:  base + *filter.Path.ReplaceFullPath + "$is_args$args",
+		}
+	case v1beta1.PrefixMatchHTTPPathModifier:
+		if filter.Path.ReplacePrefixMatch == nil {
+			break
+		}
+
+		regex, path := createReplacePrefixMatch(pathPrefix, *filter.Path.ReplacePrefixMatch)
+
+		return &http.Return{
+			Code:            code,
+			RequestURIRegex: regex,
+			URL:             base + path + "$is_args$args",
+			FallbackURL:     base + *filter.Path.ReplacePrefixMatch + "$is_args$args",
+		}
+	}
+
+	// FIXME(pleshakov): Same as the FIXME about StatusCode above.
 	return &http.Return{
 		Code: code,
-		URL:  fmt.Sprintf("%s://%s:%d$request_uri", scheme, hostname, port),
+		URL:  base + "$request_uri",
 	}
 }
 
+// isHTTPSRedirect returns true if the redirect filter redirects the requests to HTTPS.
+func isHTTPSRedirect(filter *v1beta1.HTTPRequestRedirectFilter) bool {
+	return filter.Scheme != nil && strings.EqualFold(*filter.Scheme, "https")
+}
+
+// createReplacePrefixMatch returns the regex for $request_uri and the path of the redirect that replace
+// the prefix of the path of a request with the replacement.
+// As the PathPrefix match, the prefix matches full path elements and a trailing slash of the prefix
+// or the replacement is ignored. As a result, the slashes of the path are neither duplicated nor dropped:
+// with the prefix /old/ and the replacement /new, /old redirects to /new, /old/ to /new/ and /old/foo to /new/foo.
+// The regex captures the rest of the path after the prefix as $1.
+// Because $request_uri is not normalized, it might not match the regex even if the request matches the prefix,
+// for example, when the path includes percent-encoded characters. Such requests are redirected to the replacement.
+func createReplacePrefixMatch(prefix, replacement string) (regex string, path string) {
+	prefix = regexp.QuoteMeta(strings.TrimSuffix(prefix, "/"))
+	replacement = strings.TrimSuffix(replacement, "/")
+
+	if replacement == "" {
+		// the rest of the path replaces the whole path, so it must start with a slash
+		return fmt.Sprintf("^%s/?([^?]*)(?:\\?|$)", prefix), "/$1"
+	}
+
+	return fmt.Sprintf("^%s(/[^?]*)?(?:\\?|$)", prefix), replacement + "$1"
+}
+
 // httpMatch is an internal representation of an HTTPRouteMatch.
 // This struct is marshaled into a string and stored as a variable in the nginx location block for the route's path.
 // The NJS httpmatches module will look up this variable on the request object and compare the request against the
@@ -211,16 +630,28 @@ type httpMatch struct {
 	Headers []string `json:"headers,omitempty"`
 	// QueryParams is a list of HTTPQueryParams name value pairs with the format "{name}={value}".
 	QueryParams []string `json:"params,omitempty"`
+	// Countries is a list of the ISO codes of the countries of the clients, looked up in the GeoIP2 database.
+	Countries []string `json:"countries,omitempty"`
+	// Continents is a list of the codes of the continents of the clients, looked up in the GeoIP2 database.
+	Continents []string `json:"continents,omitempty"`
 	// Any represents a match with no match conditions.
 	Any bool `json:"any,omitempty"`
 }
 
-func createHTTPMatch(match v1beta1.HTTPRouteMatch, redirectPath string) httpMatch {
+// createHTTPMatch creates the httpMatch of the HTTPRouteMatch. geoMatch can be nil.
+func createHTTPMatch(match v1beta1.HTTPRouteMatch, redirectPath string, geoMatch *graph.GeoMatch) httpMatch {
 	hm := httpMatch{
 		RedirectPath: redirectPath,
 	}
 
-	if isPathOnlyMatch(match) {
+	if geoMatch != nil {
+		switch geoMatch.Type {
+		case graph.GeoMatchTypeCountry:
+			hm.Countries = geoMatch.Codes
+		case graph.GeoMatchTypeContinent:
+			hm.Continents = geoMatch.Codes
+		}
+	} else if isPathOnlyMatch(match) {
 		hm.Any = true
 		return hm
 	}
@@ -290,6 +721,22 @@ func createProxyPassForVar(variable string) string {
 	return "http://$" + convertStringToSafeVariableName(variable)
 }
 
+func createHTTPSProxyPass(address string) string {
+	return "https://" + address
+}
+
+func createHTTPSProxyPassForVar(variable string) string {
+	return "https://$" + convertStringToSafeVariableName(variable)
+}
+
+func createGRPCPass(address string) string {
+	return "grpc://" + address
+}
+
+func createGRPCPassForVar(variable string) string {
+	return "grpc://$" + convertStringToSafeVariableName(variable)
+}
+
 func createMatchLocation(path string) http.Location {
 	return http.Location{
 		Path:     path,
@@ -297,13 +744,72 @@ func createMatchLocation(path string) http.Location {
 	}
 }
 
+// appendPrefixLocations appends the locations of a path rule to locs. The locations of the path itself,
+// unlike the internal locations of its matches, are appended once for each of the paths of createPrefixPaths.
+func appendPrefixLocations(locs []http.Location, ruleLocs []http.Location, path string) []http.Location {
+	for _, loc := range ruleLocs {
+		if loc.Path != path {
+			locs = append(locs, loc)
+			continue
+		}
+
+		for _, p := range createPrefixPaths(path) {
+			loc.Path = p
+			locs = append(locs, loc)
+		}
+	}
+
+	return locs
+}
+
+// createPrefixPaths creates the location paths of a PathPrefix path. A PathPrefix match is on path elements,
+// so /foo matches /foo, /foo/ and /foo/bar, but not /foobar. Because an NGINX prefix location matches on
+// characters, such a path needs an exact location for the path itself and a prefix location for the path followed
+// by a slash. The path is expected to have no trailing slash, unless it is the root path.
+func createPrefixPaths(path string) []string {
+	if path == rootPath {
+		return []string{rootPath}
+	}
+
+	return []string{"= " + path, path + "/"}
+}
+
 func createPathForMatch(path string, routeIdx int) string {
 	return fmt.Sprintf("%s_route%d", path, routeIdx)
 }
 
-func createDefaultRootLocation() http.Location {
-	return http.Location{
-		Path:   "/",
-		Return: &http.Return{Code: http.StatusNotFound},
+// createDefaultRootLocation creates the location for the requests that don't match any routing rule.
+// NGINX proxies such requests to the default backend, if it is configured. Otherwise, NGINX responds with 404.
+func createDefaultRootLocation(defaultBackend *dataplane.DefaultBackend, comments bool) http.Location {
+	if defaultBackend == nil {
+		return http.Location{
+			Path:   "/",
+			Return: &http.Return{Code: http.StatusNotFound},
+		}
+	}
+
+	upstreamName := defaultBackend.UpstreamName
+	if upstreamName == "" {
+		upstreamName = invalidBackendRef
+	}
+
+	loc := http.Location{
+		Path: "/",
 	}
+
+	switch {
+	case defaultBackend.Protocol.UsesGRPCModule():
+		loc.GRPCPass = createGRPCPass(upstreamName)
+	case defaultBackend.Protocol == dataplane.BackendProtocolHTTPS:
+		loc.ProxyPass = createHTTPSProxyPass(upstreamName)
+		loc.ProxySSLName = defaultBackend.TLSServerName
+	default:
+		loc.ProxyPass = createProxyPass(upstreamName)
+	}
+
+	if comments {
+		loc.Comment = createDefaultBackendComment(upstreamName)
+	}
+
+	return loc
 }
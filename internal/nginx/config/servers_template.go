@@ -1,42 +1,221 @@
 package config
 
 var serversTemplateText = `
+{{- define "listenParams" }}
+	{{- if .Backlog }} backlog={{ .Backlog }}{{ end }}
+	{{- if .ReusePort }} reuseport{{ end }}
+{{- end }}
+{{- define "clientHeaderBuffers" }}
+	{{- if .Size }}
+	client_header_buffer_size {{ .Size }};
+	{{- end }}
+	{{- if .Large }}
+	large_client_header_buffers {{ .Large }};
+	{{- end }}
+{{- end }}
 {{ range $s := . }}
 	{{ if $s.IsDefaultSSL }}
 server {
-	listen 443 ssl default_server;
+		{{ range $a := $s.Addresses }}
+	listen {{ $a }}:443 ssl{{ if $s.HTTP2 }} http2{{ end }} default_server{{ template "listenParams" $s.Listen }};
+			{{ if $s.HTTP3 }}
+	listen {{ $a }}:443 quic reuseport default_server;
+			{{ end }}
+		{{ else }}
+	listen 443 ssl{{ if $s.HTTP2 }} http2{{ end }} default_server{{ template "listenParams" $s.Listen }};
+			{{ if $s.HTTP3 }}
+	listen 443 quic reuseport default_server;
+			{{ end }}
+		{{ end }}
+		{{ if $s.ClientHeaderBuffers }}
+{{ template "clientHeaderBuffers" $s.ClientHeaderBuffers }}
+		{{ end }}
+
+		{{ if $s.SSL }}
+	ssl_certificate {{ $s.SSL.Certificate }};
+	ssl_certificate_key {{ $s.SSL.CertificateKey }};
+			{{ if $s.SSL.SecondaryCertificate }}
+	ssl_certificate {{ $s.SSL.SecondaryCertificate }};
+	ssl_certificate_key {{ $s.SSL.SecondaryCertificateKey }};
+			{{ end }}
 
+	default_type text/html;
+			{{ range $h := $s.SecurityHeaders }}
+	add_header {{ $h.Name }} "{{ $h.Value }}" always;
+			{{ end }}
+	return 404;
+		{{ else }}
 	ssl_reject_handshake on;
+		{{ end }}
 }
 	{{ else if $s.IsDefaultHTTP }}
 server {
-	listen 80 default_server;
+		{{ range $a := $s.Addresses }}
+	listen {{ $a }}:80 default_server{{ template "listenParams" $s.Listen }};
+		{{ else }}
+	listen 80 default_server{{ template "listenParams" $s.Listen }};
+		{{ end }}
+		{{ if $s.ClientHeaderBuffers }}
+{{ template "clientHeaderBuffers" $s.ClientHeaderBuffers }}
+		{{ end }}
 
 	default_type text/html;
+		{{ range $h := $s.SecurityHeaders }}
+	add_header {{ $h.Name }} "{{ $h.Value }}" always;
+		{{ end }}
 	return 404;
 }
 	{{ else }}
+		{{ if $s.Comment }}
+# {{ $s.Comment }}
+		{{ end }}
 server {
 		{{ if $s.SSL }}
-	listen 443 ssl;
+			{{ range $a := $s.Addresses }}
+	listen {{ $a }}:443 ssl{{ if $s.HTTP2 }} http2{{ end }};
+				{{ if $s.HTTP3 }}
+	listen {{ $a }}:443 quic;
+				{{ end }}
+			{{ else }}
+	listen 443 ssl{{ if $s.HTTP2 }} http2{{ end }};
+				{{ if $s.HTTP3 }}
+	listen 443 quic;
+				{{ end }}
+			{{ end }}
+			{{ if $s.HTTP3 }}
+	http3 on;
+	add_header Alt-Svc 'h3=":443"; ma=86400' always;
+			{{ end }}
 	ssl_certificate {{ $s.SSL.Certificate }};
 	ssl_certificate_key {{ $s.SSL.CertificateKey }};
+			{{ if $s.SSL.SecondaryCertificate }}
+	ssl_certificate {{ $s.SSL.SecondaryCertificate }};
+	ssl_certificate_key {{ $s.SSL.SecondaryCertificateKey }};
+			{{ end }}
+			{{ if $s.SSL.Protocols }}
+	ssl_protocols {{ $s.SSL.Protocols }};
+			{{ end }}
+			{{ if $s.SSL.Ciphers }}
+	ssl_ciphers {{ $s.SSL.Ciphers }};
+			{{ end }}
+			{{ if and $s.SSL.ClientCertificate $s.SSL.VerifyClient }}
+	ssl_client_certificate {{ $s.SSL.ClientCertificate }};
+	ssl_verify_client {{ $s.SSL.VerifyClient }};
+	error_page 495 496 = @ssl_verify_client_error;
+
+	location @ssl_verify_client_error {
+		return {{ $s.SSL.VerifyClientErrorStatus }};
+	}
+			{{ end }}
 
 	if ($ssl_server_name != $host) {
 		return 421;
 	}
+		{{ else }}
+			{{ range $a := $s.Addresses }}
+	listen {{ $a }}:80;
+			{{ end }}
 		{{ end }}
 
 	server_name {{ $s.ServerName }};
+		{{ if $s.ClientHeaderBuffers }}
+{{ template "clientHeaderBuffers" $s.ClientHeaderBuffers }}
+		{{ end }}
+		{{ if $s.RateLimit }}
+
+	limit_req zone={{ $s.RateLimit.Zone }}{{ if $s.RateLimit.Burst }} burst={{ $s.RateLimit.Burst }} nodelay{{ end }};
+	limit_req_status 429;
+		{{ end }}
+		{{ range $h := $s.SecurityHeaders }}
+	add_header {{ $h.Name }} "{{ $h.Value }}" always;
+		{{ end }}
+
+		{{ if $s.Maintenance }}
+	default_type text/html;
+	return 503{{ if $s.Maintenance.Page }} "{{ $s.Maintenance.Page }}"{{ end }};
+		{{ end }}
 
 		{{ range $l := $s.Locations }}
+			{{ if $l.Comment }}
+	# {{ $l.Comment }}
+			{{ end }}
 	location {{ $l.Path }} {
 		{{ if $l.Internal }}
 		internal;
 		{{ end }}
 
+		{{ if $l.AccessLog }}
+		access_log {{ $l.AccessLog.Path }}{{ if $l.AccessLog.Format }} {{ $l.AccessLog.Format }}{{ end }};
+		{{ end }}
+
+		{{ if $l.CORS }}
+		if ($request_method = OPTIONS) {
+			add_header Access-Control-Allow-Origin {{ $l.CORS.AllowOrigin }} always;
+			{{ if $l.CORS.AllowCredentials }}
+			add_header Access-Control-Allow-Credentials true always;
+			{{ end }}
+			{{ if $l.CORS.AllowMethods }}
+			add_header Access-Control-Allow-Methods "{{ $l.CORS.AllowMethods }}" always;
+			{{ end }}
+			{{ if $l.CORS.AllowHeaders }}
+			add_header Access-Control-Allow-Headers "{{ $l.CORS.AllowHeaders }}" always;
+			{{ end }}
+			{{ if $l.CORS.MaxAge }}
+			add_header Access-Control-Max-Age {{ $l.CORS.MaxAge }} always;
+			{{ end }}
+			add_header Vary Origin always;
+			{{ range $h := $s.SecurityHeaders }}
+			add_header {{ $h.Name }} "{{ $h.Value }}" always;
+			{{ end }}
+			{{ if $s.HTTP3 }}
+			add_header Alt-Svc 'h3=":443"; ma=86400' always;
+			{{ end }}
+			return 204;
+		}
+
+		add_header Access-Control-Allow-Origin {{ $l.CORS.AllowOrigin }} always;
+			{{ if $l.CORS.AllowCredentials }}
+		add_header Access-Control-Allow-Credentials true always;
+			{{ end }}
+			{{ if $l.CORS.ExposeHeaders }}
+		add_header Access-Control-Expose-Headers "{{ $l.CORS.ExposeHeaders }}" always;
+			{{ end }}
+		add_header Vary Origin always;
+		{{ end }}
+
+		{{ if $l.RequestID }}
+		add_header X-Request-ID $request_id_header always;
+		{{ end }}
+
+		{{ if or $l.CORS $l.RequestID }}
+			{{ range $h := $s.SecurityHeaders }}
+		add_header {{ $h.Name }} "{{ $h.Value }}" always;
+			{{ end }}
+			{{ if $s.HTTP3 }}
+		add_header Alt-Svc 'h3=":443"; ma=86400' always;
+			{{ end }}
+		{{ end }}
+
+		{{ if $l.DefaultType }}
+		default_type {{ $l.DefaultType | printf "%q" }};
+		{{ end }}
+
 		{{ if $l.Return }}
+			{{ if $l.Return.SkipForwardedHTTPS }}
+		if ($forwarded_https) {
+			break;
+		}
+
+			{{ end }}
+			{{ if $l.Return.RequestURIRegex }}
+		if ($request_uri ~ {{ $l.Return.RequestURIRegex | printf "%q" }}) {
+			return {{ $l.Return.Code }} {{ $l.Return.URL }};
+		}
+
+		return {{ $l.Return.Code }} {{ $l.Return.FallbackURL }};
+			{{ else }}
 		return {{ $l.Return.Code }} {{ $l.Return.URL }};
+			{{ end }}
 		{{ end }}
 
 		{{ if $l.HTTPMatchVar }}
@@ -45,9 +224,60 @@ server {
 		{{ end }}
 
 		{{ if $l.ProxyPass }}
+			{{ if $l.Streaming }}
+		proxy_buffering off;
+		proxy_request_buffering off;
+		proxy_http_version 1.1;
+		chunked_transfer_encoding on;
+		gzip off;
+			{{ end }}
+			{{ if $l.UpstreamHost }}
+		proxy_set_header Host $proxy_host;
+			{{ else }}
 		proxy_set_header Host $host;
+			{{ end }}
+			{{ if $s.ForwardedHeaders }}
+		proxy_set_header X-Forwarded-For $forwarded_for_header;
+		proxy_set_header X-Forwarded-Proto $scheme;
+		proxy_set_header X-Forwarded-Host $host;
+		proxy_set_header X-Forwarded-Port $server_port;
+			{{ end }}
+			{{ if $l.RequestID }}
+		proxy_set_header X-Request-ID $request_id_header;
+			{{ end }}
+			{{ range $h := $l.ProxySetHeaders }}
+		proxy_set_header {{ $h.Name }} "{{ $h.Value }}";
+			{{ end }}
+			{{ if $l.ProxyCache }}
+		proxy_cache {{ $l.ProxyCache.Zone }};
+				{{ if $l.ProxyCache.Key }}
+		proxy_cache_key {{ $l.ProxyCache.Key }};
+				{{ end }}
+		proxy_cache_valid {{ $l.ProxyCache.Valid }};
+			{{ end }}
+			{{ if $l.ProxySSLName }}
+		proxy_ssl_server_name on;
+		proxy_ssl_name {{ $l.ProxySSLName }};
+			{{ end }}
 		proxy_pass {{ $l.ProxyPass }}$request_uri;
 		{{ end }}
+
+		{{ if $l.GRPCPass }}
+		grpc_set_header Host $host;
+			{{ if $s.ForwardedHeaders }}
+		grpc_set_header X-Forwarded-For $forwarded_for_header;
+		grpc_set_header X-Forwarded-Proto $scheme;
+		grpc_set_header X-Forwarded-Host $host;
+		grpc_set_header X-Forwarded-Port $server_port;
+			{{ end }}
+			{{ if $l.RequestID }}
+		grpc_set_header X-Request-ID $request_id_header;
+			{{ end }}
+			{{ range $h := $l.ProxySetHeaders }}
+		grpc_set_header {{ $h.Name }} "{{ $h.Value }}";
+			{{ end }}
+		grpc_pass {{ $l.GRPCPass }};
+		{{ end }}
 	}
 		{{ end }}
 }
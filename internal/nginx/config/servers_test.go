@@ -3,15 +3,19 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/gateway-api/apis/v1beta1"
 
+	"github.com/nginxinc/nginx-kubernetes-gateway/apis/v1alpha1"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
@@ -30,6 +34,9 @@ func TestExecuteServers(t *testing.T) {
 			{
 				Hostname: "cafe.example.com",
 			},
+			{
+				Hostname: "*.example.com",
+			},
 		},
 		SSLServers: []dataplane.VirtualServer{
 			{
@@ -56,11 +63,201 @@ func TestExecuteServers(t *testing.T) {
 		"listen 443 ssl default_server;": 1,
 		"server_name example.com;":       2,
 		"server_name cafe.example.com;":  2,
+		"server_name *.example.com;":     1,
 		"ssl_certificate cert-path;":     2,
 		"ssl_certificate_key cert-path;": 2,
 	}
 
-	servers := string(executeServers(conf))
+	servers := string(executeServers(conf, false, false, false, false, false, "", nil, http.ListenOptions{}))
+	for expSubStr, expCount := range expSubStrings {
+		if expCount != strings.Count(servers, expSubStr) {
+			t.Errorf(
+				"executeServers() did not generate servers with substring %q %d times. Servers: %v",
+				expSubStr,
+				expCount,
+				servers,
+			)
+		}
+	}
+}
+
+func TestExecuteServersHTTP3(t *testing.T) {
+	conf := dataplane.Configuration{
+		HTTPServers: []dataplane.VirtualServer{
+			{
+				IsDefault: true,
+			},
+			{
+				Hostname: "example.com",
+			},
+		},
+		SSLServers: []dataplane.VirtualServer{
+			{
+				IsDefault: true,
+			},
+			{
+				Hostname: "example.com",
+				SSL: &dataplane.SSL{
+					CertificatePath: "cert-path",
+				},
+			},
+			{
+				Hostname: "cafe.example.com",
+				SSL: &dataplane.SSL{
+					CertificatePath: "cert-path",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		expSubStrings map[string]int
+		addresses     []string
+		msg           string
+		http3         bool
+	}{
+		{
+			expSubStrings: map[string]int{
+				"listen 443 ssl;":                                  2,
+				"listen 443 ssl default_server;":                   1,
+				"listen 443 quic;":                                 2,
+				"listen 443 quic reuseport default_server;":        1,
+				"http3 on;":                                        2,
+				`add_header Alt-Svc 'h3=":443"; ma=86400' always;`: 2,
+				// the HTTP servers don't accept QUIC
+				"listen 80 default_server;": 1,
+			},
+			http3: true,
+			msg:   "enabled",
+		},
+		{
+			expSubStrings: map[string]int{
+				"listen 10.0.0.1:443 ssl;":                           2,
+				"listen 10.0.0.1:443 quic;":                          2,
+				"listen 10.0.0.1:443 quic reuseport default_server;": 1,
+				"listen [::1]:443 quic;":                             2,
+				"listen [::1]:443 quic reuseport default_server;":    1,
+				"http3 on;": 2,
+			},
+			addresses: []string{"10.0.0.1", "::1"},
+			http3:     true,
+			msg:       "enabled with addresses",
+		},
+		{
+			expSubStrings: map[string]int{
+				"listen 443 ssl;": 2,
+				"quic":            0,
+				"http3":           0,
+				"Alt-Svc":         0,
+			},
+			http3: false,
+			msg:   "disabled",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			c := conf
+			c.Addresses = test.addresses
+
+			servers := string(executeServers(c, false, test.http3, false, false, false, "", nil, http.ListenOptions{}))
+
+			for expSubStr, expCount := range test.expSubStrings {
+				g.Expect(strings.Count(servers, expSubStr)).To(Equal(expCount), expSubStr)
+			}
+		})
+	}
+}
+
+func TestExecuteServersDefaultBackend(t *testing.T) {
+	conf := dataplane.Configuration{
+		HTTPServers: []dataplane.VirtualServer{
+			{
+				IsDefault: true,
+			},
+			{
+				Hostname:       "example.com",
+				DefaultBackend: &dataplane.DefaultBackend{UpstreamName: "test_default_8080"},
+			},
+			{
+				Hostname: "cafe.example.com",
+			},
+		},
+		SSLServers: []dataplane.VirtualServer{
+			{
+				IsDefault: true,
+			},
+			{
+				Hostname: "example.com",
+				SSL: &dataplane.SSL{
+					CertificatePath: "cert-path",
+				},
+				DefaultBackend: &dataplane.DefaultBackend{},
+			},
+		},
+	}
+
+	expSubStrings := map[string]int{
+		"proxy_pass http://test_default_8080$request_uri;":   1,
+		"proxy_pass http://invalid-backend-ref$request_uri;": 1,
+		"proxy_set_header Host $host;":                       2,
+		// the default HTTP server and the root location of cafe.example.com
+		"return 404": 2,
+	}
+
+	servers := string(executeServers(conf, false, false, false, false, false, "", nil, http.ListenOptions{}))
+	for expSubStr, expCount := range expSubStrings {
+		if expCount != strings.Count(servers, expSubStr) {
+			t.Errorf(
+				"executeServers() did not generate servers with substring %q %d times. Servers: %v",
+				expSubStr,
+				expCount,
+				servers,
+			)
+		}
+	}
+}
+
+func TestExecuteServersWithAddresses(t *testing.T) {
+	conf := dataplane.Configuration{
+		HTTPServers: []dataplane.VirtualServer{
+			{
+				IsDefault: true,
+			},
+			{
+				Hostname: "example.com",
+			},
+		},
+		SSLServers: []dataplane.VirtualServer{
+			{
+				IsDefault: true,
+			},
+			{
+				Hostname: "example.com",
+				SSL: &dataplane.SSL{
+					CertificatePath: "cert-path",
+				},
+			},
+		},
+		Addresses: []string{"10.0.0.1", "2001:db8::1"},
+	}
+
+	expSubStrings := map[string]int{
+		"listen 10.0.0.1:80 default_server;":           1,
+		"listen [2001:db8::1]:80 default_server;":      1,
+		"listen 10.0.0.1:80;":                          1,
+		"listen [2001:db8::1]:80;":                     1,
+		"listen 10.0.0.1:443 ssl default_server;":      1,
+		"listen [2001:db8::1]:443 ssl default_server;": 1,
+		"listen 10.0.0.1:443 ssl;":                     1,
+		"listen [2001:db8::1]:443 ssl;":                1,
+		"listen 80":                                    0,
+		"listen 443":                                   0,
+	}
+
+	servers := string(executeServers(conf, false, false, false, false, false, "", nil, http.ListenOptions{}))
 	for expSubStr, expCount := range expSubStrings {
 		if expCount != strings.Count(servers, expSubStr) {
 			t.Errorf(
@@ -73,6 +270,2563 @@ func TestExecuteServers(t *testing.T) {
 	}
 }
 
+func TestExecuteServersListenOptions(t *testing.T) {
+	conf := dataplane.Configuration{
+		HTTPServers: []dataplane.VirtualServer{
+			{
+				IsDefault: true,
+			},
+			{
+				Hostname: "example.com",
+			},
+		},
+		SSLServers: []dataplane.VirtualServer{
+			{
+				IsDefault: true,
+			},
+			{
+				Hostname: "example.com",
+				SSL: &dataplane.SSL{
+					CertificatePath: "cert-path",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		expSubStrings map[string]int
+		msg           string
+		listen        http.ListenOptions
+		addresses     []string
+	}{
+		{
+			listen: http.ListenOptions{},
+			expSubStrings: map[string]int{
+				"listen 80 default_server;":      1,
+				"listen 443 ssl default_server;": 1,
+				"backlog=":                       0,
+				"reuseport":                      0,
+			},
+			msg: "default",
+		},
+		{
+			listen: http.ListenOptions{Backlog: 4096, ReusePort: true},
+			expSubStrings: map[string]int{
+				"listen 80 default_server backlog=4096 reuseport;":      1,
+				"listen 443 ssl default_server backlog=4096 reuseport;": 1,
+				"backlog=":  2,
+				"reuseport": 2,
+			},
+			msg: "backlog and reuseport",
+		},
+		{
+			listen: http.ListenOptions{Backlog: 511},
+			expSubStrings: map[string]int{
+				"listen 80 default_server backlog=511;":      1,
+				"listen 443 ssl default_server backlog=511;": 1,
+				"reuseport": 0,
+			},
+			msg: "backlog",
+		},
+		{
+			listen:    http.ListenOptions{ReusePort: true},
+			addresses: []string{"10.0.0.1", "2001:db8::1"},
+			expSubStrings: map[string]int{
+				"listen 10.0.0.1:80 default_server reuseport;":           1,
+				"listen [2001:db8::1]:80 default_server reuseport;":      1,
+				"listen 10.0.0.1:443 ssl default_server reuseport;":      1,
+				"listen [2001:db8::1]:443 ssl default_server reuseport;": 1,
+				"reuseport": 4,
+			},
+			msg: "reuseport with addresses",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			c := conf
+			c.Addresses = test.addresses
+
+			servers := string(executeServers(c, false, false, false, false, false, "", nil, test.listen))
+			for expSubStr, expCount := range test.expSubStrings {
+				if expCount != strings.Count(servers, expSubStr) {
+					t.Errorf(
+						"executeServers() did not generate servers with substring %q %d times. Servers: %v",
+						expSubStr,
+						expCount,
+						servers,
+					)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateListenAddresses(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(createListenAddresses(nil)).To(BeNil())
+	g.Expect(createListenAddresses([]string{"10.0.0.1", "::1", "2001:db8::1"})).
+		To(Equal([]string{"10.0.0.1", "[::1]", "[2001:db8::1]"}))
+}
+
+func TestExecuteServersSecurityHeaders(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	conf := dataplane.Configuration{
+		HTTPServers: []dataplane.VirtualServer{
+			{
+				IsDefault: true,
+			},
+			{
+				Hostname: "example.com",
+			},
+		},
+		SSLServers: []dataplane.VirtualServer{
+			{
+				IsDefault: true,
+			},
+			{
+				Hostname: "example.com",
+				SSL: &dataplane.SSL{
+					CertificatePath: "cert-path",
+				},
+			},
+		},
+	}
+
+	securityHeaders := []http.Header{
+		{Name: "Strict-Transport-Security", Value: "max-age=31536000; includeSubDomains"},
+		{Name: "X-Content-Type-Options", Value: "nosniff"},
+	}
+
+	servers := string(executeServers(conf, false, false, false, false, false, "", securityHeaders, http.ListenOptions{}))
+
+	// the default HTTP server and the servers for example.com; the default HTTPS server rejects the handshakes
+	expSubStrings := map[string]int{
+		`add_header Strict-Transport-Security "max-age=31536000; includeSubDomains" always;`: 3,
+		`add_header X-Content-Type-Options "nosniff" always;`:                                3,
+	}
+
+	for expSubStr, expCount := range expSubStrings {
+		g.Expect(strings.Count(servers, expSubStr)).To(Equal(expCount), expSubStr)
+	}
+
+	servers = string(executeServers(conf, false, false, false, false, false, "", nil, http.ListenOptions{}))
+	g.Expect(servers).ToNot(ContainSubstring("add_header"))
+}
+
+func TestExecuteServersMaintenance(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	conf := dataplane.Configuration{
+		HTTPServers: []dataplane.VirtualServer{
+			{
+				IsDefault: true,
+			},
+			{
+				Hostname: "example.com",
+			},
+		},
+		SSLServers: []dataplane.VirtualServer{
+			{
+				IsDefault: true,
+			},
+			{
+				Hostname: "example.com",
+				SSL: &dataplane.SSL{
+					CertificatePath: "cert-path",
+				},
+			},
+		},
+		Maintenance: &dataplane.Maintenance{Page: `<h1 class="title">Under maintenance</h1>`},
+	}
+
+	servers := string(executeServers(conf, false, false, false, false, false, "", nil, http.ListenOptions{}))
+
+	// the servers for example.com; the default servers are unchanged
+	expSubStrings := map[string]int{
+		`return 503 "<h1 class=\"title\">Under maintenance</h1>";`: 2,
+		"default_type text/html;":                                  3,
+		"return 404;":                                              1,
+		"ssl_certificate cert-path;":                               1,
+		"server_name example.com;":                                 2,
+		"location ":                                                0,
+	}
+
+	for expSubStr, expCount := range expSubStrings {
+		g.Expect(strings.Count(servers, expSubStr)).To(Equal(expCount), expSubStr)
+	}
+
+	conf.Maintenance = &dataplane.Maintenance{}
+
+	servers = string(executeServers(conf, false, false, false, false, false, "", nil, http.ListenOptions{}))
+	g.Expect(strings.Count(servers, "return 503;")).To(Equal(2))
+
+	// disabling the maintenance mode restores the locations
+	conf.Maintenance = nil
+
+	servers = string(executeServers(conf, false, false, false, false, false, "", nil, http.ListenOptions{}))
+	g.Expect(servers).ToNot(ContainSubstring("return 503"))
+	g.Expect(strings.Count(servers, "location / {")).To(Equal(2))
+}
+
+func TestExecuteServersClientHeaderBuffers(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	conf := dataplane.Configuration{
+		HTTPServers: []dataplane.VirtualServer{
+			{
+				IsDefault: true,
+			},
+			{
+				Hostname: "example.com",
+			},
+		},
+		SSLServers: []dataplane.VirtualServer{
+			{
+				IsDefault: true,
+			},
+			{
+				Hostname: "example.com",
+				SSL: &dataplane.SSL{
+					CertificatePath: "cert-path",
+				},
+			},
+		},
+		ClientHeaderBuffers: &dataplane.ClientHeaderBuffers{
+			Size:        "4k",
+			LargeNumber: 8,
+			LargeSize:   "32k",
+		},
+	}
+
+	servers := string(executeServers(conf, false, false, false, false, false, "", nil, http.ListenOptions{}))
+
+	// all servers, including the default ones
+	g.Expect(strings.Count(servers, "client_header_buffer_size 4k;")).To(Equal(4))
+	g.Expect(strings.Count(servers, "large_client_header_buffers 8 32k;")).To(Equal(4))
+
+	conf.ClientHeaderBuffers = &dataplane.ClientHeaderBuffers{Size: "2k"}
+
+	servers = string(executeServers(conf, false, false, false, false, false, "", nil, http.ListenOptions{}))
+	g.Expect(strings.Count(servers, "client_header_buffer_size 2k;")).To(Equal(4))
+	g.Expect(servers).ToNot(ContainSubstring("large_client_header_buffers"))
+
+	conf.ClientHeaderBuffers = nil
+
+	servers = string(executeServers(conf, false, false, false, false, false, "", nil, http.ListenOptions{}))
+	g.Expect(servers).ToNot(ContainSubstring("client_header_buffer"))
+}
+
+func TestExecuteServersRateLimit(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	conf := dataplane.Configuration{
+		HTTPServers: []dataplane.VirtualServer{
+			{
+				IsDefault: true,
+			},
+			{
+				Hostname:  "example.com",
+				Listener:  "http",
+				RateLimit: &dataplane.RateLimit{Rate: "10r/s", Burst: 20},
+			},
+			{
+				Hostname: "no-limit.example.com",
+				Listener: "no-limit",
+			},
+		},
+		SSLServers: []dataplane.VirtualServer{
+			{
+				IsDefault: true,
+			},
+			{
+				Hostname:  "example.com",
+				Listener:  "https",
+				RateLimit: &dataplane.RateLimit{Rate: "600r/m"},
+				SSL: &dataplane.SSL{
+					CertificatePath: "cert-path",
+				},
+			},
+		},
+	}
+
+	servers := string(executeServers(conf, false, false, false, false, false, "", nil, http.ListenOptions{}))
+
+	// the limits apply at the server level to all locations of the servers of the listeners with a limit
+	expSubStrings := map[string]int{
+		"limit_req zone=listener_http burst=20 nodelay;": 1,
+		"limit_req zone=listener_https;":                 1,
+		"limit_req_status 429;":                          2,
+		"limit_req ":                                     2,
+	}
+
+	for expSubStr, expCount := range expSubStrings {
+		g.Expect(strings.Count(servers, expSubStr)).To(Equal(expCount), expSubStr)
+	}
+}
+
+func TestExecuteServersSecurityHeadersCORS(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	servers := []http.Server{
+		{
+			ServerName: "example.com",
+			Locations: []http.Location{
+				{
+					Path:      "/api",
+					ProxyPass: "http://test_foo_80",
+					CORS: &http.CORS{
+						AllowOrigin: "*",
+					},
+				},
+				{
+					Path:      "/",
+					ProxyPass: "http://test_foo_80",
+				},
+			},
+			SecurityHeaders: []http.Header{
+				{Name: "X-Content-Type-Options", Value: "nosniff"},
+			},
+		},
+	}
+
+	cfg := string(execute(serversTemplate, servers))
+
+	// the server, the location with CORS headers, which doesn't inherit the headers of the server,
+	// and its preflight response, which doesn't inherit the headers of the location
+	g.Expect(strings.Count(cfg, `add_header X-Content-Type-Options "nosniff" always;`)).To(Equal(3))
+}
+
+func TestExecuteServersHTTP3CORS(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	servers := []http.Server{
+		{
+			ServerName: "example.com",
+			SSL: &http.SSL{
+				Certificate:    "cert-path",
+				CertificateKey: "cert-path",
+			},
+			HTTP3: true,
+			Locations: []http.Location{
+				{
+					Path:      "/api",
+					ProxyPass: "http://test_foo_80",
+					CORS: &http.CORS{
+						AllowOrigin: "*",
+					},
+				},
+				{
+					Path:      "/tea",
+					ProxyPass: "http://test_foo_80",
+					RequestID: true,
+				},
+				{
+					Path:      "/",
+					ProxyPass: "http://test_foo_80",
+				},
+			},
+		},
+	}
+
+	cfg := string(execute(serversTemplate, servers))
+
+	// the server, the locations with CORS and RequestID headers, which don't inherit the headers of the server,
+	// and the preflight response, which doesn't inherit the headers of the location
+	g.Expect(strings.Count(cfg, `add_header Alt-Svc 'h3=":443"; ma=86400' always;`)).To(Equal(4))
+
+	servers[0].HTTP3 = false
+
+	cfg = string(execute(serversTemplate, servers))
+
+	g.Expect(cfg).ToNot(ContainSubstring("Alt-Svc"))
+}
+
+func TestExecuteServersRequestID(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	servers := []http.Server{
+		{
+			ServerName: "example.com",
+			Locations: []http.Location{
+				{
+					Path:      "/api",
+					ProxyPass: "http://test_foo_80",
+					RequestID: true,
+				},
+				{
+					Path:      "/grpc",
+					GRPCPass:  "grpc://test_bar_80",
+					RequestID: true,
+				},
+				{
+					Path:      "/",
+					ProxyPass: "http://test_foo_80",
+				},
+			},
+			SecurityHeaders: []http.Header{
+				{Name: "X-Content-Type-Options", Value: "nosniff"},
+			},
+		},
+	}
+
+	cfg := string(execute(serversTemplate, servers))
+
+	// the locations with the X-Request-ID header don't inherit the headers of the server
+	expSubStrings := map[string]int{
+		"proxy_set_header X-Request-ID $request_id_header;":   1,
+		"grpc_set_header X-Request-ID $request_id_header;":    1,
+		"add_header X-Request-ID $request_id_header always;":  2,
+		`add_header X-Content-Type-Options "nosniff" always;`: 3,
+		"X-Request-ID": 4,
+	}
+
+	for expSubStr, expCount := range expSubStrings {
+		g.Expect(strings.Count(cfg, expSubStr)).To(Equal(expCount), expSubStr)
+	}
+}
+
+func TestExecuteServersProxySetHeaders(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	headers := []http.Header{
+		{Name: "X-Version", Value: "$test__hr_rule0_header_x_version"},
+		{Name: "X-Debug", Value: ""},
+	}
+
+	servers := []http.Server{
+		{
+			ServerName: "example.com",
+			Locations: []http.Location{
+				{
+					Path:            "/api",
+					ProxyPass:       "http://$test__hr_rule0",
+					ProxySetHeaders: headers,
+				},
+				{
+					Path:            "/grpc",
+					GRPCPass:        "grpc://$test__hr_rule0",
+					ProxySetHeaders: headers,
+				},
+				{
+					Path:      "/",
+					ProxyPass: "http://test_foo_80",
+				},
+			},
+		},
+	}
+
+	cfg := string(execute(serversTemplate, servers))
+
+	expSubStrings := map[string]int{
+		`proxy_set_header X-Version "$test__hr_rule0_header_x_version";`: 1,
+		`proxy_set_header X-Debug "";`:                                   1,
+		`grpc_set_header X-Version "$test__hr_rule0_header_x_version";`:  1,
+		`grpc_set_header X-Debug "";`:                                    1,
+	}
+
+	for expSubStr, expCount := range expSubStrings {
+		g.Expect(strings.Count(cfg, expSubStr)).To(Equal(expCount), expSubStr)
+	}
+}
+
+func TestExecuteServersForwardedHeaders(t *testing.T) {
+	tests := []struct {
+		msg              string
+		forwardedHeaders bool
+	}{
+		{
+			forwardedHeaders: true,
+			msg:              "enabled",
+		},
+		{
+			forwardedHeaders: false,
+			msg:              "disabled",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			servers := []http.Server{
+				{
+					ServerName: "example.com",
+					Locations: []http.Location{
+						{
+							Path:      "/api",
+							ProxyPass: "http://test_foo_80",
+						},
+						{
+							Path:     "/grpc",
+							GRPCPass: "grpc://test_bar_80",
+						},
+						{
+							Path:   "/redirect",
+							Return: &http.Return{Code: http.StatusFound, URL: "https://example.com"},
+						},
+					},
+					ForwardedHeaders: test.forwardedHeaders,
+				},
+			}
+
+			cfg := string(execute(serversTemplate, servers))
+
+			expCount := 0
+			if test.forwardedHeaders {
+				expCount = 1
+			}
+
+			expSubStrings := []string{
+				"proxy_set_header X-Forwarded-For $forwarded_for_header;",
+				"proxy_set_header X-Forwarded-Proto $scheme;",
+				"proxy_set_header X-Forwarded-Host $host;",
+				"proxy_set_header X-Forwarded-Port $server_port;",
+				"grpc_set_header X-Forwarded-For $forwarded_for_header;",
+				"grpc_set_header X-Forwarded-Proto $scheme;",
+				"grpc_set_header X-Forwarded-Host $host;",
+				"grpc_set_header X-Forwarded-Port $server_port;",
+			}
+
+			for _, expSubStr := range expSubStrings {
+				g.Expect(strings.Count(cfg, expSubStr)).To(Equal(expCount), expSubStr)
+			}
+
+			g.Expect(strings.Count(cfg, "X-Forwarded-")).To(Equal(8 * expCount))
+		})
+	}
+}
+
+func TestExecuteServersAccessLog(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	servers := []http.Server{
+		{
+			ServerName: "example.com",
+			Locations: []http.Location{
+				{
+					Path:      "/debug",
+					ProxyPass: "http://test_foo_80",
+					AccessLog: &http.AccessLog{Path: "/dev/stdout", Format: "verbose"},
+				},
+				{
+					Path:      "/health",
+					ProxyPass: "http://test_foo_80",
+					AccessLog: &http.AccessLog{Path: "off"},
+				},
+				{
+					Path:      "/",
+					ProxyPass: "http://test_foo_80",
+				},
+			},
+		},
+	}
+
+	cfg := string(execute(serversTemplate, servers))
+
+	expSubStrings := map[string]int{
+		"access_log /dev/stdout verbose;": 1,
+		"access_log off;":                 3, // including the internal 502 and 500 servers
+		"access_log":                      4,
+	}
+
+	for expSubStr, expCount := range expSubStrings {
+		g.Expect(strings.Count(cfg, expSubStr)).To(Equal(expCount), expSubStr)
+	}
+}
+
+func TestCreateSecurityHeaders(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(createSecurityHeaders(nil)).To(BeNil())
+	g.Expect(createSecurityHeaders(map[string]string{
+		"X-Frame-Options":        "DENY",
+		"X-Content-Type-Options": "nosniff",
+	})).To(Equal([]http.Header{
+		{Name: "X-Content-Type-Options", Value: "nosniff"},
+		{Name: "X-Frame-Options", Value: "DENY"},
+	}))
+}
+
+func TestExecuteServersDualCertificates(t *testing.T) {
+	servers := []http.Server{
+		{
+			ServerName: "example.com",
+			SSL: &http.SSL{
+				Certificate:             "rsa-cert-path",
+				CertificateKey:          "rsa-cert-path",
+				SecondaryCertificate:    "ecdsa-cert-path",
+				SecondaryCertificateKey: "ecdsa-cert-path",
+			},
+		},
+		{
+			ServerName: "cafe.example.com",
+			SSL: &http.SSL{
+				Certificate:    "cert-path",
+				CertificateKey: "cert-path",
+			},
+		},
+	}
+
+	expSubStrings := map[string]int{
+		"ssl_certificate rsa-cert-path;":       1,
+		"ssl_certificate_key rsa-cert-path;":   1,
+		"ssl_certificate ecdsa-cert-path;":     1,
+		"ssl_certificate_key ecdsa-cert-path;": 1,
+		"ssl_certificate cert-path;":           1,
+		"ssl_certificate ":                     3,
+	}
+
+	cfg := string(execute(serversTemplate, servers))
+	for expSubStr, expCount := range expSubStrings {
+		if expCount != strings.Count(cfg, expSubStr) {
+			t.Errorf(
+				"execute() did not generate servers with substring %q %d times. Servers: %v",
+				expSubStr,
+				expCount,
+				cfg,
+			)
+		}
+	}
+}
+
+func TestExecuteServersDefaultCertificate(t *testing.T) {
+	tests := []struct {
+		ssl           *dataplane.SSL
+		expSubStrings map[string]int
+		msg           string
+	}{
+		{
+			expSubStrings: map[string]int{
+				"ssl_reject_handshake on;": 1,
+				"ssl_certificate ":         0,
+				"return 404;":              0,
+			},
+			msg: "no default certificate",
+		},
+		{
+			ssl: &dataplane.SSL{
+				CertificatePath:          "rsa-cert-path",
+				SecondaryCertificatePath: "ecdsa-cert-path",
+			},
+			expSubStrings: map[string]int{
+				"ssl_reject_handshake on;":             0,
+				"ssl_certificate rsa-cert-path;":       1,
+				"ssl_certificate_key rsa-cert-path;":   1,
+				"ssl_certificate ecdsa-cert-path;":     1,
+				"ssl_certificate_key ecdsa-cert-path;": 1,
+				"return 404;":                          1,
+			},
+			msg: "default certificate",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			conf := dataplane.Configuration{
+				SSLServers: []dataplane.VirtualServer{
+					{
+						IsDefault: true,
+						SSL:       test.ssl,
+					},
+				},
+			}
+
+			cfg := string(executeServers(conf, false, false, false, false, false, "", nil, http.ListenOptions{}))
+
+			for expSubStr, expCount := range test.expSubStrings {
+				if expCount != strings.Count(cfg, expSubStr) {
+					t.Errorf(
+						"executeServers() did not generate servers with substring %q %d times. Servers: %v",
+						expSubStr,
+						expCount,
+						cfg,
+					)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteServersTLSOptions(t *testing.T) {
+	servers := []http.Server{
+		{
+			ServerName: "example.com",
+			SSL: &http.SSL{
+				Certificate:    "cert-path",
+				CertificateKey: "cert-path",
+				Protocols:      "TLSv1.2 TLSv1.3",
+				Ciphers:        "HIGH:!aNULL:!MD5",
+			},
+		},
+		{
+			ServerName: "cafe.example.com",
+			SSL: &http.SSL{
+				Certificate:    "cert-path",
+				CertificateKey: "cert-path",
+			},
+		},
+	}
+
+	expSubStrings := map[string]int{
+		"ssl_protocols TLSv1.2 TLSv1.3;": 1,
+		"ssl_ciphers HIGH:!aNULL:!MD5;":  1,
+		"ssl_protocols ":                 1,
+		"ssl_ciphers ":                   1,
+		"ssl_certificate_key cert-path;": 2,
+	}
+
+	cfg := string(execute(serversTemplate, servers))
+	for expSubStr, expCount := range expSubStrings {
+		if expCount != strings.Count(cfg, expSubStr) {
+			t.Errorf(
+				"execute() did not generate servers with substring %q %d times. Servers: %v",
+				expSubStr,
+				expCount,
+				cfg,
+			)
+		}
+	}
+}
+
+func TestExecuteServersClientCertificate(t *testing.T) {
+	servers := []http.Server{
+		{
+			ServerName: "require.example.com",
+			SSL: &http.SSL{
+				Certificate:             "cert-path",
+				CertificateKey:          "cert-path",
+				ClientCertificate:       "require-ca-path",
+				VerifyClient:            "on",
+				VerifyClientErrorStatus: 400,
+			},
+		},
+		{
+			ServerName: "optional.example.com",
+			SSL: &http.SSL{
+				Certificate:             "cert-path",
+				CertificateKey:          "cert-path",
+				ClientCertificate:       "optional-ca-path",
+				VerifyClient:            "optional",
+				VerifyClientErrorStatus: 403,
+			},
+		},
+		{
+			ServerName: "cafe.example.com",
+			SSL: &http.SSL{
+				Certificate:    "cert-path",
+				CertificateKey: "cert-path",
+			},
+		},
+	}
+
+	expSubStrings := map[string]int{
+		"ssl_client_certificate require-ca-path;":                1,
+		"ssl_verify_client on;":                                  1,
+		"ssl_client_certificate optional-ca-path;":               1,
+		"ssl_verify_client optional;":                            1,
+		"ssl_client_certificate ":                                2,
+		"error_page 495 496 = @ssl_verify_client_error;":         2,
+		"location @ssl_verify_client_error {\n\t\treturn 400;\n": 1,
+		"location @ssl_verify_client_error {\n\t\treturn 403;\n": 1,
+	}
+
+	cfg := string(execute(serversTemplate, servers))
+	for expSubStr, expCount := range expSubStrings {
+		if expCount != strings.Count(cfg, expSubStr) {
+			t.Errorf(
+				"execute() did not generate servers with substring %q %d times. Servers: %v",
+				expSubStr,
+				expCount,
+				cfg,
+			)
+		}
+	}
+}
+
+func TestExecuteServersStreaming(t *testing.T) {
+	servers := []http.Server{
+		{
+			ServerName: "example.com",
+			Locations: []http.Location{
+				{
+					Path:      "/stream",
+					ProxyPass: "http://test_foo_80",
+					Streaming: true,
+				},
+				{
+					Path:      "/",
+					ProxyPass: "http://test_foo_80",
+				},
+			},
+		},
+	}
+
+	expSubStrings := map[string]int{
+		"proxy_buffering off;":                       1,
+		"proxy_request_buffering off;":               1,
+		"proxy_http_version 1.1;":                    1,
+		"chunked_transfer_encoding on;":              1,
+		"gzip off;":                                  1,
+		"proxy_pass http://test_foo_80$request_uri;": 2,
+	}
+
+	cfg := string(execute(serversTemplate, servers))
+	for expSubStr, expCount := range expSubStrings {
+		if expCount != strings.Count(cfg, expSubStr) {
+			t.Errorf(
+				"execute() did not generate servers with substring %q %d times. Servers: %v",
+				expSubStr,
+				expCount,
+				cfg,
+			)
+		}
+	}
+}
+
+func TestExecuteServersUpstreamHost(t *testing.T) {
+	servers := []http.Server{
+		{
+			ServerName: "example.com",
+			Locations: []http.Location{
+				{
+					Path:         "/upstream-host",
+					ProxyPass:    "http://test_foo_80",
+					UpstreamHost: true,
+				},
+				{
+					Path:      "/",
+					ProxyPass: "http://test_foo_80",
+				},
+			},
+		},
+	}
+
+	expSubStrings := map[string]int{
+		"proxy_set_header Host $proxy_host;": 1,
+		"proxy_set_header Host $host;":       1,
+	}
+
+	cfg := string(execute(serversTemplate, servers))
+	for expSubStr, expCount := range expSubStrings {
+		if expCount != strings.Count(cfg, expSubStr) {
+			t.Errorf(
+				"execute() did not generate servers with substring %q %d times. Servers: %v",
+				expSubStr,
+				expCount,
+				cfg,
+			)
+		}
+	}
+}
+
+func TestCreateServersHTTP2(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "hr",
+		},
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	createPathRules := func(protocol dataplane.BackendProtocol) []dataplane.PathRule {
+		return []dataplane.PathRule{
+			{
+				Path: "/",
+				MatchRules: []dataplane.MatchRule{
+					{
+						Source: hr,
+						BackendGroup: graph.BackendGroup{
+							Source:   client.ObjectKeyFromObject(hr),
+							Backends: []graph.BackendRef{{Name: "test_foo_80", Valid: true, Weight: 1}},
+						},
+						BackendProtocol: protocol,
+					},
+				},
+			},
+		}
+	}
+
+	ssl := &dataplane.SSL{CertificatePath: "cert.pem"}
+
+	httpServers := []dataplane.VirtualServer{
+		{
+			IsDefault: true,
+		},
+		{
+			Hostname:  "grpc.example.com",
+			PathRules: createPathRules(dataplane.BackendProtocolGRPC),
+		},
+	}
+
+	sslServers := []dataplane.VirtualServer{
+		{
+			IsDefault: true,
+		},
+		{
+			Hostname:  "grpc.example.com",
+			PathRules: createPathRules(dataplane.BackendProtocolGRPC),
+			SSL:       ssl,
+		},
+		{
+			Hostname:  "cafe.example.com",
+			PathRules: createPathRules(dataplane.BackendProtocolHTTP1),
+			SSL:       ssl,
+		},
+	}
+
+	servers := createServers(httpServers, sslServers, nil, false, false, false, false, false, "", nil)
+	g.Expect(servers).To(HaveLen(5))
+
+	// NGINX enables HTTP/2 for the address and port, so all HTTPS servers must enable it
+	for _, s := range servers[:2] {
+		g.Expect(s.HTTP2).To(BeFalse())
+	}
+	for _, s := range servers[2:] {
+		g.Expect(s.HTTP2).To(BeTrue())
+	}
+
+	cfg := string(execute(serversTemplate, servers))
+	g.Expect(strings.Count(cfg, "listen 443 ssl http2 default_server;")).To(Equal(1))
+	g.Expect(strings.Count(cfg, "listen 443 ssl http2;")).To(Equal(2))
+	g.Expect(strings.Count(cfg, "listen 443 ssl;")).To(Equal(0))
+
+	// without gRPC backends, no HTTPS server enables HTTP/2
+	sslServers[1].PathRules = createPathRules(dataplane.BackendProtocolHTTP1)
+
+	servers = createServers(httpServers, sslServers, nil, false, false, false, false, false, "", nil)
+	for _, s := range servers {
+		g.Expect(s.HTTP2).To(BeFalse())
+	}
+}
+
+func TestExecuteServersGRPC(t *testing.T) {
+	servers := []http.Server{
+		{
+			ServerName: "example.com",
+			HTTP2:      true,
+			SSL: &http.SSL{
+				Certificate:    "cert.pem",
+				CertificateKey: "cert.pem",
+			},
+			Locations: []http.Location{
+				{
+					Path:     "/grpc",
+					GRPCPass: "grpc://test_foo_8080",
+				},
+				{
+					Path:      "/",
+					ProxyPass: "http://test_foo_80",
+				},
+			},
+		},
+	}
+
+	expSubStrings := map[string]int{
+		"listen 443 ssl http2;":                      1,
+		"grpc_set_header Host $host;":                1,
+		"grpc_pass grpc://test_foo_8080;":            1,
+		"proxy_pass http://test_foo_80$request_uri;": 1,
+	}
+
+	cfg := string(execute(serversTemplate, servers))
+	for expSubStr, expCount := range expSubStrings {
+		if expCount != strings.Count(cfg, expSubStr) {
+			t.Errorf(
+				"execute() did not generate servers with substring %q %d times. Servers: %v",
+				expSubStr,
+				expCount,
+				cfg,
+			)
+		}
+	}
+}
+
+func TestExecuteServersCORS(t *testing.T) {
+	servers := []http.Server{
+		{
+			ServerName: "example.com",
+			Locations: []http.Location{
+				{
+					Path:      "/api",
+					ProxyPass: "http://test_foo_80",
+					CORS: &http.CORS{
+						AllowOrigin:      "$cors_origin_100680ad546ce6a577f4",
+						AllowMethods:     "GET, POST",
+						AllowHeaders:     "Content-Type, X-Request-Id",
+						ExposeHeaders:    "X-Trace-Id",
+						MaxAge:           "3600",
+						AllowCredentials: true,
+					},
+				},
+				{
+					Path:      "/public",
+					ProxyPass: "http://test_foo_80",
+					CORS: &http.CORS{
+						AllowOrigin: "*",
+					},
+				},
+				{
+					Path:      "/",
+					ProxyPass: "http://test_foo_80",
+				},
+			},
+		},
+	}
+
+	expSubStrings := map[string]int{
+		// preflight requests
+		"if ($request_method = OPTIONS) {": 2,
+		"return 204;":                      2,
+		`add_header Access-Control-Allow-Methods "GET, POST" always;`:                  1,
+		`add_header Access-Control-Allow-Headers "Content-Type, X-Request-Id" always;`: 1,
+		"add_header Access-Control-Max-Age 3600 always;":                               1,
+		// preflight and simple requests
+		"add_header Access-Control-Allow-Origin $cors_origin_100680ad546ce6a577f4 always;": 2,
+		"add_header Access-Control-Allow-Origin * always;":                                 2,
+		"add_header Access-Control-Allow-Credentials true always;":                         2,
+		"add_header Vary Origin always;":                                                   4,
+		// simple requests
+		`add_header Access-Control-Expose-Headers "X-Trace-Id" always;`: 1,
+		"proxy_pass http://test_foo_80$request_uri;":                    3,
+	}
+
+	cfg := string(execute(serversTemplate, servers))
+	for expSubStr, expCount := range expSubStrings {
+		if expCount != strings.Count(cfg, expSubStr) {
+			t.Errorf(
+				"execute() did not generate servers with substring %q %d times. Servers: %v",
+				expSubStr,
+				expCount,
+				cfg,
+			)
+		}
+	}
+}
+
+func TestExecuteServersComments(t *testing.T) {
+	createRoute := func(name string, matches ...v1beta1.HTTPRouteMatch) *v1beta1.HTTPRoute {
+		return &v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: name},
+			Spec: v1beta1.HTTPRouteSpec{
+				Rules: []v1beta1.HTTPRouteRule{
+					{},
+					{Matches: matches},
+				},
+			},
+		}
+	}
+
+	pathMatch := v1beta1.HTTPRouteMatch{
+		Path: &v1beta1.HTTPPathMatch{Value: helpers.GetStringPointer("/")},
+	}
+	methodMatch := v1beta1.HTTPRouteMatch{
+		Path:   &v1beta1.HTTPPathMatch{Value: helpers.GetStringPointer("/coffee")},
+		Method: helpers.GetHTTPMethodPointer(v1beta1.HTTPMethodPost),
+	}
+
+	hr1 := createRoute("hr-1", pathMatch)
+	hr2 := createRoute("hr-2", methodMatch, methodMatch)
+
+	createBackendGroup := func(hr *v1beta1.HTTPRoute, svcNames ...string) graph.BackendGroup {
+		group := graph.BackendGroup{Source: client.ObjectKeyFromObject(hr), RuleIdx: 1}
+
+		for _, name := range svcNames {
+			group.Backends = append(group.Backends, graph.BackendRef{
+				Name:   "test_" + name + "_80",
+				Svc:    &v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: name}},
+				Port:   80,
+				Valid:  true,
+				Weight: 1,
+			})
+		}
+
+		return group
+	}
+
+	conf := dataplane.Configuration{
+		Gateway: types.NamespacedName{Namespace: "test", Name: "gateway"},
+		HTTPServers: []dataplane.VirtualServer{
+			{
+				IsDefault: true,
+			},
+			{
+				Hostname: "example.com",
+				Listener: "http",
+				PathRules: []dataplane.PathRule{
+					{
+						Path: "/",
+						MatchRules: []dataplane.MatchRule{
+							{
+								Source:       hr1,
+								BackendGroup: createBackendGroup(hr1, "foo"),
+								RuleIdx:      1,
+							},
+						},
+					},
+					{
+						Path: "/coffee",
+						MatchRules: []dataplane.MatchRule{
+							{
+								Source:       hr2,
+								BackendGroup: createBackendGroup(hr2, "coffee-v1", "coffee-v2"),
+								RuleIdx:      1,
+							},
+							{
+								Source:       hr2,
+								BackendGroup: createBackendGroup(hr2, "coffee-v1", "coffee-v2"),
+								RuleIdx:      1,
+								MatchIdx:     1,
+							},
+						},
+					},
+				},
+			},
+		},
+		SSLServers: []dataplane.VirtualServer{
+			{
+				IsDefault: true,
+			},
+			{
+				Hostname: "example.com",
+				Listener: "https",
+				SSL: &dataplane.SSL{
+					CertificatePath: "cert-path",
+				},
+				DefaultBackend: &dataplane.DefaultBackend{UpstreamName: "test_default_8080"},
+			},
+		},
+	}
+
+	expSubStrings := map[string]int{
+		"# Gateway test/gateway, listener http\nserver {":                              1,
+		"# Gateway test/gateway, listener https\nserver {":                             1,
+		"# HTTPRoute test/hr-1, rule 1, match 0, backends test/foo:80\n\tlocation / {": 1,
+		"# HTTPRoute test/hr-2, rule 1, match 0, backends test/coffee-v1:80, test/coffee-v2:80\n" +
+			"\tlocation /coffee_route0 {": 1,
+		"# HTTPRoute test/hr-2, rule 1, match 1, backends test/coffee-v1:80, test/coffee-v2:80\n" +
+			"\tlocation /coffee_route1 {": 1,
+		"# matches of HTTPRoutes test/hr-2\n\tlocation = /coffee {":     1,
+		"# matches of HTTPRoutes test/hr-2\n\tlocation /coffee/ {":      1,
+		"# default backend, upstream test_default_8080\n\tlocation / {": 1,
+		"#": 8,
+	}
+
+	// remove the empty lines, so that the comments are followed by the blocks
+	servers := regexp.MustCompile(`\n\s*\n`).ReplaceAllString(
+		string(executeServers(conf, true, false, false, false, false, "", nil, http.ListenOptions{})),
+		"\n",
+	)
+
+	for expSubStr, expCount := range expSubStrings {
+		if expCount != strings.Count(servers, expSubStr) {
+			t.Errorf(
+				"executeServers() did not generate servers with substring %q %d times. Servers: %v",
+				expSubStr,
+				expCount,
+				servers,
+			)
+		}
+	}
+
+	servers = string(executeServers(conf, false, false, false, false, false, "", nil, http.ListenOptions{}))
+	if strings.Contains(servers, "#") {
+		t.Errorf("executeServers() generated comments when they are disabled")
+	}
+}
+
+func TestCreateLocationsExtensionRefFilters(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "route1",
+		},
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/cors"),
+							},
+						},
+					},
+				},
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/invalid"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	corsPolicy := &v1alpha1.CORSPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "cors",
+		},
+		Spec: v1alpha1.CORSPolicySpec{
+			AllowOrigins: []string{"https://example.com"},
+			AllowMethods: []string{"GET", "POST"},
+			MaxAge:       helpers.GetInt32Pointer(600),
+		},
+	}
+
+	backendGroup := graph.BackendGroup{
+		Source:   client.ObjectKeyFromObject(hr),
+		Backends: []graph.BackendRef{{Name: "test_foo_80", Valid: true, Weight: 1}},
+	}
+
+	pathRules := []dataplane.PathRule{
+		{
+			Path: "/cors",
+			MatchRules: []dataplane.MatchRule{
+				{
+					Source:       hr,
+					BackendGroup: backendGroup,
+					Filters:      dataplane.Filters{CORSPolicy: corsPolicy},
+				},
+			},
+		},
+		{
+			Path: "/invalid",
+			MatchRules: []dataplane.MatchRule{
+				{
+					Source:       hr,
+					RuleIdx:      1,
+					BackendGroup: backendGroup,
+					Filters:      dataplane.Filters{InvalidExtensionRef: true},
+				},
+			},
+		},
+	}
+
+	expLocations := []http.Location{
+		{
+			Path:      "= /cors",
+			ProxyPass: "http://test_foo_80",
+			CORS: &http.CORS{
+				AllowOrigin:  "$cors_origin_100680ad546ce6a577f4",
+				AllowMethods: "GET, POST",
+				MaxAge:       "600",
+			},
+		},
+		{
+			Path:      "/cors/",
+			ProxyPass: "http://test_foo_80",
+			CORS: &http.CORS{
+				AllowOrigin:  "$cors_origin_100680ad546ce6a577f4",
+				AllowMethods: "GET, POST",
+				MaxAge:       "600",
+			},
+		},
+		{
+			Path:   "= /invalid",
+			Return: &http.Return{Code: http.StatusInternalServerError},
+		},
+		{
+			Path:   "/invalid/",
+			Return: &http.Return{Code: http.StatusInternalServerError},
+		},
+		createDefaultRootLocation(nil, false),
+	}
+
+	g.Expect(createLocations(pathRules, 80, nil, false, false, false, "")).To(Equal(expLocations))
+}
+
+func TestCreateLocationsStreaming(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "route1",
+		},
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	pathRules := []dataplane.PathRule{
+		{
+			Path: "/",
+			MatchRules: []dataplane.MatchRule{
+				{
+					Source: hr,
+					BackendGroup: graph.BackendGroup{
+						Source:   client.ObjectKeyFromObject(hr),
+						Backends: []graph.BackendRef{{Name: "test_foo_80", Valid: true, Weight: 1}},
+					},
+					Options: dataplane.RouteOptions{Streaming: true},
+				},
+			},
+		},
+	}
+
+	expLocations := []http.Location{
+		{
+			Path:      "/",
+			ProxyPass: "http://test_foo_80",
+			Streaming: true,
+		},
+	}
+
+	g.Expect(createLocations(pathRules, 80, nil, false, false, false, "")).To(Equal(expLocations))
+}
+
+func TestCreateLocationsUpstreamHost(t *testing.T) {
+	tests := []struct {
+		msg             string
+		opts            dataplane.RouteOptions
+		expUpstreamHost bool
+	}{
+		{
+			msg:             "host preserved",
+			opts:            dataplane.RouteOptions{},
+			expUpstreamHost: false,
+		},
+		{
+			msg:             "upstream host",
+			opts:            dataplane.RouteOptions{UpstreamHost: true},
+			expUpstreamHost: true,
+		},
+	}
+
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "route1",
+		},
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			pathRules := []dataplane.PathRule{
+				{
+					Path: "/",
+					MatchRules: []dataplane.MatchRule{
+						{
+							Source: hr,
+							BackendGroup: graph.BackendGroup{
+								Source:   client.ObjectKeyFromObject(hr),
+								Backends: []graph.BackendRef{{Name: "test_foo_80", Valid: true, Weight: 1}},
+							},
+							Options: test.opts,
+						},
+					},
+				},
+			}
+
+			expLocations := []http.Location{
+				{
+					Path:         "/",
+					ProxyPass:    "http://test_foo_80",
+					UpstreamHost: test.expUpstreamHost,
+				},
+			}
+
+			g.Expect(createLocations(pathRules, 80, nil, false, false, false, "")).To(Equal(expLocations))
+		})
+	}
+}
+
+func TestCreateLocationsRequestID(t *testing.T) {
+	tests := []struct {
+		opts         dataplane.RouteOptions
+		msg          string
+		requestID    bool
+		expRequestID bool
+	}{
+		{
+			msg: "disabled",
+		},
+		{
+			requestID:    true,
+			expRequestID: true,
+			msg:          "enabled globally",
+		},
+		{
+			opts:         dataplane.RouteOptions{RequestID: helpers.GetBoolPointer(true)},
+			expRequestID: true,
+			msg:          "enabled by the route",
+		},
+		{
+			opts:      dataplane.RouteOptions{RequestID: helpers.GetBoolPointer(false)},
+			requestID: true,
+			msg:       "disabled by the route",
+		},
+	}
+
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "route1",
+		},
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			pathRules := []dataplane.PathRule{
+				{
+					Path: "/",
+					MatchRules: []dataplane.MatchRule{
+						{
+							Source: hr,
+							BackendGroup: graph.BackendGroup{
+								Source:   client.ObjectKeyFromObject(hr),
+								Backends: []graph.BackendRef{{Name: "test_foo_80", Valid: true, Weight: 1}},
+							},
+							Options: test.opts,
+						},
+					},
+				},
+			}
+
+			expLocations := []http.Location{
+				{
+					Path:      "/",
+					ProxyPass: "http://test_foo_80",
+					RequestID: test.expRequestID,
+				},
+			}
+
+			g.Expect(createLocations(pathRules, 80, nil, false, test.requestID, false, "")).To(Equal(expLocations))
+		})
+	}
+}
+
+func TestCreateLocationsAccessLog(t *testing.T) {
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "route1",
+		},
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/"),
+							},
+						},
+					},
+				},
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/redirect"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	redirect := &v1beta1.HTTPRequestRedirectFilter{
+		Hostname: (*v1beta1.PreciseHostname)(helpers.GetStringPointer("foo.example.com")),
+	}
+
+	tests := []struct {
+		expAccessLog *http.AccessLog
+		msg          string
+		opts         dataplane.RouteOptions
+	}{
+		{
+			msg: "not overridden",
+		},
+		{
+			opts:         dataplane.RouteOptions{AccessLog: helpers.GetBoolPointer(true)},
+			expAccessLog: &http.AccessLog{Path: "/var/log/nginx/access.log"},
+			msg:          "enabled",
+		},
+		{
+			opts: dataplane.RouteOptions{
+				AccessLog:       helpers.GetBoolPointer(true),
+				AccessLogFormat: "verbose",
+			},
+			expAccessLog: &http.AccessLog{Path: "/var/log/nginx/access.log", Format: "verbose"},
+			msg:          "enabled with format",
+		},
+		{
+			opts:         dataplane.RouteOptions{AccessLog: helpers.GetBoolPointer(false)},
+			expAccessLog: &http.AccessLog{Path: "off"},
+			msg:          "disabled",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			pathRules := []dataplane.PathRule{
+				{
+					Path: "/",
+					MatchRules: []dataplane.MatchRule{
+						{
+							Source: hr,
+							BackendGroup: graph.BackendGroup{
+								Source:   client.ObjectKeyFromObject(hr),
+								Backends: []graph.BackendRef{{Name: "test_foo_80", Valid: true, Weight: 1}},
+							},
+							Options: test.opts,
+						},
+					},
+				},
+				{
+					Path: "/redirect",
+					MatchRules: []dataplane.MatchRule{
+						{
+							Source:  hr,
+							Filters: dataplane.Filters{RequestRedirect: redirect},
+							Options: test.opts,
+							RuleIdx: 1,
+						},
+					},
+				},
+			}
+
+			expLocations := []http.Location{
+				{
+					Path:      "/",
+					ProxyPass: "http://test_foo_80",
+					AccessLog: test.expAccessLog,
+				},
+				{
+					Path: "= /redirect",
+					Return: &http.Return{
+						Code: http.StatusFound,
+						URL:  "$scheme://foo.example.com:80$request_uri",
+					},
+					AccessLog: test.expAccessLog,
+				},
+				{
+					Path: "/redirect/",
+					Return: &http.Return{
+						Code: http.StatusFound,
+						URL:  "$scheme://foo.example.com:80$request_uri",
+					},
+					AccessLog: test.expAccessLog,
+				},
+			}
+
+			locs := createLocations(pathRules, 80, nil, false, false, false, "/var/log/nginx/access.log")
+			g.Expect(locs).To(Equal(expLocations))
+		})
+	}
+}
+
+func TestCreateLocationsProxyCache(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	createRule := func(path string) v1beta1.HTTPRouteRule {
+		return v1beta1.HTTPRouteRule{
+			Matches: []v1beta1.HTTPRouteMatch{
+				{
+					Path: &v1beta1.HTTPPathMatch{
+						Value: helpers.GetStringPointer(path),
+					},
+				},
+			},
+		}
+	}
+
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "route1",
+		},
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{createRule("/"), createRule("/grpc")},
+		},
+	}
+
+	createMatchRule := func(ruleIdx int, protocol dataplane.BackendProtocol) dataplane.MatchRule {
+		return dataplane.MatchRule{
+			Source:  hr,
+			RuleIdx: ruleIdx,
+			BackendGroup: graph.BackendGroup{
+				Source:   client.ObjectKeyFromObject(hr),
+				RuleIdx:  ruleIdx,
+				Backends: []graph.BackendRef{{Name: "test_foo_80", Valid: true, Weight: 1}},
+			},
+			BackendProtocol: protocol,
+			Options: dataplane.RouteOptions{
+				Cache: &dataplane.CacheOptions{Valid: "10m", Key: "$host$request_uri", MaxSize: "100m"},
+			},
+		}
+	}
+
+	pathRules := []dataplane.PathRule{
+		{
+			Path:       "/",
+			MatchRules: []dataplane.MatchRule{createMatchRule(0, dataplane.BackendProtocolHTTP1)},
+		},
+		{
+			Path:       "/grpc",
+			MatchRules: []dataplane.MatchRule{createMatchRule(1, dataplane.BackendProtocolGRPC)},
+		},
+	}
+
+	expLocations := []http.Location{
+		{
+			Path:      "/",
+			ProxyPass: "http://test_foo_80",
+			ProxyCache: &http.ProxyCache{
+				Zone:  createCacheZoneName(client.ObjectKeyFromObject(hr)),
+				Key:   "$host$request_uri",
+				Valid: "10m",
+			},
+		},
+		// the cache doesn't apply to the gRPC backends
+		{
+			Path:     "= /grpc",
+			GRPCPass: "grpc://test_foo_80",
+		},
+		{
+			Path:     "/grpc/",
+			GRPCPass: "grpc://test_foo_80",
+		},
+	}
+
+	g.Expect(createLocations(pathRules, 80, nil, false, false, false, "")).To(Equal(expLocations))
+}
+
+func TestExecuteServersProxyCache(t *testing.T) {
+	servers := []http.Server{
+		{
+			ServerName: "example.com",
+			Locations: []http.Location{
+				{
+					Path:      "/cached",
+					ProxyPass: "http://test_foo_80",
+					ProxyCache: &http.ProxyCache{
+						Zone:  "cache_2d7a0a24bfaef3a1a836",
+						Key:   "$host$request_uri",
+						Valid: "10m",
+					},
+				},
+				{
+					Path:      "/cached-default-key",
+					ProxyPass: "http://test_foo_80",
+					ProxyCache: &http.ProxyCache{
+						Zone:  "cache_2d7a0a24bfaef3a1a836",
+						Valid: "1h",
+					},
+				},
+				{
+					Path:      "/",
+					ProxyPass: "http://test_foo_80",
+				},
+			},
+		},
+	}
+
+	expSubStrings := map[string]int{
+		"proxy_cache cache_2d7a0a24bfaef3a1a836;": 2,
+		"proxy_cache_key $host$request_uri;":      1,
+		"proxy_cache_key":                         1,
+		"proxy_cache_valid 10m;":                  1,
+		"proxy_cache_valid 1h;":                   1,
+	}
+
+	cfg := string(execute(serversTemplate, servers))
+	for expSubStr, expCount := range expSubStrings {
+		if expCount != strings.Count(cfg, expSubStr) {
+			t.Errorf(
+				"execute() did not generate servers with substring %q %d times. Servers: %v",
+				expSubStr,
+				expCount,
+				cfg,
+			)
+		}
+	}
+}
+
+func TestCreateLocationsDirectResponse(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "route1",
+		},
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	createPathRule := func(path string, response *v1alpha1.DirectResponse) dataplane.PathRule {
+		return dataplane.PathRule{
+			Path: path,
+			MatchRules: []dataplane.MatchRule{
+				{
+					Source: hr,
+					BackendGroup: graph.BackendGroup{
+						Source:   client.ObjectKeyFromObject(hr),
+						Backends: []graph.BackendRef{{Name: "test_foo_80", Valid: true, Weight: 1}},
+					},
+					Filters: dataplane.Filters{
+						DirectResponse: response,
+					},
+				},
+			},
+		}
+	}
+
+	pathRules := []dataplane.PathRule{
+		createPathRule("/", &v1alpha1.DirectResponse{
+			Spec: v1alpha1.DirectResponseSpec{
+				StatusCode:  200,
+				Body:        `{"message": "C:\temp"}`,
+				ContentType: "application/json",
+			},
+		}),
+		createPathRule("/empty", &v1alpha1.DirectResponse{
+			Spec: v1alpha1.DirectResponseSpec{
+				StatusCode: 503,
+			},
+		}),
+	}
+
+	expLocations := []http.Location{
+		{
+			Path: "/",
+			Return: &http.Return{
+				Code: 200,
+				URL:  `"{\"message\": \"C:\\temp\"}"`,
+			},
+			DefaultType: "application/json",
+		},
+		{
+			Path: "= /empty",
+			Return: &http.Return{
+				Code: 503,
+				URL:  `""`,
+			},
+			DefaultType: "text/plain",
+		},
+		{
+			Path: "/empty/",
+			Return: &http.Return{
+				Code: 503,
+				URL:  `""`,
+			},
+			DefaultType: "text/plain",
+		},
+	}
+
+	g.Expect(createLocations(pathRules, 80, nil, false, false, false, "")).To(Equal(expLocations))
+}
+
+func TestCreateLocationsForwardedProto(t *testing.T) {
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "route1",
+		},
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	createPathRule := func(path, scheme string) dataplane.PathRule {
+		return dataplane.PathRule{
+			Path: path,
+			MatchRules: []dataplane.MatchRule{
+				{
+					Source: hr,
+					BackendGroup: graph.BackendGroup{
+						Source:   client.ObjectKeyFromObject(hr),
+						Backends: []graph.BackendRef{{Name: "test_foo_80", Valid: true, Weight: 1}},
+					},
+					Filters: dataplane.Filters{
+						RequestRedirect: &v1beta1.HTTPRequestRedirectFilter{
+							Scheme:     helpers.GetStringPointer(scheme),
+							Port:       (*v1beta1.PortNumber)(helpers.GetInt32Pointer(443)),
+							StatusCode: helpers.GetIntPointer(301),
+						},
+					},
+				},
+			},
+		}
+	}
+
+	pathRules := []dataplane.PathRule{
+		createPathRule("/", "https"),
+		createPathRule("/http", "http"),
+	}
+
+	tests := []struct {
+		msg            string
+		expLocations   []http.Location
+		forwardedProto bool
+	}{
+		{
+			msg: "forwarded proto is not trusted",
+			expLocations: []http.Location{
+				{
+					Path:   "/",
+					Return: &http.Return{Code: 301, URL: "https://$host:443$request_uri"},
+				},
+				{
+					Path:   "= /http",
+					Return: &http.Return{Code: 301, URL: "http://$host:443$request_uri"},
+				},
+				{
+					Path:   "/http/",
+					Return: &http.Return{Code: 301, URL: "http://$host:443$request_uri"},
+				},
+			},
+		},
+		{
+			msg:            "forwarded proto is trusted",
+			forwardedProto: true,
+			expLocations: []http.Location{
+				{
+					Path: "/",
+					Return: &http.Return{
+						Code:               301,
+						URL:                "https://$host:443$request_uri",
+						SkipForwardedHTTPS: true,
+					},
+					ProxyPass: "http://test_foo_80",
+				},
+				{
+					Path:   "= /http",
+					Return: &http.Return{Code: 301, URL: "http://$host:443$request_uri"},
+				},
+				{
+					Path:   "/http/",
+					Return: &http.Return{Code: 301, URL: "http://$host:443$request_uri"},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			locs := createLocations(pathRules, 80, nil, false, false, test.forwardedProto, "")
+			g.Expect(locs).To(Equal(test.expLocations))
+		})
+	}
+}
+
+func TestExecuteServersForwardedProto(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	servers := []http.Server{
+		{
+			ServerName: "example.com",
+			Locations: []http.Location{
+				{
+					Path: "/",
+					Return: &http.Return{
+						Code:               301,
+						URL:                "https://$host:443$request_uri",
+						SkipForwardedHTTPS: true,
+					},
+					ProxyPass: "http://test_foo_80",
+				},
+				{
+					Path:   "/http",
+					Return: &http.Return{Code: 301, URL: "http://$host:443$request_uri"},
+				},
+			},
+		},
+	}
+
+	cfg := string(execute(serversTemplate, servers))
+
+	// the requests forwarded from HTTPS skip the return and are proxied, so that the redirect doesn't loop
+	g.Expect(cfg).To(ContainSubstring("if ($forwarded_https) {\n\t\t\tbreak;\n\t\t}\n"))
+	g.Expect(strings.Count(cfg, "$forwarded_https")).To(Equal(1))
+	g.Expect(strings.Index(cfg, "$forwarded_https")).To(
+		BeNumerically("<", strings.Index(cfg, "return 301 https://$host:443$request_uri;")),
+	)
+	g.Expect(strings.Count(cfg, "proxy_pass http://test_foo_80$request_uri;")).To(Equal(1))
+}
+
+func TestExecuteServersDirectResponse(t *testing.T) {
+	servers := []http.Server{
+		{
+			ServerName: "example.com",
+			Locations: []http.Location{
+				{
+					Path: "/",
+					Return: &http.Return{
+						Code: 200,
+						URL:  `"hello"`,
+					},
+					DefaultType: "text/plain; charset=utf-8",
+				},
+			},
+		},
+	}
+
+	expSubStrings := map[string]int{
+		`default_type "text/plain; charset=utf-8";`: 1,
+		`return 200 "hello";`:                       1,
+	}
+
+	cfg := string(execute(serversTemplate, servers))
+	for expSubStr, expCount := range expSubStrings {
+		if expCount != strings.Count(cfg, expSubStr) {
+			t.Errorf(
+				"execute() did not generate servers with substring %q %d times. Servers: %v",
+				expSubStr,
+				expCount,
+				cfg,
+			)
+		}
+	}
+}
+
+func TestCreateLocationsBackendProtocols(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "route1",
+		},
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/h2c"),
+							},
+						},
+					},
+				},
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/grpc"),
+							},
+						},
+					},
+				},
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/grpc-split"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	pathRules := []dataplane.PathRule{
+		{
+			Path: "/h2c",
+			MatchRules: []dataplane.MatchRule{
+				{
+					Source:  hr,
+					RuleIdx: 0,
+					BackendGroup: graph.BackendGroup{
+						Source:   client.ObjectKeyFromObject(hr),
+						Backends: []graph.BackendRef{{Name: "test_h2c_80", Valid: true, Weight: 1}},
+					},
+					BackendProtocol: dataplane.BackendProtocolH2C,
+					// the gRPC module always streams the requests
+					Options: dataplane.RouteOptions{Streaming: true},
+				},
+			},
+		},
+		{
+			Path: "/grpc",
+			MatchRules: []dataplane.MatchRule{
+				{
+					Source:  hr,
+					RuleIdx: 1,
+					BackendGroup: graph.BackendGroup{
+						Source:   client.ObjectKeyFromObject(hr),
+						RuleIdx:  1,
+						Backends: []graph.BackendRef{{Name: "test_grpc_8080", Valid: true, Weight: 1}},
+					},
+					BackendProtocol: dataplane.BackendProtocolGRPC,
+				},
+			},
+		},
+		{
+			Path: "/grpc-split",
+			MatchRules: []dataplane.MatchRule{
+				{
+					Source:  hr,
+					RuleIdx: 2,
+					BackendGroup: graph.BackendGroup{
+						Source:  client.ObjectKeyFromObject(hr),
+						RuleIdx: 2,
+						Backends: []graph.BackendRef{
+							{Name: "test_grpc_8080", Valid: true, Weight: 1},
+							{Name: "test_grpc-v2_8080", Valid: true, Weight: 1},
+						},
+					},
+					BackendProtocol: dataplane.BackendProtocolGRPC,
+				},
+			},
+		},
+	}
+
+	expLocations := []http.Location{
+		{
+			Path:     "= /h2c",
+			GRPCPass: "grpc://test_h2c_80",
+		},
+		{
+			Path:     "/h2c/",
+			GRPCPass: "grpc://test_h2c_80",
+		},
+		{
+			Path:     "= /grpc",
+			GRPCPass: "grpc://test_grpc_8080",
+		},
+		{
+			Path:     "/grpc/",
+			GRPCPass: "grpc://test_grpc_8080",
+		},
+		{
+			Path:     "= /grpc-split",
+			GRPCPass: "grpc://$test__route1_rule2",
+		},
+		{
+			Path:     "/grpc-split/",
+			GRPCPass: "grpc://$test__route1_rule2",
+		},
+		createDefaultRootLocation(&dataplane.DefaultBackend{
+			UpstreamName: "test_default_8080",
+			Protocol:     dataplane.BackendProtocolGRPC,
+		}, false),
+	}
+
+	locs := createLocations(pathRules, 443, &dataplane.DefaultBackend{
+		UpstreamName: "test_default_8080",
+		Protocol:     dataplane.BackendProtocolGRPC,
+	}, false, false, false, "")
+
+	g.Expect(locs).To(Equal(expLocations))
+	g.Expect(locs[6].GRPCPass).To(Equal("grpc://test_default_8080"))
+	g.Expect(hasGRPCLocations(locs)).To(BeTrue())
+	g.Expect(hasGRPCLocations([]http.Location{{Path: "/", ProxyPass: "http://test_foo_80"}})).To(BeFalse())
+}
+
+func TestCreateLocationsHTTPSBackends(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "route1",
+		},
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/secure"),
+							},
+						},
+					},
+				},
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/secure-split"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	secureSvc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "secure"}}
+	secureV2Svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "secure-v2"}}
+
+	pathRules := []dataplane.PathRule{
+		{
+			Path: "/secure",
+			MatchRules: []dataplane.MatchRule{
+				{
+					Source:  hr,
+					RuleIdx: 0,
+					BackendGroup: graph.BackendGroup{
+						Source: client.ObjectKeyFromObject(hr),
+						Backends: []graph.BackendRef{
+							{Name: "test_secure_443", Svc: secureSvc, Valid: true, Weight: 1},
+						},
+					},
+					BackendProtocol: dataplane.BackendProtocolHTTPS,
+				},
+			},
+		},
+		{
+			Path: "/secure-split",
+			MatchRules: []dataplane.MatchRule{
+				{
+					Source:  hr,
+					RuleIdx: 1,
+					BackendGroup: graph.BackendGroup{
+						Source:  client.ObjectKeyFromObject(hr),
+						RuleIdx: 1,
+						Backends: []graph.BackendRef{
+							{Name: "test_secure_443", Svc: secureSvc, Valid: true, Weight: 1},
+							{Name: "test_secure-v2_443", Svc: secureV2Svc, Valid: true, Weight: 1},
+						},
+					},
+					BackendProtocol: dataplane.BackendProtocolHTTPS,
+				},
+			},
+		},
+	}
+
+	defaultBackend := &dataplane.DefaultBackend{
+		UpstreamName:  "test_default_443",
+		Protocol:      dataplane.BackendProtocolHTTPS,
+		TLSServerName: "default.test.svc",
+	}
+
+	expLocations := []http.Location{
+		{
+			Path:         "= /secure",
+			ProxyPass:    "https://test_secure_443",
+			ProxySSLName: "secure.test.svc",
+		},
+		{
+			Path:         "/secure/",
+			ProxyPass:    "https://test_secure_443",
+			ProxySSLName: "secure.test.svc",
+		},
+		{
+			Path:         "= /secure-split",
+			ProxyPass:    "https://$test__route1_rule1",
+			ProxySSLName: "$test__route1_rule1_ssl_name",
+		},
+		{
+			Path:         "/secure-split/",
+			ProxyPass:    "https://$test__route1_rule1",
+			ProxySSLName: "$test__route1_rule1_ssl_name",
+		},
+		{
+			Path:         "/",
+			ProxyPass:    "https://test_default_443",
+			ProxySSLName: "default.test.svc",
+		},
+	}
+
+	locs := createLocations(pathRules, 80, defaultBackend, false, false, false, "")
+
+	g.Expect(locs).To(Equal(expLocations))
+}
+
+func TestExecuteServersHTTPSBackends(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "route1",
+		},
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/secure"),
+							},
+						},
+					},
+				},
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/plain"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	externalSvc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "external"},
+		Spec: v1.ServiceSpec{
+			Type:         v1.ServiceTypeExternalName,
+			ExternalName: "api.example.com",
+		},
+	}
+
+	conf := dataplane.Configuration{
+		HTTPServers: []dataplane.VirtualServer{
+			{
+				Hostname: "example.com",
+				PathRules: []dataplane.PathRule{
+					{
+						Path: "/secure",
+						MatchRules: []dataplane.MatchRule{
+							{
+								Source:  hr,
+								RuleIdx: 0,
+								BackendGroup: graph.BackendGroup{
+									Source: client.ObjectKeyFromObject(hr),
+									Backends: []graph.BackendRef{
+										{Name: "test_external_443", Svc: externalSvc, Valid: true, Weight: 1},
+									},
+								},
+								BackendProtocol: dataplane.BackendProtocolHTTPS,
+							},
+						},
+					},
+					{
+						Path: "/plain",
+						MatchRules: []dataplane.MatchRule{
+							{
+								Source:  hr,
+								RuleIdx: 1,
+								BackendGroup: graph.BackendGroup{
+									Source:  client.ObjectKeyFromObject(hr),
+									RuleIdx: 1,
+									Backends: []graph.BackendRef{
+										{Name: "test_plain_80", Valid: true, Weight: 1},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	servers := string(executeServers(conf, false, false, false, false, false, "", nil, http.ListenOptions{}))
+
+	expSubStrings := map[string]int{
+		"proxy_pass https://test_external_443$request_uri;": 2,
+		"proxy_ssl_server_name on;":                         2,
+		"proxy_ssl_name api.example.com;":                   2,
+		"proxy_pass http://test_plain_80$request_uri;":      2,
+	}
+
+	for expSubStr, expCount := range expSubStrings {
+		g.Expect(strings.Count(servers, expSubStr)).To(Equal(expCount), expSubStr)
+	}
+}
+
+func TestCreateLocationsMethodMatch(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "route1",
+		},
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/api"),
+							},
+							Method: helpers.GetHTTPMethodPointer(v1beta1.HTTPMethodGet),
+						},
+					},
+				},
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/api"),
+							},
+							Method: helpers.GetHTTPMethodPointer(v1beta1.HTTPMethodPost),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	getGroup := graph.BackendGroup{
+		Source:   client.ObjectKeyFromObject(hr),
+		RuleIdx:  0,
+		Backends: []graph.BackendRef{{Name: "test_get_80", Valid: true, Weight: 1}},
+	}
+	postGroup := graph.BackendGroup{
+		Source:   client.ObjectKeyFromObject(hr),
+		RuleIdx:  1,
+		Backends: []graph.BackendRef{{Name: "test_post_80", Valid: true, Weight: 1}},
+	}
+
+	pathRules := []dataplane.PathRule{
+		{
+			Path: "/api",
+			MatchRules: []dataplane.MatchRule{
+				{
+					MatchIdx:     0,
+					RuleIdx:      0,
+					Source:       hr,
+					BackendGroup: getGroup,
+				},
+				{
+					MatchIdx:     0,
+					RuleIdx:      1,
+					Source:       hr,
+					BackendGroup: postGroup,
+				},
+			},
+		},
+	}
+
+	expMatches := []httpMatch{
+		{Method: v1beta1.HTTPMethodGet, RedirectPath: "/api_route0"},
+		{Method: v1beta1.HTTPMethodPost, RedirectPath: "/api_route1"},
+	}
+
+	b, err := json.Marshal(expMatches)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	expLocations := []http.Location{
+		{
+			Path:      "/api_route0",
+			Internal:  true,
+			ProxyPass: "http://test_get_80",
+		},
+		{
+			Path:      "/api_route1",
+			Internal:  true,
+			ProxyPass: "http://test_post_80",
+		},
+		{
+			Path:         "= /api",
+			HTTPMatchVar: string(b),
+		},
+		{
+			Path:         "/api/",
+			HTTPMatchVar: string(b),
+		},
+		createDefaultRootLocation(nil, false),
+	}
+
+	g.Expect(createLocations(pathRules, 80, nil, false, false, false, "")).To(Equal(expLocations))
+}
+
+func TestCreateLocationsMethodOnlyMatch(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "route1",
+		},
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Method: helpers.GetHTTPMethodPointer(v1beta1.HTTPMethodDelete),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	pathRules := []dataplane.PathRule{
+		{
+			Path: "/",
+			MatchRules: []dataplane.MatchRule{
+				{
+					Source: hr,
+					BackendGroup: graph.BackendGroup{
+						Source:   client.ObjectKeyFromObject(hr),
+						Backends: []graph.BackendRef{{Name: "test_delete_80", Valid: true, Weight: 1}},
+					},
+				},
+			},
+		},
+	}
+
+	b, err := json.Marshal([]httpMatch{{Method: v1beta1.HTTPMethodDelete, RedirectPath: "/_route0"}})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The match applies to all paths, so there is no default root location.
+	expLocations := []http.Location{
+		{
+			Path:      "/_route0",
+			Internal:  true,
+			ProxyPass: "http://test_delete_80",
+		},
+		{
+			Path:         "/",
+			HTTPMatchVar: string(b),
+		},
+	}
+
+	g.Expect(createLocations(pathRules, 80, nil, false, false, false, "")).To(Equal(expLocations))
+}
+
+func TestCreateLocationsGeoMatch(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "route1",
+		},
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	pathRules := []dataplane.PathRule{
+		{
+			Path: "/",
+			MatchRules: []dataplane.MatchRule{
+				{
+					Source: hr,
+					BackendGroup: graph.BackendGroup{
+						Source:   client.ObjectKeyFromObject(hr),
+						Backends: []graph.BackendRef{{Name: "test_eu_80", Valid: true, Weight: 1}},
+					},
+					Options: dataplane.RouteOptions{
+						GeoMatch: &graph.GeoMatch{
+							Type:  graph.GeoMatchTypeContinent,
+							Codes: []string{"EU"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	b, err := json.Marshal([]httpMatch{{Continents: []string{"EU"}, RedirectPath: "/_route0"}})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// A path-only match with a GeoMatch doesn't apply to all clients, so it needs the http matches.
+	expLocations := []http.Location{
+		{
+			Path:      "/_route0",
+			Internal:  true,
+			ProxyPass: "http://test_eu_80",
+		},
+		{
+			Path:         "/",
+			HTTPMatchVar: string(b),
+		},
+	}
+
+	g.Expect(createLocations(pathRules, 80, nil, false, false, false, "")).To(Equal(expLocations))
+}
+
 func TestExecuteForDefaultServers(t *testing.T) {
 	testcases := []struct {
 		msg         string
@@ -130,7 +2884,7 @@ func TestExecuteForDefaultServers(t *testing.T) {
 	}
 
 	for _, tc := range testcases {
-		cfg := string(executeServers(tc.conf))
+		cfg := string(executeServers(tc.conf, false, false, false, false, false, "", nil, http.ListenOptions{}))
 
 		defaultSSLExists := strings.Contains(cfg, "listen 443 ssl default_server")
 		defaultHTTPExists := strings.Contains(cfg, "listen 80 default_server")
@@ -480,22 +3234,44 @@ func TestCreateServers(t *testing.T) {
 				ProxyPass: "http://$test__route1_rule1",
 			},
 			{
-				Path:         "/test",
+				Path:         "= /test",
+				HTTPMatchVar: expectedMatchString(testMatches),
+			},
+			{
+				Path:         "/test/",
 				HTTPMatchVar: expectedMatchString(testMatches),
 			},
 			{
-				Path:      "/path-only",
+				Path:      "= /path-only",
+				ProxyPass: "http://invalid-backend-ref",
+			},
+			{
+				Path:      "/path-only/",
 				ProxyPass: "http://invalid-backend-ref",
 			},
 			{
-				Path: "/redirect-implicit-port",
+				Path: "= /redirect-implicit-port",
+				Return: &http.Return{
+					Code: 302,
+					URL:  fmt.Sprintf("$scheme://foo.example.com:%d$request_uri", port),
+				},
+			},
+			{
+				Path: "/redirect-implicit-port/",
 				Return: &http.Return{
 					Code: 302,
 					URL:  fmt.Sprintf("$scheme://foo.example.com:%d$request_uri", port),
 				},
 			},
 			{
-				Path: "/redirect-explicit-port",
+				Path: "= /redirect-explicit-port",
+				Return: &http.Return{
+					Code: 302,
+					URL:  "$scheme://bar.example.com:8080$request_uri",
+				},
+			},
+			{
+				Path: "/redirect-explicit-port/",
 				Return: &http.Return{
 					Code: 302,
 					URL:  "$scheme://bar.example.com:8080$request_uri",
@@ -522,7 +3298,7 @@ func TestCreateServers(t *testing.T) {
 		},
 	}
 
-	result := createServers(httpServers, sslServers)
+	result := createServers(httpServers, sslServers, nil, false, false, false, false, false, "", nil)
 
 	if diff := cmp.Diff(expectedServers, result); diff != "" {
 		t.Errorf("createServers() mismatch (-want +got):\n%s", diff)
@@ -634,20 +3410,29 @@ func TestCreateLocationsRootPath(t *testing.T) {
 	}
 
 	tests := []struct {
-		name         string
-		pathRules    []dataplane.PathRule
-		expLocations []http.Location
+		defaultBackend *dataplane.DefaultBackend
+		name           string
+		pathRules      []dataplane.PathRule
+		expLocations   []http.Location
 	}{
 		{
 			name:      "path rules with no root path should generate a default 404 root location",
 			pathRules: getPathRules(hrWithoutRootPathRule, false),
 			expLocations: []http.Location{
 				{
-					Path:      "/path-1",
+					Path:      "= /path-1",
 					ProxyPass: "http://test_foo_80",
 				},
 				{
-					Path:      "/path-2",
+					Path:      "/path-1/",
+					ProxyPass: "http://test_foo_80",
+				},
+				{
+					Path:      "= /path-2",
+					ProxyPass: "http://test_foo_80",
+				},
+				{
+					Path:      "/path-2/",
 					ProxyPass: "http://test_foo_80",
 				},
 				{
@@ -663,11 +3448,19 @@ func TestCreateLocationsRootPath(t *testing.T) {
 			pathRules: getPathRules(hrWithRootPathRule, true),
 			expLocations: []http.Location{
 				{
-					Path:      "/path-1",
+					Path:      "= /path-1",
+					ProxyPass: "http://test_foo_80",
+				},
+				{
+					Path:      "/path-1/",
+					ProxyPass: "http://test_foo_80",
+				},
+				{
+					Path:      "= /path-2",
 					ProxyPass: "http://test_foo_80",
 				},
 				{
-					Path:      "/path-2",
+					Path:      "/path-2/",
 					ProxyPass: "http://test_foo_80",
 				},
 				{
@@ -688,10 +3481,86 @@ func TestCreateLocationsRootPath(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:           "path rules with no root path should generate a default backend root location",
+			pathRules:      getPathRules(hrWithoutRootPathRule, false),
+			defaultBackend: &dataplane.DefaultBackend{UpstreamName: "test_default_8080"},
+			expLocations: []http.Location{
+				{
+					Path:      "= /path-1",
+					ProxyPass: "http://test_foo_80",
+				},
+				{
+					Path:      "/path-1/",
+					ProxyPass: "http://test_foo_80",
+				},
+				{
+					Path:      "= /path-2",
+					ProxyPass: "http://test_foo_80",
+				},
+				{
+					Path:      "/path-2/",
+					ProxyPass: "http://test_foo_80",
+				},
+				{
+					Path:      "/",
+					ProxyPass: "http://test_default_8080",
+				},
+			},
+		},
+		{
+			name:           "path rules with a root path should not generate a default backend root location",
+			pathRules:      getPathRules(hrWithRootPathRule, true),
+			defaultBackend: &dataplane.DefaultBackend{UpstreamName: "test_default_8080"},
+			expLocations: []http.Location{
+				{
+					Path:      "= /path-1",
+					ProxyPass: "http://test_foo_80",
+				},
+				{
+					Path:      "/path-1/",
+					ProxyPass: "http://test_foo_80",
+				},
+				{
+					Path:      "= /path-2",
+					ProxyPass: "http://test_foo_80",
+				},
+				{
+					Path:      "/path-2/",
+					ProxyPass: "http://test_foo_80",
+				},
+				{
+					Path:      "/",
+					ProxyPass: "http://test_foo_80",
+				},
+			},
+		},
+		{
+			name:           "nil path rules should generate a default backend root location",
+			pathRules:      nil,
+			defaultBackend: &dataplane.DefaultBackend{UpstreamName: "test_default_8080"},
+			expLocations: []http.Location{
+				{
+					Path:      "/",
+					ProxyPass: "http://test_default_8080",
+				},
+			},
+		},
+		{
+			name:           "invalid default backend should generate an invalid backend root location",
+			pathRules:      nil,
+			defaultBackend: &dataplane.DefaultBackend{},
+			expLocations: []http.Location{
+				{
+					Path:      "/",
+					ProxyPass: "http://invalid-backend-ref",
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
-		locs := createLocations(test.pathRules, 80)
+		locs := createLocations(test.pathRules, 80, test.defaultBackend, false, false, false, "")
 		g.Expect(locs).To(Equal(test.expLocations), fmt.Sprintf("test case: %s", test.name))
 	}
 }
@@ -730,16 +3599,212 @@ func TestCreateReturnValForRedirectFilter(t *testing.T) {
 			},
 			msg: "all fields are set",
 		},
+		{
+			filter: &v1beta1.HTTPRequestRedirectFilter{
+				Path: &v1beta1.HTTPPathModifier{
+					Type:            v1beta1.FullPathHTTPPathModifier,
+					ReplaceFullPath: helpers.GetStringPointer("/new"),
+				},
+			},
+			expected: &http.Return{
+				Code: http.StatusFound,
+				URL:  "$scheme://$host:123/new$is_args$args",
+			},
+			msg: "full path",
+		},
+		{
+			filter: &v1beta1.HTTPRequestRedirectFilter{
+				Path: &v1beta1.HTTPPathModifier{
+					Type:               v1beta1.PrefixMatchHTTPPathModifier,
+					ReplacePrefixMatch: helpers.GetStringPointer("/new"),
+				},
+			},
+			expected: &http.Return{
+				Code:            http.StatusFound,
+				RequestURIRegex: `^/old(/[^?]*)?(?:\?|$)`,
+				URL:             "$scheme://$host:123/new$1$is_args$args",
+				FallbackURL:     "$scheme://$host:123/new$is_args$args",
+			},
+			msg: "prefix match",
+		},
+		{
+			filter: &v1beta1.HTTPRequestRedirectFilter{
+				Path: &v1beta1.HTTPPathModifier{
+					Type: v1beta1.PrefixMatchHTTPPathModifier,
+				},
+			},
+			expected: &http.Return{
+				Code: http.StatusFound,
+				URL:  "$scheme://$host:123$request_uri",
+			},
+			msg: "prefix match without replacement",
+		},
+		{
+			filter: &v1beta1.HTTPRequestRedirectFilter{
+				Path: &v1beta1.HTTPPathModifier{
+					Type: "Unknown",
+				},
+			},
+			expected: &http.Return{
+				Code: http.StatusFound,
+				URL:  "$scheme://$host:123$request_uri",
+			},
+			msg: "unknown path modifier",
+		},
 	}
 
 	for _, test := range tests {
-		result := createReturnValForRedirectFilter(test.filter, listenerPort)
+		result := createReturnValForRedirectFilter(test.filter, listenerPort, "/old")
 		if diff := cmp.Diff(test.expected, result); diff != "" {
 			t.Errorf("createReturnValForRedirectFilter() mismatch %q (-want +got):\n%s", test.msg, diff)
 		}
 	}
 }
 
+func TestCreateReplacePrefixMatch(t *testing.T) {
+	// redirect applies the regex and the path the same way NGINX does.
+	redirect := func(regex, path, requestURI string) string {
+		captures := regexp.MustCompile(regex).FindStringSubmatch(requestURI)
+		if captures == nil {
+			return "no match"
+		}
+
+		return strings.ReplaceAll(path, "$1", captures[1])
+	}
+
+	tests := []struct {
+		expRedirects map[string]string
+		prefix       string
+		replacement  string
+		msg          string
+	}{
+		{
+			prefix:      "/old",
+			replacement: "/new",
+			expRedirects: map[string]string{
+				"/old":         "/new",
+				"/old/":        "/new/",
+				"/old/foo":     "/new/foo",
+				"/old/foo/":    "/new/foo/",
+				"/old/foo?a=b": "/new/foo",
+				"/old?a=b":     "/new",
+				"/older":       "no match",
+			},
+			msg: "no trailing slashes",
+		},
+		{
+			prefix:      "/old/",
+			replacement: "/new",
+			expRedirects: map[string]string{
+				"/old":     "/new",
+				"/old/":    "/new/",
+				"/old/foo": "/new/foo",
+			},
+			msg: "prefix with trailing slash",
+		},
+		{
+			prefix:      "/old",
+			replacement: "/new/",
+			expRedirects: map[string]string{
+				"/old":     "/new",
+				"/old/":    "/new/",
+				"/old/foo": "/new/foo",
+			},
+			msg: "replacement with trailing slash",
+		},
+		{
+			prefix:      "/old/",
+			replacement: "/new/",
+			expRedirects: map[string]string{
+				"/old":     "/new",
+				"/old/":    "/new/",
+				"/old/foo": "/new/foo",
+			},
+			msg: "prefix and replacement with trailing slashes",
+		},
+		{
+			prefix:      "/old",
+			replacement: "/",
+			expRedirects: map[string]string{
+				"/old":      "/",
+				"/old/":     "/",
+				"/old/foo":  "/foo",
+				"/old/foo/": "/foo/",
+			},
+			msg: "root replacement",
+		},
+		{
+			prefix:      "/",
+			replacement: "/new",
+			expRedirects: map[string]string{
+				"/":        "/new/",
+				"/foo":     "/new/foo",
+				"/foo/bar": "/new/foo/bar",
+			},
+			msg: "root prefix",
+		},
+		{
+			prefix:      "/",
+			replacement: "/",
+			expRedirects: map[string]string{
+				"/":    "/",
+				"/foo": "/foo",
+			},
+			msg: "root prefix and replacement",
+		},
+		{
+			prefix:      "/v1.0",
+			replacement: "/v2",
+			expRedirects: map[string]string{
+				"/v1.0/foo": "/v2/foo",
+				"/v1x0/foo": "no match",
+			},
+			msg: "prefix with regex metacharacters",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			regex, path := createReplacePrefixMatch(test.prefix, test.replacement)
+
+			for requestURI, expRedirect := range test.expRedirects {
+				g.Expect(redirect(regex, path, requestURI)).To(Equal(expRedirect), requestURI)
+			}
+		})
+	}
+}
+
+func TestExecuteServersRedirectPath(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	servers := []http.Server{
+		{
+			ServerName: "example.com",
+			Locations: []http.Location{
+				{
+					Path: "/old",
+					Return: &http.Return{
+						Code:            http.StatusFound,
+						RequestURIRegex: `^/old(/[^?]*)?(?:\?|$)`,
+						URL:             "$scheme://$host:80/new$1$is_args$args",
+						FallbackURL:     "$scheme://$host:80/new$is_args$args",
+					},
+				},
+			},
+		},
+	}
+
+	cfg := string(execute(serversTemplate, servers))
+
+	g.Expect(cfg).To(ContainSubstring(`if ($request_uri ~ "^/old(/[^?]*)?(?:\\?|$)") {
+			return 302 $scheme://$host:80/new$1$is_args$args;
+		}
+
+		return 302 $scheme://$host:80/new$is_args$args;`))
+}
+
 func TestCreateHTTPMatch(t *testing.T) {
 	testPath := "/internal_loc"
 
@@ -806,6 +3871,7 @@ func TestCreateHTTPMatch(t *testing.T) {
 	expectedArgs := []string{"arg1=val1", "arg2=val2=another-val", "arg3===val3"}
 
 	tests := []struct {
+		geoMatch *graph.GeoMatch
 		match    v1beta1.HTTPRouteMatch
 		msg      string
 		expected httpMatch
@@ -911,9 +3977,38 @@ func TestCreateHTTPMatch(t *testing.T) {
 			},
 			msg: "duplicate header names",
 		},
+		{
+			geoMatch: &graph.GeoMatch{
+				Type:  graph.GeoMatchTypeCountry,
+				Codes: []string{"AT", "DE"},
+			},
+			match: v1beta1.HTTPRouteMatch{
+				Path: &testPathMatch,
+			},
+			expected: httpMatch{
+				Countries:    []string{"AT", "DE"},
+				RedirectPath: testPath,
+			},
+			msg: "path only match with countries",
+		},
+		{
+			geoMatch: &graph.GeoMatch{
+				Type:  graph.GeoMatchTypeContinent,
+				Codes: []string{"EU"},
+			},
+			match: v1beta1.HTTPRouteMatch{
+				Method: testMethodMatch,
+			},
+			expected: httpMatch{
+				Method:       "PUT",
+				Continents:   []string{"EU"},
+				RedirectPath: testPath,
+			},
+			msg: "method match with continents",
+		},
 	}
 	for _, tc := range tests {
-		result := createHTTPMatch(tc.match, testPath)
+		result := createHTTPMatch(tc.match, testPath, tc.geoMatch)
 		if diff := helpers.Diff(result, tc.expected); diff != "" {
 			t.Errorf("createHTTPMatch() returned incorrect httpMatch for test case: %q, diff: %+v", tc.msg, diff)
 		}
@@ -1045,6 +4140,24 @@ func TestCreateProxyPassForVar(t *testing.T) {
 	}
 }
 
+func TestCreateGRPCPass(t *testing.T) {
+	expected := "grpc://10.0.0.1:80"
+
+	result := createGRPCPass("10.0.0.1:80")
+	if result != expected {
+		t.Errorf("createGRPCPass() returned %s but expected %s", result, expected)
+	}
+}
+
+func TestCreateGRPCPassForVar(t *testing.T) {
+	expected := "grpc://$my_variable"
+
+	result := createGRPCPassForVar("my-variable")
+	if result != expected {
+		t.Errorf("createGRPCPassForVar() returned %s but expected %s", result, expected)
+	}
+}
+
 func TestCreateMatchLocation(t *testing.T) {
 	expected := http.Location{
 		Path:     "/path",
@@ -1052,7 +4165,7 @@ func TestCreateMatchLocation(t *testing.T) {
 	}
 
 	result := createMatchLocation("/path")
-	if result != expected {
+	if diff := cmp.Diff(expected, result); diff != "" {
 		t.Errorf("createMatchLocation() returned %v but expected %v", result, expected)
 	}
 }
@@ -1065,3 +4178,90 @@ func TestCreatePathForMatch(t *testing.T) {
 		t.Errorf("createPathForMatch() returned %q but expected %q", result, expected)
 	}
 }
+
+func TestCreatePrefixPaths(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(createPrefixPaths("/")).To(Equal([]string{"/"}))
+	g.Expect(createPrefixPaths("/foo")).To(Equal([]string{"= /foo", "/foo/"}))
+}
+
+func TestCreatePrefixPathsMatching(t *testing.T) {
+	// matchesLocation matches a request path against a location path like NGINX does for exact and prefix locations.
+	matchesLocation := func(locPath, reqPath string) bool {
+		if exact := strings.TrimPrefix(locPath, "= "); exact != locPath {
+			return reqPath == exact
+		}
+		return strings.HasPrefix(reqPath, locPath)
+	}
+
+	tests := []struct {
+		reqPath  string
+		expMatch bool
+	}{
+		{reqPath: "/foo", expMatch: true},
+		{reqPath: "/foo/", expMatch: true},
+		{reqPath: "/foo/bar", expMatch: true},
+		{reqPath: "/foobar", expMatch: false},
+		{reqPath: "/fo", expMatch: false},
+		{reqPath: "/bar/foo", expMatch: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.reqPath, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			matched := false
+			for _, p := range createPrefixPaths("/foo") {
+				matched = matched || matchesLocation(p, test.reqPath)
+			}
+
+			g.Expect(matched).To(Equal(test.expMatch))
+		})
+	}
+}
+
+func TestExecuteServersPathPrefix(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	hr := &v1beta1.HTTPRoute{
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{Value: helpers.GetStringPointer("/foo/")},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	conf := dataplane.Configuration{
+		HTTPServers: []dataplane.VirtualServer{
+			{
+				Hostname: "example.com",
+				PathRules: []dataplane.PathRule{
+					{
+						Path: "/foo",
+						MatchRules: []dataplane.MatchRule{
+							{
+								Source: hr,
+								BackendGroup: graph.BackendGroup{
+									Backends: []graph.BackendRef{{Name: "test_foo_80", Valid: true, Weight: 1}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	servers := string(executeServers(conf, false, false, false, false, false, "", nil, http.ListenOptions{}))
+
+	g.Expect(servers).To(ContainSubstring("location = /foo {"))
+	g.Expect(servers).To(ContainSubstring("location /foo/ {"))
+	g.Expect(servers).ToNot(ContainSubstring("location /foo {"))
+}
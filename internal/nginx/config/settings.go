@@ -0,0 +1,13 @@
+package config
+
+import (
+	gotemplate "text/template"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
+)
+
+var settingsTemplate = gotemplate.Must(gotemplate.New("settings").Parse(settingsTemplateText))
+
+func executeSettings(settings http.Settings) []byte {
+	return execute(settingsTemplate, settings)
+}
@@ -0,0 +1,61 @@
+package config
+
+// With the trusted proxies, $remote_addr is the address of the client, which the X-Forwarded-For header of
+// the request already includes, so $forwarded_for_header appends the address of the last proxy instead.
+// $forwarded_https is 1 for the requests that the trusted proxies forwarded from HTTPS, which the HTTP servers
+// don't redirect to HTTPS. The X-Forwarded-Proto header of the other clients is ignored.
+var settingsTemplateText = `
+server_tokens {{ if .ServerTokens }}on{{ else }}off{{ end }};
+merge_slashes {{ if .MergeSlashes }}on{{ else }}off{{ end }};
+absolute_redirect {{ if .AbsoluteRedirect }}on{{ else }}off{{ end }};
+{{- if .TempPath }}
+client_body_temp_path {{ .TempPath }}/client_body_temp;
+proxy_temp_path {{ .TempPath }}/proxy_temp;
+fastcgi_temp_path {{ .TempPath }}/fastcgi_temp;
+uwsgi_temp_path {{ .TempPath }}/uwsgi_temp;
+scgi_temp_path {{ .TempPath }}/scgi_temp;
+{{- end }}
+{{- if .Resolver }}
+resolver {{ .Resolver }};
+{{- end }}
+{{- range $p := .TrustedProxies }}
+set_real_ip_from {{ $p }};
+{{- end }}
+{{- if .TrustedProxies }}
+real_ip_header X-Forwarded-For;
+real_ip_recursive on;
+{{- end }}
+{{- if .GeoIP2Database }}
+
+geoip2 {{ .GeoIP2Database }} {
+	$geoip2_country_code country iso_code;
+	$geoip2_continent_code continent code;
+}
+{{- end }}
+
+map $http_x_request_id $request_id_header {
+	default $http_x_request_id;
+	"" $request_id;
+}
+{{- $addr := "$remote_addr" }}
+{{- if .TrustedProxies }}{{ $addr = "$realip_remote_addr" }}{{ end }}
+
+map $http_x_forwarded_for $forwarded_for_header {
+	default "$http_x_forwarded_for, {{ $addr }}";
+	"" {{ $addr }};
+}
+{{- if .TrustedProxies }}
+
+geo $realip_remote_addr $trusted_proxy {
+	default 0;
+	{{- range $p := .TrustedProxies }}
+	{{ $p }} 1;
+	{{- end }}
+}
+
+map "$trusted_proxy:$http_x_forwarded_proto" $forwarded_https {
+	default 0;
+	"1:https" 1;
+}
+{{- end }}
+`
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
+)
+
+func TestExecuteSettings(t *testing.T) {
+	tests := []struct {
+		settings     http.Settings
+		msg          string
+		expSubString string
+	}{
+		{
+			settings:     http.Settings{},
+			expSubString: "server_tokens off;",
+			msg:          "default",
+		},
+		{
+			settings: http.Settings{
+				ServerTokens: true,
+			},
+			expSubString: "server_tokens on;",
+			msg:          "server tokens enabled",
+		},
+		{
+			settings:     http.Settings{},
+			expSubString: "merge_slashes off;",
+			msg:          "merge slashes disabled",
+		},
+		{
+			settings: http.Settings{
+				MergeSlashes: true,
+			},
+			expSubString: "merge_slashes on;",
+			msg:          "merge slashes enabled",
+		},
+		{
+			settings:     http.Settings{},
+			expSubString: "absolute_redirect off;",
+			msg:          "absolute redirect disabled",
+		},
+		{
+			settings: http.Settings{
+				AbsoluteRedirect: true,
+			},
+			expSubString: "absolute_redirect on;",
+			msg:          "absolute redirect enabled",
+		},
+		{
+			settings: http.Settings{
+				TempPath: "/var/lib/nginx",
+			},
+			expSubString: "client_body_temp_path /var/lib/nginx/client_body_temp;\n" +
+				"proxy_temp_path /var/lib/nginx/proxy_temp;\n" +
+				"fastcgi_temp_path /var/lib/nginx/fastcgi_temp;\n" +
+				"uwsgi_temp_path /var/lib/nginx/uwsgi_temp;\n" +
+				"scgi_temp_path /var/lib/nginx/scgi_temp;\n",
+			msg: "temp path",
+		},
+		{
+			settings: http.Settings{
+				Resolver: "10.96.0.10 kube-dns.kube-system.svc.cluster.local:53",
+			},
+			expSubString: "resolver 10.96.0.10 kube-dns.kube-system.svc.cluster.local:53;",
+			msg:          "resolver",
+		},
+		{
+			settings: http.Settings{},
+			expSubString: "map $http_x_request_id $request_id_header {\n" +
+				"\tdefault $http_x_request_id;\n" +
+				"\t\"\" $request_id;\n" +
+				"}\n",
+			msg: "request id",
+		},
+		{
+			settings: http.Settings{},
+			expSubString: "map $http_x_forwarded_for $forwarded_for_header {\n" +
+				"\tdefault \"$http_x_forwarded_for, $remote_addr\";\n" +
+				"\t\"\" $remote_addr;\n" +
+				"}\n",
+			msg: "forwarded for without trusted proxies",
+		},
+		{
+			settings: http.Settings{
+				TrustedProxies: []string{"10.0.0.0/8", "192.168.1.10"},
+			},
+			expSubString: "set_real_ip_from 10.0.0.0/8;\n" +
+				"set_real_ip_from 192.168.1.10;\n" +
+				"real_ip_header X-Forwarded-For;\n" +
+				"real_ip_recursive on;\n",
+			msg: "trusted proxies",
+		},
+		{
+			settings: http.Settings{
+				TrustedProxies: []string{"10.0.0.0/8"},
+			},
+			expSubString: "map $http_x_forwarded_for $forwarded_for_header {\n" +
+				"\tdefault \"$http_x_forwarded_for, $realip_remote_addr\";\n" +
+				"\t\"\" $realip_remote_addr;\n" +
+				"}\n",
+			msg: "forwarded for with trusted proxies",
+		},
+		{
+			settings: http.Settings{
+				TrustedProxies: []string{"10.0.0.0/8", "2001:db8::/32"},
+			},
+			expSubString: "geo $realip_remote_addr $trusted_proxy {\n" +
+				"\tdefault 0;\n" +
+				"\t10.0.0.0/8 1;\n" +
+				"\t2001:db8::/32 1;\n" +
+				"}\n\n" +
+				"map \"$trusted_proxy:$http_x_forwarded_proto\" $forwarded_https {\n" +
+				"\tdefault 0;\n" +
+				"\t\"1:https\" 1;\n" +
+				"}\n",
+			msg: "forwarded https with trusted proxies",
+		},
+		{
+			settings: http.Settings{
+				GeoIP2Database: "/etc/nginx/geoip/GeoLite2-Country.mmdb",
+			},
+			expSubString: "geoip2 /etc/nginx/geoip/GeoLite2-Country.mmdb {\n" +
+				"\t$geoip2_country_code country iso_code;\n" +
+				"\t$geoip2_continent_code continent code;\n" +
+				"}\n",
+			msg: "GeoIP2 database",
+		},
+	}
+
+	for _, test := range tests {
+		settings := string(executeSettings(test.settings))
+
+		if !strings.Contains(settings, test.expSubString) {
+			t.Errorf(
+				"executeSettings() %q did not generate settings with substring %q. Settings: %v",
+				test.msg,
+				test.expSubString,
+				settings,
+			)
+		}
+	}
+}
+
+func TestExecuteSettingsWithoutTempPath(t *testing.T) {
+	settings := string(executeSettings(http.Settings{}))
+
+	if strings.Contains(settings, "_temp_path") {
+		t.Errorf("executeSettings() generated temp path directives without a temp path. Settings: %v", settings)
+	}
+}
+
+func TestExecuteSettingsWithoutResolver(t *testing.T) {
+	settings := string(executeSettings(http.Settings{}))
+
+	if strings.Contains(settings, "resolver") {
+		t.Errorf("executeSettings() generated the resolver directive without a resolver. Settings: %v", settings)
+	}
+}
+
+func TestExecuteSettingsWithoutTrustedProxies(t *testing.T) {
+	settings := string(executeSettings(http.Settings{}))
+
+	if strings.Contains(settings, "real_ip") {
+		t.Errorf("executeSettings() generated the real IP directives without trusted proxies. Settings: %v", settings)
+	}
+
+	if strings.Contains(settings, "$forwarded_https") {
+		t.Errorf("executeSettings() generated the forwarded HTTPS map without trusted proxies. Settings: %v", settings)
+	}
+}
+
+func TestExecuteSettingsWithoutGeoIP2Database(t *testing.T) {
+	settings := string(executeSettings(http.Settings{}))
+
+	if strings.Contains(settings, "geoip2") {
+		t.Errorf("executeSettings() generated the GeoIP2 lookups without a database. Settings: %v", settings)
+	}
+}
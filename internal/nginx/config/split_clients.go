@@ -2,7 +2,6 @@ package config
 
 import (
 	"fmt"
-	"math"
 	gotemplate "text/template"
 
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
@@ -10,15 +9,23 @@ import (
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
 )
 
+// defaultSplitClientsKey is the key of the split clients if no key is configured. A random request ID spreads
+// the requests across the backends, but doesn't keep the requests of a client on the same backend.
+const defaultSplitClientsKey = "$request_id"
+
 var splitClientsTemplate = gotemplate.Must(gotemplate.New("split_clients").Parse(splitClientsTemplateText))
 
-func executeSplitClients(conf dataplane.Configuration) []byte {
-	splitClients := createSplitClients(conf.BackendGroups)
+func executeSplitClients(conf dataplane.Configuration, key string) []byte {
+	splitClients := createSplitClients(conf.BackendGroups, key)
 
 	return execute(splitClientsTemplate, splitClients)
 }
 
-func createSplitClients(backendGroups []graph.BackendGroup) []http.SplitClient {
+// createSplitClients creates the split clients for the backend groups that need a split, in the order of the groups.
+// The percentages of a split client only depend on the weights of the backends, and NGINX assigns a key to
+// a backend by its hash, so the same key is assigned to the same backend across reloads as long as the weights
+// don't change. If key is empty, defaultSplitClientsKey is used.
+func createSplitClients(backendGroups []graph.BackendGroup, key string) []http.SplitClient {
 	numSplits := 0
 	for _, group := range backendGroups {
 		if backendGroupNeedsSplit(group) {
@@ -30,6 +37,10 @@ func createSplitClients(backendGroups []graph.BackendGroup) []http.SplitClient {
 		return nil
 	}
 
+	if key == "" {
+		key = defaultSplitClientsKey
+	}
+
 	splitClients := make([]http.SplitClient, 0, numSplits)
 
 	for _, group := range backendGroups {
@@ -40,6 +51,7 @@ func createSplitClients(backendGroups []graph.BackendGroup) []http.SplitClient {
 		}
 
 		splitClients = append(splitClients, http.SplitClient{
+			Key:           key,
 			VariableName:  convertStringToSafeVariableName(group.GroupName()),
 			Distributions: distributions,
 		})
@@ -54,14 +66,8 @@ func createSplitClientDistributions(group graph.BackendGroup) []http.SplitClient
 		return nil
 	}
 
-	backends := group.Backends
-
-	totalWeight := int32(0)
-	for _, b := range backends {
-		totalWeight += b.Weight
-	}
-
-	if totalWeight == 0 {
+	percentages := group.Percentages()
+	if percentages == nil {
 		return []http.SplitClientDistribution{
 			{
 				Percent: "100",
@@ -70,34 +76,15 @@ func createSplitClientDistributions(group graph.BackendGroup) []http.SplitClient
 		}
 	}
 
-	distributions := make([]http.SplitClientDistribution, 0, len(backends))
-
-	// The percentage of all backends cannot exceed 100.
-	availablePercentage := float64(100)
-
-	// Iterate over all backends except the last one.
-	// The last backend will get the remaining percentage.
-	for i := 0; i < len(backends)-1; i++ {
-		b := backends[i]
-
-		percentage := percentOf(b.Weight, totalWeight)
-		availablePercentage -= percentage
+	distributions := make([]http.SplitClientDistribution, 0, len(group.Backends))
 
+	for i, b := range group.Backends {
 		distributions = append(distributions, http.SplitClientDistribution{
-			Percent: fmt.Sprintf("%.2f", percentage),
+			Percent: fmt.Sprintf("%.2f", percentages[i]),
 			Value:   getSplitClientValue(b),
 		})
 	}
 
-	// The last backend gets the remaining percentage.
-	// This is done to guarantee that the sum of all percentages is 100.
-	lastBackend := backends[len(backends)-1]
-
-	distributions = append(distributions, http.SplitClientDistribution{
-		Percent: fmt.Sprintf("%.2f", availablePercentage),
-		Value:   getSplitClientValue(lastBackend),
-	})
-
 	return distributions
 }
 
@@ -108,16 +95,6 @@ func getSplitClientValue(b graph.BackendRef) string {
 	return invalidBackendRef
 }
 
-// percentOf returns the percentage of a weight out of a totalWeight.
-// The percentage is rounded to 2 decimal places using the Floor method.
-// Floor is used here in order to guarantee that the sum of all percentages does not exceed 100.
-// Ex. percentOf(2, 3) = 66.66
-// Ex. percentOf(800, 2000) = 40.00
-func percentOf(weight, totalWeight int32) float64 {
-	p := (float64(weight) * 100) / float64(totalWeight)
-	return math.Floor(p*100) / 100
-}
-
 func backendGroupNeedsSplit(group graph.BackendGroup) bool {
 	return len(group.Backends) > 1
 }
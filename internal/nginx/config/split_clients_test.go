@@ -41,6 +41,7 @@ func TestExecuteSplitClients(t *testing.T) {
 
 	tests := []struct {
 		msg           string
+		key           string
 		backendGroups []graph.BackendGroup
 		expStrings    []string
 		notExpStrings []string
@@ -62,6 +63,17 @@ func TestExecuteSplitClients(t *testing.T) {
 			},
 			notExpStrings: []string{"no-split", "#"},
 		},
+		{
+			msg: "custom key",
+			key: "$remote_addr",
+			backendGroups: []graph.BackendGroup{
+				bg1,
+			},
+			expStrings: []string{
+				"split_clients $remote_addr $test__hr_rule0",
+			},
+			notExpStrings: []string{"$request_id"},
+		},
 		{
 			msg: "zero weight",
 			backendGroups: []graph.BackendGroup{
@@ -98,7 +110,7 @@ func TestExecuteSplitClients(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		sc := string(executeSplitClients(dataplane.Configuration{BackendGroups: test.backendGroups}))
+		sc := string(executeSplitClients(dataplane.Configuration{BackendGroups: test.backendGroups}, test.key))
 
 		for _, expSubString := range test.expStrings {
 			if !strings.Contains(sc, expSubString) {
@@ -181,6 +193,7 @@ func TestCreateSplitClients(t *testing.T) {
 	tests := []struct {
 		msg             string
 		backendGroups   []graph.BackendGroup
+		key             string
 		expSplitClients []http.SplitClient
 	}{
 		{
@@ -195,6 +208,7 @@ func TestCreateSplitClients(t *testing.T) {
 			},
 			expSplitClients: []http.SplitClient{
 				{
+					Key:          "$request_id",
 					VariableName: "test__hr_one_split_rule0",
 					Distributions: []http.SplitClientDistribution{
 						{
@@ -208,6 +222,7 @@ func TestCreateSplitClients(t *testing.T) {
 					},
 				},
 				{
+					Key:          "$request_id",
 					VariableName: "test__hr_two_splits_rule0",
 					Distributions: []http.SplitClientDistribution{
 						{
@@ -221,6 +236,7 @@ func TestCreateSplitClients(t *testing.T) {
 					},
 				},
 				{
+					Key:          "$request_id",
 					VariableName: "test__hr_two_splits_rule1",
 					Distributions: []http.SplitClientDistribution{
 						{
@@ -239,6 +255,29 @@ func TestCreateSplitClients(t *testing.T) {
 				},
 			},
 		},
+		{
+			msg: "custom key",
+			backendGroups: []graph.BackendGroup{
+				oneSplit,
+			},
+			key: "$cookie_session$remote_addr",
+			expSplitClients: []http.SplitClient{
+				{
+					Key:          "$cookie_session$remote_addr",
+					VariableName: "test__hr_one_split_rule0",
+					Distributions: []http.SplitClientDistribution{
+						{
+							Percent: "50.00",
+							Value:   "one-split-1",
+						},
+						{
+							Percent: "50.00",
+							Value:   "one-split-2",
+						},
+					},
+				},
+			},
+		},
 		{
 			msg: "no split clients are needed",
 			backendGroups: []graph.BackendGroup{
@@ -250,7 +289,7 @@ func TestCreateSplitClients(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		result := createSplitClients(test.backendGroups)
+		result := createSplitClients(test.backendGroups, test.key)
 		if diff := cmp.Diff(test.expSplitClients, result); diff != "" {
 			t.Errorf("createSplitClients() mismatch for %q (-want +got):\n%s", test.msg, diff)
 		}
@@ -438,80 +477,6 @@ func TestGetSplitClientValue(t *testing.T) {
 	}
 }
 
-func TestPercentOf(t *testing.T) {
-	tests := []struct {
-		msg         string
-		weight      int32
-		totalWeight int32
-		expPercent  float64
-	}{
-		{
-			msg:         "50/100",
-			weight:      50,
-			totalWeight: 100,
-			expPercent:  50,
-		},
-		{
-			msg:         "2000/4000",
-			weight:      2000,
-			totalWeight: 4000,
-			expPercent:  50,
-		},
-		{
-			msg:         "100/100",
-			weight:      100,
-			totalWeight: 100,
-			expPercent:  100,
-		},
-		{
-			msg:         "5/5",
-			weight:      5,
-			totalWeight: 5,
-			expPercent:  100,
-		},
-		{
-			msg:         "0/8000",
-			weight:      0,
-			totalWeight: 8000,
-			expPercent:  0,
-		},
-		{
-			msg:         "2/3",
-			weight:      2,
-			totalWeight: 3,
-			expPercent:  66.66,
-		},
-		{
-			msg:         "4/15",
-			weight:      4,
-			totalWeight: 15,
-			expPercent:  26.66,
-		},
-		{
-			msg:         "800/2000",
-			weight:      800,
-			totalWeight: 2000,
-			expPercent:  40,
-		},
-		{
-			msg:         "300/2400",
-			weight:      300,
-			totalWeight: 2400,
-			expPercent:  12.5,
-		},
-	}
-
-	for _, test := range tests {
-		percent := percentOf(test.weight, test.totalWeight)
-		if percent != test.expPercent {
-			t.Errorf(
-				"percentOf() mismatch for test %q; expected %f, got %f",
-				test.msg, test.expPercent, percent,
-			)
-		}
-	}
-}
-
 func TestBackendGroupNeedsSplit(t *testing.T) {
 	tests := []struct {
 		msg      string
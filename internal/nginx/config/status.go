@@ -0,0 +1,13 @@
+package config
+
+import (
+	gotemplate "text/template"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
+)
+
+var statusTemplate = gotemplate.Must(gotemplate.New("status").Parse(statusTemplateText))
+
+func executeStatus(status http.Status) []byte {
+	return execute(statusTemplate, status)
+}
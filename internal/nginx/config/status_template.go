@@ -0,0 +1,22 @@
+package config
+
+// The status server only listens on the loopback address and only allows the requests from it,
+// so that the status is only available from within the pod of NGINX.
+var statusTemplateText = `
+{{- if .Port }}
+server {
+	listen 127.0.0.1:{{ .Port }};
+	access_log off;
+
+	location = {{ .Path }} {
+		stub_status;
+		allow 127.0.0.1;
+		deny all;
+	}
+
+	location / {
+		return 404;
+	}
+}
+{{- end }}
+`
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
+)
+
+func TestExecuteStatus(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	status := string(executeStatus(http.Status{Path: "/stub_status", Port: 8081}))
+
+	expSubStrings := []string{
+		"listen 127.0.0.1:8081;",
+		"location = /stub_status {\n\t\tstub_status;\n\t\tallow 127.0.0.1;\n\t\tdeny all;\n\t}",
+		"location / {\n\t\treturn 404;\n\t}",
+	}
+
+	for _, expSubStr := range expSubStrings {
+		g.Expect(status).To(ContainSubstring(expSubStr))
+	}
+
+	g.Expect(strings.TrimSpace(string(executeStatus(http.Status{Path: "/stub_status"})))).To(BeEmpty())
+}
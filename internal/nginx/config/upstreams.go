@@ -1,7 +1,7 @@
 package config
 
 import (
-	"fmt"
+	"strconv"
 	gotemplate "text/template"
 
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
@@ -17,20 +17,39 @@ const (
 	nginx500Server = "unix:/var/lib/nginx/nginx-500-server.sock"
 	// invalidBackendRef is used as an upstream name for invalid backend references.
 	invalidBackendRef = "invalid-backend-ref"
+
+	// defaultMaxFails and defaultFailTimeout configure passive health checks of the upstream servers:
+	// a server that fails 3 times within 10 seconds is not used for the following 10 seconds.
+	defaultMaxFails    = 3
+	defaultFailTimeout = "10s"
+
+	// resolveZoneSize is the size of the shared memory zone of an upstream with a server that NGINX resolves
+	// at run time. NGINX requires the zone to keep the resolved addresses of the server.
+	resolveZoneSize = "64k"
 )
 
-func executeUpstreams(conf dataplane.Configuration) []byte {
-	upstreams := createUpstreams(conf.Upstreams)
+func executeUpstreams(conf dataplane.Configuration, comments, resolve, plus bool) []byte {
+	upstreams := createUpstreams(conf.Upstreams, comments, resolve, plus)
 
 	return execute(upstreamsTemplate, upstreams)
 }
 
-func createUpstreams(upstreams []dataplane.Upstream) []http.Upstream {
+// createUpstreams creates the upstreams. If resolve is true, the upstreams of the ExternalName Services
+// have a server with the resolve parameter; otherwise, they don't have the endpoints and fail the requests with 502.
+// If plus is true, the servers have the NGINX Plus parameters, such as slow_start; otherwise, these options
+// of the upstreams are ignored.
+func createUpstreams(upstreams []dataplane.Upstream, comments, resolve, plus bool) []http.Upstream {
 	// capacity is the number of upstreams + 1 for the invalid backend ref upstream
 	ups := make([]http.Upstream, 0, len(upstreams)+1)
 
 	for _, u := range upstreams {
-		ups = append(ups, createUpstream(u))
+		up := createUpstream(u, resolve, plus)
+
+		if comments {
+			up.Comment = createUpstreamComment(u)
+		}
+
+		ups = append(ups, up)
 	}
 
 	ups = append(ups, createInvalidBackendRefUpstream())
@@ -38,7 +57,15 @@ func createUpstreams(upstreams []dataplane.Upstream) []http.Upstream {
 	return ups
 }
 
-func createUpstream(up dataplane.Upstream) http.Upstream {
+func createUpstream(up dataplane.Upstream, resolve, plus bool) http.Upstream {
+	if up.Options.UnixSocket != "" {
+		return createUnixSocketUpstream(up, plus)
+	}
+
+	if resolve && up.Hostname != "" {
+		return createResolveUpstream(up, plus)
+	}
+
 	if len(up.Endpoints) == 0 {
 		return http.Upstream{
 			Name: up.Name,
@@ -50,19 +77,109 @@ func createUpstream(up dataplane.Upstream) http.Upstream {
 		}
 	}
 
-	upstreamServers := make([]http.UpstreamServer, len(up.Endpoints))
-	for idx, ep := range up.Endpoints {
-		upstreamServers[idx] = http.UpstreamServer{
-			Address: fmt.Sprintf("%s:%d", ep.Address, ep.Port),
-		}
+	maxFails, failTimeout := getPassiveHealthCheckParams(up.Options)
+	slowStart := getSlowStart(up.Options, plus)
+
+	upstreamServers := make([]http.UpstreamServer, 0, len(up.Endpoints)+len(up.DrainingEndpoints))
+	for _, ep := range up.Endpoints {
+		upstreamServers = append(upstreamServers, http.UpstreamServer{
+			Address:     createUpstreamServerAddress(ep.Address, ep.Port),
+			MaxFails:    maxFails,
+			FailTimeout: failTimeout,
+			SlowStart:   slowStart,
+		})
+	}
+
+	// The draining endpoints are only added along with the endpoints of the Upstream, because an Upstream without
+	// the endpoints must fail the requests with 502 right away.
+	for _, ep := range up.DrainingEndpoints {
+		upstreamServers = append(upstreamServers, http.UpstreamServer{
+			Address: createUpstreamServerAddress(ep.Address, ep.Port),
+			Down:    true,
+		})
 	}
 
 	return http.Upstream{
-		Name:    up.Name,
-		Servers: upstreamServers,
+		Name:      up.Name,
+		HashKey:   up.Options.HashKey,
+		LeastConn: slowStart != "",
+		Servers:   upstreamServers,
 	}
 }
 
+// createUpstreamServerAddress creates the address of an upstream server from the host and the port.
+func createUpstreamServerAddress(host string, port int32) string {
+	return host + ":" + strconv.FormatInt(int64(port), 10)
+}
+
+// createResolveUpstream creates the upstream of an ExternalName Service with a single server that NGINX resolves
+// using the resolver of the http context and re-resolves when the DNS record of the hostname expires.
+func createResolveUpstream(up dataplane.Upstream, plus bool) http.Upstream {
+	maxFails, failTimeout := getPassiveHealthCheckParams(up.Options)
+	slowStart := getSlowStart(up.Options, plus)
+
+	return http.Upstream{
+		Name:      up.Name,
+		HashKey:   up.Options.HashKey,
+		LeastConn: slowStart != "",
+		ZoneSize:  resolveZoneSize,
+		Servers: []http.UpstreamServer{
+			{
+				Address:     createUpstreamServerAddress(up.Hostname, up.Port),
+				MaxFails:    maxFails,
+				FailTimeout: failTimeout,
+				SlowStart:   slowStart,
+				Resolve:     true,
+			},
+		},
+	}
+}
+
+// createUnixSocketUpstream creates the upstream of a Service with a single server that is the Unix domain socket
+// of the Service, for example, of a sidecar container, instead of the endpoints of the Service.
+func createUnixSocketUpstream(up dataplane.Upstream, plus bool) http.Upstream {
+	maxFails, failTimeout := getPassiveHealthCheckParams(up.Options)
+	slowStart := getSlowStart(up.Options, plus)
+
+	return http.Upstream{
+		Name:      up.Name,
+		HashKey:   up.Options.HashKey,
+		LeastConn: slowStart != "",
+		Servers: []http.UpstreamServer{
+			{
+				Address:     "unix:" + up.Options.UnixSocket,
+				MaxFails:    maxFails,
+				FailTimeout: failTimeout,
+				SlowStart:   slowStart,
+			},
+		},
+	}
+}
+
+func getPassiveHealthCheckParams(opts dataplane.UpstreamOptions) (maxFails int32, failTimeout string) {
+	maxFails = defaultMaxFails
+	if opts.MaxFails != nil {
+		maxFails = *opts.MaxFails
+	}
+
+	failTimeout = defaultFailTimeout
+	if opts.FailTimeout != "" {
+		failTimeout = opts.FailTimeout
+	}
+
+	return maxFails, failTimeout
+}
+
+// getSlowStart returns the slow start of the servers of an upstream. The slow start requires NGINX Plus and
+// is not compatible with the hash load balancing method, so it is empty without NGINX Plus or with a hash key.
+func getSlowStart(opts dataplane.UpstreamOptions, plus bool) string {
+	if !plus || opts.HashKey != "" {
+		return ""
+	}
+
+	return opts.SlowStart
+}
+
 func createInvalidBackendRefUpstream() http.Upstream {
 	return http.Upstream{
 		Name: invalidBackendRef,
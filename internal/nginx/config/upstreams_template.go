@@ -4,10 +4,26 @@ package config
 // This should be dynamically calculated based on the number of upstreams.
 var upstreamsTemplateText = `
 {{ range $u := . }}
+{{- if $u.Comment }}
+# {{ $u.Comment }}
+{{- end }}
 upstream {{ $u.Name }} {
+    {{- if $u.ZoneSize }}
+    zone {{ $u.Name }} {{ $u.ZoneSize }};
+    {{- end }}
+    {{ if $u.HashKey }}
+    hash {{ $u.HashKey }} consistent;
+    {{ else if $u.LeastConn }}
+    least_conn;
+    {{ else }}
     random two least_conn;
+    {{ end }}
     {{ range $server := $u.Servers }} 
-    server {{ $server.Address }};
+    server {{ $server.Address }}
+    {{- if $server.FailTimeout }} max_fails={{ $server.MaxFails }} fail_timeout={{ $server.FailTimeout }}{{ end }}
+    {{- if $server.SlowStart }} slow_start={{ $server.SlowStart }}{{ end }}
+    {{- if $server.Resolve }} resolve{{ end }}
+    {{- if $server.Down }} down{{ end }};
     {{ end }}
 }
 {{ end }}`
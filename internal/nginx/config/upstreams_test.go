@@ -5,7 +5,9 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config/http"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/resolver"
@@ -30,6 +32,17 @@ func TestExecuteUpstreams(t *testing.T) {
 					Port:    80,
 				},
 			},
+			Options: dataplane.UpstreamOptions{
+				HashKey:     "$http_x_session",
+				MaxFails:    helpers.GetInt32Pointer(0),
+				FailTimeout: "30s",
+			},
+			DrainingEndpoints: []resolver.Endpoint{
+				{
+					Address: "11.0.0.1",
+					Port:    80,
+				},
+			},
 		},
 		{
 			Name:      "up3",
@@ -42,12 +55,15 @@ func TestExecuteUpstreams(t *testing.T) {
 		"upstream up2",
 		"upstream up3",
 		"upstream invalid-backend-ref",
-		"server 10.0.0.0:80;",
-		"server 11.0.0.0:80;",
+		"server 10.0.0.0:80 max_fails=3 fail_timeout=10s;",
+		"server 11.0.0.0:80 max_fails=0 fail_timeout=30s;",
+		"server 11.0.0.1:80 down;",
 		"server unix:/var/lib/nginx/nginx-502-server.sock;",
+		"random two least_conn;",
+		"hash $http_x_session consistent;",
 	}
 
-	upstreams := string(executeUpstreams(dataplane.Configuration{Upstreams: stateUpstreams}))
+	upstreams := string(executeUpstreams(dataplane.Configuration{Upstreams: stateUpstreams}, false, false, false))
 	for _, expSubString := range expectedSubStrings {
 		if !strings.Contains(upstreams, expSubString) {
 			t.Errorf(
@@ -59,6 +75,87 @@ func TestExecuteUpstreams(t *testing.T) {
 	}
 }
 
+func TestExecuteUpstreamsComments(t *testing.T) {
+	conf := dataplane.Configuration{
+		Upstreams: []dataplane.Upstream{
+			{
+				Name:      "test_foo_80",
+				Service:   types.NamespacedName{Namespace: "test", Name: "foo"},
+				Port:      80,
+				Endpoints: []resolver.Endpoint{{Address: "10.0.0.0", Port: 8080}},
+			},
+			{
+				Name:    "test_bar_8080",
+				Service: types.NamespacedName{Namespace: "test", Name: "bar"},
+				Port:    8080,
+			},
+		},
+	}
+
+	expSubStrings := map[string]int{
+		"# Service test/foo, port 80\nupstream test_foo_80 {":     1,
+		"# Service test/bar, port 8080\nupstream test_bar_8080 {": 1,
+		"#": 2,
+	}
+
+	upstreams := string(executeUpstreams(conf, true, false, false))
+	for expSubStr, expCount := range expSubStrings {
+		if expCount != strings.Count(upstreams, expSubStr) {
+			t.Errorf(
+				"executeUpstreams() did not generate upstreams with substring %q %d times. Upstreams: %v",
+				expSubStr,
+				expCount,
+				upstreams,
+			)
+		}
+	}
+
+	if strings.Contains(string(executeUpstreams(conf, false, false, false)), "#") {
+		t.Errorf("executeUpstreams() generated comments when they are disabled")
+	}
+}
+
+func TestExecuteUpstreamsResolve(t *testing.T) {
+	conf := dataplane.Configuration{
+		Upstreams: []dataplane.Upstream{
+			{
+				Name:     "test_external_443",
+				Port:     443,
+				Hostname: "example.com",
+			},
+			{
+				Name:      "test_foo_80",
+				Port:      80,
+				Endpoints: []resolver.Endpoint{{Address: "10.0.0.0", Port: 8080}},
+			},
+		},
+	}
+
+	expSubStrings := map[string]int{
+		"zone test_external_443 64k;":                                  1,
+		"server example.com:443 max_fails=3 fail_timeout=10s resolve;": 1,
+		"server 10.0.0.0:8080 max_fails=3 fail_timeout=10s;":           1,
+		"zone ": 1,
+	}
+
+	upstreams := string(executeUpstreams(conf, false, true, false))
+	for expSubStr, expCount := range expSubStrings {
+		if expCount != strings.Count(upstreams, expSubStr) {
+			t.Errorf(
+				"executeUpstreams() did not generate upstreams with substring %q %d times. Upstreams: %v",
+				expSubStr,
+				expCount,
+				upstreams,
+			)
+		}
+	}
+
+	upstreams = string(executeUpstreams(conf, false, false, false))
+	if strings.Contains(upstreams, "resolve") || strings.Contains(upstreams, "zone") {
+		t.Errorf("executeUpstreams() generated a resolvable server when resolving is disabled. Upstreams: %v", upstreams)
+	}
+}
+
 func TestCreateUpstreams(t *testing.T) {
 	stateUpstreams := []dataplane.Upstream{
 		{
@@ -98,13 +195,19 @@ func TestCreateUpstreams(t *testing.T) {
 			Name: "up1",
 			Servers: []http.UpstreamServer{
 				{
-					Address: "10.0.0.0:80",
+					Address:     "10.0.0.0:80",
+					MaxFails:    defaultMaxFails,
+					FailTimeout: defaultFailTimeout,
 				},
 				{
-					Address: "10.0.0.1:80",
+					Address:     "10.0.0.1:80",
+					MaxFails:    defaultMaxFails,
+					FailTimeout: defaultFailTimeout,
 				},
 				{
-					Address: "10.0.0.2:80",
+					Address:     "10.0.0.2:80",
+					MaxFails:    defaultMaxFails,
+					FailTimeout: defaultFailTimeout,
 				},
 			},
 		},
@@ -112,7 +215,9 @@ func TestCreateUpstreams(t *testing.T) {
 			Name: "up2",
 			Servers: []http.UpstreamServer{
 				{
-					Address: "11.0.0.0:80",
+					Address:     "11.0.0.0:80",
+					MaxFails:    defaultMaxFails,
+					FailTimeout: defaultFailTimeout,
 				},
 			},
 		},
@@ -134,7 +239,7 @@ func TestCreateUpstreams(t *testing.T) {
 		},
 	}
 
-	result := createUpstreams(stateUpstreams)
+	result := createUpstreams(stateUpstreams, false, false, false)
 	if diff := cmp.Diff(expUpstreams, result); diff != "" {
 		t.Errorf("createUpstreams() mismatch (-want +got):\n%s", diff)
 	}
@@ -198,24 +303,463 @@ func TestCreateUpstream(t *testing.T) {
 				Name: "multiple-endpoints",
 				Servers: []http.UpstreamServer{
 					{
-						Address: "10.0.0.1:80",
+						Address:     "10.0.0.1:80",
+						MaxFails:    defaultMaxFails,
+						FailTimeout: defaultFailTimeout,
 					},
 					{
-						Address: "10.0.0.2:80",
+						Address:     "10.0.0.2:80",
+						MaxFails:    defaultMaxFails,
+						FailTimeout: defaultFailTimeout,
 					},
 					{
-						Address: "10.0.0.3:80",
+						Address:     "10.0.0.3:80",
+						MaxFails:    defaultMaxFails,
+						FailTimeout: defaultFailTimeout,
 					},
 				},
 			},
 			msg: "multiple endpoints",
 		},
+		{
+			stateUpstream: dataplane.Upstream{
+				Name: "hash-key",
+				Endpoints: []resolver.Endpoint{
+					{
+						Address: "10.0.0.1",
+						Port:    80,
+					},
+				},
+				Options: dataplane.UpstreamOptions{HashKey: "$cookie_session"},
+			},
+			expectedUpstream: http.Upstream{
+				Name:    "hash-key",
+				HashKey: "$cookie_session",
+				Servers: []http.UpstreamServer{
+					{
+						Address:     "10.0.0.1:80",
+						MaxFails:    defaultMaxFails,
+						FailTimeout: defaultFailTimeout,
+					},
+				},
+			},
+			msg: "hash key",
+		},
+		{
+			stateUpstream: dataplane.Upstream{
+				Name: "passive-health-checks",
+				Endpoints: []resolver.Endpoint{
+					{
+						Address: "10.0.0.1",
+						Port:    80,
+					},
+				},
+				Options: dataplane.UpstreamOptions{
+					MaxFails:    helpers.GetInt32Pointer(5),
+					FailTimeout: "1m",
+				},
+			},
+			expectedUpstream: http.Upstream{
+				Name: "passive-health-checks",
+				Servers: []http.UpstreamServer{
+					{
+						Address:     "10.0.0.1:80",
+						MaxFails:    5,
+						FailTimeout: "1m",
+					},
+				},
+			},
+			msg: "max fails and fail timeout",
+		},
+		{
+			stateUpstream: dataplane.Upstream{
+				Name: "draining-endpoints",
+				Endpoints: []resolver.Endpoint{
+					{
+						Address: "10.0.0.1",
+						Port:    80,
+					},
+				},
+				DrainingEndpoints: []resolver.Endpoint{
+					{
+						Address: "10.0.0.2",
+						Port:    80,
+					},
+				},
+			},
+			expectedUpstream: http.Upstream{
+				Name: "draining-endpoints",
+				Servers: []http.UpstreamServer{
+					{
+						Address:     "10.0.0.1:80",
+						MaxFails:    defaultMaxFails,
+						FailTimeout: defaultFailTimeout,
+					},
+					{
+						Address: "10.0.0.2:80",
+						Down:    true,
+					},
+				},
+			},
+			msg: "draining endpoints",
+		},
+		{
+			stateUpstream: dataplane.Upstream{
+				Name: "only-draining-endpoints",
+				DrainingEndpoints: []resolver.Endpoint{
+					{
+						Address: "10.0.0.2",
+						Port:    80,
+					},
+				},
+			},
+			expectedUpstream: http.Upstream{
+				Name: "only-draining-endpoints",
+				Servers: []http.UpstreamServer{
+					{
+						Address: nginx502Server,
+					},
+				},
+			},
+			msg: "only draining endpoints",
+		},
+	}
+
+	for _, test := range tests {
+		result := createUpstream(test.stateUpstream, false, false)
+		if diff := cmp.Diff(test.expectedUpstream, result); diff != "" {
+			t.Errorf("createUpstream() %q mismatch (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestCreateUpstreamResolve(t *testing.T) {
+	tests := []struct {
+		msg              string
+		stateUpstream    dataplane.Upstream
+		expectedUpstream http.Upstream
+	}{
+		{
+			stateUpstream: dataplane.Upstream{
+				Name:     "external",
+				Port:     443,
+				Hostname: "example.com",
+			},
+			expectedUpstream: http.Upstream{
+				Name:     "external",
+				ZoneSize: resolveZoneSize,
+				Servers: []http.UpstreamServer{
+					{
+						Address:     "example.com:443",
+						MaxFails:    defaultMaxFails,
+						FailTimeout: defaultFailTimeout,
+						Resolve:     true,
+					},
+				},
+			},
+			msg: "external name",
+		},
+		{
+			stateUpstream: dataplane.Upstream{
+				Name:     "external-options",
+				Port:     80,
+				Hostname: "example.com",
+				Options: dataplane.UpstreamOptions{
+					HashKey:     "$cookie_session",
+					MaxFails:    helpers.GetInt32Pointer(5),
+					FailTimeout: "1m",
+				},
+			},
+			expectedUpstream: http.Upstream{
+				Name:     "external-options",
+				HashKey:  "$cookie_session",
+				ZoneSize: resolveZoneSize,
+				Servers: []http.UpstreamServer{
+					{
+						Address:     "example.com:80",
+						MaxFails:    5,
+						FailTimeout: "1m",
+						Resolve:     true,
+					},
+				},
+			},
+			msg: "external name with options",
+		},
+		{
+			stateUpstream: dataplane.Upstream{
+				Name:      "endpoints",
+				Endpoints: []resolver.Endpoint{{Address: "10.0.0.1", Port: 80}},
+			},
+			expectedUpstream: http.Upstream{
+				Name: "endpoints",
+				Servers: []http.UpstreamServer{
+					{
+						Address:     "10.0.0.1:80",
+						MaxFails:    defaultMaxFails,
+						FailTimeout: defaultFailTimeout,
+					},
+				},
+			},
+			msg: "endpoints",
+		},
 	}
 
 	for _, test := range tests {
-		result := createUpstream(test.stateUpstream)
+		result := createUpstream(test.stateUpstream, true, false)
 		if diff := cmp.Diff(test.expectedUpstream, result); diff != "" {
 			t.Errorf("createUpstream() %q mismatch (-want +got):\n%s", test.msg, diff)
 		}
 	}
+
+	// Without resolving, an ExternalName Service doesn't have the endpoints.
+	result := createUpstream(dataplane.Upstream{Name: "external", Port: 443, Hostname: "example.com"}, false, false)
+	expected := http.Upstream{
+		Name:    "external",
+		Servers: []http.UpstreamServer{{Address: nginx502Server}},
+	}
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("createUpstream() without resolving mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCreateUpstreamSlowStart(t *testing.T) {
+	endpoints := []resolver.Endpoint{{Address: "10.0.0.1", Port: 80}}
+
+	tests := []struct {
+		msg              string
+		stateUpstream    dataplane.Upstream
+		expectedUpstream http.Upstream
+		plus             bool
+	}{
+		{
+			stateUpstream: dataplane.Upstream{
+				Name:      "slow",
+				Endpoints: endpoints,
+				Options:   dataplane.UpstreamOptions{SlowStart: "30s"},
+			},
+			plus: true,
+			expectedUpstream: http.Upstream{
+				Name:      "slow",
+				LeastConn: true,
+				Servers: []http.UpstreamServer{
+					{
+						Address:     "10.0.0.1:80",
+						MaxFails:    defaultMaxFails,
+						FailTimeout: defaultFailTimeout,
+						SlowStart:   "30s",
+					},
+				},
+			},
+			msg: "slow start with NGINX Plus",
+		},
+		{
+			stateUpstream: dataplane.Upstream{
+				Name:     "slow-external",
+				Port:     443,
+				Hostname: "example.com",
+				Options:  dataplane.UpstreamOptions{SlowStart: "1m"},
+			},
+			plus: true,
+			expectedUpstream: http.Upstream{
+				Name:      "slow-external",
+				LeastConn: true,
+				ZoneSize:  resolveZoneSize,
+				Servers: []http.UpstreamServer{
+					{
+						Address:     "example.com:443",
+						MaxFails:    defaultMaxFails,
+						FailTimeout: defaultFailTimeout,
+						SlowStart:   "1m",
+						Resolve:     true,
+					},
+				},
+			},
+			msg: "slow start of an external name with NGINX Plus",
+		},
+		{
+			stateUpstream: dataplane.Upstream{
+				Name:      "slow",
+				Endpoints: endpoints,
+				Options:   dataplane.UpstreamOptions{SlowStart: "30s"},
+			},
+			plus: false,
+			expectedUpstream: http.Upstream{
+				Name: "slow",
+				Servers: []http.UpstreamServer{
+					{
+						Address:     "10.0.0.1:80",
+						MaxFails:    defaultMaxFails,
+						FailTimeout: defaultFailTimeout,
+					},
+				},
+			},
+			msg: "slow start without NGINX Plus is ignored",
+		},
+		{
+			stateUpstream: dataplane.Upstream{
+				Name:      "slow-hash",
+				Endpoints: endpoints,
+				Options: dataplane.UpstreamOptions{
+					HashKey:   "$remote_addr",
+					SlowStart: "30s",
+				},
+			},
+			plus: true,
+			expectedUpstream: http.Upstream{
+				Name:    "slow-hash",
+				HashKey: "$remote_addr",
+				Servers: []http.UpstreamServer{
+					{
+						Address:     "10.0.0.1:80",
+						MaxFails:    defaultMaxFails,
+						FailTimeout: defaultFailTimeout,
+					},
+				},
+			},
+			msg: "slow start with a hash key is ignored",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			result := createUpstream(test.stateUpstream, true, test.plus)
+			if diff := cmp.Diff(test.expectedUpstream, result); diff != "" {
+				t.Errorf("createUpstream() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExecuteUpstreamsSlowStart(t *testing.T) {
+	conf := dataplane.Configuration{
+		Upstreams: []dataplane.Upstream{
+			{
+				Name:      "test_slow_80",
+				Endpoints: []resolver.Endpoint{{Address: "10.0.0.0", Port: 8080}},
+				Options:   dataplane.UpstreamOptions{SlowStart: "30s"},
+			},
+			{
+				Name:      "test_foo_80",
+				Endpoints: []resolver.Endpoint{{Address: "10.0.0.1", Port: 8080}},
+			},
+		},
+	}
+
+	expSubStrings := map[string]int{
+		"\n    least_conn;":      1,
+		"random two least_conn;": 2,
+		"server 10.0.0.0:8080 max_fails=3 fail_timeout=10s slow_start=30s;": 1,
+		"server 10.0.0.1:8080 max_fails=3 fail_timeout=10s;":                1,
+	}
+
+	upstreams := string(executeUpstreams(conf, false, false, true))
+	for expSubStr, expCount := range expSubStrings {
+		if expCount != strings.Count(upstreams, expSubStr) {
+			t.Errorf(
+				"executeUpstreams() did not generate upstreams with substring %q %d times. Upstreams: %v",
+				expSubStr,
+				expCount,
+				upstreams,
+			)
+		}
+	}
+
+	upstreams = string(executeUpstreams(conf, false, false, false))
+	if strings.Contains(upstreams, "slow_start") {
+		t.Errorf("executeUpstreams() generated slow_start without NGINX Plus. Upstreams: %v", upstreams)
+	}
+}
+
+func TestCreateUpstreamUnixSocket(t *testing.T) {
+	tests := []struct {
+		msg              string
+		stateUpstream    dataplane.Upstream
+		expectedUpstream http.Upstream
+	}{
+		{
+			stateUpstream: dataplane.Upstream{
+				Name:    "sidecar",
+				Options: dataplane.UpstreamOptions{UnixSocket: "/var/run/app.sock"},
+			},
+			expectedUpstream: http.Upstream{
+				Name: "sidecar",
+				Servers: []http.UpstreamServer{
+					{
+						Address:     "unix:/var/run/app.sock",
+						MaxFails:    defaultMaxFails,
+						FailTimeout: defaultFailTimeout,
+					},
+				},
+			},
+			msg: "unix socket",
+		},
+		{
+			stateUpstream: dataplane.Upstream{
+				Name:      "sidecar-endpoints",
+				Endpoints: []resolver.Endpoint{{Address: "10.0.0.1", Port: 80}},
+				Options: dataplane.UpstreamOptions{
+					UnixSocket:  "/var/run/app.sock",
+					MaxFails:    helpers.GetInt32Pointer(1),
+					FailTimeout: "5s",
+				},
+			},
+			expectedUpstream: http.Upstream{
+				Name: "sidecar-endpoints",
+				Servers: []http.UpstreamServer{
+					{
+						Address:     "unix:/var/run/app.sock",
+						MaxFails:    1,
+						FailTimeout: "5s",
+					},
+				},
+			},
+			msg: "unix socket replaces the endpoints",
+		},
+		{
+			stateUpstream: dataplane.Upstream{
+				Name:     "sidecar-external",
+				Port:     443,
+				Hostname: "example.com",
+				Options:  dataplane.UpstreamOptions{UnixSocket: "/var/run/app.sock"},
+			},
+			expectedUpstream: http.Upstream{
+				Name: "sidecar-external",
+				Servers: []http.UpstreamServer{
+					{
+						Address:     "unix:/var/run/app.sock",
+						MaxFails:    defaultMaxFails,
+						FailTimeout: defaultFailTimeout,
+					},
+				},
+			},
+			msg: "unix socket replaces the external name",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			result := createUpstream(test.stateUpstream, true, false)
+			if diff := cmp.Diff(test.expectedUpstream, result); diff != "" {
+				t.Errorf("createUpstream() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExecuteUpstreamsUnixSocket(t *testing.T) {
+	conf := dataplane.Configuration{
+		Upstreams: []dataplane.Upstream{
+			{
+				Name:    "test_sidecar_80",
+				Options: dataplane.UpstreamOptions{UnixSocket: "/var/run/app.sock"},
+			},
+		},
+	}
+
+	expSubStr := "server unix:/var/run/app.sock max_fails=3 fail_timeout=10s;"
+
+	upstreams := string(executeUpstreams(conf, false, false, false))
+	if !strings.Contains(upstreams, expSubStr) {
+		t.Errorf("executeUpstreams() did not generate upstreams with substring %q. Upstreams: %v", expSubStr, upstreams)
+	}
 }
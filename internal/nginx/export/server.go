@@ -0,0 +1,77 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	// Path is the path of the endpoint that serves the generated NGINX configuration.
+	Path = "/nginx-config"
+
+	readHeaderTimeout = 10 * time.Second
+	shutdownTimeout   = 5 * time.Second
+)
+
+// Server is an HTTP server that serves the generated NGINX configuration from a Store at Path.
+// It implements the manager.Runnable interface of the controller-runtime.
+type Server struct {
+	store  *Store
+	logger logr.Logger
+	addr   string
+}
+
+// NewServer creates a new Server that will listen on addr.
+func NewServer(addr string, store *Store, logger logr.Logger) *Server {
+	return &Server{
+		addr:   addr,
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Start starts the Server.
+// This method will block until the Server stops, which will happen after the ctx is closed.
+func (s *Server) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(Path, s.store)
+
+	srv := &http.Server{
+		Addr:              s.addr,
+		Handler:           mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		s.logger.Info("Starting the NGINX configuration export server", "address", s.addr, "path", Path)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("NGINX configuration export server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down the NGINX configuration export server: %w", err)
+	}
+
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("NGINX configuration export server failed: %w", err)
+	}
+
+	s.logger.Info("Stopped the NGINX configuration export server")
+
+	return nil
+}
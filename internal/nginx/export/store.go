@@ -0,0 +1,96 @@
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+)
+
+// Snapshot is a snapshot of the generated NGINX configuration.
+type Snapshot struct {
+	// ETag is the strong entity tag of the configuration. It is the quoted SHA-256 hash of the configuration.
+	ETag string
+	// Config is the generated NGINX configuration.
+	Config []byte
+}
+
+// Store stores the latest generated NGINX configuration and serves it over HTTP.
+// It implements http.Handler.
+//
+// The configuration and its ETag are replaced at once, so that the handler always serves a consistent snapshot,
+// even if the configuration is updated while a request is being served.
+type Store struct {
+	snapshot *Snapshot
+	lock     sync.RWMutex
+}
+
+var _ http.Handler = &Store{}
+
+// NewStore creates a new Store.
+func NewStore() *Store {
+	return &Store{}
+}
+
+// Update replaces the stored configuration with cfg. The Store doesn't copy cfg, so it must not be changed
+// after it is passed to Update.
+func (s *Store) Update(cfg []byte) {
+	sum := sha256.Sum256(cfg)
+
+	snapshot := &Snapshot{
+		ETag:   `"` + hex.EncodeToString(sum[:]) + `"`,
+		Config: cfg,
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.snapshot = snapshot
+}
+
+// Get returns the latest snapshot of the configuration. The boolean is false, if no configuration
+// has been stored yet.
+func (s *Store) Get() (Snapshot, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.snapshot == nil {
+		return Snapshot{}, false
+	}
+
+	return *s.snapshot, true
+}
+
+// ServeHTTP responds with the latest configuration and its ETag. If the If-None-Match header of the request
+// matches the ETag, it responds with 304 Not Modified.
+// Only the GET and HEAD methods are allowed.
+func (s *Store) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	snapshot, exists := s.Get()
+	if !exists {
+		http.Error(w, "NGINX configuration has not been generated yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("ETag", snapshot.ETag)
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if r.Header.Get("If-None-Match") == snapshot.ETag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	_, _ = w.Write(snapshot.Config)
+}
@@ -0,0 +1,81 @@
+package export
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestStoreServeHTTP(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	store := NewStore()
+
+	serve := func(method string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, Path, nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+
+		rec := httptest.NewRecorder()
+		store.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	// no config yet
+
+	rec := serve(http.MethodGet, nil)
+	g.Expect(rec.Code).To(Equal(http.StatusServiceUnavailable))
+
+	// first config
+
+	store.Update([]byte("config-1"))
+
+	rec = serve(http.MethodGet, nil)
+	g.Expect(rec.Code).To(Equal(http.StatusOK))
+	g.Expect(rec.Body.String()).To(Equal("config-1"))
+
+	etag1 := rec.Header().Get("ETag")
+	g.Expect(etag1).To(Equal(`"44b25fd6993edeb3b8ded5d90a7ed24479b70103fe0653e3cf5facadbfb1d1df"`))
+
+	// the same config has the same ETag
+
+	store.Update([]byte("config-1"))
+
+	rec = serve(http.MethodGet, nil)
+	g.Expect(rec.Code).To(Equal(http.StatusOK))
+	g.Expect(rec.Header().Get("ETag")).To(Equal(etag1))
+
+	rec = serve(http.MethodGet, map[string]string{"If-None-Match": etag1})
+	g.Expect(rec.Code).To(Equal(http.StatusNotModified))
+	g.Expect(rec.Body.Len()).To(BeZero())
+
+	// new config
+
+	store.Update([]byte("config-2"))
+
+	rec = serve(http.MethodGet, map[string]string{"If-None-Match": etag1})
+	g.Expect(rec.Code).To(Equal(http.StatusOK))
+	g.Expect(rec.Body.String()).To(Equal("config-2"))
+	g.Expect(rec.Header().Get("ETag")).ToNot(Equal(etag1))
+
+	// HEAD
+
+	rec = serve(http.MethodHead, nil)
+	g.Expect(rec.Code).To(Equal(http.StatusOK))
+	g.Expect(rec.Body.Len()).To(BeZero())
+	g.Expect(rec.Header().Get("ETag")).ToNot(BeEmpty())
+
+	// read-only
+
+	rec = serve(http.MethodPost, nil)
+	g.Expect(rec.Code).To(Equal(http.StatusMethodNotAllowed))
+	g.Expect(rec.Header().Get("Allow")).To(Equal("GET, HEAD"))
+
+	snapshot, exists := store.Get()
+	g.Expect(exists).To(BeTrue())
+	g.Expect(snapshot.Config).To(Equal([]byte("config-2")))
+}
@@ -0,0 +1,109 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// mainConfigKeyPrefix distinguishes the keys of the configs of the main context from the keys of the configs
+	// of the http context, which share the ConfigMap.
+	mainConfigKeyPrefix = "main-"
+	// configMapWriteTimeout limits the time of writing a config, including the retries on conflicts.
+	configMapWriteTimeout = 30 * time.Second
+)
+
+// ConfigMapManager is an implementation of Manager that writes the configs into a ConfigMap instead of
+// the file system, so that NGINX that runs in a separate pod can consume them from a mounted ConfigMap.
+// Each config is stored under the key of its file name.
+// A write is a single create or update of the ConfigMap, so the consumers see either the previous or the new
+// config, never a partially written one. The other keys of the ConfigMap are preserved.
+type ConfigMapManager struct {
+	client         client.Client
+	configMap      types.NamespacedName
+	filenameFormat string
+}
+
+// NewConfigMapManager creates a new ConfigMapManager.
+// configMap is the ConfigMap that the configs are written into. The ConfigMapManager creates it if it doesn't exist.
+// filenameFormat is the format of the keys of the configs, as for NewManagerImpl. The keys of the configs of the
+// main context are prefixed with "main-".
+func NewConfigMapManager(
+	k8sClient client.Client,
+	configMap types.NamespacedName,
+	filenameFormat string,
+) *ConfigMapManager {
+	return &ConfigMapManager{
+		client:         k8sClient,
+		configMap:      configMap,
+		filenameFormat: filenameFormat,
+	}
+}
+
+func (m *ConfigMapManager) WriteHTTPConfig(name string, cfg []byte) error {
+	return m.writeConfig(m.getKeyForConfig(name), cfg)
+}
+
+func (m *ConfigMapManager) WriteMainConfig(name string, cfg []byte) error {
+	return m.writeConfig(m.getKeyForMainConfig(name), cfg)
+}
+
+func (m *ConfigMapManager) writeConfig(key string, cfg []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), configMapWriteTimeout)
+	defer cancel()
+
+	// The update is rejected if the ConfigMap has changed since it was read, and the create is rejected
+	// if the ConfigMap has been created since, so that the concurrent changes of the other keys are not lost.
+	// In both cases, the write is retried with the current ConfigMap.
+	err := retry.OnError(retry.DefaultRetry, isConcurrentChange, func() error {
+		var cm apiv1.ConfigMap
+
+		err := m.client.Get(ctx, m.configMap, &cm)
+		if apierrors.IsNotFound(err) {
+			cm = apiv1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: m.configMap.Namespace,
+					Name:      m.configMap.Name,
+				},
+				Data: map[string]string{key: string(cfg)},
+			}
+
+			return m.client.Create(ctx, &cm)
+		}
+		if err != nil {
+			return err
+		}
+
+		if cm.Data == nil {
+			cm.Data = make(map[string]string)
+		}
+		cm.Data[key] = string(cfg)
+
+		return m.client.Update(ctx, &cm)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write config %s to ConfigMap %s: %w", key, m.configMap, err)
+	}
+
+	return nil
+}
+
+func isConcurrentChange(err error) bool {
+	return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+}
+
+func (m *ConfigMapManager) getKeyForConfig(name string) string {
+	return fmt.Sprintf(m.filenameFormat, name)
+}
+
+func (m *ConfigMapManager) getKeyForMainConfig(name string) string {
+	return mainConfigKeyPrefix + fmt.Sprintf(m.filenameFormat, name)
+}
@@ -0,0 +1,135 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiv1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// conflictingClient fails the first updates with a conflict, as if the ConfigMap was changed concurrently.
+type conflictingClient struct {
+	client.Client
+	conflicts int
+}
+
+func (c *conflictingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if c.conflicts > 0 {
+		c.conflicts--
+		return apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, obj.GetName(), errors.New("test"))
+	}
+
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+// failingClient fails all gets.
+type failingClient struct {
+	client.Client
+}
+
+func (c *failingClient) Get(context.Context, client.ObjectKey, client.Object, ...client.GetOption) error {
+	return errors.New("test")
+}
+
+func TestConfigMapManager(t *testing.T) {
+	nsname := types.NamespacedName{Namespace: "nginx-gateway", Name: "nginx-config"}
+
+	createFakeClient := func(objs ...client.Object) client.Client {
+		scheme := runtime.NewScheme()
+		if err := apiv1.AddToScheme(scheme); err != nil {
+			t.Fatalf("failed to add core types to the scheme: %v", err)
+		}
+
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	}
+
+	existing := &apiv1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: nsname.Namespace,
+			Name:      nsname.Name,
+		},
+		Data: map[string]string{
+			"nkg-http.conf": "server {}",
+			"other":         "value",
+		},
+	}
+
+	tests := []struct {
+		k8sClient func() client.Client
+		expData   map[string]string
+		msg       string
+		expErr    bool
+	}{
+		{
+			k8sClient: func() client.Client {
+				return createFakeClient()
+			},
+			expData: map[string]string{
+				"nkg-http.conf":      "upstream {}",
+				"main-nkg-main.conf": "worker_shutdown_timeout 30000ms;",
+			},
+			msg: "ConfigMap doesn't exist",
+		},
+		{
+			k8sClient: func() client.Client {
+				return createFakeClient(existing.DeepCopy())
+			},
+			expData: map[string]string{
+				"nkg-http.conf":      "upstream {}",
+				"main-nkg-main.conf": "worker_shutdown_timeout 30000ms;",
+				"other":              "value",
+			},
+			msg: "ConfigMap exists",
+		},
+		{
+			k8sClient: func() client.Client {
+				return &conflictingClient{Client: createFakeClient(existing.DeepCopy()), conflicts: 2}
+			},
+			expData: map[string]string{
+				"nkg-http.conf":      "upstream {}",
+				"main-nkg-main.conf": "worker_shutdown_timeout 30000ms;",
+				"other":              "value",
+			},
+			msg: "conflicting updates are retried",
+		},
+		{
+			k8sClient: func() client.Client {
+				return &failingClient{Client: createFakeClient(existing.DeepCopy())}
+			},
+			expErr: true,
+			msg:    "failed get",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			k8sClient := test.k8sClient()
+			mgr := NewConfigMapManager(k8sClient, nsname, "nkg-%s.conf")
+
+			err := mgr.WriteHTTPConfig("http", []byte("upstream {}"))
+			if test.expErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			err = mgr.WriteMainConfig("main", []byte("worker_shutdown_timeout 30000ms;"))
+			g.Expect(err).ToNot(HaveOccurred())
+
+			var cm apiv1.ConfigMap
+			g.Expect(k8sClient.Get(context.Background(), nsname, &cm)).To(Succeed())
+			g.Expect(cm.Data).To(Equal(test.expData))
+		})
+	}
+}
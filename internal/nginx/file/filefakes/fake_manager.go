@@ -20,6 +20,18 @@ type FakeManager struct {
 	writeHTTPConfigReturnsOnCall map[int]struct {
 		result1 error
 	}
+	WriteMainConfigStub        func(string, []byte) error
+	writeMainConfigMutex       sync.RWMutex
+	writeMainConfigArgsForCall []struct {
+		arg1 string
+		arg2 []byte
+	}
+	writeMainConfigReturns struct {
+		result1 error
+	}
+	writeMainConfigReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -91,11 +103,80 @@ func (fake *FakeManager) WriteHTTPConfigReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeManager) WriteMainConfig(arg1 string, arg2 []byte) error {
+	var arg2Copy []byte
+	if arg2 != nil {
+		arg2Copy = make([]byte, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.writeMainConfigMutex.Lock()
+	ret, specificReturn := fake.writeMainConfigReturnsOnCall[len(fake.writeMainConfigArgsForCall)]
+	fake.writeMainConfigArgsForCall = append(fake.writeMainConfigArgsForCall, struct {
+		arg1 string
+		arg2 []byte
+	}{arg1, arg2Copy})
+	stub := fake.WriteMainConfigStub
+	fakeReturns := fake.writeMainConfigReturns
+	fake.recordInvocation("WriteMainConfig", []interface{}{arg1, arg2Copy})
+	fake.writeMainConfigMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeManager) WriteMainConfigCallCount() int {
+	fake.writeMainConfigMutex.RLock()
+	defer fake.writeMainConfigMutex.RUnlock()
+	return len(fake.writeMainConfigArgsForCall)
+}
+
+func (fake *FakeManager) WriteMainConfigCalls(stub func(string, []byte) error) {
+	fake.writeMainConfigMutex.Lock()
+	defer fake.writeMainConfigMutex.Unlock()
+	fake.WriteMainConfigStub = stub
+}
+
+func (fake *FakeManager) WriteMainConfigArgsForCall(i int) (string, []byte) {
+	fake.writeMainConfigMutex.RLock()
+	defer fake.writeMainConfigMutex.RUnlock()
+	argsForCall := fake.writeMainConfigArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeManager) WriteMainConfigReturns(result1 error) {
+	fake.writeMainConfigMutex.Lock()
+	defer fake.writeMainConfigMutex.Unlock()
+	fake.WriteMainConfigStub = nil
+	fake.writeMainConfigReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) WriteMainConfigReturnsOnCall(i int, result1 error) {
+	fake.writeMainConfigMutex.Lock()
+	defer fake.writeMainConfigMutex.Unlock()
+	fake.WriteMainConfigStub = nil
+	if fake.writeMainConfigReturnsOnCall == nil {
+		fake.writeMainConfigReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.writeMainConfigReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeManager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.writeHTTPConfigMutex.RLock()
 	defer fake.writeHTTPConfigMutex.RUnlock()
+	fake.writeMainConfigMutex.RLock()
+	defer fake.writeMainConfigMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
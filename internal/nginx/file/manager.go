@@ -6,8 +6,6 @@ import (
 	"path/filepath"
 )
 
-const confdFolder = "/etc/nginx/conf.d"
-
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . Manager
 
 // Manager manages NGINX configuration files.
@@ -16,19 +14,40 @@ type Manager interface {
 	// The name distinguishes this config among all other configs. For that, it must be unique.
 	// Note that name is not the name of the corresponding configuration file.
 	WriteHTTPConfig(name string, cfg []byte) error
+	// WriteMainConfig writes the config of the main context on the file system.
+	// The name has the same meaning as for WriteHTTPConfig.
+	WriteMainConfig(name string, cfg []byte) error
 }
 
 // ManagerImpl is an implementation of Manager.
-type ManagerImpl struct{}
+type ManagerImpl struct {
+	confdFolder    string
+	mainFolder     string
+	filenameFormat string
+}
 
 // NewManagerImpl creates a new NewManagerImpl.
-func NewManagerImpl() *ManagerImpl {
-	return &ManagerImpl{}
+// confdFolder is the folder where the configuration files of the http context are written.
+// mainFolder is the folder where the configuration files of the main context are written.
+// filenameFormat is the format of the configuration file names. It must include exactly one %s verb, which is
+// replaced with the name of the config. For example, "%s.conf".
+func NewManagerImpl(confdFolder string, mainFolder string, filenameFormat string) *ManagerImpl {
+	return &ManagerImpl{
+		confdFolder:    confdFolder,
+		mainFolder:     mainFolder,
+		filenameFormat: filenameFormat,
+	}
 }
 
 func (m *ManagerImpl) WriteHTTPConfig(name string, cfg []byte) error {
-	path := getPathForConfig(name)
+	return writeConfig(m.getPathForConfig(name), cfg)
+}
 
+func (m *ManagerImpl) WriteMainConfig(name string, cfg []byte) error {
+	return writeConfig(m.getPathForMainConfig(name), cfg)
+}
+
+func writeConfig(path string, cfg []byte) error {
 	file, err := os.Create(path)
 	if err != nil {
 		return fmt.Errorf("failed to create server config %s: %w", path, err)
@@ -44,6 +63,10 @@ func (m *ManagerImpl) WriteHTTPConfig(name string, cfg []byte) error {
 	return nil
 }
 
-func getPathForConfig(name string) string {
-	return filepath.Join(confdFolder, name+".conf")
+func (m *ManagerImpl) getPathForConfig(name string) string {
+	return filepath.Join(m.confdFolder, fmt.Sprintf(m.filenameFormat, name))
+}
+
+func (m *ManagerImpl) getPathForMainConfig(name string) string {
+	return filepath.Join(m.mainFolder, fmt.Sprintf(m.filenameFormat, name))
 }
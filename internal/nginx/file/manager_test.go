@@ -1,12 +1,80 @@
 package file
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
 
 func TestGetPathForServerConfig(t *testing.T) {
-	expected := "/etc/nginx/conf.d/test.example.com.conf"
+	tests := []struct {
+		confdFolder    string
+		filenameFormat string
+		expected       string
+		msg            string
+	}{
+		{
+			confdFolder:    "/etc/nginx/conf.d",
+			filenameFormat: "%s.conf",
+			expected:       "/etc/nginx/conf.d/test.example.com.conf",
+			msg:            "default layout",
+		},
+		{
+			confdFolder:    "/opt/nginx/includes",
+			filenameFormat: "nkg-%s.include.conf",
+			expected:       "/opt/nginx/includes/nkg-test.example.com.include.conf",
+			msg:            "custom layout",
+		},
+	}
+
+	for _, test := range tests {
+		mgr := NewManagerImpl(test.confdFolder, "/etc/nginx/main.d", test.filenameFormat)
+
+		result := mgr.getPathForConfig("test.example.com")
+		if result != test.expected {
+			t.Errorf("getPathForConfig() returned %q but expected %q for case %q", result, test.expected, test.msg)
+		}
+	}
+}
+
+func TestWriteHTTPConfig(t *testing.T) {
+	confdFolder := t.TempDir()
+	cfg := []byte("server {}")
+
+	mgr := NewManagerImpl(confdFolder, t.TempDir(), "nkg-%s.conf")
+
+	err := mgr.WriteHTTPConfig("http", cfg)
+	if err != nil {
+		t.Fatalf("WriteHTTPConfig() returned unexpected error %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(confdFolder, "nkg-http.conf"))
+	if err != nil {
+		t.Fatalf("WriteHTTPConfig() didn't write the config to the configured folder: %v", err)
+	}
+
+	if string(content) != string(cfg) {
+		t.Errorf("WriteHTTPConfig() wrote %q but expected %q", content, cfg)
+	}
+}
+
+func TestWriteMainConfig(t *testing.T) {
+	mainFolder := t.TempDir()
+	cfg := []byte("worker_shutdown_timeout 30000ms;")
+
+	mgr := NewManagerImpl(t.TempDir(), mainFolder, "nkg-%s.conf")
+
+	err := mgr.WriteMainConfig("main", cfg)
+	if err != nil {
+		t.Fatalf("WriteMainConfig() returned unexpected error %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(mainFolder, "nkg-main.conf"))
+	if err != nil {
+		t.Fatalf("WriteMainConfig() didn't write the config to the main folder: %v", err)
+	}
 
-	result := getPathForConfig("test.example.com")
-	if result != expected {
-		t.Errorf("getPathForConfig() returned %q but expected %q", result, expected)
+	if string(content) != string(cfg) {
+		t.Errorf("WriteMainConfig() wrote %q but expected %q", content, cfg)
 	}
 }
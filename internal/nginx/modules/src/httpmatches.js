@@ -1,4 +1,6 @@
 const MATCHES_VARIABLE = 'http_matches';
+const COUNTRY_CODE_VARIABLE = 'geoip2_country_code';
+const CONTINENT_CODE_VARIABLE = 'geoip2_continent_code';
 const HTTP_CODES = {
   notFound: 404,
   internalServerError: 500,
@@ -125,6 +127,16 @@ function testMatch(r, match) {
     }
   }
 
+  // check the country and the continent of the client, looked up in the GeoIP2 database.
+  // Without the database, the variables are not set, so such matches are never satisfied.
+  if (match.countries && !match.countries.includes(r.variables[COUNTRY_CODE_VARIABLE])) {
+    return false;
+  }
+
+  if (match.continents && !match.continents.includes(r.variables[CONTINENT_CODE_VARIABLE])) {
+    return false;
+  }
+
   // all match conditions are satisfied so return true
   return true;
 }
@@ -146,6 +158,11 @@ function headersMatch(requestHeaders, headers) {
       return false;
     }
 
+    // The port is not a part of the host, so the Host header "example.com:8443" matches the value "example.com".
+    if (kv[0].toLowerCase() === 'host') {
+      val = stripPort(val);
+    }
+
     // split on comma because nginx uses commas to delimit multiple header values
     const values = val.split(',');
     if (!values.includes(kv[1])) {
@@ -156,6 +173,16 @@ function headersMatch(requestHeaders, headers) {
   return true;
 }
 
+function stripPort(host) {
+  const idx = host.lastIndexOf(':');
+  // The colons of an IPv6 address are enclosed in brackets, for example "[::1]:8080".
+  if (idx === -1 || idx < host.lastIndexOf(']')) {
+    return host;
+  }
+
+  return host.slice(0, idx);
+}
+
 function paramsMatch(requestParams, params) {
   for (let i = 0; i < params.length; i++) {
     let p = params[i];
@@ -205,4 +232,6 @@ export default {
   extractMatchesFromRequest,
   HTTP_CODES,
   MATCHES_VARIABLE,
+  COUNTRY_CODE_VARIABLE,
+  CONTINENT_CODE_VARIABLE,
 };
@@ -3,7 +3,14 @@ import { expect } from 'chai';
 
 // Creates a NGINX HTTP Request Object for testing.
 // See documentation for all properties available: http://nginx.org/en/docs/njs/reference.html
-function createRequest({ method = '', headers = {}, params = {}, matches = '' } = {}) {
+function createRequest({
+  method = '',
+  headers = {},
+  params = {},
+  matches = '',
+  country = '',
+  continent = '',
+} = {}) {
   let r = {
     // Test mocks
     return(statusCode) {
@@ -34,6 +41,14 @@ function createRequest({ method = '', headers = {}, params = {}, matches = '' }
     r.variables[hm.MATCHES_VARIABLE] = matches;
   }
 
+  if (country) {
+    r.variables[hm.COUNTRY_CODE_VARIABLE] = country;
+  }
+
+  if (continent) {
+    r.variables[hm.CONTINENT_CODE_VARIABLE] = continent;
+  }
+
   return r;
 }
 
@@ -134,6 +149,30 @@ describe('testMatch', () => {
       request: createRequest({ method: 'GET', headers: { header: 'value' } }), // no params set on request
       expected: false,
     },
+    {
+      name: 'returns true if the country matches',
+      match: { method: 'GET', countries: ['AT', 'DE'] },
+      request: createRequest({ method: 'GET', country: 'DE' }),
+      expected: true,
+    },
+    {
+      name: 'returns false if the country does not match',
+      match: { countries: ['AT', 'DE'] },
+      request: createRequest({ country: 'US' }),
+      expected: false,
+    },
+    {
+      name: 'returns true if the continent matches',
+      match: { continents: ['EU'] },
+      request: createRequest({ continent: 'EU' }),
+      expected: true,
+    },
+    {
+      name: 'returns false if the continent is not known',
+      match: { continents: ['EU'] },
+      request: createRequest(), // the GeoIP2 variables are not set
+      expected: false,
+    },
     {
       name: 'throws if headers are malformed',
       match: { headers: ['malformedheader'] },
@@ -260,6 +299,38 @@ describe('headersMatch', () => {
       },
       expected: true,
     },
+    {
+      name: 'returns true if the Host header has a port and the host matches',
+      headers: ['Host:example.com'],
+      requestHeaders: {
+        Host: 'example.com:8443',
+      },
+      expected: true,
+    },
+    {
+      name: 'returns true if the Host header has no port and the host matches',
+      headers: ['Host:example.com'],
+      requestHeaders: {
+        Host: 'example.com',
+      },
+      expected: true,
+    },
+    {
+      name: 'returns false if the Host header has a port and the host does not match',
+      headers: ['Host:example.com'],
+      requestHeaders: {
+        Host: 'foo.example.com:8443',
+      },
+      expected: false,
+    },
+    {
+      name: 'does not strip the port of the other headers',
+      headers: ['X-Forwarded-Host:example.com'],
+      requestHeaders: {
+        'X-Forwarded-Host': 'example.com:8443',
+      },
+      expected: false,
+    },
   ];
 
   tests.forEach((test) => {
@@ -405,6 +476,8 @@ describe('redirect', () => {
     params: ['Arg1=value1', 'arg2=value2=SOME=other=value'],
     redirectPath: '/a-match',
   };
+  const testGetMatch = { method: 'GET', redirectPath: '/get' };
+  const testPostMatch = { method: 'POST', redirectPath: '/post' };
 
   const tests = [
     {
@@ -441,6 +514,18 @@ describe('redirect', () => {
       matches: [testHeaderMatches, testQueryParamMatches, testAllMatchTypes, testAnyMatch], // request matches testAllMatchTypes and testAnyMatch. But first match should win.
       expectedRedirect: '/a-match',
     },
+    {
+      name: 'redirects GET request to the redirectPath of the GET match',
+      request: createRequest({ method: 'GET' }),
+      matches: [testGetMatch, testPostMatch],
+      expectedRedirect: '/get',
+    },
+    {
+      name: 'redirects POST request to the redirectPath of the POST match',
+      request: createRequest({ method: 'POST' }),
+      matches: [testGetMatch, testPostMatch],
+      expectedRedirect: '/post',
+    },
   ];
 
   tests.forEach((test) => {
@@ -0,0 +1,46 @@
+package reload
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// TriggerFunc reloads NGINX and returns the result of the reload. It must return when the ctx is closed.
+type TriggerFunc func(ctx context.Context) error
+
+// Handler reloads NGINX on a POST request using a TriggerFunc.
+// It implements http.Handler.
+type Handler struct {
+	trigger TriggerFunc
+}
+
+var _ http.Handler = &Handler{}
+
+// NewHandler creates a new Handler.
+func NewHandler(trigger TriggerFunc) *Handler {
+	return &Handler{
+		trigger: trigger,
+	}
+}
+
+// ServeHTTP reloads NGINX and responds with 200 OK if the reload succeeds or with 500 Internal Server Error
+// and the error otherwise.
+// Only the POST method is allowed.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := h.trigger(r.Context()); err != nil {
+		http.Error(w, fmt.Sprintf("failed to reload NGINX: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	_, _ = w.Write([]byte("NGINX was reloaded\n"))
+}
@@ -0,0 +1,58 @@
+package reload
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestHandlerServeHTTP(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var (
+		triggered  int
+		triggerErr error
+	)
+
+	handler := NewHandler(func(_ context.Context) error {
+		triggered++
+		return triggerErr
+	})
+
+	serve := func(method string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, Path, nil))
+
+		return rec
+	}
+
+	// successful reload
+
+	rec := serve(http.MethodPost)
+	g.Expect(rec.Code).To(Equal(http.StatusOK))
+	g.Expect(rec.Body.String()).To(Equal("NGINX was reloaded\n"))
+	g.Expect(triggered).To(Equal(1))
+
+	// failed reload
+
+	triggerErr = errors.New("test")
+
+	rec = serve(http.MethodPost)
+	g.Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+	g.Expect(rec.Body.String()).To(Equal("failed to reload NGINX: test\n"))
+	g.Expect(triggered).To(Equal(2))
+
+	// methods other than POST are not allowed and don't reload NGINX
+
+	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete} {
+		rec = serve(method)
+		g.Expect(rec.Code).To(Equal(http.StatusMethodNotAllowed))
+		g.Expect(rec.Header().Get("Allow")).To(Equal("POST"))
+	}
+
+	g.Expect(triggered).To(Equal(2))
+}
@@ -0,0 +1,159 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ProcessInspector inspects the running processes.
+type ProcessInspector interface {
+	// Running returns whether the process with the PID is running. Zombie processes are not running.
+	Running(pid int) (bool, error)
+	// Children returns the PIDs of the running child processes of the process with the PID.
+	Children(pid int) ([]int, error)
+}
+
+// ProcFSInspector implements ProcessInspector by reading the proc filesystem.
+// NGINX Kubernetes Gateway shares the process namespace with NGINX, so it sees the NGINX processes.
+type ProcFSInspector struct {
+	root string
+}
+
+// NewProcFSInspector creates a new ProcFSInspector.
+// root is the mount point of the proc filesystem, for example, /proc.
+func NewProcFSInspector(root string) *ProcFSInspector {
+	return &ProcFSInspector{
+		root: root,
+	}
+}
+
+// procStat holds the fields of the stat file of a process that the ProcFSInspector uses.
+type procStat struct {
+	state byte
+	ppid  int
+}
+
+func (i *ProcFSInspector) Running(pid int) (bool, error) {
+	stat, err := i.readStat(strconv.Itoa(pid))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return stat.state != 'Z', nil
+}
+
+func (i *ProcFSInspector) Children(pid int) ([]int, error) {
+	entries, err := os.ReadDir(i.root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the proc filesystem: %w", err)
+	}
+
+	var children []int
+
+	for _, e := range entries {
+		childPID, err := strconv.Atoi(e.Name())
+		if err != nil {
+			// not a process
+			continue
+		}
+
+		stat, err := i.readStat(e.Name())
+		if err != nil {
+			// the process exited after the directory was read
+			continue
+		}
+
+		if stat.ppid == pid && stat.state != 'Z' {
+			children = append(children, childPID)
+		}
+	}
+
+	return children, nil
+}
+
+func (i *ProcFSInspector) readStat(pid string) (procStat, error) {
+	content, err := os.ReadFile(filepath.Join(i.root, pid, "stat"))
+	if err != nil {
+		return procStat{}, err
+	}
+
+	return parseProcStat(string(content))
+}
+
+// parseProcStat parses the content of the stat file of a process, for example,
+// "42 (nginx) S 1 42 42 0 -1 ...". The name of the executable can include spaces and parentheses,
+// so the fields are read after its last closing parenthesis.
+func parseProcStat(content string) (procStat, error) {
+	idx := strings.LastIndexByte(content, ')')
+	if idx == -1 {
+		return procStat{}, fmt.Errorf("invalid stat content %q", content)
+	}
+
+	fields := strings.Fields(content[idx+1:])
+	if len(fields) < 2 || len(fields[0]) != 1 {
+		return procStat{}, fmt.Errorf("invalid stat content %q", content)
+	}
+
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return procStat{}, fmt.Errorf("invalid parent PID in stat content %q: %w", content, err)
+	}
+
+	return procStat{state: fields[0][0], ppid: ppid}, nil
+}
+
+// HealthChecker checks the health of NGINX. NGINX is healthy if its main process is running and has at least
+// one running child process. A reload of NGINX can succeed while the new worker processes crash, for example,
+// because of a bug in a module, which leaves NGINX unable to handle the requests.
+type HealthChecker struct {
+	inspector ProcessInspector
+	readFile  readFileFunc
+	pidFile   string
+}
+
+// NewHealthChecker creates a new HealthChecker.
+// pidFile is the path to the PID file of the NGINX main process.
+func NewHealthChecker(pidFile string, inspector ProcessInspector) *HealthChecker {
+	return &HealthChecker{
+		inspector: inspector,
+		readFile:  os.ReadFile,
+		pidFile:   pidFile,
+	}
+}
+
+// Check returns the number of the running worker processes of NGINX and an error if NGINX is not healthy.
+// The worker processes include all child processes of the main process, such as the cache manager.
+func (c *HealthChecker) Check() (int, error) {
+	pid, err := findMainProcess(c.readFile, c.pidFile)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find NGINX main process: %w", err)
+	}
+
+	running, err := c.inspector.Running(pid)
+	if err != nil {
+		return 0, fmt.Errorf("failed to inspect NGINX main process: %w", err)
+	}
+
+	if !running {
+		return 0, fmt.Errorf("NGINX main process %d is not running", pid)
+	}
+
+	workers, err := c.inspector.Children(pid)
+	if err != nil {
+		return 0, fmt.Errorf("failed to inspect NGINX worker processes: %w", err)
+	}
+
+	if len(workers) == 0 {
+		return 0, fmt.Errorf("NGINX main process %d has no running worker processes", pid)
+	}
+
+	return len(workers), nil
+}
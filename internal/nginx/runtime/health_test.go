@@ -0,0 +1,215 @@
+package runtime
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeProcessInspector is a ProcessInspector with the processes and their parent PIDs in memory.
+type fakeProcessInspector struct {
+	err     error
+	parents map[int]int
+}
+
+func (i *fakeProcessInspector) Running(pid int) (bool, error) {
+	if i.err != nil {
+		return false, i.err
+	}
+
+	_, exists := i.parents[pid]
+	return exists, nil
+}
+
+func (i *fakeProcessInspector) Children(pid int) ([]int, error) {
+	if i.err != nil {
+		return nil, i.err
+	}
+
+	var children []int
+	for child, parent := range i.parents {
+		if parent == pid {
+			children = append(children, child)
+		}
+	}
+
+	return children, nil
+}
+
+func TestHealthCheckerCheck(t *testing.T) {
+	readPIDFile := func(string) ([]byte, error) {
+		return []byte("10\n"), nil
+	}
+	readFileError := func(string) ([]byte, error) {
+		return nil, errors.New("error")
+	}
+
+	tests := []struct {
+		inspector   *fakeProcessInspector
+		readFile    readFileFunc
+		msg         string
+		expWorkers  int
+		expectError bool
+	}{
+		{
+			inspector:  &fakeProcessInspector{parents: map[int]int{1: 0, 10: 1, 11: 10, 12: 10}},
+			readFile:   readPIDFile,
+			expWorkers: 2,
+			msg:        "healthy",
+		},
+		{
+			inspector:   &fakeProcessInspector{parents: map[int]int{1: 0, 10: 1}},
+			readFile:    readPIDFile,
+			expectError: true,
+			msg:         "workers crashed",
+		},
+		{
+			inspector:   &fakeProcessInspector{parents: map[int]int{1: 0, 11: 1}},
+			readFile:    readPIDFile,
+			expectError: true,
+			msg:         "main process not running",
+		},
+		{
+			inspector:   &fakeProcessInspector{err: errors.New("error")},
+			readFile:    readPIDFile,
+			expectError: true,
+			msg:         "inspector error",
+		},
+		{
+			inspector:   &fakeProcessInspector{},
+			readFile:    readFileError,
+			expectError: true,
+			msg:         "cannot read PID file",
+		},
+	}
+
+	for _, test := range tests {
+		checker := NewHealthChecker(pidFile, test.inspector)
+		checker.readFile = test.readFile
+
+		workers, err := checker.Check()
+
+		if workers != test.expWorkers {
+			t.Errorf("Check() returned %d workers but expected %d for case %q", workers, test.expWorkers, test.msg)
+		}
+
+		if test.expectError {
+			if err == nil {
+				t.Errorf("Check() didn't return error for case %q", test.msg)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("Check() returned unexpected error %v for case %q", err, test.msg)
+			}
+		}
+	}
+}
+
+func TestParseProcStat(t *testing.T) {
+	tests := []struct {
+		content     string
+		msg         string
+		expected    procStat
+		expectError bool
+	}{
+		{
+			content:  "42 (nginx) S 1 42 42 0 -1 4194560",
+			expected: procStat{state: 'S', ppid: 1},
+			msg:      "normal case",
+		},
+		{
+			content:  "43 (nginx: worker (1)) Z 42 42 42 0 -1 4194560",
+			expected: procStat{state: 'Z', ppid: 42},
+			msg:      "executable name with spaces and parentheses",
+		},
+		{
+			content:     "42 nginx S 1",
+			expectError: true,
+			msg:         "no parentheses",
+		},
+		{
+			content:     "42 (nginx) S",
+			expectError: true,
+			msg:         "missing parent PID",
+		},
+		{
+			content:     "42 (nginx) S parent",
+			expectError: true,
+			msg:         "invalid parent PID",
+		},
+	}
+
+	for _, test := range tests {
+		result, err := parseProcStat(test.content)
+
+		if test.expectError {
+			if err == nil {
+				t.Errorf("parseProcStat() didn't return error for case %q", test.msg)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("parseProcStat() returned unexpected error %v for case %q", err, test.msg)
+		}
+
+		if result != test.expected {
+			t.Errorf("parseProcStat() returned %+v but expected %+v for case %q", result, test.expected, test.msg)
+		}
+	}
+}
+
+func TestProcFSInspector(t *testing.T) {
+	root := t.TempDir()
+
+	writeStat := func(pid string, content string) {
+		err := os.MkdirAll(filepath.Join(root, pid), 0o755)
+		if err != nil {
+			t.Fatalf("failed to create the process dir: %v", err)
+		}
+
+		err = os.WriteFile(filepath.Join(root, pid, "stat"), []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("failed to write the stat file: %v", err)
+		}
+	}
+
+	writeStat("10", "10 (nginx) S 1 10 10 0 -1")
+	writeStat("11", "11 (nginx) S 10 10 10 0 -1")
+	writeStat("12", "12 (nginx) R 10 10 10 0 -1")
+	writeStat("13", "13 (nginx) Z 10 10 10 0 -1")
+	writeStat("14", "14 (sh) S 1 14 14 0 -1")
+
+	err := os.MkdirAll(filepath.Join(root, "self"), 0o755)
+	if err != nil {
+		t.Fatalf("failed to create the self dir: %v", err)
+	}
+
+	inspector := NewProcFSInspector(root)
+
+	children, err := inspector.Children(10)
+	if err != nil {
+		t.Fatalf("Children() returned unexpected error %v", err)
+	}
+
+	sort.Ints(children)
+
+	if diff := cmp.Diff([]int{11, 12}, children); diff != "" {
+		t.Errorf("Children() mismatch (-want +got):\n%s", diff)
+	}
+
+	for pid, expected := range map[int]bool{10: true, 13: false, 99: false} {
+		running, err := inspector.Running(pid)
+		if err != nil {
+			t.Errorf("Running() returned unexpected error %v for PID %d", err, pid)
+		}
+
+		if running != expected {
+			t.Errorf("Running() returned %t but expected %t for PID %d", running, expected, pid)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package runtime
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -10,24 +11,35 @@ import (
 	"time"
 )
 
-const pidFile = "/etc/nginx/nginx.pid"
+// quitPollInterval is the interval at which Quit checks whether the NGINX main process has exited.
+const quitPollInterval = 100 * time.Millisecond
 
 type readFileFunc func(string) ([]byte, error)
 
+type killFunc func(pid int, sig syscall.Signal) error
+
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . Manager
 
 // Manager manages the runtime of NGINX.
 type Manager interface {
 	// Reload reloads NGINX configuration. It is a blocking operation.
 	Reload(ctx context.Context) error
+	// Quit gracefully shuts down NGINX and waits until NGINX exits or the timeout expires.
+	// It is a blocking operation.
+	Quit(ctx context.Context, timeout time.Duration) error
 }
 
 // ManagerImpl implements Manager.
-type ManagerImpl struct{}
+type ManagerImpl struct {
+	pidFile string
+}
 
 // NewManagerImpl creates a new ManagerImpl.
-func NewManagerImpl() *ManagerImpl {
-	return &ManagerImpl{}
+// pidFile is the path to the PID file of the NGINX main process.
+func NewManagerImpl(pidFile string) *ManagerImpl {
+	return &ManagerImpl{
+		pidFile: pidFile,
+	}
 }
 
 func (m *ManagerImpl) Reload(ctx context.Context) error {
@@ -37,7 +49,7 @@ func (m *ManagerImpl) Reload(ctx context.Context) error {
 	// Make sure to prevent this case, so we don't get an error.
 
 	// We find the main NGINX PID on every reload because it will change if the NGINX container is restarted.
-	pid, err := findMainProcess(os.ReadFile)
+	pid, err := findMainProcess(os.ReadFile, m.pidFile)
 	if err != nil {
 		return fmt.Errorf("failed to find NGINX main process: %w", err)
 	}
@@ -65,7 +77,49 @@ func (m *ManagerImpl) Reload(ctx context.Context) error {
 	return nil
 }
 
-func findMainProcess(readFile readFileFunc) (int, error) {
+func (m *ManagerImpl) Quit(ctx context.Context, timeout time.Duration) error {
+	pid, err := findMainProcess(os.ReadFile, m.pidFile)
+	if err != nil {
+		return fmt.Errorf("failed to find NGINX main process: %w", err)
+	}
+
+	return quit(ctx, syscall.Kill, pid, timeout, quitPollInterval)
+}
+
+// quit sends the QUIT signal to the NGINX main process, so that the workers stop accepting new connections
+// and complete the in-flight requests, and waits until the main process exits or the timeout expires.
+// See https://nginx.org/en/docs/control.html
+func quit(ctx context.Context, kill killFunc, pid int, timeout time.Duration, pollInterval time.Duration) error {
+	err := kill(pid, syscall.SIGQUIT)
+	if err != nil {
+		if errors.Is(err, syscall.ESRCH) {
+			// NGINX has already exited
+			return nil
+		}
+		return fmt.Errorf("failed to send the QUIT signal to NGINX main: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// the signal 0 is not sent; it only checks whether the process exists
+		if err := kill(pid, 0); errors.Is(err, syscall.ESRCH) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("NGINX main process didn't exit within %v", timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+func findMainProcess(readFile readFileFunc, pidFile string) (int, error) {
 	content, err := readFile(pidFile)
 	if err != nil {
 		return 0, err
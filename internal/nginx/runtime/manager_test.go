@@ -1,10 +1,15 @@
 package runtime
 
 import (
+	"context"
 	"errors"
+	"syscall"
 	"testing"
+	"time"
 )
 
+const pidFile = "/etc/nginx/nginx.pid"
+
 func TestFindMainProcess(t *testing.T) {
 	readFileFuncGen := func(content []byte) readFileFunc {
 		return func(name string) ([]byte, error) {
@@ -51,7 +56,7 @@ func TestFindMainProcess(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		result, err := findMainProcess(test.readFile)
+		result, err := findMainProcess(test.readFile, pidFile)
 
 		if result != test.expected {
 			t.Errorf("findMainProcess() returned %d but expected %d for case %q", result, test.expected, test.msg)
@@ -68,3 +73,94 @@ func TestFindMainProcess(t *testing.T) {
 		}
 	}
 }
+
+func TestQuit(t *testing.T) {
+	const pid = 1
+
+	// fakeKill simulates an NGINX main process that exits after it is checked the number of times.
+	fakeKill := func(checksBeforeExit int, quitErr error) (killFunc, *[]syscall.Signal) {
+		var signals []syscall.Signal
+
+		return func(p int, sig syscall.Signal) error {
+			if p != pid {
+				return errors.New("unexpected pid")
+			}
+
+			signals = append(signals, sig)
+
+			if sig == syscall.SIGQUIT {
+				return quitErr
+			}
+
+			if len(signals)-1 > checksBeforeExit {
+				return syscall.ESRCH
+			}
+
+			return nil
+		}, &signals
+	}
+
+	tests := []struct {
+		quitErr          error
+		msg              string
+		checksBeforeExit int
+		timeout          time.Duration
+		expSignals       int
+		expectError      bool
+	}{
+		{
+			checksBeforeExit: 0,
+			timeout:          time.Second,
+			expSignals:       2,
+			msg:              "exits right away",
+		},
+		{
+			checksBeforeExit: 3,
+			timeout:          time.Second,
+			expSignals:       5,
+			msg:              "exits after in-flight requests complete",
+		},
+		{
+			checksBeforeExit: 1000,
+			timeout:          10 * time.Millisecond,
+			expectError:      true,
+			msg:              "doesn't exit within timeout",
+		},
+		{
+			quitErr:    syscall.ESRCH,
+			timeout:    time.Second,
+			expSignals: 1,
+			msg:        "already exited",
+		},
+		{
+			quitErr:     syscall.EPERM,
+			timeout:     time.Second,
+			expSignals:  1,
+			expectError: true,
+			msg:         "cannot send quit signal",
+		},
+	}
+
+	for _, test := range tests {
+		kill, signals := fakeKill(test.checksBeforeExit, test.quitErr)
+
+		err := quit(context.Background(), kill, pid, test.timeout, time.Millisecond)
+
+		if test.expectError {
+			if err == nil {
+				t.Errorf("quit() didn't return error for case %q", test.msg)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("quit() returned unexpected error %v for case %q", err, test.msg)
+			}
+			if len(*signals) != test.expSignals {
+				t.Errorf("quit() sent %d signals but expected %d for case %q", len(*signals), test.expSignals, test.msg)
+			}
+		}
+
+		if (*signals)[0] != syscall.SIGQUIT {
+			t.Errorf("quit() sent %v first but expected the QUIT signal for case %q", (*signals)[0], test.msg)
+		}
+	}
+}
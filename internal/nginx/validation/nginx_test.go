@@ -0,0 +1,143 @@
+//go:build nginx
+
+package validation_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/config"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/validation"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/resolver/resolverfakes"
+)
+
+// getEnv returns the value of the environment variable or the default value if the variable is not set.
+func getEnv(name, defaultValue string) string {
+	if v, exists := os.LookupEnv(name); exists {
+		return v
+	}
+
+	return defaultValue
+}
+
+// TestValidateGeneratedConfig renders the configuration for a set of resources and validates it with the nginx
+// binary. It requires an NGINX with the JavaScript module. Run it with:
+//
+//	go test -tags nginx ./internal/nginx/validation/
+//
+// The NGINX_BINARY and NGINX_JS_MODULE environment variables override the paths to the nginx binary and
+// the JavaScript module.
+func TestValidateGeneratedConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	const gcName = "nginx"
+
+	store := graph.ClusterStore{
+		GatewayClass: &v1beta1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: gcName},
+			Spec: v1beta1.GatewayClassSpec{
+				ControllerName: "test.example.com/gateway",
+			},
+		},
+		Gateways: map[types.NamespacedName]*v1beta1.Gateway{
+			{Namespace: "test", Name: "gateway"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "gateway"},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: gcName,
+					Listeners: []v1beta1.Listener{
+						{
+							Name:     "http",
+							Port:     80,
+							Protocol: v1beta1.HTTPProtocolType,
+						},
+					},
+				},
+			},
+		},
+		HTTPRoutes: map[types.NamespacedName]*v1beta1.HTTPRoute{
+			{Namespace: "test", Name: "hr"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "hr"},
+				Spec: v1beta1.HTTPRouteSpec{
+					CommonRouteSpec: v1beta1.CommonRouteSpec{
+						ParentRefs: []v1beta1.ParentReference{
+							{
+								Name:        "gateway",
+								SectionName: (*v1beta1.SectionName)(helpers.GetStringPointer("http")),
+							},
+						},
+					},
+					Hostnames: []v1beta1.Hostname{"example.com"},
+					Rules: []v1beta1.HTTPRouteRule{
+						{
+							Matches: []v1beta1.HTTPRouteMatch{
+								{
+									Path: &v1beta1.HTTPPathMatch{
+										Type:  (*v1beta1.PathMatchType)(helpers.GetStringPointer("PathPrefix")),
+										Value: helpers.GetStringPointer("/coffee"),
+									},
+									Headers: []v1beta1.HTTPHeaderMatch{
+										{
+											Type:  (*v1beta1.HeaderMatchType)(helpers.GetStringPointer("Exact")),
+											Name:  "version",
+											Value: "v2",
+										},
+									},
+								},
+							},
+							BackendRefs: []v1beta1.HTTPBackendRef{
+								{
+									BackendRef: v1beta1.BackendRef{
+										BackendObjectReference: v1beta1.BackendObjectReference{
+											Kind: (*v1beta1.Kind)(helpers.GetStringPointer("Service")),
+											Name: "coffee",
+											Port: (*v1beta1.PortNumber)(helpers.GetInt32Pointer(80)),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Services: map[types.NamespacedName]*v1.Service{
+			{Namespace: "test", Name: "coffee"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "coffee"},
+			},
+		},
+	}
+
+	gr := graph.BuildGraph(store, "test.example.com/gateway", gcName, nil, 0, nil, nil, false)
+	conf, _ := dataplane.BuildConfiguration(context.Background(), gr, &resolverfakes.FakeServiceResolver{})
+
+	generated := config.NewGeneratorImpl(config.GeneratorConfig{
+		AccessLog:        "off",
+		ErrorLog:         "stderr",
+		ErrorLogLevel:    "info",
+		MergeSlashes:     true,
+		AbsoluteRedirect: true,
+	}).Generate(conf)
+
+	httpMatchesModulePath, err := filepath.Abs("../modules/src/httpmatches.js")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	validator := validation.NewValidator(validation.ValidatorConfig{
+		Runner:                validation.NewExecRunner(getEnv("NGINX_BINARY", "nginx")),
+		Dir:                   t.TempDir(),
+		JSModulePath:          getEnv("NGINX_JS_MODULE", "/usr/lib/nginx/modules/ngx_http_js_module.so"),
+		HTTPMatchesModulePath: httpMatchesModulePath,
+	})
+
+	g.Expect(validator.Validate(context.Background(), generated)).To(Succeed())
+}
@@ -0,0 +1,133 @@
+package validation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	gotemplate "text/template"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/file"
+)
+
+const (
+	// confdFolder is the folder under the directory of the Validator that holds the generated configuration files.
+	confdFolder = "conf.d"
+	// mainFolder is the folder under the directory of the Validator that holds the main context configuration files.
+	mainFolder = "main.d"
+	// mainConfigFile is the file under the directory of the Validator that holds the main NGINX configuration.
+	mainConfigFile = "nginx.conf"
+	// configName is the name of the generated http config, which the file manager turns into the file name.
+	configName = "http"
+)
+
+// mainConfigTemplateText is the main NGINX configuration that includes the generated configuration.
+// It mirrors the main configuration that the nginx-config-initializer container of the deployment creates, with
+// the pid directive that NKG renders into the main.d folder.
+var mainConfigTemplateText = `load_module {{ .JSModulePath }};
+events {}
+pid {{ .Dir }}/nginx.pid;
+include {{ .Dir }}/main.d/*.conf;
+error_log stderr;
+http {
+    include {{ .Dir }}/conf.d/*.conf;
+    js_import {{ .HTTPMatchesModulePath }};
+}
+`
+
+var mainConfigTemplate = gotemplate.Must(gotemplate.New("main").Parse(mainConfigTemplateText))
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . Runner
+
+// Runner runs the nginx binary.
+type Runner interface {
+	// Run runs the nginx binary with the arguments and returns its combined output.
+	Run(ctx context.Context, args ...string) ([]byte, error)
+}
+
+// ExecRunner runs the nginx binary as a separate process.
+type ExecRunner struct {
+	path string
+}
+
+// NewExecRunner creates a new ExecRunner.
+// path is the path to the nginx binary.
+func NewExecRunner(path string) *ExecRunner {
+	return &ExecRunner{
+		path: path,
+	}
+}
+
+func (r *ExecRunner) Run(ctx context.Context, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, r.path, args...).CombinedOutput()
+}
+
+// ValidatorConfig holds configuration parameters for the Validator.
+type ValidatorConfig struct {
+	// Runner runs the nginx binary.
+	Runner Runner
+	// Dir is the directory where the Validator writes the main and the generated configuration. It must exist.
+	Dir string
+	// JSModulePath is the path to the NGINX JavaScript module, which the generated configuration requires.
+	JSModulePath string
+	// HTTPMatchesModulePath is the path to the httpmatches njs module, which the generated configuration uses.
+	HTTPMatchesModulePath string
+}
+
+// Validator validates the generated NGINX configuration by running nginx -t against it, so that invalid
+// configuration can be caught, for example, in CI, before NGINX fails to reload it.
+type Validator struct {
+	cfg     ValidatorConfig
+	fileMgr file.Manager
+}
+
+// NewValidator creates a new Validator.
+func NewValidator(cfg ValidatorConfig) *Validator {
+	return &Validator{
+		cfg: cfg,
+		// the generated configuration is written the same way NKG writes it for NGINX
+		fileMgr: file.NewManagerImpl(
+			filepath.Join(cfg.Dir, confdFolder),
+			filepath.Join(cfg.Dir, mainFolder),
+			"%s.conf",
+		),
+	}
+}
+
+// Validate writes the generated configuration along with the main configuration to the directory of the Validator
+// and runs nginx -t against them. If NGINX reports the configuration as invalid, the returned error includes
+// the output of NGINX.
+func (v *Validator) Validate(ctx context.Context, generated []byte) error {
+	err := os.MkdirAll(filepath.Join(v.cfg.Dir, confdFolder), 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create the conf.d folder: %w", err)
+	}
+
+	var mainConfig bytes.Buffer
+
+	err = mainConfigTemplate.Execute(&mainConfig, v.cfg)
+	if err != nil {
+		return fmt.Errorf("failed to execute the main config template: %w", err)
+	}
+
+	mainConfigPath := filepath.Join(v.cfg.Dir, mainConfigFile)
+
+	err = os.WriteFile(mainConfigPath, mainConfig.Bytes(), 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write the main config %s: %w", mainConfigPath, err)
+	}
+
+	err = v.fileMgr.WriteHTTPConfig(configName, generated)
+	if err != nil {
+		return err
+	}
+
+	output, err := v.cfg.Runner.Run(ctx, "-t", "-q", "-c", mainConfigPath)
+	if err != nil {
+		return fmt.Errorf("invalid NGINX configuration: %w: %s", err, bytes.TrimSpace(output))
+	}
+
+	return nil
+}
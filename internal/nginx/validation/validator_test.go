@@ -0,0 +1,68 @@
+package validation_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/validation"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/nginx/validation/validationfakes"
+)
+
+func TestValidate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	dir := t.TempDir()
+	runner := &validationfakes.FakeRunner{}
+
+	validator := validation.NewValidator(validation.ValidatorConfig{
+		Runner:                runner,
+		Dir:                   dir,
+		JSModulePath:          "/modules/ngx_http_js_module.so",
+		HTTPMatchesModulePath: "/modules/njs/httpmatches.js",
+	})
+
+	generated := []byte("server { listen 80; }\n")
+
+	err := validator.Validate(context.Background(), generated)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(runner.RunCallCount()).To(Equal(1))
+	_, args := runner.RunArgsForCall(0)
+	g.Expect(args).To(Equal([]string{"-t", "-q", "-c", filepath.Join(dir, "nginx.conf")}))
+
+	mainConfig, err := os.ReadFile(filepath.Join(dir, "nginx.conf"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(mainConfig)).To(ContainSubstring("load_module /modules/ngx_http_js_module.so;"))
+	g.Expect(string(mainConfig)).To(ContainSubstring("include " + filepath.Join(dir, "conf.d") + "/*.conf;"))
+	g.Expect(string(mainConfig)).To(ContainSubstring("js_import /modules/njs/httpmatches.js;"))
+
+	written, err := os.ReadFile(filepath.Join(dir, "conf.d", "http.conf"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(written).To(Equal(generated))
+}
+
+func TestValidateInvalidConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	runner := &validationfakes.FakeRunner{}
+	runner.RunReturns(
+		[]byte("nginx: [emerg] unknown directive \"foo\"\n"),
+		errors.New("exit status 1"),
+	)
+
+	validator := validation.NewValidator(validation.ValidatorConfig{
+		Runner: runner,
+		Dir:    t.TempDir(),
+	})
+
+	err := validator.Validate(context.Background(), []byte("foo;"))
+	g.Expect(err).To(MatchError(
+		"invalid NGINX configuration: exit status 1: nginx: [emerg] unknown directive \"foo\"",
+	))
+	g.Expect(runner.RunCallCount()).To(Equal(1))
+}
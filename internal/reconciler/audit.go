@@ -0,0 +1,110 @@
+package reconciler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . AuditLogger
+
+// AuditDecision is the decision that the reconciler made about a resource.
+type AuditDecision string
+
+const (
+	// AuditDecisionUpserted means the resource was accepted and sent to the event loop as upserted.
+	AuditDecisionUpserted AuditDecision = "Upserted"
+	// AuditDecisionDeleted means the resource doesn't exist and was sent to the event loop as deleted.
+	AuditDecisionDeleted AuditDecision = "Deleted"
+	// AuditDecisionRejected means the resource failed the webhook validation and was sent to the event loop
+	// as deleted.
+	AuditDecisionRejected AuditDecision = "Rejected"
+	// AuditDecisionFiltered means the resource was filtered out or ignored. If the reconciler got the resource,
+	// it was sent to the event loop as deleted.
+	AuditDecisionFiltered AuditDecision = "Filtered"
+	// AuditDecisionCoalesced means the resource was found in the same state as the previous reconciliation that sent
+	// it to the event loop within the coalescing window, so it was not sent again.
+	AuditDecisionCoalesced AuditDecision = "Coalesced"
+)
+
+// AuditRecord is a record of the decision that the reconciler made about a resource.
+type AuditRecord struct {
+	// Time is the time of the decision.
+	Time time.Time `json:"time"`
+	// Kind is the kind of the resource.
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the resource. It is empty for cluster-scoped resources.
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the resource.
+	Name string `json:"name"`
+	// Decision is the decision about the resource.
+	Decision AuditDecision `json:"decision"`
+	// Reason explains the decision. It is empty for the upserted and the deleted resources.
+	Reason string `json:"reason,omitempty"`
+}
+
+func newAuditRecord(
+	kind string,
+	nsname types.NamespacedName,
+	decision AuditDecision,
+	reason string,
+) AuditRecord {
+	return AuditRecord{
+		Time:      time.Now(),
+		Kind:      kind,
+		Namespace: nsname.Namespace,
+		Name:      nsname.Name,
+		Decision:  decision,
+		Reason:    reason,
+	}
+}
+
+// AuditLogger logs the decisions that the reconciler makes about resources.
+type AuditLogger interface {
+	// Log logs the record.
+	Log(record AuditRecord)
+}
+
+// JSONAuditLogger is an AuditLogger that writes each record to a writer as a JSON object on a separate line.
+// JSONAuditLogger is safe for concurrent use.
+type JSONAuditLogger struct {
+	writer io.Writer
+	// logger logs the errors of writing the records, which the reconciler can't handle.
+	logger logr.Logger
+	lock   sync.Mutex
+}
+
+var _ AuditLogger = &JSONAuditLogger{}
+
+// NewJSONAuditLogger creates a new JSONAuditLogger that writes the records to writer. The errors of writing
+// the records are logged with logger.
+func NewJSONAuditLogger(writer io.Writer, logger logr.Logger) *JSONAuditLogger {
+	return &JSONAuditLogger{
+		writer: writer,
+		logger: logger,
+	}
+}
+
+// Log writes the record.
+func (l *JSONAuditLogger) Log(record AuditRecord) {
+	b, err := json.Marshal(record)
+	if err != nil {
+		// panic is safe here because we should never fail to marshal the record unless we constructed it incorrectly.
+		panic(fmt.Errorf("could not marshal audit record: %w", err))
+	}
+
+	b = append(b, '\n')
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if _, err := l.writer.Write(b); err != nil {
+		l.logger.Error(err, "Failed to write the audit record", "kind", record.Kind, "namespace",
+			record.Namespace, "name", record.Name, "decision", record.Decision)
+	}
+}
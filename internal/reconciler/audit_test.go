@@ -0,0 +1,57 @@
+package reconciler
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+)
+
+// failingWriter fails all writes.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("test")
+}
+
+func TestJSONAuditLogger(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var buf bytes.Buffer
+
+	logger := NewJSONAuditLogger(&buf, logr.Discard())
+
+	logger.Log(AuditRecord{
+		Time:      time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC),
+		Kind:      "HTTPRoute",
+		Namespace: "test",
+		Name:      "hr",
+		Decision:  AuditDecisionRejected,
+		Reason:    "validation error: test",
+	})
+	logger.Log(AuditRecord{
+		Time:     time.Date(2023, 4, 1, 12, 0, 1, 0, time.UTC),
+		Kind:     "GatewayClass",
+		Name:     "nginx",
+		Decision: AuditDecisionUpserted,
+	})
+
+	expected := `{"time":"2023-04-01T12:00:00Z","kind":"HTTPRoute","namespace":"test","name":"hr",` +
+		`"decision":"Rejected","reason":"validation error: test"}` + "\n" +
+		`{"time":"2023-04-01T12:00:01Z","kind":"GatewayClass","name":"nginx","decision":"Upserted"}` + "\n"
+
+	g.Expect(buf.String()).To(Equal(expected))
+}
+
+func TestJSONAuditLoggerWriteError(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger := NewJSONAuditLogger(failingWriter{}, logr.Discard())
+
+	g.Expect(func() {
+		logger.Log(AuditRecord{Kind: "HTTPRoute", Name: "hr", Decision: AuditDecisionDeleted})
+	}).ToNot(Panic())
+}
@@ -0,0 +1,146 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// cacheKey identifies a cached result by the type and the namespaced name of the resource.
+type cacheKey struct {
+	objType reflect.Type
+	nsname  types.NamespacedName
+}
+
+// cacheEntry is a cached result of a Get: either a copy of the resource or a NotFound error.
+type cacheEntry struct {
+	obj     client.Object
+	err     error
+	expires time.Time
+}
+
+// CachingGetter is a Getter that caches the results of another Getter for a TTL, so that the repeated gets of
+// the same resource don't reach the other Getter until the TTL expires. NotFound results are cached too,
+// for a separate, usually shorter, TTL. Other errors are not cached.
+// Because the cached results can be stale for up to the TTL, the CachingGetter is meant for the expensive
+// lookups that tolerate stale results, not for getting the resources that a reconciliation is triggered for.
+// The GetOptions are passed to the other Getter, but are not a part of the cache key.
+// CachingGetter is safe for concurrent use.
+type CachingGetter struct {
+	getter      Getter
+	entries     map[cacheKey]cacheEntry
+	lastSweep   time.Time
+	now         func() time.Time
+	ttl         time.Duration
+	notFoundTTL time.Duration
+	lock        sync.Mutex
+}
+
+var _ Getter = &CachingGetter{}
+
+// NewCachingGetter creates a new CachingGetter that caches the results of getter.
+// ttl is the TTL of the found resources, and notFoundTTL is the TTL of the NotFound results.
+// A TTL of 0 disables caching of the corresponding results.
+func NewCachingGetter(getter Getter, ttl, notFoundTTL time.Duration) *CachingGetter {
+	return &CachingGetter{
+		getter:      getter,
+		entries:     make(map[cacheKey]cacheEntry),
+		now:         time.Now,
+		ttl:         ttl,
+		notFoundTTL: notFoundTTL,
+	}
+}
+
+// Get copies the cached resource of the type of obj with the namespaced name key into obj or returns the cached
+// NotFound error. If there is no unexpired result in the cache, it gets the resource from the other Getter and
+// caches the result.
+func (g *CachingGetter) Get(
+	ctx context.Context,
+	key client.ObjectKey,
+	obj client.Object,
+	opts ...client.GetOption,
+) error {
+	dst := reflect.ValueOf(obj)
+	if dst.Kind() != reflect.Pointer || dst.IsNil() {
+		return fmt.Errorf("obj must be a non-nil pointer, got %T", obj)
+	}
+
+	ck := cacheKey{
+		objType: reflect.TypeOf(obj),
+		nsname:  key,
+	}
+
+	if entry, exists := g.getEntry(ck); exists {
+		if entry.err != nil {
+			return entry.err
+		}
+
+		dst.Elem().Set(reflect.ValueOf(entry.obj.DeepCopyObject()).Elem())
+		return nil
+	}
+
+	err := g.getter.Get(ctx, key, obj, opts...)
+
+	switch {
+	case err == nil:
+		g.setEntry(ck, cacheEntry{obj: obj.DeepCopyObject().(client.Object)}, g.ttl)
+	case apierrors.IsNotFound(err):
+		g.setEntry(ck, cacheEntry{err: err}, g.notFoundTTL)
+	}
+
+	return err
+}
+
+func (g *CachingGetter) getEntry(ck cacheKey) (cacheEntry, bool) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	entry, exists := g.entries[ck]
+	if !exists {
+		return cacheEntry{}, false
+	}
+
+	if !g.now().Before(entry.expires) {
+		delete(g.entries, ck)
+		return cacheEntry{}, false
+	}
+
+	return entry, true
+}
+
+// setEntry caches the entry for the TTL.
+// It also removes the expired entries, at most once per the longer of the TTLs, so that the entries of
+// the resources that are not read again don't accumulate.
+func (g *CachingGetter) setEntry(ck cacheKey, entry cacheEntry, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	now := g.now()
+
+	sweepInterval := g.ttl
+	if g.notFoundTTL > sweepInterval {
+		sweepInterval = g.notFoundTTL
+	}
+
+	if now.Sub(g.lastSweep) >= sweepInterval {
+		for key, e := range g.entries {
+			if !now.Before(e.expires) {
+				delete(g.entries, key)
+			}
+		}
+		g.lastSweep = now
+	}
+
+	entry.expires = now.Add(ttl)
+	g.entries[ck] = entry
+}
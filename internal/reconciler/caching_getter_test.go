@@ -0,0 +1,205 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	apiv1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// countingGetter gets the Services from a map and counts the gets. Other types are not found.
+type countingGetter struct {
+	services map[types.NamespacedName]*apiv1.Service
+	err      error
+	gets     int
+}
+
+func (g *countingGetter) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	g.gets++
+
+	if g.err != nil {
+		return g.err
+	}
+
+	dst, isService := obj.(*apiv1.Service)
+	svc, exists := g.services[key]
+	if !isService || !exists {
+		return apierrors.NewNotFound(schema.GroupResource{Resource: "services"}, key.Name)
+	}
+
+	svc.DeepCopyInto(dst)
+
+	return nil
+}
+
+func TestCachingGetter(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	nsname := types.NamespacedName{Namespace: "test", Name: "svc"}
+	absent := types.NamespacedName{Namespace: "test", Name: "absent"}
+
+	svc := &apiv1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: nsname.Namespace,
+			Name:      nsname.Name,
+		},
+		Spec: apiv1.ServiceSpec{
+			ClusterIP: "10.0.0.1",
+		},
+	}
+
+	fakeGetter := &countingGetter{
+		services: map[types.NamespacedName]*apiv1.Service{nsname: svc},
+	}
+
+	now := time.Now()
+
+	getter := NewCachingGetter(fakeGetter, 10*time.Second, time.Second)
+	getter.now = func() time.Time { return now }
+
+	get := func(nsname types.NamespacedName) (*apiv1.Service, error) {
+		var got apiv1.Service
+		err := getter.Get(context.Background(), nsname, &got)
+		return &got, err
+	}
+
+	// miss
+
+	got, err := get(nsname)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(svc))
+	g.Expect(fakeGetter.gets).To(Equal(1))
+
+	// hit
+
+	got.Spec.ClusterIP = "10.0.0.2" // the cache stores a copy
+
+	got, err = get(nsname)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(svc))
+	g.Expect(fakeGetter.gets).To(Equal(1))
+
+	// NotFound miss and hit
+
+	_, err = get(absent)
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	g.Expect(fakeGetter.gets).To(Equal(2))
+
+	_, err = get(absent)
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	g.Expect(fakeGetter.gets).To(Equal(2))
+
+	// NotFound expires before the found resource
+
+	now = now.Add(time.Second)
+
+	_, err = get(absent)
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	g.Expect(fakeGetter.gets).To(Equal(3))
+
+	_, err = get(nsname)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(fakeGetter.gets).To(Equal(3))
+
+	// the found resource expires
+
+	updated := svc.DeepCopy()
+	updated.Spec.ClusterIP = "10.0.0.3"
+	fakeGetter.services[nsname] = updated
+
+	now = now.Add(9 * time.Second)
+
+	got, err = get(nsname)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(updated))
+	g.Expect(fakeGetter.gets).To(Equal(4))
+
+	// a resource of a different type with the same name is a miss
+
+	err = getter.Get(context.Background(), nsname, &apiv1.Secret{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	g.Expect(fakeGetter.gets).To(Equal(5))
+}
+
+func TestCachingGetterSweep(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	absent1 := types.NamespacedName{Namespace: "test", Name: "absent-1"}
+	absent2 := types.NamespacedName{Namespace: "test", Name: "absent-2"}
+	absent3 := types.NamespacedName{Namespace: "test", Name: "absent-3"}
+
+	now := time.Now()
+
+	getter := NewCachingGetter(&countingGetter{}, 10*time.Second, time.Second)
+	getter.now = func() time.Time { return now }
+
+	get := func(nsname types.NamespacedName) {
+		err := getter.Get(context.Background(), nsname, &apiv1.Service{})
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	}
+
+	get(absent1)
+	g.Expect(getter.entries).To(HaveLen(1))
+
+	// the expired entries are removed on a write
+
+	now = now.Add(10 * time.Second)
+
+	get(absent2)
+	g.Expect(getter.entries).To(HaveLen(1))
+	g.Expect(getter.entries).To(HaveKey(cacheKey{objType: reflect.TypeOf(&apiv1.Service{}), nsname: absent2}))
+
+	// at most once per TTL
+
+	now = now.Add(time.Second)
+
+	get(absent3)
+	g.Expect(getter.entries).To(HaveLen(2))
+}
+
+func TestCachingGetterErrors(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	nsname := types.NamespacedName{Namespace: "test", Name: "svc"}
+
+	fakeGetter := &countingGetter{
+		err: errors.New("test"),
+	}
+
+	getter := NewCachingGetter(fakeGetter, 10*time.Second, time.Second)
+
+	// errors other than NotFound are not cached
+
+	for i := 1; i <= 2; i++ {
+		err := getter.Get(context.Background(), nsname, &apiv1.Service{})
+		g.Expect(err).To(MatchError("test"))
+		g.Expect(fakeGetter.gets).To(Equal(i))
+	}
+
+	// a zero TTL disables caching
+
+	fakeGetter.err = nil
+	getter = NewCachingGetter(fakeGetter, 0, 0)
+
+	for i := 3; i <= 4; i++ {
+		err := getter.Get(context.Background(), nsname, &apiv1.Service{})
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		g.Expect(fakeGetter.gets).To(Equal(i))
+	}
+
+	// obj must be a pointer
+
+	var svc *apiv1.Service
+	err := getter.Get(context.Background(), nsname, svc)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(fakeGetter.gets).To(Equal(4))
+}
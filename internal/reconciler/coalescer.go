@@ -0,0 +1,87 @@
+package reconciler
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// coalescerEntry is the state of a resource that the reconciler last sent an event for.
+type coalescerEntry struct {
+	expires time.Time
+	state   string
+}
+
+// coalescer remembers the states of the resources that the reconciler sent events for within a window, so that
+// the reconciler can skip the events of the back-to-back reconciliations that find a resource in the same state.
+// A state identifies the event: the deletion of a resource or the upsert of a particular version of it.
+// Because a newer change of a resource has a different state, the coalescer never skips it.
+// coalescer is safe for concurrent use.
+type coalescer struct {
+	entries   map[types.NamespacedName]coalescerEntry
+	lastSweep time.Time
+	now       func() time.Time
+	window    time.Duration
+	lock      sync.Mutex
+}
+
+func newCoalescer(window time.Duration) *coalescer {
+	return &coalescer{
+		entries: make(map[types.NamespacedName]coalescerEntry),
+		now:     time.Now,
+		window:  window,
+	}
+}
+
+// isRedundant returns true if the reconciler sent an event for the resource with the same state within the window.
+// An empty state is never redundant.
+func (c *coalescer) isRedundant(nsname types.NamespacedName, state string) bool {
+	if state == "" {
+		return false
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, exists := c.entries[nsname]
+	if !exists {
+		return false
+	}
+
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, nsname)
+		return false
+	}
+
+	return entry.state == state
+}
+
+// record remembers the state of the resource that the reconciler sent an event for.
+// It also removes the expired entries, at most once per window, so that the entries of the resources that are
+// not reconciled again don't accumulate.
+func (c *coalescer) record(nsname types.NamespacedName, state string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+
+	if now.Sub(c.lastSweep) >= c.window {
+		for key, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, key)
+			}
+		}
+		c.lastSweep = now
+	}
+
+	if state == "" {
+		delete(c.entries, nsname)
+		return
+	}
+
+	c.entries[nsname] = coalescerEntry{
+		expires: now.Add(c.window),
+		state:   state,
+	}
+}
@@ -0,0 +1,67 @@
+package reconciler
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestCoalescer(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	nsname1 := types.NamespacedName{Namespace: "test", Name: "hr-1"}
+	nsname2 := types.NamespacedName{Namespace: "test", Name: "hr-2"}
+
+	now := time.Now()
+
+	c := newCoalescer(10 * time.Second)
+	c.now = func() time.Time { return now }
+
+	// nothing recorded
+
+	g.Expect(c.isRedundant(nsname1, "Upserted/1")).To(BeFalse())
+
+	// same state
+
+	c.record(nsname1, "Upserted/1")
+	g.Expect(c.isRedundant(nsname1, "Upserted/1")).To(BeTrue())
+
+	// newer state
+
+	g.Expect(c.isRedundant(nsname1, "Upserted/2")).To(BeFalse())
+	g.Expect(c.isRedundant(nsname1, "Deleted")).To(BeFalse())
+
+	c.record(nsname1, "Upserted/2")
+	g.Expect(c.isRedundant(nsname1, "Upserted/1")).To(BeFalse())
+	g.Expect(c.isRedundant(nsname1, "Upserted/2")).To(BeTrue())
+
+	// other resource
+
+	g.Expect(c.isRedundant(nsname2, "Upserted/2")).To(BeFalse())
+
+	// empty state
+
+	g.Expect(c.isRedundant(nsname1, "")).To(BeFalse())
+
+	c.record(nsname1, "")
+	g.Expect(c.entries).ToNot(HaveKey(nsname1))
+
+	// expired entry
+
+	c.record(nsname1, "Deleted")
+	now = now.Add(10 * time.Second)
+
+	g.Expect(c.isRedundant(nsname1, "Deleted")).To(BeFalse())
+	g.Expect(c.entries).ToNot(HaveKey(nsname1))
+
+	// sweep of the expired entries
+
+	c.record(nsname2, "Deleted")
+	now = now.Add(10 * time.Second)
+
+	c.record(nsname1, "Deleted")
+	g.Expect(c.entries).To(HaveLen(1))
+	g.Expect(c.entries).To(HaveKey(nsname1))
+}
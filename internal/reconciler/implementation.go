@@ -2,8 +2,10 @@ package reconciler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"time"
 
 	apiv1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -19,9 +21,17 @@ import (
 // If the function returns false, the reconciler will log the returned string.
 type NamespacedNameFilterFunc func(nsname types.NamespacedName) (bool, string)
 
+// ObjectFilterFunc is a function that returns true if the resource should be processed by the reconciler.
+// Unlike NamespacedNameFilterFunc, it gets the resource, so it can filter resources by their contents,
+// for example, annotations. If the function returns false, the reconciler will log the returned string.
+type ObjectFilterFunc func(obj client.Object) (bool, string)
+
 // ValidatorFunc validates a Kubernetes resource.
 type ValidatorFunc func(object client.Object) error
 
+// IgnoredStatusUpdaterFunc updates the status of a resource to report that NKG ignores it with the message.
+type IgnoredStatusUpdaterFunc func(ctx context.Context, obj client.Object, msg string)
+
 // Config contains the configuration for the Implementation.
 type Config struct {
 	// Getter gets a resource from the k8s API.
@@ -32,12 +42,35 @@ type Config struct {
 	EventCh chan<- interface{}
 	// NamespacedNameFilter filters resources the controller will process. Can be nil.
 	NamespacedNameFilter NamespacedNameFilterFunc
+	// ObjectFilter filters resources the controller will process after the reconciler gets them. The reconciler
+	// handles the filtered out resources as if they were deleted. Can be nil.
+	ObjectFilter ObjectFilterFunc
 	// WebhookValidator validates a resource using the same rules as in the Gateway API Webhook. Can be nil.
 	WebhookValidator ValidatorFunc
 	// EventRecorder records event about resources.
 	EventRecorder EventRecorder
+	// AuditLogger logs the decisions that the reconciler makes about the resources. Can be nil.
+	AuditLogger AuditLogger
+	// HonorIgnoreAnnotation makes the reconciler handle resources annotated with IgnoreAnnotation set to "true"
+	// as if they were deleted.
+	HonorIgnoreAnnotation bool
+	// IgnoredStatusUpdater updates the statuses of the resources ignored because of IgnoreAnnotation. Can be nil.
+	IgnoredStatusUpdater IgnoredStatusUpdaterFunc
+	// EventSendTimeout is the maximum time the reconciler waits for the event channel to receive an event.
+	// If the event is not received in time, the reconciler requeues the resource instead of waiting longer.
+	// 0 means the reconciler waits until the event is received or the context is canceled.
+	EventSendTimeout time.Duration
+	// CoalescingWindow is the time within which the reconciler skips the event of a reconciliation that finds
+	// the resource in the same state as the previous reconciliation that sent an event, for example, the same
+	// version of the resource. Because a newer change of the resource changes its state, its event is never skipped.
+	// 0 disables the coalescing.
+	CoalescingWindow time.Duration
 }
 
+// IgnoreAnnotation is the annotation that makes NKG ignore a resource, if its value is "true".
+// NKG handles an ignored resource as if it didn't exist, but reports that it is ignored in its status.
+const IgnoreAnnotation = "k8s-gateway.nginx.org/ignore"
+
 // Implementation is a reconciler for Kubernetes resources.
 // It implements the reconcile.Reconciler interface.
 // A successful reconciliation of a resource has the two possible outcomes:
@@ -45,15 +78,23 @@ type Config struct {
 // (2) If the resource is upserted (created or updated), the Implementation will send an UpsertEvent
 // to the event channel.
 type Implementation struct {
-	cfg Config
+	// coalescer is nil if the coalescing is disabled.
+	coalescer *coalescer
+	cfg       Config
 }
 
 var _ reconcile.Reconciler = &Implementation{}
 
 // NewImplementation creates a new Implementation.
 func NewImplementation(cfg Config) *Implementation {
+	var c *coalescer
+	if cfg.CoalescingWindow > 0 {
+		c = newCoalescer(cfg.CoalescingWindow)
+	}
+
 	return &Implementation{
-		cfg: cfg,
+		coalescer: c,
+		cfg:       cfg,
 	}
 }
 
@@ -70,8 +111,15 @@ const (
 	webhookValidationErrorLogMsg = "Rejected the resource because the Gateway API webhook failed to reject it with " +
 		"a validation error; make sure the webhook is installed and running correctly; " +
 		"NKG will delete any existing NGINX configuration that corresponds to the resource"
+	ignoreAnnotationLogMsg = "Ignored the resource because it has the " + IgnoreAnnotation + " annotation set to " +
+		"\"true\"; NKG will delete any existing NGINX configuration that corresponds to the resource"
+	coalescedLogMsg = "Skipped the resource because it has not changed since the previous reconciliation"
 )
 
+func isIgnored(obj client.Object) bool {
+	return obj.GetAnnotations()[IgnoreAnnotation] == "true"
+}
+
 // Reconcile implements the reconcile.Reconciler Reconcile method.
 func (r *Implementation) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
 	logger := log.FromContext(ctx)
@@ -83,6 +131,7 @@ func (r *Implementation) Reconcile(ctx context.Context, req reconcile.Request) (
 	if r.cfg.NamespacedNameFilter != nil {
 		if allow, msg := r.cfg.NamespacedNameFilter(req.NamespacedName); !allow {
 			logger.Info(msg)
+			r.audit(req.NamespacedName, AuditDecisionFiltered, msg)
 			return reconcile.Result{}, nil
 		}
 	}
@@ -98,8 +147,34 @@ func (r *Implementation) Reconcile(ctx context.Context, req reconcile.Request) (
 		obj = nil
 	}
 
+	ignored := obj != nil && r.cfg.HonorIgnoreAnnotation && isIgnored(obj)
+
+	// auditDecision and auditReason are logged once the event is sent, because the decision only takes effect then.
+	var (
+		auditDecision AuditDecision
+		auditReason   string
+	)
+
+	if ignored {
+		logger.Info(ignoreAnnotationLogMsg)
+		r.cfg.EventRecorder.Eventf(obj, apiv1.EventTypeNormal, "Ignored", ignoreAnnotationLogMsg)
+		if r.cfg.IgnoredStatusUpdater != nil {
+			r.cfg.IgnoredStatusUpdater(ctx, obj, ignoreAnnotationLogMsg)
+		}
+		auditDecision, auditReason = AuditDecisionFiltered, ignoreAnnotationLogMsg
+	}
+
+	if obj != nil && !ignored && r.cfg.ObjectFilter != nil {
+		if allow, msg := r.cfg.ObjectFilter(obj); !allow {
+			logger.Info(msg)
+			// We don't record an event, because the filter can filter out most resources in the cluster.
+			ignored = true
+			auditDecision, auditReason = AuditDecisionFiltered, msg
+		}
+	}
+
 	var validationError error
-	if obj != nil && r.cfg.WebhookValidator != nil {
+	if obj != nil && !ignored && r.cfg.WebhookValidator != nil {
 		validationError = r.cfg.WebhookValidator(obj)
 	}
 
@@ -107,33 +182,84 @@ func (r *Implementation) Reconcile(ctx context.Context, req reconcile.Request) (
 		logger.Error(validationError, webhookValidationErrorLogMsg)
 		r.cfg.EventRecorder.Eventf(obj, apiv1.EventTypeWarning, "Rejected",
 			webhookValidationErrorLogMsg+"; validation error: %v", validationError)
+		auditDecision, auditReason = AuditDecisionRejected, fmt.Sprintf("validation error: %v", validationError)
 	}
 
 	var e interface{}
 	var op string
+	// state identifies the event for the coalescing. An empty state is never coalesced.
+	var state string
 
-	if obj == nil || validationError != nil {
-		// In case of a validation error, we handle the resource as if it was deleted.
+	if obj == nil || ignored || validationError != nil {
+		// In case of an ignored or filtered out resource or a validation error, we handle the resource as
+		// if it was deleted.
 		e = &events.DeleteEvent{
 			Type:           r.cfg.ObjectType,
 			NamespacedName: req.NamespacedName,
 		}
 		op = "Deleted"
+		state = op
+		if auditDecision == "" {
+			auditDecision = AuditDecisionDeleted
+		}
 	} else {
 		e = &events.UpsertEvent{
 			Resource: obj,
 		}
 		op = "Upserted"
+		auditDecision = AuditDecisionUpserted
+		// Without the resource version, the reconciler can't tell the versions of the resource apart.
+		if rv := obj.GetResourceVersion(); rv != "" {
+			state = op + "/" + rv
+		}
+	}
+
+	if r.coalescer != nil && r.coalescer.isRedundant(req.NamespacedName, state) {
+		logger.V(1).Info(coalescedLogMsg)
+		r.audit(req.NamespacedName, AuditDecisionCoalesced, coalescedLogMsg)
+		return reconcile.Result{}, nil
+	}
+
+	// A nil timeout channel blocks forever, so without a timeout only the context can stop the waiting.
+	var timeoutCh <-chan time.Time
+	if r.cfg.EventSendTimeout > 0 {
+		timer := time.NewTimer(r.cfg.EventSendTimeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
 	}
 
 	select {
 	case <-ctx.Done():
 		logger.Info("Did not process the resource because the context was canceled")
 		return reconcile.Result{}, nil
+	case <-timeoutCh:
+		logger.Error(
+			errors.New("timed out sending the event"),
+			"Did not process the resource because the event loop didn't receive the event in time; "+
+				"the resource will be requeued",
+			"timeout", r.cfg.EventSendTimeout,
+		)
+		return reconcile.Result{Requeue: true}, nil
 	case r.cfg.EventCh <- e:
 	}
 
+	if r.coalescer != nil {
+		r.coalescer.record(req.NamespacedName, state)
+	}
+
 	logger.Info(fmt.Sprintf("%s the resource", op))
+	r.audit(req.NamespacedName, auditDecision, auditReason)
 
 	return reconcile.Result{}, nil
 }
+
+// audit logs the decision about the resource, if the AuditLogger is configured.
+func (r *Implementation) audit(nsname types.NamespacedName, decision AuditDecision, reason string) {
+	if r.cfg.AuditLogger == nil {
+		return
+	}
+
+	kind := reflect.TypeOf(r.cfg.ObjectType).Elem().Name()
+
+	r.cfg.AuditLogger.Log(newAuditRecord(kind, nsname, decision, reason))
+}
@@ -3,9 +3,11 @@ package reconciler_test
 import (
 	"context"
 	"errors"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	apiv1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -57,6 +59,21 @@ var _ = Describe("Reconciler", func() {
 			},
 		}
 
+		hr3NsName = types.NamespacedName{
+			Namespace: "test",
+			Name:      "hr-3",
+		}
+
+		hr3 = &v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: hr3NsName.Namespace,
+				Name:      hr3NsName.Name,
+				Annotations: map[string]string{
+					reconciler.IgnoreAnnotation: "true",
+				},
+			},
+		}
+
 		hr2IsInvalidValidator = func(obj client.Object) error {
 			if client.ObjectKeyFromObject(obj) == hr2NsName {
 				return errors.New("test")
@@ -249,6 +266,128 @@ var _ = Describe("Reconciler", func() {
 				Expect(fakeRecorder.EventfCallCount()).To(Equal(0))
 			})
 		})
+
+		When("Reconciler honors the ignore annotation", func() {
+			var (
+				fakeRecorder         *reconcilerfakes.FakeEventRecorder
+				ignoredStatusObjects []client.Object
+				ignoredStatusMsgs    []string
+			)
+
+			BeforeEach(func() {
+				fakeRecorder = &reconcilerfakes.FakeEventRecorder{}
+				ignoredStatusObjects, ignoredStatusMsgs = nil, nil
+
+				rec = reconciler.NewImplementation(reconciler.Config{
+					Getter:                fakeGetter,
+					ObjectType:            &v1beta1.HTTPRoute{},
+					EventCh:               eventCh,
+					EventRecorder:         fakeRecorder,
+					HonorIgnoreAnnotation: true,
+					IgnoredStatusUpdater: func(_ context.Context, obj client.Object, msg string) {
+						ignoredStatusObjects = append(ignoredStatusObjects, obj)
+						ignoredStatusMsgs = append(ignoredStatusMsgs, msg)
+					},
+				})
+			})
+
+			It("should upsert HTTPRoute without the annotation", func() {
+				testUpsert(hr1)
+				Expect(fakeRecorder.EventfCallCount()).To(Equal(0))
+				Expect(ignoredStatusObjects).To(BeEmpty())
+			})
+
+			It("should handle ignored HTTPRoute as deleted", func() {
+				fakeGetter.GetCalls(getReturnsHRForHR(hr3))
+
+				resultCh := startReconciling(hr3NsName)
+
+				Eventually(eventCh).Should(Receive(Equal(&events.DeleteEvent{
+					NamespacedName: hr3NsName,
+					Type:           &v1beta1.HTTPRoute{},
+				})))
+				Eventually(resultCh).Should(Receive(Equal(result{err: nil, reconcileResult: reconcile.Result{}})))
+
+				Expect(fakeRecorder.EventfCallCount()).To(Equal(1))
+				obj, eventType, reason, _, _ := fakeRecorder.EventfArgsForCall(0)
+				Expect(obj).To(Equal(hr3))
+				Expect(eventType).To(Equal(apiv1.EventTypeNormal))
+				Expect(reason).To(Equal("Ignored"))
+
+				Expect(ignoredStatusObjects).To(Equal([]client.Object{hr3}))
+				Expect(ignoredStatusMsgs).To(HaveLen(1))
+				Expect(ignoredStatusMsgs[0]).To(ContainSubstring(reconciler.IgnoreAnnotation))
+			})
+
+			It("should upsert HTTPRoute with the annotation set to false", func() {
+				hr := hr3.DeepCopy()
+				hr.Annotations[reconciler.IgnoreAnnotation] = "false"
+
+				testUpsert(hr)
+				Expect(fakeRecorder.EventfCallCount()).To(Equal(0))
+				Expect(ignoredStatusObjects).To(BeEmpty())
+			})
+		})
+
+		When("Reconciler has an object filter", func() {
+			var fakeRecorder *reconcilerfakes.FakeEventRecorder
+
+			BeforeEach(func() {
+				fakeRecorder = &reconcilerfakes.FakeEventRecorder{}
+
+				rec = reconciler.NewImplementation(reconciler.Config{
+					Getter:        fakeGetter,
+					ObjectType:    &v1beta1.HTTPRoute{},
+					EventCh:       eventCh,
+					EventRecorder: fakeRecorder,
+					ObjectFilter: func(obj client.Object) (bool, string) {
+						if client.ObjectKeyFromObject(obj) == hr2NsName {
+							return false, "filtered out"
+						}
+						return true, ""
+					},
+					HonorIgnoreAnnotation: true,
+				})
+			})
+
+			It("should upsert HTTPRoute allowed by the filter", func() {
+				testUpsert(hr1)
+			})
+
+			It("should delete HTTPRoute filtered out by the filter", func() {
+				testDelete(hr2)
+			})
+
+			It("should handle HTTPRoute filtered out by the filter as deleted", func() {
+				fakeGetter.GetCalls(getReturnsHRForHR(hr2))
+
+				resultCh := startReconciling(hr2NsName)
+
+				Eventually(eventCh).Should(Receive(Equal(&events.DeleteEvent{
+					NamespacedName: hr2NsName,
+					Type:           &v1beta1.HTTPRoute{},
+				})))
+				Eventually(resultCh).Should(Receive(Equal(result{err: nil, reconcileResult: reconcile.Result{}})))
+			})
+
+			AfterEach(func() {
+				Expect(fakeRecorder.EventfCallCount()).To(Equal(0))
+			})
+		})
+
+		When("Reconciler doesn't honor the ignore annotation", func() {
+			BeforeEach(func() {
+				rec = reconciler.NewImplementation(reconciler.Config{
+					Getter:     fakeGetter,
+					ObjectType: &v1beta1.HTTPRoute{},
+					EventCh:    eventCh,
+				})
+			})
+
+			It("should upsert HTTPRoute with the annotation", func() {
+				testUpsert(hr3)
+			})
+		})
 	})
 
 	Describe("Edge cases", func() {
@@ -294,4 +433,308 @@ var _ = Describe("Reconciler", func() {
 			Entry("Upserting invalid HTTPRoute", getReturnsHRForHR(hr2), 1, hr2NsName),
 		)
 	})
+
+	Describe("Event send timeout", func() {
+		BeforeEach(func() {
+			rec = reconciler.NewImplementation(reconciler.Config{
+				Getter:           fakeGetter,
+				ObjectType:       &v1beta1.HTTPRoute{},
+				EventCh:          eventCh,
+				EventSendTimeout: 500 * time.Millisecond,
+			})
+		})
+
+		It("should requeue when the event is not received in time", func() {
+			fakeGetter.GetCalls(getReturnsHRForHR(hr1))
+
+			resultCh := startReconciling(hr1NsName)
+
+			Eventually(resultCh).WithTimeout(2 * time.Second).Should(
+				Receive(Equal(result{err: nil, reconcileResult: reconcile.Result{Requeue: true}})),
+			)
+			Expect(eventCh).ToNot(Receive())
+		})
+
+		It("should send the event when it is received in time", func() {
+			fakeGetter.GetCalls(getReturnsHRForHR(hr1))
+
+			resultCh := startReconciling(hr1NsName)
+
+			Eventually(eventCh).Should(Receive(Equal(&events.UpsertEvent{Resource: hr1})))
+			Eventually(resultCh).Should(Receive(Equal(result{err: nil, reconcileResult: reconcile.Result{}})))
+		})
+
+		It("should not block when ctx is done", func() {
+			fakeGetter.GetCalls(getReturnsHRForHR(hr1))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			resultCh := startReconcilingWithContext(ctx, hr1NsName)
+
+			Eventually(resultCh).Should(Receive(Equal(result{err: nil, reconcileResult: reconcile.Result{}})))
+			Expect(eventCh).ToNot(Receive())
+		})
+	})
+
+	Describe("Coalescing", func() {
+		var hr1v1, hr1v2 *v1beta1.HTTPRoute
+
+		BeforeEach(func() {
+			rec = reconciler.NewImplementation(reconciler.Config{
+				Getter:           fakeGetter,
+				ObjectType:       &v1beta1.HTTPRoute{},
+				EventCh:          eventCh,
+				CoalescingWindow: time.Hour,
+			})
+
+			hr1v1 = hr1.DeepCopy()
+			hr1v1.ResourceVersion = "1"
+
+			hr1v2 = hr1.DeepCopy()
+			hr1v2.ResourceVersion = "2"
+		})
+
+		reconcileAndExpectEvent := func(get getFunc, expEvent interface{}) {
+			fakeGetter.GetCalls(get)
+
+			resultCh := startReconciling(hr1NsName)
+
+			Eventually(eventCh).Should(Receive(Equal(expEvent)))
+			Eventually(resultCh).Should(Receive(Equal(result{err: nil, reconcileResult: reconcile.Result{}})))
+		}
+
+		reconcileAndExpectNoEvent := func(get getFunc) {
+			fakeGetter.GetCalls(get)
+
+			resultCh := startReconciling(hr1NsName)
+
+			Eventually(resultCh).Should(Receive(Equal(result{err: nil, reconcileResult: reconcile.Result{}})))
+			Expect(eventCh).ToNot(Receive())
+		}
+
+		deleteEvent := &events.DeleteEvent{
+			Type:           &v1beta1.HTTPRoute{},
+			NamespacedName: hr1NsName,
+		}
+
+		It("should coalesce the reconciliations of the same state and keep the latest state", func() {
+			reconcileAndExpectEvent(getReturnsHRForHR(hr1v1), &events.UpsertEvent{Resource: hr1v1})
+			reconcileAndExpectNoEvent(getReturnsHRForHR(hr1v1))
+
+			reconcileAndExpectEvent(getReturnsHRForHR(hr1v2), &events.UpsertEvent{Resource: hr1v2})
+			reconcileAndExpectNoEvent(getReturnsHRForHR(hr1v2))
+
+			reconcileAndExpectEvent(getReturnsNotFoundErrorForHR(hr1), deleteEvent)
+			reconcileAndExpectNoEvent(getReturnsNotFoundErrorForHR(hr1))
+
+			reconcileAndExpectEvent(getReturnsHRForHR(hr1v1), &events.UpsertEvent{Resource: hr1v1})
+
+			Expect(fakeGetter.GetCallCount()).To(Equal(7))
+		})
+
+		It("should not coalesce the resources without the resource version", func() {
+			reconcileAndExpectEvent(getReturnsHRForHR(hr1), &events.UpsertEvent{Resource: hr1})
+			reconcileAndExpectEvent(getReturnsHRForHR(hr1), &events.UpsertEvent{Resource: hr1})
+		})
+
+		It("should not coalesce the reconciliation that didn't send the event", func() {
+			fakeGetter.GetCalls(getReturnsHRForHR(hr1v1))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			resultCh := startReconcilingWithContext(ctx, hr1NsName)
+
+			Eventually(resultCh).Should(Receive(Equal(result{err: nil, reconcileResult: reconcile.Result{}})))
+			Expect(eventCh).ToNot(Receive())
+
+			reconcileAndExpectEvent(getReturnsHRForHR(hr1v1), &events.UpsertEvent{Resource: hr1v1})
+		})
+
+		It("should not coalesce when the window expires", func() {
+			rec = reconciler.NewImplementation(reconciler.Config{
+				Getter:           fakeGetter,
+				ObjectType:       &v1beta1.HTTPRoute{},
+				EventCh:          eventCh,
+				CoalescingWindow: 100 * time.Millisecond,
+			})
+
+			reconcileAndExpectEvent(getReturnsHRForHR(hr1v1), &events.UpsertEvent{Resource: hr1v1})
+
+			time.Sleep(200 * time.Millisecond)
+
+			reconcileAndExpectEvent(getReturnsHRForHR(hr1v1), &events.UpsertEvent{Resource: hr1v1})
+		})
+	})
+
+	Describe("Audit log", func() {
+		var fakeAuditLogger *reconcilerfakes.FakeAuditLogger
+
+		hr4NsName := types.NamespacedName{
+			Namespace: "test",
+			Name:      "hr-4",
+		}
+
+		hr4 := &v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: hr4NsName.Namespace,
+				Name:      hr4NsName.Name,
+			},
+		}
+
+		hr5NsName := types.NamespacedName{
+			Namespace: "other",
+			Name:      "hr-5",
+		}
+
+		BeforeEach(func() {
+			fakeAuditLogger = &reconcilerfakes.FakeAuditLogger{}
+
+			rec = reconciler.NewImplementation(reconciler.Config{
+				Getter:     fakeGetter,
+				ObjectType: &v1beta1.HTTPRoute{},
+				EventCh:    eventCh,
+				NamespacedNameFilter: func(nsname types.NamespacedName) (bool, string) {
+					if nsname.Namespace == hr5NsName.Namespace {
+						return false, "filtered out by name"
+					}
+					return true, ""
+				},
+				ObjectFilter: func(obj client.Object) (bool, string) {
+					if client.ObjectKeyFromObject(obj) == hr4NsName {
+						return false, "filtered out"
+					}
+					return true, ""
+				},
+				WebhookValidator:      hr2IsInvalidValidator,
+				EventRecorder:         &reconcilerfakes.FakeEventRecorder{},
+				HonorIgnoreAnnotation: true,
+				AuditLogger:           fakeAuditLogger,
+			})
+		})
+
+		DescribeTable("Reconciler should log the decision",
+			func(
+				get getFunc,
+				nsname types.NamespacedName,
+				expDecision reconciler.AuditDecision,
+				expReason string,
+				expEvent bool,
+			) {
+				fakeGetter.GetCalls(get)
+
+				before := time.Now()
+				resultCh := startReconciling(nsname)
+
+				if expEvent {
+					Eventually(eventCh).Should(Receive())
+				}
+				Eventually(resultCh).Should(Receive(Equal(result{err: nil, reconcileResult: reconcile.Result{}})))
+
+				Expect(fakeAuditLogger.LogCallCount()).To(Equal(1))
+
+				record := fakeAuditLogger.LogArgsForCall(0)
+				Expect(record.Time.Before(before)).To(BeFalse())
+
+				record.Time = time.Time{}
+				Expect(record).To(Equal(reconciler.AuditRecord{
+					Kind:      "HTTPRoute",
+					Namespace: nsname.Namespace,
+					Name:      nsname.Name,
+					Decision:  expDecision,
+					Reason:    expReason,
+				}))
+			},
+			Entry("Upserted HTTPRoute", getReturnsHRForHR(hr1), hr1NsName, reconciler.AuditDecisionUpserted, "", true),
+			Entry(
+				"Deleted HTTPRoute",
+				getReturnsNotFoundErrorForHR(hr1),
+				hr1NsName,
+				reconciler.AuditDecisionDeleted,
+				"",
+				true,
+			),
+			Entry(
+				"Rejected HTTPRoute",
+				getReturnsHRForHR(hr2),
+				hr2NsName,
+				reconciler.AuditDecisionRejected,
+				"validation error: test",
+				true,
+			),
+			Entry(
+				"Ignored HTTPRoute",
+				getReturnsHRForHR(hr3),
+				hr3NsName,
+				reconciler.AuditDecisionFiltered,
+				"Ignored the resource because it has the k8s-gateway.nginx.org/ignore annotation set to \"true\"; "+
+					"NKG will delete any existing NGINX configuration that corresponds to the resource",
+				true,
+			),
+			Entry(
+				"HTTPRoute filtered out by the object filter",
+				getReturnsHRForHR(hr4),
+				hr4NsName,
+				reconciler.AuditDecisionFiltered,
+				"filtered out",
+				true,
+			),
+			Entry(
+				"HTTPRoute filtered out by the namespaced name filter",
+				getReturnsNotFoundErrorForHR(hr1),
+				hr5NsName,
+				reconciler.AuditDecisionFiltered,
+				"filtered out by name",
+				false,
+			),
+		)
+
+		It("should log the coalesced reconciliation", func() {
+			rec = reconciler.NewImplementation(reconciler.Config{
+				Getter:           fakeGetter,
+				ObjectType:       &v1beta1.HTTPRoute{},
+				EventCh:          eventCh,
+				CoalescingWindow: time.Hour,
+				AuditLogger:      fakeAuditLogger,
+			})
+
+			hr1v1 := hr1.DeepCopy()
+			hr1v1.ResourceVersion = "1"
+
+			fakeGetter.GetCalls(getReturnsHRForHR(hr1v1))
+
+			resultCh := startReconciling(hr1NsName)
+			Eventually(eventCh).Should(Receive())
+			Eventually(resultCh).Should(Receive(Equal(result{err: nil, reconcileResult: reconcile.Result{}})))
+
+			resultCh = startReconciling(hr1NsName)
+			Eventually(resultCh).Should(Receive(Equal(result{err: nil, reconcileResult: reconcile.Result{}})))
+			Expect(eventCh).ToNot(Receive())
+
+			Expect(fakeAuditLogger.LogCallCount()).To(Equal(2))
+
+			record := fakeAuditLogger.LogArgsForCall(1)
+			record.Time = time.Time{}
+			Expect(record).To(Equal(reconciler.AuditRecord{
+				Kind:      "HTTPRoute",
+				Namespace: hr1NsName.Namespace,
+				Name:      hr1NsName.Name,
+				Decision:  reconciler.AuditDecisionCoalesced,
+				Reason:    "Skipped the resource because it has not changed since the previous reconciliation",
+			}))
+		})
+
+		It("should not log the decision when ctx is done", func() {
+			fakeGetter.GetCalls(getReturnsHRForHR(hr1))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			resultCh := startReconcilingWithContext(ctx, hr1NsName)
+
+			Eventually(resultCh).Should(Receive(Equal(result{err: nil, reconcileResult: reconcile.Result{}})))
+			Expect(fakeAuditLogger.LogCallCount()).To(Equal(0))
+		})
+	})
 })
@@ -0,0 +1,91 @@
+// Package testutil contains helpers for the unit tests of the code that uses the reconciler package.
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/reconciler"
+)
+
+// objectKey identifies an object by its type and namespaced name.
+type objectKey struct {
+	objType reflect.Type
+	nsname  types.NamespacedName
+}
+
+// MemoryGetter is a reconciler.Getter that gets objects from an in-memory store.
+// It returns a NotFound error for objects that are not in the store.
+// MemoryGetter is safe for concurrent use.
+type MemoryGetter struct {
+	objects map[objectKey]client.Object
+	lock    sync.RWMutex
+}
+
+var _ reconciler.Getter = &MemoryGetter{}
+
+// NewMemoryGetter creates a new MemoryGetter that stores the copies of objs.
+func NewMemoryGetter(objs ...client.Object) *MemoryGetter {
+	g := &MemoryGetter{
+		objects: make(map[objectKey]client.Object),
+	}
+
+	g.Upsert(objs...)
+
+	return g
+}
+
+// Upsert stores the copies of objs, replacing the stored objects of the same type and namespaced name.
+func (g *MemoryGetter) Upsert(objs ...client.Object) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	for _, obj := range objs {
+		g.objects[newObjectKey(obj, client.ObjectKeyFromObject(obj))] = obj.DeepCopyObject().(client.Object)
+	}
+}
+
+// Delete deletes the object of the type of objType with the namespaced name nsname.
+func (g *MemoryGetter) Delete(objType client.Object, nsname types.NamespacedName) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	delete(g.objects, newObjectKey(objType, nsname))
+}
+
+// Get copies the stored object of the type of obj with the namespaced name key into obj.
+// It returns a NotFound error if such object doesn't exist.
+func (g *MemoryGetter) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	dst := reflect.ValueOf(obj)
+	if dst.Kind() != reflect.Pointer || dst.IsNil() {
+		return fmt.Errorf("obj must be a non-nil pointer, got %T", obj)
+	}
+
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	stored, exists := g.objects[newObjectKey(obj, key)]
+	if !exists {
+		resource := strings.ToLower(dst.Elem().Type().Name())
+		return apierrors.NewNotFound(schema.GroupResource{Resource: resource}, key.String())
+	}
+
+	dst.Elem().Set(reflect.ValueOf(stored.DeepCopyObject()).Elem())
+
+	return nil
+}
+
+func newObjectKey(obj client.Object, nsname types.NamespacedName) objectKey {
+	return objectKey{
+		objType: reflect.TypeOf(obj),
+		nsname:  nsname,
+	}
+}
@@ -0,0 +1,74 @@
+package testutil
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiv1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func TestMemoryGetter(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "route",
+		},
+		Spec: v1beta1.HTTPRouteSpec{
+			Hostnames: []v1beta1.Hostname{"example.com"},
+		},
+	}
+	nsname := types.NamespacedName{Namespace: "test", Name: "route"}
+
+	getter := NewMemoryGetter(hr)
+
+	// get
+
+	var got v1beta1.HTTPRoute
+	err := getter.Get(context.Background(), nsname, &got)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(*hr))
+
+	// the getter returns copies
+
+	got.Spec.Hostnames[0] = "foo.example.com"
+
+	var gotAgain v1beta1.HTTPRoute
+	err = getter.Get(context.Background(), nsname, &gotAgain)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(gotAgain.Spec.Hostnames).To(Equal([]v1beta1.Hostname{"example.com"}))
+
+	// an object of a different type with the same name is not found
+
+	err = getter.Get(context.Background(), nsname, &apiv1.Service{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+	// an absent object is not found
+
+	absent := types.NamespacedName{Namespace: "test", Name: "absent"}
+	err = getter.Get(context.Background(), absent, &v1beta1.HTTPRoute{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+	// update
+
+	hrUpdated := hr.DeepCopy()
+	hrUpdated.Generation = 2
+	getter.Upsert(hrUpdated)
+
+	err = getter.Get(context.Background(), nsname, &got)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.Generation).To(Equal(int64(2)))
+
+	// delete
+
+	getter.Delete(&v1beta1.HTTPRoute{}, nsname)
+
+	err = getter.Get(context.Background(), nsname, &got)
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/go-logr/logr"
 	v1 "k8s.io/api/core/v1"
@@ -12,6 +14,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/gateway-api/apis/v1beta1"
 
+	"github.com/nginxinc/nginx-kubernetes-gateway/apis/v1alpha1"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/metrics"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/relationship"
@@ -34,8 +38,8 @@ type ChangeProcessor interface {
 	CaptureDeleteChange(resourceType client.Object, nsname types.NamespacedName)
 	// Process processes any captured changes and produces an internal representation of the Gateway configuration and
 	// the status information about the processed resources.
-	// If no changes were captured, the changed return argument will be false and both the configuration and statuses
-	// will be empty.
+	// If no changes were captured and the grace period of no draining endpoint has expired, the changed return
+	// argument will be false and both the configuration and statuses will be empty.
 	Process(ctx context.Context) (changed bool, conf dataplane.Configuration, statuses Statuses)
 }
 
@@ -53,6 +57,28 @@ type ChangeProcessorConfig struct {
 	RelationshipCapturer relationship.Capturer
 	// Logger is the logger for this Change Processor.
 	Logger logr.Logger
+	// RequestProcessing is called when the grace period of a draining endpoint expires, so that the next call to
+	// Process removes the endpoint from the configuration. It is called in its own goroutine. Can be nil.
+	RequestProcessing func()
+	// EndpointRemovalGracePeriod is the period during which the endpoints removed from an Upstream stay in the
+	// Upstream as draining endpoints. 0 means the endpoints are removed right away.
+	EndpointRemovalGracePeriod time.Duration
+	// MaxRoutesPerListener is the maximum number of HTTPRoutes that can attach to a listener. 0 means no limit.
+	MaxRoutesPerListener int
+	// ExternalNameAllowlist is the list of the external names of the ExternalName Services that HTTPRoutes can
+	// reference as backends. Empty means no limit.
+	ExternalNameAllowlist []string
+	// UnixSocketBackendsDir is the directory of the Unix domain sockets that Services can configure as their
+	// backends. Empty means the Unix socket backends are disabled.
+	UnixSocketBackendsDir string
+	// ConformanceMode makes the graph reject the rules of HTTPRoutes that use unsupported features instead of
+	// ignoring the features.
+	ConformanceMode bool
+	// BackendResolvers resolves the backendRefs of the custom kinds, other than the core Service.
+	// The backendRefs of the kinds without a resolver are invalid.
+	BackendResolvers graph.BackendResolvers
+	// MetricsCollector collects the numbers of the resources that NGINX is configured for. Can be nil.
+	MetricsCollector *metrics.GraphCollector
 }
 
 // ChangeProcessorImpl is an implementation of ChangeProcessor.
@@ -62,18 +88,32 @@ type ChangeProcessorImpl struct {
 
 	// changed is true if any changes that were captured require an update to nginx.
 	// It is true if the store changed, or if a Kubernetes resource (e.g.
-	// Service, EndpointSlice) that is related to a Gateway API resource (e.g. Gateway, HTTPRoute) changed.
+	// Service, EndpointSlice, Secret) that is related to a Gateway API resource (e.g. Gateway, HTTPRoute) changed.
 	// It is reset to false after Process is called.
 	changed bool
 
+	// latestGraph and latestUpstreams are the Graph and the Upstreams built during the latest call to Process.
+	// They are used to log the differences between the consecutive rebuilds.
+	latestGraph     *graph.Graph
+	latestUpstreams []dataplane.Upstream
+
+	// routeSnapshots holds the routeSnapshots built during the latest call to Process. It is replaced rather than
+	// modified, so that GetRouteSnapshot reads it without waiting for Process to finish.
+	routeSnapshots atomic.Value
+
+	drainer *endpointDrainer
+	// processingTimer calls RequestProcessing when the grace period of the next draining endpoint expires.
+	processingTimer *time.Timer
+
 	lock sync.Mutex
 }
 
 // NewChangeProcessorImpl creates a new ChangeProcessorImpl for the Gateway resource with the configured namespace name.
 func NewChangeProcessorImpl(cfg ChangeProcessorConfig) *ChangeProcessorImpl {
 	return &ChangeProcessorImpl{
-		store: newStore(),
-		cfg:   cfg,
+		store:   newStore(),
+		cfg:     cfg,
+		drainer: newEndpointDrainer(cfg.EndpointRemovalGracePeriod),
 	}
 }
 
@@ -98,8 +138,17 @@ func (c *ChangeProcessorImpl) CaptureUpsertChange(obj client.Object) {
 		c.store.captureHTTPRouteChange(o)
 	case *v1.Service:
 		c.store.captureServiceChange(o)
+	case *v1alpha1.CORSPolicy:
+		c.store.captureCORSPolicyChange(o)
+	case *v1alpha1.DirectResponse:
+		c.store.captureDirectResponseChange(o)
+	case *v1.Namespace:
+		c.store.captureNamespaceChange(o)
 	case *discoveryV1.EndpointSlice:
 		break
+	case *v1.Secret:
+		// Secrets are stored in the SecretStore. We only need to know if a Gateway references the Secret.
+		break
 	default:
 		panic(fmt.Errorf("ChangeProcessor doesn't support %T", obj))
 	}
@@ -128,7 +177,16 @@ func (c *ChangeProcessorImpl) CaptureDeleteChange(resourceType client.Object, ns
 		delete(c.store.httpRoutes, nsname)
 	case *v1.Service:
 		delete(c.store.services, nsname)
-	case *discoveryV1.EndpointSlice:
+	case *v1alpha1.CORSPolicy:
+		_, c.store.changed = c.store.corsPolicies[nsname]
+		delete(c.store.corsPolicies, nsname)
+	case *v1alpha1.DirectResponse:
+		_, c.store.changed = c.store.directResponses[nsname]
+		delete(c.store.directResponses, nsname)
+	case *v1.Namespace:
+		c.store.captureNamespaceDelete(nsname)
+	case *discoveryV1.EndpointSlice, *v1.Secret:
+		// Secrets are stored in the secrets.SecretStore, which the SecretMemoryManager reads Secrets from.
 		break
 	default:
 		panic(fmt.Errorf("ChangeProcessor doesn't support %T", resourceType))
@@ -145,7 +203,9 @@ func (c *ChangeProcessorImpl) Process(
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	if !c.changed {
+	now := time.Now()
+
+	if !c.changed && !c.drainingEndpointsExpired(now) {
 		return false, conf, statuses
 	}
 
@@ -154,18 +214,28 @@ func (c *ChangeProcessorImpl) Process(
 
 	g := graph.BuildGraph(
 		graph.ClusterStore{
-			GatewayClass: c.store.gc,
-			Gateways:     c.store.gateways,
-			HTTPRoutes:   c.store.httpRoutes,
-			Services:     c.store.services,
+			GatewayClass:    c.store.gc,
+			Gateways:        c.store.gateways,
+			HTTPRoutes:      c.store.httpRoutes,
+			Services:        c.store.services,
+			CORSPolicies:    c.store.corsPolicies,
+			DirectResponses: c.store.directResponses,
+			Namespaces:      c.store.namespaces,
 		},
 		c.cfg.GatewayCtlrName,
 		c.cfg.GatewayClassName,
 		c.cfg.SecretMemoryManager,
+		c.cfg.MaxRoutesPerListener,
+		c.cfg.ExternalNameAllowlist,
+		c.cfg.BackendResolvers,
+		c.cfg.ConformanceMode,
 	)
 
 	var warnings dataplane.Warnings
-	conf, warnings = dataplane.BuildConfiguration(ctx, g, c.cfg.ServiceResolver)
+	conf, warnings = dataplane.BuildConfiguration(ctx, g, c.cfg.ServiceResolver, c.cfg.UnixSocketBackendsDir)
+
+	c.drainer.drain(conf.Upstreams, now)
+	c.scheduleProcessing(now)
 
 	for obj, objWarnings := range warnings {
 		for _, w := range objWarnings {
@@ -178,7 +248,68 @@ func (c *ChangeProcessorImpl) Process(
 		}
 	}
 
+	c.logDiff(g, conf.Upstreams)
+
+	if c.cfg.MetricsCollector != nil {
+		c.cfg.MetricsCollector.Update(buildGraphCounts(g, conf))
+	}
+
 	statuses = buildStatuses(g)
 
+	c.routeSnapshots.Store(buildRouteSnapshots(g, statuses.HTTPRouteStatuses))
+
 	return true, conf, statuses
 }
+
+// GetRouteSnapshot returns the snapshot of the status of the HTTPRoute with the namespaced name computed during
+// the latest call to Process. If the HTTPRoute was not processed, the exists return argument is false.
+// GetRouteSnapshot is safe for concurrent use and doesn't wait for a running call to Process, which means it returns
+// the snapshot of the previous call until the running one finishes. The snapshot is shared between the callers
+// and must not be modified.
+func (c *ChangeProcessorImpl) GetRouteSnapshot(nsname types.NamespacedName) (snapshot RouteSnapshot, exists bool) {
+	snapshots, _ := c.routeSnapshots.Load().(routeSnapshots)
+
+	snapshot, exists = snapshots[nsname]
+	return snapshot, exists
+}
+
+// drainingEndpointsExpired returns true if the grace period of any draining endpoint has expired by now.
+func (c *ChangeProcessorImpl) drainingEndpointsExpired(now time.Time) bool {
+	expiry, exists := c.drainer.nextExpiry()
+	return exists && !now.Before(expiry)
+}
+
+// scheduleProcessing schedules a call to RequestProcessing for when the grace period of the next draining endpoint
+// expires. It replaces the previously scheduled call.
+func (c *ChangeProcessorImpl) scheduleProcessing(now time.Time) {
+	if c.processingTimer != nil {
+		c.processingTimer.Stop()
+		c.processingTimer = nil
+	}
+
+	expiry, exists := c.drainer.nextExpiry()
+	if !exists || c.cfg.RequestProcessing == nil {
+		return
+	}
+
+	c.processingTimer = time.AfterFunc(expiry.Sub(now), c.cfg.RequestProcessing)
+}
+
+// logDiff logs the differences between the latest and the current Graph and Upstreams at the debug level.
+// The differences are not computed if the debug level is not enabled.
+func (c *ChangeProcessorImpl) logDiff(g *graph.Graph, upstreams []dataplane.Upstream) {
+	defer func() {
+		c.latestGraph = g
+		c.latestUpstreams = upstreams
+	}()
+
+	logger := c.cfg.Logger.V(1)
+	if !logger.Enabled() {
+		return
+	}
+
+	diff := diffGraphs(c.latestGraph, g)
+	diff = append(diff, diffUpstreams(c.latestUpstreams, upstreams)...)
+
+	logger.Info("Rebuilt Graph", "diff", diff)
+}
@@ -2,10 +2,14 @@ package state_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sort"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	apiv1 "k8s.io/api/core/v1"
 	discoveryV1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -17,12 +21,15 @@ import (
 
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/manager/index"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/metrics"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/conditions"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/relationship"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/relationship/relationshipfakes"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/resolver"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/resolver/resolverfakes"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/secrets/secretsfakes"
 )
 
@@ -190,7 +197,7 @@ var _ = Describe("ChangeProcessor", func() {
 				GatewayCtlrName:      controllerName,
 				GatewayClassName:     gcName,
 				SecretMemoryManager:  fakeSecretMemoryMgr,
-				RelationshipCapturer: relationship.NewCapturerImpl(),
+				RelationshipCapturer: relationship.NewCapturerImpl(false),
 				Logger:               zap.New(),
 			})
 
@@ -250,6 +257,31 @@ var _ = Describe("ChangeProcessor", func() {
 				ExpectWithOffset(1, helpers.Diff(expected, result)).To(BeEmpty())
 			}
 
+			// createHR1StatusGatewayNotFound creates the status of hr1Updated after its Gateway gateway-1 is deleted.
+			// baseConds are the conditions that don't depend on the parentRef.
+			createHR1StatusGatewayNotFound := func(baseConds ...conditions.Condition) state.HTTPRouteStatus {
+				conds := append(
+					baseConds,
+					conditions.NewRouteNoMatchingParent("Gateway is not found for this parent ref"),
+				)
+
+				return state.HTTPRouteStatus{
+					ObservedGeneration: hr1Updated.Generation,
+					ParentStatuses: []state.ParentStatus{
+						{
+							GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
+							SectionName:   "listener-80-1",
+							Conditions:    conds,
+						},
+						{
+							GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
+							SectionName:   "listener-443-1",
+							Conditions:    conds,
+						},
+					},
+				}
+			}
+
 			When("no upsert has occurred", func() {
 				It("returns empty configuration and statuses", func() {
 					changed, conf, statuses := processor.Process(context.TODO())
@@ -261,19 +293,41 @@ var _ = Describe("ChangeProcessor", func() {
 			When("GatewayClass doesn't exist", func() {
 				When("Gateways don't exist", func() {
 					When("the first HTTPRoute is upserted", func() {
-						It("returns empty configuration and statuses", func() {
+						It("returns empty configuration and statuses with missing parents", func() {
 							processor.CaptureUpsertChange(hr1)
 
 							expectedConf := dataplane.Configuration{}
+
+							// NoMatchingParent overrides the default Accepted condition
+							expectedConds := []conditions.Condition{
+								conditions.NewTODO("GatewayClass is invalid or doesn't exist"),
+								conditions.NewRouteNoMatchingParent("Gateway is not found for this parent ref"),
+							}
 							expectedStatuses := state.Statuses{
 								IgnoredGatewayStatuses: map[types.NamespacedName]state.IgnoredGatewayStatus{},
-								HTTPRouteStatuses:      map[types.NamespacedName]state.HTTPRouteStatus{},
+								HTTPRouteStatuses: map[types.NamespacedName]state.HTTPRouteStatus{
+									{Namespace: "test", Name: "hr-1"}: {
+										ObservedGeneration: hr1.Generation,
+										ParentStatuses: []state.ParentStatus{
+											{
+												GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
+												SectionName:   "listener-80-1",
+												Conditions:    expectedConds,
+											},
+											{
+												GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
+												SectionName:   "listener-443-1",
+												Conditions:    expectedConds,
+											},
+										},
+									},
+								},
 							}
 
 							changed, conf, statuses := processor.Process(context.TODO())
 							Expect(changed).To(BeTrue())
 							Expect(helpers.Diff(expectedConf, conf)).To(BeEmpty())
-							Expect(helpers.Diff(expectedStatuses, statuses)).To(BeEmpty())
+							assertStatuses(expectedStatuses, statuses)
 						})
 					})
 				})
@@ -307,14 +361,18 @@ var _ = Describe("ChangeProcessor", func() {
 							HTTPRouteStatuses: map[types.NamespacedName]state.HTTPRouteStatus{
 								{Namespace: "test", Name: "hr-1"}: {
 									ObservedGeneration: hr1.Generation,
-									ParentStatuses: map[string]state.ParentStatus{
-										"listener-80-1": {
+									ParentStatuses: []state.ParentStatus{
+										{
+											GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
+											SectionName:   "listener-80-1",
 											Conditions: append(
 												conditions.NewDefaultRouteConditions(),
 												conditions.NewTODO("GatewayClass is invalid or doesn't exist"),
 											),
 										},
-										"listener-443-1": {
+										{
+											GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
+											SectionName:   "listener-443-1",
 											Conditions: append(
 												conditions.NewDefaultRouteConditions(),
 												conditions.NewTODO("GatewayClass is invalid or doesn't exist"),
@@ -343,6 +401,7 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 							{
 								Hostname: "foo.example.com",
+								Listener: "listener-80-1",
 								PathRules: []dataplane.PathRule{
 									{
 										Path: "/",
@@ -364,6 +423,7 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 							{
 								Hostname: "foo.example.com",
+								Listener: "listener-443-1",
 								SSL:      &dataplane.SSL{CertificatePath: certificatePath},
 								PathRules: []dataplane.PathRule{
 									{
@@ -381,12 +441,14 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 							{
 								Hostname: "~^",
+								Listener: "listener-443-1",
 								SSL:      &dataplane.SSL{CertificatePath: certificatePath},
 							},
 						},
 						BackendGroups: []graph.BackendGroup{
 							hr1Group,
 						},
+						Gateway: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
 					}
 
 					expectedStatuses := state.Statuses{
@@ -412,12 +474,16 @@ var _ = Describe("ChangeProcessor", func() {
 						HTTPRouteStatuses: map[types.NamespacedName]state.HTTPRouteStatus{
 							{Namespace: "test", Name: "hr-1"}: {
 								ObservedGeneration: hr1.Generation,
-								ParentStatuses: map[string]state.ParentStatus{
-									"listener-80-1": {
-										Conditions: conditions.NewDefaultRouteConditions(),
+								ParentStatuses: []state.ParentStatus{
+									{
+										GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
+										SectionName:   "listener-80-1",
+										Conditions:    conditions.NewDefaultRouteConditions(),
 									},
-									"listener-443-1": {
-										Conditions: conditions.NewDefaultRouteConditions(),
+									{
+										GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
+										SectionName:   "listener-443-1",
+										Conditions:    conditions.NewDefaultRouteConditions(),
 									},
 								},
 							},
@@ -453,6 +519,7 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 							{
 								Hostname: "foo.example.com",
+								Listener: "listener-80-1",
 								PathRules: []dataplane.PathRule{
 									{
 										Path: "/",
@@ -474,6 +541,7 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 							{
 								Hostname: "foo.example.com",
+								Listener: "listener-443-1",
 								SSL:      &dataplane.SSL{CertificatePath: certificatePath},
 								PathRules: []dataplane.PathRule{
 									{
@@ -491,12 +559,14 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 							{
 								Hostname: "~^",
+								Listener: "listener-443-1",
 								SSL:      &dataplane.SSL{CertificatePath: certificatePath},
 							},
 						},
 						BackendGroups: []graph.BackendGroup{
 							hr1Group,
 						},
+						Gateway: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
 					}
 					expectedStatuses := state.Statuses{
 						GatewayClassStatus: &state.GatewayClassStatus{
@@ -521,12 +591,16 @@ var _ = Describe("ChangeProcessor", func() {
 						HTTPRouteStatuses: map[types.NamespacedName]state.HTTPRouteStatus{
 							{Namespace: "test", Name: "hr-1"}: {
 								ObservedGeneration: hr1Updated.Generation,
-								ParentStatuses: map[string]state.ParentStatus{
-									"listener-80-1": {
-										Conditions: conditions.NewDefaultRouteConditions(),
+								ParentStatuses: []state.ParentStatus{
+									{
+										GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
+										SectionName:   "listener-80-1",
+										Conditions:    conditions.NewDefaultRouteConditions(),
 									},
-									"listener-443-1": {
-										Conditions: conditions.NewDefaultRouteConditions(),
+									{
+										GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
+										SectionName:   "listener-443-1",
+										Conditions:    conditions.NewDefaultRouteConditions(),
 									},
 								},
 							},
@@ -563,6 +637,7 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 							{
 								Hostname: "foo.example.com",
+								Listener: "listener-80-1",
 								PathRules: []dataplane.PathRule{
 									{
 										Path: "/",
@@ -584,6 +659,7 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 							{
 								Hostname: "foo.example.com",
+								Listener: "listener-443-1",
 								SSL:      &dataplane.SSL{CertificatePath: certificatePath},
 								PathRules: []dataplane.PathRule{
 									{
@@ -601,12 +677,14 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 							{
 								Hostname: "~^",
+								Listener: "listener-443-1",
 								SSL:      &dataplane.SSL{CertificatePath: certificatePath},
 							},
 						},
 						BackendGroups: []graph.BackendGroup{
 							hr1Group,
 						},
+						Gateway: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
 					}
 					expectedStatuses := state.Statuses{
 						GatewayClassStatus: &state.GatewayClassStatus{
@@ -631,12 +709,16 @@ var _ = Describe("ChangeProcessor", func() {
 						HTTPRouteStatuses: map[types.NamespacedName]state.HTTPRouteStatus{
 							{Namespace: "test", Name: "hr-1"}: {
 								ObservedGeneration: hr1Updated.Generation,
-								ParentStatuses: map[string]state.ParentStatus{
-									"listener-80-1": {
-										Conditions: conditions.NewDefaultRouteConditions(),
+								ParentStatuses: []state.ParentStatus{
+									{
+										GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
+										SectionName:   "listener-80-1",
+										Conditions:    conditions.NewDefaultRouteConditions(),
 									},
-									"listener-443-1": {
-										Conditions: conditions.NewDefaultRouteConditions(),
+									{
+										GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
+										SectionName:   "listener-443-1",
+										Conditions:    conditions.NewDefaultRouteConditions(),
 									},
 								},
 							},
@@ -672,6 +754,7 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 							{
 								Hostname: "foo.example.com",
+								Listener: "listener-80-1",
 								PathRules: []dataplane.PathRule{
 									{
 										Path: "/",
@@ -693,6 +776,7 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 							{
 								Hostname: "foo.example.com",
+								Listener: "listener-443-1",
 								SSL:      &dataplane.SSL{CertificatePath: certificatePath},
 								PathRules: []dataplane.PathRule{
 									{
@@ -710,12 +794,14 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 							{
 								Hostname: "~^",
+								Listener: "listener-443-1",
 								SSL:      &dataplane.SSL{CertificatePath: certificatePath},
 							},
 						},
 						BackendGroups: []graph.BackendGroup{
 							hr1Group,
 						},
+						Gateway: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
 					}
 					expectedStatuses := state.Statuses{
 						GatewayClassStatus: &state.GatewayClassStatus{
@@ -740,12 +826,16 @@ var _ = Describe("ChangeProcessor", func() {
 						HTTPRouteStatuses: map[types.NamespacedName]state.HTTPRouteStatus{
 							{Namespace: "test", Name: "hr-1"}: {
 								ObservedGeneration: hr1Updated.Generation,
-								ParentStatuses: map[string]state.ParentStatus{
-									"listener-80-1": {
-										Conditions: conditions.NewDefaultRouteConditions(),
+								ParentStatuses: []state.ParentStatus{
+									{
+										GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
+										SectionName:   "listener-80-1",
+										Conditions:    conditions.NewDefaultRouteConditions(),
 									},
-									"listener-443-1": {
-										Conditions: conditions.NewDefaultRouteConditions(),
+									{
+										GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
+										SectionName:   "listener-443-1",
+										Conditions:    conditions.NewDefaultRouteConditions(),
 									},
 								},
 							},
@@ -778,6 +868,7 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 							{
 								Hostname: "foo.example.com",
+								Listener: "listener-80-1",
 								PathRules: []dataplane.PathRule{
 									{
 										Path: "/",
@@ -799,6 +890,7 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 							{
 								Hostname: "foo.example.com",
+								Listener: "listener-443-1",
 								PathRules: []dataplane.PathRule{
 									{
 										Path: "/",
@@ -818,12 +910,14 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 							{
 								Hostname: "~^",
+								Listener: "listener-443-1",
 								SSL:      &dataplane.SSL{CertificatePath: certificatePath},
 							},
 						},
 						BackendGroups: []graph.BackendGroup{
 							hr1Group,
 						},
+						Gateway: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
 					}
 					expectedStatuses := state.Statuses{
 						GatewayClassStatus: &state.GatewayClassStatus{
@@ -852,12 +946,16 @@ var _ = Describe("ChangeProcessor", func() {
 						HTTPRouteStatuses: map[types.NamespacedName]state.HTTPRouteStatus{
 							{Namespace: "test", Name: "hr-1"}: {
 								ObservedGeneration: hr1Updated.Generation,
-								ParentStatuses: map[string]state.ParentStatus{
-									"listener-80-1": {
-										Conditions: conditions.NewDefaultRouteConditions(),
+								ParentStatuses: []state.ParentStatus{
+									{
+										GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
+										SectionName:   "listener-80-1",
+										Conditions:    conditions.NewDefaultRouteConditions(),
 									},
-									"listener-443-1": {
-										Conditions: conditions.NewDefaultRouteConditions(),
+									{
+										GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
+										SectionName:   "listener-443-1",
+										Conditions:    conditions.NewDefaultRouteConditions(),
 									},
 								},
 							},
@@ -881,6 +979,7 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 							{
 								Hostname: "foo.example.com",
+								Listener: "listener-80-1",
 								PathRules: []dataplane.PathRule{
 									{
 										Path: "/",
@@ -902,6 +1001,7 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 							{
 								Hostname: "foo.example.com",
+								Listener: "listener-443-1",
 								SSL:      &dataplane.SSL{CertificatePath: certificatePath},
 								PathRules: []dataplane.PathRule{
 									{
@@ -919,12 +1019,14 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 							{
 								Hostname: "~^",
+								Listener: "listener-443-1",
 								SSL:      &dataplane.SSL{CertificatePath: certificatePath},
 							},
 						},
 						BackendGroups: []graph.BackendGroup{
 							hr1Group,
 						},
+						Gateway: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
 					}
 					expectedStatuses := state.Statuses{
 						GatewayClassStatus: &state.GatewayClassStatus{
@@ -953,25 +1055,33 @@ var _ = Describe("ChangeProcessor", func() {
 						HTTPRouteStatuses: map[types.NamespacedName]state.HTTPRouteStatus{
 							{Namespace: "test", Name: "hr-1"}: {
 								ObservedGeneration: hr1Updated.Generation,
-								ParentStatuses: map[string]state.ParentStatus{
-									"listener-80-1": {
-										Conditions: conditions.NewDefaultRouteConditions(),
+								ParentStatuses: []state.ParentStatus{
+									{
+										GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
+										SectionName:   "listener-80-1",
+										Conditions:    conditions.NewDefaultRouteConditions(),
 									},
-									"listener-443-1": {
-										Conditions: conditions.NewDefaultRouteConditions(),
+									{
+										GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway-1"},
+										SectionName:   "listener-443-1",
+										Conditions:    conditions.NewDefaultRouteConditions(),
 									},
 								},
 							},
 							{Namespace: "test", Name: "hr-2"}: {
 								ObservedGeneration: hr2.Generation,
-								ParentStatuses: map[string]state.ParentStatus{
-									"listener-80-1": {
+								ParentStatuses: []state.ParentStatus{
+									{
+										GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway-2"},
+										SectionName:   "listener-80-1",
 										Conditions: append(
 											conditions.NewDefaultRouteConditions(),
 											conditions.NewTODO("Gateway is ignored"),
 										),
 									},
-									"listener-443-1": {
+									{
+										GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway-2"},
+										SectionName:   "listener-443-1",
 										Conditions: append(
 											conditions.NewDefaultRouteConditions(),
 											conditions.NewTODO("Gateway is ignored"),
@@ -1002,6 +1112,7 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 							{
 								Hostname: "bar.example.com",
+								Listener: "listener-80-1",
 								PathRules: []dataplane.PathRule{
 									{
 										Path: "/",
@@ -1023,6 +1134,7 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 							{
 								Hostname: "bar.example.com",
+								Listener: "listener-443-1",
 								SSL:      &dataplane.SSL{CertificatePath: certificatePath},
 								PathRules: []dataplane.PathRule{
 									{
@@ -1040,12 +1152,14 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 							{
 								Hostname: "~^",
+								Listener: "listener-443-1",
 								SSL:      &dataplane.SSL{CertificatePath: certificatePath},
 							},
 						},
 						BackendGroups: []graph.BackendGroup{
 							hr2Group,
 						},
+						Gateway: types.NamespacedName{Namespace: "test", Name: "gateway-2"},
 					}
 					expectedStatuses := state.Statuses{
 						GatewayClassStatus: &state.GatewayClassStatus{
@@ -1068,14 +1182,19 @@ var _ = Describe("ChangeProcessor", func() {
 						},
 						IgnoredGatewayStatuses: map[types.NamespacedName]state.IgnoredGatewayStatus{},
 						HTTPRouteStatuses: map[types.NamespacedName]state.HTTPRouteStatus{
+							{Namespace: "test", Name: "hr-1"}: createHR1StatusGatewayNotFound(),
 							{Namespace: "test", Name: "hr-2"}: {
 								ObservedGeneration: hr2.Generation,
-								ParentStatuses: map[string]state.ParentStatus{
-									"listener-80-1": {
-										Conditions: conditions.NewDefaultRouteConditions(),
+								ParentStatuses: []state.ParentStatus{
+									{
+										GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway-2"},
+										SectionName:   "listener-80-1",
+										Conditions:    conditions.NewDefaultRouteConditions(),
 									},
-									"listener-443-1": {
-										Conditions: conditions.NewDefaultRouteConditions(),
+									{
+										GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway-2"},
+										SectionName:   "listener-443-1",
+										Conditions:    conditions.NewDefaultRouteConditions(),
 									},
 								},
 							},
@@ -1107,9 +1226,11 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 							{
 								Hostname: "~^",
+								Listener: "listener-443-1",
 								SSL:      &dataplane.SSL{CertificatePath: certificatePath},
 							},
 						},
+						Gateway: types.NamespacedName{Namespace: "test", Name: "gateway-2"},
 					}
 					expectedStatuses := state.Statuses{
 						GatewayClassStatus: &state.GatewayClassStatus{
@@ -1131,7 +1252,9 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 						},
 						IgnoredGatewayStatuses: map[types.NamespacedName]state.IgnoredGatewayStatus{},
-						HTTPRouteStatuses:      map[types.NamespacedName]state.HTTPRouteStatus{},
+						HTTPRouteStatuses: map[types.NamespacedName]state.HTTPRouteStatus{
+							{Namespace: "test", Name: "hr-1"}: createHR1StatusGatewayNotFound(),
+						},
 					}
 
 					changed, conf, statuses := processor.Process(context.TODO())
@@ -1170,7 +1293,11 @@ var _ = Describe("ChangeProcessor", func() {
 							},
 						},
 						IgnoredGatewayStatuses: map[types.NamespacedName]state.IgnoredGatewayStatus{},
-						HTTPRouteStatuses:      map[types.NamespacedName]state.HTTPRouteStatus{},
+						HTTPRouteStatuses: map[types.NamespacedName]state.HTTPRouteStatus{
+							{Namespace: "test", Name: "hr-1"}: createHR1StatusGatewayNotFound(
+								conditions.NewTODO("GatewayClass is invalid or doesn't exist"),
+							),
+						},
 					}
 
 					changed, conf, statuses := processor.Process(context.TODO())
@@ -1180,7 +1307,7 @@ var _ = Describe("ChangeProcessor", func() {
 				})
 			})
 			When("the second Gateway is deleted", func() {
-				It("returns empty configuration and empty statuses", func() {
+				It("returns empty configuration and statuses with missing parents", func() {
 					processor.CaptureDeleteChange(
 						&v1beta1.Gateway{},
 						types.NamespacedName{Namespace: "test", Name: "gateway-2"},
@@ -1189,7 +1316,11 @@ var _ = Describe("ChangeProcessor", func() {
 					expectedConf := dataplane.Configuration{}
 					expectedStatuses := state.Statuses{
 						IgnoredGatewayStatuses: map[types.NamespacedName]state.IgnoredGatewayStatus{},
-						HTTPRouteStatuses:      map[types.NamespacedName]state.HTTPRouteStatus{},
+						HTTPRouteStatuses: map[types.NamespacedName]state.HTTPRouteStatus{
+							{Namespace: "test", Name: "hr-1"}: createHR1StatusGatewayNotFound(
+								conditions.NewTODO("GatewayClass is invalid or doesn't exist"),
+							),
+						},
 					}
 
 					changed, conf, statuses := processor.Process(context.TODO())
@@ -1552,6 +1683,690 @@ var _ = Describe("ChangeProcessor", func() {
 		})
 	})
 
+	Describe("Process Secrets referenced by Gateways", Ordered, func() {
+		var (
+			processor                              state.ChangeProcessor
+			gw                                     *v1beta1.Gateway
+			secret, secretUpdated, unrelatedSecret *apiv1.Secret
+			gwNsName, secretNsName                 types.NamespacedName
+		)
+
+		BeforeAll(func() {
+			fakeSecretMemoryMgr := &secretsfakes.FakeSecretDiskMemoryManager{}
+			fakeSecretMemoryMgr.RequestReturns("/etc/nginx/secrets/secret", nil)
+
+			processor = state.NewChangeProcessorImpl(state.ChangeProcessorConfig{
+				GatewayCtlrName:      "test.controller",
+				GatewayClassName:     "my-class",
+				SecretMemoryManager:  fakeSecretMemoryMgr,
+				RelationshipCapturer: relationship.NewCapturerImpl(false),
+				Logger:               zap.New(),
+			})
+
+			gw = createGatewayWithTLSListener("gateway")
+			gwNsName = types.NamespacedName{Namespace: gw.Namespace, Name: gw.Name}
+
+			secret = &apiv1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:       "test",
+					Name:            "secret",
+					ResourceVersion: "1",
+				},
+			}
+			secretNsName = types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}
+
+			// Secrets don't have a generation. A rotated Secret only has an updated resource version.
+			secretUpdated = secret.DeepCopy()
+			secretUpdated.ResourceVersion = "2"
+
+			unrelatedSecret = &apiv1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "test",
+					Name:      "unrelated",
+				},
+			}
+		})
+
+		testUpsertTriggersChange := func(obj client.Object, expChanged bool) {
+			processor.CaptureUpsertChange(obj)
+			changed, _, _ := processor.Process(context.TODO())
+			Expect(changed).To(Equal(expChanged))
+		}
+
+		testDeleteTriggersChange := func(obj client.Object, nsname types.NamespacedName, expChanged bool) {
+			processor.CaptureDeleteChange(obj, nsname)
+			changed, _, _ := processor.Process(context.TODO())
+			Expect(changed).To(Equal(expChanged))
+		}
+
+		When("a Gateway with a TLS listener is added", func() {
+			It("should trigger a change", func() {
+				testUpsertTriggersChange(gw, true)
+			})
+		})
+		When("the referenced Secret is added", func() {
+			It("should trigger a change", func() {
+				testUpsertTriggersChange(secret, true)
+			})
+		})
+		When("the referenced Secret is rotated", func() {
+			It("should trigger a change", func() {
+				testUpsertTriggersChange(secretUpdated, true)
+			})
+		})
+		When("a Secret that is not referenced by any Gateway is added", func() {
+			It("should not trigger a change", func() {
+				testUpsertTriggersChange(unrelatedSecret, false)
+			})
+		})
+		When("a Secret that is not referenced by any Gateway is deleted", func() {
+			It("should not trigger a change", func() {
+				testDeleteTriggersChange(
+					&apiv1.Secret{},
+					types.NamespacedName{Namespace: unrelatedSecret.Namespace, Name: unrelatedSecret.Name},
+					false,
+				)
+			})
+		})
+		When("the referenced Secret is deleted", func() {
+			It("should trigger a change", func() {
+				testDeleteTriggersChange(&apiv1.Secret{}, secretNsName, true)
+			})
+		})
+		When("the Gateway is deleted", func() {
+			It("should trigger a change", func() {
+				testDeleteTriggersChange(&v1beta1.Gateway{}, gwNsName, true)
+			})
+		})
+		When("the Secret is recreated after the Gateway is deleted", func() {
+			It("should not trigger a change", func() {
+				testUpsertTriggersChange(secret, false)
+			})
+		})
+	})
+
+	Describe("Process disabled listeners of Gateways", Ordered, func() {
+		var (
+			processor                                      state.ChangeProcessor
+			gw, gwDisabled, gwDisabledUpdated, gwReEnabled *v1beta1.Gateway
+			gwMaintenance                                  *v1beta1.Gateway
+		)
+
+		BeforeAll(func() {
+			processor = state.NewChangeProcessorImpl(state.ChangeProcessorConfig{
+				GatewayCtlrName:      "test.controller",
+				GatewayClassName:     "my-class",
+				SecretMemoryManager:  &secretsfakes.FakeSecretDiskMemoryManager{},
+				RelationshipCapturer: relationship.NewCapturerImpl(false),
+				Logger:               zap.New(),
+			})
+
+			gw = createGatewayWithTLSListener("gateway")
+
+			// Annotations don't update the generation.
+			gwDisabled = gw.DeepCopy()
+			gwDisabled.Annotations = map[string]string{graph.DisabledListenersAnnotation: "listener-443-1"}
+
+			gwDisabledUpdated = gwDisabled.DeepCopy()
+			gwDisabledUpdated.Labels = map[string]string{"app": "gateway"}
+
+			gwReEnabled = gw.DeepCopy()
+
+			gwMaintenance = gw.DeepCopy()
+			gwMaintenance.Annotations = map[string]string{dataplane.MaintenanceAnnotation: "true"}
+		})
+
+		testUpsertTriggersChange := func(obj client.Object, expChanged bool) {
+			processor.CaptureUpsertChange(obj)
+			changed, _, _ := processor.Process(context.TODO())
+			Expect(changed).To(Equal(expChanged))
+		}
+
+		When("a Gateway is added", func() {
+			It("should trigger a change", func() {
+				testUpsertTriggersChange(gw, true)
+			})
+		})
+		When("a listener of the Gateway is disabled", func() {
+			It("should trigger a change", func() {
+				testUpsertTriggersChange(gwDisabled, true)
+			})
+		})
+		When("the Gateway is updated without changing the disabled listeners", func() {
+			It("should not trigger a change", func() {
+				testUpsertTriggersChange(gwDisabledUpdated, false)
+			})
+		})
+		When("the listener of the Gateway is re-enabled", func() {
+			It("should trigger a change", func() {
+				testUpsertTriggersChange(gwReEnabled, true)
+			})
+		})
+		When("the maintenance mode of the Gateway is enabled", func() {
+			It("should trigger a change", func() {
+				testUpsertTriggersChange(gwMaintenance, true)
+			})
+		})
+		When("the maintenance mode of the Gateway is disabled", func() {
+			It("should trigger a change", func() {
+				testUpsertTriggersChange(gwReEnabled, true)
+			})
+		})
+	})
+
+	Describe("Getting route snapshots", Ordered, func() {
+		var (
+			processor         *state.ChangeProcessorImpl
+			gc                *v1beta1.GatewayClass
+			gw                *v1beta1.Gateway
+			hr, hrUpdated     *v1beta1.HTTPRoute
+			hrNsName          types.NamespacedName
+			fakeSecretManager *secretsfakes.FakeSecretDiskMemoryManager
+		)
+
+		BeforeAll(func() {
+			fakeSecretManager = &secretsfakes.FakeSecretDiskMemoryManager{}
+			fakeSecretManager.RequestReturns(certificatePath, nil)
+
+			processor = state.NewChangeProcessorImpl(state.ChangeProcessorConfig{
+				GatewayCtlrName:      controllerName,
+				GatewayClassName:     gcName,
+				SecretMemoryManager:  fakeSecretManager,
+				RelationshipCapturer: relationship.NewCapturerImpl(false),
+				Logger:               zap.New(),
+			})
+
+			gc = &v1beta1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       gcName,
+					Generation: 1,
+				},
+				Spec: v1beta1.GatewayClassSpec{
+					ControllerName: controllerName,
+				},
+			}
+			gw = createGatewayWithTLSListener("gateway")
+			hr = createRoute("hr", "gateway", "foo.example.com")
+			hrNsName = client.ObjectKeyFromObject(hr)
+
+			hrUpdated = hr.DeepCopy()
+			hrUpdated.Generation++
+		})
+
+		When("no changes are processed", func() {
+			It("doesn't return a snapshot", func() {
+				_, exists := processor.GetRouteSnapshot(hrNsName)
+				Expect(exists).To(BeFalse())
+			})
+		})
+		When("the HTTPRoute is processed", func() {
+			It("returns the snapshot of the latest computed status", func() {
+				processor.CaptureUpsertChange(gc)
+				processor.CaptureUpsertChange(gw)
+				processor.CaptureUpsertChange(hr)
+
+				changed, _, statuses := processor.Process(context.TODO())
+				Expect(changed).To(BeTrue())
+
+				snapshot, exists := processor.GetRouteSnapshot(hrNsName)
+				Expect(exists).To(BeTrue())
+				Expect(snapshot.ObservedGeneration).To(Equal(hr.Generation))
+				Expect(snapshot.ParentStatuses).To(Equal(statuses.HTTPRouteStatuses[hrNsName].ParentStatuses))
+				Expect(snapshot.BackendGroups).To(HaveLen(1))
+			})
+		})
+		When("the HTTPRoute is updated", func() {
+			It("returns the snapshot of the updated HTTPRoute", func() {
+				processor.CaptureUpsertChange(hrUpdated)
+
+				changed, _, _ := processor.Process(context.TODO())
+				Expect(changed).To(BeTrue())
+
+				snapshot, exists := processor.GetRouteSnapshot(hrNsName)
+				Expect(exists).To(BeTrue())
+				Expect(snapshot.ObservedGeneration).To(Equal(hrUpdated.Generation))
+			})
+		})
+		When("the HTTPRoute is deleted", func() {
+			It("doesn't return a snapshot", func() {
+				processor.CaptureDeleteChange(&v1beta1.HTTPRoute{}, hrNsName)
+
+				changed, _, _ := processor.Process(context.TODO())
+				Expect(changed).To(BeTrue())
+
+				_, exists := processor.GetRouteSnapshot(hrNsName)
+				Expect(exists).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("Process Service port changes", Ordered, func() {
+		var (
+			processor     state.ChangeProcessor
+			gc            *v1beta1.GatewayClass
+			gw            *v1beta1.Gateway
+			hr            *v1beta1.HTTPRoute
+			hrNsName      types.NamespacedName
+			svc, svcPorts *apiv1.Service
+		)
+
+		BeforeAll(func() {
+			fakeResolver := &resolverfakes.FakeServiceResolver{}
+			fakeResolver.ResolveReturns([]resolver.Endpoint{{Address: "10.0.0.1", Port: 8080}}, nil)
+
+			processor = state.NewChangeProcessorImpl(state.ChangeProcessorConfig{
+				GatewayCtlrName:      controllerName,
+				GatewayClassName:     gcName,
+				SecretMemoryManager:  &secretsfakes.FakeSecretDiskMemoryManager{},
+				ServiceResolver:      fakeResolver,
+				RelationshipCapturer: relationship.NewCapturerImpl(false),
+				Logger:               zap.New(),
+			})
+
+			gc = &v1beta1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{Name: gcName},
+				Spec: v1beta1.GatewayClassSpec{
+					ControllerName: controllerName,
+				},
+			}
+			gw = createGatewayWithTLSListener("gateway")
+
+			ref := createBackendRef(
+				(*v1beta1.Kind)(helpers.GetStringPointer("Service")),
+				"svc",
+				(*v1beta1.Namespace)(helpers.GetStringPointer("test")),
+			)
+			ref.Port = (*v1beta1.PortNumber)(helpers.GetInt32Pointer(80))
+
+			hr = createRoute("hr", "gateway", "foo.example.com", ref)
+			hrNsName = client.ObjectKeyFromObject(hr)
+
+			svc = &apiv1.Service{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "svc"},
+				Spec: apiv1.ServiceSpec{
+					Ports: []apiv1.ServicePort{{Port: 8080}},
+				},
+			}
+
+			svcPorts = svc.DeepCopy()
+			svcPorts.Spec.Ports = append(svcPorts.Spec.Ports, apiv1.ServicePort{Name: "http", Port: 80})
+		})
+
+		findResolvedRefsCondition := func(statuses state.Statuses) *conditions.Condition {
+			for _, ps := range statuses.HTTPRouteStatuses[hrNsName].ParentStatuses {
+				for _, c := range ps.Conditions {
+					if c.Type == string(v1beta1.RouteConditionResolvedRefs) {
+						c := c
+						return &c
+					}
+				}
+			}
+
+			return nil
+		}
+
+		findUpstream := func(conf dataplane.Configuration) *dataplane.Upstream {
+			for _, up := range conf.Upstreams {
+				if up.Name == "test_svc_80" {
+					up := up
+					return &up
+				}
+			}
+
+			return nil
+		}
+
+		When("the Service doesn't have the port of the backendRef", func() {
+			It("reports the backendRef as unresolved", func() {
+				processor.CaptureUpsertChange(gc)
+				processor.CaptureUpsertChange(gw)
+				processor.CaptureUpsertChange(hr)
+				processor.CaptureUpsertChange(svc)
+
+				changed, conf, statuses := processor.Process(context.TODO())
+				Expect(changed).To(BeTrue())
+
+				cond := findResolvedRefsCondition(statuses)
+				Expect(cond).ToNot(BeNil())
+				Expect(cond.Reason).To(Equal(string(v1beta1.RouteReasonBackendNotFound)))
+				Expect(cond.Message).To(Equal("rule 0: the Service test/svc does not have the port 80"))
+
+				Expect(findUpstream(conf)).To(BeNil())
+			})
+		})
+		When("the port is added to the Service", func() {
+			It("resolves the backendRef", func() {
+				processor.CaptureUpsertChange(svcPorts)
+
+				changed, conf, statuses := processor.Process(context.TODO())
+				Expect(changed).To(BeTrue())
+
+				Expect(findResolvedRefsCondition(statuses)).To(BeNil())
+
+				up := findUpstream(conf)
+				Expect(up).ToNot(BeNil())
+				Expect(up.Endpoints).To(ConsistOf(resolver.Endpoint{Address: "10.0.0.1", Port: 8080}))
+			})
+		})
+		When("the port is removed from the Service", func() {
+			It("reports the backendRef as unresolved again", func() {
+				processor.CaptureUpsertChange(svc)
+
+				changed, conf, statuses := processor.Process(context.TODO())
+				Expect(changed).To(BeTrue())
+
+				Expect(findResolvedRefsCondition(statuses)).ToNot(BeNil())
+				Expect(findUpstream(conf)).To(BeNil())
+			})
+		})
+	})
+
+	Describe("Process Namespace label changes", Ordered, func() {
+		var (
+			processor                                   state.ChangeProcessor
+			gc                                          *v1beta1.GatewayClass
+			gw                                          *v1beta1.Gateway
+			hr                                          *v1beta1.HTTPRoute
+			nsApps, nsAppsProd, nsAppsProdTeam, nsOther *apiv1.Namespace
+		)
+
+		BeforeAll(func() {
+			processor = state.NewChangeProcessorImpl(state.ChangeProcessorConfig{
+				GatewayCtlrName:      controllerName,
+				GatewayClassName:     gcName,
+				SecretMemoryManager:  &secretsfakes.FakeSecretDiskMemoryManager{},
+				RelationshipCapturer: relationship.NewCapturerImpl(false),
+				Logger:               zap.New(),
+			})
+
+			gc = &v1beta1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       gcName,
+					Generation: 1,
+				},
+				Spec: v1beta1.GatewayClassSpec{
+					ControllerName: controllerName,
+				},
+			}
+
+			gw = createGateway("gateway")
+			gw.Spec.Listeners[0].AllowedRoutes = &v1beta1.AllowedRoutes{
+				Namespaces: &v1beta1.RouteNamespaces{
+					From: (*v1beta1.FromNamespaces)(helpers.GetStringPointer(string(v1beta1.NamespacesFromSelector))),
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"env": "prod"},
+					},
+				},
+			}
+
+			hr = createRoute("hr", "gateway", "foo.example.com")
+			hr.Namespace = "apps"
+
+			nsApps = &apiv1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "apps",
+				},
+			}
+
+			nsAppsProd = nsApps.DeepCopy()
+			nsAppsProd.Labels = map[string]string{"env": "prod"}
+
+			nsAppsProdTeam = nsAppsProd.DeepCopy()
+			nsAppsProdTeam.Labels["team"] = "coffee"
+
+			nsOther = &apiv1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "other",
+					Labels: map[string]string{"env": "dev"},
+				},
+			}
+		})
+
+		// isRouteAttached returns true if NGINX serves the hostname of the HTTPRoute.
+		isRouteAttached := func(conf dataplane.Configuration) bool {
+			for _, s := range conf.HTTPServers {
+				if s.Hostname == "foo.example.com" {
+					return true
+				}
+			}
+			return false
+		}
+
+		testUpsertTriggersChange := func(obj client.Object, expChanged bool, expAttached bool) {
+			processor.CaptureUpsertChange(obj)
+			changed, conf, _ := processor.Process(context.TODO())
+			Expect(changed).To(Equal(expChanged))
+			if changed {
+				Expect(isRouteAttached(conf)).To(Equal(expAttached))
+			}
+		}
+
+		When("the HTTPRoute is in a Namespace that the listener doesn't select", func() {
+			It("should not attach the HTTPRoute", func() {
+				processor.CaptureUpsertChange(gc)
+				processor.CaptureUpsertChange(gw)
+				processor.CaptureUpsertChange(hr)
+				testUpsertTriggersChange(nsApps, true, false)
+			})
+		})
+		When("the Namespace is relabeled to be selected by the listener", func() {
+			It("should trigger a change and attach the HTTPRoute", func() {
+				testUpsertTriggersChange(nsAppsProd, true, true)
+			})
+		})
+		When("the Namespace gets a label that doesn't change its selection", func() {
+			It("should not trigger a change", func() {
+				testUpsertTriggersChange(nsAppsProdTeam, false, true)
+			})
+		})
+		When("a Namespace that the listener doesn't select is added", func() {
+			It("should not trigger a change", func() {
+				testUpsertTriggersChange(nsOther, false, true)
+			})
+		})
+		When("the Namespace is relabeled to be no longer selected by the listener", func() {
+			It("should trigger a change and detach the HTTPRoute", func() {
+				testUpsertTriggersChange(nsApps, true, false)
+			})
+		})
+		When("the selected Namespace is deleted", func() {
+			It("should trigger a change", func() {
+				processor.CaptureUpsertChange(nsAppsProd)
+				changed, conf, _ := processor.Process(context.TODO())
+				Expect(changed).To(BeTrue())
+				Expect(isRouteAttached(conf)).To(BeTrue())
+
+				processor.CaptureDeleteChange(&apiv1.Namespace{}, types.NamespacedName{Name: "apps"})
+				changed, conf, _ = processor.Process(context.TODO())
+				Expect(changed).To(BeTrue())
+				Expect(isRouteAttached(conf)).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("Process the route option annotations of HTTPRoutes", Ordered, func() {
+		var (
+			processor                                              state.ChangeProcessor
+			hr, hrStreaming, hrRelabeled, hrUpstreamHost, hrWeight *v1beta1.HTTPRoute
+		)
+
+		BeforeAll(func() {
+			processor = state.NewChangeProcessorImpl(state.ChangeProcessorConfig{
+				GatewayCtlrName:      "test.controller",
+				GatewayClassName:     "my-class",
+				SecretMemoryManager:  &secretsfakes.FakeSecretDiskMemoryManager{},
+				RelationshipCapturer: relationship.NewCapturerImpl(false),
+				Logger:               zap.New(),
+			})
+
+			hr = createRoute("hr", "gateway", "foo.example.com")
+
+			// Annotations don't update the generation.
+			hrStreaming = hr.DeepCopy()
+			hrStreaming.Annotations = map[string]string{dataplane.StreamingAnnotation: "true"}
+
+			hrRelabeled = hrStreaming.DeepCopy()
+			hrRelabeled.Labels = map[string]string{"app": "route"}
+
+			hrUpstreamHost = hrRelabeled.DeepCopy()
+			hrUpstreamHost.Annotations[dataplane.PreserveHostAnnotation] = "false"
+
+			hrWeight = hrUpstreamHost.DeepCopy()
+			hrWeight.Annotations[graph.WeightAnnotation] = "80"
+		})
+
+		testUpsertTriggersChange := func(obj client.Object, expChanged bool) {
+			processor.CaptureUpsertChange(obj)
+			changed, _, _ := processor.Process(context.TODO())
+			Expect(changed).To(Equal(expChanged))
+		}
+
+		When("an HTTPRoute is added", func() {
+			It("should trigger a change", func() {
+				testUpsertTriggersChange(hr, true)
+			})
+		})
+		When("streaming is enabled for the HTTPRoute", func() {
+			It("should trigger a change", func() {
+				testUpsertTriggersChange(hrStreaming, true)
+			})
+		})
+		When("the HTTPRoute is updated without changing the streaming annotation", func() {
+			It("should not trigger a change", func() {
+				testUpsertTriggersChange(hrRelabeled, false)
+			})
+		})
+		When("the Host header is no longer preserved for the HTTPRoute", func() {
+			It("should trigger a change", func() {
+				testUpsertTriggersChange(hrUpstreamHost, true)
+			})
+		})
+		When("the weight of the HTTPRoute is set", func() {
+			It("should trigger a change", func() {
+				testUpsertTriggersChange(hrWeight, true)
+			})
+		})
+		When("streaming is disabled for the HTTPRoute", func() {
+			It("should trigger a change", func() {
+				testUpsertTriggersChange(hr, true)
+			})
+		})
+	})
+
+	Describe("Update metrics", Ordered, func() {
+		var (
+			processor        state.ChangeProcessor
+			metricsCollector *metrics.GraphCollector
+			gc               *v1beta1.GatewayClass
+			gw               *v1beta1.Gateway
+			hr1, hr2         *v1beta1.HTTPRoute
+			svc              *apiv1.Service
+		)
+
+		BeforeAll(func() {
+			metricsCollector = metrics.NewGraphCollector()
+
+			fakeResolver := &resolverfakes.FakeServiceResolver{}
+			fakeResolver.ResolveCalls(
+				func(_ context.Context, svc *apiv1.Service, _ int32) ([]resolver.Endpoint, error) {
+					if svc == nil {
+						return nil, errors.New("service doesn't exist")
+					}
+					return []resolver.Endpoint{{Address: "10.0.0.1", Port: 8080}}, nil
+				},
+			)
+
+			processor = state.NewChangeProcessorImpl(state.ChangeProcessorConfig{
+				GatewayCtlrName:      controllerName,
+				GatewayClassName:     gcName,
+				SecretMemoryManager:  &secretsfakes.FakeSecretDiskMemoryManager{},
+				ServiceResolver:      fakeResolver,
+				RelationshipCapturer: relationship.NewCapturerImpl(false),
+				Logger:               zap.New(),
+				MetricsCollector:     metricsCollector,
+			})
+
+			gc = &v1beta1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{Name: gcName},
+				Spec: v1beta1.GatewayClassSpec{
+					ControllerName: controllerName,
+				},
+			}
+			gw = createGatewayWithTLSListener("gateway")
+
+			createSvcRef := func(name string) v1beta1.HTTPBackendRef {
+				ref := createBackendRef(
+					(*v1beta1.Kind)(helpers.GetStringPointer("Service")),
+					v1beta1.ObjectName(name),
+					(*v1beta1.Namespace)(helpers.GetStringPointer("test")),
+				)
+				ref.Port = (*v1beta1.PortNumber)(helpers.GetInt32Pointer(80))
+
+				return ref
+			}
+
+			hr1 = createRoute("hr-1", "gateway", "foo.example.com", createSvcRef("svc"))
+			hr2 = createRoute("hr-2", "gateway", "bar.example.com", createSvcRef("missing-svc"))
+
+			svc = &apiv1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "svc"}}
+		})
+
+		expectMetrics := func(gateways, listeners, httpRoutes, upstreams int) {
+			expected := fmt.Sprintf(`
+# HELP nginx_kubernetes_gateway_gateways Number of the programmed Gateways
+# TYPE nginx_kubernetes_gateway_gateways gauge
+nginx_kubernetes_gateway_gateways %d
+# HELP nginx_kubernetes_gateway_listeners Number of the programmed listeners of the Gateways
+# TYPE nginx_kubernetes_gateway_listeners gauge
+nginx_kubernetes_gateway_listeners %d
+# HELP nginx_kubernetes_gateway_httproutes Number of the HTTPRoutes attached to the programmed listeners
+# TYPE nginx_kubernetes_gateway_httproutes gauge
+nginx_kubernetes_gateway_httproutes %d
+# HELP nginx_kubernetes_gateway_upstreams Number of the upstreams whose Services are resolved
+# TYPE nginx_kubernetes_gateway_upstreams gauge
+nginx_kubernetes_gateway_upstreams %d
+`, gateways, listeners, httpRoutes, upstreams)
+
+			ExpectWithOffset(1, testutil.CollectAndCompare(metricsCollector, strings.NewReader(expected))).To(Succeed())
+		}
+
+		When("the resources are added", func() {
+			It("counts the programmed resources", func() {
+				processor.CaptureUpsertChange(gc)
+				processor.CaptureUpsertChange(gw)
+				processor.CaptureUpsertChange(hr1)
+				processor.CaptureUpsertChange(hr2)
+				processor.CaptureUpsertChange(svc)
+
+				changed, _, _ := processor.Process(context.TODO())
+				Expect(changed).To(BeTrue())
+
+				// the upstream of hr2 is not counted, because its Service doesn't exist
+				expectMetrics(1, 2, 2, 1)
+			})
+		})
+		When("an HTTPRoute is deleted", func() {
+			It("doesn't count the HTTPRoute", func() {
+				processor.CaptureDeleteChange(&v1beta1.HTTPRoute{}, client.ObjectKeyFromObject(hr2))
+
+				changed, _, _ := processor.Process(context.TODO())
+				Expect(changed).To(BeTrue())
+
+				expectMetrics(1, 2, 1, 1)
+			})
+		})
+		When("the Gateway is deleted", func() {
+			It("doesn't count anything", func() {
+				processor.CaptureDeleteChange(&v1beta1.Gateway{}, client.ObjectKeyFromObject(gw))
+
+				changed, _, _ := processor.Process(context.TODO())
+				Expect(changed).To(BeTrue())
+
+				expectMetrics(0, 0, 0, 0)
+			})
+		})
+	})
+
 	Describe("Ensuring non-changing changes don't override previously changing changes", func() {
 		// Note: in these tests, we deliberately don't fully inspect the returned configuration and statuses
 		// -- this is done in 'Normal cases of processing changes'
@@ -1577,6 +2392,7 @@ var _ = Describe("ChangeProcessor", func() {
 				GatewayClassName:     "my-class",
 				SecretMemoryManager:  fakeSecretMemoryMgr,
 				RelationshipCapturer: fakeRelationshipCapturer,
+				Logger:               zap.New(),
 			})
 
 			gcNsName = types.NamespacedName{Name: "my-class"}
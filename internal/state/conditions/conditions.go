@@ -10,9 +10,61 @@ import (
 const (
 	// RouteReasonInvalidListener is used with the "Accepted" condition when the route references an invalid listener.
 	RouteReasonInvalidListener v1beta1.RouteConditionReason = "InvalidListener"
+	// RouteReasonExtensionRefNotFound is used with the "ResolvedRefs" condition when the route references
+	// a resource through an ExtensionRef filter that doesn't exist.
+	RouteReasonExtensionRefNotFound v1beta1.RouteConditionReason = "ExtensionRefNotFound"
+	// RouteReasonInvalidExtensionRef is used with the "ResolvedRefs" condition when the route references
+	// an invalid resource through an ExtensionRef filter.
+	RouteReasonInvalidExtensionRef v1beta1.RouteConditionReason = "InvalidExtensionRef"
+	// RouteReasonListenerDisabled is used with the "Accepted" condition when the route references a disabled listener.
+	RouteReasonListenerDisabled v1beta1.RouteConditionReason = "ListenerDisabled"
+	// RouteReasonTooManyRoutes is used with the "Accepted" condition when the route references a listener
+	// that already has the maximum number of attached routes.
+	RouteReasonTooManyRoutes v1beta1.RouteConditionReason = "TooManyRoutes"
+	// RouteConditionBackendWeights is an NKG-specific condition type that reports how NGINX distributes
+	// the traffic among the backendRefs of the rules of the route.
+	RouteConditionBackendWeights v1beta1.RouteConditionType = "BackendWeights"
+	// RouteReasonWeightsNormalized is used with the "BackendWeights" condition when every rule of the route with
+	// backendRefs has at least one backendRef with a nonzero weight.
+	RouteReasonWeightsNormalized v1beta1.RouteConditionReason = "WeightsNormalized"
+	// RouteReasonAllWeightsZero is used with the "BackendWeights" condition when all backendRefs of a rule
+	// of the route have zero weight.
+	RouteReasonAllWeightsZero v1beta1.RouteConditionReason = "AllWeightsZero"
+	// RouteReasonExternalNameNotAllowed is used with the "ResolvedRefs" condition when the route references
+	// an ExternalName Service whose external name is not allowed.
+	RouteReasonExternalNameNotAllowed v1beta1.RouteConditionReason = "ExternalNameNotAllowed"
+	// RouteReasonConflictingBackendFilters is used with the "ResolvedRefs" condition when the backendRefs of a rule
+	// that reference the same backend have different filters.
+	RouteReasonConflictingBackendFilters v1beta1.RouteConditionReason = "ConflictingBackendFilters"
+	// RouteConditionConflicted is an NKG-specific condition type that reports the matches of the route that conflict
+	// with the matches of other routes for the same hostname.
+	RouteConditionConflicted v1beta1.RouteConditionType = "Conflicted"
+	// RouteReasonMatchesShadowed is used with the "Conflicted" condition when some matches of the route are
+	// shadowed by the same matches of the routes that take precedence.
+	RouteReasonMatchesShadowed v1beta1.RouteConditionReason = "MatchesShadowed"
 	// ListenerReasonUnsupportedValue is used with the "Accepted" condition when a value of a field in a Listener
 	// is invalid or not supported.
 	ListenerReasonUnsupportedValue v1beta1.ListenerConditionReason = "UnsupportedValue"
+	// ListenerReasonDisabled is used with the "Accepted" condition when a Listener is disabled.
+	ListenerReasonDisabled v1beta1.ListenerConditionReason = "Disabled"
+	// ListenerReasonInvalidDefaultBackend is used with the "ResolvedRefs" condition when the default backend
+	// of a Listener is invalid.
+	ListenerReasonInvalidDefaultBackend v1beta1.ListenerConditionReason = "InvalidDefaultBackend"
+	// ListenerConditionRateLimited is an NKG-specific condition type that reports whether NGINX limits the rate
+	// of the requests to a Listener.
+	ListenerConditionRateLimited v1beta1.ListenerConditionType = "RateLimited"
+	// ListenerReasonInvalidRateLimit is used with the "RateLimited" condition when the rate limit of a Listener
+	// is invalid.
+	ListenerReasonInvalidRateLimit v1beta1.ListenerConditionReason = "InvalidRateLimit"
+	// GatewayConditionReloadPending is an NKG-specific condition type that reports whether the NGINX configuration
+	// for the Gateway is written but NGINX is not reloaded yet, because automatic reloading is disabled.
+	GatewayConditionReloadPending v1beta1.GatewayConditionType = "ReloadPending"
+	// GatewayReasonAutoReloadDisabled is used with the "ReloadPending" condition when NGINX must be reloaded
+	// manually to apply the written configuration.
+	GatewayReasonAutoReloadDisabled v1beta1.GatewayConditionReason = "AutoReloadDisabled"
+	// GatewayReasonReloaded is used with the "ReloadPending" condition when NGINX was reloaded with the written
+	// configuration.
+	GatewayReasonReloaded v1beta1.GatewayConditionReason = "Reloaded"
 )
 
 // Condition defines a condition to be reported in the status of resources.
@@ -83,6 +135,39 @@ func NewRouteAccepted() Condition {
 	}
 }
 
+// NewRouteUnsupportedValue returns a Condition that indicates that some rules of the HTTPRoute are not accepted
+// because they include unsupported values, such as invalid regular expressions.
+func NewRouteUnsupportedValue(msg string) Condition {
+	return Condition{
+		Type:    string(v1beta1.RouteConditionAccepted),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(v1beta1.RouteReasonUnsupportedValue),
+		Message: msg,
+	}
+}
+
+// NewRouteNoMatchingParent returns a Condition that indicates that the HTTPRoute is not accepted because
+// its parentRef doesn't match a Gateway or a listener of the Gateway.
+func NewRouteNoMatchingParent(msg string) Condition {
+	return Condition{
+		Type:    string(v1beta1.RouteConditionAccepted),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(v1beta1.RouteReasonNoMatchingParent),
+		Message: msg,
+	}
+}
+
+// NewRouteNotAllowedByListeners returns a Condition that indicates that the HTTPRoute is not accepted because
+// the allowedRoutes of the listener don't allow it.
+func NewRouteNotAllowedByListeners(msg string) Condition {
+	return Condition{
+		Type:    string(v1beta1.RouteConditionAccepted),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(v1beta1.RouteReasonNotAllowedByListeners),
+		Message: msg,
+	}
+}
+
 // NewTODO returns a Condition that can be used as a placeholder for a condition that is not yet implemented.
 func NewTODO(msg string) Condition {
 	return Condition{
@@ -104,6 +189,138 @@ func NewRouteInvalidListener() Condition {
 	}
 }
 
+// NewRouteListenerDisabled returns a Condition that indicates that the HTTPRoute is not accepted because it
+// references a disabled listener.
+func NewRouteListenerDisabled() Condition {
+	return Condition{
+		Type:    string(v1beta1.RouteConditionAccepted),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(RouteReasonListenerDisabled),
+		Message: "Listener is disabled for this parent ref",
+	}
+}
+
+// NewRouteTooManyRoutes returns a Condition that indicates that the HTTPRoute is not accepted because the
+// listener already has the maximum number of attached routes.
+func NewRouteTooManyRoutes(maxRoutes int) Condition {
+	return Condition{
+		Type:    string(v1beta1.RouteConditionAccepted),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(RouteReasonTooManyRoutes),
+		Message: fmt.Sprintf("Listener already has the maximum number of attached routes (%d)", maxRoutes),
+	}
+}
+
+// NewRouteUnsupportedExtensionRefKind returns a Condition that indicates that the HTTPRoute references a resource
+// of an unsupported kind through an ExtensionRef filter.
+func NewRouteUnsupportedExtensionRefKind(msg string) Condition {
+	return Condition{
+		Type:    string(v1beta1.RouteConditionResolvedRefs),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(v1beta1.RouteReasonInvalidKind),
+		Message: msg,
+	}
+}
+
+// NewRouteBackendRefInvalidKind returns a Condition that indicates that the HTTPRoute references a resource
+// of an unsupported kind through a backendRef.
+func NewRouteBackendRefInvalidKind(msg string) Condition {
+	return Condition{
+		Type:    string(v1beta1.RouteConditionResolvedRefs),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(v1beta1.RouteReasonInvalidKind),
+		Message: msg,
+	}
+}
+
+// NewRouteBackendNotFound returns a Condition that indicates that a backendRef of the HTTPRoute can't be resolved
+// to a backend.
+func NewRouteBackendNotFound(msg string) Condition {
+	return Condition{
+		Type:    string(v1beta1.RouteConditionResolvedRefs),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(v1beta1.RouteReasonBackendNotFound),
+		Message: msg,
+	}
+}
+
+// NewRouteExtensionRefNotFound returns a Condition that indicates that the HTTPRoute references a resource
+// that doesn't exist through an ExtensionRef filter.
+func NewRouteExtensionRefNotFound(msg string) Condition {
+	return Condition{
+		Type:    string(v1beta1.RouteConditionResolvedRefs),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(RouteReasonExtensionRefNotFound),
+		Message: msg,
+	}
+}
+
+// NewRouteInvalidExtensionRef returns a Condition that indicates that the HTTPRoute references an invalid resource
+// through an ExtensionRef filter.
+func NewRouteInvalidExtensionRef(msg string) Condition {
+	return Condition{
+		Type:    string(v1beta1.RouteConditionResolvedRefs),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(RouteReasonInvalidExtensionRef),
+		Message: msg,
+	}
+}
+
+// NewRouteExternalNameNotAllowed returns a Condition that indicates that the HTTPRoute references ExternalName
+// Services whose external names are not allowed.
+func NewRouteExternalNameNotAllowed(msg string) Condition {
+	return Condition{
+		Type:    string(v1beta1.RouteConditionResolvedRefs),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(RouteReasonExternalNameNotAllowed),
+		Message: msg,
+	}
+}
+
+// NewRouteConflictingBackendFilters returns a Condition that indicates that the backendRefs of a rule
+// of the HTTPRoute reference the same backend with different filters.
+func NewRouteConflictingBackendFilters(msg string) Condition {
+	return Condition{
+		Type:    string(v1beta1.RouteConditionResolvedRefs),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(RouteReasonConflictingBackendFilters),
+		Message: msg,
+	}
+}
+
+// NewRouteBackendWeightsNormalized returns a Condition that reports the percentages of the traffic that NGINX
+// sends to the backendRefs of the HTTPRoute.
+func NewRouteBackendWeightsNormalized(msg string) Condition {
+	return Condition{
+		Type:    string(RouteConditionBackendWeights),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(RouteReasonWeightsNormalized),
+		Message: msg,
+	}
+}
+
+// NewRouteAllBackendWeightsZero returns a Condition that indicates that all backendRefs of a rule of the HTTPRoute
+// have zero weight.
+func NewRouteAllBackendWeightsZero(msg string) Condition {
+	return Condition{
+		Type:    string(RouteConditionBackendWeights),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(RouteReasonAllWeightsZero),
+		Message: msg,
+	}
+}
+
+// NewRouteMatchesShadowed returns a Condition that indicates that some matches of the HTTPRoute are shadowed
+// by the same matches of other HTTPRoutes, so that the requests that match them are not routed to the HTTPRoute.
+func NewRouteMatchesShadowed(msg string) Condition {
+	return Condition{
+		Type:    string(RouteConditionConflicted),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(RouteReasonMatchesShadowed),
+		Message: msg,
+	}
+}
+
 // NewListenerPortUnavailable returns a Condition that indicates a port is unavailable in a Listener.
 func NewListenerPortUnavailable(msg string) Condition {
 	return Condition{
@@ -138,6 +355,38 @@ func NewDefaultListenerConditions() []Condition {
 	}
 }
 
+// NewListenerDisabled returns a Condition that indicates that a Listener is disabled.
+func NewListenerDisabled(msg string) Condition {
+	return Condition{
+		Type:    string(v1beta1.ListenerConditionAccepted),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(ListenerReasonDisabled),
+		Message: msg,
+	}
+}
+
+// NewListenerInvalidDefaultBackend returns a Condition that indicates that the default backend of a Listener
+// is invalid.
+func NewListenerInvalidDefaultBackend(msg string) Condition {
+	return Condition{
+		Type:    string(v1beta1.ListenerConditionResolvedRefs),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(ListenerReasonInvalidDefaultBackend),
+		Message: msg,
+	}
+}
+
+// NewListenerInvalidRateLimit returns a Condition that indicates that the rate limit of a Listener is invalid,
+// so NGINX doesn't limit the rate of the requests to the Listener.
+func NewListenerInvalidRateLimit(msg string) Condition {
+	return Condition{
+		Type:    string(ListenerConditionRateLimited),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(ListenerReasonInvalidRateLimit),
+		Message: msg,
+	}
+}
+
 // NewListenerUnsupportedValue returns a Condition that indicates that a field of a Listener has an unsupported value.
 // Unsupported means that the value is not supported by the implementation or invalid.
 func NewListenerUnsupportedValue(msg string) Condition {
@@ -204,3 +453,25 @@ func NewListenerUnsupportedProtocol(msg string) Condition {
 		Message: msg,
 	}
 }
+
+// NewGatewayReloadPending returns a Condition that indicates that the NGINX configuration for the Gateway is written,
+// but NGINX must be reloaded manually to apply it.
+func NewGatewayReloadPending() Condition {
+	return Condition{
+		Type:    string(GatewayConditionReloadPending),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(GatewayReasonAutoReloadDisabled),
+		Message: "The NGINX configuration is written, but NGINX must be reloaded manually to apply it",
+	}
+}
+
+// NewGatewayReloaded returns a Condition that indicates that NGINX was reloaded with the written configuration
+// for the Gateway.
+func NewGatewayReloaded() Condition {
+	return Condition{
+		Type:    string(GatewayConditionReloadPending),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(GatewayReasonReloaded),
+		Message: "NGINX was reloaded with the written configuration",
+	}
+}
@@ -0,0 +1,674 @@
+package dataplane
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
+)
+
+// StreamingAnnotation is the HTTPRoute annotation that enables streaming of requests and responses for all rules
+// of the HTTPRoute. The value must be a boolean. Streaming is disabled by default.
+const StreamingAnnotation = "k8s-gateway.nginx.org/streaming"
+
+// PreserveHostAnnotation is the HTTPRoute annotation that configures the Host header of the requests that NGINX
+// proxies to the backends of all rules of the HTTPRoute. The value must be a boolean. When true, NGINX passes
+// the Host header of the client request. When false, NGINX sets the Host header to the name of the upstream.
+// The Host header is preserved by default.
+const PreserveHostAnnotation = "k8s-gateway.nginx.org/preserve-host"
+
+// SchemeAnnotation is the HTTPRoute annotation that scopes the rules of the HTTPRoute to the requests with
+// a scheme. The value must be http or https, which scopes all rules, or a comma-separated list of
+// <rule-index>:<scheme> pairs, for example, 0:https,2:http, which scopes the rules with the indexes. The rules are
+// only configured for the listeners of the corresponding protocol, HTTP or HTTPS, so that an HTTPRoute attached
+// to both can handle the http and https requests differently. By default, the rules apply to the requests with
+// any scheme.
+const SchemeAnnotation = "k8s-gateway.nginx.org/scheme"
+
+// RequestIDAnnotation is the HTTPRoute annotation that configures the propagation of the request ID for all rules
+// of the HTTPRoute. The value must be a boolean. When true, NGINX passes the request ID to the backends and returns it
+// to the clients in the X-Request-ID header. The request ID is the X-Request-ID header of the client request or,
+// without it, a generated one. By default, the global setting of the propagation applies.
+const RequestIDAnnotation = "k8s-gateway.nginx.org/request-id"
+
+// AccessLogAnnotation is the HTTPRoute annotation that enables or disables the access log for all rules of
+// the HTTPRoute. The value must be a boolean. The requests are logged to the destination of the global access log or,
+// if it is off, to /dev/stdout. By default, the global access log applies.
+const AccessLogAnnotation = "k8s-gateway.nginx.org/access-log"
+
+// AccessLogFormatAnnotation is the HTTPRoute annotation that enables the access log for all rules of the HTTPRoute
+// with the named log format. The value must be one of the AccessLogFormats. The annotation is ignored if
+// the AccessLogAnnotation disables the access log.
+const AccessLogFormatAnnotation = "k8s-gateway.nginx.org/access-log-format"
+
+// AccessLogFormats are the names of the log formats of the AccessLogFormatAnnotation: the combined format
+// predefined by NGINX and the verbose format, which also logs the request time, the request ID and the address,
+// status and timings of the upstream.
+var AccessLogFormats = []string{"combined", "verbose"}
+
+// MaintenanceAnnotation is the Gateway annotation that enables the maintenance mode of the Gateway. The value must be
+// a boolean. In the maintenance mode, the servers of the Listeners of the Gateway respond to all requests with 503
+// instead of routing them. The maintenance mode is disabled by default.
+const MaintenanceAnnotation = "k8s-gateway.nginx.org/maintenance"
+
+// MaintenancePageAnnotation is the Gateway annotation that configures the HTML page of the 503 responses in
+// the maintenance mode. The value must be up to 4096 characters without $. By default, the page is the 503 error page
+// of NGINX.
+const MaintenancePageAnnotation = "k8s-gateway.nginx.org/maintenance-page"
+
+// maxMaintenancePageLength is the maximum length of the value of the MaintenancePageAnnotation.
+const maxMaintenancePageLength = 4096
+
+// ClientHeaderBufferSizeAnnotation is the Gateway annotation that configures the size of the buffer for reading
+// the headers of the client requests. The value must be an NGINX size in bytes, kilobytes or megabytes.
+// For example, 4k. By default, the size is the default of NGINX, 1k.
+const ClientHeaderBufferSizeAnnotation = "k8s-gateway.nginx.org/client-header-buffer-size"
+
+// LargeClientHeaderBuffersAnnotation is the Gateway annotation that configures the number and the size of the buffers
+// for reading the large headers of the client requests, which don't fit in the client header buffer. The value must
+// be the number and the NGINX size of the buffers separated by a space. For example, 4 16k. The size must be at least
+// 1k. By default, the number and the size are the defaults of NGINX, 4 8k. A request line or a header that doesn't
+// fit in a buffer is rejected with 414 or 400 respectively.
+const LargeClientHeaderBuffersAnnotation = "k8s-gateway.nginx.org/large-client-header-buffers"
+
+// minLargeClientHeaderBufferSize is the minimum size of the large client header buffers. NGINX requires it
+// to be at least the size of the connection memory pool.
+const minLargeClientHeaderBufferSize = 1024
+
+// maxLargeClientHeaderBuffers is the maximum number of the large client header buffers.
+const maxLargeClientHeaderBuffers = 1024
+
+// ProxyCacheValidAnnotation is the HTTPRoute annotation that enables caching of the responses of the backends of
+// all rules of the HTTPRoute. The value is the NGINX time for which the 200, 301 and 302 responses are cached,
+// in seconds, minutes, hours or days. For example, 10m. Every HTTPRoute with the annotation has its own cache zone.
+const ProxyCacheValidAnnotation = "k8s-gateway.nginx.org/proxy-cache-valid"
+
+// ProxyCacheKeyAnnotation is the HTTPRoute annotation that configures the key of the cached responses.
+// The value must be a concatenation of NGINX variables. For example, $host$request_uri. By default, the key is
+// defaultProxyCacheKey. The annotation only applies together with the ProxyCacheValidAnnotation.
+const ProxyCacheKeyAnnotation = "k8s-gateway.nginx.org/proxy-cache-key"
+
+// defaultProxyCacheKey is the default key of the cached responses. Unlike the NGINX default
+// $scheme$proxy_host$request_uri, it includes the host of the request rather than the name of the upstream,
+// so that the responses for the different hostnames of an HTTPRoute are cached separately.
+const defaultProxyCacheKey = "$scheme$host$request_uri"
+
+// ProxyCacheMaxSizeAnnotation is the HTTPRoute annotation that configures the maximum size of the cache of
+// the HTTPRoute. The value must be an NGINX size in bytes, kilobytes, megabytes or gigabytes. For example, 100m.
+// By default, the size is not limited. The annotation only applies together with the ProxyCacheValidAnnotation.
+const ProxyCacheMaxSizeAnnotation = "k8s-gateway.nginx.org/proxy-cache-max-size"
+
+// LBHashKeyAnnotation is the Service annotation that enables consistent hashing load balancing for the upstreams
+// of the Service. The value is the NGINX variable used as the hash key. For example, $http_x_session.
+const LBHashKeyAnnotation = "k8s-gateway.nginx.org/lb-hash-key"
+
+// MaxFailsAnnotation is the Service annotation that configures the number of unsuccessful attempts to communicate
+// with an endpoint of the Service during the fail timeout, after which NGINX considers the endpoint unavailable for
+// the duration of the fail timeout. The value must be a non-negative integer. 0 disables the accounting of attempts.
+const MaxFailsAnnotation = "k8s-gateway.nginx.org/max-fails"
+
+// FailTimeoutAnnotation is the Service annotation that configures the fail timeout for the endpoints of the Service.
+// The value must be an NGINX time in milliseconds, seconds, minutes or hours. For example, 10s.
+const FailTimeoutAnnotation = "k8s-gateway.nginx.org/fail-timeout"
+
+// SlowStartAnnotation is the Service annotation that configures the time during which NGINX gradually increases
+// the share of the requests of a recovered or a newly added endpoint of the Service from zero to the normal share.
+// The value must be an NGINX time in milliseconds, seconds, minutes or hours. For example, 30s. Requires NGINX Plus.
+const SlowStartAnnotation = "k8s-gateway.nginx.org/slow-start"
+
+// UnixSocketAnnotation is the Service annotation that configures the Unix domain socket that NGINX proxies
+// the requests for the Service to instead of its endpoints. For example, a socket of a sidecar container
+// that shares a volume with NGINX. The value must be an absolute path to the socket. For example, /var/run/app.sock.
+// Because the socket can be any socket in the NGINX container, the annotation is ignored unless the Unix socket
+// backends are enabled with a directory that the socket must be in.
+const UnixSocketAnnotation = "k8s-gateway.nginx.org/unix-socket"
+
+// maxUnixSocketPathLength is the maximum length of the path of a Unix domain socket, which doesn't include
+// the terminating null byte of the sun_path field of the socket address.
+const maxUnixSocketPathLength = 107
+
+// failTimeoutRegexp matches an NGINX time with an optional ms, s, m or h unit. Without a unit, the time is in seconds.
+var failTimeoutRegexp = regexp.MustCompile(`^[0-9]{1,6}(ms|s|m|h)?$`)
+
+// cacheValidRegexp matches an NGINX time with an optional s, m, h or d unit. Without a unit, the time is in seconds.
+var cacheValidRegexp = regexp.MustCompile(`^[0-9]{1,6}(s|m|h|d)?$`)
+
+// cacheKeyRegexp matches a concatenation of NGINX variables.
+var cacheKeyRegexp = regexp.MustCompile(`^(\$[A-Za-z0-9_]+)+$`)
+
+// cacheMaxSizeRegexp matches an NGINX size with an optional k, m or g unit. Without a unit, the size is in bytes.
+var cacheMaxSizeRegexp = regexp.MustCompile(`^[0-9]{1,6}(k|m|g)?$`)
+
+// unixSocketPathRegexp matches an absolute path of letters, digits, '.', '_' and '-'.
+var unixSocketPathRegexp = regexp.MustCompile(`^(/[A-Za-z0-9._-]+)+$`)
+
+// headerBufferSizeRegexp matches a non-zero NGINX size with an optional k or m unit. Without a unit, the size
+// is in bytes.
+var headerBufferSizeRegexp = regexp.MustCompile(`^[1-9][0-9]{0,5}(k|m)?$`)
+
+// lbHashKeyRegexp matches the NGINX variables supported as a hash key: request headers, cookies and query arguments,
+// a few request properties, and the country and the continent of the client looked up in the GeoIP2 database.
+var lbHashKeyRegexp = regexp.MustCompile(`^\$(http_[a-z0-9_]+|cookie_[A-Za-z0-9_]+|arg_[A-Za-z0-9_]+|` +
+	`remote_addr|request_uri|uri|host|geoip2_country_code|geoip2_continent_code)$`)
+
+// RouteOptions holds the options of a MatchRule, which are configured through the annotations of the HTTPRoute.
+type RouteOptions struct {
+	// Streaming disables buffering of requests and responses, so that they are streamed between the client and
+	// the backend.
+	Streaming bool
+	// UpstreamHost sets the Host header of the proxied requests to the name of the upstream instead of the Host header
+	// of the client request.
+	UpstreamHost bool
+	// RequestID enables or disables the propagation of the request ID in the X-Request-ID header.
+	// Nil means the global setting applies.
+	RequestID *bool
+	// AccessLog enables or disables the access log. Nil means the global access log applies.
+	AccessLog *bool
+	// AccessLogFormat is the name of the log format of the access log. Empty means the NGINX default, combined.
+	// It only applies when AccessLog is enabled.
+	AccessLogFormat string
+	// Scheme is the scheme of the requests that the MatchRules of all rules apply to: http or https.
+	// Empty means the MatchRules apply to the requests with any scheme.
+	Scheme string
+	// RuleSchemes are the schemes of the requests that the MatchRules of the rules with the indexes apply to.
+	// They are only set if Scheme is empty.
+	RuleSchemes map[int]string
+	// GeoMatch scopes the MatchRule to the clients from some countries or continents.
+	// Nil means the MatchRule applies to all clients.
+	GeoMatch *graph.GeoMatch
+	// Weight is the weight of the HTTPRoute in a weighted split of the traffic across multiple HTTPRoutes.
+	// Nil means the HTTPRoute doesn't take part in weighted splits.
+	Weight *int32
+	// Cache enables caching of the responses of the backends. Nil means caching is disabled.
+	Cache *CacheOptions
+}
+
+// CacheOptions holds the options of the cache of the responses of the backends of an HTTPRoute.
+type CacheOptions struct {
+	// Valid is the NGINX time for which the responses are cached.
+	Valid string
+	// Key is the key of the cached responses.
+	Key string
+	// MaxSize is the NGINX size of the maximum size of the cache. Empty means no limit.
+	MaxSize string
+}
+
+// ruleAppliesToListener returns true if the options allow the MatchRules of the rule with the index to be configured
+// for the listener.
+func (o RouteOptions) ruleAppliesToListener(ruleIdx int, l *graph.Listener) bool {
+	if scheme, exists := o.RuleSchemes[ruleIdx]; exists {
+		return schemeAppliesToListener(scheme, l)
+	}
+
+	return schemeAppliesToListener(o.Scheme, l)
+}
+
+// appliesToListener returns true if the options allow the MatchRules of any of the rules to be configured
+// for the listener.
+func (o RouteOptions) appliesToListener(l *graph.Listener, rules int) bool {
+	if len(o.RuleSchemes) == 0 {
+		return schemeAppliesToListener(o.Scheme, l)
+	}
+
+	for i := 0; i < rules; i++ {
+		if o.ruleAppliesToListener(i, l) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func schemeAppliesToListener(scheme string, l *graph.Listener) bool {
+	switch scheme {
+	case "http":
+		return l.Source.Protocol == v1beta1.HTTPProtocolType
+	case "https":
+		return l.Source.Protocol == v1beta1.HTTPSProtocolType
+	default:
+		return true
+	}
+}
+
+// createRouteOptions creates RouteOptions from the annotations of an HTTPRoute.
+// Annotations with invalid values are ignored and reported in the returned messages.
+func createRouteOptions(annotations map[string]string) (RouteOptions, []string) {
+	var (
+		opts RouteOptions
+		msgs []string
+	)
+
+	if v, exists := annotations[StreamingAnnotation]; exists {
+		streaming, err := strconv.ParseBool(v)
+		if err != nil {
+			msgs = append(msgs, fmt.Sprintf("invalid value %q of the annotation %s; must be a boolean", v,
+				StreamingAnnotation))
+		} else {
+			opts.Streaming = streaming
+		}
+	}
+
+	if v, exists := annotations[PreserveHostAnnotation]; exists {
+		preserveHost, err := strconv.ParseBool(v)
+		if err != nil {
+			msgs = append(msgs, fmt.Sprintf("invalid value %q of the annotation %s; must be a boolean", v,
+				PreserveHostAnnotation))
+		} else {
+			opts.UpstreamHost = !preserveHost
+		}
+	}
+
+	if v, exists := annotations[RequestIDAnnotation]; exists {
+		requestID, err := strconv.ParseBool(v)
+		if err != nil {
+			msgs = append(msgs, fmt.Sprintf("invalid value %q of the annotation %s; must be a boolean", v,
+				RequestIDAnnotation))
+		} else {
+			opts.RequestID = &requestID
+		}
+	}
+
+	var accessLogMsgs []string
+	opts.AccessLog, opts.AccessLogFormat, accessLogMsgs = createAccessLogOptions(annotations)
+	msgs = append(msgs, accessLogMsgs...)
+
+	if v, exists := annotations[SchemeAnnotation]; exists {
+		if isScheme(v) {
+			opts.Scheme = v
+		} else if ruleSchemes, err := parseRuleSchemes(v); err != nil {
+			msgs = append(msgs, fmt.Sprintf("invalid value %q of the annotation %s; %v", v, SchemeAnnotation, err))
+		} else {
+			opts.RuleSchemes = ruleSchemes
+		}
+	}
+
+	if v, exists := annotations[graph.GeoMatchAnnotation]; exists {
+		geoMatch, err := graph.ParseGeoMatch(v)
+		if err != nil {
+			msgs = append(msgs, fmt.Sprintf("invalid value %q of the annotation %s; %v", v, graph.GeoMatchAnnotation,
+				err))
+		} else {
+			opts.GeoMatch = &geoMatch
+		}
+	}
+
+	if v, exists := annotations[graph.WeightAnnotation]; exists {
+		weight, err := graph.ParseRouteWeight(v)
+		if err != nil {
+			msgs = append(msgs, fmt.Sprintf("invalid value %q of the annotation %s; %v", v, graph.WeightAnnotation,
+				err))
+		} else {
+			opts.Weight = &weight
+		}
+	}
+
+	cache, cacheMsgs := createCacheOptions(annotations)
+	opts.Cache = cache
+	msgs = append(msgs, cacheMsgs...)
+
+	return opts, msgs
+}
+
+// createAccessLogOptions returns whether the access log is enabled and its format from the annotations
+// of an HTTPRoute. A valid AccessLogFormatAnnotation enables the access log, unless the AccessLogAnnotation
+// disables it.
+func createAccessLogOptions(annotations map[string]string) (accessLog *bool, format string, msgs []string) {
+	if v, exists := annotations[AccessLogFormatAnnotation]; exists {
+		if !isAccessLogFormat(v) {
+			msgs = append(msgs, fmt.Sprintf("invalid value %q of the annotation %s; must be one of %s", v,
+				AccessLogFormatAnnotation, strings.Join(AccessLogFormats, ", ")))
+		} else {
+			enabled := true
+			accessLog = &enabled
+			format = v
+		}
+	}
+
+	if v, exists := annotations[AccessLogAnnotation]; exists {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			msgs = append(msgs, fmt.Sprintf("invalid value %q of the annotation %s; must be a boolean", v,
+				AccessLogAnnotation))
+		} else {
+			accessLog = &enabled
+			if !enabled {
+				format = ""
+			}
+		}
+	}
+
+	return accessLog, format, msgs
+}
+
+func isScheme(scheme string) bool {
+	return scheme == "http" || scheme == "https"
+}
+
+// parseRuleSchemes parses the comma-separated list of <rule-index>:<scheme> pairs of the SchemeAnnotation.
+func parseRuleSchemes(value string) (map[int]string, error) {
+	const format = "must be http, https or a comma-separated list of <rule-index>:<scheme> pairs"
+
+	schemes := make(map[int]string)
+
+	for _, pair := range strings.Split(value, ",") {
+		idx, scheme, found := strings.Cut(pair, ":")
+		if !found {
+			return nil, errors.New(format)
+		}
+
+		ruleIdx, err := strconv.Atoi(idx)
+		if err != nil || ruleIdx < 0 {
+			return nil, fmt.Errorf("invalid rule index %q; %s", idx, format)
+		}
+
+		if !isScheme(scheme) {
+			return nil, fmt.Errorf("invalid scheme %q of the rule %d; must be http or https", scheme, ruleIdx)
+		}
+
+		if _, exists := schemes[ruleIdx]; exists {
+			return nil, fmt.Errorf("duplicate rule index %d", ruleIdx)
+		}
+
+		schemes[ruleIdx] = scheme
+	}
+
+	return schemes, nil
+}
+
+func isAccessLogFormat(format string) bool {
+	for _, f := range AccessLogFormats {
+		if f == format {
+			return true
+		}
+	}
+
+	return false
+}
+
+// createCacheOptions creates CacheOptions from the annotations of an HTTPRoute. It returns nil if caching is not
+// enabled or the ProxyCacheValidAnnotation is invalid.
+func createCacheOptions(annotations map[string]string) (*CacheOptions, []string) {
+	v, exists := annotations[ProxyCacheValidAnnotation]
+	if !exists {
+		for _, a := range []string{ProxyCacheKeyAnnotation, ProxyCacheMaxSizeAnnotation} {
+			if _, exists := annotations[a]; exists {
+				return nil, []string{fmt.Sprintf("the annotation %s is ignored without the annotation %s", a,
+					ProxyCacheValidAnnotation)}
+			}
+		}
+
+		return nil, nil
+	}
+
+	if !cacheValidRegexp.MatchString(v) {
+		return nil, []string{fmt.Sprintf("invalid value %q of the annotation %s; must be a time in seconds, "+
+			"minutes, hours or days, for example 10m", v, ProxyCacheValidAnnotation)}
+	}
+
+	opts := &CacheOptions{
+		Valid: v,
+		Key:   defaultProxyCacheKey,
+	}
+
+	var msgs []string
+
+	if v, exists := annotations[ProxyCacheKeyAnnotation]; exists {
+		if !cacheKeyRegexp.MatchString(v) {
+			msgs = append(msgs, fmt.Sprintf("invalid value %q of the annotation %s; must be a concatenation of "+
+				"NGINX variables, for example $host$request_uri", v, ProxyCacheKeyAnnotation))
+		} else {
+			opts.Key = v
+		}
+	}
+
+	if v, exists := annotations[ProxyCacheMaxSizeAnnotation]; exists {
+		if !cacheMaxSizeRegexp.MatchString(v) {
+			msgs = append(msgs, fmt.Sprintf("invalid value %q of the annotation %s; must be a size in bytes, "+
+				"kilobytes, megabytes or gigabytes, for example 100m", v, ProxyCacheMaxSizeAnnotation))
+		} else {
+			opts.MaxSize = v
+		}
+	}
+
+	return opts, msgs
+}
+
+// Maintenance holds the configuration of the maintenance mode of a Gateway.
+type Maintenance struct {
+	// Page is the HTML page of the 503 responses. Empty means the 503 error page of NGINX.
+	Page string
+}
+
+// createMaintenance creates the Maintenance from the annotations of a Gateway. It returns nil if the maintenance mode
+// is not enabled. Annotations with invalid values are ignored and reported in the returned messages.
+func createMaintenance(annotations map[string]string) (*Maintenance, []string) {
+	v, exists := annotations[MaintenanceAnnotation]
+	if !exists {
+		return nil, nil
+	}
+
+	enabled, err := strconv.ParseBool(v)
+	if err != nil {
+		return nil, []string{fmt.Sprintf("invalid value %q of the annotation %s; must be a boolean", v,
+			MaintenanceAnnotation)}
+	}
+
+	if !enabled {
+		return nil, nil
+	}
+
+	maintenance := &Maintenance{}
+
+	var msgs []string
+
+	if v, exists := annotations[MaintenancePageAnnotation]; exists {
+		// NGINX would interpret the text after $ as a variable.
+		if len(v) > maxMaintenancePageLength || strings.Contains(v, "$") {
+			msgs = append(msgs, fmt.Sprintf("invalid value of the annotation %s; must be up to %d characters "+
+				"without $", MaintenancePageAnnotation, maxMaintenancePageLength))
+		} else {
+			maintenance.Page = v
+		}
+	}
+
+	return maintenance, msgs
+}
+
+// ClientHeaderBuffers holds the sizes of the buffers for reading the headers of the client requests of a Gateway.
+type ClientHeaderBuffers struct {
+	// Size is the NGINX size of the client header buffer. Empty means the default.
+	Size string
+	// LargeSize is the NGINX size of the large client header buffers. Empty means the default number and size.
+	LargeSize string
+	// LargeNumber is the number of the large client header buffers. It is only set with LargeSize.
+	LargeNumber int32
+}
+
+// createClientHeaderBuffers creates the ClientHeaderBuffers from the annotations of a Gateway. It returns nil if
+// none of the sizes is configured. Annotations with invalid values are ignored and reported in the returned messages.
+func createClientHeaderBuffers(annotations map[string]string) (*ClientHeaderBuffers, []string) {
+	var (
+		buffers ClientHeaderBuffers
+		msgs    []string
+	)
+
+	if v, exists := annotations[ClientHeaderBufferSizeAnnotation]; exists {
+		if !headerBufferSizeRegexp.MatchString(v) {
+			msgs = append(msgs, fmt.Sprintf("invalid value %q of the annotation %s; must be a size in bytes, "+
+				"kilobytes or megabytes, for example 4k", v, ClientHeaderBufferSizeAnnotation))
+		} else {
+			buffers.Size = v
+		}
+	}
+
+	if v, exists := annotations[LargeClientHeaderBuffersAnnotation]; exists {
+		number, size, valid := parseLargeClientHeaderBuffers(v)
+		if !valid {
+			msgs = append(msgs, fmt.Sprintf("invalid value %q of the annotation %s; must be a number from 1 to %d "+
+				"and a size of at least 1k in bytes, kilobytes or megabytes, separated by a space, for example 4 16k",
+				v, LargeClientHeaderBuffersAnnotation, maxLargeClientHeaderBuffers))
+		} else {
+			buffers.LargeNumber = number
+			buffers.LargeSize = size
+		}
+	}
+
+	if buffers == (ClientHeaderBuffers{}) {
+		return nil, msgs
+	}
+
+	return &buffers, msgs
+}
+
+// parseLargeClientHeaderBuffers parses the number and the size of the large client header buffers of the value of
+// the LargeClientHeaderBuffersAnnotation.
+func parseLargeClientHeaderBuffers(v string) (number int32, size string, valid bool) {
+	fields := strings.Split(v, " ")
+	if len(fields) != 2 {
+		return 0, "", false
+	}
+
+	n, err := strconv.ParseInt(fields[0], 10, 32)
+	if err != nil || n < 1 || n > maxLargeClientHeaderBuffers {
+		return 0, "", false
+	}
+
+	size = fields[1]
+	if !headerBufferSizeRegexp.MatchString(size) || sizeInBytes(size) < minLargeClientHeaderBufferSize {
+		return 0, "", false
+	}
+
+	return int32(n), size, true
+}
+
+// sizeInBytes returns the number of bytes of an NGINX size with an optional k or m unit, which is expected
+// to be validated.
+func sizeInBytes(size string) int64 {
+	multiplier := int64(1)
+
+	switch size[len(size)-1] {
+	case 'k':
+		multiplier = 1024
+		size = size[:len(size)-1]
+	case 'm':
+		multiplier = 1024 * 1024
+		size = size[:len(size)-1]
+	}
+
+	n, _ := strconv.ParseInt(size, 10, 64)
+
+	return n * multiplier
+}
+
+// UpstreamOptions holds the options of an Upstream, which are configured through the annotations of the Service.
+type UpstreamOptions struct {
+	// HashKey is the NGINX variable used as the key for consistent hashing load balancing.
+	// Empty means the default load balancing method.
+	HashKey string
+	// MaxFails is the number of unsuccessful attempts to communicate with an endpoint during FailTimeout, after which
+	// the endpoint is considered unavailable for the duration of FailTimeout. Nil means the default.
+	MaxFails *int32
+	// FailTimeout is the NGINX time of the fail timeout of the endpoints. Empty means the default.
+	FailTimeout string
+	// SlowStart is the NGINX time during which the share of the requests of a recovered or a newly added endpoint
+	// increases to the normal share. Empty means no slow start.
+	SlowStart string
+	// UnixSocket is the path of the Unix domain socket that replaces the endpoints of the Upstream.
+	// Empty means the endpoints of the Service.
+	UnixSocket string
+}
+
+// createUpstreamOptions creates UpstreamOptions from the annotations of a Service.
+// unixSocketDir is the directory of the Unix domain sockets that the UnixSocketAnnotation can configure.
+// Empty means the UnixSocketAnnotation is not allowed.
+// Annotations with invalid values are ignored and reported in the returned messages.
+func createUpstreamOptions(annotations map[string]string, unixSocketDir string) (UpstreamOptions, []string) {
+	var (
+		opts UpstreamOptions
+		msgs []string
+	)
+
+	if v, exists := annotations[LBHashKeyAnnotation]; exists {
+		if !lbHashKeyRegexp.MatchString(v) {
+			msgs = append(msgs, fmt.Sprintf("invalid value %q of the annotation %s; must be one of the NGINX "+
+				"variables $http_<name>, $cookie_<name>, $arg_<name>, $remote_addr, $request_uri, $uri, $host, "+
+				"$geoip2_country_code or $geoip2_continent_code", v,
+				LBHashKeyAnnotation))
+		} else {
+			opts.HashKey = v
+		}
+	}
+
+	if v, exists := annotations[MaxFailsAnnotation]; exists {
+		maxFails, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || maxFails < 0 {
+			msgs = append(msgs, fmt.Sprintf("invalid value %q of the annotation %s; must be a non-negative integer",
+				v, MaxFailsAnnotation))
+		} else {
+			mf := int32(maxFails)
+			opts.MaxFails = &mf
+		}
+	}
+
+	if v, exists := annotations[FailTimeoutAnnotation]; exists {
+		if !failTimeoutRegexp.MatchString(v) {
+			msgs = append(msgs, fmt.Sprintf("invalid value %q of the annotation %s; must be a time in "+
+				"milliseconds, seconds, minutes or hours, for example 10s", v, FailTimeoutAnnotation))
+		} else {
+			opts.FailTimeout = v
+		}
+	}
+
+	if v, exists := annotations[SlowStartAnnotation]; exists {
+		if !failTimeoutRegexp.MatchString(v) {
+			msgs = append(msgs, fmt.Sprintf("invalid value %q of the annotation %s; must be a time in "+
+				"milliseconds, seconds, minutes or hours, for example 30s", v, SlowStartAnnotation))
+		} else {
+			opts.SlowStart = v
+		}
+	}
+
+	if v, exists := annotations[UnixSocketAnnotation]; exists {
+		switch {
+		case unixSocketDir == "":
+			msgs = append(msgs, fmt.Sprintf("the annotation %s is ignored, because the Unix socket backends "+
+				"are disabled", UnixSocketAnnotation))
+		case !validUnixSocketPath(v):
+			msgs = append(msgs, fmt.Sprintf("invalid value %q of the annotation %s; must be an absolute path of "+
+				"up to %d characters of letters, digits, '.', '_' and '-' without . or .. elements, "+
+				"for example /var/run/app.sock", v, UnixSocketAnnotation, maxUnixSocketPathLength))
+		case !inDirectory(v, unixSocketDir):
+			msgs = append(msgs, fmt.Sprintf("invalid value %q of the annotation %s; must be in the directory %s",
+				v, UnixSocketAnnotation, unixSocketDir))
+		default:
+			opts.UnixSocket = v
+		}
+	}
+
+	return opts, msgs
+}
+
+// inDirectory returns true if the path is in the directory or its subdirectories. The path is expected to be
+// a valid Unix socket path, which doesn't have . or .. elements.
+func inDirectory(path, dir string) bool {
+	return strings.HasPrefix(path, strings.TrimSuffix(dir, "/")+"/")
+}
+
+func validUnixSocketPath(path string) bool {
+	if len(path) > maxUnixSocketPathLength || !unixSocketPathRegexp.MatchString(path) {
+		return false
+	}
+
+	for _, elem := range strings.Split(path[1:], "/") {
+		if elem == "." || elem == ".." {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,611 @@
+package dataplane
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
+)
+
+func TestCreateRouteOptions(t *testing.T) {
+	tests := []struct {
+		annotations map[string]string
+		msg         string
+		expOpts     RouteOptions
+		expMsgs     int
+	}{
+		{
+			annotations: nil,
+			expOpts:     RouteOptions{},
+			msg:         "no annotations",
+		},
+		{
+			annotations: map[string]string{StreamingAnnotation: "true"},
+			expOpts:     RouteOptions{Streaming: true},
+			msg:         "streaming enabled",
+		},
+		{
+			annotations: map[string]string{StreamingAnnotation: "false"},
+			expOpts:     RouteOptions{},
+			msg:         "streaming disabled",
+		},
+		{
+			annotations: map[string]string{StreamingAnnotation: "yes"},
+			expOpts:     RouteOptions{},
+			expMsgs:     1,
+			msg:         "invalid streaming value",
+		},
+		{
+			annotations: map[string]string{PreserveHostAnnotation: "false"},
+			expOpts:     RouteOptions{UpstreamHost: true},
+			msg:         "host not preserved",
+		},
+		{
+			annotations: map[string]string{PreserveHostAnnotation: "true"},
+			expOpts:     RouteOptions{},
+			msg:         "host preserved",
+		},
+		{
+			annotations: map[string]string{PreserveHostAnnotation: "no"},
+			expOpts:     RouteOptions{},
+			expMsgs:     1,
+			msg:         "invalid preserve-host value",
+		},
+		{
+			annotations: map[string]string{StreamingAnnotation: "true", PreserveHostAnnotation: "false"},
+			expOpts:     RouteOptions{Streaming: true, UpstreamHost: true},
+			msg:         "streaming and host not preserved",
+		},
+		{
+			annotations: map[string]string{RequestIDAnnotation: "true"},
+			expOpts:     RouteOptions{RequestID: helpers.GetBoolPointer(true)},
+			msg:         "request id enabled",
+		},
+		{
+			annotations: map[string]string{RequestIDAnnotation: "false"},
+			expOpts:     RouteOptions{RequestID: helpers.GetBoolPointer(false)},
+			msg:         "request id disabled",
+		},
+		{
+			annotations: map[string]string{RequestIDAnnotation: "on"},
+			expOpts:     RouteOptions{},
+			expMsgs:     1,
+			msg:         "invalid request id value",
+		},
+		{
+			annotations: map[string]string{AccessLogAnnotation: "true"},
+			expOpts:     RouteOptions{AccessLog: helpers.GetBoolPointer(true)},
+			msg:         "access log enabled",
+		},
+		{
+			annotations: map[string]string{AccessLogAnnotation: "false"},
+			expOpts:     RouteOptions{AccessLog: helpers.GetBoolPointer(false)},
+			msg:         "access log disabled",
+		},
+		{
+			annotations: map[string]string{AccessLogFormatAnnotation: "verbose"},
+			expOpts:     RouteOptions{AccessLog: helpers.GetBoolPointer(true), AccessLogFormat: "verbose"},
+			msg:         "access log format",
+		},
+		{
+			annotations: map[string]string{AccessLogAnnotation: "true", AccessLogFormatAnnotation: "combined"},
+			expOpts:     RouteOptions{AccessLog: helpers.GetBoolPointer(true), AccessLogFormat: "combined"},
+			msg:         "access log enabled with format",
+		},
+		{
+			annotations: map[string]string{AccessLogAnnotation: "false", AccessLogFormatAnnotation: "verbose"},
+			expOpts:     RouteOptions{AccessLog: helpers.GetBoolPointer(false)},
+			msg:         "access log disabled with format",
+		},
+		{
+			annotations: map[string]string{AccessLogAnnotation: "off", AccessLogFormatAnnotation: "main"},
+			expOpts:     RouteOptions{},
+			expMsgs:     2,
+			msg:         "invalid access log values",
+		},
+		{
+			annotations: map[string]string{SchemeAnnotation: "http"},
+			expOpts:     RouteOptions{Scheme: "http"},
+			msg:         "http scheme",
+		},
+		{
+			annotations: map[string]string{SchemeAnnotation: "https"},
+			expOpts:     RouteOptions{Scheme: "https"},
+			msg:         "https scheme",
+		},
+		{
+			annotations: map[string]string{SchemeAnnotation: "HTTPS"},
+			expOpts:     RouteOptions{},
+			expMsgs:     1,
+			msg:         "invalid scheme",
+		},
+		{
+			annotations: map[string]string{SchemeAnnotation: "0:https,2:http"},
+			expOpts:     RouteOptions{RuleSchemes: map[int]string{0: "https", 2: "http"}},
+			msg:         "rule schemes",
+		},
+		{
+			annotations: map[string]string{SchemeAnnotation: "0:https,0:http"},
+			expOpts:     RouteOptions{},
+			expMsgs:     1,
+			msg:         "duplicate rule index",
+		},
+		{
+			annotations: map[string]string{SchemeAnnotation: "first:https"},
+			expOpts:     RouteOptions{},
+			expMsgs:     1,
+			msg:         "invalid rule index",
+		},
+		{
+			annotations: map[string]string{SchemeAnnotation: "-1:https"},
+			expOpts:     RouteOptions{},
+			expMsgs:     1,
+			msg:         "negative rule index",
+		},
+		{
+			annotations: map[string]string{SchemeAnnotation: "0:https,1:ftp"},
+			expOpts:     RouteOptions{},
+			expMsgs:     1,
+			msg:         "invalid rule scheme",
+		},
+		{
+			annotations: map[string]string{SchemeAnnotation: "https,1:http"},
+			expOpts:     RouteOptions{},
+			expMsgs:     1,
+			msg:         "scheme together with rule schemes",
+		},
+		{
+			annotations: map[string]string{graph.GeoMatchAnnotation: "continent=EU"},
+			expOpts: RouteOptions{
+				GeoMatch: &graph.GeoMatch{Type: graph.GeoMatchTypeContinent, Codes: []string{"EU"}},
+			},
+			msg: "geo match",
+		},
+		{
+			annotations: map[string]string{graph.GeoMatchAnnotation: "city=Berlin"},
+			expOpts:     RouteOptions{},
+			expMsgs:     1,
+			msg:         "invalid geo match",
+		},
+		{
+			annotations: map[string]string{graph.WeightAnnotation: "80"},
+			expOpts:     RouteOptions{Weight: helpers.GetInt32Pointer(80)},
+			msg:         "weight",
+		},
+		{
+			annotations: map[string]string{graph.WeightAnnotation: "0"},
+			expOpts:     RouteOptions{Weight: helpers.GetInt32Pointer(0)},
+			msg:         "zero weight",
+		},
+		{
+			annotations: map[string]string{graph.WeightAnnotation: "1001"},
+			expOpts:     RouteOptions{},
+			expMsgs:     1,
+			msg:         "weight out of range",
+		},
+		{
+			annotations: map[string]string{graph.WeightAnnotation: "half"},
+			expOpts:     RouteOptions{},
+			expMsgs:     1,
+			msg:         "invalid weight",
+		},
+		{
+			annotations: map[string]string{ProxyCacheValidAnnotation: "10m"},
+			expOpts:     RouteOptions{Cache: &CacheOptions{Valid: "10m", Key: "$scheme$host$request_uri"}},
+			msg:         "cache",
+		},
+		{
+			annotations: map[string]string{
+				ProxyCacheValidAnnotation:   "1h",
+				ProxyCacheKeyAnnotation:     "$host$request_uri",
+				ProxyCacheMaxSizeAnnotation: "100m",
+			},
+			expOpts: RouteOptions{Cache: &CacheOptions{Valid: "1h", Key: "$host$request_uri", MaxSize: "100m"}},
+			msg:     "cache with key and max size",
+		},
+		{
+			annotations: map[string]string{ProxyCacheValidAnnotation: "10 minutes"},
+			expOpts:     RouteOptions{},
+			expMsgs:     1,
+			msg:         "invalid cache valid time",
+		},
+		{
+			annotations: map[string]string{
+				ProxyCacheValidAnnotation:   "10m",
+				ProxyCacheKeyAnnotation:     "$host;$request_uri",
+				ProxyCacheMaxSizeAnnotation: "1t",
+			},
+			expOpts: RouteOptions{Cache: &CacheOptions{Valid: "10m", Key: "$scheme$host$request_uri"}},
+			expMsgs: 2,
+			msg:     "invalid cache key and max size",
+		},
+		{
+			annotations: map[string]string{ProxyCacheKeyAnnotation: "$host$request_uri"},
+			expOpts:     RouteOptions{},
+			expMsgs:     1,
+			msg:         "cache key without cache valid time",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			opts, msgs := createRouteOptions(test.annotations)
+			g.Expect(opts).To(Equal(test.expOpts))
+			g.Expect(msgs).To(HaveLen(test.expMsgs))
+		})
+	}
+}
+
+func TestCreateUpstreamOptions(t *testing.T) {
+	tests := []struct {
+		annotations map[string]string
+		msg         string
+		expOpts     UpstreamOptions
+		expMsgs     int
+	}{
+		{
+			annotations: nil,
+			expOpts:     UpstreamOptions{},
+			msg:         "no annotations",
+		},
+		{
+			annotations: map[string]string{LBHashKeyAnnotation: "$http_x_session"},
+			expOpts:     UpstreamOptions{HashKey: "$http_x_session"},
+			msg:         "header hash key",
+		},
+		{
+			annotations: map[string]string{LBHashKeyAnnotation: "$cookie_Session_ID"},
+			expOpts:     UpstreamOptions{HashKey: "$cookie_Session_ID"},
+			msg:         "cookie hash key",
+		},
+		{
+			annotations: map[string]string{LBHashKeyAnnotation: "$remote_addr"},
+			expOpts:     UpstreamOptions{HashKey: "$remote_addr"},
+			msg:         "remote address hash key",
+		},
+		{
+			annotations: map[string]string{LBHashKeyAnnotation: "$geoip2_continent_code"},
+			expOpts:     UpstreamOptions{HashKey: "$geoip2_continent_code"},
+			msg:         "GeoIP2 continent hash key",
+		},
+		{
+			annotations: map[string]string{LBHashKeyAnnotation: "$request_body"},
+			expOpts:     UpstreamOptions{},
+			expMsgs:     1,
+			msg:         "unsupported variable",
+		},
+		{
+			annotations: map[string]string{LBHashKeyAnnotation: "$http_x_session$remote_addr"},
+			expOpts:     UpstreamOptions{},
+			expMsgs:     1,
+			msg:         "multiple variables",
+		},
+		{
+			annotations: map[string]string{LBHashKeyAnnotation: "http_x_session"},
+			expOpts:     UpstreamOptions{},
+			expMsgs:     1,
+			msg:         "not a variable",
+		},
+		{
+			annotations: map[string]string{
+				MaxFailsAnnotation:    "5",
+				FailTimeoutAnnotation: "30s",
+			},
+			expOpts: UpstreamOptions{
+				MaxFails:    helpers.GetInt32Pointer(5),
+				FailTimeout: "30s",
+			},
+			msg: "max fails and fail timeout",
+		},
+		{
+			annotations: map[string]string{
+				MaxFailsAnnotation:    "0",
+				FailTimeoutAnnotation: "500ms",
+			},
+			expOpts: UpstreamOptions{
+				MaxFails:    helpers.GetInt32Pointer(0),
+				FailTimeout: "500ms",
+			},
+			msg: "max fails disabled and fail timeout in milliseconds",
+		},
+		{
+			annotations: map[string]string{FailTimeoutAnnotation: "10"},
+			expOpts:     UpstreamOptions{FailTimeout: "10"},
+			msg:         "fail timeout without a unit",
+		},
+		{
+			annotations: map[string]string{
+				MaxFailsAnnotation:    "-1",
+				FailTimeoutAnnotation: "10 s",
+			},
+			expOpts: UpstreamOptions{},
+			expMsgs: 2,
+			msg:     "invalid max fails and fail timeout",
+		},
+		{
+			annotations: map[string]string{
+				MaxFailsAnnotation:    "many",
+				FailTimeoutAnnotation: "10d",
+			},
+			expOpts: UpstreamOptions{},
+			expMsgs: 2,
+			msg:     "max fails is not a number and unsupported fail timeout unit",
+		},
+		{
+			annotations: map[string]string{SlowStartAnnotation: "30s"},
+			expOpts:     UpstreamOptions{SlowStart: "30s"},
+			msg:         "slow start",
+		},
+		{
+			annotations: map[string]string{SlowStartAnnotation: "slow"},
+			expOpts:     UpstreamOptions{},
+			expMsgs:     1,
+			msg:         "invalid slow start",
+		},
+		{
+			annotations: map[string]string{UnixSocketAnnotation: "/var/run/app-1_v2.sock"},
+			expOpts:     UpstreamOptions{UnixSocket: "/var/run/app-1_v2.sock"},
+			msg:         "unix socket",
+		},
+		{
+			annotations: map[string]string{UnixSocketAnnotation: "var/run/app.sock"},
+			expOpts:     UpstreamOptions{},
+			expMsgs:     1,
+			msg:         "relative unix socket path",
+		},
+		{
+			annotations: map[string]string{UnixSocketAnnotation: "/var/run/../app.sock"},
+			expOpts:     UpstreamOptions{},
+			expMsgs:     1,
+			msg:         "unix socket path with a parent element",
+		},
+		{
+			annotations: map[string]string{UnixSocketAnnotation: "/var/run/app.sock;"},
+			expOpts:     UpstreamOptions{},
+			expMsgs:     1,
+			msg:         "unix socket path with an invalid character",
+		},
+		{
+			annotations: map[string]string{UnixSocketAnnotation: "/var/run//app.sock"},
+			expOpts:     UpstreamOptions{},
+			expMsgs:     1,
+			msg:         "unix socket path with an empty element",
+		},
+		{
+			annotations: map[string]string{UnixSocketAnnotation: "/" + strings.Repeat("a", 107)},
+			expOpts:     UpstreamOptions{},
+			expMsgs:     1,
+			msg:         "too long unix socket path",
+		},
+		{
+			annotations: map[string]string{UnixSocketAnnotation: "/var/lib/nginx/nginx-502-server.sock"},
+			expOpts:     UpstreamOptions{},
+			expMsgs:     1,
+			msg:         "unix socket outside the directory",
+		},
+		{
+			annotations: map[string]string{UnixSocketAnnotation: "/var/running/app.sock"},
+			expOpts:     UpstreamOptions{},
+			expMsgs:     1,
+			msg:         "unix socket in a directory with the same prefix",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			opts, msgs := createUpstreamOptions(test.annotations, "/var/run")
+			g.Expect(opts).To(Equal(test.expOpts))
+			g.Expect(msgs).To(HaveLen(test.expMsgs))
+		})
+	}
+}
+
+func TestCreateUpstreamOptionsUnixSocketDisabled(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	opts, msgs := createUpstreamOptions(map[string]string{UnixSocketAnnotation: "/var/run/app.sock"}, "")
+	g.Expect(opts).To(Equal(UpstreamOptions{}))
+	g.Expect(msgs).To(Equal([]string{
+		"the annotation k8s-gateway.nginx.org/unix-socket is ignored, because the Unix socket backends are disabled",
+	}))
+}
+
+func TestCreateMaintenance(t *testing.T) {
+	tests := []struct {
+		annotations    map[string]string
+		expMaintenance *Maintenance
+		msg            string
+		expMsgs        int
+	}{
+		{
+			annotations:    nil,
+			expMaintenance: nil,
+			msg:            "no annotations",
+		},
+		{
+			annotations:    map[string]string{MaintenancePageAnnotation: "<h1>Under maintenance</h1>"},
+			expMaintenance: nil,
+			msg:            "page without maintenance mode",
+		},
+		{
+			annotations:    map[string]string{MaintenanceAnnotation: "true"},
+			expMaintenance: &Maintenance{},
+			msg:            "maintenance mode",
+		},
+		{
+			annotations: map[string]string{
+				MaintenanceAnnotation:     "true",
+				MaintenancePageAnnotation: "<h1>Under maintenance</h1>",
+			},
+			expMaintenance: &Maintenance{Page: "<h1>Under maintenance</h1>"},
+			msg:            "maintenance mode with a page",
+		},
+		{
+			annotations: map[string]string{
+				MaintenanceAnnotation:     "false",
+				MaintenancePageAnnotation: "<h1>Under maintenance</h1>",
+			},
+			expMaintenance: nil,
+			msg:            "maintenance mode disabled",
+		},
+		{
+			annotations:    map[string]string{MaintenanceAnnotation: "yes"},
+			expMaintenance: nil,
+			expMsgs:        1,
+			msg:            "invalid maintenance mode",
+		},
+		{
+			annotations: map[string]string{
+				MaintenanceAnnotation:     "true",
+				MaintenancePageAnnotation: "<p>$host</p>",
+			},
+			expMaintenance: &Maintenance{},
+			expMsgs:        1,
+			msg:            "page with a variable",
+		},
+		{
+			annotations: map[string]string{
+				MaintenanceAnnotation:     "true",
+				MaintenancePageAnnotation: strings.Repeat("a", maxMaintenancePageLength+1),
+			},
+			expMaintenance: &Maintenance{},
+			expMsgs:        1,
+			msg:            "page is too long",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			maintenance, msgs := createMaintenance(test.annotations)
+			g.Expect(maintenance).To(Equal(test.expMaintenance))
+			g.Expect(msgs).To(HaveLen(test.expMsgs))
+		})
+	}
+}
+
+func TestCreateClientHeaderBuffers(t *testing.T) {
+	tests := []struct {
+		annotations map[string]string
+		expBuffers  *ClientHeaderBuffers
+		msg         string
+		expMsgs     int
+	}{
+		{
+			annotations: nil,
+			expBuffers:  nil,
+			msg:         "no annotations",
+		},
+		{
+			annotations: map[string]string{
+				ClientHeaderBufferSizeAnnotation:   "4k",
+				LargeClientHeaderBuffersAnnotation: "8 32k",
+			},
+			expBuffers: &ClientHeaderBuffers{
+				Size:        "4k",
+				LargeNumber: 8,
+				LargeSize:   "32k",
+			},
+			msg: "both buffers",
+		},
+		{
+			annotations: map[string]string{ClientHeaderBufferSizeAnnotation: "512"},
+			expBuffers:  &ClientHeaderBuffers{Size: "512"},
+			msg:         "client header buffer size in bytes",
+		},
+		{
+			annotations: map[string]string{LargeClientHeaderBuffersAnnotation: "2 1m"},
+			expBuffers: &ClientHeaderBuffers{
+				LargeNumber: 2,
+				LargeSize:   "1m",
+			},
+			msg: "large client header buffers in megabytes",
+		},
+		{
+			annotations: map[string]string{LargeClientHeaderBuffersAnnotation: "4 1024"},
+			expBuffers: &ClientHeaderBuffers{
+				LargeNumber: 4,
+				LargeSize:   "1024",
+			},
+			msg: "minimum large client header buffer size",
+		},
+		{
+			annotations: map[string]string{
+				ClientHeaderBufferSizeAnnotation:   "4kb",
+				LargeClientHeaderBuffersAnnotation: "8 32k",
+			},
+			expBuffers: &ClientHeaderBuffers{
+				LargeNumber: 8,
+				LargeSize:   "32k",
+			},
+			expMsgs: 1,
+			msg:     "invalid client header buffer size unit",
+		},
+		{
+			annotations: map[string]string{ClientHeaderBufferSizeAnnotation: "0"},
+			expBuffers:  nil,
+			expMsgs:     1,
+			msg:         "zero client header buffer size",
+		},
+		{
+			annotations: map[string]string{ClientHeaderBufferSizeAnnotation: "1g"},
+			expBuffers:  nil,
+			expMsgs:     1,
+			msg:         "client header buffer size in gigabytes",
+		},
+		{
+			annotations: map[string]string{LargeClientHeaderBuffersAnnotation: "16k"},
+			expBuffers:  nil,
+			expMsgs:     1,
+			msg:         "large client header buffers without a number",
+		},
+		{
+			annotations: map[string]string{LargeClientHeaderBuffersAnnotation: "4  16k"},
+			expBuffers:  nil,
+			expMsgs:     1,
+			msg:         "large client header buffers with multiple spaces",
+		},
+		{
+			annotations: map[string]string{LargeClientHeaderBuffersAnnotation: "0 16k"},
+			expBuffers:  nil,
+			expMsgs:     1,
+			msg:         "zero large client header buffers",
+		},
+		{
+			annotations: map[string]string{LargeClientHeaderBuffersAnnotation: "1025 16k"},
+			expBuffers:  nil,
+			expMsgs:     1,
+			msg:         "too many large client header buffers",
+		},
+		{
+			annotations: map[string]string{LargeClientHeaderBuffersAnnotation: "4 512"},
+			expBuffers:  nil,
+			expMsgs:     1,
+			msg:         "too small large client header buffers",
+		},
+		{
+			annotations: map[string]string{LargeClientHeaderBuffersAnnotation: "4 16k;"},
+			expBuffers:  nil,
+			expMsgs:     1,
+			msg:         "invalid large client header buffer size",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			buffers, msgs := createClientHeaderBuffers(test.annotations)
+			g.Expect(buffers).To(Equal(test.expBuffers))
+			g.Expect(msgs).To(HaveLen(test.expMsgs))
+		})
+	}
+}
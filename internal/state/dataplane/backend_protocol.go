@@ -0,0 +1,100 @@
+package dataplane
+
+import (
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
+)
+
+// BackendProtocol is the protocol that NGINX uses to proxy requests to a backend.
+type BackendProtocol int
+
+const (
+	// BackendProtocolHTTP1 is HTTP/1.1. It is the protocol of the backends with an unknown or no appProtocol.
+	BackendProtocolHTTP1 BackendProtocol = iota
+	// BackendProtocolH2C is HTTP/2 over cleartext TCP. It is selected by the kubernetes.io/h2c appProtocol.
+	BackendProtocolH2C
+	// BackendProtocolGRPC is gRPC. It is selected by the grpc appProtocol.
+	BackendProtocolGRPC
+	// BackendProtocolHTTPS is HTTP/1.1 over TLS. It is selected by the https appProtocol.
+	BackendProtocolHTTPS
+)
+
+const (
+	appProtocolH2C   = "kubernetes.io/h2c"
+	appProtocolGRPC  = "grpc"
+	appProtocolHTTPS = "https"
+)
+
+// getBackendProtocol returns the protocol of the backend according to the appProtocol of the port of the Service.
+func getBackendProtocol(svc *v1.Service, port int32) BackendProtocol {
+	if svc == nil {
+		return BackendProtocolHTTP1
+	}
+
+	for _, p := range svc.Spec.Ports {
+		if p.Port != port || p.AppProtocol == nil {
+			continue
+		}
+
+		switch *p.AppProtocol {
+		case appProtocolH2C:
+			return BackendProtocolH2C
+		case appProtocolGRPC:
+			return BackendProtocolGRPC
+		case appProtocolHTTPS:
+			return BackendProtocolHTTPS
+		}
+	}
+
+	return BackendProtocolHTTP1
+}
+
+// UsesGRPCModule returns true if NGINX proxies the requests to the backends of the protocol using the gRPC module.
+func (p BackendProtocol) UsesGRPCModule() bool {
+	return p == BackendProtocolH2C || p == BackendProtocolGRPC
+}
+
+// TLSServerName returns the server name that NGINX sends to a backend Service over TLS with SNI, so that
+// the backend presents the right certificate: the external name of an ExternalName Service, or the DNS name
+// <name>.<namespace>.svc of other Services. NGINX doesn't verify the certificate of the backend.
+// It returns an empty string if the Service is nil.
+func TLSServerName(svc *v1.Service) string {
+	if svc == nil {
+		return ""
+	}
+
+	if svc.Spec.Type == v1.ServiceTypeExternalName {
+		return svc.Spec.ExternalName
+	}
+
+	return svc.Name + "." + svc.Namespace + ".svc"
+}
+
+// getBackendGroupProtocol returns the protocol of the backends of the group that receive traffic.
+// If those backends use different protocols, NGINX cannot proxy requests to all of them with the same directive,
+// so HTTP/1.1 is returned and the consistent return value is false.
+func getBackendGroupProtocol(group graph.BackendGroup) (protocol BackendProtocol, consistent bool) {
+	found := false
+
+	for _, b := range group.Backends {
+		if !b.Valid || b.Weight <= 0 {
+			continue
+		}
+
+		p := getBackendProtocol(b.Svc, b.Port)
+
+		if !found {
+			protocol = p
+			found = true
+
+			continue
+		}
+
+		if p != protocol {
+			return BackendProtocolHTTP1, false
+		}
+	}
+
+	return protocol, true
+}
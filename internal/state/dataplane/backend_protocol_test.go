@@ -0,0 +1,202 @@
+package dataplane
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
+)
+
+func createServiceWithAppProtocol(appProtocol *string) *v1.Service {
+	return &v1.Service{
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{
+				{
+					Port:        8080,
+					AppProtocol: appProtocol,
+				},
+				{
+					Port: 80,
+				},
+			},
+		},
+	}
+}
+
+func TestGetBackendProtocol(t *testing.T) {
+	tests := []struct {
+		svc      *v1.Service
+		msg      string
+		port     int32
+		expected BackendProtocol
+	}{
+		{
+			svc:      createServiceWithAppProtocol(helpers.GetStringPointer("kubernetes.io/h2c")),
+			port:     8080,
+			expected: BackendProtocolH2C,
+			msg:      "h2c",
+		},
+		{
+			svc:      createServiceWithAppProtocol(helpers.GetStringPointer("grpc")),
+			port:     8080,
+			expected: BackendProtocolGRPC,
+			msg:      "grpc",
+		},
+		{
+			svc:      createServiceWithAppProtocol(helpers.GetStringPointer("https")),
+			port:     8080,
+			expected: BackendProtocolHTTPS,
+			msg:      "https",
+		},
+		{
+			svc:      createServiceWithAppProtocol(helpers.GetStringPointer("kubernetes.io/ws")),
+			port:     8080,
+			expected: BackendProtocolHTTP1,
+			msg:      "unknown appProtocol",
+		},
+		{
+			svc:      createServiceWithAppProtocol(nil),
+			port:     8080,
+			expected: BackendProtocolHTTP1,
+			msg:      "no appProtocol",
+		},
+		{
+			svc:      createServiceWithAppProtocol(helpers.GetStringPointer("grpc")),
+			port:     80,
+			expected: BackendProtocolHTTP1,
+			msg:      "appProtocol of another port",
+		},
+		{
+			svc:      nil,
+			port:     8080,
+			expected: BackendProtocolHTTP1,
+			msg:      "no Service",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			g.Expect(getBackendProtocol(test.svc, test.port)).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestGetBackendGroupProtocol(t *testing.T) {
+	h2cSvc := createServiceWithAppProtocol(helpers.GetStringPointer("kubernetes.io/h2c"))
+	grpcSvc := createServiceWithAppProtocol(helpers.GetStringPointer("grpc"))
+	httpSvc := createServiceWithAppProtocol(nil)
+
+	tests := []struct {
+		msg           string
+		backends      []graph.BackendRef
+		expected      BackendProtocol
+		expConsistent bool
+	}{
+		{
+			msg:           "no backends",
+			expected:      BackendProtocolHTTP1,
+			expConsistent: true,
+		},
+		{
+			msg: "h2c backend",
+			backends: []graph.BackendRef{
+				{Svc: h2cSvc, Port: 8080, Valid: true, Weight: 1},
+			},
+			expected:      BackendProtocolH2C,
+			expConsistent: true,
+		},
+		{
+			msg: "grpc backends",
+			backends: []graph.BackendRef{
+				{Svc: grpcSvc, Port: 8080, Valid: true, Weight: 1},
+				{Svc: grpcSvc, Port: 8080, Valid: true, Weight: 3},
+			},
+			expected:      BackendProtocolGRPC,
+			expConsistent: true,
+		},
+		{
+			msg: "invalid and zero weight backends are ignored",
+			backends: []graph.BackendRef{
+				{Svc: httpSvc, Port: 8080, Valid: false, Weight: 1},
+				{Svc: grpcSvc, Port: 8080, Valid: true, Weight: 1},
+				{Svc: h2cSvc, Port: 8080, Valid: true, Weight: 0},
+			},
+			expected:      BackendProtocolGRPC,
+			expConsistent: true,
+		},
+		{
+			msg: "different protocols",
+			backends: []graph.BackendRef{
+				{Svc: grpcSvc, Port: 8080, Valid: true, Weight: 1},
+				{Svc: httpSvc, Port: 8080, Valid: true, Weight: 1},
+			},
+			expected:      BackendProtocolHTTP1,
+			expConsistent: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			protocol, consistent := getBackendGroupProtocol(graph.BackendGroup{Backends: test.backends})
+			g.Expect(protocol).To(Equal(test.expected))
+			g.Expect(consistent).To(Equal(test.expConsistent))
+		})
+	}
+}
+
+func TestBackendProtocolUsesGRPCModule(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(BackendProtocolHTTP1.UsesGRPCModule()).To(BeFalse())
+	g.Expect(BackendProtocolH2C.UsesGRPCModule()).To(BeTrue())
+	g.Expect(BackendProtocolGRPC.UsesGRPCModule()).To(BeTrue())
+	g.Expect(BackendProtocolHTTPS.UsesGRPCModule()).To(BeFalse())
+}
+
+func TestTLSServerName(t *testing.T) {
+	tests := []struct {
+		svc      *v1.Service
+		msg      string
+		expected string
+	}{
+		{
+			svc: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "secure"},
+			},
+			expected: "secure.test.svc",
+			msg:      "cluster Service",
+		},
+		{
+			svc: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "external"},
+				Spec: v1.ServiceSpec{
+					Type:         v1.ServiceTypeExternalName,
+					ExternalName: "api.example.com",
+				},
+			},
+			expected: "api.example.com",
+			msg:      "ExternalName Service",
+		},
+		{
+			svc:      nil,
+			expected: "",
+			msg:      "no Service",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			g.Expect(TLSServerName(test.svc)).To(Equal(test.expected))
+		})
+	}
+}
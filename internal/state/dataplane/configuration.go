@@ -4,9 +4,14 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/gateway-api/apis/v1beta1"
 
+	"github.com/nginxinc/nginx-kubernetes-gateway/apis/v1alpha1"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/resolver"
 )
@@ -26,6 +31,16 @@ type Configuration struct {
 	// BackendGroups holds all unique BackendGroups.
 	// FIXME(pleshakov): Ensure Configuration doesn't include types from the graph package.
 	BackendGroups []graph.BackendGroup
+	// Addresses holds the unique IP addresses that the servers listen on. Empty means all addresses.
+	Addresses []string
+	// Gateway is the namespaced name of the Gateway that the configuration is built for.
+	Gateway types.NamespacedName
+	// Maintenance is the maintenance mode of the Gateway, configured through the annotations of the Gateway.
+	// Nil means the maintenance mode is disabled.
+	Maintenance *Maintenance
+	// ClientHeaderBuffers are the sizes of the buffers for reading the headers of the client requests,
+	// configured through the annotations of the Gateway. Nil means the defaults.
+	ClientHeaderBuffers *ClientHeaderBuffers
 }
 
 // VirtualServer is a virtual server.
@@ -34,24 +49,72 @@ type VirtualServer struct {
 	SSL *SSL
 	// Hostname is the hostname of the server.
 	Hostname string
+	// Listener is the name of the Listener that the server is created for. It is empty for the default servers.
+	Listener string
 	// PathRules is a collection of routing rules.
 	PathRules []PathRule
-	// IsDefault indicates whether the server is the default server.
+	// DefaultBackend is the backend for the requests that don't match any routing rule. It is nil if the Listener
+	// of the server doesn't have a default backend.
+	DefaultBackend *DefaultBackend
+	// RateLimit is the limit of the rate of the requests to the server per client address, shared by all servers
+	// of the Listener. It is nil if the Listener of the server doesn't have a rate limit.
+	RateLimit *RateLimit
+	// IsDefault indicates whether the server is the default server. The default HTTPS server has SSL only
+	// if a Listener provides the default certificate; otherwise, NGINX rejects the TLS handshakes.
 	IsDefault bool
 }
 
+// RateLimit is the limit of the rate of the requests.
+type RateLimit struct {
+	// Rate is the rate in the NGINX format, for example, 10r/s.
+	Rate string
+	// Burst is the number of the requests in excess of the Rate that NGINX accepts without delay.
+	Burst int32
+}
+
+// DefaultBackend is the backend that NGINX proxies the requests that don't match any routing rule of a server to.
+type DefaultBackend struct {
+	// UpstreamName is the name of the Upstream of the backend. It is empty if the backend is invalid.
+	UpstreamName string
+	// TLSServerName is the server name that NGINX sends to the backend with SNI. It is only set for
+	// the BackendProtocolHTTPS.
+	TLSServerName string
+	// Protocol is the protocol that NGINX uses to proxy requests to the backend.
+	Protocol BackendProtocol
+}
+
 type Upstream struct {
 	// Name is the name of the Upstream. Will be unique for each service/port combination.
 	Name string
+	// Service is the namespaced name of the Service of the Upstream.
+	Service types.NamespacedName
+	// Port is the port of the Service.
+	Port int32
+	// Hostname is the external DNS name of the Service if it is of the ExternalName type. Such Services don't have
+	// the Endpoints, so NGINX can only reach them by resolving the Hostname. Empty for the other types of Services.
+	Hostname string
 	// ErrorMsg contains the error message if the Upstream is invalid.
 	ErrorMsg string
 	// Endpoints are the endpoints of the Upstream.
 	Endpoints []resolver.Endpoint
+	// DrainingEndpoints are the endpoints recently removed from the Upstream. NGINX doesn't send new requests
+	// to them, but keeps them in the Upstream until their removal grace period expires.
+	DrainingEndpoints []resolver.Endpoint
+	// Options holds the options configured through the annotations of the Service.
+	Options UpstreamOptions
 }
 
 type SSL struct {
 	// CertificatePath is the path to the certificate file.
 	CertificatePath string
+	// SecondaryCertificatePath is the path to the second certificate file, which has a different key type than
+	// the first one. Can be empty.
+	SecondaryCertificatePath string
+	// ClientCertificatePath is the path to the bundle of the CA certificates for verifying the client certificates.
+	// Empty means the client certificates are not verified.
+	ClientCertificatePath string
+	// Options holds the TLS options configured through the options of the TLS config of the listener.
+	Options TLSOptions
 }
 
 // PathRule represents routing rules that share a common path.
@@ -65,6 +128,13 @@ type PathRule struct {
 // Filters hold the filters for a MatchRule.
 type Filters struct {
 	RequestRedirect *v1beta1.HTTPRequestRedirectFilter
+	// CORSPolicy is the CORSPolicy referenced through an ExtensionRef filter.
+	CORSPolicy *v1alpha1.CORSPolicy
+	// DirectResponse is the DirectResponse referenced through an ExtensionRef filter.
+	DirectResponse *v1alpha1.DirectResponse
+	// InvalidExtensionRef is true if an ExtensionRef filter cannot be resolved. In that case, requests must
+	// receive an error response.
+	InvalidExtensionRef bool
 }
 
 // MatchRule represents a routing rule. It corresponds directly to a Match in the HTTPRoute resource.
@@ -74,12 +144,16 @@ type Filters struct {
 type MatchRule struct {
 	// Filters holds the filters for the MatchRule.
 	Filters Filters
+	// Options holds the options for the MatchRule configured through the annotations of the HTTPRoute.
+	Options RouteOptions
 	// Source is the corresponding HTTPRoute resource.
 	// FIXME(pleshakov): Consider referencing only the parts needed for the config generation rather than
 	// the entire resource.
 	Source *v1beta1.HTTPRoute
 	// BackendGroup is the group of Backends that the rule routes to.
 	BackendGroup graph.BackendGroup
+	// BackendProtocol is the protocol that NGINX uses to proxy requests to the Backends.
+	BackendProtocol BackendProtocol
 	// MatchIdx is the index of the rule in the Rule.Matches.
 	MatchIdx int
 	// RuleIdx is the index of the corresponding rule in the HTTPRoute.
@@ -92,11 +166,14 @@ func (r *MatchRule) GetMatch() v1beta1.HTTPRouteMatch {
 }
 
 // BuildConfiguration builds the Configuration from the Graph.
+// unixSocketDir is the directory of the Unix domain sockets that the Services can configure as their backends
+// with the UnixSocketAnnotation. Empty means the Unix socket backends are disabled.
 // FIXME(pleshakov) For now we only handle paths with prefix matches. Handle exact and regex matches
 func BuildConfiguration(
 	ctx context.Context,
 	g *graph.Graph,
 	resolver resolver.ServiceResolver,
+	unixSocketDir string,
 ) (Configuration, Warnings) {
 	if g.GatewayClass == nil || !g.GatewayClass.Valid {
 		return Configuration{}, nil
@@ -106,22 +183,53 @@ func BuildConfiguration(
 		return Configuration{}, nil
 	}
 
-	upstreamsMap := buildUpstreamsMap(ctx, g.Gateway.Listeners, resolver)
+	upstreamsMap := buildUpstreamsMap(ctx, g.Gateway.Listeners, resolver, unixSocketDir)
 	httpServers, sslServers := buildServers(g.Gateway.Listeners)
-	backendGroups := buildBackendGroups(g.Gateway.Listeners)
+	backendGroups := buildBackendGroups(g.Gateway.Listeners, httpServers, sslServers)
+
+	warnings := buildWarnings(g, upstreamsMap, unixSocketDir)
 
-	warnings := buildWarnings(g, upstreamsMap)
+	maintenance, _ := createMaintenance(g.Gateway.Source.Annotations)
+	clientHeaderBuffers, _ := createClientHeaderBuffers(g.Gateway.Source.Annotations)
 
 	config := Configuration{
-		HTTPServers:   httpServers,
-		SSLServers:    sslServers,
-		Upstreams:     upstreamsMapToSlice(upstreamsMap),
-		BackendGroups: backendGroups,
+		HTTPServers:         httpServers,
+		SSLServers:          sslServers,
+		Upstreams:           upstreamsMapToSlice(upstreamsMap),
+		BackendGroups:       backendGroups,
+		Addresses:           buildAddresses(g.Gateway.Source.Spec.Addresses),
+		Gateway:             client.ObjectKeyFromObject(g.Gateway.Source),
+		Maintenance:         maintenance,
+		ClientHeaderBuffers: clientHeaderBuffers,
 	}
 
 	return config, warnings
 }
 
+// buildAddresses returns the unique values of the addresses of the Gateway in their original order.
+// The Graph ensures that all addresses are IP addresses; otherwise, the Listeners are invalid and there are no servers.
+func buildAddresses(gwAddresses []v1beta1.GatewayAddress) []string {
+	if len(gwAddresses) == 0 {
+		return nil
+	}
+
+	addresses := make([]string, 0, len(gwAddresses))
+	seen := make(map[string]struct{}, len(gwAddresses))
+
+	for _, a := range gwAddresses {
+		if _, exists := seen[a.Value]; exists {
+			continue
+		}
+
+		seen[a.Value] = struct{}{}
+		addresses = append(addresses, a.Value)
+	}
+
+	return addresses
+}
+
+// upstreamsMapToSlice returns the upstreams sorted by name. The iteration order of the map is random,
+// so that without sorting, the same upstreams would produce different NGINX configurations.
 func upstreamsMapToSlice(upstreamsMap map[string]Upstream) []Upstream {
 	if len(upstreamsMap) == 0 {
 		return nil
@@ -132,13 +240,45 @@ func upstreamsMapToSlice(upstreamsMap map[string]Upstream) []Upstream {
 		upstreams = append(upstreams, upstream)
 	}
 
+	sort.Slice(upstreams, func(i, j int) bool {
+		return upstreams[i].Name < upstreams[j].Name
+	})
+
 	return upstreams
 }
 
-func buildWarnings(graph *graph.Graph, upstreams map[string]Upstream) Warnings {
+func buildWarnings(graph *graph.Graph, upstreams map[string]Upstream, unixSocketDir string) Warnings {
 	warnings := newWarnings()
 
+	_, msgs := createMaintenance(graph.Gateway.Source.Annotations)
+	for _, msg := range msgs {
+		warnings.AddWarning(graph.Gateway.Source, msg)
+	}
+
+	_, msgs = createClientHeaderBuffers(graph.Gateway.Source.Annotations)
+	for _, msg := range msgs {
+		warnings.AddWarning(graph.Gateway.Source, msg)
+	}
+
 	for _, l := range graph.Gateway.Listeners {
+		if l.Valid && l.Source.TLS != nil {
+			_, msgs := createTLSOptions(l.Source.TLS.Options)
+			for _, msg := range msgs {
+				warnings.AddWarningf(graph.Gateway.Source, "listener %s: %s", l.Source.Name, msg)
+			}
+		}
+
+		if l.Valid && l.DefaultBackend != nil && l.DefaultBackend.Name != "" {
+			if upstream := upstreams[l.DefaultBackend.Name]; upstream.ErrorMsg != "" {
+				warnings.AddWarningf(
+					graph.Gateway.Source,
+					"listener %s: cannot resolve default backend: %s",
+					l.Source.Name,
+					upstream.ErrorMsg,
+				)
+			}
+		}
+
 		for _, r := range l.Routes {
 			if !l.Valid {
 				warnings.AddWarningf(
@@ -150,12 +290,25 @@ func buildWarnings(graph *graph.Graph, upstreams map[string]Upstream) Warnings {
 				continue
 			}
 
+			_, msgs := createRouteOptions(r.Source.Annotations)
+			for _, msg := range msgs {
+				warnings.AddWarning(r.Source, msg)
+			}
+
 			for _, group := range r.BackendGroups {
 
 				for _, errMsg := range group.Errors {
 					warnings.AddWarningf(r.Source, "invalid backend ref: %s", errMsg)
 				}
 
+				if _, consistent := getBackendGroupProtocol(group); !consistent {
+					warnings.AddWarningf(
+						r.Source,
+						"rule %d: backend refs use different protocols; NGINX proxies requests to them over HTTP/1.1",
+						group.RuleIdx,
+					)
+				}
+
 				for _, backend := range group.Backends {
 					if backend.Name != "" {
 						upstream, ok := upstreams[backend.Name]
@@ -176,6 +329,19 @@ func buildWarnings(graph *graph.Graph, upstreams map[string]Upstream) Warnings {
 								upstream.ErrorMsg,
 							)
 						}
+
+						if backend.Svc != nil {
+							_, msgs := createUpstreamOptions(backend.Svc.Annotations, unixSocketDir)
+							for _, msg := range msgs {
+								warnings.AddWarningf(
+									r.Source,
+									"backend ref Service %s/%s: %s",
+									backend.Svc.Namespace,
+									backend.Svc.Name,
+									msg,
+								)
+							}
+						}
 					}
 				}
 			}
@@ -189,11 +355,24 @@ func buildWarnings(graph *graph.Graph, upstreams map[string]Upstream) Warnings {
 	return warnings
 }
 
-func buildBackendGroups(listeners map[string]*graph.Listener) []graph.BackendGroup {
+func buildBackendGroups(listeners map[string]*graph.Listener, servers ...[]VirtualServer) []graph.BackendGroup {
 	// There can be duplicate backend groups if a route is attached to multiple listeners.
 	// We use a map to deduplicate them.
 	uniqueGroups := make(map[string]graph.BackendGroup)
 
+	// The servers include the backend groups that merge the groups of multiple routes.
+	for _, ss := range servers {
+		for _, s := range ss {
+			for _, pr := range s.PathRules {
+				for _, mr := range pr.MatchRules {
+					if mr.BackendGroup.Name != "" {
+						uniqueGroups[mr.BackendGroup.GroupName()] = mr.BackendGroup
+					}
+				}
+			}
+		}
+	}
+
 	for _, l := range listeners {
 
 		if !l.Valid {
@@ -220,6 +399,12 @@ func buildBackendGroups(listeners map[string]*graph.Listener) []graph.BackendGro
 		groups = append(groups, group)
 	}
 
+	// Sort the groups, so that the split clients of the groups are generated in the same order
+	// and the NGINX configuration doesn't change across the builds if the groups don't change.
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].GroupName() < groups[j].GroupName()
+	})
+
 	return groups
 }
 
@@ -229,7 +414,16 @@ func buildServers(listeners map[string]*graph.Listener) (http, ssl []VirtualServ
 		v1beta1.HTTPSProtocolType: newHostPathRules(),
 	}
 
-	for _, l := range listeners {
+	// The listeners are upserted in the order of their names, so that the listener of a hostname claimed by
+	// multiple listeners doesn't change across reconciles.
+	names := make([]string, 0, len(listeners))
+	for name := range listeners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		l := listeners[name]
 		if l.Valid {
 			rules := rulesForProtocol[l.Source.Protocol]
 			rules.upsertListener(l)
@@ -246,7 +440,10 @@ type hostPathRules struct {
 	rulesPerHost     map[string]map[string]PathRule
 	listenersForHost map[string]*graph.Listener
 	httpsListeners   []*graph.Listener
-	listenersExist   bool
+	httpListeners    []*graph.Listener
+	// defaultCertificateListener is the Listener that provides the certificates of the default server.
+	defaultCertificateListener *graph.Listener
+	listenersExist             bool
 }
 
 func newHostPathRules() *hostPathRules {
@@ -254,6 +451,7 @@ func newHostPathRules() *hostPathRules {
 		rulesPerHost:     make(map[string]map[string]PathRule),
 		listenersForHost: make(map[string]*graph.Listener),
 		httpsListeners:   make([]*graph.Listener, 0),
+		httpListeners:    make([]*graph.Listener, 0),
 	}
 }
 
@@ -262,9 +460,21 @@ func (hpr *hostPathRules) upsertListener(l *graph.Listener) {
 
 	if l.Source.Protocol == v1beta1.HTTPSProtocolType {
 		hpr.httpsListeners = append(hpr.httpsListeners, l)
+
+		if l.DefaultCertificate {
+			hpr.defaultCertificateListener = l
+		}
+	} else {
+		hpr.httpListeners = append(hpr.httpListeners, l)
 	}
 
 	for _, r := range l.Routes {
+		opts, _ := createRouteOptions(r.Source.Annotations)
+
+		if !opts.appliesToListener(l, len(r.Source.Spec.Rules)) {
+			continue
+		}
+
 		var hostnames []string
 
 		for _, h := range r.Source.Spec.Hostnames {
@@ -273,8 +483,20 @@ func (hpr *hostPathRules) upsertListener(l *graph.Listener) {
 			}
 		}
 
+		// A wildcard hostname of the route also matches the more specific accepted hostnames of the listener.
+		// For example, the route hostname *.example.com matches the listener hostname foo.example.com.
+		for accepted := range l.AcceptedHostnames {
+			if !containsHostname(r.Source.Spec.Hostnames, accepted) &&
+				isMatchedByWildcardHostname(accepted, r.Source.Spec.Hostnames) {
+				hostnames = append(hostnames, accepted)
+			}
+		}
+
 		for _, h := range hostnames {
-			hpr.listenersForHost[h] = l
+			// the first listener of the hostname wins
+			if _, exist := hpr.listenersForHost[h]; !exist {
+				hpr.listenersForHost[h] = l
+			}
 
 			if _, exist := hpr.rulesPerHost[h]; !exist {
 				hpr.rulesPerHost[h] = make(map[string]PathRule)
@@ -282,7 +504,16 @@ func (hpr *hostPathRules) upsertListener(l *graph.Listener) {
 		}
 
 		for i, rule := range r.Source.Spec.Rules {
-			filters := createFilters(rule.Filters)
+			if _, invalid := r.InvalidRules[i]; invalid {
+				continue
+			}
+
+			if !opts.ruleAppliesToListener(i, l) {
+				continue
+			}
+
+			filters := createFilters(rule.Filters, r.RuleFilters[i])
+			protocol, _ := getBackendGroupProtocol(r.BackendGroups[i])
 
 			for _, h := range hostnames {
 				for j, m := range rule.Matches {
@@ -294,11 +525,13 @@ func (hpr *hostPathRules) upsertListener(l *graph.Listener) {
 					}
 
 					rule.MatchRules = append(rule.MatchRules, MatchRule{
-						MatchIdx:     j,
-						RuleIdx:      i,
-						Source:       r.Source,
-						BackendGroup: r.BackendGroups[i],
-						Filters:      filters,
+						MatchIdx:        j,
+						RuleIdx:         i,
+						Source:          r.Source,
+						BackendGroup:    r.BackendGroups[i],
+						BackendProtocol: protocol,
+						Filters:         filters,
+						Options:         opts,
 					})
 
 					hpr.rulesPerHost[h][path] = rule
@@ -312,22 +545,27 @@ func (hpr *hostPathRules) buildServers() []VirtualServer {
 	servers := make([]VirtualServer, 0, len(hpr.rulesPerHost)+len(hpr.httpsListeners))
 
 	for h, rules := range hpr.rulesPerHost {
-		s := VirtualServer{
-			Hostname:  h,
-			PathRules: make([]PathRule, 0, len(rules)),
-		}
-
 		l, ok := hpr.listenersForHost[h]
 		if !ok {
 			panic(fmt.Sprintf("no listener found for hostname: %s", h))
 		}
 
+		s := VirtualServer{
+			Hostname:  h,
+			Listener:  string(l.Source.Name),
+			PathRules: make([]PathRule, 0, len(rules)),
+		}
+
 		if l.SecretPath != "" {
-			s.SSL = &SSL{CertificatePath: l.SecretPath}
+			s.SSL = createSSL(l)
 		}
 
+		s.DefaultBackend = createDefaultBackend(l)
+		s.RateLimit = createRateLimit(l)
+
 		for _, r := range rules {
 			sortMatchRules(r.MatchRules)
+			r.MatchRules = mergeWeightedMatchRules(r.MatchRules)
 
 			s.PathRules = append(s.PathRules, r)
 		}
@@ -343,72 +581,193 @@ func (hpr *hostPathRules) buildServers() []VirtualServer {
 	for _, l := range hpr.httpsListeners {
 		hostname := getListenerHostname(l.Source.Hostname)
 		// generate a 404 ssl server block for listeners with no routes or listeners with wildcard (match-all) routes
-		// FIXME(kate-osborn): when we support regex hostnames (e.g. *.example.com)
-		// we will have to modify this check to catch regex hostnames.
-		if len(l.Routes) == 0 || hostname == wildcardHostname {
+		// or wildcard hostnames (e.g. *.example.com), unless the routes already have a server block for the hostname.
+		_, hostnameHasRules := hpr.rulesPerHost[hostname]
+
+		if len(l.Routes) == 0 || hostname == wildcardHostname || (isWildcardHostname(hostname) && !hostnameHasRules) {
 			s := VirtualServer{
-				Hostname: hostname,
+				Hostname:       hostname,
+				Listener:       string(l.Source.Name),
+				DefaultBackend: createDefaultBackend(l),
+				RateLimit:      createRateLimit(l),
 			}
 
 			if l.SecretPath != "" {
-				s.SSL = &SSL{CertificatePath: l.SecretPath}
+				s.SSL = createSSL(l)
 			}
 
 			servers = append(servers, s)
 		}
 	}
 
+	for _, l := range hpr.httpListeners {
+		// Without a default backend, the default server responds with 404 to the requests for the hostname
+		// of a listener without routes for the hostname.
+		if l.DefaultBackend == nil {
+			continue
+		}
+
+		hostname := getListenerHostname(l.Source.Hostname)
+		if _, hostnameHasRules := hpr.rulesPerHost[hostname]; hostnameHasRules {
+			continue
+		}
+
+		servers = append(servers, VirtualServer{
+			Hostname:       hostname,
+			Listener:       string(l.Source.Name),
+			DefaultBackend: createDefaultBackend(l),
+			RateLimit:      createRateLimit(l),
+		})
+	}
+
 	// if any listeners exist, we need to generate a default server block.
 	if hpr.listenersExist {
-		servers = append(servers, VirtualServer{IsDefault: true})
+		servers = append(servers, VirtualServer{
+			IsDefault: true,
+			SSL:       createDefaultSSL(hpr.defaultCertificateListener),
+		})
 	}
 
-	// We sort the servers so the order is preserved after reconfiguration.
-	sort.Slice(servers, func(i, j int) bool {
+	// We sort the servers so the order is preserved after reconfiguration. The servers of the listeners
+	// with the same hostname keep the order of the listeners.
+	sort.SliceStable(servers, func(i, j int) bool {
 		return servers[i].Hostname < servers[j].Hostname
 	})
 
 	return servers
 }
 
+func createDefaultBackend(l *graph.Listener) *DefaultBackend {
+	if l.DefaultBackend == nil {
+		return nil
+	}
+
+	backend := &DefaultBackend{
+		UpstreamName: l.DefaultBackend.Name,
+		Protocol:     getBackendProtocol(l.DefaultBackend.Svc, l.DefaultBackend.Port),
+	}
+
+	if backend.Protocol == BackendProtocolHTTPS {
+		backend.TLSServerName = TLSServerName(l.DefaultBackend.Svc)
+	}
+
+	return backend
+}
+
+func createRateLimit(l *graph.Listener) *RateLimit {
+	if l.RateLimit == nil {
+		return nil
+	}
+
+	return &RateLimit{
+		Rate:  l.RateLimit.Rate,
+		Burst: l.RateLimit.Burst,
+	}
+}
+
+// createDefaultSSL creates the SSL of the default server from the certificates of the Listener. The TLS options
+// and the verification of the client certificates of the Listener don't apply to the default server.
+// It returns nil if there is no Listener.
+func createDefaultSSL(l *graph.Listener) *SSL {
+	if l == nil {
+		return nil
+	}
+
+	return &SSL{
+		CertificatePath:          l.SecretPath,
+		SecondaryCertificatePath: l.SecondarySecretPath,
+	}
+}
+
+func createSSL(l *graph.Listener) *SSL {
+	ssl := &SSL{
+		CertificatePath:          l.SecretPath,
+		SecondaryCertificatePath: l.SecondarySecretPath,
+		ClientCertificatePath:    l.ClientCertificatePath,
+	}
+
+	if l.Source.TLS != nil {
+		ssl.Options, _ = createTLSOptions(l.Source.TLS.Options)
+	}
+
+	return ssl
+}
+
 func buildUpstreamsMap(
 	ctx context.Context,
 	listeners map[string]*graph.Listener,
-	resolver resolver.ServiceResolver,
+	svcResolver resolver.ServiceResolver,
+	unixSocketDir string,
 ) map[string]Upstream {
 	// There can be duplicate upstreams if multiple routes reference the same upstream.
 	// We use a map to deduplicate them.
 	uniqueUpstreams := make(map[string]Upstream)
 
+	addUpstream := func(backend graph.BackendRef) {
+		name := backend.Name
+		if name == "" {
+			return
+		}
+
+		if _, exist := uniqueUpstreams[name]; exist {
+			return
+		}
+
+		var (
+			opts    UpstreamOptions
+			svcName types.NamespacedName
+		)
+		if backend.Svc != nil {
+			opts, _ = createUpstreamOptions(backend.Svc.Annotations, unixSocketDir)
+			svcName = client.ObjectKeyFromObject(backend.Svc)
+		}
+
+		var (
+			errMsg   string
+			eps      []resolver.Endpoint
+			hostname string
+		)
+
+		switch {
+		case opts.UnixSocket != "":
+			// The Unix socket replaces the endpoints of the Service, so they are not resolved.
+		case backend.Svc != nil && backend.Svc.Spec.Type == v1.ServiceTypeExternalName:
+			// ExternalName Services don't have EndpointSlices.
+			hostname = backend.Svc.Spec.ExternalName
+		default:
+			var err error
+			if eps, err = svcResolver.Resolve(ctx, backend.Svc, backend.Port); err != nil {
+				errMsg = err.Error()
+			}
+
+			sortEndpoints(eps)
+		}
+
+		uniqueUpstreams[name] = Upstream{
+			Name:      name,
+			Service:   svcName,
+			Port:      backend.Port,
+			Hostname:  hostname,
+			Endpoints: eps,
+			ErrorMsg:  errMsg,
+			Options:   opts,
+		}
+	}
+
 	for _, l := range listeners {
 
 		if !l.Valid {
 			continue
 		}
 
+		if l.DefaultBackend != nil {
+			addUpstream(*l.DefaultBackend)
+		}
+
 		for _, route := range l.Routes {
 			for _, group := range route.BackendGroups {
 				for _, backend := range group.Backends {
-					if name := backend.Name; name != "" {
-						_, exist := uniqueUpstreams[name]
-
-						if exist {
-							continue
-						}
-
-						var errMsg string
-
-						eps, err := resolver.Resolve(ctx, backend.Svc, backend.Port)
-						if err != nil {
-							errMsg = err.Error()
-						}
-
-						uniqueUpstreams[name] = Upstream{
-							Name:      name,
-							Endpoints: eps,
-							ErrorMsg:  errMsg,
-						}
-					}
+					addUpstream(backend)
 				}
 			}
 		}
@@ -417,6 +776,18 @@ func buildUpstreamsMap(
 	return uniqueUpstreams
 }
 
+// sortEndpoints sorts the endpoints by address and port. The resolver returns the endpoints in a random order,
+// so that without sorting, the same endpoints would produce different NGINX configurations.
+func sortEndpoints(endpoints []resolver.Endpoint) {
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Address != endpoints[j].Address {
+			return endpoints[i].Address < endpoints[j].Address
+		}
+
+		return endpoints[i].Port < endpoints[j].Port
+	})
+}
+
 func getListenerHostname(h *v1beta1.Hostname) string {
 	if h == nil || *h == "" {
 		return wildcardHostname
@@ -425,18 +796,70 @@ func getListenerHostname(h *v1beta1.Hostname) string {
 	return string(*h)
 }
 
+func isWildcardHostname(h string) bool {
+	return strings.HasPrefix(h, "*.")
+}
+
+// isMatchedByWildcardHostname returns true if one of the wildcard hostnames matches the more specific hostname h.
+// A wildcard hostname (*.example.com) matches hostnames with any number of additional labels (foo.example.com,
+// foo.bar.example.com, *.foo.example.com), but not the hostname without the wildcard (example.com).
+func isMatchedByWildcardHostname(h string, hostnames []v1beta1.Hostname) bool {
+	for _, wildcard := range hostnames {
+		w := string(wildcard)
+
+		if isWildcardHostname(w) && len(h) > len(w)-1 && strings.HasSuffix(h, w[1:]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsHostname(hostnames []v1beta1.Hostname, h string) bool {
+	for _, hostname := range hostnames {
+		if string(hostname) == h {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getPath returns the path of a PathPrefix match. Because a PathPrefix match is on path elements, its trailing
+// slash is ignored, so that the prefixes /foo and /foo/ are the same path.
 func getPath(path *v1beta1.HTTPPathMatch) string {
 	if path == nil || path.Value == nil || *path.Value == "" {
 		return "/"
 	}
-	return *path.Value
+
+	p := strings.TrimRight(*path.Value, "/")
+	if p == "" {
+		return "/"
+	}
+
+	return p
 }
 
-func createFilters(filters []v1beta1.HTTPRouteFilter) Filters {
-	var result Filters
+// createFilters creates the Filters of a rule. The filters apply in the order they are listed. Because
+// a RequestRedirect or a DirectResponse filter responds to the request, the filters listed after it don't apply.
+// For example, the CORSPolicy of an ExtensionRef filter listed after a RequestRedirect filter doesn't add its
+// headers to the redirect responses.
+func createFilters(filters []v1beta1.HTTPRouteFilter, ruleFilters graph.RuleFilters) Filters {
+	result := Filters{
+		InvalidExtensionRef: !ruleFilters.Valid,
+	}
 
 	for _, f := range filters {
 		switch f.Type {
+		case v1beta1.HTTPRouteFilterExtensionRef:
+			if ruleFilters.CORSPolicy != nil && isCORSPolicyRef(f.ExtensionRef, ruleFilters.CORSPolicy) {
+				result.CORSPolicy = ruleFilters.CORSPolicy
+			}
+			if ruleFilters.DirectResponse != nil && isDirectResponseRef(f.ExtensionRef, ruleFilters.DirectResponse) {
+				result.DirectResponse = ruleFilters.DirectResponse
+				// using the first filter
+				return result
+			}
 		case v1beta1.HTTPRouteFilterRequestRedirect:
 			result.RequestRedirect = f.RequestRedirect
 			// using the first filter
@@ -446,3 +869,19 @@ func createFilters(filters []v1beta1.HTTPRouteFilter) Filters {
 
 	return result
 }
+
+// isCORSPolicyRef returns true if the ExtensionRef references the CORSPolicy.
+func isCORSPolicyRef(ref *v1beta1.LocalObjectReference, policy *v1alpha1.CORSPolicy) bool {
+	return ref != nil &&
+		ref.Group == v1alpha1.GroupName &&
+		ref.Kind == v1alpha1.CORSPolicyKind &&
+		string(ref.Name) == policy.Name
+}
+
+// isDirectResponseRef returns true if the ExtensionRef references the DirectResponse.
+func isDirectResponseRef(ref *v1beta1.LocalObjectReference, response *v1alpha1.DirectResponse) bool {
+	return ref != nil &&
+		ref.Group == v1alpha1.GroupName &&
+		ref.Kind == v1alpha1.DirectResponseKind &&
+		string(ref.Name) == response.Name
+}
@@ -4,15 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/gateway-api/apis/v1beta1"
 
+	"github.com/nginxinc/nginx-kubernetes-gateway/apis/v1alpha1"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/conditions"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
@@ -75,6 +79,8 @@ func TestBuildConfiguration(t *testing.T) {
 
 	fooUpstream := Upstream{
 		Name:      fooUpstreamName,
+		Service:   types.NamespacedName{Namespace: "test", Name: "foo"},
+		Port:      80,
 		Endpoints: fooEndpoints,
 	}
 
@@ -109,6 +115,10 @@ func TestBuildConfiguration(t *testing.T) {
 			InvalidSectionNameRefs: make(map[string]conditions.Condition),
 			ValidSectionNameRefs:   map[string]struct{}{validSectionName: {}},
 			BackendGroups:          groups,
+			RuleFilters:            make([]graph.RuleFilters, len(groups)),
+		}
+		for i := range r.RuleFilters {
+			r.RuleFilters[i].Valid = true
 		}
 		return r
 	}
@@ -188,6 +198,7 @@ func TestBuildConfiguration(t *testing.T) {
 		InvalidSectionNameRefs: make(map[string]conditions.Condition),
 		ValidSectionNameRefs:   map[string]struct{}{"listener-80-1": {}},
 		BackendGroups:          []graph.BackendGroup{hr5BackendGroup},
+		RuleFilters:            []graph.RuleFilters{{Valid: true}},
 	}
 
 	listener80 := v1beta1.Listener{
@@ -267,6 +278,62 @@ func TestBuildConfiguration(t *testing.T) {
 			},
 			msg: "no listeners and routes",
 		},
+		{
+			graph: &graph.Graph{
+				GatewayClass: &graph.GatewayClass{
+					Source: &v1beta1.GatewayClass{},
+					Valid:  true,
+				},
+				Gateway: &graph.Gateway{
+					Source: &v1beta1.Gateway{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								MaintenanceAnnotation:     "true",
+								MaintenancePageAnnotation: "<h1>Under maintenance</h1>",
+							},
+						},
+					},
+					Listeners: map[string]*graph.Listener{},
+				},
+				Routes: map[types.NamespacedName]*graph.Route{},
+			},
+			expConf: Configuration{
+				HTTPServers: []VirtualServer{},
+				SSLServers:  []VirtualServer{},
+				Maintenance: &Maintenance{Page: "<h1>Under maintenance</h1>"},
+			},
+			msg: "gateway in maintenance mode",
+		},
+		{
+			graph: &graph.Graph{
+				GatewayClass: &graph.GatewayClass{
+					Source: &v1beta1.GatewayClass{},
+					Valid:  true,
+				},
+				Gateway: &graph.Gateway{
+					Source: &v1beta1.Gateway{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								ClientHeaderBufferSizeAnnotation:   "4k",
+								LargeClientHeaderBuffersAnnotation: "8 32k",
+							},
+						},
+					},
+					Listeners: map[string]*graph.Listener{},
+				},
+				Routes: map[types.NamespacedName]*graph.Route{},
+			},
+			expConf: Configuration{
+				HTTPServers: []VirtualServer{},
+				SSLServers:  []VirtualServer{},
+				ClientHeaderBuffers: &ClientHeaderBuffers{
+					Size:        "4k",
+					LargeNumber: 8,
+					LargeSize:   "32k",
+				},
+			},
+			msg: "gateway with client header buffers",
+		},
 		{
 			graph: &graph.Graph{
 				GatewayClass: &graph.GatewayClass{
@@ -331,10 +398,12 @@ func TestBuildConfiguration(t *testing.T) {
 					},
 					{
 						Hostname: string(hostname),
+						Listener: "listener-443-with-hostname",
 						SSL:      &SSL{CertificatePath: secretPath},
 					},
 					{
 						Hostname: wildcardHostname,
+						Listener: "listener-443-1",
 						SSL:      &SSL{CertificatePath: secretPath},
 					},
 				},
@@ -415,6 +484,7 @@ func TestBuildConfiguration(t *testing.T) {
 					},
 					{
 						Hostname: "bar.example.com",
+						Listener: "listener-80-1",
 						PathRules: []PathRule{
 							{
 								Path: "/",
@@ -431,6 +501,7 @@ func TestBuildConfiguration(t *testing.T) {
 					},
 					{
 						Hostname: "foo.example.com",
+						Listener: "listener-80-1",
 						PathRules: []PathRule{
 							{
 								Path: "/",
@@ -501,6 +572,7 @@ func TestBuildConfiguration(t *testing.T) {
 					},
 					{
 						Hostname: "bar.example.com",
+						Listener: "listener-443-1",
 						PathRules: []PathRule{
 							{
 								Path: "/",
@@ -520,6 +592,7 @@ func TestBuildConfiguration(t *testing.T) {
 					},
 					{
 						Hostname: "example.com",
+						Listener: "listener-443-with-hostname",
 						PathRules: []PathRule{
 							{
 								Path: "/",
@@ -539,6 +612,7 @@ func TestBuildConfiguration(t *testing.T) {
 					},
 					{
 						Hostname: "foo.example.com",
+						Listener: "listener-443-1",
 						PathRules: []PathRule{
 							{
 								Path: "/",
@@ -558,6 +632,7 @@ func TestBuildConfiguration(t *testing.T) {
 					},
 					{
 						Hostname: wildcardHostname,
+						Listener: "listener-443-1",
 						SSL:      &SSL{CertificatePath: secretPath},
 					},
 				},
@@ -614,6 +689,7 @@ func TestBuildConfiguration(t *testing.T) {
 					},
 					{
 						Hostname: "foo.example.com",
+						Listener: "listener-80-1",
 						PathRules: []PathRule{
 							{
 								Path: "/",
@@ -663,6 +739,7 @@ func TestBuildConfiguration(t *testing.T) {
 					},
 					{
 						Hostname: "foo.example.com",
+						Listener: "listener-443-1",
 						SSL: &SSL{
 							CertificatePath: secretPath,
 						},
@@ -710,6 +787,7 @@ func TestBuildConfiguration(t *testing.T) {
 					},
 					{
 						Hostname: wildcardHostname,
+						Listener: "listener-443-1",
 						SSL:      &SSL{CertificatePath: secretPath},
 					},
 				},
@@ -825,6 +903,7 @@ func TestBuildConfiguration(t *testing.T) {
 					},
 					{
 						Hostname: "foo.example.com",
+						Listener: "listener-80-1",
 						PathRules: []PathRule{
 							{
 								Path: "/",
@@ -851,16 +930,12 @@ func TestBuildConfiguration(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		result, warns := BuildConfiguration(context.TODO(), test.graph, fakeResolver)
+		result, warns := BuildConfiguration(context.TODO(), test.graph, fakeResolver, "")
 
 		sort.Slice(result.BackendGroups, func(i, j int) bool {
 			return result.BackendGroups[i].GroupName() < result.BackendGroups[j].GroupName()
 		})
 
-		sort.Slice(result.Upstreams, func(i, j int) bool {
-			return result.Upstreams[i].Name < result.Upstreams[j].Name
-		})
-
 		if diff := cmp.Diff(test.expConf, result); diff != "" {
 			t.Errorf("BuildConfiguration() %q mismatch for configuration (-want +got):\n%s", test.msg, diff)
 		}
@@ -871,123 +946,162 @@ func TestBuildConfiguration(t *testing.T) {
 	}
 }
 
-func TestGetPath(t *testing.T) {
-	tests := []struct {
-		path     *v1beta1.HTTPPathMatch
-		expected string
-		msg      string
-	}{
-		{
-			path:     &v1beta1.HTTPPathMatch{Value: helpers.GetStringPointer("/abc")},
-			expected: "/abc",
-			msg:      "normal case",
-		},
-		{
-			path:     nil,
-			expected: "/",
-			msg:      "nil path",
-		},
-		{
-			path:     &v1beta1.HTTPPathMatch{Value: nil},
-			expected: "/",
-			msg:      "nil value",
-		},
-		{
-			path:     &v1beta1.HTTPPathMatch{Value: helpers.GetStringPointer("")},
-			expected: "/",
-			msg:      "empty value",
-		},
+func TestBuildServersWildcardHostnames(t *testing.T) {
+	createRoute := func(name, hostname, path string) *graph.Route {
+		hr := &v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test",
+				Name:      name,
+			},
+			Spec: v1beta1.HTTPRouteSpec{
+				Hostnames: []v1beta1.Hostname{v1beta1.Hostname(hostname)},
+				Rules: []v1beta1.HTTPRouteRule{
+					{
+						Matches: []v1beta1.HTTPRouteMatch{
+							{
+								Path: &v1beta1.HTTPPathMatch{
+									Value: helpers.GetStringPointer(path),
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		return &graph.Route{
+			Source:        hr,
+			BackendGroups: []graph.BackendGroup{{Source: types.NamespacedName{Namespace: "test", Name: name}}},
+			RuleFilters:   []graph.RuleFilters{{Valid: true}},
+		}
 	}
 
-	for _, test := range tests {
-		result := getPath(test.path)
-		if result != test.expected {
-			t.Errorf("getPath() returned %q but expected %q for the case of %q", result, test.expected, test.msg)
+	createMatchRule := func(r *graph.Route) MatchRule {
+		return MatchRule{
+			Source:       r.Source,
+			BackendGroup: r.BackendGroups[0],
 		}
 	}
-}
 
-func TestCreateFilters(t *testing.T) {
-	redirect1 := v1beta1.HTTPRouteFilter{
-		Type: v1beta1.HTTPRouteFilterRequestRedirect,
-		RequestRedirect: &v1beta1.HTTPRequestRedirectFilter{
-			Hostname: (*v1beta1.PreciseHostname)(helpers.GetStringPointer("foo.example.com")),
+	exactRoute := createRoute("exact", "foo.example.com", "/exact")
+	wildcardRoute := createRoute("wildcard", "*.example.com", "/wildcard")
+
+	wildcardHostname := (*v1beta1.Hostname)(helpers.GetStringPointer("*.example.com"))
+
+	listeners := map[string]*graph.Listener{
+		"listener-80": {
+			Source: v1beta1.Listener{
+				Name:     "listener-80",
+				Hostname: wildcardHostname,
+				Port:     80,
+				Protocol: v1beta1.HTTPProtocolType,
+			},
+			Valid: true,
+			Routes: map[types.NamespacedName]*graph.Route{
+				{Namespace: "test", Name: "exact"}:    exactRoute,
+				{Namespace: "test", Name: "wildcard"}: wildcardRoute,
+			},
+			AcceptedHostnames: map[string]struct{}{
+				"foo.example.com": {},
+				"*.example.com":   {},
+			},
 		},
-	}
-	redirect2 := v1beta1.HTTPRouteFilter{
-		Type: v1beta1.HTTPRouteFilterRequestRedirect,
-		RequestRedirect: &v1beta1.HTTPRequestRedirectFilter{
-			Hostname: (*v1beta1.PreciseHostname)(helpers.GetStringPointer("bar.example.com")),
+		"listener-443": {
+			Source: v1beta1.Listener{
+				Name:     "listener-443",
+				Hostname: wildcardHostname,
+				Port:     443,
+				Protocol: v1beta1.HTTPSProtocolType,
+			},
+			Valid:      true,
+			SecretPath: "secret-path",
+			Routes: map[types.NamespacedName]*graph.Route{
+				{Namespace: "test", Name: "exact"}: exactRoute,
+			},
+			AcceptedHostnames: map[string]struct{}{
+				"foo.example.com": {},
+			},
 		},
 	}
 
-	tests := []struct {
-		expected Filters
-		msg      string
-		filters  []v1beta1.HTTPRouteFilter
-	}{
+	expHTTPServers := []VirtualServer{
 		{
-			filters:  []v1beta1.HTTPRouteFilter{},
-			expected: Filters{},
-			msg:      "no filters",
+			IsDefault: true,
 		},
 		{
-			filters: []v1beta1.HTTPRouteFilter{
-				redirect1,
-			},
-			expected: Filters{
-				RequestRedirect: redirect1.RequestRedirect,
+			Hostname: "*.example.com",
+			Listener: "listener-80",
+			PathRules: []PathRule{
+				{
+					Path:       "/wildcard",
+					MatchRules: []MatchRule{createMatchRule(wildcardRoute)},
+				},
 			},
-			msg: "one filter",
 		},
 		{
-			filters: []v1beta1.HTTPRouteFilter{
-				redirect1,
-				redirect2,
+			// the wildcard route also matches the more specific hostname, so its rules are included as well.
+			Hostname: "foo.example.com",
+			Listener: "listener-80",
+			PathRules: []PathRule{
+				{
+					Path:       "/exact",
+					MatchRules: []MatchRule{createMatchRule(exactRoute)},
+				},
+				{
+					Path:       "/wildcard",
+					MatchRules: []MatchRule{createMatchRule(wildcardRoute)},
+				},
 			},
-			expected: Filters{
-				RequestRedirect: redirect1.RequestRedirect,
+		},
+	}
+
+	expSSLServers := []VirtualServer{
+		{
+			IsDefault: true,
+		},
+		{
+			// the listener has the wildcard hostname, but no routes for it
+			Hostname: "*.example.com",
+			Listener: "listener-443",
+			SSL:      &SSL{CertificatePath: "secret-path"},
+		},
+		{
+			Hostname: "foo.example.com",
+			Listener: "listener-443",
+			SSL:      &SSL{CertificatePath: "secret-path"},
+			PathRules: []PathRule{
+				{
+					Path:       "/exact",
+					MatchRules: []MatchRule{createMatchRule(exactRoute)},
+				},
 			},
-			msg: "two filters, first wins",
 		},
 	}
 
-	for _, test := range tests {
-		result := createFilters(test.filters)
-		if diff := cmp.Diff(test.expected, result); diff != "" {
-			t.Errorf("createFilters() %q mismatch (-want +got):\n%s", test.msg, diff)
-		}
+	httpServers, sslServers := buildServers(listeners)
+
+	if diff := cmp.Diff(expHTTPServers, httpServers); diff != "" {
+		t.Errorf("buildServers() http servers mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expSSLServers, sslServers); diff != "" {
+		t.Errorf("buildServers() ssl servers mismatch (-want +got):\n%s", diff)
 	}
 }
 
-func TestMatchRuleGetMatch(t *testing.T) {
+func TestBuildServersDefaultBackend(t *testing.T) {
 	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "foo",
+		},
 		Spec: v1beta1.HTTPRouteSpec{
+			Hostnames: []v1beta1.Hostname{"foo.example.com"},
 			Rules: []v1beta1.HTTPRouteRule{
 				{
 					Matches: []v1beta1.HTTPRouteMatch{
 						{
 							Path: &v1beta1.HTTPPathMatch{
-								Value: helpers.GetStringPointer("/path-1"),
-							},
-						},
-						{
-							Path: &v1beta1.HTTPPathMatch{
-								Value: helpers.GetStringPointer("/path-2"),
-							},
-						},
-					},
-				},
-				{
-					Matches: []v1beta1.HTTPRouteMatch{
-						{
-							Path: &v1beta1.HTTPPathMatch{
-								Value: helpers.GetStringPointer("/path-3"),
-							},
-						},
-						{
-							Path: &v1beta1.HTTPPathMatch{
-								Value: helpers.GetStringPointer("/path-4"),
+								Value: helpers.GetStringPointer("/path"),
 							},
 						},
 					},
@@ -996,100 +1110,1420 @@ func TestMatchRuleGetMatch(t *testing.T) {
 		},
 	}
 
-	tests := []struct {
-		name    string
-		expPath string
-		rule    MatchRule
-	}{
-		{
-			name:    "first match in first rule",
-			expPath: "/path-1",
-			rule:    MatchRule{MatchIdx: 0, RuleIdx: 0, Source: hr},
-		},
-		{
-			name:    "second match in first rule",
-			expPath: "/path-2",
-			rule:    MatchRule{MatchIdx: 1, RuleIdx: 0, Source: hr},
-		},
-		{
-			name:    "second match in second rule",
-			expPath: "/path-4",
-			rule:    MatchRule{MatchIdx: 1, RuleIdx: 1, Source: hr},
-		},
+	route := &graph.Route{
+		Source:        hr,
+		BackendGroups: []graph.BackendGroup{{Source: types.NamespacedName{Namespace: "test", Name: "foo"}}},
+		RuleFilters:   []graph.RuleFilters{{Valid: true}},
 	}
 
-	for _, tc := range tests {
-		actual := tc.rule.GetMatch()
-		if *actual.Path.Value != tc.expPath {
-			t.Errorf(
-				"MatchRule.GetMatch() returned incorrect match with path: %s, expected path: %s for test case: %q",
-				*actual.Path.Value,
-				tc.expPath,
-				tc.name,
-			)
+	createListener := func(
+		name string,
+		hostname string,
+		protocol v1beta1.ProtocolType,
+		defaultBackend *graph.BackendRef,
+		routes map[types.NamespacedName]*graph.Route,
+	) *graph.Listener {
+		l := &graph.Listener{
+			Source: v1beta1.Listener{
+				Name:     v1beta1.SectionName(name),
+				Hostname: (*v1beta1.Hostname)(helpers.GetStringPointer(hostname)),
+				Protocol: protocol,
+			},
+			Valid:             true,
+			Routes:            routes,
+			AcceptedHostnames: map[string]struct{}{},
+			DefaultBackend:    defaultBackend,
 		}
-	}
-}
 
-func TestGetListenerHostname(t *testing.T) {
-	var emptyHostname v1beta1.Hostname
-	var hostname v1beta1.Hostname = "example.com"
+		if protocol == v1beta1.HTTPSProtocolType {
+			l.SecretPath = "secret-path"
+		}
 
-	tests := []struct {
-		hostname *v1beta1.Hostname
-		expected string
-		msg      string
-	}{
-		{
-			hostname: nil,
-			expected: wildcardHostname,
-			msg:      "nil hostname",
-		},
-		{
-			hostname: &emptyHostname,
-			expected: wildcardHostname,
-			msg:      "empty hostname",
-		},
-		{
-			hostname: &hostname,
-			expected: string(hostname),
-			msg:      "normal hostname",
-		},
+		if len(routes) > 0 {
+			l.AcceptedHostnames[hostname] = struct{}{}
+		}
+
+		return l
 	}
 
-	for _, test := range tests {
-		result := getListenerHostname(test.hostname)
-		if result != test.expected {
-			t.Errorf(
-				"getListenerHostname() returned %q but expected %q for the case of %q",
-				result,
-				test.expected,
-				test.msg,
-			)
-		}
+	defaultBackend := &graph.BackendRef{Name: "test_default_8080", Valid: true, Weight: 1}
+
+	listeners := map[string]*graph.Listener{
+		"listener-80-1": createListener(
+			"listener-80-1",
+			"foo.example.com",
+			v1beta1.HTTPProtocolType,
+			defaultBackend,
+			map[types.NamespacedName]*graph.Route{{Namespace: "test", Name: "foo"}: route},
+		),
+		"listener-80-2": createListener("listener-80-2", "bar.example.com", v1beta1.HTTPProtocolType, defaultBackend, nil),
+		// no default backend, so the default server handles the requests for the hostname.
+		"listener-80-3": createListener("listener-80-3", "baz.example.com", v1beta1.HTTPProtocolType, nil, nil),
+		"listener-443-1": createListener(
+			"listener-443-1",
+			"foo.example.com",
+			v1beta1.HTTPSProtocolType,
+			&graph.BackendRef{},
+			nil,
+		),
 	}
-}
 
-func TestBuildUpstreams(t *testing.T) {
-	fooEndpoints := []resolver.Endpoint{
+	expHTTPServers := []VirtualServer{
 		{
-			Address: "10.0.0.0",
-			Port:    8080,
+			IsDefault: true,
 		},
 		{
-			Address: "10.0.0.1",
-			Port:    8080,
+			Listener:       "listener-80-2",
+			Hostname:       "bar.example.com",
+			DefaultBackend: &DefaultBackend{UpstreamName: "test_default_8080"},
 		},
 		{
-			Address: "10.0.0.2",
-			Port:    8080,
+			Listener: "listener-80-1",
+			Hostname: "foo.example.com",
+			PathRules: []PathRule{
+				{
+					Path: "/path",
+					MatchRules: []MatchRule{
+						{
+							Source:       hr,
+							BackendGroup: route.BackendGroups[0],
+						},
+					},
+				},
+			},
+			DefaultBackend: &DefaultBackend{UpstreamName: "test_default_8080"},
 		},
 	}
 
-	barEndpoints := []resolver.Endpoint{
+	expSSLServers := []VirtualServer{
 		{
-			Address: "11.0.0.0",
-			Port:    80,
+			IsDefault: true,
+		},
+		{
+			Hostname:       "foo.example.com",
+			Listener:       "listener-443-1",
+			SSL:            &SSL{CertificatePath: "secret-path"},
+			DefaultBackend: &DefaultBackend{},
+		},
+	}
+
+	httpServers, sslServers := buildServers(listeners)
+
+	if diff := cmp.Diff(expHTTPServers, httpServers); diff != "" {
+		t.Errorf("buildServers() http servers mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expSSLServers, sslServers); diff != "" {
+		t.Errorf("buildServers() ssl servers mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBuildServersRateLimit(t *testing.T) {
+	createRoute := func(name, hostname string) *graph.Route {
+		return &graph.Route{
+			Source: &v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "test",
+					Name:      name,
+				},
+				Spec: v1beta1.HTTPRouteSpec{
+					Hostnames: []v1beta1.Hostname{v1beta1.Hostname(hostname)},
+					Rules: []v1beta1.HTTPRouteRule{
+						{
+							Matches: []v1beta1.HTTPRouteMatch{
+								{
+									Path: &v1beta1.HTTPPathMatch{
+										Value: helpers.GetStringPointer("/"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			BackendGroups: []graph.BackendGroup{{Source: types.NamespacedName{Namespace: "test", Name: name}}},
+			RuleFilters:   []graph.RuleFilters{{Valid: true}},
+		}
+	}
+
+	fooRoute := createRoute("foo", "foo.example.com")
+	barRoute := createRoute("bar", "bar.example.com")
+
+	createListener := func(
+		name string,
+		hostname string,
+		protocol v1beta1.ProtocolType,
+		rateLimit *graph.RateLimit,
+		route *graph.Route,
+	) *graph.Listener {
+		l := &graph.Listener{
+			Source: v1beta1.Listener{
+				Name:     v1beta1.SectionName(name),
+				Hostname: (*v1beta1.Hostname)(helpers.GetStringPointer(hostname)),
+				Protocol: protocol,
+			},
+			Valid:             true,
+			Routes:            map[types.NamespacedName]*graph.Route{},
+			AcceptedHostnames: map[string]struct{}{},
+			RateLimit:         rateLimit,
+		}
+
+		if protocol == v1beta1.HTTPSProtocolType {
+			l.SecretPath = "secret-path"
+		}
+
+		if route != nil {
+			l.Routes[client.ObjectKeyFromObject(route.Source)] = route
+			l.AcceptedHostnames[hostname] = struct{}{}
+		}
+
+		return l
+	}
+
+	listeners := map[string]*graph.Listener{
+		"listener-80-1": createListener(
+			"listener-80-1",
+			"foo.example.com",
+			v1beta1.HTTPProtocolType,
+			&graph.RateLimit{Rate: "10r/s", Burst: 20},
+			fooRoute,
+		),
+		// no rate limit
+		"listener-80-2": createListener("listener-80-2", "bar.example.com", v1beta1.HTTPProtocolType, nil, barRoute),
+		"listener-443-1": createListener(
+			"listener-443-1",
+			"foo.example.com",
+			v1beta1.HTTPSProtocolType,
+			&graph.RateLimit{Rate: "600r/m"},
+			nil,
+		),
+	}
+
+	createPathRules := func(r *graph.Route) []PathRule {
+		return []PathRule{
+			{
+				Path: "/",
+				MatchRules: []MatchRule{
+					{
+						Source:       r.Source,
+						BackendGroup: r.BackendGroups[0],
+					},
+				},
+			},
+		}
+	}
+
+	expHTTPServers := []VirtualServer{
+		{
+			IsDefault: true,
+		},
+		{
+			Listener:  "listener-80-2",
+			Hostname:  "bar.example.com",
+			PathRules: createPathRules(barRoute),
+		},
+		{
+			Listener:  "listener-80-1",
+			Hostname:  "foo.example.com",
+			PathRules: createPathRules(fooRoute),
+			RateLimit: &RateLimit{Rate: "10r/s", Burst: 20},
+		},
+	}
+
+	expSSLServers := []VirtualServer{
+		{
+			IsDefault: true,
+		},
+		{
+			Hostname:  "foo.example.com",
+			Listener:  "listener-443-1",
+			SSL:       &SSL{CertificatePath: "secret-path"},
+			RateLimit: &RateLimit{Rate: "600r/m"},
+		},
+	}
+
+	httpServers, sslServers := buildServers(listeners)
+
+	if diff := cmp.Diff(expHTTPServers, httpServers); diff != "" {
+		t.Errorf("buildServers() http servers mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expSSLServers, sslServers); diff != "" {
+		t.Errorf("buildServers() ssl servers mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBuildServersDefaultCertificate(t *testing.T) {
+	createListener := func(name, secretPath string, defaultCertificate bool) *graph.Listener {
+		return &graph.Listener{
+			Source: v1beta1.Listener{
+				Name:     v1beta1.SectionName(name),
+				Protocol: v1beta1.HTTPSProtocolType,
+			},
+			Valid:               true,
+			SecretPath:          secretPath,
+			SecondarySecretPath: secretPath + "-ecdsa",
+			// the client certificates are not verified by the default server
+			ClientCertificatePath: "ca-path",
+			Routes:                map[types.NamespacedName]*graph.Route{},
+			AcceptedHostnames:     map[string]struct{}{},
+			DefaultCertificate:    defaultCertificate,
+		}
+	}
+
+	tests := []struct {
+		listeners        map[string]*graph.Listener
+		expDefaultServer VirtualServer
+		msg              string
+	}{
+		{
+			listeners: map[string]*graph.Listener{
+				"listener-443-1": createListener("listener-443-1", "secret-1", false),
+			},
+			expDefaultServer: VirtualServer{IsDefault: true},
+			msg:              "no default certificate",
+		},
+		{
+			listeners: map[string]*graph.Listener{
+				"listener-443-1": createListener("listener-443-1", "secret-1", false),
+				"listener-443-2": createListener("listener-443-2", "secret-2", true),
+			},
+			expDefaultServer: VirtualServer{
+				IsDefault: true,
+				SSL: &SSL{
+					CertificatePath:          "secret-2",
+					SecondaryCertificatePath: "secret-2-ecdsa",
+				},
+			},
+			msg: "default certificate",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			_, sslServers := buildServers(test.listeners)
+
+			// the default server comes first
+			if diff := cmp.Diff(test.expDefaultServer, sslServers[0]); diff != "" {
+				t.Errorf("buildServers() default ssl server mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreateDefaultBackendHTTPS(t *testing.T) {
+	httpsSvc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "secure"},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{Port: 443, AppProtocol: helpers.GetStringPointer("https")}},
+		},
+	}
+
+	tests := []struct {
+		expected *DefaultBackend
+		msg      string
+		port     int32
+	}{
+		{
+			port: 443,
+			expected: &DefaultBackend{
+				UpstreamName:  "test_secure_443",
+				Protocol:      BackendProtocolHTTPS,
+				TLSServerName: "secure.test.svc",
+			},
+			msg: "https",
+		},
+		{
+			port: 80,
+			expected: &DefaultBackend{
+				UpstreamName: "test_secure_443",
+				Protocol:     BackendProtocolHTTP1,
+			},
+			msg: "the server name is only set for https",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			l := &graph.Listener{
+				DefaultBackend: &graph.BackendRef{
+					Name:   "test_secure_443",
+					Svc:    httpsSvc,
+					Port:   test.port,
+					Valid:  true,
+					Weight: 1,
+				},
+			}
+
+			if diff := cmp.Diff(test.expected, createDefaultBackend(l)); diff != "" {
+				t.Errorf("createDefaultBackend() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBuildServersBackendProtocols(t *testing.T) {
+	grpcSvc := &v1.Service{
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{Port: 8080, AppProtocol: helpers.GetStringPointer("grpc")}},
+		},
+	}
+	h2cSvc := &v1.Service{
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{Port: 80, AppProtocol: helpers.GetStringPointer("kubernetes.io/h2c")}},
+		},
+	}
+
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "grpc",
+		},
+		Spec: v1beta1.HTTPRouteSpec{
+			Hostnames: []v1beta1.Hostname{"foo.example.com"},
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	backendGroup := graph.BackendGroup{
+		Source: types.NamespacedName{Namespace: "test", Name: "grpc"},
+		Backends: []graph.BackendRef{
+			{Name: "test_grpc_8080", Svc: grpcSvc, Port: 8080, Valid: true, Weight: 1},
+		},
+	}
+
+	listeners := map[string]*graph.Listener{
+		"listener-80-1": {
+			Source: v1beta1.Listener{
+				Name:     "listener-80-1",
+				Hostname: (*v1beta1.Hostname)(helpers.GetStringPointer("foo.example.com")),
+				Protocol: v1beta1.HTTPProtocolType,
+			},
+			Valid: true,
+			Routes: map[types.NamespacedName]*graph.Route{
+				{Namespace: "test", Name: "grpc"}: {
+					Source:        hr,
+					BackendGroups: []graph.BackendGroup{backendGroup},
+					RuleFilters:   []graph.RuleFilters{{Valid: true}},
+				},
+			},
+			AcceptedHostnames: map[string]struct{}{"foo.example.com": {}},
+			DefaultBackend:    &graph.BackendRef{Name: "test_h2c_80", Svc: h2cSvc, Port: 80, Valid: true, Weight: 1},
+		},
+	}
+
+	httpServers, _ := buildServers(listeners)
+
+	expHTTPServers := []VirtualServer{
+		{
+			IsDefault: true,
+		},
+		{
+			Hostname: "foo.example.com",
+			Listener: "listener-80-1",
+			PathRules: []PathRule{
+				{
+					Path: "/",
+					MatchRules: []MatchRule{
+						{
+							Source:          hr,
+							BackendGroup:    backendGroup,
+							BackendProtocol: BackendProtocolGRPC,
+						},
+					},
+				},
+			},
+			DefaultBackend: &DefaultBackend{
+				UpstreamName: "test_h2c_80",
+				Protocol:     BackendProtocolH2C,
+			},
+		},
+	}
+
+	if diff := cmp.Diff(expHTTPServers, httpServers); diff != "" {
+		t.Errorf("buildServers() http servers mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBuildServersScheme(t *testing.T) {
+	createRoute := func(name string, annotations map[string]string) *v1beta1.HTTPRoute {
+		return &v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "test",
+				Name:        name,
+				Annotations: annotations,
+			},
+			Spec: v1beta1.HTTPRouteSpec{
+				Hostnames: []v1beta1.Hostname{"foo.example.com"},
+				Rules: []v1beta1.HTTPRouteRule{
+					{
+						Matches: []v1beta1.HTTPRouteMatch{
+							{
+								Path: &v1beta1.HTTPPathMatch{
+									Value: helpers.GetStringPointer("/" + name),
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	hrAny := createRoute("any", nil)
+	hrHTTP := createRoute("http", map[string]string{SchemeAnnotation: "http"})
+	hrHTTPS := createRoute("https", map[string]string{SchemeAnnotation: "https"})
+
+	// only the first two rules are scoped
+	hrMixed := createRoute("mixed", map[string]string{SchemeAnnotation: "0:https,1:http"})
+	hrMixed.Spec.Rules = nil
+	for _, path := range []string{"/mixed-https", "/mixed-http", "/mixed-any"} {
+		hrMixed.Spec.Rules = append(hrMixed.Spec.Rules, v1beta1.HTTPRouteRule{
+			Matches: []v1beta1.HTTPRouteMatch{
+				{
+					Path: &v1beta1.HTTPPathMatch{
+						Value: helpers.GetStringPointer(path),
+					},
+				},
+			},
+		})
+	}
+
+	createGraphRoute := func(hr *v1beta1.HTTPRoute) *graph.Route {
+		r := &graph.Route{
+			Source: hr,
+		}
+
+		for range hr.Spec.Rules {
+			r.BackendGroups = append(r.BackendGroups, graph.BackendGroup{})
+			r.RuleFilters = append(r.RuleFilters, graph.RuleFilters{Valid: true})
+		}
+
+		return r
+	}
+
+	routes := map[types.NamespacedName]*graph.Route{
+		{Namespace: "test", Name: "any"}:   createGraphRoute(hrAny),
+		{Namespace: "test", Name: "http"}:  createGraphRoute(hrHTTP),
+		{Namespace: "test", Name: "https"}: createGraphRoute(hrHTTPS),
+		{Namespace: "test", Name: "mixed"}: createGraphRoute(hrMixed),
+	}
+
+	listeners := map[string]*graph.Listener{
+		"listener-80": {
+			Source: v1beta1.Listener{
+				Name:     "listener-80",
+				Hostname: (*v1beta1.Hostname)(helpers.GetStringPointer("foo.example.com")),
+				Protocol: v1beta1.HTTPProtocolType,
+			},
+			Valid:             true,
+			Routes:            routes,
+			AcceptedHostnames: map[string]struct{}{"foo.example.com": {}},
+		},
+		"listener-443": {
+			Source: v1beta1.Listener{
+				Name:     "listener-443",
+				Hostname: (*v1beta1.Hostname)(helpers.GetStringPointer("foo.example.com")),
+				Protocol: v1beta1.HTTPSProtocolType,
+			},
+			Valid:             true,
+			SecretPath:        "secret-path",
+			Routes:            routes,
+			AcceptedHostnames: map[string]struct{}{"foo.example.com": {}},
+		},
+	}
+
+	createPathRule := func(hr *v1beta1.HTTPRoute, opts RouteOptions) PathRule {
+		return PathRule{
+			Path: "/" + hr.Name,
+			MatchRules: []MatchRule{
+				{
+					Source:  hr,
+					Options: opts,
+				},
+			},
+		}
+	}
+
+	mixedOpts := RouteOptions{RuleSchemes: map[int]string{0: "https", 1: "http"}}
+
+	createMixedPathRule := func(path string, ruleIdx int) PathRule {
+		return PathRule{
+			Path: path,
+			MatchRules: []MatchRule{
+				{
+					RuleIdx: ruleIdx,
+					Source:  hrMixed,
+					Options: mixedOpts,
+				},
+			},
+		}
+	}
+
+	expHTTPServers := []VirtualServer{
+		{
+			IsDefault: true,
+		},
+		{
+			Hostname: "foo.example.com",
+			Listener: "listener-80",
+			PathRules: []PathRule{
+				createPathRule(hrAny, RouteOptions{}),
+				createPathRule(hrHTTP, RouteOptions{Scheme: "http"}),
+				createMixedPathRule("/mixed-any", 2),
+				createMixedPathRule("/mixed-http", 1),
+			},
+		},
+	}
+
+	expSSLServers := []VirtualServer{
+		{
+			IsDefault: true,
+		},
+		{
+			Hostname: "foo.example.com",
+			Listener: "listener-443",
+			SSL:      &SSL{CertificatePath: "secret-path"},
+			PathRules: []PathRule{
+				createPathRule(hrAny, RouteOptions{}),
+				createPathRule(hrHTTPS, RouteOptions{Scheme: "https"}),
+				createMixedPathRule("/mixed-any", 2),
+				createMixedPathRule("/mixed-https", 0),
+			},
+		},
+	}
+
+	httpServers, sslServers := buildServers(listeners)
+
+	if diff := cmp.Diff(expHTTPServers, httpServers); diff != "" {
+		t.Errorf("buildServers() http servers mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expSSLServers, sslServers); diff != "" {
+		t.Errorf("buildServers() ssl servers mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBuildServersConflictingRoutes(t *testing.T) {
+	before := metav1.Now()
+	later := metav1.NewTime(before.Add(time.Second))
+
+	createRoute := func(name string, created metav1.Time) *graph.Route {
+		return &graph.Route{
+			Source: &v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:         "test",
+					Name:              name,
+					CreationTimestamp: created,
+				},
+				Spec: v1beta1.HTTPRouteSpec{
+					Hostnames: []v1beta1.Hostname{"foo.example.com"},
+					Rules: []v1beta1.HTTPRouteRule{
+						{
+							Matches: []v1beta1.HTTPRouteMatch{
+								{
+									Path: &v1beta1.HTTPPathMatch{
+										Value: helpers.GetStringPointer("/coffee"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			BackendGroups: []graph.BackendGroup{{}},
+			RuleFilters:   []graph.RuleFilters{{Valid: true}},
+		}
+	}
+
+	// the oldest route wins, even though its name comes last in alphabetical order
+	hrOld := createRoute("hr-z", before)
+	hrNew := createRoute("hr-a", later)
+
+	createListener := func(name, secretPath string, r *graph.Route) *graph.Listener {
+		return &graph.Listener{
+			Source: v1beta1.Listener{
+				Name:     v1beta1.SectionName(name),
+				Hostname: (*v1beta1.Hostname)(helpers.GetStringPointer("foo.example.com")),
+				Protocol: v1beta1.HTTPSProtocolType,
+			},
+			Valid:      true,
+			SecretPath: secretPath,
+			Routes: map[types.NamespacedName]*graph.Route{
+				client.ObjectKeyFromObject(r.Source): r,
+			},
+			AcceptedHostnames: map[string]struct{}{"foo.example.com": {}},
+		}
+	}
+
+	listeners := map[string]*graph.Listener{
+		"listener-443-b": createListener("listener-443-b", "secret-b", hrOld),
+		"listener-443-a": createListener("listener-443-a", "secret-a", hrNew),
+	}
+
+	expSSLServers := []VirtualServer{
+		{
+			IsDefault: true,
+		},
+		{
+			Hostname: "foo.example.com",
+			Listener: "listener-443-a",
+			SSL:      &SSL{CertificatePath: "secret-a"},
+			PathRules: []PathRule{
+				{
+					Path: "/coffee",
+					MatchRules: []MatchRule{
+						{Source: hrOld.Source},
+						{Source: hrNew.Source},
+					},
+				},
+			},
+		},
+	}
+
+	// the listener of the hostname and the order of the conflicting rules don't depend on the order of map iteration
+	for i := 0; i < 10; i++ {
+		_, sslServers := buildServers(listeners)
+
+		if diff := cmp.Diff(expSSLServers, sslServers); diff != "" {
+			t.Errorf("buildServers() build %d ssl servers mismatch (-want +got):\n%s", i, diff)
+		}
+	}
+}
+
+func TestBuildServersInvalidRules(t *testing.T) {
+	regex := v1beta1.PathMatchRegularExpression
+
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "hr",
+		},
+		Spec: v1beta1.HTTPRouteSpec{
+			Hostnames: []v1beta1.Hostname{"foo.example.com"},
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Type:  &regex,
+								Value: helpers.GetStringPointer("/coffee(["),
+							},
+						},
+					},
+				},
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/tea"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	r := &graph.Route{
+		Source:        hr,
+		BackendGroups: []graph.BackendGroup{{RuleIdx: 0}, {RuleIdx: 1}},
+		RuleFilters:   []graph.RuleFilters{{Valid: true}, {Valid: true}},
+		InvalidRules:  map[int]struct{}{0: {}},
+	}
+
+	listeners := map[string]*graph.Listener{
+		"listener-80-1": {
+			Source: v1beta1.Listener{
+				Name:     "listener-80-1",
+				Protocol: v1beta1.HTTPProtocolType,
+			},
+			Valid: true,
+			Routes: map[types.NamespacedName]*graph.Route{
+				client.ObjectKeyFromObject(hr): r,
+			},
+			AcceptedHostnames: map[string]struct{}{"foo.example.com": {}},
+		},
+	}
+
+	// only the valid rule is configured
+	expHTTPServers := []VirtualServer{
+		{
+			IsDefault: true,
+		},
+		{
+			Hostname: "foo.example.com",
+			Listener: "listener-80-1",
+			PathRules: []PathRule{
+				{
+					Path: "/tea",
+					MatchRules: []MatchRule{
+						{
+							RuleIdx:      1,
+							Source:       hr,
+							BackendGroup: r.BackendGroups[1],
+						},
+					},
+				},
+			},
+		},
+	}
+
+	httpServers, _ := buildServers(listeners)
+
+	if diff := cmp.Diff(expHTTPServers, httpServers); diff != "" {
+		t.Errorf("buildServers() http servers mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBuildUpstreamsExternalName(t *testing.T) {
+	externalSvc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "external"},
+		Spec: v1.ServiceSpec{
+			Type:         v1.ServiceTypeExternalName,
+			ExternalName: "example.com",
+		},
+	}
+
+	listeners := map[string]*graph.Listener{
+		"listener-80-1": {
+			Valid: true,
+			DefaultBackend: &graph.BackendRef{
+				Name:   "test_external_443",
+				Svc:    externalSvc,
+				Port:   443,
+				Valid:  true,
+				Weight: 1,
+			},
+		},
+	}
+
+	fakeResolver := &resolverfakes.FakeServiceResolver{}
+
+	expUpstreams := map[string]Upstream{
+		"test_external_443": {
+			Name:     "test_external_443",
+			Service:  types.NamespacedName{Namespace: "test", Name: "external"},
+			Port:     443,
+			Hostname: "example.com",
+		},
+	}
+
+	upstreams := buildUpstreamsMap(context.TODO(), listeners, fakeResolver, "")
+
+	if diff := cmp.Diff(expUpstreams, upstreams); diff != "" {
+		t.Errorf("buildUpstreamsMap() mismatch (-want +got):\n%s", diff)
+	}
+
+	if fakeResolver.ResolveCallCount() != 0 {
+		t.Errorf("buildUpstreamsMap() resolved %d Services; expected 0", fakeResolver.ResolveCallCount())
+	}
+}
+
+func TestBuildUpstreamsUnixSocket(t *testing.T) {
+	sidecarSvc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "test",
+			Name:        "sidecar",
+			Annotations: map[string]string{UnixSocketAnnotation: "/var/run/app.sock"},
+		},
+	}
+
+	listeners := map[string]*graph.Listener{
+		"listener-80-1": {
+			Valid: true,
+			DefaultBackend: &graph.BackendRef{
+				Name:   "test_sidecar_80",
+				Svc:    sidecarSvc,
+				Port:   80,
+				Valid:  true,
+				Weight: 1,
+			},
+		},
+	}
+
+	fakeResolver := &resolverfakes.FakeServiceResolver{}
+
+	expUpstreams := map[string]Upstream{
+		"test_sidecar_80": {
+			Name:    "test_sidecar_80",
+			Service: types.NamespacedName{Namespace: "test", Name: "sidecar"},
+			Port:    80,
+			Options: UpstreamOptions{UnixSocket: "/var/run/app.sock"},
+		},
+	}
+
+	upstreams := buildUpstreamsMap(context.TODO(), listeners, fakeResolver, "/var/run")
+
+	if diff := cmp.Diff(expUpstreams, upstreams); diff != "" {
+		t.Errorf("buildUpstreamsMap() mismatch (-want +got):\n%s", diff)
+	}
+
+	if fakeResolver.ResolveCallCount() != 0 {
+		t.Errorf("buildUpstreamsMap() resolved %d Services; expected 0", fakeResolver.ResolveCallCount())
+	}
+
+	// Without the directory, the Unix socket backends are disabled, and the endpoints of the Service are used.
+	upstreams = buildUpstreamsMap(context.TODO(), listeners, fakeResolver, "")
+
+	if upstreams["test_sidecar_80"].Options.UnixSocket != "" {
+		t.Errorf("buildUpstreamsMap() configured the Unix socket; expected the Unix socket backends to be disabled")
+	}
+
+	if fakeResolver.ResolveCallCount() != 1 {
+		t.Errorf("buildUpstreamsMap() resolved %d Services; expected 1", fakeResolver.ResolveCallCount())
+	}
+}
+
+func TestBuildUpstreamsDefaultBackend(t *testing.T) {
+	defaultSvc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "default"}}
+
+	listeners := map[string]*graph.Listener{
+		"listener-80-1": {
+			Valid: true,
+			DefaultBackend: &graph.BackendRef{
+				Name:   "test_default_8080",
+				Svc:    defaultSvc,
+				Port:   8080,
+				Valid:  true,
+				Weight: 1,
+			},
+		},
+		"listener-80-2": {
+			Valid:          true,
+			DefaultBackend: &graph.BackendRef{},
+		},
+		"invalid-listener": {
+			Valid: false,
+			DefaultBackend: &graph.BackendRef{
+				Name:   "test_invalid_8080",
+				Svc:    &v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "invalid"}},
+				Port:   8080,
+				Valid:  true,
+				Weight: 1,
+			},
+		},
+	}
+
+	endpoints := []resolver.Endpoint{{Address: "10.0.0.0", Port: 8080}}
+
+	fakeResolver := &resolverfakes.FakeServiceResolver{}
+	fakeResolver.ResolveReturns(endpoints, nil)
+
+	expUpstreams := map[string]Upstream{
+		"test_default_8080": {
+			Name:      "test_default_8080",
+			Service:   types.NamespacedName{Namespace: "test", Name: "default"},
+			Port:      8080,
+			Endpoints: endpoints,
+		},
+	}
+
+	upstreams := buildUpstreamsMap(context.TODO(), listeners, fakeResolver, "")
+
+	if diff := cmp.Diff(expUpstreams, upstreams); diff != "" {
+		t.Errorf("buildUpstreamsMap() mismatch (-want +got):\n%s", diff)
+	}
+
+	if fakeResolver.ResolveCallCount() != 1 {
+		t.Errorf("buildUpstreamsMap() resolved %d Services; expected 1", fakeResolver.ResolveCallCount())
+	}
+}
+
+func TestBuildConfigurationSortsUpstreams(t *testing.T) {
+	createListener := func(name, svcName string) *graph.Listener {
+		return &graph.Listener{
+			Source: v1beta1.Listener{
+				Name:     v1beta1.SectionName(name),
+				Hostname: (*v1beta1.Hostname)(helpers.GetStringPointer(name + ".example.com")),
+				Port:     80,
+				Protocol: v1beta1.HTTPProtocolType,
+			},
+			Valid: true,
+			DefaultBackend: &graph.BackendRef{
+				Name: "test_" + svcName + "_80",
+				Svc: &v1.Service{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: svcName},
+				},
+				Port:   80,
+				Valid:  true,
+				Weight: 1,
+			},
+		}
+	}
+
+	g := &graph.Graph{
+		GatewayClass: &graph.GatewayClass{
+			Source: &v1beta1.GatewayClass{},
+			Valid:  true,
+		},
+		Gateway: &graph.Gateway{
+			Source: &v1beta1.Gateway{},
+			Listeners: map[string]*graph.Listener{
+				"listener-1": createListener("listener-1", "foo"),
+				"listener-2": createListener("listener-2", "bar"),
+				"listener-3": createListener("listener-3", "baz"),
+				"listener-4": createListener("listener-4", "qux"),
+			},
+		},
+		Routes: map[types.NamespacedName]*graph.Route{},
+	}
+
+	expEndpoints := []resolver.Endpoint{
+		{Address: "10.0.0.0", Port: 80},
+		{Address: "10.0.0.0", Port: 81},
+		{Address: "10.0.0.1", Port: 80},
+		{Address: "10.0.0.10", Port: 80},
+		{Address: "10.0.0.2", Port: 80},
+		{Address: "fd00::1", Port: 80},
+	}
+
+	createUpstream := func(svcName string) Upstream {
+		return Upstream{
+			Name:      "test_" + svcName + "_80",
+			Service:   types.NamespacedName{Namespace: "test", Name: svcName},
+			Port:      80,
+			Endpoints: expEndpoints,
+		}
+	}
+
+	expUpstreams := []Upstream{
+		createUpstream("bar"),
+		createUpstream("baz"),
+		createUpstream("foo"),
+		createUpstream("qux"),
+	}
+
+	random := rand.New(rand.NewSource(1)) //nolint:gosec // the shuffling doesn't need a secure random source
+
+	fakeResolver := &resolverfakes.FakeServiceResolver{}
+	fakeResolver.ResolveStub = func(context.Context, *v1.Service, int32) ([]resolver.Endpoint, error) {
+		shuffled := make([]resolver.Endpoint, len(expEndpoints))
+		copy(shuffled, expEndpoints)
+
+		random.Shuffle(len(shuffled), func(i, j int) {
+			shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+		})
+
+		return shuffled, nil
+	}
+
+	for i := 0; i < 10; i++ {
+		conf, _ := BuildConfiguration(context.TODO(), g, fakeResolver, "")
+
+		if diff := cmp.Diff(expUpstreams, conf.Upstreams); diff != "" {
+			t.Errorf("BuildConfiguration() build %d upstreams mismatch (-want +got):\n%s", i, diff)
+		}
+	}
+}
+
+func TestGetPath(t *testing.T) {
+	tests := []struct {
+		path     *v1beta1.HTTPPathMatch
+		expected string
+		msg      string
+	}{
+		{
+			path:     &v1beta1.HTTPPathMatch{Value: helpers.GetStringPointer("/abc")},
+			expected: "/abc",
+			msg:      "normal case",
+		},
+		{
+			path:     nil,
+			expected: "/",
+			msg:      "nil path",
+		},
+		{
+			path:     &v1beta1.HTTPPathMatch{Value: nil},
+			expected: "/",
+			msg:      "nil value",
+		},
+		{
+			path:     &v1beta1.HTTPPathMatch{Value: helpers.GetStringPointer("")},
+			expected: "/",
+			msg:      "empty value",
+		},
+		{
+			path:     &v1beta1.HTTPPathMatch{Value: helpers.GetStringPointer("/abc/")},
+			expected: "/abc",
+			msg:      "trailing slash",
+		},
+		{
+			path:     &v1beta1.HTTPPathMatch{Value: helpers.GetStringPointer("/")},
+			expected: "/",
+			msg:      "root path",
+		},
+	}
+
+	for _, test := range tests {
+		result := getPath(test.path)
+		if result != test.expected {
+			t.Errorf("getPath() returned %q but expected %q for the case of %q", result, test.expected, test.msg)
+		}
+	}
+}
+
+func TestCreateFilters(t *testing.T) {
+	redirect1 := v1beta1.HTTPRouteFilter{
+		Type: v1beta1.HTTPRouteFilterRequestRedirect,
+		RequestRedirect: &v1beta1.HTTPRequestRedirectFilter{
+			Hostname: (*v1beta1.PreciseHostname)(helpers.GetStringPointer("foo.example.com")),
+		},
+	}
+	redirect2 := v1beta1.HTTPRouteFilter{
+		Type: v1beta1.HTTPRouteFilterRequestRedirect,
+		RequestRedirect: &v1beta1.HTTPRequestRedirectFilter{
+			Hostname: (*v1beta1.PreciseHostname)(helpers.GetStringPointer("bar.example.com")),
+		},
+	}
+
+	corsPolicy := &v1alpha1.CORSPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "cors"},
+		Spec: v1alpha1.CORSPolicySpec{
+			AllowOrigins: []string{"https://example.com"},
+		},
+	}
+
+	corsRef := v1beta1.HTTPRouteFilter{
+		Type: v1beta1.HTTPRouteFilterExtensionRef,
+		ExtensionRef: &v1beta1.LocalObjectReference{
+			Group: v1alpha1.GroupName,
+			Kind:  v1alpha1.CORSPolicyKind,
+			Name:  "cors",
+		},
+	}
+
+	directResponse := &v1alpha1.DirectResponse{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "response"},
+		Spec: v1alpha1.DirectResponseSpec{
+			StatusCode: 200,
+		},
+	}
+
+	directResponseRef := v1beta1.HTTPRouteFilter{
+		Type: v1beta1.HTTPRouteFilterExtensionRef,
+		ExtensionRef: &v1beta1.LocalObjectReference{
+			Group: v1alpha1.GroupName,
+			Kind:  v1alpha1.DirectResponseKind,
+			Name:  "response",
+		},
+	}
+
+	validRuleFilters := graph.RuleFilters{Valid: true}
+	directResponseRuleFilters := graph.RuleFilters{
+		CORSPolicy:     corsPolicy,
+		DirectResponse: directResponse,
+		Valid:          true,
+	}
+	corsRuleFilters := graph.RuleFilters{
+		CORSPolicy: corsPolicy,
+		Valid:      true,
+	}
+
+	tests := []struct {
+		expected    Filters
+		ruleFilters graph.RuleFilters
+		msg         string
+		filters     []v1beta1.HTTPRouteFilter
+	}{
+		{
+			filters:     []v1beta1.HTTPRouteFilter{},
+			ruleFilters: validRuleFilters,
+			expected:    Filters{},
+			msg:         "no filters",
+		},
+		{
+			filters: []v1beta1.HTTPRouteFilter{
+				corsRef,
+				redirect1,
+			},
+			ruleFilters: corsRuleFilters,
+			expected: Filters{
+				RequestRedirect: redirect1.RequestRedirect,
+				CORSPolicy:      corsPolicy,
+			},
+			msg: "CORS policy before redirect",
+		},
+		{
+			filters: []v1beta1.HTTPRouteFilter{
+				redirect1,
+				corsRef,
+			},
+			ruleFilters: corsRuleFilters,
+			expected: Filters{
+				RequestRedirect: redirect1.RequestRedirect,
+			},
+			msg: "CORS policy after redirect doesn't apply",
+		},
+		{
+			filters: []v1beta1.HTTPRouteFilter{
+				corsRef,
+			},
+			ruleFilters: corsRuleFilters,
+			expected: Filters{
+				CORSPolicy: corsPolicy,
+			},
+			msg: "CORS policy",
+		},
+		{
+			filters: []v1beta1.HTTPRouteFilter{
+				corsRef,
+				directResponseRef,
+				redirect1,
+			},
+			ruleFilters: directResponseRuleFilters,
+			expected: Filters{
+				CORSPolicy:     corsPolicy,
+				DirectResponse: directResponse,
+			},
+			msg: "CORS policy before direct response, redirect after it doesn't apply",
+		},
+		{
+			filters: []v1beta1.HTTPRouteFilter{
+				directResponseRef,
+				corsRef,
+			},
+			ruleFilters: directResponseRuleFilters,
+			expected: Filters{
+				DirectResponse: directResponse,
+			},
+			msg: "CORS policy after direct response doesn't apply",
+		},
+		{
+			filters: []v1beta1.HTTPRouteFilter{
+				redirect1,
+				directResponseRef,
+			},
+			ruleFilters: directResponseRuleFilters,
+			expected: Filters{
+				RequestRedirect: redirect1.RequestRedirect,
+			},
+			msg: "direct response after redirect doesn't apply",
+		},
+		{
+			filters:     []v1beta1.HTTPRouteFilter{},
+			ruleFilters: graph.RuleFilters{Valid: false},
+			expected: Filters{
+				InvalidExtensionRef: true,
+			},
+			msg: "unresolved extension ref",
+		},
+		{
+			filters: []v1beta1.HTTPRouteFilter{
+				redirect1,
+			},
+			ruleFilters: validRuleFilters,
+			expected: Filters{
+				RequestRedirect: redirect1.RequestRedirect,
+			},
+			msg: "one filter",
+		},
+		{
+			filters: []v1beta1.HTTPRouteFilter{
+				redirect1,
+				redirect2,
+			},
+			ruleFilters: validRuleFilters,
+			expected: Filters{
+				RequestRedirect: redirect1.RequestRedirect,
+			},
+			msg: "two filters, first wins",
+		},
+	}
+
+	for _, test := range tests {
+		result := createFilters(test.filters, test.ruleFilters)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("createFilters() %q mismatch (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestMatchRuleGetMatch(t *testing.T) {
+	hr := &v1beta1.HTTPRoute{
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/path-1"),
+							},
+						},
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/path-2"),
+							},
+						},
+					},
+				},
+				{
+					Matches: []v1beta1.HTTPRouteMatch{
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/path-3"),
+							},
+						},
+						{
+							Path: &v1beta1.HTTPPathMatch{
+								Value: helpers.GetStringPointer("/path-4"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		expPath string
+		rule    MatchRule
+	}{
+		{
+			name:    "first match in first rule",
+			expPath: "/path-1",
+			rule:    MatchRule{MatchIdx: 0, RuleIdx: 0, Source: hr},
+		},
+		{
+			name:    "second match in first rule",
+			expPath: "/path-2",
+			rule:    MatchRule{MatchIdx: 1, RuleIdx: 0, Source: hr},
+		},
+		{
+			name:    "second match in second rule",
+			expPath: "/path-4",
+			rule:    MatchRule{MatchIdx: 1, RuleIdx: 1, Source: hr},
+		},
+	}
+
+	for _, tc := range tests {
+		actual := tc.rule.GetMatch()
+		if *actual.Path.Value != tc.expPath {
+			t.Errorf(
+				"MatchRule.GetMatch() returned incorrect match with path: %s, expected path: %s for test case: %q",
+				*actual.Path.Value,
+				tc.expPath,
+				tc.name,
+			)
+		}
+	}
+}
+
+func TestIsMatchedByWildcardHostname(t *testing.T) {
+	hostnames := []v1beta1.Hostname{"foo.example.com", "*.example.com"}
+
+	tests := []struct {
+		hostname string
+		msg      string
+		expected bool
+	}{
+		{
+			hostname: "bar.example.com",
+			expected: true,
+			msg:      "one additional label",
+		},
+		{
+			hostname: "a.example.com",
+			expected: true,
+			msg:      "one-letter label",
+		},
+		{
+			hostname: "a.b.example.com",
+			expected: true,
+			msg:      "multiple additional labels",
+		},
+		{
+			hostname: "example.com",
+			expected: false,
+			msg:      "hostname without the wildcard",
+		},
+		{
+			hostname: "foo.com",
+			expected: false,
+			msg:      "other domain",
+		},
+	}
+
+	for _, test := range tests {
+		result := isMatchedByWildcardHostname(test.hostname, hostnames)
+		if result != test.expected {
+			t.Errorf(
+				"isMatchedByWildcardHostname() returned %v but expected %v for the case of %q",
+				result,
+				test.expected,
+				test.msg,
+			)
+		}
+	}
+}
+
+func TestGetListenerHostname(t *testing.T) {
+	var emptyHostname v1beta1.Hostname
+	var hostname v1beta1.Hostname = "example.com"
+
+	tests := []struct {
+		hostname *v1beta1.Hostname
+		expected string
+		msg      string
+	}{
+		{
+			hostname: nil,
+			expected: wildcardHostname,
+			msg:      "nil hostname",
+		},
+		{
+			hostname: &emptyHostname,
+			expected: wildcardHostname,
+			msg:      "empty hostname",
+		},
+		{
+			hostname: &hostname,
+			expected: string(hostname),
+			msg:      "normal hostname",
+		},
+	}
+
+	for _, test := range tests {
+		result := getListenerHostname(test.hostname)
+		if result != test.expected {
+			t.Errorf(
+				"getListenerHostname() returned %q but expected %q for the case of %q",
+				result,
+				test.expected,
+				test.msg,
+			)
+		}
+	}
+}
+
+func TestBuildUpstreams(t *testing.T) {
+	fooEndpoints := []resolver.Endpoint{
+		{
+			Address: "10.0.0.0",
+			Port:    8080,
+		},
+		{
+			Address: "10.0.0.1",
+			Port:    8080,
+		},
+		{
+			Address: "10.0.0.2",
+			Port:    8080,
+		},
+	}
+
+	barEndpoints := []resolver.Endpoint{
+		{
+			Address: "11.0.0.0",
+			Port:    80,
 		},
 		{
 			Address: "11.0.0.1",
@@ -1193,27 +2627,33 @@ func TestBuildUpstreams(t *testing.T) {
 	expUpstreams := map[string]Upstream{
 		"bar": {
 			Name:      "bar",
+			Service:   types.NamespacedName{Namespace: "test", Name: "bar"},
 			Endpoints: barEndpoints,
 		},
 		"baz": {
 			Name:      "baz",
+			Service:   types.NamespacedName{Namespace: "test", Name: "baz"},
 			Endpoints: bazEndpoints,
 		},
 		"baz2": {
 			Name:      "baz2",
+			Service:   types.NamespacedName{Namespace: "test", Name: "baz2"},
 			Endpoints: baz2Endpoints,
 		},
 		"empty-endpoints": {
 			Name:      "empty-endpoints",
+			Service:   types.NamespacedName{Namespace: "test", Name: "empty-endpoints"},
 			Endpoints: []resolver.Endpoint{},
 			ErrorMsg:  emptyEndpointsErrMsg,
 		},
 		"foo": {
 			Name:      "foo",
+			Service:   types.NamespacedName{Namespace: "test", Name: "foo"},
 			Endpoints: fooEndpoints,
 		},
 		"nil-endpoints": {
 			Name:      "nil-endpoints",
+			Service:   types.NamespacedName{Namespace: "test", Name: "nil-endpoints"},
 			Endpoints: nil,
 			ErrorMsg:  nilEndpointsErrMsg,
 		},
@@ -1238,7 +2678,7 @@ func TestBuildUpstreams(t *testing.T) {
 		}
 	})
 
-	upstreams := buildUpstreamsMap(context.TODO(), listeners, fakeResolver)
+	upstreams := buildUpstreamsMap(context.TODO(), listeners, fakeResolver, "")
 
 	if diff := cmp.Diff(expUpstreams, upstreams); diff != "" {
 		t.Errorf("buildUpstreamsMap() mismatch (-want +got):\n%s", diff)
@@ -1313,6 +2753,25 @@ func TestBuildBackendGroups(t *testing.T) {
 		},
 	}
 
+	weighted := graph.BackendGroup{
+		Source:   types.NamespacedName{Namespace: "test", Name: "hr1"},
+		Name:     "weighted__test__hr1_rule0__test__hr2_rule0",
+		Backends: []graph.BackendRef{{Name: "foo"}, {Name: "bar"}},
+	}
+
+	servers := []VirtualServer{
+		{
+			PathRules: []PathRule{
+				{
+					MatchRules: []MatchRule{
+						{BackendGroup: hr1Rule0}, // groups of the graph are already included.
+						{BackendGroup: weighted},
+					},
+				},
+			},
+		},
+	}
+
 	expGroups := []graph.BackendGroup{
 		hr1Rule0,
 		hr1Rule1,
@@ -1320,16 +2779,16 @@ func TestBuildBackendGroups(t *testing.T) {
 		hr2Rule1,
 		hr3Rule0,
 		hr3Rule1,
+		weighted,
 	}
 
-	result := buildBackendGroups(listeners)
-
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].GroupName() < result[j].GroupName()
-	})
+	// The groups come from maps, so build them repeatedly to ensure the order doesn't depend on the iteration order.
+	for i := 0; i < 10; i++ {
+		result := buildBackendGroups(listeners, servers)
 
-	if diff := helpers.Diff(expGroups, result); diff != "" {
-		t.Errorf("buildBackendGroups() mismatch: %+v", diff)
+		if diff := helpers.Diff(expGroups, result); diff != "" {
+			t.Fatalf("buildBackendGroups() mismatch: %+v", diff)
+		}
 	}
 }
 
@@ -1384,6 +2843,20 @@ func TestBuildWarnings(t *testing.T) {
 		createBackendRefs("dne"),
 	)
 
+	grpcSvc := &v1.Service{
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{Port: 80, AppProtocol: helpers.GetStringPointer("grpc")}},
+		},
+	}
+
+	hr4BackendGroup0 := createBackendGroup(
+		"hr4",
+		[]graph.BackendRef{
+			{Name: "foo", Svc: grpcSvc, Port: 80, Valid: true, Weight: 1},
+			{Name: "bar", Svc: &v1.Service{}, Port: 80, Valid: true, Weight: 1},
+		},
+	)
+
 	hrInvalidGroup := createBackendGroup(
 		"hr-invalid",
 		createBackendRefs("invalid"),
@@ -1392,7 +2865,14 @@ func TestBuildWarnings(t *testing.T) {
 
 	hr1 := &v1beta1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Name: "hr1", Namespace: "test"}}
 	hr2 := &v1beta1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Name: "hr2", Namespace: "test"}}
-	hr3 := &v1beta1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Name: "hr3", Namespace: "test"}}
+	hr3 := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "hr3",
+			Namespace:   "test",
+			Annotations: map[string]string{StreamingAnnotation: "yes"},
+		},
+	}
+	hr4 := &v1beta1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Name: "hr4", Namespace: "test"}}
 	hrInvalid := &v1beta1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Name: "hr-invalid", Namespace: "test"}}
 
 	invalidRoutes := map[types.NamespacedName]*graph.Route{
@@ -1418,6 +2898,10 @@ func TestBuildWarnings(t *testing.T) {
 			Source:        hr3,
 			BackendGroups: []graph.BackendGroup{hr3BackendGroup0, hr3BackendGroup1},
 		},
+		{Name: "hr4", Namespace: "test"}: {
+			Source:        hr4,
+			BackendGroups: []graph.BackendGroup{hr4BackendGroup0},
+		},
 	}
 
 	upstreamMap := map[string]Upstream{
@@ -1426,8 +2910,21 @@ func TestBuildWarnings(t *testing.T) {
 		"resolve-error": {ErrorMsg: "resolve error"},
 	}
 
+	gw := &v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gateway",
+			Namespace: "test",
+			Annotations: map[string]string{
+				MaintenanceAnnotation:              "true",
+				MaintenancePageAnnotation:          "$maintenance",
+				LargeClientHeaderBuffersAnnotation: "4",
+			},
+		},
+	}
+
 	graph := &graph.Graph{
 		Gateway: &graph.Gateway{
+			Source: gw,
 			Listeners: map[string]*graph.Listener{
 				"invalid-listener": {
 					Source: v1beta1.Listener{
@@ -1446,6 +2943,12 @@ func TestBuildWarnings(t *testing.T) {
 				"listener2": {
 					Source: v1beta1.Listener{
 						Name: "valid2",
+						TLS: &v1beta1.GatewayTLSConfig{
+							Options: map[v1beta1.AnnotationKey]v1beta1.AnnotationValue{
+								SSLProtocolsTLSOption: "TLSv1.3",
+								"example.com/unknown": "value",
+							},
+						},
 					},
 					Valid:  true,
 					Routes: routes2,
@@ -1465,18 +2968,60 @@ func TestBuildWarnings(t *testing.T) {
 			"cannot resolve backend ref: resolve error",
 		},
 		hr3: []string{
+			`invalid value "yes" of the annotation k8s-gateway.nginx.org/streaming; must be a boolean`,
 			"invalid backend ref: error3",
 			"cannot resolve backend ref; internal error: upstream dne not found in map",
 		},
+		hr4: []string{
+			"rule 0: backend refs use different protocols; NGINX proxies requests to them over HTTP/1.1",
+		},
 		hrInvalid: []string{"cannot configure routes for listener invalid; listener is invalid"},
+		gw: []string{
+			"invalid value of the annotation k8s-gateway.nginx.org/maintenance-page; must be up to 4096 characters " +
+				"without $",
+			`invalid value "4" of the annotation k8s-gateway.nginx.org/large-client-header-buffers; must be ` +
+				"a number from 1 to 1024 and a size of at least 1k in bytes, kilobytes or megabytes, separated by " +
+				"a space, for example 4 16k",
+			"listener valid2: unknown TLS option example.com/unknown is ignored",
+		},
 	}
 
-	warns := buildWarnings(graph, upstreamMap)
+	warns := buildWarnings(graph, upstreamMap, "")
 	if diff := cmp.Diff(expWarns, warns); diff != "" {
 		t.Errorf("buildWarnings() mismatch (-want +got):\n%s", diff)
 	}
 }
 
+func TestBuildAddresses(t *testing.T) {
+	tests := []struct {
+		msg         string
+		gwAddresses []v1beta1.GatewayAddress
+		expected    []string
+	}{
+		{
+			gwAddresses: nil,
+			expected:    nil,
+			msg:         "no addresses",
+		},
+		{
+			gwAddresses: []v1beta1.GatewayAddress{
+				{Value: "10.0.0.1"},
+				{Value: "2001:db8::1"},
+				{Value: "10.0.0.1"}, // duplicate
+			},
+			expected: []string{"10.0.0.1", "2001:db8::1"},
+			msg:      "multiple addresses",
+		},
+	}
+
+	for _, test := range tests {
+		result := buildAddresses(test.gwAddresses)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("buildAddresses() %q mismatch (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
 func TestUpstreamsMapToSlice(t *testing.T) {
 	fooUpstream := Upstream{
 		Name: "foo",
@@ -1513,10 +3058,6 @@ func TestUpstreamsMapToSlice(t *testing.T) {
 
 	upstreams := upstreamsMapToSlice(upstreamMap)
 
-	sort.Slice(upstreams, func(i, j int) bool {
-		return upstreams[i].Name < upstreams[j].Name
-	})
-
 	if diff := cmp.Diff(expUpstreams, upstreams); diff != "" {
 		t.Errorf("upstreamMapToSlice() mismatch (-want +got):\n%s", diff)
 	}
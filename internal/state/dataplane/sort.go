@@ -35,8 +35,8 @@ continuing on ties:
 If ties still exist within the Route that has been given precedence,
 matching precedence MUST be granted to the first matching rule meeting the above criteria.
 
-higherPriority will determine precedence by comparing len(headers), len(query parameters), creation timestamp,
-and namespace name. The other criteria are handled by NGINX.
+higherPriority will determine precedence by comparing len(headers), len(query parameters), the GeoMatch option,
+creation timestamp, and namespace name. The other criteria are handled by NGINX.
 */
 func higherPriority(rule1, rule2 MatchRule) bool {
 	// Get the matches from the rules
@@ -60,6 +60,15 @@ func higherPriority(rule1, rule2 MatchRule) bool {
 		return l1 > l2
 	}
 
+	// If still tied, the match scoped to the clients from some countries or continents wins, so that the same match
+	// for all clients doesn't shadow it.
+	geo1 := rule1.Options.GeoMatch != nil
+	geo2 := rule2.Options.GeoMatch != nil
+
+	if geo1 != geo2 {
+		return geo1
+	}
+
 	// If still tied, compare the object meta of the two routes.
 	return nkgsort.LessObjectMeta(&rule1.Source.ObjectMeta, &rule2.Source.ObjectMeta)
 }
@@ -9,6 +9,7 @@ import (
 	"sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
 )
 
 func TestSort(t *testing.T) {
@@ -132,6 +133,28 @@ func TestSort(t *testing.T) {
 		},
 	}
 
+	hr4 := v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "hr4",
+			Namespace:         "test",
+			CreationTimestamp: later,
+		},
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					Matches: []v1beta1.HTTPRouteMatch{pathOnlyMatch}, // tie decided on geo match
+				},
+			},
+		},
+	}
+
+	geoMatchOpts := RouteOptions{
+		GeoMatch: &graph.GeoMatch{
+			Type:  graph.GeoMatchTypeContinent,
+			Codes: []string{"EU"},
+		},
+	}
+
 	routes := []MatchRule{
 		{
 			MatchIdx: 0, // pathOnlyMatch
@@ -163,6 +186,12 @@ func TestSort(t *testing.T) {
 			RuleIdx:  0,
 			Source:   &hr3,
 		},
+		{
+			MatchIdx: 0, // pathOnlyMatch / geo match
+			RuleIdx:  0,
+			Source:   &hr4,
+			Options:  geoMatchOpts,
+		},
 	}
 
 	sortedRoutes := []MatchRule{
@@ -191,6 +220,12 @@ func TestSort(t *testing.T) {
 			RuleIdx:  0,
 			Source:   &hr2,
 		},
+		{
+			MatchIdx: 0, // pathOnlyMatch / geo match
+			RuleIdx:  0,
+			Source:   &hr4,
+			Options:  geoMatchOpts,
+		},
 		{
 			MatchIdx: 0, // pathOnlyMatch
 			RuleIdx:  0,
@@ -0,0 +1,158 @@
+package dataplane
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
+)
+
+const (
+	// SSLProtocolsTLSOption is the key of the listener TLS option that configures the enabled TLS protocols.
+	// The value is a space-separated list of the protocols TLSv1, TLSv1.1, TLSv1.2 and TLSv1.3.
+	SSLProtocolsTLSOption = "k8s-gateway.nginx.org/ssl-protocols"
+	// SSLCiphersTLSOption is the key of the listener TLS option that configures the enabled ciphers.
+	// The value is a list of ciphers in the OpenSSL format.
+	SSLCiphersTLSOption = "k8s-gateway.nginx.org/ssl-ciphers"
+	// SSLVerifyClientTLSOption is the key of the listener TLS option that configures the verification mode of
+	// the client certificates: require (the default) or optional. It requires the graph.ClientCertificateTLSOption.
+	SSLVerifyClientTLSOption = "k8s-gateway.nginx.org/ssl-verify-client"
+	// SSLVerifyClientErrorStatusTLSOption is the key of the listener TLS option that configures the status code
+	// of the response when the verification of a client certificate fails. Must be in the range 400-599.
+	// It requires the graph.ClientCertificateTLSOption.
+	SSLVerifyClientErrorStatusTLSOption = "k8s-gateway.nginx.org/ssl-verify-client-error-status"
+)
+
+const (
+	// VerifyClientOn is the NGINX verification mode that requires a valid client certificate.
+	VerifyClientOn = "on"
+	// VerifyClientOptional is the NGINX verification mode that verifies the client certificate if the client
+	// sends one.
+	VerifyClientOptional = "optional"
+
+	defaultVerifyClientErrorStatus = 400
+)
+
+var (
+	verifyClientModes = map[string]string{
+		"require":  VerifyClientOn,
+		"optional": VerifyClientOptional,
+	}
+
+	supportedSSLProtocols = map[string]struct{}{
+		"TLSv1":   {},
+		"TLSv1.1": {},
+		"TLSv1.2": {},
+		"TLSv1.3": {},
+	}
+
+	sslCiphersRegexp = regexp.MustCompile(`^[A-Za-z0-9!+@:=_-]+$`)
+)
+
+// TLSOptions holds the TLS options of an SSL server, which are configured through the options of the TLS config
+// of the listener.
+type TLSOptions struct {
+	// Protocols is a space-separated list of the enabled TLS protocols. Empty means the NGINX default.
+	Protocols string
+	// Ciphers is a list of the enabled ciphers in the OpenSSL format. Empty means the NGINX default.
+	Ciphers string
+	// VerifyClient is the NGINX verification mode of the client certificates: VerifyClientOn or
+	// VerifyClientOptional. Empty means the client certificates are not verified.
+	VerifyClient string
+	// VerifyClientErrorStatus is the status code of the response when the verification of a client certificate
+	// fails. Only set together with VerifyClient.
+	VerifyClientErrorStatus int
+}
+
+// createTLSOptions creates TLSOptions from the options of the TLS config of a listener.
+// Unknown options and options with invalid values are ignored and reported in the returned messages.
+func createTLSOptions(options map[v1beta1.AnnotationKey]v1beta1.AnnotationValue) (TLSOptions, []string) {
+	var (
+		opts TLSOptions
+		msgs []string
+
+		verifyClient            = VerifyClientOn
+		verifyClientErrorStatus = defaultVerifyClientErrorStatus
+	)
+
+	// sort the keys so that the messages are reported in the same order
+	keys := make([]string, 0, len(options))
+	for k := range options {
+		keys = append(keys, string(k))
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := string(options[v1beta1.AnnotationKey(k)])
+
+		switch k {
+		case SSLProtocolsTLSOption:
+			if err := validateSSLProtocols(v); err != nil {
+				msgs = append(msgs, fmt.Sprintf("invalid value %q of the TLS option %s: %v", v, k, err))
+				continue
+			}
+			opts.Protocols = v
+		case SSLCiphersTLSOption:
+			if !sslCiphersRegexp.MatchString(v) {
+				msgs = append(msgs, fmt.Sprintf("invalid value %q of the TLS option %s; must be a list of ciphers "+
+					"in the OpenSSL format", v, k))
+				continue
+			}
+			opts.Ciphers = v
+		case graph.ClientCertificateTLSOption:
+			// The Secret is loaded by the graph, which invalidates the listener if the Secret is invalid.
+		case SSLVerifyClientTLSOption:
+			mode, ok := verifyClientModes[v]
+			if !ok {
+				msgs = append(msgs, fmt.Sprintf("invalid value %q of the TLS option %s; must be require or optional",
+					v, k))
+				continue
+			}
+			verifyClient = mode
+		case SSLVerifyClientErrorStatusTLSOption:
+			status, err := strconv.Atoi(v)
+			if err != nil || status < 400 || status > 599 {
+				msgs = append(msgs, fmt.Sprintf("invalid value %q of the TLS option %s; must be a status code "+
+					"in the range 400-599", v, k))
+				continue
+			}
+			verifyClientErrorStatus = status
+		default:
+			msgs = append(msgs, fmt.Sprintf("unknown TLS option %s is ignored", k))
+		}
+	}
+
+	if _, exists := options[graph.ClientCertificateTLSOption]; exists {
+		opts.VerifyClient = verifyClient
+		opts.VerifyClientErrorStatus = verifyClientErrorStatus
+	} else {
+		for _, k := range []string{SSLVerifyClientTLSOption, SSLVerifyClientErrorStatusTLSOption} {
+			if _, exists := options[v1beta1.AnnotationKey(k)]; exists {
+				msgs = append(msgs, fmt.Sprintf("TLS option %s is ignored because the TLS option %s is not set",
+					k, graph.ClientCertificateTLSOption))
+			}
+		}
+	}
+
+	return opts, msgs
+}
+
+func validateSSLProtocols(protocols string) error {
+	fields := strings.Fields(protocols)
+	if len(fields) == 0 {
+		return fmt.Errorf("must include at least one protocol")
+	}
+
+	for _, p := range fields {
+		if _, ok := supportedSSLProtocols[p]; !ok {
+			return fmt.Errorf("unsupported protocol %s; must be one of TLSv1, TLSv1.1, TLSv1.2, TLSv1.3", p)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package dataplane
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
+)
+
+func TestCreateTLSOptions(t *testing.T) {
+	tests := []struct {
+		options map[v1beta1.AnnotationKey]v1beta1.AnnotationValue
+		msg     string
+		expOpts TLSOptions
+		expMsgs int
+	}{
+		{
+			options: nil,
+			expOpts: TLSOptions{},
+			msg:     "no options",
+		},
+		{
+			options: map[v1beta1.AnnotationKey]v1beta1.AnnotationValue{
+				SSLProtocolsTLSOption: "TLSv1.2 TLSv1.3",
+				SSLCiphersTLSOption:   "HIGH:!aNULL:!MD5",
+			},
+			expOpts: TLSOptions{
+				Protocols: "TLSv1.2 TLSv1.3",
+				Ciphers:   "HIGH:!aNULL:!MD5",
+			},
+			msg: "recognized options",
+		},
+		{
+			options: map[v1beta1.AnnotationKey]v1beta1.AnnotationValue{
+				SSLProtocolsTLSOption:            "TLSv1.3",
+				"example.com/unknown-tls-option": "value",
+			},
+			expOpts: TLSOptions{Protocols: "TLSv1.3"},
+			expMsgs: 1,
+			msg:     "unrecognized option",
+		},
+		{
+			options: map[v1beta1.AnnotationKey]v1beta1.AnnotationValue{
+				SSLProtocolsTLSOption: "SSLv3 TLSv1.2",
+			},
+			expOpts: TLSOptions{},
+			expMsgs: 1,
+			msg:     "invalid protocols",
+		},
+		{
+			options: map[v1beta1.AnnotationKey]v1beta1.AnnotationValue{
+				SSLProtocolsTLSOption: " ",
+			},
+			expOpts: TLSOptions{},
+			expMsgs: 1,
+			msg:     "empty protocols",
+		},
+		{
+			options: map[v1beta1.AnnotationKey]v1beta1.AnnotationValue{
+				SSLCiphersTLSOption: "HIGH; return 200",
+			},
+			expOpts: TLSOptions{},
+			expMsgs: 1,
+			msg:     "invalid ciphers",
+		},
+		{
+			options: map[v1beta1.AnnotationKey]v1beta1.AnnotationValue{
+				graph.ClientCertificateTLSOption: "ca",
+			},
+			expOpts: TLSOptions{
+				VerifyClient:            VerifyClientOn,
+				VerifyClientErrorStatus: 400,
+			},
+			msg: "client certificate with the default verify mode",
+		},
+		{
+			options: map[v1beta1.AnnotationKey]v1beta1.AnnotationValue{
+				graph.ClientCertificateTLSOption:    "ca",
+				SSLVerifyClientTLSOption:            "require",
+				SSLVerifyClientErrorStatusTLSOption: "403",
+			},
+			expOpts: TLSOptions{
+				VerifyClient:            VerifyClientOn,
+				VerifyClientErrorStatus: 403,
+			},
+			msg: "client certificate with the require verify mode",
+		},
+		{
+			options: map[v1beta1.AnnotationKey]v1beta1.AnnotationValue{
+				graph.ClientCertificateTLSOption:    "ca",
+				SSLVerifyClientTLSOption:            "optional",
+				SSLVerifyClientErrorStatusTLSOption: "495",
+			},
+			expOpts: TLSOptions{
+				VerifyClient:            VerifyClientOptional,
+				VerifyClientErrorStatus: 495,
+			},
+			msg: "client certificate with the optional verify mode",
+		},
+		{
+			options: map[v1beta1.AnnotationKey]v1beta1.AnnotationValue{
+				graph.ClientCertificateTLSOption:    "ca",
+				SSLVerifyClientTLSOption:            "optional_no_ca",
+				SSLVerifyClientErrorStatusTLSOption: "200",
+			},
+			expOpts: TLSOptions{
+				VerifyClient:            VerifyClientOn,
+				VerifyClientErrorStatus: 400,
+			},
+			expMsgs: 2,
+			msg:     "client certificate with invalid verify mode and error status",
+		},
+		{
+			options: map[v1beta1.AnnotationKey]v1beta1.AnnotationValue{
+				SSLVerifyClientTLSOption:            "require",
+				SSLVerifyClientErrorStatusTLSOption: "403",
+			},
+			expOpts: TLSOptions{},
+			expMsgs: 2,
+			msg:     "verify options without client certificate",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			opts, msgs := createTLSOptions(test.options)
+			g.Expect(opts).To(Equal(test.expOpts))
+			g.Expect(msgs).To(HaveLen(test.expMsgs))
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package dataplane
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
+)
+
+// routeWeightScale scales the weights of the backends of a merged rule, so that the weights stay integers while
+// the shares of the backends within a rule are preserved.
+const routeWeightScale = 1000
+
+// mergeWeightedMatchRules merges the MatchRules of different HTTPRoutes with the WeightAnnotation that have
+// the same match and GeoMatch into a single MatchRule, whose BackendGroup splits the requests across the backends
+// of all merged rules in proportion to the weights of their HTTPRoutes. Without a merge, the MatchRule with
+// the highest precedence would handle all such requests.
+// The MatchRules must be sorted by precedence. A merged MatchRule takes the place, the filters and the options
+// of the MatchRule with the highest precedence.
+func mergeWeightedMatchRules(matchRules []MatchRule) []MatchRule {
+	groups := make([][]MatchRule, 0, len(matchRules))
+
+	for _, r := range matchRules {
+		idx := findWeightedGroup(groups, r)
+		if idx == -1 {
+			groups = append(groups, []MatchRule{r})
+			continue
+		}
+
+		groups[idx] = append(groups[idx], r)
+	}
+
+	if len(groups) == len(matchRules) {
+		return matchRules
+	}
+
+	merged := make([]MatchRule, 0, len(groups))
+	for _, g := range groups {
+		merged = append(merged, mergeMatchRules(g))
+	}
+
+	return merged
+}
+
+// findWeightedGroup returns the index of the group of MatchRules that the MatchRule can be merged into,
+// or -1 if there is no such group.
+func findWeightedGroup(groups [][]MatchRule, r MatchRule) int {
+	if r.Options.Weight == nil {
+		return -1
+	}
+
+	for i, g := range groups {
+		if g[0].Options.Weight == nil || !reflect.DeepEqual(g[0].GetMatch(), r.GetMatch()) ||
+			!reflect.DeepEqual(g[0].Options.GeoMatch, r.Options.GeoMatch) {
+			continue
+		}
+
+		// A MatchRule of the same HTTPRoute is shadowed by the one with the higher precedence.
+		for _, gr := range g {
+			if gr.Source == r.Source {
+				return -1
+			}
+		}
+
+		return i
+	}
+
+	return -1
+}
+
+// mergeMatchRules merges the MatchRules into the first one. A single MatchRule is returned as is.
+func mergeMatchRules(matchRules []MatchRule) MatchRule {
+	merged := matchRules[0]
+	if len(matchRules) == 1 {
+		return merged
+	}
+
+	names := make([]string, 0, len(matchRules))
+	group := graph.BackendGroup{
+		Source:  merged.BackendGroup.Source,
+		RuleIdx: merged.BackendGroup.RuleIdx,
+	}
+
+	for _, r := range matchRules {
+		names = append(names, r.BackendGroup.GroupName())
+		group.Backends = append(group.Backends, scaleBackends(r.BackendGroup, *r.Options.Weight)...)
+	}
+
+	group.Name = "weighted__" + strings.Join(names, "__")
+
+	merged.BackendGroup = group
+	merged.BackendProtocol, _ = getBackendGroupProtocol(group)
+
+	return merged
+}
+
+// scaleBackends returns the backends of the group with their weights scaled so that the backends get the weight
+// of the HTTPRoute in total. A group without backends or with zero total weight gets an invalid backend,
+// so that NGINX responds with an error to the share of the requests of the HTTPRoute.
+func scaleBackends(group graph.BackendGroup, routeWeight int32) []graph.BackendRef {
+	totalWeight := int64(0)
+	for _, b := range group.Backends {
+		totalWeight += int64(b.Weight)
+	}
+
+	if totalWeight == 0 {
+		return []graph.BackendRef{{Weight: routeWeight * routeWeightScale}}
+	}
+
+	backends := make([]graph.BackendRef, 0, len(group.Backends))
+
+	for _, b := range group.Backends {
+		b.Weight = int32(int64(routeWeight) * routeWeightScale * int64(b.Weight) / totalWeight)
+		backends = append(backends, b)
+	}
+
+	return backends
+}
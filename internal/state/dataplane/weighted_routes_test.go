@@ -0,0 +1,155 @@
+package dataplane
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
+)
+
+func TestMergeWeightedMatchRules(t *testing.T) {
+	createRoute := func(name string, paths ...string) *v1beta1.HTTPRoute {
+		rules := make([]v1beta1.HTTPRouteRule, 0, len(paths))
+		for _, p := range paths {
+			rules = append(rules, v1beta1.HTTPRouteRule{
+				Matches: []v1beta1.HTTPRouteMatch{
+					{
+						Path: &v1beta1.HTTPPathMatch{
+							Value: helpers.GetStringPointer(p),
+						},
+					},
+				},
+			})
+		}
+
+		return &v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test",
+				Name:      name,
+			},
+			Spec: v1beta1.HTTPRouteSpec{
+				Rules: rules,
+			},
+		}
+	}
+
+	createMatchRule := func(
+		route *v1beta1.HTTPRoute,
+		ruleIdx int,
+		weight *int32,
+		backends ...graph.BackendRef,
+	) MatchRule {
+		return MatchRule{
+			Source:  route,
+			RuleIdx: ruleIdx,
+			Options: RouteOptions{Weight: weight},
+			BackendGroup: graph.BackendGroup{
+				Source:   types.NamespacedName{Namespace: route.Namespace, Name: route.Name},
+				RuleIdx:  ruleIdx,
+				Backends: backends,
+			},
+		}
+	}
+
+	fooBackend := graph.BackendRef{Name: "foo", Valid: true, Weight: 1}
+	barBackend := graph.BackendRef{Name: "bar", Valid: true, Weight: 3}
+	bazBackend := graph.BackendRef{Name: "baz", Valid: true, Weight: 1}
+
+	hr1 := createRoute("hr1", "/coffee")
+	hr2 := createRoute("hr2", "/coffee", "/tea")
+	hr3 := createRoute("hr3", "/coffee", "/coffee")
+
+	hr1Rule0 := createMatchRule(hr1, 0, helpers.GetInt32Pointer(80), fooBackend, barBackend)
+	hr2Rule0 := createMatchRule(hr2, 0, helpers.GetInt32Pointer(20), bazBackend)
+	hr2Rule1 := createMatchRule(hr2, 1, helpers.GetInt32Pointer(20), bazBackend)
+	hr2Rule0NoWeight := createMatchRule(hr2, 0, nil, bazBackend)
+	hr2Rule0NoBackends := createMatchRule(hr2, 0, helpers.GetInt32Pointer(20))
+	hr2Rule0GeoMatch := createMatchRule(hr2, 0, helpers.GetInt32Pointer(20), bazBackend)
+	hr2Rule0GeoMatch.Options.GeoMatch = &graph.GeoMatch{Type: graph.GeoMatchTypeContinent, Codes: []string{"EU"}}
+	hr3Rule0 := createMatchRule(hr3, 0, helpers.GetInt32Pointer(50), fooBackend)
+	hr3Rule1 := createMatchRule(hr3, 1, helpers.GetInt32Pointer(50), bazBackend)
+
+	createMergedMatchRule := func(groupName string, backends ...graph.BackendRef) MatchRule {
+		merged := hr1Rule0
+		merged.BackendGroup = graph.BackendGroup{
+			Source:   types.NamespacedName{Namespace: "test", Name: "hr1"},
+			Name:     groupName,
+			Backends: backends,
+		}
+
+		return merged
+	}
+
+	tests := []struct {
+		msg      string
+		rules    []MatchRule
+		expRules []MatchRule
+	}{
+		{
+			msg:   "rules with the same match",
+			rules: []MatchRule{hr1Rule0, hr2Rule0},
+			expRules: []MatchRule{
+				createMergedMatchRule(
+					"weighted__test__hr1_rule0__test__hr2_rule0",
+					graph.BackendRef{Name: "foo", Valid: true, Weight: 20000},
+					graph.BackendRef{Name: "bar", Valid: true, Weight: 60000},
+					graph.BackendRef{Name: "baz", Valid: true, Weight: 20000},
+				),
+			},
+		},
+		{
+			msg:   "rule without backends",
+			rules: []MatchRule{hr1Rule0, hr2Rule0NoBackends},
+			expRules: []MatchRule{
+				createMergedMatchRule(
+					"weighted__test__hr1_rule0__test__hr2_rule0",
+					graph.BackendRef{Name: "foo", Valid: true, Weight: 20000},
+					graph.BackendRef{Name: "bar", Valid: true, Weight: 60000},
+					graph.BackendRef{Weight: 20000},
+				),
+			},
+		},
+		{
+			msg:      "rules with different matches",
+			rules:    []MatchRule{hr1Rule0, hr2Rule1},
+			expRules: []MatchRule{hr1Rule0, hr2Rule1},
+		},
+		{
+			msg:      "rule without weight",
+			rules:    []MatchRule{hr1Rule0, hr2Rule0NoWeight},
+			expRules: []MatchRule{hr1Rule0, hr2Rule0NoWeight},
+		},
+		{
+			msg:      "rules with different geo matches",
+			rules:    []MatchRule{hr1Rule0, hr2Rule0GeoMatch},
+			expRules: []MatchRule{hr1Rule0, hr2Rule0GeoMatch},
+		},
+		{
+			msg:   "rules of the same route with the same match",
+			rules: []MatchRule{hr1Rule0, hr3Rule0, hr3Rule1},
+			expRules: []MatchRule{
+				createMergedMatchRule(
+					"weighted__test__hr1_rule0__test__hr3_rule0",
+					graph.BackendRef{Name: "foo", Valid: true, Weight: 20000},
+					graph.BackendRef{Name: "bar", Valid: true, Weight: 60000},
+					graph.BackendRef{Name: "foo", Valid: true, Weight: 50000},
+				),
+				hr3Rule1,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			result := mergeWeightedMatchRules(test.rules)
+			g.Expect(helpers.Diff(test.expRules, result)).To(BeEmpty())
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package state
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
+)
+
+// diffGraphs returns a human-readable list of the differences between the previous and the current Graph.
+// The previous Graph can be nil, if the current Graph is the first one.
+// The entries are sorted so that the result is deterministic.
+func diffGraphs(prev, cur *graph.Graph) []string {
+	if prev == nil {
+		prev = &graph.Graph{}
+	}
+
+	var diff []string
+
+	diff = append(diff, diffGatewayClasses(prev.GatewayClass, cur.GatewayClass)...)
+	diff = append(diff, diffGateways(prev.Gateway, cur.Gateway)...)
+
+	for nsname := range cur.Routes {
+		if _, exist := prev.Routes[nsname]; !exist {
+			diff = append(diff, fmt.Sprintf("added HTTPRoute %s", nsname))
+		}
+	}
+
+	for nsname, prevRoute := range prev.Routes {
+		curRoute, exist := cur.Routes[nsname]
+		if !exist {
+			diff = append(diff, fmt.Sprintf("removed HTTPRoute %s", nsname))
+			continue
+		}
+
+		if !reflect.DeepEqual(prevRoute.ValidSectionNameRefs, curRoute.ValidSectionNameRefs) ||
+			!reflect.DeepEqual(prevRoute.InvalidSectionNameRefs, curRoute.InvalidSectionNameRefs) {
+			diff = append(diff, fmt.Sprintf("changed parent refs of HTTPRoute %s", nsname))
+		}
+
+		if prevRoute.Source.Generation != curRoute.Source.Generation {
+			diff = append(diff, fmt.Sprintf("changed spec of HTTPRoute %s", nsname))
+		}
+	}
+
+	sort.Strings(diff)
+
+	return diff
+}
+
+func diffGatewayClasses(prev, cur *graph.GatewayClass) []string {
+	switch {
+	case prev == nil && cur == nil:
+		return nil
+	case prev == nil:
+		return []string{fmt.Sprintf("added GatewayClass %s", cur.Source.Name)}
+	case cur == nil:
+		return []string{fmt.Sprintf("removed GatewayClass %s", prev.Source.Name)}
+	case prev.Valid != cur.Valid || prev.ErrorMsg != cur.ErrorMsg:
+		return []string{fmt.Sprintf("changed validity of GatewayClass %s", cur.Source.Name)}
+	}
+
+	return nil
+}
+
+func diffGateways(prev, cur *graph.Gateway) []string {
+	switch {
+	case prev == nil && cur == nil:
+		return nil
+	case prev == nil:
+		return []string{fmt.Sprintf("added Gateway %s/%s", cur.Source.Namespace, cur.Source.Name)}
+	case cur == nil:
+		return []string{fmt.Sprintf("removed Gateway %s/%s", prev.Source.Namespace, prev.Source.Name)}
+	}
+
+	var diff []string
+
+	for name := range cur.Listeners {
+		if _, exist := prev.Listeners[name]; !exist {
+			diff = append(diff, fmt.Sprintf("added listener %s", name))
+		}
+	}
+
+	for name, prevListener := range prev.Listeners {
+		curListener, exist := cur.Listeners[name]
+		if !exist {
+			diff = append(diff, fmt.Sprintf("removed listener %s", name))
+			continue
+		}
+
+		if !reflect.DeepEqual(prevListener.Conditions, curListener.Conditions) {
+			diff = append(diff, fmt.Sprintf("changed conditions of listener %s", name))
+		}
+
+		if !reflect.DeepEqual(prevListener.AcceptedHostnames, curListener.AcceptedHostnames) {
+			diff = append(diff, fmt.Sprintf("changed accepted hostnames of listener %s", name))
+		}
+	}
+
+	return diff
+}
+
+// diffUpstreams returns a human-readable list of the differences between the previous and the current Upstreams.
+// The entries are sorted so that the result is deterministic.
+func diffUpstreams(prev, cur []dataplane.Upstream) []string {
+	prevUpstreams := make(map[string]dataplane.Upstream, len(prev))
+	for _, u := range prev {
+		prevUpstreams[u.Name] = u
+	}
+
+	curUpstreams := make(map[string]dataplane.Upstream, len(cur))
+	for _, u := range cur {
+		curUpstreams[u.Name] = u
+	}
+
+	var diff []string
+
+	for name := range curUpstreams {
+		if _, exist := prevUpstreams[name]; !exist {
+			diff = append(diff, fmt.Sprintf("added upstream %s", name))
+		}
+	}
+
+	for name, prevUpstream := range prevUpstreams {
+		curUpstream, exist := curUpstreams[name]
+		if !exist {
+			diff = append(diff, fmt.Sprintf("removed upstream %s", name))
+			continue
+		}
+
+		if !reflect.DeepEqual(prevUpstream.Endpoints, curUpstream.Endpoints) {
+			diff = append(diff, fmt.Sprintf("changed endpoints of upstream %s", name))
+		}
+
+		if !reflect.DeepEqual(prevUpstream.Options, curUpstream.Options) {
+			diff = append(diff, fmt.Sprintf("changed options of upstream %s", name))
+		}
+	}
+
+	sort.Strings(diff)
+
+	return diff
+}
@@ -0,0 +1,149 @@
+package state
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/conditions"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/resolver"
+)
+
+func TestDiffGraphs(t *testing.T) {
+	createRoute := func(name string, generation int64) *graph.Route {
+		return &graph.Route{
+			Source: &v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:  "test",
+					Name:       name,
+					Generation: generation,
+				},
+			},
+			ValidSectionNameRefs:   map[string]struct{}{"listener-80-1": {}},
+			InvalidSectionNameRefs: map[string]conditions.Condition{},
+		}
+	}
+
+	gw := &v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "gateway",
+		},
+	}
+
+	prev := &graph.Graph{
+		Gateway: &graph.Gateway{
+			Source: gw,
+			Listeners: map[string]*graph.Listener{
+				"listener-80-1": {Valid: true},
+				"listener-80-2": {Valid: true},
+			},
+		},
+		Routes: map[types.NamespacedName]*graph.Route{
+			{Namespace: "test", Name: "hr-1"}: createRoute("hr-1", 1),
+			{Namespace: "test", Name: "hr-2"}: createRoute("hr-2", 1),
+		},
+	}
+
+	cur := &graph.Graph{
+		Gateway: &graph.Gateway{
+			Source: gw,
+			Listeners: map[string]*graph.Listener{
+				"listener-80-1": {
+					Valid:      false,
+					Conditions: []conditions.Condition{conditions.NewListenerUnsupportedValue("test")},
+				},
+				"listener-80-3": {Valid: true},
+			},
+		},
+		Routes: map[types.NamespacedName]*graph.Route{
+			{Namespace: "test", Name: "hr-1"}: createRoute("hr-1", 2),
+			{Namespace: "test", Name: "hr-3"}: createRoute("hr-3", 1),
+		},
+	}
+
+	tests := []struct {
+		prev, cur *graph.Graph
+		msg       string
+		expected  []string
+	}{
+		{
+			prev:     nil,
+			cur:      &graph.Graph{},
+			expected: nil,
+			msg:      "first empty graph",
+		},
+		{
+			prev:     prev,
+			cur:      prev,
+			expected: nil,
+			msg:      "no changes",
+		},
+		{
+			prev: &graph.Graph{},
+			cur: &graph.Graph{
+				Routes: map[types.NamespacedName]*graph.Route{
+					{Namespace: "test", Name: "hr-1"}: createRoute("hr-1", 1),
+				},
+			},
+			expected: []string{"added HTTPRoute test/hr-1"},
+			msg:      "route added",
+		},
+		{
+			prev: prev,
+			cur:  cur,
+			expected: []string{
+				"added HTTPRoute test/hr-3",
+				"added listener listener-80-3",
+				"changed conditions of listener listener-80-1",
+				"changed spec of HTTPRoute test/hr-1",
+				"removed HTTPRoute test/hr-2",
+				"removed listener listener-80-2",
+			},
+			msg: "multiple changes",
+		},
+		{
+			prev:     prev,
+			cur:      &graph.Graph{Routes: prev.Routes},
+			expected: []string{"removed Gateway test/gateway"},
+			msg:      "gateway removed",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			g.Expect(diffGraphs(test.prev, test.cur)).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestDiffUpstreams(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	prev := []dataplane.Upstream{
+		{Name: "up1", Endpoints: []resolver.Endpoint{{Address: "10.0.0.1", Port: 80}}},
+		{Name: "up2", Endpoints: []resolver.Endpoint{{Address: "10.0.0.2", Port: 80}}},
+		{Name: "up3"},
+	}
+
+	cur := []dataplane.Upstream{
+		{Name: "up1", Endpoints: []resolver.Endpoint{{Address: "10.0.0.1", Port: 80}}},
+		{Name: "up2", Endpoints: []resolver.Endpoint{{Address: "10.0.0.3", Port: 80}}},
+		{Name: "up4"},
+	}
+
+	expected := []string{
+		"added upstream up4",
+		"changed endpoints of upstream up2",
+		"removed upstream up3",
+	}
+
+	g.Expect(diffUpstreams(prev, cur)).To(Equal(expected))
+}
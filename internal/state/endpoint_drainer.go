@@ -0,0 +1,112 @@
+package state
+
+import (
+	"sort"
+	"time"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/resolver"
+)
+
+// endpointDrainer keeps the endpoints removed from the Upstreams in the Upstreams as draining endpoints for
+// a grace period. NGINX doesn't send new requests to the draining endpoints, so that the endpoints, for example,
+// the Pods of a Deployment that is being scaled down, can complete the requests in flight before they go away.
+// After the grace period expires, the endpoints are removed from the Upstreams.
+//
+// An endpoint that comes back to its Upstream during the grace period stops draining. If an Upstream is removed,
+// its draining endpoints are removed along with it.
+type endpointDrainer struct {
+	// endpoints holds the endpoints of the Upstreams seen by the latest call to drain.
+	endpoints map[string]map[resolver.Endpoint]struct{}
+	// removals holds the removal times of the draining endpoints of the Upstreams.
+	removals    map[string]map[resolver.Endpoint]time.Time
+	gracePeriod time.Duration
+}
+
+func newEndpointDrainer(gracePeriod time.Duration) *endpointDrainer {
+	return &endpointDrainer{
+		endpoints:   make(map[string]map[resolver.Endpoint]struct{}),
+		removals:    make(map[string]map[resolver.Endpoint]time.Time),
+		gracePeriod: gracePeriod,
+	}
+}
+
+// drain adds the endpoints removed from the upstreams, whose grace period hasn't expired by now,
+// to the DrainingEndpoints of the upstreams. The upstreams are modified in place.
+func (d *endpointDrainer) drain(upstreams []dataplane.Upstream, now time.Time) {
+	if d.gracePeriod <= 0 {
+		return
+	}
+
+	endpoints := make(map[string]map[resolver.Endpoint]struct{}, len(upstreams))
+	removals := make(map[string]map[resolver.Endpoint]time.Time)
+
+	for i := range upstreams {
+		u := &upstreams[i]
+
+		current := make(map[resolver.Endpoint]struct{}, len(u.Endpoints))
+		for _, ep := range u.Endpoints {
+			current[ep] = struct{}{}
+		}
+
+		removed := make(map[resolver.Endpoint]time.Time)
+
+		for ep, removedAt := range d.removals[u.Name] {
+			if _, exists := current[ep]; exists {
+				continue
+			}
+
+			if !now.Before(removedAt.Add(d.gracePeriod)) {
+				continue
+			}
+
+			removed[ep] = removedAt
+		}
+
+		for ep := range d.endpoints[u.Name] {
+			if _, exists := current[ep]; !exists {
+				removed[ep] = now
+			}
+		}
+
+		endpoints[u.Name] = current
+
+		if len(removed) == 0 {
+			continue
+		}
+
+		removals[u.Name] = removed
+
+		u.DrainingEndpoints = make([]resolver.Endpoint, 0, len(removed))
+		for ep := range removed {
+			u.DrainingEndpoints = append(u.DrainingEndpoints, ep)
+		}
+
+		sort.Slice(u.DrainingEndpoints, func(i, j int) bool {
+			if u.DrainingEndpoints[i].Address != u.DrainingEndpoints[j].Address {
+				return u.DrainingEndpoints[i].Address < u.DrainingEndpoints[j].Address
+			}
+
+			return u.DrainingEndpoints[i].Port < u.DrainingEndpoints[j].Port
+		})
+	}
+
+	d.endpoints = endpoints
+	d.removals = removals
+}
+
+// nextExpiry returns the time when the grace period of the next draining endpoint expires.
+// If there are no draining endpoints, the exists return value is false.
+func (d *endpointDrainer) nextExpiry() (expiry time.Time, exists bool) {
+	for _, removed := range d.removals {
+		for _, removedAt := range removed {
+			t := removedAt.Add(d.gracePeriod)
+			if !exists || t.Before(expiry) {
+				expiry = t
+				exists = true
+			}
+		}
+	}
+
+	return expiry, exists
+}
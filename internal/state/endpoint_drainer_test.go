@@ -0,0 +1,121 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/resolver"
+)
+
+func TestEndpointDrainer(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	const gracePeriod = 10 * time.Second
+
+	ep1 := resolver.Endpoint{Address: "10.0.0.1", Port: 8080}
+	ep2 := resolver.Endpoint{Address: "10.0.0.2", Port: 8080}
+	ep3 := resolver.Endpoint{Address: "10.0.0.3", Port: 8080}
+
+	createUpstreams := func(eps ...resolver.Endpoint) []dataplane.Upstream {
+		return []dataplane.Upstream{
+			{
+				Name:      "up",
+				Endpoints: eps,
+			},
+		}
+	}
+
+	start := time.Now()
+	drainer := newEndpointDrainer(gracePeriod)
+
+	// all endpoints are up
+
+	upstreams := createUpstreams(ep1, ep2, ep3)
+	drainer.drain(upstreams, start)
+
+	g.Expect(upstreams[0].DrainingEndpoints).To(BeEmpty())
+	_, exists := drainer.nextExpiry()
+	g.Expect(exists).To(BeFalse())
+
+	// ep2 and ep3 are removed and become draining (down)
+
+	upstreams = createUpstreams(ep1)
+	drainer.drain(upstreams, start.Add(time.Second))
+
+	g.Expect(upstreams[0].Endpoints).To(Equal([]resolver.Endpoint{ep1}))
+	g.Expect(upstreams[0].DrainingEndpoints).To(Equal([]resolver.Endpoint{ep2, ep3}))
+	expiry, exists := drainer.nextExpiry()
+	g.Expect(exists).To(BeTrue())
+	g.Expect(expiry).To(Equal(start.Add(time.Second + gracePeriod)))
+
+	// ep3 comes back during the grace period and stops draining
+
+	upstreams = createUpstreams(ep1, ep3)
+	drainer.drain(upstreams, start.Add(5*time.Second))
+
+	g.Expect(upstreams[0].DrainingEndpoints).To(Equal([]resolver.Endpoint{ep2}))
+
+	// ep1 is removed, while ep2 is still draining
+
+	upstreams = createUpstreams(ep3)
+	drainer.drain(upstreams, start.Add(8*time.Second))
+
+	g.Expect(upstreams[0].DrainingEndpoints).To(Equal([]resolver.Endpoint{ep1, ep2}))
+	expiry, exists = drainer.nextExpiry()
+	g.Expect(exists).To(BeTrue())
+	g.Expect(expiry).To(Equal(start.Add(time.Second + gracePeriod)))
+
+	// the grace period of ep2 expires and ep2 disappears
+
+	upstreams = createUpstreams(ep3)
+	drainer.drain(upstreams, start.Add(time.Second+gracePeriod))
+
+	g.Expect(upstreams[0].DrainingEndpoints).To(Equal([]resolver.Endpoint{ep1}))
+	expiry, exists = drainer.nextExpiry()
+	g.Expect(exists).To(BeTrue())
+	g.Expect(expiry).To(Equal(start.Add(8*time.Second + gracePeriod)))
+
+	// the grace period of ep1 expires and ep1 disappears
+
+	upstreams = createUpstreams(ep3)
+	drainer.drain(upstreams, start.Add(8*time.Second+gracePeriod))
+
+	g.Expect(upstreams[0].DrainingEndpoints).To(BeEmpty())
+	_, exists = drainer.nextExpiry()
+	g.Expect(exists).To(BeFalse())
+
+	// ep3 is removed and becomes draining
+
+	upstreams = createUpstreams()
+	drainer.drain(upstreams, start.Add(20*time.Second))
+
+	g.Expect(upstreams[0].DrainingEndpoints).To(Equal([]resolver.Endpoint{ep3}))
+
+	// the upstream is removed along with its draining endpoints
+
+	drainer.drain(nil, start.Add(21*time.Second))
+
+	_, exists = drainer.nextExpiry()
+	g.Expect(exists).To(BeFalse())
+}
+
+func TestEndpointDrainerNoGracePeriod(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ep1 := resolver.Endpoint{Address: "10.0.0.1", Port: 8080}
+	ep2 := resolver.Endpoint{Address: "10.0.0.2", Port: 8080}
+
+	drainer := newEndpointDrainer(0)
+
+	drainer.drain([]dataplane.Upstream{{Name: "up", Endpoints: []resolver.Endpoint{ep1, ep2}}}, time.Now())
+
+	upstreams := []dataplane.Upstream{{Name: "up", Endpoints: []resolver.Endpoint{ep1}}}
+	drainer.drain(upstreams, time.Now())
+
+	g.Expect(upstreams[0].DrainingEndpoints).To(BeEmpty())
+	_, exists := drainer.nextExpiry()
+	g.Expect(exists).To(BeFalse())
+}
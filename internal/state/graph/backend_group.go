@@ -1,15 +1,20 @@
 package graph
 
 import (
-	"fmt"
+	"math"
+	"strconv"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
 // BackendGroup represents a group of backends for a rule in an HTTPRoute.
 type BackendGroup struct {
-	Source   types.NamespacedName
+	Source types.NamespacedName
+	// Name overrides the name of the group. It is set for the groups that combine the backends of the rules of
+	// multiple HTTPRoutes, which are not created by the graph.
+	Name     string
 	Errors   []string
 	Backends []BackendRef
 	RuleIdx  int
@@ -17,11 +22,14 @@ type BackendGroup struct {
 
 // BackendRef is an internal representation of a backendRef in an HTTPRoute.
 type BackendRef struct {
-	Svc    *v1.Service
-	Name   string
-	Port   int32
-	Weight int32
-	Valid  bool
+	Svc *v1.Service
+	// RequestHeaderModifier modifies the headers of the requests that NGINX sends to this backend.
+	// It comes from the first RequestHeaderModifier filter of the backendRef. Nil means no modifications.
+	RequestHeaderModifier *v1beta1.HTTPHeaderFilter
+	Name                  string
+	Port                  int32
+	Weight                int32
+	Valid                 bool
 }
 
 // GroupName returns the name of the backend group.
@@ -29,6 +37,53 @@ type BackendRef struct {
 // The RuleIdx is used to make the name unique across all rules within the same HTTPRoute.
 // The RuleIdx may change for a given rule if an update is made to the HTTPRoute, but it will always match the index
 // of the rule in the stored HTTPRoute.
+// If the Name of the group is set, it is returned instead.
 func (bg *BackendGroup) GroupName() string {
-	return fmt.Sprintf("%s__%s_rule%d", bg.Source.Namespace, bg.Source.Name, bg.RuleIdx)
+	if bg.Name != "" {
+		return bg.Name
+	}
+
+	return bg.Source.Namespace + "__" + bg.Source.Name + "_rule" + strconv.Itoa(bg.RuleIdx)
+}
+
+// Percentages returns the percentages of the traffic, in the order of the Backends, that NGINX sends to each
+// backend of the group according to the weights of the backends.
+// The percentages are rounded down to 2 decimal places, except for the last backend, which gets the remaining
+// percentage. This guarantees that the sum of all percentages is 100.
+// If the total weight of the backends is 0, it returns nil.
+func (bg *BackendGroup) Percentages() []float64 {
+	totalWeight := int32(0)
+	for _, b := range bg.Backends {
+		totalWeight += b.Weight
+	}
+
+	if totalWeight == 0 {
+		return nil
+	}
+
+	percentages := make([]float64, 0, len(bg.Backends))
+
+	// The percentage of all backends cannot exceed 100.
+	availablePercentage := float64(100)
+
+	// Iterate over all backends except the last one.
+	// The last backend will get the remaining percentage.
+	for i := 0; i < len(bg.Backends)-1; i++ {
+		percentage := percentOf(bg.Backends[i].Weight, totalWeight)
+		availablePercentage -= percentage
+
+		percentages = append(percentages, percentage)
+	}
+
+	return append(percentages, availablePercentage)
+}
+
+// percentOf returns the percentage of a weight out of a totalWeight.
+// The percentage is rounded to 2 decimal places using the Floor method.
+// Floor is used here in order to guarantee that the sum of all percentages does not exceed 100.
+// Ex. percentOf(2, 3) = 66.66
+// Ex. percentOf(800, 2000) = 40.00
+func percentOf(weight, totalWeight int32) float64 {
+	p := (float64(weight) * 100) / float64(totalWeight)
+	return math.Floor(p*100) / 100
 }
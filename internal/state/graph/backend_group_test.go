@@ -1,15 +1,15 @@
-package graph_test
+package graph
 
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"k8s.io/apimachinery/pkg/types"
-
-	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
 )
 
 func TestBackendGroup_GroupName(t *testing.T) {
-	bg := graph.BackendGroup{
+	bg := BackendGroup{
 		Source:  types.NamespacedName{Namespace: "test", Name: "hr"},
 		RuleIdx: 20,
 	}
@@ -19,3 +19,133 @@ func TestBackendGroup_GroupName(t *testing.T) {
 		t.Errorf("BackendGroup.GroupName() mismatch; expected %s, got %s", expected, result)
 	}
 }
+
+func TestBackendGroup_Percentages(t *testing.T) {
+	createGroup := func(weights ...int32) BackendGroup {
+		bg := BackendGroup{}
+		for _, w := range weights {
+			bg.Backends = append(bg.Backends, BackendRef{Weight: w})
+		}
+		return bg
+	}
+
+	tests := []struct {
+		msg      string
+		group    BackendGroup
+		expected []float64
+	}{
+		{
+			msg:      "no backends",
+			group:    createGroup(),
+			expected: nil,
+		},
+		{
+			msg:      "one backend",
+			group:    createGroup(5),
+			expected: []float64{100},
+		},
+		{
+			msg:      "equal weights",
+			group:    createGroup(1, 1),
+			expected: []float64{50, 50},
+		},
+		{
+			msg:      "last backend gets the remaining percentage",
+			group:    createGroup(1, 1, 1),
+			expected: []float64{33.33, 33.33, 33.34},
+		},
+		{
+			msg:      "canary",
+			group:    createGroup(90, 0, 10),
+			expected: []float64{90, 0, 10},
+		},
+		{
+			msg:      "all weights are zero",
+			group:    createGroup(0, 0),
+			expected: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			result := test.group.Percentages()
+			if diff := cmp.Diff(test.expected, result, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("BackendGroup.Percentages() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPercentOf(t *testing.T) {
+	tests := []struct {
+		msg         string
+		weight      int32
+		totalWeight int32
+		expPercent  float64
+	}{
+		{
+			msg:         "50/100",
+			weight:      50,
+			totalWeight: 100,
+			expPercent:  50,
+		},
+		{
+			msg:         "2000/4000",
+			weight:      2000,
+			totalWeight: 4000,
+			expPercent:  50,
+		},
+		{
+			msg:         "100/100",
+			weight:      100,
+			totalWeight: 100,
+			expPercent:  100,
+		},
+		{
+			msg:         "5/5",
+			weight:      5,
+			totalWeight: 5,
+			expPercent:  100,
+		},
+		{
+			msg:         "0/8000",
+			weight:      0,
+			totalWeight: 8000,
+			expPercent:  0,
+		},
+		{
+			msg:         "2/3",
+			weight:      2,
+			totalWeight: 3,
+			expPercent:  66.66,
+		},
+		{
+			msg:         "4/15",
+			weight:      4,
+			totalWeight: 15,
+			expPercent:  26.66,
+		},
+		{
+			msg:         "800/2000",
+			weight:      800,
+			totalWeight: 2000,
+			expPercent:  40,
+		},
+		{
+			msg:         "300/2400",
+			weight:      300,
+			totalWeight: 2400,
+			expPercent:  12.5,
+		},
+	}
+
+	for _, test := range tests {
+		percent := percentOf(test.weight, test.totalWeight)
+		if percent != test.expPercent {
+			t.Errorf(
+				"percentOf() mismatch for test %q; expected %f, got %f",
+				test.msg, test.expPercent, percent,
+			)
+		}
+	}
+}
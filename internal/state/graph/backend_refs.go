@@ -3,73 +3,478 @@ package graph
 import (
 	"errors"
 	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/conditions"
 )
 
+// BackendKind is the group and the kind of the resource that a backendRef references.
+type BackendKind struct {
+	// Group is the API group of the resource. The empty string is the core group.
+	Group string
+	// Kind is the kind of the resource.
+	Kind string
+}
+
+func (k BackendKind) String() string {
+	if k.Group == "" {
+		return k.Kind
+	}
+
+	return fmt.Sprintf("%s.%s", k.Kind, k.Group)
+}
+
+// serviceBackendKind is the kind of the backendRefs that reference core Services, which are supported by default.
+var serviceBackendKind = BackendKind{Kind: "Service"}
+
+// BackendResolver resolves a backendRef that references a resource of a custom kind, for example, a multi-cluster
+// ServiceImport, to the Service and the port that NGINX proxies the requests to. routeNamespace is the namespace
+// of the HTTPRoute. services are all Services of the cluster.
+type BackendResolver func(
+	ref v1beta1.BackendRef,
+	routeNamespace string,
+	services map[types.NamespacedName]*v1.Service,
+) (*v1.Service, int32, error)
+
+// BackendResolvers holds the BackendResolvers of the custom kinds of backendRefs.
+type BackendResolvers map[BackendKind]BackendResolver
+
 // addBackendGroupsToRoutes iterates over the routes and adds BackendGroups to the routes.
 // The routes are modified in place.
 // If a backend ref is invalid it will store an error message in the BackendGroup.Errors field.
+// The backend refs of a rule that reference the same backend are merged into one with the sum of their weights.
+// If their filters differ, the merged backend ref is invalid, and the route gets the ResolvedRefs condition with
+// the ConflictingBackendFilters reason.
+// If the route has rules with multiple backend refs or rules whose backend refs all have zero weight,
+// it adds a condition that reports the normalized weights to the route.
 // A backend ref is invalid if:
-// - the Kind is not Service
+// - the Group and Kind are not the core Service and there is no resolver for them in backendResolvers.
+// The route of such a backend ref gets the ResolvedRefs condition with the InvalidKind reason.
+// - the resolver of its custom kind fails. The route gets the ResolvedRefs condition with the BackendNotFound reason.
+// - the Service doesn't exist. The route gets the ResolvedRefs condition with the BackendNotFound reason.
+// - the Service doesn't have the port of the backend ref. The route gets the ResolvedRefs condition with
+// the BackendNotFound reason.
 // - the Namespace is not the same as the HTTPRoute namespace
 // - the Port is nil
+// - the Service is of the ExternalName type, and its external name doesn't match the externalNameAllowlist.
+// The route of such a backend ref gets the ResolvedRefs condition with the ExternalNameNotAllowed reason.
+// An empty externalNameAllowlist allows all external names.
+// All invalid backend refs of the route are reported with a single ResolvedRefs condition, whose reason is picked
+// by createResolvedRefsCondition.
 func addBackendGroupsToRoutes(
 	routes map[types.NamespacedName]*Route,
 	services map[types.NamespacedName]*v1.Service,
+	externalNameAllowlist []string,
+	backendResolvers BackendResolvers,
 ) {
 	for _, r := range routes {
 		r.BackendGroups = make([]BackendGroup, len(r.Source.Spec.Rules))
 
+		var notAllowedMsgs, invalidKindMsgs, unresolvedMsgs, conflictingMsgs []string
+
 		for idx, rule := range r.Source.Spec.Rules {
+			refs, conflicting := mergeDuplicateBackendRefs(rule.BackendRefs, r.Source.Namespace)
 
 			group := BackendGroup{
 				Source:  client.ObjectKeyFromObject(r.Source),
 				RuleIdx: idx,
 			}
 
-			if len(rule.BackendRefs) == 0 {
+			if len(refs) == 0 {
 
 				r.BackendGroups[idx] = group
 				continue
 			}
 
-			group.Errors = make([]string, 0, len(rule.BackendRefs))
-			group.Backends = make([]BackendRef, 0, len(rule.BackendRefs))
+			group.Errors = make([]string, 0, len(refs))
+			group.Backends = make([]BackendRef, 0, len(refs))
+
+			for refIdx, ref := range refs {
 
-			for _, ref := range rule.BackendRefs {
+				weight := getBackendRefWeight(ref.BackendRef)
 
-				weight := int32(1)
-				if ref.Weight != nil {
-					weight = *ref.Weight
+				if _, exists := conflicting[refIdx]; exists {
+					msg := fmt.Sprintf(
+						"the backendRefs %s reference the same backend with different filters",
+						getBackendRefName(ref.BackendRef),
+					)
+
+					group.Backends = append(group.Backends, BackendRef{Weight: weight})
+					group.Errors = append(group.Errors, msg)
+					conflictingMsgs = append(conflictingMsgs, fmt.Sprintf("rule %d: %s", idx, msg))
+
+					continue
+				}
+
+				var (
+					svc  *v1.Service
+					port int32
+					err  error
+				)
+
+				kind := getBackendKind(ref.BackendRef)
+
+				if kind == serviceBackendKind {
+					svc, port, err = getServiceAndPortFromRef(ref.BackendRef, r.Source.Namespace, services)
+				} else {
+					resolve, exists := backendResolvers[kind]
+					if !exists {
+						msg := fmt.Sprintf("the kind %s of the backendRef %s is not supported", kind, ref.Name)
+
+						group.Backends = append(group.Backends, BackendRef{Weight: weight})
+						group.Errors = append(group.Errors, msg)
+						invalidKindMsgs = append(invalidKindMsgs, fmt.Sprintf("rule %d: %s", idx, msg))
+
+						continue
+					}
+
+					svc, port, err = resolve(ref.BackendRef, r.Source.Namespace, services)
+					if err != nil {
+						unresolvedMsgs = append(unresolvedMsgs, fmt.Sprintf("rule %d: %s", idx, err))
+					}
 				}
 
-				svc, port, err := getServiceAndPortFromRef(ref.BackendRef, r.Source.Namespace, services)
 				if err != nil {
 					group.Backends = append(group.Backends, BackendRef{Weight: weight})
 
 					group.Errors = append(group.Errors, err.Error())
 
+					var notFoundErr serviceNotFoundError
+					if errors.As(err, &notFoundErr) {
+						unresolvedMsgs = append(unresolvedMsgs, fmt.Sprintf("rule %d: %s", idx, err))
+					}
+
+					continue
+				}
+
+				if !serviceHasPort(svc, port) {
+					msg := fmt.Sprintf("the Service %s does not have the port %d", client.ObjectKeyFromObject(svc), port)
+
+					group.Backends = append(group.Backends, BackendRef{Weight: weight})
+					group.Errors = append(group.Errors, msg)
+					unresolvedMsgs = append(unresolvedMsgs, fmt.Sprintf("rule %d: %s", idx, msg))
+
+					continue
+				}
+
+				if !externalNameAllowed(svc, externalNameAllowlist) {
+					msg := fmt.Sprintf(
+						"the external name %s of the Service %s is not allowed",
+						svc.Spec.ExternalName,
+						client.ObjectKeyFromObject(svc),
+					)
+
+					group.Backends = append(group.Backends, BackendRef{Weight: weight})
+					group.Errors = append(group.Errors, msg)
+					notAllowedMsgs = append(notAllowedMsgs, fmt.Sprintf("rule %d: %s", idx, msg))
+
 					continue
 				}
 
 				group.Backends = append(group.Backends, BackendRef{
-					Name:   fmt.Sprintf("%s_%s_%d", svc.Namespace, svc.Name, port),
-					Svc:    svc,
-					Port:   port,
-					Valid:  true,
-					Weight: weight,
+					Name:                  getBackendName(svc, port),
+					Svc:                   svc,
+					Port:                  port,
+					Valid:                 true,
+					Weight:                weight,
+					RequestHeaderModifier: findRequestHeaderModifier(ref.Filters),
 				})
 			}
 
 			r.BackendGroups[idx] = group
 		}
+
+		cond := createResolvedRefsCondition(invalidKindMsgs, unresolvedMsgs, notAllowedMsgs, conflictingMsgs)
+		if cond != nil {
+			r.Conditions = append(r.Conditions, *cond)
+		}
+
+		if cond := createBackendWeightsCondition(r); cond != nil {
+			r.Conditions = append(r.Conditions, *cond)
+		}
 	}
 }
 
+// createResolvedRefsCondition returns a ResolvedRefs condition that reports all invalid backend refs of a route.
+// Because the status of a route only keeps the last condition of a type, the problems are reported with a single
+// condition. Its reason is the reason of the first kind of the problems in this priority: InvalidKind,
+// BackendNotFound, ExternalNameNotAllowed and ConflictingBackendFilters. Its message joins the messages of all
+// problems in the same order. It returns nil if there are no problems.
+func createResolvedRefsCondition(
+	invalidKindMsgs []string,
+	unresolvedMsgs []string,
+	notAllowedMsgs []string,
+	conflictingMsgs []string,
+) *conditions.Condition {
+	msgs := make([]string, 0, len(invalidKindMsgs)+len(unresolvedMsgs)+len(notAllowedMsgs)+len(conflictingMsgs))
+	msgs = append(msgs, invalidKindMsgs...)
+	msgs = append(msgs, unresolvedMsgs...)
+	msgs = append(msgs, notAllowedMsgs...)
+	msgs = append(msgs, conflictingMsgs...)
+
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	msg := strings.Join(msgs, "; ")
+
+	var cond conditions.Condition
+
+	switch {
+	case len(invalidKindMsgs) > 0:
+		cond = conditions.NewRouteBackendRefInvalidKind(msg)
+	case len(unresolvedMsgs) > 0:
+		cond = conditions.NewRouteBackendNotFound(msg)
+	case len(notAllowedMsgs) > 0:
+		cond = conditions.NewRouteExternalNameNotAllowed(msg)
+	default:
+		cond = conditions.NewRouteConflictingBackendFilters(msg)
+	}
+
+	return &cond
+}
+
+// serviceHasPort returns whether the Service has the port, so that its endpoints can be resolved for the port.
+// The ports of an ExternalName Service are informational, so such a Service has any port. So does a Service
+// without any ports.
+func serviceHasPort(svc *v1.Service, port int32) bool {
+	if svc.Spec.Type == v1.ServiceTypeExternalName || len(svc.Spec.Ports) == 0 {
+		return true
+	}
+
+	for _, p := range svc.Spec.Ports {
+		if p.Port == port {
+			return true
+		}
+	}
+
+	return false
+}
+
+// externalNameAllowed returns whether the Service can be used as a backend according to the allowlist of
+// the external names. The Services of types other than ExternalName are always allowed. An entry of the allowlist
+// is either a hostname, which matches the same external name, or a wildcard hostname like *.example.com,
+// which matches the external names in its subdomains, like foo.example.com and foo.bar.example.com.
+// An empty allowlist allows all external names.
+func externalNameAllowed(svc *v1.Service, allowlist []string) bool {
+	if svc.Spec.Type != v1.ServiceTypeExternalName || len(allowlist) == 0 {
+		return true
+	}
+
+	name := strings.ToLower(strings.TrimSuffix(svc.Spec.ExternalName, "."))
+
+	for _, entry := range allowlist {
+		entry = strings.ToLower(entry)
+
+		if strings.HasPrefix(entry, "*.") {
+			if strings.HasSuffix(name, entry[1:]) {
+				return true
+			}
+			continue
+		}
+
+		if name == entry {
+			return true
+		}
+	}
+
+	return false
+}
+
+// createBackendWeightsCondition returns a condition that reports the percentages of the traffic that NGINX sends
+// to the backendRefs of the rules of the route with multiple backendRefs, and the rules whose backendRefs all have
+// zero weight. NGINX responds with 500 to the requests of such rules.
+// It returns nil if the route has no such rules.
+func createBackendWeightsCondition(r *Route) *conditions.Condition {
+	var (
+		details  []string
+		zeroRule bool
+	)
+
+	for _, group := range r.BackendGroups {
+		if len(group.Backends) == 0 {
+			continue
+		}
+
+		percentages := group.Percentages()
+		if percentages == nil {
+			zeroRule = true
+			details = append(details, fmt.Sprintf("rule %d: all backendRefs have zero weight", group.RuleIdx))
+
+			continue
+		}
+
+		if len(group.Backends) == 1 {
+			continue
+		}
+
+		refs, _ := mergeDuplicateBackendRefs(r.Source.Spec.Rules[group.RuleIdx].BackendRefs, r.Source.Namespace)
+		backends := make([]string, 0, len(refs))
+
+		for i, ref := range refs {
+			backends = append(backends, fmt.Sprintf("%s %.2f%%", getBackendRefName(ref.BackendRef), percentages[i]))
+		}
+
+		details = append(details, fmt.Sprintf("rule %d: %s", group.RuleIdx, strings.Join(backends, ", ")))
+	}
+
+	if len(details) == 0 {
+		return nil
+	}
+
+	msg := strings.Join(details, "; ")
+
+	if zeroRule {
+		cond := conditions.NewRouteAllBackendWeightsZero(msg)
+		return &cond
+	}
+
+	cond := conditions.NewRouteBackendWeightsNormalized(msg)
+	return &cond
+}
+
+// backendRefKey identifies the backend that a backendRef references.
+type backendRefKey struct {
+	kind      BackendKind
+	namespace string
+	name      string
+	port      int32
+}
+
+// mergeDuplicateBackendRefs merges the backendRefs of a rule that reference the same backend: the same group, kind,
+// namespace, name and port. The merged backendRef is in the position of the first of them and has the sum of their
+// weights, so that the backend gets the same share of the requests as if the duplicates were separate backends,
+// but is listed once in the split. The other fields are of the first of them.
+// It also returns the indexes of the merged backendRefs whose duplicates have different filters, because the requests
+// of the backend can't be modified by the filters of one of them only.
+// If there are no duplicates, it returns the backendRefs as is.
+func mergeDuplicateBackendRefs(
+	refs []v1beta1.HTTPBackendRef,
+	routeNamespace string,
+) ([]v1beta1.HTTPBackendRef, map[int]struct{}) {
+	if len(refs) < 2 {
+		return refs, nil
+	}
+
+	indexes := make(map[backendRefKey]int, len(refs))
+	merged := make([]v1beta1.HTTPBackendRef, 0, len(refs))
+
+	var conflicting map[int]struct{}
+
+	for _, ref := range refs {
+		key := backendRefKey{
+			kind:      getBackendKind(ref.BackendRef),
+			namespace: routeNamespace,
+			name:      string(ref.Name),
+		}
+		if ref.Namespace != nil {
+			key.namespace = string(*ref.Namespace)
+		}
+		if ref.Port != nil {
+			key.port = int32(*ref.Port)
+		}
+
+		idx, exists := indexes[key]
+		if !exists {
+			indexes[key] = len(merged)
+			merged = append(merged, ref)
+			continue
+		}
+
+		weight := getBackendRefWeight(merged[idx].BackendRef) + getBackendRefWeight(ref.BackendRef)
+		merged[idx].Weight = &weight
+
+		if !filtersEqual(merged[idx].Filters, ref.Filters) {
+			if conflicting == nil {
+				conflicting = make(map[int]struct{})
+			}
+			conflicting[idx] = struct{}{}
+		}
+	}
+
+	if len(merged) == len(refs) {
+		return refs, nil
+	}
+
+	return merged, conflicting
+}
+
+// filtersEqual returns true if the filters are the same. Nil and empty filters are the same.
+func filtersEqual(f1, f2 []v1beta1.HTTPRouteFilter) bool {
+	if len(f1) == 0 && len(f2) == 0 {
+		return true
+	}
+
+	return reflect.DeepEqual(f1, f2)
+}
+
+// getBackendRefWeight returns the weight of the backendRef. The weight defaults to 1.
+func getBackendRefWeight(ref v1beta1.BackendRef) int32 {
+	if ref.Weight == nil {
+		return 1
+	}
+
+	return *ref.Weight
+}
+
+// getBackendRefName returns the name of the backendRef with its port, if the port is set. For example, "svc:80".
+func getBackendRefName(ref v1beta1.BackendRef) string {
+	if ref.Port == nil {
+		return string(ref.Name)
+	}
+
+	return fmt.Sprintf("%s:%d", ref.Name, *ref.Port)
+}
+
+// getBackendName returns the name of the backend for the port of the Service.
+// The name is unique for each Service and port combination.
+func getBackendName(svc *v1.Service, port int32) string {
+	return svc.Namespace + "_" + svc.Name + "_" + strconv.FormatInt(int64(port), 10)
+}
+
+// findRequestHeaderModifier returns the RequestHeaderModifier of the first filter of that type of a backendRef.
+// It returns nil if there is no such filter.
+func findRequestHeaderModifier(filters []v1beta1.HTTPRouteFilter) *v1beta1.HTTPHeaderFilter {
+	for _, f := range filters {
+		if f.Type == v1beta1.HTTPRouteFilterRequestHeaderModifier && f.RequestHeaderModifier != nil {
+			return f.RequestHeaderModifier
+		}
+	}
+
+	return nil
+}
+
+// getBackendKind returns the kind of the backendRef. The Group and Kind default to the core Service.
+func getBackendKind(ref v1beta1.BackendRef) BackendKind {
+	kind := serviceBackendKind
+
+	if ref.Group != nil {
+		kind.Group = string(*ref.Group)
+	}
+
+	if ref.Kind != nil {
+		kind.Kind = string(*ref.Kind)
+	}
+
+	return kind
+}
+
+// serviceNotFoundError is the error of a backendRef that references a Service that doesn't exist.
+type serviceNotFoundError struct {
+	nsname types.NamespacedName
+}
+
+func (e serviceNotFoundError) Error() string {
+	return fmt.Sprintf("the Service %s does not exist", e.nsname)
+}
+
 func getServiceAndPortFromRef(
 	ref v1beta1.BackendRef,
 	routeNamespace string,
@@ -84,7 +489,7 @@ func getServiceAndPortFromRef(
 
 	svc, ok := services[svcNsName]
 	if !ok {
-		return nil, 0, fmt.Errorf("the Service %s does not exist", svcNsName)
+		return nil, 0, serviceNotFoundError{nsname: svcNsName}
 	}
 
 	// safe to dereference port here because we already validated that the port is not nil.
@@ -96,6 +501,11 @@ func validateBackendRef(ref v1beta1.BackendRef, routeNs string) error {
 		return fmt.Errorf("the Kind must be Service; got %s", *ref.Kind)
 	}
 
+	return validateBackendRefNamespaceAndPort(ref, routeNs)
+}
+
+// validateBackendRefNamespaceAndPort validates the fields of a backendRef that don't depend on its kind.
+func validateBackendRefNamespaceAndPort(ref v1beta1.BackendRef, routeNs string) error {
 	if ref.Namespace != nil && string(*ref.Namespace) != routeNs {
 		return fmt.Errorf(
 			"cross-namespace routing is not permitted; namespace %s does not match the HTTPRoute namespace %s",
@@ -1,9 +1,12 @@
 package graph
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -11,6 +14,7 @@ import (
 	"sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/conditions"
 )
 
 func getNormalRef() v1beta1.BackendRef {
@@ -309,6 +313,12 @@ func TestResolveBackendRefs(t *testing.T) {
 					},
 				},
 			},
+			Conditions: []conditions.Condition{
+				conditions.NewRouteBackendWeightsNormalized(
+					"rule 0: svc1:80 16.66%, svc1:81 83.34%; rule 1: svc2:80 16.66%, svc2:81 83.34%; " +
+						"rule 2: svc3:80 16.66%, svc3:81 83.34%",
+				),
+			},
 		},
 		{Namespace: "test", Name: "hr2"}: {
 			Source: hr2,
@@ -356,14 +366,19 @@ func TestResolveBackendRefs(t *testing.T) {
 					},
 				},
 			},
+			Conditions: []conditions.Condition{
+				conditions.NewRouteBackendWeightsNormalized(
+					"rule 0: svc1:80 16.66%, svc1:81 83.34%; rule 1: svc4:80 16.66%, svc4:81 83.34%",
+				),
+			},
 		},
 		{Namespace: "test", Name: "hr3"}: {
 			Source: hr3,
 			BackendGroups: []BackendGroup{
 				{
 					Errors: []string{
-						"the Kind must be Service; got NotService",
-						"the Kind must be Service; got NotService",
+						"the kind NotService of the backendRef not-svc is not supported",
+						"the kind NotService of the backendRef not-svc is not supported",
 					},
 					Source:  client.ObjectKeyFromObject(hr3),
 					RuleIdx: 0,
@@ -377,6 +392,15 @@ func TestResolveBackendRefs(t *testing.T) {
 					},
 				},
 			},
+			Conditions: []conditions.Condition{
+				conditions.NewRouteBackendRefInvalidKind(
+					"rule 0: the kind NotService of the backendRef not-svc is not supported; " +
+						"rule 0: the kind NotService of the backendRef not-svc is not supported",
+				),
+				conditions.NewRouteBackendWeightsNormalized(
+					"rule 0: not-svc:80 16.66%, not-svc:81 83.34%",
+				),
+			},
 		},
 		{Namespace: "test", Name: "hr4"}: {
 			Source: hr4,
@@ -389,9 +413,971 @@ func TestResolveBackendRefs(t *testing.T) {
 		},
 	}
 
-	addBackendGroupsToRoutes(routes, services)
+	addBackendGroupsToRoutes(routes, services, nil, nil)
 
 	if diff := cmp.Diff(expRoutes, routes); diff != "" {
 		t.Errorf("resolveBackendRefs() mismatch on routes (-want +got):\n%s", diff)
 	}
 }
+
+func TestCreateBackendWeightsCondition(t *testing.T) {
+	createBackendRef := func(name string, weight *int32) v1beta1.HTTPBackendRef {
+		return v1beta1.HTTPBackendRef{
+			BackendRef: v1beta1.BackendRef{
+				BackendObjectReference: v1beta1.BackendObjectReference{
+					Name: v1beta1.ObjectName(name),
+					Port: (*v1beta1.PortNumber)(helpers.GetInt32Pointer(80)),
+				},
+				Weight: weight,
+			},
+		}
+	}
+
+	createRoute := func(rules ...[]v1beta1.HTTPBackendRef) *Route {
+		hr := &v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test",
+				Name:      "hr",
+			},
+		}
+
+		r := &Route{Source: hr}
+
+		for idx, refs := range rules {
+			hr.Spec.Rules = append(hr.Spec.Rules, v1beta1.HTTPRouteRule{BackendRefs: refs})
+
+			group := BackendGroup{
+				Source:  client.ObjectKeyFromObject(hr),
+				RuleIdx: idx,
+			}
+			for _, ref := range refs {
+				weight := int32(1)
+				if ref.Weight != nil {
+					weight = *ref.Weight
+				}
+				group.Backends = append(group.Backends, BackendRef{Weight: weight})
+			}
+
+			r.BackendGroups = append(r.BackendGroups, group)
+		}
+
+		return r
+	}
+
+	getConditionPointer := func(cond conditions.Condition) *conditions.Condition {
+		return &cond
+	}
+
+	tests := []struct {
+		route    *Route
+		expected *conditions.Condition
+		msg      string
+	}{
+		{
+			route:    createRoute(nil),
+			expected: nil,
+			msg:      "no backendRefs",
+		},
+		{
+			route:    createRoute([]v1beta1.HTTPBackendRef{createBackendRef("svc", nil)}),
+			expected: nil,
+			msg:      "one backendRef",
+		},
+		{
+			route: createRoute(
+				[]v1beta1.HTTPBackendRef{
+					createBackendRef("stable", helpers.GetInt32Pointer(90)),
+					createBackendRef("canary", helpers.GetInt32Pointer(10)),
+				},
+				[]v1beta1.HTTPBackendRef{
+					createBackendRef("a", nil),
+					createBackendRef("b", nil),
+					createBackendRef("c", helpers.GetInt32Pointer(0)),
+				},
+			),
+			expected: getConditionPointer(conditions.NewRouteBackendWeightsNormalized(
+				"rule 0: stable:80 90.00%, canary:80 10.00%; rule 1: a:80 50.00%, b:80 50.00%, c:80 0.00%",
+			)),
+			msg: "multiple backendRefs",
+		},
+		{
+			route: createRoute(
+				[]v1beta1.HTTPBackendRef{
+					createBackendRef("stable", helpers.GetInt32Pointer(1)),
+					createBackendRef("canary", helpers.GetInt32Pointer(3)),
+				},
+				[]v1beta1.HTTPBackendRef{
+					createBackendRef("a", helpers.GetInt32Pointer(0)),
+					createBackendRef("b", helpers.GetInt32Pointer(0)),
+				},
+				[]v1beta1.HTTPBackendRef{createBackendRef("svc", helpers.GetInt32Pointer(0))},
+			),
+			expected: getConditionPointer(conditions.NewRouteAllBackendWeightsZero(
+				"rule 0: stable:80 25.00%, canary:80 75.00%; rule 1: all backendRefs have zero weight; " +
+					"rule 2: all backendRefs have zero weight",
+			)),
+			msg: "all weights are zero",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			result := createBackendWeightsCondition(test.route)
+			if diff := cmp.Diff(test.expected, result); diff != "" {
+				t.Errorf("createBackendWeightsCondition() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAddBackendGroupsToRoutesExternalNameAllowlist(t *testing.T) {
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "hr"},
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					BackendRefs: []v1beta1.HTTPBackendRef{
+						{
+							BackendRef: v1beta1.BackendRef{
+								BackendObjectReference: v1beta1.BackendObjectReference{
+									Name: "allowed",
+									Port: (*v1beta1.PortNumber)(helpers.GetInt32Pointer(443)),
+								},
+							},
+						},
+					},
+				},
+				{
+					BackendRefs: []v1beta1.HTTPBackendRef{
+						{
+							BackendRef: v1beta1.BackendRef{
+								BackendObjectReference: v1beta1.BackendObjectReference{
+									Name: "blocked",
+									Port: (*v1beta1.PortNumber)(helpers.GetInt32Pointer(443)),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	createExternalNameService := func(name, externalName string) *v1.Service {
+		return &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: name},
+			Spec: v1.ServiceSpec{
+				Type:         v1.ServiceTypeExternalName,
+				ExternalName: externalName,
+			},
+		}
+	}
+
+	allowedSvc := createExternalNameService("allowed", "api.example.com")
+	blockedSvc := createExternalNameService("blocked", "169.254.169.254.nip.io")
+
+	services := map[types.NamespacedName]*v1.Service{
+		{Namespace: "test", Name: "allowed"}: allowedSvc,
+		{Namespace: "test", Name: "blocked"}: blockedSvc,
+	}
+
+	allowedGroup := BackendGroup{
+		Source:  client.ObjectKeyFromObject(hr),
+		RuleIdx: 0,
+		Errors:  []string{},
+		Backends: []BackendRef{
+			{
+				Name:   "test_allowed_443",
+				Svc:    allowedSvc,
+				Port:   443,
+				Valid:  true,
+				Weight: 1,
+			},
+		},
+	}
+
+	tests := []struct {
+		expRoute  *Route
+		msg       string
+		allowlist []string
+	}{
+		{
+			allowlist: nil,
+			expRoute: &Route{
+				Source: hr,
+				BackendGroups: []BackendGroup{
+					allowedGroup,
+					{
+						Source:  client.ObjectKeyFromObject(hr),
+						RuleIdx: 1,
+						Errors:  []string{},
+						Backends: []BackendRef{
+							{
+								Name:   "test_blocked_443",
+								Svc:    blockedSvc,
+								Port:   443,
+								Valid:  true,
+								Weight: 1,
+							},
+						},
+					},
+				},
+			},
+			msg: "empty allowlist allows all external names",
+		},
+		{
+			allowlist: []string{"*.example.com"},
+			expRoute: &Route{
+				Source: hr,
+				BackendGroups: []BackendGroup{
+					allowedGroup,
+					{
+						Source:  client.ObjectKeyFromObject(hr),
+						RuleIdx: 1,
+						Errors: []string{
+							"the external name 169.254.169.254.nip.io of the Service test/blocked is not allowed",
+						},
+						Backends: []BackendRef{{Weight: 1}},
+					},
+				},
+				Conditions: []conditions.Condition{
+					conditions.NewRouteExternalNameNotAllowed(
+						"rule 1: the external name 169.254.169.254.nip.io of the Service test/blocked is not allowed",
+					),
+				},
+			},
+			msg: "allowlist blocks the external names that don't match",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			routes := map[types.NamespacedName]*Route{
+				{Namespace: "test", Name: "hr"}: {Source: hr},
+			}
+
+			addBackendGroupsToRoutes(routes, services, test.allowlist, nil)
+
+			if diff := cmp.Diff(test.expRoute, routes[types.NamespacedName{Namespace: "test", Name: "hr"}]); diff != "" {
+				t.Errorf("addBackendGroupsToRoutes() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExternalNameAllowed(t *testing.T) {
+	createService := func(svcType v1.ServiceType, externalName string) *v1.Service {
+		return &v1.Service{
+			Spec: v1.ServiceSpec{
+				Type:         svcType,
+				ExternalName: externalName,
+			},
+		}
+	}
+
+	allowlist := []string{"api.example.com", "*.internal.example.org"}
+
+	tests := []struct {
+		svc       *v1.Service
+		msg       string
+		allowlist []string
+		expected  bool
+	}{
+		{
+			svc:       createService(v1.ServiceTypeExternalName, "evil.example.net"),
+			allowlist: nil,
+			expected:  true,
+			msg:       "empty allowlist",
+		},
+		{
+			svc:       createService(v1.ServiceTypeClusterIP, ""),
+			allowlist: allowlist,
+			expected:  true,
+			msg:       "not an ExternalName Service",
+		},
+		{
+			svc:       createService(v1.ServiceTypeExternalName, "api.example.com"),
+			allowlist: allowlist,
+			expected:  true,
+			msg:       "exact match",
+		},
+		{
+			svc:       createService(v1.ServiceTypeExternalName, "API.Example.com."),
+			allowlist: allowlist,
+			expected:  true,
+			msg:       "exact match with a different case and a trailing dot",
+		},
+		{
+			svc:       createService(v1.ServiceTypeExternalName, "db.eu.internal.example.org"),
+			allowlist: allowlist,
+			expected:  true,
+			msg:       "wildcard match",
+		},
+		{
+			svc:       createService(v1.ServiceTypeExternalName, "internal.example.org"),
+			allowlist: allowlist,
+			expected:  false,
+			msg:       "wildcard doesn't match the parent domain",
+		},
+		{
+			svc:       createService(v1.ServiceTypeExternalName, "www.api.example.com"),
+			allowlist: allowlist,
+			expected:  false,
+			msg:       "hostname doesn't match subdomains",
+		},
+		{
+			svc:       createService(v1.ServiceTypeExternalName, "evilinternal.example.org"),
+			allowlist: allowlist,
+			expected:  false,
+			msg:       "wildcard doesn't match a suffix that isn't a subdomain",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			if result := externalNameAllowed(test.svc, test.allowlist); result != test.expected {
+				t.Errorf("externalNameAllowed() returned %v but expected %v", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestAddBackendGroupsToRoutesBackendResolvers(t *testing.T) {
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "hr"},
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					BackendRefs: []v1beta1.HTTPBackendRef{
+						{
+							BackendRef: v1beta1.BackendRef{
+								BackendObjectReference: v1beta1.BackendObjectReference{
+									Group: (*v1beta1.Group)(helpers.GetStringPointer("multicluster.x-k8s.io")),
+									Kind:  (*v1beta1.Kind)(helpers.GetStringPointer("ServiceImport")),
+									Name:  "coffee",
+									Port:  (*v1beta1.PortNumber)(helpers.GetInt32Pointer(80)),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	derivedSvc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "derived-coffee"},
+	}
+
+	services := map[types.NamespacedName]*v1.Service{
+		{Namespace: "test", Name: "derived-coffee"}: derivedSvc,
+	}
+
+	serviceImportKind := BackendKind{Group: "multicluster.x-k8s.io", Kind: "ServiceImport"}
+
+	resolveServiceImport := func(
+		ref v1beta1.BackendRef,
+		routeNamespace string,
+		services map[types.NamespacedName]*v1.Service,
+	) (*v1.Service, int32, error) {
+		svcNsName := types.NamespacedName{Namespace: routeNamespace, Name: "derived-" + string(ref.Name)}
+
+		svc, exists := services[svcNsName]
+		if !exists {
+			return nil, 0, fmt.Errorf("the derived Service %s does not exist", svcNsName)
+		}
+
+		return svc, int32(*ref.Port), nil
+	}
+
+	failingResolver := func(v1beta1.BackendRef, string, map[types.NamespacedName]*v1.Service) (*v1.Service, int32, error) {
+		return nil, 0, errors.New("the ServiceImport test/coffee does not exist")
+	}
+
+	tests := []struct {
+		resolvers BackendResolvers
+		expRoute  *Route
+		msg       string
+	}{
+		{
+			resolvers: nil,
+			expRoute: &Route{
+				Source: hr,
+				BackendGroups: []BackendGroup{
+					{
+						Source:  client.ObjectKeyFromObject(hr),
+						RuleIdx: 0,
+						Errors: []string{
+							"the kind ServiceImport.multicluster.x-k8s.io of the backendRef coffee is not supported",
+						},
+						Backends: []BackendRef{{Weight: 1}},
+					},
+				},
+				Conditions: []conditions.Condition{
+					conditions.NewRouteBackendRefInvalidKind(
+						"rule 0: the kind ServiceImport.multicluster.x-k8s.io of the backendRef coffee is not supported",
+					),
+				},
+			},
+			msg: "no resolver for the kind",
+		},
+		{
+			resolvers: BackendResolvers{serviceImportKind: resolveServiceImport},
+			expRoute: &Route{
+				Source: hr,
+				BackendGroups: []BackendGroup{
+					{
+						Source:  client.ObjectKeyFromObject(hr),
+						RuleIdx: 0,
+						Errors:  []string{},
+						Backends: []BackendRef{
+							{
+								Name:   "test_derived-coffee_80",
+								Svc:    derivedSvc,
+								Port:   80,
+								Valid:  true,
+								Weight: 1,
+							},
+						},
+					},
+				},
+			},
+			msg: "resolver resolves the kind",
+		},
+		{
+			resolvers: BackendResolvers{serviceImportKind: failingResolver},
+			expRoute: &Route{
+				Source: hr,
+				BackendGroups: []BackendGroup{
+					{
+						Source:   client.ObjectKeyFromObject(hr),
+						RuleIdx:  0,
+						Errors:   []string{"the ServiceImport test/coffee does not exist"},
+						Backends: []BackendRef{{Weight: 1}},
+					},
+				},
+				Conditions: []conditions.Condition{
+					conditions.NewRouteBackendNotFound("rule 0: the ServiceImport test/coffee does not exist"),
+				},
+			},
+			msg: "resolver fails",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			routes := map[types.NamespacedName]*Route{
+				{Namespace: "test", Name: "hr"}: {Source: hr},
+			}
+
+			addBackendGroupsToRoutes(routes, services, nil, test.resolvers)
+
+			if diff := cmp.Diff(test.expRoute, routes[types.NamespacedName{Namespace: "test", Name: "hr"}]); diff != "" {
+				t.Errorf("addBackendGroupsToRoutes() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGetBackendKind(t *testing.T) {
+	tests := []struct {
+		group    *v1beta1.Group
+		kind     *v1beta1.Kind
+		msg      string
+		expected string
+	}{
+		{
+			msg:      "defaults",
+			expected: "Service",
+		},
+		{
+			group:    (*v1beta1.Group)(helpers.GetStringPointer("")),
+			kind:     (*v1beta1.Kind)(helpers.GetStringPointer("Service")),
+			msg:      "core Service",
+			expected: "Service",
+		},
+		{
+			group:    (*v1beta1.Group)(helpers.GetStringPointer("multicluster.x-k8s.io")),
+			kind:     (*v1beta1.Kind)(helpers.GetStringPointer("ServiceImport")),
+			msg:      "custom kind",
+			expected: "ServiceImport.multicluster.x-k8s.io",
+		},
+	}
+
+	for _, test := range tests {
+		ref := v1beta1.BackendRef{
+			BackendObjectReference: v1beta1.BackendObjectReference{
+				Group: test.group,
+				Kind:  test.kind,
+			},
+		}
+
+		result := getBackendKind(ref).String()
+		if result != test.expected {
+			t.Errorf("getBackendKind() returned %q but expected %q for case %q", result, test.expected, test.msg)
+		}
+	}
+}
+
+func TestFindRequestHeaderModifier(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	first := &v1beta1.HTTPHeaderFilter{Set: []v1beta1.HTTPHeader{{Name: "X-Version", Value: "v1"}}}
+	second := &v1beta1.HTTPHeaderFilter{Set: []v1beta1.HTTPHeader{{Name: "X-Version", Value: "v2"}}}
+
+	filters := []v1beta1.HTTPRouteFilter{
+		{
+			Type:                   v1beta1.HTTPRouteFilterResponseHeaderModifier,
+			ResponseHeaderModifier: &v1beta1.HTTPHeaderFilter{},
+		},
+		{
+			Type:                  v1beta1.HTTPRouteFilterRequestHeaderModifier,
+			RequestHeaderModifier: first,
+		},
+		{
+			Type:                  v1beta1.HTTPRouteFilterRequestHeaderModifier,
+			RequestHeaderModifier: second,
+		},
+	}
+
+	g.Expect(findRequestHeaderModifier(filters)).To(Equal(first))
+	g.Expect(findRequestHeaderModifier(filters[:1])).To(BeNil())
+	g.Expect(findRequestHeaderModifier(nil)).To(BeNil())
+}
+
+func TestMergeDuplicateBackendRefs(t *testing.T) {
+	createBackendRef := func(
+		namespace *string,
+		name string,
+		port int32,
+		weight *int32,
+		filters ...v1beta1.HTTPRouteFilter,
+	) v1beta1.HTTPBackendRef {
+		return v1beta1.HTTPBackendRef{
+			BackendRef: v1beta1.BackendRef{
+				BackendObjectReference: v1beta1.BackendObjectReference{
+					Namespace: (*v1beta1.Namespace)(namespace),
+					Name:      v1beta1.ObjectName(name),
+					Port:      (*v1beta1.PortNumber)(helpers.GetInt32Pointer(port)),
+				},
+				Weight: weight,
+			},
+			Filters: filters,
+		}
+	}
+
+	filter := v1beta1.HTTPRouteFilter{
+		Type: v1beta1.HTTPRouteFilterRequestHeaderModifier,
+		RequestHeaderModifier: &v1beta1.HTTPHeaderFilter{
+			Set: []v1beta1.HTTPHeader{{Name: "X-Version", Value: "v1"}},
+		},
+	}
+
+	otherFilter := v1beta1.HTTPRouteFilter{
+		Type: v1beta1.HTTPRouteFilterRequestHeaderModifier,
+		RequestHeaderModifier: &v1beta1.HTTPHeaderFilter{
+			Set: []v1beta1.HTTPHeader{{Name: "X-Version", Value: "v2"}},
+		},
+	}
+
+	tests := []struct {
+		refs           []v1beta1.HTTPBackendRef
+		expected       []v1beta1.HTTPBackendRef
+		expConflicting map[int]struct{}
+		msg            string
+	}{
+		{
+			refs:     nil,
+			expected: nil,
+			msg:      "no backendRefs",
+		},
+		{
+			refs: []v1beta1.HTTPBackendRef{
+				createBackendRef(nil, "svc", 80, nil),
+				createBackendRef(nil, "svc", 8080, nil),
+				createBackendRef(helpers.GetStringPointer("other"), "svc", 80, nil),
+				createBackendRef(nil, "other", 80, nil),
+			},
+			expected: []v1beta1.HTTPBackendRef{
+				createBackendRef(nil, "svc", 80, nil),
+				createBackendRef(nil, "svc", 8080, nil),
+				createBackendRef(helpers.GetStringPointer("other"), "svc", 80, nil),
+				createBackendRef(nil, "other", 80, nil),
+			},
+			msg: "no duplicates",
+		},
+		{
+			refs: []v1beta1.HTTPBackendRef{
+				createBackendRef(nil, "svc", 80, helpers.GetInt32Pointer(3), filter),
+				createBackendRef(nil, "other", 80, helpers.GetInt32Pointer(4)),
+				createBackendRef(helpers.GetStringPointer("test"), "svc", 80, nil, filter),
+				createBackendRef(nil, "svc", 80, helpers.GetInt32Pointer(0), filter),
+			},
+			expected: []v1beta1.HTTPBackendRef{
+				createBackendRef(nil, "svc", 80, helpers.GetInt32Pointer(4), filter),
+				createBackendRef(nil, "other", 80, helpers.GetInt32Pointer(4)),
+			},
+			msg: "duplicates are merged into the first of them",
+		},
+		{
+			refs: []v1beta1.HTTPBackendRef{
+				createBackendRef(nil, "other", 80, nil),
+				createBackendRef(nil, "svc", 80, nil, filter),
+				createBackendRef(nil, "svc", 80, nil, otherFilter),
+				createBackendRef(nil, "other", 80, nil),
+				createBackendRef(nil, "svc", 80, nil),
+			},
+			expected: []v1beta1.HTTPBackendRef{
+				createBackendRef(nil, "other", 80, helpers.GetInt32Pointer(2)),
+				createBackendRef(nil, "svc", 80, helpers.GetInt32Pointer(3), filter),
+			},
+			expConflicting: map[int]struct{}{1: {}},
+			msg:            "duplicates with different filters",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			result, conflicting := mergeDuplicateBackendRefs(test.refs, "test")
+			if diff := cmp.Diff(test.expected, result); diff != "" {
+				t.Errorf("mergeDuplicateBackendRefs() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.expConflicting, conflicting); diff != "" {
+				t.Errorf("mergeDuplicateBackendRefs() conflicting mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAddBackendGroupsToRoutesDuplicateBackendRefs(t *testing.T) {
+	createBackendRef := func(name string, weight *int32) v1beta1.HTTPBackendRef {
+		return v1beta1.HTTPBackendRef{
+			BackendRef: v1beta1.BackendRef{
+				BackendObjectReference: v1beta1.BackendObjectReference{
+					Name: v1beta1.ObjectName(name),
+					Port: (*v1beta1.PortNumber)(helpers.GetInt32Pointer(80)),
+				},
+				Weight: weight,
+			},
+		}
+	}
+
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "hr"},
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					BackendRefs: []v1beta1.HTTPBackendRef{
+						createBackendRef("stable", nil),
+						createBackendRef("canary", nil),
+						createBackendRef("stable", nil),
+					},
+				},
+			},
+		},
+	}
+
+	stableSvc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "stable"}}
+	canarySvc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "canary"}}
+
+	services := map[types.NamespacedName]*v1.Service{
+		{Namespace: "test", Name: "stable"}: stableSvc,
+		{Namespace: "test", Name: "canary"}: canarySvc,
+	}
+
+	expRoute := &Route{
+		Source: hr,
+		BackendGroups: []BackendGroup{
+			{
+				Source:  client.ObjectKeyFromObject(hr),
+				RuleIdx: 0,
+				Errors:  []string{},
+				Backends: []BackendRef{
+					{
+						Name:   "test_stable_80",
+						Svc:    stableSvc,
+						Port:   80,
+						Valid:  true,
+						Weight: 2,
+					},
+					{
+						Name:   "test_canary_80",
+						Svc:    canarySvc,
+						Port:   80,
+						Valid:  true,
+						Weight: 1,
+					},
+				},
+			},
+		},
+		Conditions: []conditions.Condition{
+			conditions.NewRouteBackendWeightsNormalized("rule 0: stable:80 66.66%, canary:80 33.34%"),
+		},
+	}
+
+	routes := map[types.NamespacedName]*Route{
+		{Namespace: "test", Name: "hr"}: {Source: hr},
+	}
+
+	addBackendGroupsToRoutes(routes, services, nil, nil)
+
+	if diff := cmp.Diff(expRoute, routes[types.NamespacedName{Namespace: "test", Name: "hr"}]); diff != "" {
+		t.Errorf("addBackendGroupsToRoutes() mismatch (-want +got):\n%s", diff)
+	}
+
+	// duplicates with different filters
+
+	hr.Spec.Rules[0].BackendRefs[2].Filters = []v1beta1.HTTPRouteFilter{
+		{
+			Type: v1beta1.HTTPRouteFilterRequestHeaderModifier,
+			RequestHeaderModifier: &v1beta1.HTTPHeaderFilter{
+				Set: []v1beta1.HTTPHeader{{Name: "X-Version", Value: "v2"}},
+			},
+		},
+	}
+
+	msg := "the backendRefs stable:80 reference the same backend with different filters"
+
+	expRoute.BackendGroups[0].Errors = []string{msg}
+	expRoute.BackendGroups[0].Backends[0] = BackendRef{Weight: 2}
+	expRoute.Conditions = []conditions.Condition{
+		conditions.NewRouteConflictingBackendFilters("rule 0: " + msg),
+		conditions.NewRouteBackendWeightsNormalized("rule 0: stable:80 66.66%, canary:80 33.34%"),
+	}
+
+	routes = map[types.NamespacedName]*Route{
+		{Namespace: "test", Name: "hr"}: {Source: hr},
+	}
+
+	addBackendGroupsToRoutes(routes, services, nil, nil)
+
+	if diff := cmp.Diff(expRoute, routes[types.NamespacedName{Namespace: "test", Name: "hr"}]); diff != "" {
+		t.Errorf("addBackendGroupsToRoutes() with different filters mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestAddBackendGroupsToRoutesMultipleInvalidBackendRefs(t *testing.T) {
+	createBackendRef := func(name string, filters ...v1beta1.HTTPRouteFilter) v1beta1.HTTPBackendRef {
+		return v1beta1.HTTPBackendRef{
+			BackendRef: v1beta1.BackendRef{
+				BackendObjectReference: v1beta1.BackendObjectReference{
+					Name: v1beta1.ObjectName(name),
+					Port: (*v1beta1.PortNumber)(helpers.GetInt32Pointer(80)),
+				},
+			},
+			Filters: filters,
+		}
+	}
+
+	filter := v1beta1.HTTPRouteFilter{
+		Type: v1beta1.HTTPRouteFilterRequestHeaderModifier,
+		RequestHeaderModifier: &v1beta1.HTTPHeaderFilter{
+			Set: []v1beta1.HTTPHeader{{Name: "X-Version", Value: "v1"}},
+		},
+	}
+
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "hr"},
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					BackendRefs: []v1beta1.HTTPBackendRef{
+						createBackendRef("svc", filter),
+						createBackendRef("svc"),
+					},
+				},
+				{
+					BackendRefs: []v1beta1.HTTPBackendRef{
+						createBackendRef("blocked"),
+					},
+				},
+			},
+		},
+	}
+
+	services := map[types.NamespacedName]*v1.Service{
+		{Namespace: "test", Name: "svc"}: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "svc"},
+		},
+		{Namespace: "test", Name: "blocked"}: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "blocked"},
+			Spec: v1.ServiceSpec{
+				Type:         v1.ServiceTypeExternalName,
+				ExternalName: "169.254.169.254.nip.io",
+			},
+		},
+	}
+
+	routes := map[types.NamespacedName]*Route{
+		{Namespace: "test", Name: "hr"}: {Source: hr},
+	}
+
+	addBackendGroupsToRoutes(routes, services, []string{"*.example.com"}, nil)
+
+	expConditions := []conditions.Condition{
+		conditions.NewRouteExternalNameNotAllowed(
+			"rule 1: the external name 169.254.169.254.nip.io of the Service test/blocked is not allowed; " +
+				"rule 0: the backendRefs svc:80 reference the same backend with different filters",
+		),
+	}
+
+	route := routes[types.NamespacedName{Namespace: "test", Name: "hr"}]
+	if diff := cmp.Diff(expConditions, route.Conditions); diff != "" {
+		t.Errorf("addBackendGroupsToRoutes() conditions mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCreateResolvedRefsCondition(t *testing.T) {
+	invalidKind := []string{"rule 0: invalid kind"}
+	unresolved := []string{"rule 1: not found"}
+	notAllowed := []string{"rule 2: not allowed"}
+	conflicting := []string{"rule 3: conflicting filters"}
+
+	getConditionPointer := func(cond conditions.Condition) *conditions.Condition {
+		return &cond
+	}
+
+	tests := []struct {
+		expected    *conditions.Condition
+		invalidKind []string
+		unresolved  []string
+		notAllowed  []string
+		conflicting []string
+		msg         string
+	}{
+		{
+			expected: nil,
+			msg:      "no problems",
+		},
+		{
+			invalidKind: invalidKind,
+			unresolved:  unresolved,
+			notAllowed:  notAllowed,
+			conflicting: conflicting,
+			expected: getConditionPointer(conditions.NewRouteBackendRefInvalidKind(
+				"rule 0: invalid kind; rule 1: not found; rule 2: not allowed; rule 3: conflicting filters",
+			)),
+			msg: "all problems",
+		},
+		{
+			unresolved:  unresolved,
+			conflicting: conflicting,
+			expected: getConditionPointer(conditions.NewRouteBackendNotFound(
+				"rule 1: not found; rule 3: conflicting filters",
+			)),
+			msg: "unresolved and conflicting",
+		},
+		{
+			conflicting: conflicting,
+			expected:    getConditionPointer(conditions.NewRouteConflictingBackendFilters("rule 3: conflicting filters")),
+			msg:         "conflicting only",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			result := createResolvedRefsCondition(test.invalidKind, test.unresolved, test.notAllowed, test.conflicting)
+			if diff := cmp.Diff(test.expected, result); diff != "" {
+				t.Errorf("createResolvedRefsCondition() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAddBackendGroupsToRoutesServicePorts(t *testing.T) {
+	hr := &v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "hr"},
+		Spec: v1beta1.HTTPRouteSpec{
+			Rules: []v1beta1.HTTPRouteRule{
+				{
+					BackendRefs: []v1beta1.HTTPBackendRef{
+						{
+							BackendRef: v1beta1.BackendRef{
+								BackendObjectReference: v1beta1.BackendObjectReference{
+									Name: "svc",
+									Port: (*v1beta1.PortNumber)(helpers.GetInt32Pointer(80)),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	createService := func(svcType v1.ServiceType, ports ...int32) *v1.Service {
+		svc := &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "svc"},
+			Spec:       v1.ServiceSpec{Type: svcType},
+		}
+
+		for _, p := range ports {
+			svc.Spec.Ports = append(svc.Spec.Ports, v1.ServicePort{Port: p})
+		}
+
+		return svc
+	}
+
+	tests := []struct {
+		svc      *v1.Service
+		msg      string
+		expError string
+		expValid bool
+	}{
+		{
+			svc:      createService(v1.ServiceTypeClusterIP, 80, 8080),
+			expValid: true,
+			msg:      "Service has the port",
+		},
+		{
+			svc:      createService(v1.ServiceTypeClusterIP, 8080),
+			expValid: false,
+			expError: "the Service test/svc does not have the port 80",
+			msg:      "Service doesn't have the port",
+		},
+		{
+			svc:      nil,
+			expValid: false,
+			expError: "the Service test/svc does not exist",
+			msg:      "Service doesn't exist",
+		},
+		{
+			svc:      createService(v1.ServiceTypeClusterIP),
+			expValid: true,
+			msg:      "Service without ports",
+		},
+		{
+			svc:      createService(v1.ServiceTypeExternalName, 443),
+			expValid: true,
+			msg:      "ExternalName Service",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			routes := map[types.NamespacedName]*Route{
+				{Namespace: "test", Name: "hr"}: {Source: hr},
+			}
+			services := map[types.NamespacedName]*v1.Service{}
+			if test.svc != nil {
+				services[types.NamespacedName{Namespace: "test", Name: "svc"}] = test.svc
+			}
+
+			addBackendGroupsToRoutes(routes, services, nil, nil)
+
+			r := routes[types.NamespacedName{Namespace: "test", Name: "hr"}]
+			g.Expect(r.BackendGroups[0].Backends).To(HaveLen(1))
+			g.Expect(r.BackendGroups[0].Backends[0].Valid).To(Equal(test.expValid))
+
+			if test.expValid {
+				g.Expect(r.Conditions).To(BeEmpty())
+				return
+			}
+
+			g.Expect(r.BackendGroups[0].Errors).To(ConsistOf(test.expError))
+			g.Expect(r.Conditions).To(ConsistOf(conditions.NewRouteBackendNotFound("rule 0: " + test.expError)))
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/conditions"
+)
+
+// DefaultBackendAnnotation is the Gateway annotation that configures the Service that NGINX proxies the requests
+// that match the hostname of a Listener but no routes to. The value is a comma-separated list of entries.
+// An entry of the form <service>:<port> configures the default backend of all Listeners.
+// An entry of the form <listener>=<service>:<port> configures the default backend of a Listener and takes
+// precedence over the entry for all Listeners.
+// The Services must be in the namespace of the Gateway.
+const DefaultBackendAnnotation = "k8s-gateway.nginx.org/default-backend"
+
+// DefaultBackendRef is a reference to a Service in the namespace of the Gateway.
+type DefaultBackendRef struct {
+	// Name is the name of the Service.
+	Name string
+	// Port is the port of the Service.
+	Port int32
+}
+
+// DefaultBackendRefs holds the default backends configured through the DefaultBackendAnnotation.
+type DefaultBackendRefs struct {
+	// All is the default backend of the Listeners without their own default backend. It is nil if not configured.
+	All *DefaultBackendRef
+	// Listeners maps the names of Listeners to their default backends.
+	Listeners map[string]DefaultBackendRef
+}
+
+// ParseDefaultBackendRefs parses the value of the DefaultBackendAnnotation.
+func ParseDefaultBackendRefs(value string) (DefaultBackendRefs, error) {
+	refs := DefaultBackendRefs{
+		Listeners: make(map[string]DefaultBackendRef),
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		listener, backend, forListener := strings.Cut(entry, "=")
+		if !forListener {
+			backend = listener
+		}
+
+		ref, err := parseDefaultBackendRef(strings.TrimSpace(backend))
+		if err != nil {
+			return DefaultBackendRefs{}, fmt.Errorf("invalid entry %q: %w", entry, err)
+		}
+
+		if !forListener {
+			if refs.All != nil {
+				return DefaultBackendRefs{}, fmt.Errorf(
+					"invalid entry %q: the default backend of all listeners is already configured",
+					entry,
+				)
+			}
+
+			refs.All = &ref
+
+			continue
+		}
+
+		listener = strings.TrimSpace(listener)
+		if _, exists := refs.Listeners[listener]; exists {
+			return DefaultBackendRefs{}, fmt.Errorf(
+				"invalid entry %q: the default backend of the listener %q is already configured",
+				entry,
+				listener,
+			)
+		}
+
+		refs.Listeners[listener] = ref
+	}
+
+	return refs, nil
+}
+
+func parseDefaultBackendRef(value string) (DefaultBackendRef, error) {
+	name, portValue, found := strings.Cut(value, ":")
+	if !found {
+		return DefaultBackendRef{}, fmt.Errorf("%q must be of the form <service>:<port>", value)
+	}
+
+	if msgs := validation.IsDNS1035Label(name); len(msgs) > 0 {
+		return DefaultBackendRef{}, fmt.Errorf("invalid service name %q: %s", name, strings.Join(msgs, ", "))
+	}
+
+	port, err := strconv.ParseInt(portValue, 10, 32)
+	if err != nil || validation.IsValidPortNum(int(port)) != nil {
+		return DefaultBackendRef{}, fmt.Errorf("invalid port %q: must be between 1 and 65535", portValue)
+	}
+
+	return DefaultBackendRef{Name: name, Port: int32(port)}, nil
+}
+
+// addDefaultBackendsToListeners resolves the default backends configured through the DefaultBackendAnnotation
+// of the Gateway and adds them to the valid Listeners. The Listeners are modified in place.
+// If the annotation is invalid, the Service of a default backend doesn't exist, or the external name of
+// an ExternalName Service doesn't match the externalNameAllowlist, the default backend of the Listener is invalid
+// and a condition is added to the Listener.
+func addDefaultBackendsToListeners(
+	gw *v1beta1.Gateway,
+	listeners map[string]*Listener,
+	services map[types.NamespacedName]*v1.Service,
+	externalNameAllowlist []string,
+) {
+	if gw == nil {
+		return
+	}
+
+	value, exists := gw.Annotations[DefaultBackendAnnotation]
+	if !exists {
+		return
+	}
+
+	refs, err := ParseDefaultBackendRefs(value)
+
+	for name, l := range listeners {
+		if !l.Valid {
+			continue
+		}
+
+		if err != nil {
+			l.DefaultBackend = &BackendRef{}
+			l.Conditions = append(l.Conditions, conditions.NewListenerInvalidDefaultBackend(
+				fmt.Sprintf("Invalid %s annotation: %v", DefaultBackendAnnotation, err),
+			))
+
+			continue
+		}
+
+		ref, exists := refs.Listeners[name]
+		if !exists {
+			if refs.All == nil {
+				continue
+			}
+
+			ref = *refs.All
+		}
+
+		svcNsName := types.NamespacedName{Namespace: gw.Namespace, Name: ref.Name}
+
+		svc, exists := services[svcNsName]
+		if !exists {
+			l.DefaultBackend = &BackendRef{}
+			l.Conditions = append(l.Conditions, conditions.NewListenerInvalidDefaultBackend(
+				fmt.Sprintf("Invalid default backend: the Service %s does not exist", svcNsName),
+			))
+
+			continue
+		}
+
+		if !externalNameAllowed(svc, externalNameAllowlist) {
+			l.DefaultBackend = &BackendRef{}
+			l.Conditions = append(l.Conditions, conditions.NewListenerInvalidDefaultBackend(
+				fmt.Sprintf(
+					"Invalid default backend: the external name %s of the Service %s is not allowed",
+					svc.Spec.ExternalName,
+					svcNsName,
+				),
+			))
+
+			continue
+		}
+
+		l.DefaultBackend = &BackendRef{
+			Name:   getBackendName(svc, ref.Port),
+			Svc:    svc,
+			Port:   ref.Port,
+			Valid:  true,
+			Weight: 1,
+		}
+	}
+}
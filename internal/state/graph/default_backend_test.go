@@ -0,0 +1,283 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/conditions"
+)
+
+func TestParseDefaultBackendRefs(t *testing.T) {
+	tests := []struct {
+		msg      string
+		value    string
+		expected DefaultBackendRefs
+		expErr   bool
+	}{
+		{
+			msg:   "empty",
+			value: "",
+			expected: DefaultBackendRefs{
+				Listeners: map[string]DefaultBackendRef{},
+			},
+		},
+		{
+			msg:   "all listeners",
+			value: "default:8080",
+			expected: DefaultBackendRefs{
+				All:       &DefaultBackendRef{Name: "default", Port: 8080},
+				Listeners: map[string]DefaultBackendRef{},
+			},
+		},
+		{
+			msg:   "all listeners and listener overrides",
+			value: " default:8080, https = secure:8443,http=plain:80 ,",
+			expected: DefaultBackendRefs{
+				All: &DefaultBackendRef{Name: "default", Port: 8080},
+				Listeners: map[string]DefaultBackendRef{
+					"https": {Name: "secure", Port: 8443},
+					"http":  {Name: "plain", Port: 80},
+				},
+			},
+		},
+		{
+			msg:    "missing port",
+			value:  "default",
+			expErr: true,
+		},
+		{
+			msg:    "invalid port",
+			value:  "default:http",
+			expErr: true,
+		},
+		{
+			msg:    "port out of range",
+			value:  "default:65536",
+			expErr: true,
+		},
+		{
+			msg:    "invalid service name",
+			value:  "Default:8080",
+			expErr: true,
+		},
+		{
+			msg:    "duplicate all listeners entries",
+			value:  "default:8080,other:8080",
+			expErr: true,
+		},
+		{
+			msg:    "duplicate listener entries",
+			value:  "http=default:8080,http=other:8080",
+			expErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			result, err := ParseDefaultBackendRefs(test.value)
+			if test.expErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(result).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestAddDefaultBackendsToListeners(t *testing.T) {
+	createGateway := func(annotation string) *v1beta1.Gateway {
+		return &v1beta1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "test",
+				Name:        "gateway",
+				Annotations: map[string]string{DefaultBackendAnnotation: annotation},
+			},
+		}
+	}
+
+	createListeners := func() map[string]*Listener {
+		return map[string]*Listener{
+			"http":    {Valid: true},
+			"https":   {Valid: true},
+			"invalid": {Valid: false},
+		}
+	}
+
+	defaultSvc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "default"}}
+	secureSvc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "secure"}}
+	allowedSvc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "allowed"},
+		Spec: v1.ServiceSpec{
+			Type:         v1.ServiceTypeExternalName,
+			ExternalName: "api.example.com",
+		},
+	}
+	blockedSvc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "blocked"},
+		Spec: v1.ServiceSpec{
+			Type:         v1.ServiceTypeExternalName,
+			ExternalName: "169.254.169.254.nip.io",
+		},
+	}
+
+	services := map[types.NamespacedName]*v1.Service{
+		{Namespace: "test", Name: "default"}: defaultSvc,
+		{Namespace: "test", Name: "secure"}:  secureSvc,
+		{Namespace: "test", Name: "allowed"}: allowedSvc,
+		{Namespace: "test", Name: "blocked"}: blockedSvc,
+	}
+
+	externalNameAllowlist := []string{"*.example.com"}
+
+	defaultBackend := &BackendRef{
+		Name:   "test_default_8080",
+		Svc:    defaultSvc,
+		Port:   8080,
+		Valid:  true,
+		Weight: 1,
+	}
+	secureBackend := &BackendRef{
+		Name:   "test_secure_8443",
+		Svc:    secureSvc,
+		Port:   8443,
+		Valid:  true,
+		Weight: 1,
+	}
+
+	tests := []struct {
+		gateway   *v1beta1.Gateway
+		expected  map[string]*Listener
+		msg       string
+		listeners map[string]*Listener
+	}{
+		{
+			gateway:   nil,
+			listeners: createListeners(),
+			expected:  createListeners(),
+			msg:       "no gateway",
+		},
+		{
+			gateway: &v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "gateway"},
+			},
+			listeners: createListeners(),
+			expected:  createListeners(),
+			msg:       "no annotation",
+		},
+		{
+			gateway:   createGateway("default:8080"),
+			listeners: createListeners(),
+			expected: map[string]*Listener{
+				"http":    {Valid: true, DefaultBackend: defaultBackend},
+				"https":   {Valid: true, DefaultBackend: defaultBackend},
+				"invalid": {Valid: false},
+			},
+			msg: "default backend of all listeners",
+		},
+		{
+			gateway:   createGateway("default:8080,https=secure:8443,unknown=secure:8443"),
+			listeners: createListeners(),
+			expected: map[string]*Listener{
+				"http":    {Valid: true, DefaultBackend: defaultBackend},
+				"https":   {Valid: true, DefaultBackend: secureBackend},
+				"invalid": {Valid: false},
+			},
+			msg: "default backend of a listener overrides the default backend of all listeners",
+		},
+		{
+			gateway:   createGateway("https=secure:8443"),
+			listeners: createListeners(),
+			expected: map[string]*Listener{
+				"http":    {Valid: true},
+				"https":   {Valid: true, DefaultBackend: secureBackend},
+				"invalid": {Valid: false},
+			},
+			msg: "default backend of a listener only",
+		},
+		{
+			gateway:   createGateway("https=does-not-exist:8443"),
+			listeners: createListeners(),
+			expected: map[string]*Listener{
+				"http": {Valid: true},
+				"https": {
+					Valid:          true,
+					DefaultBackend: &BackendRef{},
+					Conditions: []conditions.Condition{
+						conditions.NewListenerInvalidDefaultBackend(
+							"Invalid default backend: the Service test/does-not-exist does not exist",
+						),
+					},
+				},
+				"invalid": {Valid: false},
+			},
+			msg: "service does not exist",
+		},
+		{
+			gateway:   createGateway("http=allowed:443,https=blocked:443"),
+			listeners: createListeners(),
+			expected: map[string]*Listener{
+				"http": {
+					Valid: true,
+					DefaultBackend: &BackendRef{
+						Name:   "test_allowed_443",
+						Svc:    allowedSvc,
+						Port:   443,
+						Valid:  true,
+						Weight: 1,
+					},
+				},
+				"https": {
+					Valid:          true,
+					DefaultBackend: &BackendRef{},
+					Conditions: []conditions.Condition{
+						conditions.NewListenerInvalidDefaultBackend(
+							"Invalid default backend: the external name 169.254.169.254.nip.io of the Service " +
+								"test/blocked is not allowed",
+						),
+					},
+				},
+				"invalid": {Valid: false},
+			},
+			msg: "external names",
+		},
+		{
+			gateway: createGateway("default"),
+			listeners: map[string]*Listener{
+				"http": {Valid: true},
+			},
+			expected: map[string]*Listener{
+				"http": {
+					Valid:          true,
+					DefaultBackend: &BackendRef{},
+					Conditions: []conditions.Condition{
+						conditions.NewListenerInvalidDefaultBackend(
+							`Invalid k8s-gateway.nginx.org/default-backend annotation: invalid entry "default": ` +
+								`"default" must be of the form <service>:<port>`,
+						),
+					},
+				},
+			},
+			msg: "invalid annotation",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			addDefaultBackendsToListeners(test.gateway, test.listeners, services, externalNameAllowlist)
+
+			if diff := cmp.Diff(test.expected, test.listeners); diff != "" {
+				t.Errorf("addDefaultBackendsToListeners() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
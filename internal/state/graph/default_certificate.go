@@ -0,0 +1,33 @@
+package graph
+
+import (
+	"strings"
+
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// DefaultCertificateAnnotation is the Gateway annotation that designates the HTTPS Listener whose certificates
+// NGINX presents to the clients that don't send SNI or send a hostname that no Listener matches. The value is
+// the name of the Listener. Without the annotation, NGINX rejects the TLS handshakes of such clients.
+const DefaultCertificateAnnotation = "k8s-gateway.nginx.org/default-certificate"
+
+// addDefaultCertificateToListeners marks the Listener designated through the DefaultCertificateAnnotation
+// of the Gateway as the Listener of the default certificate. The Listeners are modified in place.
+// The annotation is ignored if the Listener doesn't exist, is invalid or is not an HTTPS Listener.
+func addDefaultCertificateToListeners(gw *v1beta1.Gateway, listeners map[string]*Listener) {
+	if gw == nil {
+		return
+	}
+
+	name, exists := gw.Annotations[DefaultCertificateAnnotation]
+	if !exists {
+		return
+	}
+
+	l, exists := listeners[strings.TrimSpace(name)]
+	if !exists || !l.Valid || l.Source.Protocol != v1beta1.HTTPSProtocolType {
+		return
+	}
+
+	l.DefaultCertificate = true
+}
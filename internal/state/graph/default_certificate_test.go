@@ -0,0 +1,92 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func TestAddDefaultCertificateToListeners(t *testing.T) {
+	createGateway := func(annotation string) *v1beta1.Gateway {
+		return &v1beta1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "test",
+				Name:        "gateway",
+				Annotations: map[string]string{DefaultCertificateAnnotation: annotation},
+			},
+		}
+	}
+
+	createListener := func(protocol v1beta1.ProtocolType, valid, defaultCertificate bool) *Listener {
+		return &Listener{
+			Source:             v1beta1.Listener{Protocol: protocol},
+			Valid:              valid,
+			DefaultCertificate: defaultCertificate,
+		}
+	}
+
+	createListeners := func() map[string]*Listener {
+		return map[string]*Listener{
+			"http":          createListener(v1beta1.HTTPProtocolType, true, false),
+			"https":         createListener(v1beta1.HTTPSProtocolType, true, false),
+			"invalid-https": createListener(v1beta1.HTTPSProtocolType, false, false),
+		}
+	}
+
+	tests := []struct {
+		gateway  *v1beta1.Gateway
+		expected map[string]*Listener
+		msg      string
+	}{
+		{
+			gateway:  nil,
+			expected: createListeners(),
+			msg:      "no gateway",
+		},
+		{
+			gateway: &v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "gateway"},
+			},
+			expected: createListeners(),
+			msg:      "no annotation",
+		},
+		{
+			gateway: createGateway("https"),
+			expected: map[string]*Listener{
+				"http":          createListener(v1beta1.HTTPProtocolType, true, false),
+				"https":         createListener(v1beta1.HTTPSProtocolType, true, true),
+				"invalid-https": createListener(v1beta1.HTTPSProtocolType, false, false),
+			},
+			msg: "https listener",
+		},
+		{
+			gateway:  createGateway("http"),
+			expected: createListeners(),
+			msg:      "http listener",
+		},
+		{
+			gateway:  createGateway("invalid-https"),
+			expected: createListeners(),
+			msg:      "invalid listener",
+		},
+		{
+			gateway:  createGateway("unknown"),
+			expected: createListeners(),
+			msg:      "unknown listener",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			listeners := createListeners()
+
+			addDefaultCertificateToListeners(test.gateway, listeners)
+
+			if diff := cmp.Diff(test.expected, listeners); diff != "" {
+				t.Errorf("addDefaultCertificateToListeners() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
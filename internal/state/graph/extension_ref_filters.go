@@ -0,0 +1,207 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/apis/v1alpha1"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/conditions"
+)
+
+// RuleFilters holds the resolved ExtensionRef filters of a rule of an HTTPRoute.
+type RuleFilters struct {
+	// CORSPolicy is the CORSPolicy that the rule references. It is nil if the rule doesn't reference a valid
+	// CORSPolicy.
+	CORSPolicy *v1alpha1.CORSPolicy
+	// DirectResponse is the DirectResponse that the rule references. It is nil if the rule doesn't reference
+	// a valid DirectResponse.
+	DirectResponse *v1alpha1.DirectResponse
+	// Valid shows whether all ExtensionRef filters of the rule are resolved. If not, requests that match
+	// the rule must receive an error response.
+	Valid bool
+}
+
+var (
+	corsOriginRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*://[A-Za-z0-9.-]+(:[0-9]{1,5})?$`)
+	corsMethodRegexp = regexp.MustCompile(`^[A-Za-z]+$`)
+	corsHeaderRegexp = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+	// contentTypeRegexp matches a media type with optional parameters. For example, text/html; charset=utf-8.
+	contentTypeRegexp = regexp.MustCompile(`^[A-Za-z0-9.+-]+/[A-Za-z0-9.+-]+(; ?[A-Za-z0-9-]+=[A-Za-z0-9.-]+)*$`)
+)
+
+// maxDirectResponseBodyLength is the maximum length of the body of a DirectResponse.
+const maxDirectResponseBodyLength = 4096
+
+// addRuleFiltersToRoutes iterates over the routes and resolves the ExtensionRef filters of their rules.
+// The routes are modified in place.
+// If an ExtensionRef filter cannot be resolved, the corresponding RuleFilters is invalid and a condition is added
+// to the route. An ExtensionRef filter cannot be resolved if:
+// - its Group is not gateway.nginx.org or its Kind is not CORSPolicy or DirectResponse
+// - the referenced resource doesn't exist in the namespace of the route
+// - the referenced resource is invalid
+func addRuleFiltersToRoutes(
+	routes map[types.NamespacedName]*Route,
+	corsPolicies map[types.NamespacedName]*v1alpha1.CORSPolicy,
+	directResponses map[types.NamespacedName]*v1alpha1.DirectResponse,
+) {
+	for _, r := range routes {
+		r.RuleFilters = make([]RuleFilters, len(r.Source.Spec.Rules))
+
+		for idx, rule := range r.Source.Spec.Rules {
+			ruleFilters := RuleFilters{Valid: true}
+
+			for _, f := range rule.Filters {
+				if f.Type != v1beta1.HTTPRouteFilterExtensionRef || f.ExtensionRef == nil {
+					continue
+				}
+
+				ref := *f.ExtensionRef
+
+				var cond *conditions.Condition
+
+				switch {
+				case ref.Group == v1alpha1.GroupName && ref.Kind == v1alpha1.CORSPolicyKind:
+					var policy *v1alpha1.CORSPolicy
+					policy, cond = resolveCORSPolicyRef(ref, r.Source.Namespace, corsPolicies)
+
+					// using the first CORSPolicy
+					if cond == nil && ruleFilters.CORSPolicy == nil {
+						ruleFilters.CORSPolicy = policy
+					}
+				case ref.Group == v1alpha1.GroupName && ref.Kind == v1alpha1.DirectResponseKind:
+					var response *v1alpha1.DirectResponse
+					response, cond = resolveDirectResponseRef(ref, r.Source.Namespace, directResponses)
+
+					// using the first DirectResponse
+					if cond == nil && ruleFilters.DirectResponse == nil {
+						ruleFilters.DirectResponse = response
+					}
+				default:
+					c := conditions.NewRouteUnsupportedExtensionRefKind(
+						fmt.Sprintf("Unsupported ExtensionRef %s/%s; must be %s/%s or %s/%s",
+							ref.Group, ref.Kind, v1alpha1.GroupName, v1alpha1.CORSPolicyKind,
+							v1alpha1.GroupName, v1alpha1.DirectResponseKind),
+					)
+					cond = &c
+				}
+
+				if cond != nil {
+					ruleFilters.Valid = false
+					r.Conditions = append(r.Conditions, *cond)
+				}
+			}
+
+			r.RuleFilters[idx] = ruleFilters
+		}
+	}
+}
+
+func resolveCORSPolicyRef(
+	ref v1beta1.LocalObjectReference,
+	routeNamespace string,
+	corsPolicies map[types.NamespacedName]*v1alpha1.CORSPolicy,
+) (*v1alpha1.CORSPolicy, *conditions.Condition) {
+	nsname := types.NamespacedName{Namespace: routeNamespace, Name: string(ref.Name)}
+
+	policy, exist := corsPolicies[nsname]
+	if !exist {
+		cond := conditions.NewRouteExtensionRefNotFound(fmt.Sprintf("CORSPolicy %s not found", nsname))
+		return nil, &cond
+	}
+
+	if err := validateCORSPolicy(policy.Spec); err != nil {
+		cond := conditions.NewRouteInvalidExtensionRef(fmt.Sprintf("CORSPolicy %s is invalid: %v", nsname, err))
+		return nil, &cond
+	}
+
+	return policy, nil
+}
+
+func resolveDirectResponseRef(
+	ref v1beta1.LocalObjectReference,
+	routeNamespace string,
+	directResponses map[types.NamespacedName]*v1alpha1.DirectResponse,
+) (*v1alpha1.DirectResponse, *conditions.Condition) {
+	nsname := types.NamespacedName{Namespace: routeNamespace, Name: string(ref.Name)}
+
+	response, exist := directResponses[nsname]
+	if !exist {
+		cond := conditions.NewRouteExtensionRefNotFound(fmt.Sprintf("DirectResponse %s not found", nsname))
+		return nil, &cond
+	}
+
+	if err := validateDirectResponse(response.Spec); err != nil {
+		cond := conditions.NewRouteInvalidExtensionRef(fmt.Sprintf("DirectResponse %s is invalid: %v", nsname, err))
+		return nil, &cond
+	}
+
+	return response, nil
+}
+
+func validateDirectResponse(spec v1alpha1.DirectResponseSpec) error {
+	code := spec.StatusCode
+	if code < 200 || code > 599 || (code >= 300 && code < 400) {
+		return fmt.Errorf("invalid statusCode %d; must be in the range 200-599, except the redirect codes 3xx", code)
+	}
+
+	if len(spec.Body) > maxDirectResponseBodyLength {
+		return fmt.Errorf("body is longer than %d characters", maxDirectResponseBodyLength)
+	}
+
+	// NGINX would interpret the text after $ as a variable.
+	if strings.Contains(spec.Body, "$") {
+		return errors.New("body cannot include the $ character")
+	}
+
+	if spec.ContentType != "" && !contentTypeRegexp.MatchString(spec.ContentType) {
+		return fmt.Errorf("invalid contentType %q", spec.ContentType)
+	}
+
+	return nil
+}
+
+func validateCORSPolicy(spec v1alpha1.CORSPolicySpec) error {
+	if len(spec.AllowOrigins) == 0 {
+		return errors.New("allowOrigins must be set")
+	}
+
+	for _, o := range spec.AllowOrigins {
+		if o == "*" {
+			if spec.AllowCredentials {
+				return errors.New("the * origin cannot be used together with allowCredentials")
+			}
+			continue
+		}
+
+		if !corsOriginRegexp.MatchString(o) {
+			return fmt.Errorf("invalid origin %q; must be * or of the form scheme://host[:port]", o)
+		}
+	}
+
+	for _, m := range spec.AllowMethods {
+		if !corsMethodRegexp.MatchString(m) {
+			return fmt.Errorf("invalid method %q", m)
+		}
+	}
+
+	headers := make([]string, 0, len(spec.AllowHeaders)+len(spec.ExposeHeaders))
+	headers = append(headers, spec.AllowHeaders...)
+	headers = append(headers, spec.ExposeHeaders...)
+
+	for _, h := range headers {
+		if !corsHeaderRegexp.MatchString(h) {
+			return fmt.Errorf("invalid header %q", h)
+		}
+	}
+
+	if spec.MaxAge != nil && *spec.MaxAge < 0 {
+		return fmt.Errorf("invalid maxAge %d; must not be negative", *spec.MaxAge)
+	}
+
+	return nil
+}
@@ -0,0 +1,427 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/apis/v1alpha1"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/conditions"
+)
+
+func TestAddRuleFiltersToRoutes(t *testing.T) {
+	createExtensionRef := func(group, kind, name string) v1beta1.HTTPRouteFilter {
+		return v1beta1.HTTPRouteFilter{
+			Type: v1beta1.HTTPRouteFilterExtensionRef,
+			ExtensionRef: &v1beta1.LocalObjectReference{
+				Group: v1beta1.Group(group),
+				Kind:  v1beta1.Kind(kind),
+				Name:  v1beta1.ObjectName(name),
+			},
+		}
+	}
+
+	createRoute := func(name string, filters ...[]v1beta1.HTTPRouteFilter) *Route {
+		hr := &v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test",
+				Name:      name,
+			},
+		}
+
+		for _, f := range filters {
+			hr.Spec.Rules = append(hr.Spec.Rules, v1beta1.HTTPRouteRule{Filters: f})
+		}
+
+		return &Route{Source: hr}
+	}
+
+	corsPolicy := &v1alpha1.CORSPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "cors",
+		},
+		Spec: v1alpha1.CORSPolicySpec{
+			AllowOrigins: []string{"https://example.com"},
+		},
+	}
+
+	corsPolicy2 := &v1alpha1.CORSPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "cors-2",
+		},
+		Spec: v1alpha1.CORSPolicySpec{
+			AllowOrigins: []string{"*"},
+		},
+	}
+
+	invalidCORSPolicy := &v1alpha1.CORSPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "invalid",
+		},
+		Spec: v1alpha1.CORSPolicySpec{
+			AllowOrigins: []string{"example.com"},
+		},
+	}
+
+	corsPolicies := map[types.NamespacedName]*v1alpha1.CORSPolicy{
+		{Namespace: "test", Name: "cors"}:    corsPolicy,
+		{Namespace: "test", Name: "cors-2"}:  corsPolicy2,
+		{Namespace: "test", Name: "invalid"}: invalidCORSPolicy,
+	}
+
+	directResponse := &v1alpha1.DirectResponse{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "response",
+		},
+		Spec: v1alpha1.DirectResponseSpec{
+			StatusCode: 200,
+			Body:       "hello",
+		},
+	}
+
+	directResponse2 := &v1alpha1.DirectResponse{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "response-2",
+		},
+		Spec: v1alpha1.DirectResponseSpec{
+			StatusCode: 404,
+		},
+	}
+
+	invalidDirectResponse := &v1alpha1.DirectResponse{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "invalid",
+		},
+		Spec: v1alpha1.DirectResponseSpec{
+			StatusCode: 301,
+		},
+	}
+
+	directResponses := map[types.NamespacedName]*v1alpha1.DirectResponse{
+		{Namespace: "test", Name: "response"}:   directResponse,
+		{Namespace: "test", Name: "response-2"}: directResponse2,
+		{Namespace: "test", Name: "invalid"}:    invalidDirectResponse,
+	}
+
+	redirect := v1beta1.HTTPRouteFilter{
+		Type:            v1beta1.HTTPRouteFilterRequestRedirect,
+		RequestRedirect: &v1beta1.HTTPRequestRedirectFilter{},
+	}
+
+	tests := []struct {
+		route          *Route
+		msg            string
+		expRuleFilters []RuleFilters
+		expConditions  []conditions.Condition
+	}{
+		{
+			route:          createRoute("no-filters", nil),
+			expRuleFilters: []RuleFilters{{Valid: true}},
+			msg:            "no filters",
+		},
+		{
+			route: createRoute(
+				"cors",
+				[]v1beta1.HTTPRouteFilter{redirect, createExtensionRef("gateway.nginx.org", "CORSPolicy", "cors")},
+				nil,
+			),
+			expRuleFilters: []RuleFilters{
+				{CORSPolicy: corsPolicy, Valid: true},
+				{Valid: true},
+			},
+			msg: "CORS policy",
+		},
+		{
+			route: createRoute(
+				"two-cors",
+				[]v1beta1.HTTPRouteFilter{
+					createExtensionRef("gateway.nginx.org", "CORSPolicy", "cors"),
+					createExtensionRef("gateway.nginx.org", "CORSPolicy", "cors-2"),
+				},
+			),
+			expRuleFilters: []RuleFilters{{CORSPolicy: corsPolicy, Valid: true}},
+			msg:            "two CORS policies, first wins",
+		},
+		{
+			route: createRoute(
+				"not-found",
+				[]v1beta1.HTTPRouteFilter{createExtensionRef("gateway.nginx.org", "CORSPolicy", "dne")},
+				[]v1beta1.HTTPRouteFilter{createExtensionRef("gateway.nginx.org", "CORSPolicy", "cors")},
+			),
+			expRuleFilters: []RuleFilters{
+				{Valid: false},
+				{CORSPolicy: corsPolicy, Valid: true},
+			},
+			expConditions: []conditions.Condition{
+				conditions.NewRouteExtensionRefNotFound("CORSPolicy test/dne not found"),
+			},
+			msg: "CORS policy not found",
+		},
+		{
+			route: createRoute(
+				"unsupported-kind",
+				[]v1beta1.HTTPRouteFilter{createExtensionRef("example.com", "Filter", "cors")},
+			),
+			expRuleFilters: []RuleFilters{{Valid: false}},
+			expConditions: []conditions.Condition{
+				conditions.NewRouteUnsupportedExtensionRefKind(
+					"Unsupported ExtensionRef example.com/Filter; must be gateway.nginx.org/CORSPolicy " +
+						"or gateway.nginx.org/DirectResponse",
+				),
+			},
+			msg: "unsupported kind",
+		},
+		{
+			route: createRoute(
+				"invalid",
+				[]v1beta1.HTTPRouteFilter{
+					createExtensionRef("gateway.nginx.org", "CORSPolicy", "invalid"),
+					createExtensionRef("gateway.nginx.org", "CORSPolicy", "cors"),
+				},
+			),
+			expRuleFilters: []RuleFilters{{CORSPolicy: corsPolicy, Valid: false}},
+			expConditions: []conditions.Condition{
+				conditions.NewRouteInvalidExtensionRef(
+					`CORSPolicy test/invalid is invalid: invalid origin "example.com"; ` +
+						"must be * or of the form scheme://host[:port]",
+				),
+			},
+			msg: "invalid CORS policy",
+		},
+		{
+			route: createRoute(
+				"direct-response",
+				[]v1beta1.HTTPRouteFilter{
+					createExtensionRef("gateway.nginx.org", "CORSPolicy", "cors"),
+					createExtensionRef("gateway.nginx.org", "DirectResponse", "response"),
+					createExtensionRef("gateway.nginx.org", "DirectResponse", "response-2"),
+				},
+			),
+			expRuleFilters: []RuleFilters{{CORSPolicy: corsPolicy, DirectResponse: directResponse, Valid: true}},
+			msg:            "two direct responses, first wins",
+		},
+		{
+			route: createRoute(
+				"direct-response-not-found",
+				[]v1beta1.HTTPRouteFilter{createExtensionRef("gateway.nginx.org", "DirectResponse", "dne")},
+			),
+			expRuleFilters: []RuleFilters{{Valid: false}},
+			expConditions: []conditions.Condition{
+				conditions.NewRouteExtensionRefNotFound("DirectResponse test/dne not found"),
+			},
+			msg: "direct response not found",
+		},
+		{
+			route: createRoute(
+				"invalid-direct-response",
+				[]v1beta1.HTTPRouteFilter{createExtensionRef("gateway.nginx.org", "DirectResponse", "invalid")},
+			),
+			expRuleFilters: []RuleFilters{{Valid: false}},
+			expConditions: []conditions.Condition{
+				conditions.NewRouteInvalidExtensionRef(
+					"DirectResponse test/invalid is invalid: invalid statusCode 301; " +
+						"must be in the range 200-599, except the redirect codes 3xx",
+				),
+			},
+			msg: "invalid direct response",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			routes := map[types.NamespacedName]*Route{
+				{Namespace: "test", Name: test.route.Source.Name}: test.route,
+			}
+
+			addRuleFiltersToRoutes(routes, corsPolicies, directResponses)
+
+			if diff := cmp.Diff(test.expRuleFilters, test.route.RuleFilters); diff != "" {
+				t.Errorf("addRuleFiltersToRoutes() mismatch on rule filters (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.expConditions, test.route.Conditions); diff != "" {
+				t.Errorf("addRuleFiltersToRoutes() mismatch on conditions (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateCORSPolicy(t *testing.T) {
+	tests := []struct {
+		msg    string
+		spec   v1alpha1.CORSPolicySpec
+		expErr bool
+	}{
+		{
+			spec: v1alpha1.CORSPolicySpec{
+				AllowOrigins:     []string{"https://example.com", "http://foo.example.com:8080"},
+				AllowMethods:     []string{"GET", "POST"},
+				AllowHeaders:     []string{"Content-Type", "X-Request-Id"},
+				ExposeHeaders:    []string{"X-Trace-Id"},
+				MaxAge:           helpers.GetInt32Pointer(3600),
+				AllowCredentials: true,
+			},
+			expErr: false,
+			msg:    "valid",
+		},
+		{
+			spec: v1alpha1.CORSPolicySpec{
+				AllowOrigins: []string{"*"},
+			},
+			expErr: false,
+			msg:    "any origin",
+		},
+		{
+			spec:   v1alpha1.CORSPolicySpec{},
+			expErr: true,
+			msg:    "no origins",
+		},
+		{
+			spec: v1alpha1.CORSPolicySpec{
+				AllowOrigins:     []string{"*"},
+				AllowCredentials: true,
+			},
+			expErr: true,
+			msg:    "any origin with credentials",
+		},
+		{
+			spec: v1alpha1.CORSPolicySpec{
+				AllowOrigins: []string{`https://example.com"; return 200; "`},
+			},
+			expErr: true,
+			msg:    "invalid origin",
+		},
+		{
+			spec: v1alpha1.CORSPolicySpec{
+				AllowOrigins: []string{"https://example.com"},
+				AllowMethods: []string{"GET;"},
+			},
+			expErr: true,
+			msg:    "invalid method",
+		},
+		{
+			spec: v1alpha1.CORSPolicySpec{
+				AllowOrigins: []string{"https://example.com"},
+				AllowHeaders: []string{"Content Type"},
+			},
+			expErr: true,
+			msg:    "invalid allow header",
+		},
+		{
+			spec: v1alpha1.CORSPolicySpec{
+				AllowOrigins:  []string{"https://example.com"},
+				ExposeHeaders: []string{`X-Trace-Id"`},
+			},
+			expErr: true,
+			msg:    "invalid expose header",
+		},
+		{
+			spec: v1alpha1.CORSPolicySpec{
+				AllowOrigins: []string{"https://example.com"},
+				MaxAge:       helpers.GetInt32Pointer(-1),
+			},
+			expErr: true,
+			msg:    "negative max age",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			err := validateCORSPolicy(test.spec)
+			if test.expErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestValidateDirectResponse(t *testing.T) {
+	tests := []struct {
+		msg    string
+		spec   v1alpha1.DirectResponseSpec
+		expErr bool
+	}{
+		{
+			spec: v1alpha1.DirectResponseSpec{
+				StatusCode:  200,
+				Body:        `{"status": "ok"}`,
+				ContentType: "application/json; charset=utf-8",
+			},
+			expErr: false,
+			msg:    "valid",
+		},
+		{
+			spec: v1alpha1.DirectResponseSpec{
+				StatusCode: 503,
+			},
+			expErr: false,
+			msg:    "no body",
+		},
+		{
+			spec: v1alpha1.DirectResponseSpec{
+				StatusCode: 302,
+			},
+			expErr: true,
+			msg:    "redirect code",
+		},
+		{
+			spec: v1alpha1.DirectResponseSpec{
+				StatusCode: 600,
+			},
+			expErr: true,
+			msg:    "code out of range",
+		},
+		{
+			spec: v1alpha1.DirectResponseSpec{
+				StatusCode: 200,
+				Body:       "$host",
+			},
+			expErr: true,
+			msg:    "body with variable",
+		},
+		{
+			spec: v1alpha1.DirectResponseSpec{
+				StatusCode: 200,
+				Body:       strings.Repeat("a", maxDirectResponseBodyLength+1),
+			},
+			expErr: true,
+			msg:    "body too long",
+		},
+		{
+			spec: v1alpha1.DirectResponseSpec{
+				StatusCode:  200,
+				ContentType: `text/plain"; return 500; "`,
+			},
+			expErr: true,
+			msg:    "invalid content type",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			err := validateDirectResponse(test.spec)
+			if test.expErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
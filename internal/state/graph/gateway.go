@@ -3,6 +3,7 @@ package graph
 import (
 	"errors"
 	"fmt"
+	"net"
 	"sort"
 	"strings"
 
@@ -16,6 +17,16 @@ import (
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/secrets"
 )
 
+// DisabledListenersAnnotation is the Gateway annotation that disables Listeners of the Gateway. The value is
+// a comma-separated list of the names of the Listeners. NGINX doesn't serve traffic for disabled Listeners,
+// and Routes can't attach to them.
+const DisabledListenersAnnotation = "k8s-gateway.nginx.org/disabled-listeners"
+
+// ClientCertificateTLSOption is the key of the listener TLS option that enables the verification of the client
+// certificates (mTLS). The value is the name of a Secret in the namespace of the Gateway that holds the bundle of
+// the CA certificates in the ca.crt field.
+const ClientCertificateTLSOption = "k8s-gateway.nginx.org/ssl-client-certificate"
+
 // Gateway represents the winning Gateway resource.
 type Gateway struct {
 	// Source is the corresponding Gateway resource.
@@ -36,11 +47,30 @@ type Listener struct {
 	AcceptedHostnames map[string]struct{}
 	// SecretPath is the path to the secret on disk.
 	SecretPath string
+	// SecondarySecretPath is the path to the second secret on disk. It is set when the Listener references
+	// two secrets, which must have different key types (RSA and ECDSA). NGINX chooses the certificate based on
+	// the client handshake.
+	SecondarySecretPath string
+	// ClientCertificatePath is the path to the bundle of the CA certificates on disk, which NGINX verifies
+	// the client certificates with. It is set when the Listener configures the ClientCertificateTLSOption.
+	ClientCertificatePath string
 	// Conditions holds the conditions of the Listener.
 	Conditions []conditions.Condition
 	// Valid shows whether the Listener is valid.
 	// A Listener is considered valid if NKG can generate valid NGINX configuration for it.
 	Valid bool
+	// Disabled shows whether the Listener is disabled through the DisabledListenersAnnotation.
+	// A disabled Listener is not valid.
+	Disabled bool
+	// DefaultBackend is the backend that NGINX proxies the requests that match the hostname of the Listener
+	// but no routes to. It is configured through the DefaultBackendAnnotation. It is nil if not configured.
+	DefaultBackend *BackendRef
+	// RateLimit is the limit of the rate of the requests to the Listener per client address. It is configured
+	// through the RateLimitAnnotation. It is nil if not configured or invalid.
+	RateLimit *RateLimit
+	// DefaultCertificate shows whether NGINX presents the certificates of the Listener to the clients that don't
+	// send SNI. It is configured through the DefaultCertificateAnnotation.
+	DefaultCertificate bool
 }
 
 // processGateways determines which Gateway resource the NGINX Gateway will use (the winner) and which Gateway(s) will
@@ -89,8 +119,14 @@ func buildListeners(
 	}
 
 	listenerFactory := newListenerConfiguratorFactory(gw, secretMemoryMgr)
+	disabledListeners := getDisabledListeners(gw)
 
 	for _, gl := range gw.Spec.Listeners {
+		if _, disabled := disabledListeners[string(gl.Name)]; disabled {
+			listeners[string(gl.Name)] = newDisabledListener(gl)
+			continue
+		}
+
 		configurator := listenerFactory.getConfiguratorForListener(gl)
 		listeners[string(gl.Name)] = configurator.configure(gl)
 	}
@@ -98,6 +134,42 @@ func buildListeners(
 	return listeners
 }
 
+// getDisabledListeners returns the names of the Listeners disabled through the DisabledListenersAnnotation.
+func getDisabledListeners(gw *v1beta1.Gateway) map[string]struct{} {
+	v, exists := gw.Annotations[DisabledListenersAnnotation]
+	if !exists {
+		return nil
+	}
+
+	names := make(map[string]struct{})
+
+	for _, name := range strings.Split(v, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			names[name] = struct{}{}
+		}
+	}
+
+	return names
+}
+
+// newDisabledListener creates a disabled Listener. A disabled Listener is not validated, doesn't request
+// its Secrets and doesn't take part in detecting hostname conflicts.
+func newDisabledListener(gl v1beta1.Listener) *Listener {
+	msg := fmt.Sprintf("Listener is disabled by the %s annotation of the Gateway", DisabledListenersAnnotation)
+
+	return &Listener{
+		Source:            gl,
+		Valid:             false,
+		Disabled:          true,
+		Routes:            make(map[types.NamespacedName]*Route),
+		AcceptedHostnames: make(map[string]struct{}),
+		Conditions: []conditions.Condition{
+			conditions.NewListenerDisabled(msg),
+		},
+	}
+}
+
 type listenerConfigurator interface {
 	configure(listener v1beta1.Listener) *Listener
 }
@@ -164,8 +236,9 @@ func validateListener(
 ) (conds []conditions.Condition, validHostname bool) {
 	conds = validate(gl)
 
-	if len(gw.Spec.Addresses) > 0 {
-		conds = append(conds, conditions.NewListenerUnsupportedAddress("Specifying Gateway addresses is not supported"))
+	if err := validateGatewayAddresses(gw.Spec.Addresses); err != nil {
+		msg := fmt.Sprintf("Unsupported address: %v", err)
+		conds = append(conds, conditions.NewListenerUnsupportedAddress(msg))
 	}
 
 	validHostnameErr := validateListenerHostname(gl.Hostname)
@@ -177,14 +250,37 @@ func validateListener(
 	return conds, validHostnameErr == nil
 }
 
+// validateGatewayAddresses validates the addresses of the Gateway. Only addresses of the IPAddress type are supported.
+// The Listeners are bound to those addresses.
+func validateGatewayAddresses(addresses []v1beta1.GatewayAddress) error {
+	for _, a := range addresses {
+		if a.Type != nil && *a.Type != v1beta1.IPAddressType {
+			return fmt.Errorf("type %q of the address %q is not supported; must be %q", *a.Type, a.Value,
+				v1beta1.IPAddressType)
+		}
+
+		if net.ParseIP(a.Value) == nil {
+			return fmt.Errorf("%q is not a valid IP address", a.Value)
+		}
+	}
+
+	return nil
+}
+
+// ensureUniqueHostnamesAmongListeners ensures that Listeners of the same protocol that share a port use different
+// hostnames. Such Listeners share the same NGINX listen socket, and NGINX selects the server for a request
+// based on its hostname (and the SNI for HTTPS). If Listeners share a port and a hostname, they become conflicted.
 func (c *httpListenerConfigurator) ensureUniqueHostnamesAmongListeners(l *Listener) {
 	h := getHostname(l.Source.Hostname)
+	key := fmt.Sprintf("%d/%s", l.Source.Port, h)
 
-	if holder, exist := c.usedHostnames[h]; exist {
+	if holder, exist := c.usedHostnames[key]; exist {
 		l.Valid = false
 
 		holder.Valid = false   // all listeners for the same hostname become conflicted
-		holder.SecretPath = "" // ensure secret path is unset for invalid listeners
+		holder.SecretPath = "" // ensure secret paths are unset for invalid listeners
+		holder.SecondarySecretPath = ""
+		holder.ClientCertificatePath = ""
 
 		format := "Multiple listeners for the same port use the same hostname %q; " +
 			"ensure only one listener uses that hostname"
@@ -196,7 +292,7 @@ func (c *httpListenerConfigurator) ensureUniqueHostnamesAmongListeners(l *Listen
 		return
 	}
 
-	c.usedHostnames[h] = l
+	c.usedHostnames[key] = l
 }
 
 func (c *httpListenerConfigurator) loadSecretIntoListener(l *Listener) {
@@ -204,19 +300,78 @@ func (c *httpListenerConfigurator) loadSecretIntoListener(l *Listener) {
 		return
 	}
 
+	paths := make([]string, 0, len(l.Source.TLS.CertificateRefs))
+	keyTypes := make(map[secrets.KeyType]struct{}, len(l.Source.TLS.CertificateRefs))
+
+	for _, ref := range l.Source.TLS.CertificateRefs {
+		nsname := types.NamespacedName{
+			Namespace: c.gateway.Namespace,
+			Name:      string(ref.Name),
+		}
+
+		path, err := c.secretMemoryMgr.Request(nsname)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to get the certificate %s: %v", nsname.String(), err)
+			l.Conditions = append(l.Conditions, conditions.NewListenerInvalidCertificateRef(msg)...)
+			l.Valid = false
+			return
+		}
+
+		if len(l.Source.TLS.CertificateRefs) > 1 {
+			keyType, err := c.secretMemoryMgr.GetKeyType(nsname)
+			if err != nil {
+				msg := fmt.Sprintf("Failed to get the key type of the certificate %s: %v", nsname.String(), err)
+				l.Conditions = append(l.Conditions, conditions.NewListenerInvalidCertificateRef(msg)...)
+				l.Valid = false
+				return
+			}
+
+			if _, exists := keyTypes[keyType]; exists || keyType == secrets.KeyTypeUnknown {
+				msg := fmt.Sprintf("Certificates must have different key types, one RSA and one ECDSA; "+
+					"the certificate %s has the key type %s", nsname.String(), keyType)
+				l.Conditions = append(l.Conditions, conditions.NewListenerInvalidCertificateRef(msg)...)
+				l.Valid = false
+				return
+			}
+
+			keyTypes[keyType] = struct{}{}
+		}
+
+		paths = append(paths, path)
+	}
+
+	l.SecretPath = paths[0]
+	if len(paths) > 1 {
+		l.SecondarySecretPath = paths[1]
+	}
+
+	c.loadClientCertificateIntoListener(l)
+}
+
+// loadClientCertificateIntoListener loads the bundle of the CA certificates for verifying the client certificates,
+// if the Listener configures it through the ClientCertificateTLSOption.
+func (c *httpListenerConfigurator) loadClientCertificateIntoListener(l *Listener) {
+	name, exists := l.Source.TLS.Options[ClientCertificateTLSOption]
+	if !exists {
+		return
+	}
+
 	nsname := types.NamespacedName{
 		Namespace: c.gateway.Namespace,
-		Name:      string(l.Source.TLS.CertificateRefs[0].Name),
+		Name:      string(name),
 	}
 
-	var err error
-
-	l.SecretPath, err = c.secretMemoryMgr.Request(nsname)
+	path, err := c.secretMemoryMgr.RequestCA(nsname)
 	if err != nil {
-		msg := fmt.Sprintf("Failed to get the certificate %s: %v", nsname.String(), err)
+		msg := fmt.Sprintf("Failed to get the client CA certificate %s: %v", nsname.String(), err)
 		l.Conditions = append(l.Conditions, conditions.NewListenerInvalidCertificateRef(msg)...)
 		l.Valid = false
+		l.SecretPath = ""
+		l.SecondarySecretPath = ""
+		return
 	}
+
+	l.ClientCertificatePath = path
 }
 
 func (c *httpListenerConfigurator) configure(gl v1beta1.Listener) *Listener {
@@ -251,17 +406,72 @@ func (c *invalidProtocolListenerConfigurator) configure(gl v1beta1.Listener) *Li
 	msg := fmt.Sprintf("Protocol %q is not supported, use %q or %q",
 		gl.Protocol, v1beta1.HTTPProtocolType, v1beta1.HTTPSProtocolType)
 
+	conds := []conditions.Condition{conditions.NewListenerUnsupportedProtocol(msg)}
+	// The protocol/TLS combination is reported too, so that the user can fix both at once.
+	conds = append(conds, validateListenerTLS(gl.Protocol, gl.TLS)...)
+
 	return &Listener{
 		Source:            gl,
 		Valid:             false,
 		Routes:            make(map[types.NamespacedName]*Route),
 		AcceptedHostnames: make(map[string]struct{}),
-		Conditions: []conditions.Condition{
-			conditions.NewListenerUnsupportedProtocol(msg),
-		},
+		Conditions:        conds,
 	}
 }
 
+// validateListenerTLS validates the combination of the protocol and the tls field (the tls.mode and
+// the tls.certificateRefs) of a Listener:
+// - HTTP, TCP and UDP Listeners must not have the tls field.
+// - HTTPS and TLS Listeners must have the tls field.
+// - Listeners with the Terminate tls.mode (the default) must have tls.certificateRefs.
+// - Listeners with the Passthrough tls.mode must not have tls.certificateRefs.
+//
+// The Gateway webhook validation, which NKG runs for the Gateways, rejects some of those combinations. However,
+// NKG doesn't rely on it, so that an invalid combination never results in an invalid NGINX configuration.
+func validateListenerTLS(
+	protocol v1beta1.ProtocolType,
+	tls *v1beta1.GatewayTLSConfig,
+) []conditions.Condition {
+	switch protocol {
+	case v1beta1.HTTPProtocolType, v1beta1.TCPProtocolType, v1beta1.UDPProtocolType:
+		if tls != nil {
+			msg := fmt.Sprintf("tls must not be set for protocol %q", protocol)
+			return []conditions.Condition{conditions.NewListenerUnsupportedValue(msg)}
+		}
+
+		return nil
+	case v1beta1.HTTPSProtocolType, v1beta1.TLSProtocolType:
+		if tls == nil {
+			msg := fmt.Sprintf("tls must be set for protocol %q", protocol)
+			return []conditions.Condition{conditions.NewListenerUnsupportedValue(msg)}
+		}
+	default:
+		return nil
+	}
+
+	mode := getTLSMode(tls)
+
+	switch {
+	case mode == v1beta1.TLSModeTerminate && len(tls.CertificateRefs) == 0:
+		msg := fmt.Sprintf("tls.certificateRefs must be set for tls.mode %q", mode)
+		return conditions.NewListenerInvalidCertificateRef(msg)
+	case mode == v1beta1.TLSModePassthrough && len(tls.CertificateRefs) > 0:
+		msg := fmt.Sprintf("tls.certificateRefs must not be set for tls.mode %q", mode)
+		return []conditions.Condition{conditions.NewListenerUnsupportedValue(msg)}
+	}
+
+	return nil
+}
+
+// getTLSMode returns the tls.mode, which is Terminate if unset, as defaulted by the Gateway API.
+func getTLSMode(tls *v1beta1.GatewayTLSConfig) v1beta1.TLSModeType {
+	if tls.Mode == nil {
+		return v1beta1.TLSModeTerminate
+	}
+
+	return *tls.Mode
+}
+
 func validateHTTPListener(listener v1beta1.Listener) []conditions.Condition {
 	var conds []conditions.Condition
 
@@ -270,8 +480,7 @@ func validateHTTPListener(listener v1beta1.Listener) []conditions.Condition {
 		conds = append(conds, conditions.NewListenerPortUnavailable(msg))
 	}
 
-	// The imported Webhook validation ensures the tls field is not set for an HTTP listener.
-	// FIXME(pleshakov): Add a unit test for the imported Webhook validation code for this case.
+	conds = append(conds, validateListenerTLS(v1beta1.HTTPProtocolType, listener.TLS)...)
 
 	return conds
 }
@@ -284,43 +493,43 @@ func validateHTTPSListener(listener v1beta1.Listener, gwNsName string) []conditi
 		conds = append(conds, conditions.NewListenerPortUnavailable(msg))
 	}
 
-	// The imported Webhook validation ensures the tls field is not nil for an HTTPS listener.
-	// FIXME(pleshakov): Add a unit test for the imported Webhook validation code for this case.
+	tlsConds := validateListenerTLS(v1beta1.HTTPSProtocolType, listener.TLS)
+	conds = append(conds, tlsConds...)
 
-	if *listener.TLS.Mode != v1beta1.TLSModeTerminate {
-		msg := fmt.Sprintf("tls.mode %q is not supported, use %q", *listener.TLS.Mode, v1beta1.TLSModeTerminate)
-		conds = append(conds, conditions.NewListenerUnsupportedValue(msg))
+	if listener.TLS == nil {
+		// the fields below can't be validated without the tls field
+		return conds
 	}
 
-	if len(listener.TLS.Options) > 0 {
-		conds = append(conds, conditions.NewListenerUnsupportedValue("tls.options are not supported"))
+	if mode := getTLSMode(listener.TLS); mode != v1beta1.TLSModeTerminate {
+		msg := fmt.Sprintf("tls.mode %q is not supported, use %q", mode, v1beta1.TLSModeTerminate)
+		conds = append(conds, conditions.NewListenerUnsupportedValue(msg))
 	}
 
-	// The imported Webhook validation ensures len(listener.TLS.Certificates) is not 0.
-	// FIXME(pleshakov): Add a unit test for the imported Webhook validation code for this case.
-
-	certRef := listener.TLS.CertificateRefs[0]
-
-	if certRef.Kind != nil && *certRef.Kind != "Secret" {
-		msg := fmt.Sprintf("Kind must be Secret, got %q", *certRef.Kind)
-		conds = append(conds, conditions.NewListenerInvalidCertificateRef(msg)...)
-	}
+	// tls.options are validated when the NGINX configuration is built: the unknown keys are ignored with a warning.
 
-	// for Kind Secret, certRef.Group must be nil or empty
-	if certRef.Group != nil && *certRef.Group != "" {
-		msg := fmt.Sprintf("Group must be empty, got %q", *certRef.Group)
-		conds = append(conds, conditions.NewListenerInvalidCertificateRef(msg)...)
-	}
+	for _, certRef := range listener.TLS.CertificateRefs {
+		if certRef.Kind != nil && *certRef.Kind != "Secret" {
+			msg := fmt.Sprintf("Kind must be Secret, got %q", *certRef.Kind)
+			conds = append(conds, conditions.NewListenerInvalidCertificateRef(msg)...)
+		}
 
-	// secret must be in the same namespace as the gateway
-	if certRef.Namespace != nil && string(*certRef.Namespace) != gwNsName {
-		const msg = "Referenced Secret must belong to the same namespace as the Gateway"
-		conds = append(conds, conditions.NewListenerInvalidCertificateRef(msg)...)
+		// for Kind Secret, certRef.Group must be nil or empty
+		if certRef.Group != nil && *certRef.Group != "" {
+			msg := fmt.Sprintf("Group must be empty, got %q", *certRef.Group)
+			conds = append(conds, conditions.NewListenerInvalidCertificateRef(msg)...)
+		}
 
+		// secret must be in the same namespace as the gateway
+		if certRef.Namespace != nil && string(*certRef.Namespace) != gwNsName {
+			const msg = "Referenced Secret must belong to the same namespace as the Gateway"
+			conds = append(conds, conditions.NewListenerInvalidCertificateRef(msg)...)
+		}
 	}
 
-	if l := len(listener.TLS.CertificateRefs); l > 1 {
-		msg := fmt.Sprintf("Only 1 certificateRef is supported, got %d", l)
+	// Two certificateRefs are supported to serve an RSA and an ECDSA certificate for the same hostname.
+	if l := len(listener.TLS.CertificateRefs); l > 2 {
+		msg := fmt.Sprintf("Up to 2 certificateRefs are supported, got %d", l)
 		conds = append(conds, conditions.NewListenerUnsupportedValue(msg))
 	}
 
@@ -338,12 +547,13 @@ func validateListenerHostname(host *v1beta1.Hostname) error {
 		return nil
 	}
 
-	// FIXME(pleshakov): For now, we don't support wildcard hostnames
+	var msgs []string
 	if strings.HasPrefix(h, "*") {
-		return fmt.Errorf("wildcard hostnames are not supported")
+		msgs = validation.IsWildcardDNS1123Subdomain(h)
+	} else {
+		msgs = validation.IsDNS1123Subdomain(h)
 	}
 
-	msgs := validation.IsDNS1123Subdomain(h)
 	if len(msgs) > 0 {
 		combined := strings.Join(msgs, ",")
 		return errors.New(combined)
@@ -1,6 +1,7 @@
 package graph
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -12,6 +13,7 @@ import (
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/conditions"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/secrets"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/secrets/secretsfakes"
 )
 
 func TestProcessGateways(t *testing.T) {
@@ -106,6 +108,14 @@ func TestBuildListeners(t *testing.T) {
 		Port:     80,
 		Protocol: v1beta1.TCPProtocolType, // invalid protocol
 	}
+	listenerTCPWithTLS := v1beta1.Listener{
+		Name:     "listener-tcp-tls",
+		Port:     80,
+		Protocol: v1beta1.TCPProtocolType,
+		TLS: &v1beta1.GatewayTLSConfig{
+			CertificateRefs: []v1beta1.SecretObjectReference{{Name: "secret"}},
+		},
+	}
 	listener803 := v1beta1.Listener{
 		Name:     "listener-80-3",
 		Hostname: (*v1beta1.Hostname)(helpers.GetStringPointer("bar.example.com")),
@@ -194,6 +204,25 @@ func TestBuildListeners(t *testing.T) {
 		TLS:      gatewayTLSConfig,
 		Protocol: v1beta1.HTTPSProtocolType,
 	}
+	listener4437 := v1beta1.Listener{
+		Name:     "listener-443-7",
+		Port:     443, // no hostname
+		TLS:      gatewayTLSConfig,
+		Protocol: v1beta1.HTTPSProtocolType,
+	}
+
+	gatewayTLSConfigWithOptions := gatewayTLSConfig.DeepCopy()
+	gatewayTLSConfigWithOptions.Options = map[v1beta1.AnnotationKey]v1beta1.AnnotationValue{
+		"k8s-gateway.nginx.org/ssl-protocols": "TLSv1.3",
+		"unknown":                             "value",
+	}
+	listener4438 := v1beta1.Listener{
+		Name:     "listener-443-8",
+		Hostname: (*v1beta1.Hostname)(helpers.GetStringPointer("foo.example.com")),
+		Port:     443,
+		TLS:      gatewayTLSConfigWithOptions,
+		Protocol: v1beta1.HTTPSProtocolType,
+	}
 
 	const (
 		invalidHostnameMsg = "Invalid hostname: a lowercase RFC 1123 subdomain " +
@@ -201,6 +230,8 @@ func TestBuildListeners(t *testing.T) {
 			"with an alphanumeric character (e.g. 'example.com', regex used for validation is " +
 			`'[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`
 
+		disabledMsg = "Listener is disabled by the k8s-gateway.nginx.org/disabled-listeners annotation of the Gateway"
+
 		conflictedHostnamesMsg = `Multiple listeners for the same port use the same hostname "foo.example.com"; ` +
 			"ensure only one listener uses that hostname"
 	)
@@ -256,6 +287,29 @@ func TestBuildListeners(t *testing.T) {
 			},
 			name: "valid https listener",
 		},
+		{
+			gateway: &v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "test",
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: gcName,
+					Listeners: []v1beta1.Listener{
+						listener4438,
+					},
+				},
+			},
+			expected: map[string]*Listener{
+				"listener-443-8": {
+					Source:            listener4438,
+					Valid:             true,
+					Routes:            map[types.NamespacedName]*Route{},
+					AcceptedHostnames: map[string]struct{}{},
+					SecretPath:        secretPath,
+				},
+			},
+			name: "valid https listener with tls options",
+		},
 		{
 			gateway: &v1beta1.Gateway{
 				ObjectMeta: metav1.ObjectMeta{
@@ -282,6 +336,33 @@ func TestBuildListeners(t *testing.T) {
 			},
 			name: "invalid listener protocol",
 		},
+		{
+			gateway: &v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "test",
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: gcName,
+					Listeners: []v1beta1.Listener{
+						listenerTCPWithTLS,
+					},
+				},
+			},
+			expected: map[string]*Listener{
+				"listener-tcp-tls": {
+					Source:            listenerTCPWithTLS,
+					Valid:             false,
+					Routes:            map[types.NamespacedName]*Route{},
+					AcceptedHostnames: map[string]struct{}{},
+					Conditions: []conditions.Condition{
+						conditions.NewListenerUnsupportedProtocol(`Protocol "TCP" is not supported, use "HTTP" ` +
+							`or "HTTPS"`),
+						conditions.NewListenerUnsupportedValue(`tls must not be set for protocol "TCP"`),
+					},
+				},
+			},
+			name: "invalid listener protocol with certificateRefs",
+		},
 		{
 			gateway: &v1beta1.Gateway{
 				ObjectMeta: metav1.ObjectMeta{
@@ -479,6 +560,112 @@ func TestBuildListeners(t *testing.T) {
 			},
 			name: "collisions",
 		},
+		{
+			gateway: &v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "test",
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: gcName,
+					Listeners: []v1beta1.Listener{
+						listener4431, listener4432, listener4437,
+					},
+				},
+			},
+			expected: map[string]*Listener{
+				"listener-443-1": {
+					Source:            listener4431,
+					Valid:             true,
+					Routes:            map[types.NamespacedName]*Route{},
+					AcceptedHostnames: map[string]struct{}{},
+					SecretPath:        secretPath,
+				},
+				"listener-443-2": {
+					Source:            listener4432,
+					Valid:             true,
+					Routes:            map[types.NamespacedName]*Route{},
+					AcceptedHostnames: map[string]struct{}{},
+					SecretPath:        secretPath,
+				},
+				"listener-443-7": {
+					Source:            listener4437,
+					Valid:             true,
+					Routes:            map[types.NamespacedName]*Route{},
+					AcceptedHostnames: map[string]struct{}{},
+					SecretPath:        secretPath,
+				},
+			},
+			name: "https listeners with different hostnames share a port",
+		},
+		{
+			gateway: &v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "test",
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: gcName,
+					Listeners: []v1beta1.Listener{
+						listener4431, listener4432, listener4433,
+					},
+				},
+			},
+			expected: map[string]*Listener{
+				"listener-443-1": {
+					Source:            listener4431,
+					Valid:             false,
+					Routes:            map[types.NamespacedName]*Route{},
+					AcceptedHostnames: map[string]struct{}{},
+					Conditions:        conditions.NewListenerConflictedHostname(conflictedHostnamesMsg),
+				},
+				"listener-443-2": {
+					Source:            listener4432,
+					Valid:             true,
+					Routes:            map[types.NamespacedName]*Route{},
+					AcceptedHostnames: map[string]struct{}{},
+					SecretPath:        secretPath,
+				},
+				"listener-443-3": {
+					Source:            listener4433,
+					Valid:             false,
+					Routes:            map[types.NamespacedName]*Route{},
+					AcceptedHostnames: map[string]struct{}{},
+					Conditions:        conditions.NewListenerConflictedHostname(conflictedHostnamesMsg),
+				},
+			},
+			name: "https listeners share a port, some with the same hostname",
+		},
+		{
+			gateway: &v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "test",
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: gcName,
+					Listeners: []v1beta1.Listener{
+						listener4431, listener4436,
+					},
+				},
+			},
+			expected: map[string]*Listener{
+				"listener-443-1": {
+					Source:            listener4431,
+					Valid:             true,
+					Routes:            map[types.NamespacedName]*Route{},
+					AcceptedHostnames: map[string]struct{}{},
+					SecretPath:        secretPath,
+				},
+				"listener-443-6": {
+					Source:            listener4436,
+					Valid:             false,
+					Routes:            map[types.NamespacedName]*Route{},
+					AcceptedHostnames: map[string]struct{}{},
+					Conditions: []conditions.Condition{
+						conditions.NewListenerPortUnavailable("Port 444 is not supported for HTTPS, use 443"),
+					},
+				},
+			},
+			name: "https listeners with the same hostname on different ports",
+		},
 		{
 			gateway: &v1beta1.Gateway{
 				ObjectMeta: metav1.ObjectMeta{
@@ -502,7 +689,7 @@ func TestBuildListeners(t *testing.T) {
 					Routes:            map[types.NamespacedName]*Route{},
 					AcceptedHostnames: map[string]struct{}{},
 					Conditions: []conditions.Condition{
-						conditions.NewListenerUnsupportedAddress("Specifying Gateway addresses is not supported"),
+						conditions.NewListenerUnsupportedAddress(`Unsupported address: "" is not a valid IP address`),
 					},
 				},
 				"listener-443-1": {
@@ -512,11 +699,125 @@ func TestBuildListeners(t *testing.T) {
 					AcceptedHostnames: map[string]struct{}{},
 					SecretPath:        "",
 					Conditions: []conditions.Condition{
-						conditions.NewListenerUnsupportedAddress("Specifying Gateway addresses is not supported"),
+						conditions.NewListenerUnsupportedAddress(`Unsupported address: "" is not a valid IP address`),
+					},
+				},
+			},
+			name: "invalid gateway address",
+		},
+		{
+			gateway: &v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "test",
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: gcName,
+					Listeners: []v1beta1.Listener{
+						listener801,
+					},
+					Addresses: []v1beta1.GatewayAddress{
+						{
+							Type:  (*v1beta1.AddressType)(helpers.GetStringPointer(string(v1beta1.IPAddressType))),
+							Value: "10.0.0.1",
+						},
+						{
+							Type:  (*v1beta1.AddressType)(helpers.GetStringPointer(string(v1beta1.HostnameAddressType))),
+							Value: "example.com",
+						},
+					},
+				},
+			},
+			expected: map[string]*Listener{
+				"listener-80-1": {
+					Source:            listener801,
+					Valid:             false,
+					Routes:            map[types.NamespacedName]*Route{},
+					AcceptedHostnames: map[string]struct{}{},
+					Conditions: []conditions.Condition{
+						conditions.NewListenerUnsupportedAddress(
+							`Unsupported address: type "Hostname" of the address "example.com" is not supported; ` +
+								`must be "IPAddress"`,
+						),
 					},
 				},
 			},
-			name: "gateway addresses are not supported",
+			name: "unsupported gateway address type",
+		},
+		{
+			gateway: &v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "test",
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: gcName,
+					Listeners: []v1beta1.Listener{
+						listener801,
+					},
+					Addresses: []v1beta1.GatewayAddress{
+						{
+							Type:  (*v1beta1.AddressType)(helpers.GetStringPointer(string(v1beta1.IPAddressType))),
+							Value: "10.0.0.1",
+						},
+						{
+							Value: "2001:db8::1",
+						},
+					},
+				},
+			},
+			expected: map[string]*Listener{
+				"listener-80-1": {
+					Source:            listener801,
+					Valid:             true,
+					Routes:            map[types.NamespacedName]*Route{},
+					AcceptedHostnames: map[string]struct{}{},
+				},
+			},
+			name: "IP gateway addresses",
+		},
+		{
+			gateway: &v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "test",
+					Annotations: map[string]string{
+						DisabledListenersAnnotation: "listener-80-4, listener-443-5,unknown,",
+					},
+				},
+				Spec: v1beta1.GatewaySpec{
+					GatewayClassName: gcName,
+					Listeners: []v1beta1.Listener{
+						listener801, listener804, listener4435,
+					},
+				},
+			},
+			expected: map[string]*Listener{
+				"listener-80-1": {
+					Source:            listener801,
+					Valid:             true,
+					Routes:            map[types.NamespacedName]*Route{},
+					AcceptedHostnames: map[string]struct{}{},
+				},
+				"listener-80-4": {
+					Source:            listener804,
+					Valid:             false,
+					Disabled:          true,
+					Routes:            map[types.NamespacedName]*Route{},
+					AcceptedHostnames: map[string]struct{}{},
+					Conditions: []conditions.Condition{
+						conditions.NewListenerDisabled(disabledMsg),
+					},
+				},
+				"listener-443-5": {
+					Source:            listener4435,
+					Valid:             false,
+					Disabled:          true,
+					Routes:            map[types.NamespacedName]*Route{},
+					AcceptedHostnames: map[string]struct{}{},
+					Conditions: []conditions.Condition{
+						conditions.NewListenerDisabled(disabledMsg),
+					},
+				},
+			},
+			name: "disabled listeners",
 		},
 		{
 			gateway:  nil,
@@ -578,6 +879,18 @@ func TestValidateHTTPListener(t *testing.T) {
 			},
 			name: "invalid port",
 		},
+		{
+			l: v1beta1.Listener{
+				Port: 80,
+				TLS: &v1beta1.GatewayTLSConfig{
+					Mode: helpers.GetTLSModePointer(v1beta1.TLSModeTerminate),
+				},
+			},
+			expected: []conditions.Condition{
+				conditions.NewListenerUnsupportedValue(`tls must not be set for protocol "HTTP"`),
+			},
+			name: "tls",
+		},
 	}
 
 	for _, test := range tests {
@@ -590,6 +903,91 @@ func TestValidateHTTPListener(t *testing.T) {
 	}
 }
 
+func TestValidateListenerTLS(t *testing.T) {
+	certRefs := []v1beta1.SecretObjectReference{{Name: "secret"}}
+
+	tests := []struct {
+		tls      *v1beta1.GatewayTLSConfig
+		name     string
+		protocol v1beta1.ProtocolType
+		expected []conditions.Condition
+	}{
+		{
+			protocol: v1beta1.HTTPProtocolType,
+			expected: nil,
+			name:     "HTTP without tls",
+		},
+		{
+			protocol: v1beta1.TCPProtocolType,
+			tls:      &v1beta1.GatewayTLSConfig{CertificateRefs: certRefs},
+			expected: []conditions.Condition{
+				conditions.NewListenerUnsupportedValue(`tls must not be set for protocol "TCP"`),
+			},
+			name: "TCP with certificateRefs",
+		},
+		{
+			protocol: v1beta1.UDPProtocolType,
+			tls:      &v1beta1.GatewayTLSConfig{},
+			expected: []conditions.Condition{
+				conditions.NewListenerUnsupportedValue(`tls must not be set for protocol "UDP"`),
+			},
+			name: "UDP with tls",
+		},
+		{
+			protocol: v1beta1.TLSProtocolType,
+			expected: []conditions.Condition{
+				conditions.NewListenerUnsupportedValue(`tls must be set for protocol "TLS"`),
+			},
+			name: "TLS without tls",
+		},
+		{
+			protocol: v1beta1.HTTPSProtocolType,
+			tls:      &v1beta1.GatewayTLSConfig{},
+			expected: conditions.NewListenerInvalidCertificateRef(
+				`tls.certificateRefs must be set for tls.mode "Terminate"`,
+			),
+			name: "HTTPS with the default mode without certificateRefs",
+		},
+		{
+			protocol: v1beta1.TLSProtocolType,
+			tls: &v1beta1.GatewayTLSConfig{
+				Mode:            helpers.GetTLSModePointer(v1beta1.TLSModePassthrough),
+				CertificateRefs: certRefs,
+			},
+			expected: []conditions.Condition{
+				conditions.NewListenerUnsupportedValue(`tls.certificateRefs must not be set for tls.mode "Passthrough"`),
+			},
+			name: "TLS passthrough with certificateRefs",
+		},
+		{
+			protocol: v1beta1.TLSProtocolType,
+			tls: &v1beta1.GatewayTLSConfig{
+				Mode: helpers.GetTLSModePointer(v1beta1.TLSModePassthrough),
+			},
+			expected: nil,
+			name:     "TLS passthrough without certificateRefs",
+		},
+		{
+			protocol: v1beta1.HTTPSProtocolType,
+			tls: &v1beta1.GatewayTLSConfig{
+				Mode:            helpers.GetTLSModePointer(v1beta1.TLSModeTerminate),
+				CertificateRefs: certRefs,
+			},
+			expected: nil,
+			name:     "HTTPS terminate with certificateRefs",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			result := validateListenerTLS(test.protocol, test.tls)
+			g.Expect(result).To(Equal(test.expected))
+		})
+	}
+}
+
 func TestValidateHTTPSListener(t *testing.T) {
 	gwNs := "gateway-ns"
 
@@ -656,10 +1054,20 @@ func TestValidateHTTPSListener(t *testing.T) {
 					Options:         map[v1beta1.AnnotationKey]v1beta1.AnnotationValue{"key": "val"},
 				},
 			},
+			expected: nil,
+			name:     "options",
+		},
+		{
+			l: v1beta1.Listener{
+				Port: 443,
+				TLS: &v1beta1.GatewayTLSConfig{
+					Mode: helpers.GetTLSModePointer(v1beta1.TLSModePassthrough),
+				},
+			},
 			expected: []conditions.Condition{
-				conditions.NewListenerUnsupportedValue("tls.options are not supported"),
+				conditions.NewListenerUnsupportedValue(`tls.mode "Passthrough" is not supported, use "Terminate"`),
 			},
-			name: "invalid options",
+			name: "invalid tls mode",
 		},
 		{
 			l: v1beta1.Listener{
@@ -670,9 +1078,41 @@ func TestValidateHTTPSListener(t *testing.T) {
 				},
 			},
 			expected: []conditions.Condition{
+				conditions.NewListenerUnsupportedValue(`tls.certificateRefs must not be set for tls.mode "Passthrough"`),
 				conditions.NewListenerUnsupportedValue(`tls.mode "Passthrough" is not supported, use "Terminate"`),
 			},
-			name: "invalid tls mode",
+			name: "invalid tls mode with certificateRefs",
+		},
+		{
+			l: v1beta1.Listener{
+				Port: 443,
+			},
+			expected: []conditions.Condition{
+				conditions.NewListenerUnsupportedValue(`tls must be set for protocol "HTTPS"`),
+			},
+			name: "no tls",
+		},
+		{
+			l: v1beta1.Listener{
+				Port: 443,
+				TLS: &v1beta1.GatewayTLSConfig{
+					Mode: helpers.GetTLSModePointer(v1beta1.TLSModeTerminate),
+				},
+			},
+			expected: conditions.NewListenerInvalidCertificateRef(
+				`tls.certificateRefs must be set for tls.mode "Terminate"`,
+			),
+			name: "no certificateRefs",
+		},
+		{
+			l: v1beta1.Listener{
+				Port: 443,
+				TLS: &v1beta1.GatewayTLSConfig{
+					CertificateRefs: []v1beta1.SecretObjectReference{validSecretRef},
+				},
+			},
+			expected: nil,
+			name:     "default tls mode",
 		},
 		{
 			l: v1beta1.Listener{
@@ -716,8 +1156,37 @@ func TestValidateHTTPSListener(t *testing.T) {
 					CertificateRefs: []v1beta1.SecretObjectReference{validSecretRef, validSecretRef},
 				},
 			},
+			expected: nil,
+			name:     "two cert refs",
+		},
+		{
+			l: v1beta1.Listener{
+				Port: 443,
+				TLS: &v1beta1.GatewayTLSConfig{
+					Mode: helpers.GetTLSModePointer(v1beta1.TLSModeTerminate),
+					CertificateRefs: []v1beta1.SecretObjectReference{
+						validSecretRef,
+						invalidSecretRefKind,
+					},
+				},
+			},
+			expected: conditions.NewListenerInvalidCertificateRef(`Kind must be Secret, got "ConfigMap"`),
+			name:     "invalid second cert ref kind",
+		},
+		{
+			l: v1beta1.Listener{
+				Port: 443,
+				TLS: &v1beta1.GatewayTLSConfig{
+					Mode: helpers.GetTLSModePointer(v1beta1.TLSModeTerminate),
+					CertificateRefs: []v1beta1.SecretObjectReference{
+						validSecretRef,
+						validSecretRef,
+						validSecretRef,
+					},
+				},
+			},
 			expected: []conditions.Condition{
-				conditions.NewListenerUnsupportedValue("Only 1 certificateRef is supported, got 2"),
+				conditions.NewListenerUnsupportedValue("Up to 2 certificateRefs are supported, got 3"),
 			},
 			name: "too many cert refs",
 		},
@@ -733,6 +1202,183 @@ func TestValidateHTTPSListener(t *testing.T) {
 	}
 }
 
+func TestLoadSecretIntoListener(t *testing.T) {
+	gw := &v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "gateway",
+		},
+	}
+
+	createListener := func(secretNames ...string) *Listener {
+		refs := make([]v1beta1.SecretObjectReference, 0, len(secretNames))
+		for _, name := range secretNames {
+			refs = append(refs, v1beta1.SecretObjectReference{Name: v1beta1.ObjectName(name)})
+		}
+
+		return &Listener{
+			Source: v1beta1.Listener{
+				Protocol: v1beta1.HTTPSProtocolType,
+				TLS: &v1beta1.GatewayTLSConfig{
+					CertificateRefs: refs,
+				},
+			},
+			Valid: true,
+		}
+	}
+
+	keyTypes := map[string]secrets.KeyType{
+		"rsa":     secrets.KeyTypeRSA,
+		"rsa-2":   secrets.KeyTypeRSA,
+		"ecdsa":   secrets.KeyTypeECDSA,
+		"ed25519": secrets.KeyTypeUnknown,
+	}
+
+	secretMemoryMgr := &secretsfakes.FakeSecretDiskMemoryManager{}
+	secretMemoryMgr.RequestCalls(func(nsname types.NamespacedName) (string, error) {
+		if _, exists := keyTypes[nsname.Name]; !exists {
+			return "", errors.New("does not exist")
+		}
+		return "/etc/nginx/secrets/test_" + nsname.Name, nil
+	})
+	secretMemoryMgr.GetKeyTypeCalls(func(nsname types.NamespacedName) (secrets.KeyType, error) {
+		return keyTypes[nsname.Name], nil
+	})
+
+	tests := []struct {
+		listener               *Listener
+		name                   string
+		expSecretPath          string
+		expSecondarySecretPath string
+		expValid               bool
+	}{
+		{
+			listener:      createListener("rsa"),
+			expSecretPath: "/etc/nginx/secrets/test_rsa",
+			expValid:      true,
+			name:          "one cert",
+		},
+		{
+			listener:               createListener("rsa", "ecdsa"),
+			expSecretPath:          "/etc/nginx/secrets/test_rsa",
+			expSecondarySecretPath: "/etc/nginx/secrets/test_ecdsa",
+			expValid:               true,
+			name:                   "rsa and ecdsa certs",
+		},
+		{
+			listener: createListener("rsa", "rsa-2"),
+			expValid: false,
+			name:     "certs with the same key type",
+		},
+		{
+			listener: createListener("rsa", "ed25519"),
+			expValid: false,
+			name:     "cert with unsupported key type",
+		},
+		{
+			listener: createListener("rsa", "dne"),
+			expValid: false,
+			name:     "second cert does not exist",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			c := newHTTPSListenerConfigurator(gw, secretMemoryMgr)
+			c.loadSecretIntoListener(test.listener)
+
+			g.Expect(test.listener.Valid).To(Equal(test.expValid))
+			g.Expect(test.listener.SecretPath).To(Equal(test.expSecretPath))
+			g.Expect(test.listener.SecondarySecretPath).To(Equal(test.expSecondarySecretPath))
+			if !test.expValid {
+				g.Expect(test.listener.Conditions).ToNot(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestLoadClientCertificateIntoListener(t *testing.T) {
+	gw := &v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "gateway",
+		},
+	}
+
+	createListener := func(options map[v1beta1.AnnotationKey]v1beta1.AnnotationValue) *Listener {
+		return &Listener{
+			Source: v1beta1.Listener{
+				Protocol: v1beta1.HTTPSProtocolType,
+				TLS: &v1beta1.GatewayTLSConfig{
+					CertificateRefs: []v1beta1.SecretObjectReference{{Name: "secret"}},
+					Options:         options,
+				},
+			},
+			Valid: true,
+		}
+	}
+
+	secretMemoryMgr := &secretsfakes.FakeSecretDiskMemoryManager{}
+	secretMemoryMgr.RequestReturns("/etc/nginx/secrets/test_secret", nil)
+	secretMemoryMgr.RequestCACalls(func(nsname types.NamespacedName) (string, error) {
+		if nsname.Name != "ca" {
+			return "", errors.New("does not exist")
+		}
+		return "/etc/nginx/secrets/test_ca_ca", nil
+	})
+
+	tests := []struct {
+		listener                 *Listener
+		name                     string
+		expSecretPath            string
+		expClientCertificatePath string
+		expConditions            []conditions.Condition
+		expValid                 bool
+	}{
+		{
+			listener:      createListener(nil),
+			expSecretPath: "/etc/nginx/secrets/test_secret",
+			expValid:      true,
+			name:          "no client certificate",
+		},
+		{
+			listener: createListener(map[v1beta1.AnnotationKey]v1beta1.AnnotationValue{
+				ClientCertificateTLSOption: "ca",
+			}),
+			expSecretPath:            "/etc/nginx/secrets/test_secret",
+			expClientCertificatePath: "/etc/nginx/secrets/test_ca_ca",
+			expValid:                 true,
+			name:                     "client certificate",
+		},
+		{
+			listener: createListener(map[v1beta1.AnnotationKey]v1beta1.AnnotationValue{
+				ClientCertificateTLSOption: "dne",
+			}),
+			expConditions: conditions.NewListenerInvalidCertificateRef(
+				"Failed to get the client CA certificate test/dne: does not exist",
+			),
+			expValid: false,
+			name:     "client certificate does not exist",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			c := newHTTPSListenerConfigurator(gw, secretMemoryMgr)
+			c.loadSecretIntoListener(test.listener)
+
+			g.Expect(test.listener.Valid).To(Equal(test.expValid))
+			g.Expect(test.listener.SecretPath).To(Equal(test.expSecretPath))
+			g.Expect(test.listener.ClientCertificatePath).To(Equal(test.expClientCertificatePath))
+			g.Expect(test.listener.Conditions).To(Equal(test.expConditions))
+		})
+	}
+}
+
 func TestValidateListenerHostname(t *testing.T) {
 	tests := []struct {
 		hostname  *v1beta1.Hostname
@@ -756,9 +1402,19 @@ func TestValidateListenerHostname(t *testing.T) {
 		},
 		{
 			hostname:  (*v1beta1.Hostname)(helpers.GetStringPointer("*.example.com")),
-			expectErr: true,
+			expectErr: false,
 			name:      "wildcard hostname",
 		},
+		{
+			hostname:  (*v1beta1.Hostname)(helpers.GetStringPointer("*.example$com")),
+			expectErr: true,
+			name:      "invalid wildcard hostname",
+		},
+		{
+			hostname:  (*v1beta1.Hostname)(helpers.GetStringPointer("foo.*.com")),
+			expectErr: true,
+			name:      "wildcard not in the first label",
+		},
 		{
 			hostname:  (*v1beta1.Hostname)(helpers.GetStringPointer("example$com")),
 			expectErr: true,
@@ -0,0 +1,114 @@
+package graph
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// GeoMatchAnnotation is the HTTPRoute annotation that scopes all rules of the HTTPRoute to the requests of the
+// clients from some countries or continents, as looked up in the GeoIP2 database of NGINX. The value has the form
+// country=<codes> or continent=<codes>, where <codes> is a comma-separated list of the ISO country codes, for example,
+// DE, or the continent codes, for example, EU. The matches of the HTTPRoute take precedence over the same matches
+// without the annotation, so that an HTTPRoute with the annotation can route the requests of such clients to other
+// backends than an HTTPRoute with the same hostnames for all other clients.
+const GeoMatchAnnotation = "k8s-gateway.nginx.org/geo-match"
+
+// Types of a GeoMatch.
+const (
+	// GeoMatchTypeCountry matches the ISO code of the country of the client.
+	GeoMatchTypeCountry = "country"
+	// GeoMatchTypeContinent matches the code of the continent of the client.
+	GeoMatchTypeContinent = "continent"
+)
+
+// countryCodeRegexp matches an ISO 3166-1 alpha-2 country code.
+var countryCodeRegexp = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// continentCodes are the continent codes of the GeoIP2 databases.
+var continentCodes = map[string]struct{}{
+	"AF": {},
+	"AN": {},
+	"AS": {},
+	"EU": {},
+	"NA": {},
+	"OC": {},
+	"SA": {},
+}
+
+// GeoMatch is the match of the country or the continent of the client configured through the GeoMatchAnnotation.
+type GeoMatch struct {
+	// Type is GeoMatchTypeCountry or GeoMatchTypeContinent.
+	Type string
+	// Codes are the sorted unique codes of the countries or the continents.
+	Codes []string
+}
+
+// String returns the same string for the equivalent GeoMatches.
+func (m GeoMatch) String() string {
+	return m.Type + "=" + strings.Join(m.Codes, ",")
+}
+
+// ParseGeoMatch parses the value of the GeoMatchAnnotation.
+func ParseGeoMatch(v string) (GeoMatch, error) {
+	matchType, list, found := strings.Cut(v, "=")
+	if !found || list == "" {
+		return GeoMatch{}, fmt.Errorf("must be in the form %s=<codes> or %s=<codes>", GeoMatchTypeCountry,
+			GeoMatchTypeContinent)
+	}
+
+	var validCode func(code string) bool
+
+	switch matchType {
+	case GeoMatchTypeCountry:
+		validCode = countryCodeRegexp.MatchString
+	case GeoMatchTypeContinent:
+		validCode = func(code string) bool {
+			_, exists := continentCodes[code]
+			return exists
+		}
+	default:
+		return GeoMatch{}, fmt.Errorf("unknown type %q; must be %s or %s", matchType, GeoMatchTypeCountry,
+			GeoMatchTypeContinent)
+	}
+
+	unique := make(map[string]struct{})
+	codes := make([]string, 0, strings.Count(list, ",")+1)
+
+	for _, code := range strings.Split(list, ",") {
+		code = strings.TrimSpace(code)
+		if !validCode(code) {
+			return GeoMatch{}, fmt.Errorf("invalid %s code %q", matchType, code)
+		}
+
+		if _, exists := unique[code]; exists {
+			continue
+		}
+		unique[code] = struct{}{}
+		codes = append(codes, code)
+	}
+
+	sort.Strings(codes)
+
+	return GeoMatch{
+		Type:  matchType,
+		Codes: codes,
+	}, nil
+}
+
+// getGeoMatchKey returns the string of the valid GeoMatch of the route, or an empty string if the route doesn't
+// have the GeoMatchAnnotation with a valid value.
+func getGeoMatchKey(r *Route) string {
+	v, exists := r.Source.Annotations[GeoMatchAnnotation]
+	if !exists {
+		return ""
+	}
+
+	m, err := ParseGeoMatch(v)
+	if err != nil {
+		return ""
+	}
+
+	return m.String()
+}
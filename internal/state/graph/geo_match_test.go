@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseGeoMatch(t *testing.T) {
+	tests := []struct {
+		value    string
+		msg      string
+		expMatch GeoMatch
+		expErr   bool
+	}{
+		{
+			value:    "country=DE",
+			expMatch: GeoMatch{Type: GeoMatchTypeCountry, Codes: []string{"DE"}},
+			msg:      "country",
+		},
+		{
+			value:    "country=DE, AT,DE",
+			expMatch: GeoMatch{Type: GeoMatchTypeCountry, Codes: []string{"AT", "DE"}},
+			msg:      "countries are sorted and deduplicated",
+		},
+		{
+			value:    "continent=NA,EU",
+			expMatch: GeoMatch{Type: GeoMatchTypeContinent, Codes: []string{"EU", "NA"}},
+			msg:      "continents",
+		},
+		{
+			value:  "continent=XX",
+			expErr: true,
+			msg:    "unknown continent",
+		},
+		{
+			value:  "country=de",
+			expErr: true,
+			msg:    "lowercase country",
+		},
+		{
+			value:  "country=DEU",
+			expErr: true,
+			msg:    "three-letter country",
+		},
+		{
+			value:  "country=DE,",
+			expErr: true,
+			msg:    "empty code",
+		},
+		{
+			value:  "country=",
+			expErr: true,
+			msg:    "no codes",
+		},
+		{
+			value:  "EU",
+			expErr: true,
+			msg:    "no type",
+		},
+		{
+			value:  "city=Berlin",
+			expErr: true,
+			msg:    "unknown type",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			m, err := ParseGeoMatch(test.value)
+			if test.expErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+			g.Expect(m).To(Equal(test.expMatch))
+		})
+	}
+}
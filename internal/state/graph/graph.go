@@ -6,6 +6,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/gateway-api/apis/v1beta1"
 
+	"github.com/nginxinc/nginx-kubernetes-gateway/apis/v1alpha1"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/secrets"
 )
 
@@ -15,6 +16,11 @@ type ClusterStore struct {
 	Gateways     map[types.NamespacedName]*v1beta1.Gateway
 	HTTPRoutes   map[types.NamespacedName]*v1beta1.HTTPRoute
 	Services     map[types.NamespacedName]*v1.Service
+	CORSPolicies map[types.NamespacedName]*v1alpha1.CORSPolicy
+	// DirectResponses holds the DirectResponse resources, which HTTPRoutes reference through ExtensionRef filters.
+	DirectResponses map[types.NamespacedName]*v1alpha1.DirectResponse
+	// Namespaces holds the Namespace resources, whose labels the namespace selectors of the listeners match.
+	Namespaces map[types.NamespacedName]*v1.Namespace
 }
 
 // Graph is a Graph-like representation of Gateway API resources.
@@ -32,27 +38,52 @@ type Graph struct {
 }
 
 // BuildGraph builds a Graph from a store.
+// maxRoutesPerListener limits the number of the routes attached to a listener. 0 means no limit.
+// externalNameAllowlist limits the external names of the ExternalName Services that the routes and the default
+// backends of the listeners can reference as backends. Empty means no limit.
+// If conformance is true, the rules of the routes that use unsupported features are not accepted instead of
+// the features being ignored.
 func BuildGraph(
 	store ClusterStore,
 	controllerName string,
 	gcName string,
 	secretMemoryMgr secrets.SecretDiskMemoryManager,
+	maxRoutesPerListener int,
+	externalNameAllowlist []string,
+	backendResolvers BackendResolvers,
+	conformance bool,
 ) *Graph {
 	gc := buildGatewayClass(store.GatewayClass, controllerName)
 
 	gw, ignoredGws := processGateways(store.Gateways, gcName)
 
 	listeners := buildListeners(gw, gcName, secretMemoryMgr)
+	addDefaultBackendsToListeners(gw, listeners, store.Services, externalNameAllowlist)
+	addRateLimitsToListeners(gw, listeners)
+	addDefaultCertificateToListeners(gw, listeners)
 
 	routes := make(map[types.NamespacedName]*Route)
 	for _, ghr := range store.HTTPRoutes {
-		ignored, r := bindHTTPRouteToListeners(ghr, gw, ignoredGws, listeners)
+		ignored, r := bindHTTPRouteToListeners(
+			applyDefaultMatches(ghr),
+			gw,
+			ignoredGws,
+			store.Gateways,
+			listeners,
+			store.Namespaces,
+		)
 		if !ignored {
 			routes[client.ObjectKeyFromObject(ghr)] = r
 		}
 	}
 
-	addBackendGroupsToRoutes(routes, store.Services)
+	validateRouteRules(routes, conformance)
+
+	limitListenerRoutes(listeners, maxRoutesPerListener)
+	resolveRouteConflicts(listeners)
+
+	addBackendGroupsToRoutes(routes, store.Services, externalNameAllowlist, backendResolvers)
+	addRuleFiltersToRoutes(routes, store.CORSPolicies, store.DirectResponses)
 
 	g := &Graph{
 		GatewayClass:    gc,
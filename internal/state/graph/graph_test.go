@@ -2,6 +2,7 @@
 package graph
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -130,8 +131,8 @@ func TestBuildGraph(t *testing.T) {
 	}
 
 	hr1 := createRoute("hr-1", "gateway-1", "listener-80-1")
-	hr2 := createRoute("hr-2", "wrong-gateway", "listener-80-1")
-	hr3 := createRoute("hr-3", "gateway-1", "listener-443-1") // https listener; should not conflict with hr1
+	hr2 := createRoute("hr-2", "wrong-gateway", "listener-80-1") // the Gateway doesn't exist
+	hr3 := createRoute("hr-3", "gateway-1", "listener-443-1")    // https listener; should not conflict with hr1
 
 	fooSvc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "test"}}
 
@@ -150,6 +151,21 @@ func TestBuildGraph(t *testing.T) {
 		},
 	}
 
+	hr2Group := BackendGroup{
+		Errors:  []string{},
+		Source:  types.NamespacedName{Namespace: hr2.Namespace, Name: hr2.Name},
+		RuleIdx: 0,
+		Backends: []BackendRef{
+			{
+				Name:   "test_foo_80",
+				Svc:    fooSvc,
+				Port:   80,
+				Valid:  true,
+				Weight: 1,
+			},
+		},
+	}
+
 	hr3Group := BackendGroup{
 		Errors:  []string{},
 		Source:  types.NamespacedName{Namespace: hr3.Namespace, Name: hr3.Name},
@@ -233,7 +249,22 @@ func TestBuildGraph(t *testing.T) {
 			"listener-80-1": {},
 		},
 		InvalidSectionNameRefs: map[string]conditions.Condition{},
-		BackendGroups:          []BackendGroup{hr1Group},
+		ParentRefs: []ParentRef{
+			{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway-1"}, SectionName: "listener-80-1"},
+		},
+		BackendGroups: []BackendGroup{hr1Group},
+		RuleFilters:   []RuleFilters{{Valid: true}},
+	}
+
+	routeHR2 := &Route{
+		Source:                 hr2,
+		ValidSectionNameRefs:   map[string]struct{}{},
+		InvalidSectionNameRefs: map[string]conditions.Condition{},
+		ParentRefs: []ParentRef{
+			{Gateway: types.NamespacedName{Namespace: "test", Name: "wrong-gateway"}, SectionName: "listener-80-1"},
+		},
+		BackendGroups: []BackendGroup{hr2Group},
+		RuleFilters:   []RuleFilters{{Valid: true}},
 	}
 
 	routeHR3 := &Route{
@@ -242,7 +273,11 @@ func TestBuildGraph(t *testing.T) {
 			"listener-443-1": {},
 		},
 		InvalidSectionNameRefs: map[string]conditions.Condition{},
-		BackendGroups:          []BackendGroup{hr3Group},
+		ParentRefs: []ParentRef{
+			{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway-1"}, SectionName: "listener-443-1"},
+		},
+		BackendGroups: []BackendGroup{hr3Group},
+		RuleFilters:   []RuleFilters{{Valid: true}},
 	}
 
 	// add test secret to store
@@ -286,12 +321,120 @@ func TestBuildGraph(t *testing.T) {
 		},
 		Routes: map[types.NamespacedName]*Route{
 			{Namespace: "test", Name: "hr-1"}: routeHR1,
+			{Namespace: "test", Name: "hr-2"}: routeHR2,
 			{Namespace: "test", Name: "hr-3"}: routeHR3,
 		},
 	}
 
-	result := BuildGraph(store, controllerName, gcName, secretMemoryMgr)
+	result := BuildGraph(store, controllerName, gcName, secretMemoryMgr, 0, nil, nil, false)
 	if diff := cmp.Diff(expected, result); diff != "" {
 		t.Errorf("BuildGraph() mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func BenchmarkBuildGraph(b *testing.B) {
+	const (
+		gcName         = "my-class"
+		controllerName = "my.controller"
+	)
+
+	createStore := func(routeCount int) ClusterStore {
+		store := ClusterStore{
+			GatewayClass: &v1beta1.GatewayClass{
+				Spec: v1beta1.GatewayClassSpec{
+					ControllerName: controllerName,
+				},
+			},
+			Gateways: map[types.NamespacedName]*v1beta1.Gateway{
+				{Namespace: "test", Name: "gateway"}: {
+					ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "gateway"},
+					Spec: v1beta1.GatewaySpec{
+						GatewayClassName: gcName,
+						Listeners: []v1beta1.Listener{
+							{
+								Name:     "http",
+								Port:     80,
+								Protocol: v1beta1.HTTPProtocolType,
+							},
+						},
+					},
+				},
+			},
+			HTTPRoutes: make(map[types.NamespacedName]*v1beta1.HTTPRoute, routeCount),
+			Services:   make(map[types.NamespacedName]*v1.Service, routeCount),
+		}
+
+		for i := 0; i < routeCount; i++ {
+			name := fmt.Sprintf("app-%d", i)
+
+			createBackendRefs := func(port int32) []v1beta1.HTTPBackendRef {
+				return []v1beta1.HTTPBackendRef{
+					{
+						BackendRef: v1beta1.BackendRef{
+							BackendObjectReference: v1beta1.BackendObjectReference{
+								Name: v1beta1.ObjectName(name),
+								Port: (*v1beta1.PortNumber)(helpers.GetInt32Pointer(port)),
+							},
+						},
+					},
+				}
+			}
+
+			store.HTTPRoutes[types.NamespacedName{Namespace: "test", Name: name}] = &v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: name},
+				Spec: v1beta1.HTTPRouteSpec{
+					CommonRouteSpec: v1beta1.CommonRouteSpec{
+						ParentRefs: []v1beta1.ParentReference{
+							{
+								Name:        "gateway",
+								SectionName: (*v1beta1.SectionName)(helpers.GetStringPointer("http")),
+							},
+						},
+					},
+					Hostnames: []v1beta1.Hostname{v1beta1.Hostname(name + ".example.com")},
+					Rules: []v1beta1.HTTPRouteRule{
+						{
+							BackendRefs: createBackendRefs(80),
+						},
+						{
+							Matches: []v1beta1.HTTPRouteMatch{
+								{
+									Path: &v1beta1.HTTPPathMatch{Value: helpers.GetStringPointer("/api")},
+									Headers: []v1beta1.HTTPHeaderMatch{
+										{
+											Type:  helpers.GetHeaderMatchTypePointer(v1beta1.HeaderMatchExact),
+											Name:  "version",
+											Value: "v2",
+										},
+									},
+								},
+							},
+							BackendRefs: createBackendRefs(8080),
+						},
+					},
+				},
+			}
+
+			store.Services[types.NamespacedName{Namespace: "test", Name: name}] = &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: name},
+			}
+		}
+
+		return store
+	}
+
+	for _, count := range []int{10, 100, 1000} {
+		store := createStore(count)
+
+		b.Run(fmt.Sprintf("%d routes", count), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				g := BuildGraph(store, controllerName, gcName, nil, 0, nil, nil, false)
+				if len(g.Gateway.Listeners["http"].Routes) != count {
+					b.Fatalf("expected %d routes, got %d", count, len(g.Gateway.Listeners["http"].Routes))
+				}
+			}
+		})
+	}
+}
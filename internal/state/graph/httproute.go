@@ -1,10 +1,18 @@
 package graph
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/gateway-api/apis/v1beta1"
 
+	nkgsort "github.com/nginxinc/nginx-kubernetes-gateway/internal/sort"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/conditions"
 )
 
@@ -22,11 +30,100 @@ type Route struct {
 	// InvalidSectionNameRefs includes the sectionNames from the parentRefs of the HTTPRoute that are invalid.
 	// The Condition describes why the sectionName is invalid.
 	InvalidSectionNameRefs map[string]conditions.Condition
+	// ParentRefs includes the parentRefs of the HTTPRoute that reference the winning Gateway, an ignored Gateway
+	// or a Gateway that doesn't exist, in the order of the parentRefs in the HTTPRoute.
+	// Each of them gets its own status.
+	ParentRefs []ParentRef
 	// BackendGroups includes the backend groups of the HTTPRoute.
 	// There's one BackendGroup per rule in the HTTPRoute.
 	// The BackendGroups are stored in order of the rules.
 	// Ex: Source.Spec.Rules[0] -> BackendGroups[0].
 	BackendGroups []BackendGroup
+	// RuleFilters includes the resolved ExtensionRef filters of the HTTPRoute.
+	// There's one RuleFilters per rule in the HTTPRoute, stored in order of the rules.
+	RuleFilters []RuleFilters
+	// InvalidRules includes the indexes of the rules of the HTTPRoute that are not accepted, because their matches
+	// are invalid. Such rules are not configured in NGINX.
+	InvalidRules map[int]struct{}
+	// Conditions includes the conditions that apply to all parentRefs of the HTTPRoute.
+	Conditions []conditions.Condition
+}
+
+// ParentRef is a parentRef of an HTTPRoute.
+type ParentRef struct {
+	// Gateway is the namespaced name of the Gateway that the parentRef references.
+	Gateway types.NamespacedName
+	// SectionName is the sectionName of the parentRef.
+	SectionName string
+}
+
+// defaultPathMatchValue is the value of the path match that the Gateway API implies when a rule has no matches
+// or a match has no path.
+const defaultPathMatchValue = "/"
+
+// applyDefaultMatches returns the HTTPRoute with the matches that the Gateway API implies: a rule with no matches
+// gets a PathPrefix / match, and a match with no path gets a PathPrefix / path.
+// Usually, the defaults are set by the API server. However, they're missing when the CRDs are installed without
+// the defaults, so NKG sets them too.
+// If the HTTPRoute needs defaults, a copy is returned, so that the resource in the store is not modified.
+// The copy is shallow, except for the rules and their matches: the other fields are shared with the resource in
+// the store and must not be modified either.
+func applyDefaultMatches(ghr *v1beta1.HTTPRoute) *v1beta1.HTTPRoute {
+	if !needsDefaultMatches(ghr) {
+		return ghr
+	}
+
+	defaulted := *ghr
+	defaulted.Spec.Rules = make([]v1beta1.HTTPRouteRule, len(ghr.Spec.Rules))
+	copy(defaulted.Spec.Rules, ghr.Spec.Rules)
+
+	for i := range defaulted.Spec.Rules {
+		rule := &defaulted.Spec.Rules[i]
+
+		if len(rule.Matches) == 0 {
+			rule.Matches = []v1beta1.HTTPRouteMatch{{Path: createDefaultPathMatch()}}
+			continue
+		}
+
+		matches := make([]v1beta1.HTTPRouteMatch, len(rule.Matches))
+		copy(matches, rule.Matches)
+
+		for j := range matches {
+			if matches[j].Path == nil {
+				matches[j].Path = createDefaultPathMatch()
+			}
+		}
+
+		rule.Matches = matches
+	}
+
+	return &defaulted
+}
+
+func needsDefaultMatches(ghr *v1beta1.HTTPRoute) bool {
+	for _, rule := range ghr.Spec.Rules {
+		if len(rule.Matches) == 0 {
+			return true
+		}
+
+		for _, m := range rule.Matches {
+			if m.Path == nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func createDefaultPathMatch() *v1beta1.HTTPPathMatch {
+	pathType := v1beta1.PathMatchPathPrefix
+	value := defaultPathMatchValue
+
+	return &v1beta1.HTTPPathMatch{
+		Type:  &pathType,
+		Value: &value,
+	}
 }
 
 // bindHTTPRouteToListeners tries to bind an HTTPRoute to listener.
@@ -34,11 +131,16 @@ type Route struct {
 // (1) HTTPRoute will be ignored.
 // (2) HTTPRoute will be processed but not bound.
 // (3) HTTPRoute will be processed and bound to a listener.
+// gws includes all Gateway resources, so that the parentRefs that reference Gateways that don't exist
+// can be reported in the status of the HTTPRoute.
+// namespaces includes the Namespace resources, whose labels the namespace selectors of the listeners match.
 func bindHTTPRouteToListeners(
 	ghr *v1beta1.HTTPRoute,
 	gw *v1beta1.Gateway,
 	ignoredGws map[types.NamespacedName]*v1beta1.Gateway,
+	gws map[types.NamespacedName]*v1beta1.Gateway,
 	listeners map[string]*Listener,
+	namespaces map[types.NamespacedName]*v1.Namespace,
 ) (ignored bool, r *Route) {
 	if len(ghr.Spec.ParentRefs) == 0 {
 		// ignore HTTPRoute without refs
@@ -51,9 +153,8 @@ func bindHTTPRouteToListeners(
 		InvalidSectionNameRefs: make(map[string]conditions.Condition),
 	}
 
-	// FIXME (pleshakov) Handle the case when parent refs are duplicated
-
 	processed := false
+	seen := make(map[ParentRef]struct{})
 
 	for _, p := range ghr.Spec.ParentRefs {
 		// FIXME(pleshakov) Support empty section name
@@ -61,6 +162,10 @@ func bindHTTPRouteToListeners(
 			continue
 		}
 
+		if !isGatewayParentRef(p) {
+			continue
+		}
+
 		// if the namespace is missing, assume the namespace of the HTTPRoute
 		ns := ghr.Namespace
 		if p.Namespace != nil {
@@ -69,7 +174,16 @@ func bindHTTPRouteToListeners(
 
 		name := string(*p.SectionName)
 
-		// Below we will figure out what Gateway resource the parentRef references and act accordingly. There are 3 cases.
+		key := types.NamespacedName{Namespace: ns, Name: string(p.Name)}
+
+		ref := ParentRef{Gateway: key, SectionName: name}
+		if _, exists := seen[ref]; exists {
+			// a duplicate parentRef shares the status of the first one
+			continue
+		}
+		seen[ref] = struct{}{}
+
+		// Below we will figure out what Gateway resource the parentRef references and act accordingly. There are 4 cases.
 
 		// Case 1: the parentRef references the winning Gateway.
 
@@ -78,9 +192,7 @@ func bindHTTPRouteToListeners(
 			// Find a listener
 
 			// FIXME(pleshakov)
-			// For now, let's do simple matching.
-			// However, we need to also support wildcard matching.
-			// More over, we need to handle cases when a Route host matches multiple HTTP listeners on the same port when
+			// We need to handle cases when a Route host matches multiple HTTP listeners on the same port when
 			// sectionName is empty and only choose one listener.
 			// For example:
 			// - Route with host foo.example.com;
@@ -89,12 +201,18 @@ func bindHTTPRouteToListeners(
 			// In this case, the Route host foo.example.com should choose listener 1, as it is a more specific match.
 
 			processed = true
+			r.ParentRefs = append(r.ParentRefs, ref)
 
 			l, exists := listeners[name]
 			if !exists {
-				// FIXME(pleshakov): Add a proper condition once it is available in the Gateway API.
-				// https://github.com/nginxinc/nginx-kubernetes-gateway/issues/306
-				r.InvalidSectionNameRefs[name] = conditions.NewTODO("listener is not found")
+				r.InvalidSectionNameRefs[name] = conditions.NewRouteNoMatchingParent(
+					"Listener is not found for this parent ref",
+				)
+				continue
+			}
+
+			if l.Disabled {
+				r.InvalidSectionNameRefs[name] = conditions.NewRouteListenerDisabled()
 				continue
 			}
 
@@ -103,6 +221,11 @@ func bindHTTPRouteToListeners(
 				continue
 			}
 
+			if msg, allowed := isRouteAllowedByListener(ghr, gw, l.Source.AllowedRoutes, namespaces); !allowed {
+				r.InvalidSectionNameRefs[name] = conditions.NewRouteNotAllowedByListeners(msg)
+				continue
+			}
+
 			accepted := findAcceptedHostnames(l.Source.Hostname, ghr.Spec.Hostnames)
 
 			if len(accepted) > 0 {
@@ -120,18 +243,23 @@ func bindHTTPRouteToListeners(
 
 		// Case 2: the parentRef references an ignored Gateway resource.
 
-		key := types.NamespacedName{Namespace: ns, Name: string(p.Name)}
-
 		if _, exist := ignoredGws[key]; exist {
-			// FIXME(pleshakov): Add a proper condition.
-			// https://github.com/nginxinc/nginx-kubernetes-gateway/issues/306
-			r.InvalidSectionNameRefs[name] = conditions.NewTODO("Gateway is ignored")
+			r.ParentRefs = append(r.ParentRefs, ref)
+
+			processed = true
+			continue
+		}
+
+		// Case 3: the parentRef references a Gateway resource that doesn't exist.
+
+		if _, exist := gws[key]; !exist {
+			r.ParentRefs = append(r.ParentRefs, ref)
 
 			processed = true
 			continue
 		}
 
-		// Case 3: the parentRef references some unrelated to this NGINX Gateway Gateway or other resource.
+		// Case 4: the parentRef references some unrelated to this NGINX Gateway Gateway.
 
 		// Do nothing
 	}
@@ -143,27 +271,175 @@ func bindHTTPRouteToListeners(
 	return false, r
 }
 
-func findAcceptedHostnames(listenerHostname *v1beta1.Hostname, routeHostnames []v1beta1.Hostname) []string {
-	hostname := getHostname(listenerHostname)
+// limitListenerRoutes detaches the routes over maxRoutes from every listener. The listener keeps the routes
+// that come first according to the Gateway API conflict resolution guidelines, so that the result doesn't depend
+// on the order in which the routes were bound. The detached routes are not accepted for that listener.
+// The accepted hostnames of a listener with detached routes are recomputed from the routes it keeps.
+// 0 maxRoutes means no limit.
+func limitListenerRoutes(listeners map[string]*Listener, maxRoutes int) {
+	if maxRoutes <= 0 {
+		return
+	}
 
-	match := func(h v1beta1.Hostname) bool {
-		if hostname == "" {
-			return true
+	for name, l := range listeners {
+		if len(l.Routes) <= maxRoutes {
+			continue
+		}
+
+		routes := make([]*Route, 0, len(l.Routes))
+		for _, r := range l.Routes {
+			routes = append(routes, r)
+		}
+
+		sort.Slice(routes, func(i, j int) bool {
+			return nkgsort.LessObjectMeta(&routes[i].Source.ObjectMeta, &routes[j].Source.ObjectMeta)
+		})
+
+		for _, r := range routes[maxRoutes:] {
+			delete(l.Routes, client.ObjectKeyFromObject(r.Source))
+			delete(r.ValidSectionNameRefs, name)
+			r.InvalidSectionNameRefs[name] = conditions.NewRouteTooManyRoutes(maxRoutes)
+		}
+
+		l.AcceptedHostnames = make(map[string]struct{})
+		for _, r := range routes[:maxRoutes] {
+			for _, h := range findAcceptedHostnames(l.Source.Hostname, r.Source.Spec.Hostnames) {
+				l.AcceptedHostnames[h] = struct{}{}
+			}
+		}
+	}
+}
+
+// isGatewayParentRef returns true if the parentRef references a Gateway resource, which is the default kind
+// of the parentRefs.
+func isGatewayParentRef(p v1beta1.ParentReference) bool {
+	if p.Group != nil && *p.Group != v1beta1.GroupName {
+		return false
+	}
+
+	return p.Kind == nil || *p.Kind == "Gateway"
+}
+
+// isRouteAllowedByListener returns true if the allowedRoutes of the listener allow the HTTPRoute to attach to
+// the listener. If not, it also returns the message that explains why.
+func isRouteAllowedByListener(
+	hr *v1beta1.HTTPRoute,
+	gw *v1beta1.Gateway,
+	allowedRoutes *v1beta1.AllowedRoutes,
+	namespaces map[types.NamespacedName]*v1.Namespace,
+) (msg string, allowed bool) {
+	if allowedRoutes == nil {
+		// the default allowedRoutes only allow the routes from the namespace of the Gateway
+		allowedRoutes = &v1beta1.AllowedRoutes{}
+	}
+
+	if len(allowedRoutes.Kinds) > 0 {
+		kindAllowed := false
+
+		for _, k := range allowedRoutes.Kinds {
+			if (k.Group == nil || *k.Group == v1beta1.GroupName) && k.Kind == "HTTPRoute" {
+				kindAllowed = true
+				break
+			}
+		}
+
+		if !kindAllowed {
+			return "Listener doesn't allow HTTPRoutes", false
+		}
+	}
+
+	from := v1beta1.NamespacesFromSame
+	if allowedRoutes.Namespaces != nil && allowedRoutes.Namespaces.From != nil {
+		from = *allowedRoutes.Namespaces.From
+	}
+
+	switch from {
+	case v1beta1.NamespacesFromAll:
+		return "", true
+	case v1beta1.NamespacesFromSame:
+		if hr.Namespace == gw.Namespace {
+			return "", true
+		}
+
+		return "Listener only allows routes from the namespace of the Gateway", false
+	case v1beta1.NamespacesFromSelector:
+		if allowedRoutes.Namespaces.Selector == nil {
+			return "Listener allows routes from the namespaces selected by a selector, but the selector is not set",
+				false
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(allowedRoutes.Namespaces.Selector)
+		if err != nil {
+			return fmt.Sprintf("Listener has an invalid namespace selector: %v", err), false
+		}
+
+		ns, exists := namespaces[types.NamespacedName{Name: hr.Namespace}]
+		if !exists || !selector.Matches(labels.Set(ns.Labels)) {
+			return "Listener only allows routes from the namespaces selected by its selector", false
 		}
-		return string(h) == hostname
+
+		return "", true
+	default:
+		return fmt.Sprintf("Listener allows routes from namespaces %q, which is not supported", from), false
 	}
+}
+
+func findAcceptedHostnames(listenerHostname *v1beta1.Hostname, routeHostnames []v1beta1.Hostname) []string {
+	hostname := getHostname(listenerHostname)
 
 	var result []string
+	seen := make(map[string]struct{})
 
 	for _, h := range routeHostnames {
-		if match(h) {
-			result = append(result, string(h))
+		accepted := string(h)
+
+		if hostname != "" {
+			var ok bool
+			if accepted, ok = intersectHostnames(hostname, accepted); !ok {
+				continue
+			}
 		}
+
+		if _, exist := seen[accepted]; exist {
+			continue
+		}
+
+		seen[accepted] = struct{}{}
+		result = append(result, accepted)
 	}
 
 	return result
 }
 
+// intersectHostnames returns the most specific hostname matched by both the listener and the route hostnames.
+// As per the Gateway API spec, a wildcard hostname (*.example.com) matches hostnames with any number of
+// additional labels (foo.example.com, foo.bar.example.com), but not the hostname without the wildcard
+// (example.com).
+func intersectHostnames(listenerHostname, routeHostname string) (string, bool) {
+	switch {
+	case listenerHostname == routeHostname:
+		return listenerHostname, true
+	case hostnameMatchesWildcard(routeHostname, listenerHostname):
+		return routeHostname, true
+	case hostnameMatchesWildcard(listenerHostname, routeHostname):
+		return listenerHostname, true
+	}
+
+	return "", false
+}
+
+// hostnameMatchesWildcard returns true if the hostname (which can be a wildcard hostname too) is matched by the
+// wildcard hostname.
+func hostnameMatchesWildcard(hostname, wildcard string) bool {
+	if !strings.HasPrefix(wildcard, "*.") {
+		return false
+	}
+
+	// The hostname must have at least one more character than the suffix of the wildcard (.example.com),
+	// so that a.example.com matches *.example.com, but example.com doesn't.
+	return strings.HasSuffix(hostname, wildcard[1:]) && len(hostname) > len(wildcard)-1
+}
+
 func getHostname(h *v1beta1.Hostname) string {
 	if h == nil {
 		return ""
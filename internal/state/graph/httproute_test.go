@@ -2,10 +2,14 @@ package graph
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
@@ -64,6 +68,51 @@ func TestBindRouteToListeners(t *testing.T) {
 		SectionName: (*v1beta1.SectionName)(helpers.GetStringPointer("listener-80-1")),
 	})
 
+	hrMissingGateway := createRoute(
+		"foo.example.com",
+		v1beta1.ParentReference{
+			Namespace:   (*v1beta1.Namespace)(helpers.GetStringPointer("test")),
+			Name:        "missing-gateway",
+			SectionName: (*v1beta1.SectionName)(helpers.GetStringPointer("listener-80-1")),
+		},
+		v1beta1.ParentReference{
+			Namespace:   (*v1beta1.Namespace)(helpers.GetStringPointer("test")),
+			Name:        "gateway",
+			SectionName: (*v1beta1.SectionName)(helpers.GetStringPointer("listener-80-1")),
+		},
+		// duplicate parentRef
+		v1beta1.ParentReference{
+			Name:        "gateway",
+			SectionName: (*v1beta1.SectionName)(helpers.GetStringPointer("listener-80-1")),
+		},
+		// not a Gateway
+		v1beta1.ParentReference{
+			Kind:        (*v1beta1.Kind)(helpers.GetStringPointer("Service")),
+			Name:        "svc",
+			SectionName: (*v1beta1.SectionName)(helpers.GetStringPointer("http")),
+		},
+	)
+
+	hrTwoGateways := createRoute(
+		"foo.example.com",
+		v1beta1.ParentReference{
+			Namespace:   (*v1beta1.Namespace)(helpers.GetStringPointer("test")),
+			Name:        "ignored-gateway",
+			SectionName: (*v1beta1.SectionName)(helpers.GetStringPointer("listener-80-1")),
+		},
+		v1beta1.ParentReference{
+			Namespace:   (*v1beta1.Namespace)(helpers.GetStringPointer("test")),
+			Name:        "gateway",
+			SectionName: (*v1beta1.SectionName)(helpers.GetStringPointer("listener-80-1")),
+		},
+	)
+
+	hrOtherNamespaceGateway := createRoute("foo.example.com", v1beta1.ParentReference{
+		Namespace:   (*v1beta1.Namespace)(helpers.GetStringPointer("other")),
+		Name:        "gateway",
+		SectionName: (*v1beta1.SectionName)(helpers.GetStringPointer("listener-80-1")),
+	})
+
 	// we create a new listener each time because the function under test can modify it
 	createListener := func() *Listener {
 		return &Listener{
@@ -89,11 +138,65 @@ func TestBindRouteToListeners(t *testing.T) {
 		},
 	}
 
+	gwOtherNamespace := &v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "other",
+			Name:      "gateway",
+		},
+	}
+
+	createListenerWithAllowedRoutes := func(allowedRoutes *v1beta1.AllowedRoutes) *Listener {
+		return createModifiedListener(func(l *Listener) {
+			l.Source.AllowedRoutes = allowedRoutes
+		})
+	}
+
+	otherNamespaceParentRefs := []ParentRef{
+		{Gateway: types.NamespacedName{Namespace: "other", Name: "gateway"}, SectionName: "listener-80-1"},
+	}
+
+	createNotAllowedRoute := func(msg string) *Route {
+		return &Route{
+			Source:               hrOtherNamespaceGateway,
+			ValidSectionNameRefs: map[string]struct{}{},
+			InvalidSectionNameRefs: map[string]conditions.Condition{
+				"listener-80-1": conditions.NewRouteNotAllowedByListeners(msg),
+			},
+			ParentRefs: otherNamespaceParentRefs,
+		}
+	}
+
+	allowedFromAll := &v1beta1.AllowedRoutes{
+		Namespaces: &v1beta1.RouteNamespaces{
+			From: (*v1beta1.FromNamespaces)(helpers.GetStringPointer(string(v1beta1.NamespacesFromAll))),
+		},
+	}
+
+	allowedFromSelector := &v1beta1.AllowedRoutes{
+		Namespaces: &v1beta1.RouteNamespaces{
+			From: (*v1beta1.FromNamespaces)(helpers.GetStringPointer(string(v1beta1.NamespacesFromSelector))),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"env": "prod"},
+			},
+		},
+	}
+
+	createNamespace := func(labels map[string]string) *v1.Namespace {
+		return &v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "test",
+				Labels: labels,
+			},
+		}
+	}
+
 	tests := []struct {
 		httpRoute         *v1beta1.HTTPRoute
 		gw                *v1beta1.Gateway
 		ignoredGws        map[types.NamespacedName]*v1beta1.Gateway
+		gws               map[types.NamespacedName]*v1beta1.Gateway
 		listeners         map[string]*Listener
+		namespaces        map[types.NamespacedName]*v1.Namespace
 		expectedRoute     *Route
 		expectedListeners map[string]*Listener
 		msg               string
@@ -121,6 +224,9 @@ func TestBindRouteToListeners(t *testing.T) {
 			}),
 			gw:         gw,
 			ignoredGws: nil,
+			gws: map[types.NamespacedName]*v1beta1.Gateway{
+				{Namespace: "test", Name: "some-gateway"}: {},
+			},
 			listeners: map[string]*Listener{
 				"listener-80-1": createListener(),
 			},
@@ -143,7 +249,10 @@ func TestBindRouteToListeners(t *testing.T) {
 				Source:               hrNonExistingSectionName,
 				ValidSectionNameRefs: map[string]struct{}{},
 				InvalidSectionNameRefs: map[string]conditions.Condition{
-					"listener-80-2": conditions.NewTODO("listener is not found"),
+					"listener-80-2": conditions.NewRouteNoMatchingParent("Listener is not found for this parent ref"),
+				},
+				ParentRefs: []ParentRef{
+					{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway"}, SectionName: "listener-80-2"},
 				},
 			},
 			expectedListeners: map[string]*Listener{
@@ -179,6 +288,9 @@ func TestBindRouteToListeners(t *testing.T) {
 					"listener-80-1": {},
 				},
 				InvalidSectionNameRefs: map[string]conditions.Condition{},
+				ParentRefs: []ParentRef{
+					{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway"}, SectionName: "listener-80-1"},
+				},
 			},
 			expectedListeners: map[string]*Listener{
 				"listener-80-1": createModifiedListener(func(l *Listener) {
@@ -189,6 +301,9 @@ func TestBindRouteToListeners(t *testing.T) {
 								"listener-80-1": {},
 							},
 							InvalidSectionNameRefs: map[string]conditions.Condition{},
+							ParentRefs: []ParentRef{
+								{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway"}, SectionName: "listener-80-1"},
+							},
 						},
 					}
 					l.AcceptedHostnames = map[string]struct{}{
@@ -212,6 +327,9 @@ func TestBindRouteToListeners(t *testing.T) {
 					"listener-80-1": {},
 				},
 				InvalidSectionNameRefs: map[string]conditions.Condition{},
+				ParentRefs: []ParentRef{
+					{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway"}, SectionName: "listener-80-1"},
+				},
 			},
 			expectedListeners: map[string]*Listener{
 				"listener-80-1": createModifiedListener(func(l *Listener) {
@@ -222,6 +340,9 @@ func TestBindRouteToListeners(t *testing.T) {
 								"listener-80-1": {},
 							},
 							InvalidSectionNameRefs: map[string]conditions.Condition{},
+							ParentRefs: []ParentRef{
+								{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway"}, SectionName: "listener-80-1"},
+							},
 						},
 					}
 					l.AcceptedHostnames = map[string]struct{}{
@@ -245,6 +366,9 @@ func TestBindRouteToListeners(t *testing.T) {
 				InvalidSectionNameRefs: map[string]conditions.Condition{
 					"listener-80-1": conditions.NewRouteNoMatchingListenerHostname(),
 				},
+				ParentRefs: []ParentRef{
+					{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway"}, SectionName: "listener-80-1"},
+				},
 			},
 			expectedListeners: map[string]*Listener{
 				"listener-80-1": createListener(),
@@ -262,10 +386,11 @@ func TestBindRouteToListeners(t *testing.T) {
 			},
 			expectedIgnored: false,
 			expectedRoute: &Route{
-				Source:               hrIgnoredGateway,
-				ValidSectionNameRefs: map[string]struct{}{},
-				InvalidSectionNameRefs: map[string]conditions.Condition{
-					"listener-80-1": conditions.NewTODO("Gateway is ignored"),
+				Source:                 hrIgnoredGateway,
+				ValidSectionNameRefs:   map[string]struct{}{},
+				InvalidSectionNameRefs: map[string]conditions.Condition{},
+				ParentRefs: []ParentRef{
+					{Gateway: types.NamespacedName{Namespace: "test", Name: "ignored-gateway"}, SectionName: "listener-80-1"},
 				},
 			},
 			expectedListeners: map[string]*Listener{
@@ -274,12 +399,19 @@ func TestBindRouteToListeners(t *testing.T) {
 			msg: "HTTPRoute with ignored gateway reference",
 		},
 		{
-			httpRoute:         hrFoo,
-			gw:                nil,
-			ignoredGws:        nil,
-			listeners:         nil,
-			expectedIgnored:   true,
-			expectedRoute:     nil,
+			httpRoute:       hrFoo,
+			gw:              nil,
+			ignoredGws:      nil,
+			listeners:       nil,
+			expectedIgnored: false,
+			expectedRoute: &Route{
+				Source:                 hrFoo,
+				ValidSectionNameRefs:   map[string]struct{}{},
+				InvalidSectionNameRefs: map[string]conditions.Condition{},
+				ParentRefs: []ParentRef{
+					{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway"}, SectionName: "listener-80-1"},
+				},
+			},
 			expectedListeners: nil,
 			msg:               "HTTPRoute when no gateway exists",
 		},
@@ -299,6 +431,9 @@ func TestBindRouteToListeners(t *testing.T) {
 				InvalidSectionNameRefs: map[string]conditions.Condition{
 					"listener-80-1": conditions.NewRouteInvalidListener(),
 				},
+				ParentRefs: []ParentRef{
+					{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway"}, SectionName: "listener-80-1"},
+				},
 			},
 			expectedListeners: map[string]*Listener{
 				"listener-80-1": createModifiedListener(func(l *Listener) {
@@ -307,10 +442,316 @@ func TestBindRouteToListeners(t *testing.T) {
 			},
 			msg: "HTTPRoute with invalid listener parentRef",
 		},
+		{
+			httpRoute:  hrFoo,
+			gw:         gw,
+			ignoredGws: nil,
+			listeners: map[string]*Listener{
+				"listener-80-1": createModifiedListener(func(l *Listener) {
+					l.Valid = false
+					l.Disabled = true
+				}),
+			},
+			expectedIgnored: false,
+			expectedRoute: &Route{
+				Source:               hrFoo,
+				ValidSectionNameRefs: map[string]struct{}{},
+				InvalidSectionNameRefs: map[string]conditions.Condition{
+					"listener-80-1": conditions.NewRouteListenerDisabled(),
+				},
+				ParentRefs: []ParentRef{
+					{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway"}, SectionName: "listener-80-1"},
+				},
+			},
+			expectedListeners: map[string]*Listener{
+				"listener-80-1": createModifiedListener(func(l *Listener) {
+					l.Valid = false
+					l.Disabled = true
+				}),
+			},
+			msg: "HTTPRoute with disabled listener parentRef",
+		},
+		{
+			httpRoute: hrMissingGateway,
+			gw:        gw,
+			gws: map[types.NamespacedName]*v1beta1.Gateway{
+				{Namespace: "test", Name: "gateway"}: gw,
+			},
+			listeners: map[string]*Listener{
+				"listener-80-1": createListener(),
+			},
+			expectedIgnored: false,
+			expectedRoute: &Route{
+				Source: hrMissingGateway,
+				ValidSectionNameRefs: map[string]struct{}{
+					"listener-80-1": {},
+				},
+				InvalidSectionNameRefs: map[string]conditions.Condition{},
+				ParentRefs: []ParentRef{
+					{Gateway: types.NamespacedName{Namespace: "test", Name: "missing-gateway"}, SectionName: "listener-80-1"},
+					{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway"}, SectionName: "listener-80-1"},
+				},
+			},
+			expectedListeners: map[string]*Listener{
+				"listener-80-1": createModifiedListener(func(l *Listener) {
+					l.Routes = map[types.NamespacedName]*Route{
+						{Namespace: "test", Name: "hr-1"}: {
+							Source: hrMissingGateway,
+							ValidSectionNameRefs: map[string]struct{}{
+								"listener-80-1": {},
+							},
+							InvalidSectionNameRefs: map[string]conditions.Condition{},
+							ParentRefs: []ParentRef{
+								{
+									Gateway:     types.NamespacedName{Namespace: "test", Name: "missing-gateway"},
+									SectionName: "listener-80-1",
+								},
+								{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway"}, SectionName: "listener-80-1"},
+							},
+						},
+					}
+					l.AcceptedHostnames = map[string]struct{}{
+						"foo.example.com": {},
+					}
+				}),
+			},
+			msg: "HTTPRoute with parentRefs to a missing gateway, duplicate and non-Gateway parentRefs",
+		},
+		{
+			httpRoute: hrTwoGateways,
+			gw:        gw,
+			ignoredGws: map[types.NamespacedName]*v1beta1.Gateway{
+				{Namespace: "test", Name: "ignored-gateway"}: {},
+			},
+			listeners: map[string]*Listener{
+				"listener-80-1": createListener(),
+			},
+			expectedIgnored: false,
+			expectedRoute: &Route{
+				Source: hrTwoGateways,
+				ValidSectionNameRefs: map[string]struct{}{
+					"listener-80-1": {},
+				},
+				InvalidSectionNameRefs: map[string]conditions.Condition{},
+				ParentRefs: []ParentRef{
+					{Gateway: types.NamespacedName{Namespace: "test", Name: "ignored-gateway"}, SectionName: "listener-80-1"},
+					{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway"}, SectionName: "listener-80-1"},
+				},
+			},
+			expectedListeners: map[string]*Listener{
+				"listener-80-1": createModifiedListener(func(l *Listener) {
+					l.Routes = map[types.NamespacedName]*Route{
+						{Namespace: "test", Name: "hr-1"}: {
+							Source: hrTwoGateways,
+							ValidSectionNameRefs: map[string]struct{}{
+								"listener-80-1": {},
+							},
+							InvalidSectionNameRefs: map[string]conditions.Condition{},
+							ParentRefs: []ParentRef{
+								{
+									Gateway:     types.NamespacedName{Namespace: "test", Name: "ignored-gateway"},
+									SectionName: "listener-80-1",
+								},
+								{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway"}, SectionName: "listener-80-1"},
+							},
+						},
+					}
+					l.AcceptedHostnames = map[string]struct{}{
+						"foo.example.com": {},
+					}
+				}),
+			},
+			msg: "HTTPRoute with parentRefs to two gateways binds only to the winning gateway",
+		},
+		{
+			httpRoute: hrOtherNamespaceGateway,
+			gw:        gwOtherNamespace,
+			listeners: map[string]*Listener{
+				"listener-80-1": createListener(),
+			},
+			expectedIgnored: false,
+			expectedRoute:   createNotAllowedRoute("Listener only allows routes from the namespace of the Gateway"),
+			expectedListeners: map[string]*Listener{
+				"listener-80-1": createListener(),
+			},
+			msg: "HTTPRoute from another namespace not allowed by default",
+		},
+		{
+			httpRoute: hrOtherNamespaceGateway,
+			gw:        gwOtherNamespace,
+			listeners: map[string]*Listener{
+				"listener-80-1": createListenerWithAllowedRoutes(&v1beta1.AllowedRoutes{
+					Namespaces: &v1beta1.RouteNamespaces{
+						From:     (*v1beta1.FromNamespaces)(helpers.GetStringPointer(string(v1beta1.NamespacesFromSelector))),
+						Selector: &metav1.LabelSelector{},
+					},
+				}),
+			},
+			expectedIgnored: false,
+			expectedRoute: createNotAllowedRoute(
+				"Listener only allows routes from the namespaces selected by its selector",
+			),
+			expectedListeners: map[string]*Listener{
+				"listener-80-1": createListenerWithAllowedRoutes(&v1beta1.AllowedRoutes{
+					Namespaces: &v1beta1.RouteNamespaces{
+						From:     (*v1beta1.FromNamespaces)(helpers.GetStringPointer(string(v1beta1.NamespacesFromSelector))),
+						Selector: &metav1.LabelSelector{},
+					},
+				}),
+			},
+			msg: "HTTPRoute from a namespace that doesn't exist not allowed by namespace selector",
+		},
+		{
+			httpRoute: hrOtherNamespaceGateway,
+			gw:        gwOtherNamespace,
+			listeners: map[string]*Listener{
+				"listener-80-1": createListenerWithAllowedRoutes(allowedFromSelector),
+			},
+			namespaces: map[types.NamespacedName]*v1.Namespace{
+				{Name: "test"}: createNamespace(map[string]string{"env": "dev"}),
+			},
+			expectedIgnored: false,
+			expectedRoute: createNotAllowedRoute(
+				"Listener only allows routes from the namespaces selected by its selector",
+			),
+			expectedListeners: map[string]*Listener{
+				"listener-80-1": createListenerWithAllowedRoutes(allowedFromSelector),
+			},
+			msg: "HTTPRoute from a namespace with other labels not allowed by namespace selector",
+		},
+		{
+			httpRoute: hrOtherNamespaceGateway,
+			gw:        gwOtherNamespace,
+			listeners: map[string]*Listener{
+				"listener-80-1": createListenerWithAllowedRoutes(&v1beta1.AllowedRoutes{
+					Namespaces: &v1beta1.RouteNamespaces{
+						From: (*v1beta1.FromNamespaces)(helpers.GetStringPointer(string(v1beta1.NamespacesFromSelector))),
+					},
+				}),
+			},
+			expectedIgnored: false,
+			expectedRoute: createNotAllowedRoute(
+				"Listener allows routes from the namespaces selected by a selector, but the selector is not set",
+			),
+			expectedListeners: map[string]*Listener{
+				"listener-80-1": createListenerWithAllowedRoutes(&v1beta1.AllowedRoutes{
+					Namespaces: &v1beta1.RouteNamespaces{
+						From: (*v1beta1.FromNamespaces)(helpers.GetStringPointer(string(v1beta1.NamespacesFromSelector))),
+					},
+				}),
+			},
+			msg: "HTTPRoute not allowed by a listener without a namespace selector",
+		},
+		{
+			httpRoute: hrOtherNamespaceGateway,
+			gw:        gwOtherNamespace,
+			listeners: map[string]*Listener{
+				"listener-80-1": createListenerWithAllowedRoutes(allowedFromSelector),
+			},
+			namespaces: map[types.NamespacedName]*v1.Namespace{
+				{Name: "test"}: createNamespace(map[string]string{"env": "prod"}),
+			},
+			expectedIgnored: false,
+			expectedRoute: &Route{
+				Source: hrOtherNamespaceGateway,
+				ValidSectionNameRefs: map[string]struct{}{
+					"listener-80-1": {},
+				},
+				InvalidSectionNameRefs: map[string]conditions.Condition{},
+				ParentRefs:             otherNamespaceParentRefs,
+			},
+			expectedListeners: map[string]*Listener{
+				"listener-80-1": createModifiedListener(func(l *Listener) {
+					l.Source.AllowedRoutes = allowedFromSelector
+					l.Routes = map[types.NamespacedName]*Route{
+						{Namespace: "test", Name: "hr-1"}: {
+							Source: hrOtherNamespaceGateway,
+							ValidSectionNameRefs: map[string]struct{}{
+								"listener-80-1": {},
+							},
+							InvalidSectionNameRefs: map[string]conditions.Condition{},
+							ParentRefs:             otherNamespaceParentRefs,
+						},
+					}
+					l.AcceptedHostnames = map[string]struct{}{
+						"foo.example.com": {},
+					}
+				}),
+			},
+			msg: "HTTPRoute allowed by namespace selector",
+		},
+		{
+			httpRoute: hrFoo,
+			gw:        gw,
+			listeners: map[string]*Listener{
+				"listener-80-1": createListenerWithAllowedRoutes(&v1beta1.AllowedRoutes{
+					Kinds: []v1beta1.RouteGroupKind{{Kind: "GRPCRoute"}},
+				}),
+			},
+			expectedIgnored: false,
+			expectedRoute: &Route{
+				Source:               hrFoo,
+				ValidSectionNameRefs: map[string]struct{}{},
+				InvalidSectionNameRefs: map[string]conditions.Condition{
+					"listener-80-1": conditions.NewRouteNotAllowedByListeners("Listener doesn't allow HTTPRoutes"),
+				},
+				ParentRefs: []ParentRef{
+					{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway"}, SectionName: "listener-80-1"},
+				},
+			},
+			expectedListeners: map[string]*Listener{
+				"listener-80-1": createListenerWithAllowedRoutes(&v1beta1.AllowedRoutes{
+					Kinds: []v1beta1.RouteGroupKind{{Kind: "GRPCRoute"}},
+				}),
+			},
+			msg: "HTTPRoute not allowed by listener kinds",
+		},
+		{
+			httpRoute: hrOtherNamespaceGateway,
+			gw:        gwOtherNamespace,
+			listeners: map[string]*Listener{
+				"listener-80-1": createListenerWithAllowedRoutes(allowedFromAll),
+			},
+			expectedIgnored: false,
+			expectedRoute: &Route{
+				Source: hrOtherNamespaceGateway,
+				ValidSectionNameRefs: map[string]struct{}{
+					"listener-80-1": {},
+				},
+				InvalidSectionNameRefs: map[string]conditions.Condition{},
+				ParentRefs:             otherNamespaceParentRefs,
+			},
+			expectedListeners: map[string]*Listener{
+				"listener-80-1": createModifiedListener(func(l *Listener) {
+					l.Source.AllowedRoutes = allowedFromAll
+					l.Routes = map[types.NamespacedName]*Route{
+						{Namespace: "test", Name: "hr-1"}: {
+							Source: hrOtherNamespaceGateway,
+							ValidSectionNameRefs: map[string]struct{}{
+								"listener-80-1": {},
+							},
+							InvalidSectionNameRefs: map[string]conditions.Condition{},
+							ParentRefs:             otherNamespaceParentRefs,
+						},
+					}
+					l.AcceptedHostnames = map[string]struct{}{
+						"foo.example.com": {},
+					}
+				}),
+			},
+			msg: "HTTPRoute from another namespace allowed by listener",
+		},
 	}
 
 	for _, test := range tests {
-		ignored, route := bindHTTPRouteToListeners(test.httpRoute, test.gw, test.ignoredGws, test.listeners)
+		ignored, route := bindHTTPRouteToListeners(
+			test.httpRoute,
+			test.gw,
+			test.ignoredGws,
+			test.gws,
+			test.listeners,
+			test.namespaces,
+		)
 		if diff := cmp.Diff(test.expectedIgnored, ignored); diff != "" {
 			t.Errorf("bindHTTPRouteToListeners() %q  mismatch on ignored (-want +got):\n%s", test.msg, diff)
 		}
@@ -323,9 +764,166 @@ func TestBindRouteToListeners(t *testing.T) {
 	}
 }
 
+func TestLimitListenerRoutes(t *testing.T) {
+	before := metav1.Now()
+	later := metav1.NewTime(before.Add(time.Second))
+
+	createRoute := func(ns, name, hostname string, created metav1.Time) *v1beta1.HTTPRoute {
+		return &v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         ns,
+				Name:              name,
+				CreationTimestamp: created,
+			},
+			Spec: v1beta1.HTTPRouteSpec{
+				CommonRouteSpec: v1beta1.CommonRouteSpec{
+					ParentRefs: []v1beta1.ParentReference{
+						{
+							Namespace:   (*v1beta1.Namespace)(helpers.GetStringPointer("test")),
+							Name:        "gateway",
+							SectionName: (*v1beta1.SectionName)(helpers.GetStringPointer("listener-80-1")),
+						},
+					},
+				},
+				Hostnames: []v1beta1.Hostname{
+					v1beta1.Hostname(hostname),
+				},
+			},
+		}
+	}
+
+	// the order in which the routes are kept: the oldest first, then by namespace, then by name
+	hrOldest := createRoute("test", "hr-z", "oldest.example.com", before)
+	hrOtherNs := createRoute("other", "hr-b", "other-ns.example.com", later)
+	hrA := createRoute("test", "hr-a", "a.example.com", later)
+	hrB := createRoute("test", "hr-b", "b.example.com", later)
+
+	gw := &v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "gateway",
+		},
+	}
+
+	allowedFromAll := &v1beta1.AllowedRoutes{
+		Namespaces: &v1beta1.RouteNamespaces{
+			From: (*v1beta1.FromNamespaces)(helpers.GetStringPointer(string(v1beta1.NamespacesFromAll))),
+		},
+	}
+
+	tests := []struct {
+		msg                 string
+		routes              []*v1beta1.HTTPRoute
+		maxRoutes           int
+		expectedKept        []string
+		expectedHostnames   map[string]struct{}
+		expectedTooManyKeys []types.NamespacedName
+	}{
+		{
+			msg:          "no limit",
+			routes:       []*v1beta1.HTTPRoute{hrB, hrA, hrOtherNs, hrOldest},
+			maxRoutes:    0,
+			expectedKept: []string{"other/hr-b", "test/hr-a", "test/hr-b", "test/hr-z"},
+			expectedHostnames: map[string]struct{}{
+				"oldest.example.com":   {},
+				"other-ns.example.com": {},
+				"a.example.com":        {},
+				"b.example.com":        {},
+			},
+		},
+		{
+			msg:          "under the limit",
+			routes:       []*v1beta1.HTTPRoute{hrB, hrA},
+			maxRoutes:    2,
+			expectedKept: []string{"test/hr-a", "test/hr-b"},
+			expectedHostnames: map[string]struct{}{
+				"a.example.com": {},
+				"b.example.com": {},
+			},
+		},
+		{
+			msg:          "over the limit",
+			routes:       []*v1beta1.HTTPRoute{hrB, hrA, hrOtherNs, hrOldest},
+			maxRoutes:    2,
+			expectedKept: []string{"other/hr-b", "test/hr-z"},
+			expectedHostnames: map[string]struct{}{
+				"oldest.example.com":   {},
+				"other-ns.example.com": {},
+			},
+			expectedTooManyKeys: []types.NamespacedName{
+				{Namespace: "test", Name: "hr-a"},
+				{Namespace: "test", Name: "hr-b"},
+			},
+		},
+		{
+			msg:          "over the limit, another binding order",
+			routes:       []*v1beta1.HTTPRoute{hrOldest, hrA, hrB, hrOtherNs},
+			maxRoutes:    3,
+			expectedKept: []string{"other/hr-b", "test/hr-a", "test/hr-z"},
+			expectedHostnames: map[string]struct{}{
+				"oldest.example.com":   {},
+				"other-ns.example.com": {},
+				"a.example.com":        {},
+			},
+			expectedTooManyKeys: []types.NamespacedName{
+				{Namespace: "test", Name: "hr-b"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			l := &Listener{
+				Source: v1beta1.Listener{
+					AllowedRoutes: allowedFromAll,
+				},
+				Valid:             true,
+				Routes:            map[types.NamespacedName]*Route{},
+				AcceptedHostnames: map[string]struct{}{},
+			}
+			listeners := map[string]*Listener{"listener-80-1": l}
+
+			routes := make(map[types.NamespacedName]*Route)
+			for _, hr := range test.routes {
+				_, r := bindHTTPRouteToListeners(hr, gw, nil, nil, listeners, nil)
+				routes[client.ObjectKeyFromObject(hr)] = r
+			}
+
+			limitListenerRoutes(listeners, test.maxRoutes)
+
+			kept := make([]string, 0, len(l.Routes))
+			for key := range l.Routes {
+				kept = append(kept, key.String())
+			}
+			g.Expect(kept).To(ConsistOf(test.expectedKept))
+			g.Expect(l.AcceptedHostnames).To(Equal(test.expectedHostnames))
+
+			tooMany := make(map[types.NamespacedName]struct{}, len(test.expectedTooManyKeys))
+			for _, key := range test.expectedTooManyKeys {
+				tooMany[key] = struct{}{}
+			}
+
+			for key, r := range routes {
+				if _, exists := tooMany[key]; exists {
+					g.Expect(r.ValidSectionNameRefs).To(BeEmpty())
+					g.Expect(r.InvalidSectionNameRefs).To(Equal(map[string]conditions.Condition{
+						"listener-80-1": conditions.NewRouteTooManyRoutes(test.maxRoutes),
+					}))
+				} else {
+					g.Expect(r.ValidSectionNameRefs).To(HaveKey("listener-80-1"))
+					g.Expect(r.InvalidSectionNameRefs).To(BeEmpty())
+				}
+			}
+		})
+	}
+}
+
 func TestFindAcceptedHostnames(t *testing.T) {
 	var listenerHostnameFoo v1beta1.Hostname = "foo.example.com"
 	var listenerHostnameCafe v1beta1.Hostname = "cafe.example.com"
+	var listenerHostnameWildcard v1beta1.Hostname = "*.example.com"
 	routeHostnames := []v1beta1.Hostname{"foo.example.com", "bar.example.com"}
 
 	tests := []struct {
@@ -352,6 +950,36 @@ func TestFindAcceptedHostnames(t *testing.T) {
 			expected:         []string{"foo.example.com", "bar.example.com"},
 			msg:              "nil listener hostname",
 		},
+		{
+			listenerHostname: &listenerHostnameWildcard,
+			routeHostnames:   []v1beta1.Hostname{"foo.example.com", "foo.bar.example.com", "example.com", "foo.com"},
+			expected:         []string{"foo.example.com", "foo.bar.example.com"},
+			msg:              "wildcard listener hostname",
+		},
+		{
+			listenerHostname: &listenerHostnameFoo,
+			routeHostnames:   []v1beta1.Hostname{"*.example.com", "*.com", "*.cafe.example.com"},
+			expected:         []string{"foo.example.com"},
+			msg:              "wildcard route hostnames",
+		},
+		{
+			listenerHostname: &listenerHostnameWildcard,
+			routeHostnames:   []v1beta1.Hostname{"*.example.com", "*.bar.example.com", "*.com"},
+			expected:         []string{"*.example.com", "*.bar.example.com"},
+			msg:              "wildcard listener and route hostnames",
+		},
+		{
+			listenerHostname: &listenerHostnameWildcard,
+			routeHostnames:   []v1beta1.Hostname{"a.example.com", "a.b.example.com"},
+			expected:         []string{"a.example.com", "a.b.example.com"},
+			msg:              "wildcard listener hostname and one-letter labels",
+		},
+		{
+			listenerHostname: (*v1beta1.Hostname)(helpers.GetStringPointer("a.example.com")),
+			routeHostnames:   []v1beta1.Hostname{"*.example.com"},
+			expected:         []string{"a.example.com"},
+			msg:              "wildcard route hostname and one-letter label",
+		},
 	}
 
 	for _, test := range tests {
@@ -395,3 +1023,79 @@ func TestGetHostname(t *testing.T) {
 		}
 	}
 }
+
+func TestApplyDefaultMatches(t *testing.T) {
+	defaultPath := &v1beta1.HTTPPathMatch{
+		Type:  (*v1beta1.PathMatchType)(helpers.GetStringPointer(string(v1beta1.PathMatchPathPrefix))),
+		Value: helpers.GetStringPointer("/"),
+	}
+	coffeePath := &v1beta1.HTTPPathMatch{
+		Type:  (*v1beta1.PathMatchType)(helpers.GetStringPointer(string(v1beta1.PathMatchPathPrefix))),
+		Value: helpers.GetStringPointer("/coffee"),
+	}
+
+	createRoute := func(rules ...v1beta1.HTTPRouteRule) *v1beta1.HTTPRoute {
+		return &v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test",
+				Name:      "hr",
+			},
+			Spec: v1beta1.HTTPRouteSpec{
+				Rules: rules,
+			},
+		}
+	}
+
+	tests := []struct {
+		hr       *v1beta1.HTTPRoute
+		expected *v1beta1.HTTPRoute
+		msg      string
+	}{
+		{
+			hr:       createRoute(v1beta1.HTTPRouteRule{}),
+			expected: createRoute(v1beta1.HTTPRouteRule{Matches: []v1beta1.HTTPRouteMatch{{Path: defaultPath}}}),
+			msg:      "rule without matches",
+		},
+		{
+			hr: createRoute(v1beta1.HTTPRouteRule{
+				Matches: []v1beta1.HTTPRouteMatch{
+					{Method: helpers.GetHTTPMethodPointer(v1beta1.HTTPMethodGet)},
+				},
+			}),
+			expected: createRoute(v1beta1.HTTPRouteRule{
+				Matches: []v1beta1.HTTPRouteMatch{
+					{Path: defaultPath, Method: helpers.GetHTTPMethodPointer(v1beta1.HTTPMethodGet)},
+				},
+			}),
+			msg: "match without path",
+		},
+		{
+			hr: createRoute(
+				v1beta1.HTTPRouteRule{Matches: []v1beta1.HTTPRouteMatch{{Path: coffeePath}}},
+				v1beta1.HTTPRouteRule{},
+			),
+			expected: createRoute(
+				v1beta1.HTTPRouteRule{Matches: []v1beta1.HTTPRouteMatch{{Path: coffeePath}}},
+				v1beta1.HTTPRouteRule{Matches: []v1beta1.HTTPRouteMatch{{Path: defaultPath}}},
+			),
+			msg: "rule with matches and rule without matches",
+		},
+	}
+
+	for _, test := range tests {
+		original := test.hr.DeepCopy()
+
+		result := applyDefaultMatches(test.hr)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("applyDefaultMatches() mismatch for %q (-want +got):\n%s", test.msg, diff)
+		}
+		if diff := cmp.Diff(original, test.hr); diff != "" {
+			t.Errorf("applyDefaultMatches() modified the HTTPRoute for %q (-want +got):\n%s", test.msg, diff)
+		}
+	}
+
+	hr := createRoute(v1beta1.HTTPRouteRule{Matches: []v1beta1.HTTPRouteMatch{{Path: coffeePath}}})
+	if result := applyDefaultMatches(hr); result != hr {
+		t.Errorf("applyDefaultMatches() returned a copy of the HTTPRoute that doesn't need defaults")
+	}
+}
@@ -0,0 +1,159 @@
+package graph
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/conditions"
+)
+
+// RateLimitAnnotation is the Gateway annotation that limits the rate of the requests to the Listeners per client
+// address, regardless of the routes. The value is a comma-separated list of entries.
+// An entry of the form <rate>[:<burst>] configures the limit of all Listeners.
+// An entry of the form <listener>=<rate>[:<burst>] configures the limit of a Listener and takes precedence over
+// the entry for all Listeners.
+// The rate is the number of requests per second or minute, for example, 10r/s or 600r/m. The burst is the number
+// of the requests in excess of the rate that NGINX accepts without delay. By default, the burst is 0.
+const RateLimitAnnotation = "k8s-gateway.nginx.org/rate-limit"
+
+// maxRateLimitBurst is the maximum burst of a rate limit.
+const maxRateLimitBurst = 100000
+
+// rateRegexp matches a rate of the NGINX limit_req_zone directive in requests per second or minute.
+var rateRegexp = regexp.MustCompile(`^[1-9][0-9]{0,5}r/[sm]$`)
+
+// RateLimit is the limit of the rate of the requests.
+type RateLimit struct {
+	// Rate is the rate in the NGINX format, for example, 10r/s.
+	Rate string
+	// Burst is the number of the requests in excess of the Rate that NGINX accepts without delay.
+	Burst int32
+}
+
+// rateLimits holds the rate limits configured through the RateLimitAnnotation.
+type rateLimits struct {
+	// all is the rate limit of the Listeners without their own rate limit. It is nil if not configured.
+	all *RateLimit
+	// listeners maps the names of Listeners to their rate limits.
+	listeners map[string]RateLimit
+}
+
+// parseRateLimits parses the value of the RateLimitAnnotation.
+func parseRateLimits(value string) (rateLimits, error) {
+	limits := rateLimits{
+		listeners: make(map[string]RateLimit),
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		listener, limitValue, forListener := strings.Cut(entry, "=")
+		if !forListener {
+			limitValue = listener
+		}
+
+		limit, err := parseRateLimit(strings.TrimSpace(limitValue))
+		if err != nil {
+			return rateLimits{}, fmt.Errorf("invalid entry %q: %w", entry, err)
+		}
+
+		if !forListener {
+			if limits.all != nil {
+				return rateLimits{}, fmt.Errorf(
+					"invalid entry %q: the rate limit of all listeners is already configured",
+					entry,
+				)
+			}
+
+			limits.all = &limit
+
+			continue
+		}
+
+		listener = strings.TrimSpace(listener)
+		if _, exists := limits.listeners[listener]; exists {
+			return rateLimits{}, fmt.Errorf(
+				"invalid entry %q: the rate limit of the listener %q is already configured",
+				entry,
+				listener,
+			)
+		}
+
+		limits.listeners[listener] = limit
+	}
+
+	return limits, nil
+}
+
+func parseRateLimit(value string) (RateLimit, error) {
+	rate, burstValue, hasBurst := strings.Cut(value, ":")
+
+	if !rateRegexp.MatchString(rate) {
+		return RateLimit{}, fmt.Errorf(
+			"invalid rate %q: must be a number of requests per second or minute, for example, 10r/s or 600r/m",
+			rate,
+		)
+	}
+
+	limit := RateLimit{Rate: rate}
+
+	if hasBurst {
+		burst, err := strconv.ParseInt(burstValue, 10, 32)
+		if err != nil || burst < 0 || burst > maxRateLimitBurst {
+			return RateLimit{}, fmt.Errorf("invalid burst %q: must be between 0 and %d", burstValue, maxRateLimitBurst)
+		}
+
+		limit.Burst = int32(burst)
+	}
+
+	return limit, nil
+}
+
+// addRateLimitsToListeners adds the rate limits configured through the RateLimitAnnotation of the Gateway
+// to the valid Listeners. The Listeners are modified in place.
+// If the annotation is invalid, NGINX doesn't limit the rate of the requests to the Listeners, and a condition
+// is added to the Listeners.
+func addRateLimitsToListeners(gw *v1beta1.Gateway, listeners map[string]*Listener) {
+	if gw == nil {
+		return
+	}
+
+	value, exists := gw.Annotations[RateLimitAnnotation]
+	if !exists {
+		return
+	}
+
+	limits, err := parseRateLimits(value)
+
+	for name, l := range listeners {
+		if !l.Valid {
+			continue
+		}
+
+		if err != nil {
+			l.Conditions = append(l.Conditions, conditions.NewListenerInvalidRateLimit(
+				fmt.Sprintf("Invalid %s annotation: %v", RateLimitAnnotation, err),
+			))
+
+			continue
+		}
+
+		limit, exists := limits.listeners[name]
+		if !exists {
+			if limits.all == nil {
+				continue
+			}
+
+			limit = *limits.all
+		}
+
+		l.RateLimit = &limit
+	}
+}
@@ -0,0 +1,204 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/conditions"
+)
+
+func TestParseRateLimits(t *testing.T) {
+	tests := []struct {
+		msg      string
+		value    string
+		expected rateLimits
+		expErr   bool
+	}{
+		{
+			msg:   "empty",
+			value: "",
+			expected: rateLimits{
+				listeners: map[string]RateLimit{},
+			},
+		},
+		{
+			msg:   "all listeners",
+			value: "10r/s",
+			expected: rateLimits{
+				all:       &RateLimit{Rate: "10r/s"},
+				listeners: map[string]RateLimit{},
+			},
+		},
+		{
+			msg:   "all listeners and listener overrides",
+			value: " 10r/s:20, https = 600r/m ,http=5r/s:0,",
+			expected: rateLimits{
+				all: &RateLimit{Rate: "10r/s", Burst: 20},
+				listeners: map[string]RateLimit{
+					"https": {Rate: "600r/m"},
+					"http":  {Rate: "5r/s"},
+				},
+			},
+		},
+		{
+			msg:    "missing unit",
+			value:  "10",
+			expErr: true,
+		},
+		{
+			msg:    "invalid unit",
+			value:  "10r/h",
+			expErr: true,
+		},
+		{
+			msg:    "zero rate",
+			value:  "0r/s",
+			expErr: true,
+		},
+		{
+			msg:    "invalid burst",
+			value:  "10r/s:many",
+			expErr: true,
+		},
+		{
+			msg:    "negative burst",
+			value:  "10r/s:-1",
+			expErr: true,
+		},
+		{
+			msg:    "burst out of range",
+			value:  "10r/s:100001",
+			expErr: true,
+		},
+		{
+			msg:    "duplicate all listeners entries",
+			value:  "10r/s,20r/s",
+			expErr: true,
+		},
+		{
+			msg:    "duplicate listener entries",
+			value:  "http=10r/s,http=20r/s",
+			expErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			result, err := parseRateLimits(test.value)
+			if test.expErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(result).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestAddRateLimitsToListeners(t *testing.T) {
+	createGateway := func(annotation string) *v1beta1.Gateway {
+		return &v1beta1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "test",
+				Name:        "gateway",
+				Annotations: map[string]string{RateLimitAnnotation: annotation},
+			},
+		}
+	}
+
+	createListeners := func() map[string]*Listener {
+		return map[string]*Listener{
+			"http":    {Valid: true},
+			"https":   {Valid: true},
+			"invalid": {Valid: false},
+		}
+	}
+
+	tests := []struct {
+		gateway   *v1beta1.Gateway
+		expected  map[string]*Listener
+		msg       string
+		listeners map[string]*Listener
+	}{
+		{
+			gateway:   nil,
+			listeners: createListeners(),
+			expected:  createListeners(),
+			msg:       "no gateway",
+		},
+		{
+			gateway: &v1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "gateway"},
+			},
+			listeners: createListeners(),
+			expected:  createListeners(),
+			msg:       "no annotation",
+		},
+		{
+			gateway:   createGateway("10r/s:20"),
+			listeners: createListeners(),
+			expected: map[string]*Listener{
+				"http":    {Valid: true, RateLimit: &RateLimit{Rate: "10r/s", Burst: 20}},
+				"https":   {Valid: true, RateLimit: &RateLimit{Rate: "10r/s", Burst: 20}},
+				"invalid": {Valid: false},
+			},
+			msg: "rate limit of all listeners",
+		},
+		{
+			gateway:   createGateway("10r/s,https=100r/s:50,unknown=1r/s"),
+			listeners: createListeners(),
+			expected: map[string]*Listener{
+				"http":    {Valid: true, RateLimit: &RateLimit{Rate: "10r/s"}},
+				"https":   {Valid: true, RateLimit: &RateLimit{Rate: "100r/s", Burst: 50}},
+				"invalid": {Valid: false},
+			},
+			msg: "rate limit of a listener overrides the rate limit of all listeners",
+		},
+		{
+			gateway:   createGateway("https=100r/s"),
+			listeners: createListeners(),
+			expected: map[string]*Listener{
+				"http":    {Valid: true},
+				"https":   {Valid: true, RateLimit: &RateLimit{Rate: "100r/s"}},
+				"invalid": {Valid: false},
+			},
+			msg: "rate limit of a listener only",
+		},
+		{
+			gateway: createGateway("10r/s,https=fast"),
+			listeners: map[string]*Listener{
+				"http": {Valid: true},
+			},
+			expected: map[string]*Listener{
+				"http": {
+					Valid: true,
+					Conditions: []conditions.Condition{
+						conditions.NewListenerInvalidRateLimit(
+							`Invalid k8s-gateway.nginx.org/rate-limit annotation: invalid entry "https=fast": ` +
+								`invalid rate "fast": must be a number of requests per second or minute, ` +
+								`for example, 10r/s or 600r/m`,
+						),
+					},
+				},
+			},
+			msg: "invalid annotation",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			addRateLimitsToListeners(test.gateway, test.listeners)
+
+			if diff := cmp.Diff(test.expected, test.listeners); diff != "" {
+				t.Errorf("addRateLimitsToListeners() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	nkgsort "github.com/nginxinc/nginx-kubernetes-gateway/internal/sort"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/conditions"
+)
+
+// matchKey identifies a match of a rule for a hostname. NGINX serves the listeners of the same protocol
+// with the same servers, so the routes of such listeners share the keys. The same matches of the routes with
+// different GeoMatches apply to different clients, so they have different keys.
+type matchKey struct {
+	protocol v1beta1.ProtocolType
+	hostname string
+	match    string
+	geo      string
+}
+
+// resolveRouteConflicts finds the matches of the routes that are shadowed by the same matches of other routes
+// for the same hostname and adds a condition that lists them to such routes.
+// According to the Gateway API conflict resolution guidelines, the oldest route wins, continuing on ties with
+// the route that comes first in alphabetical order by {namespace}/{name}. NGINX routes the requests in the same
+// order, so the winning route doesn't depend on the order in which the routes were processed.
+func resolveRouteConflicts(listeners map[string]*Listener) {
+	listenerNames := make([]string, 0, len(listeners))
+	for name, l := range listeners {
+		if l.Valid {
+			listenerNames = append(listenerNames, name)
+		}
+	}
+	sort.Strings(listenerNames)
+
+	routesMap := make(map[types.NamespacedName]*Route)
+	for _, name := range listenerNames {
+		for nsname, r := range listeners[name].Routes {
+			routesMap[nsname] = r
+		}
+	}
+
+	routes := make([]*Route, 0, len(routesMap))
+	for _, r := range routesMap {
+		routes = append(routes, r)
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		return nkgsort.LessObjectMeta(&routes[i].Source.ObjectMeta, &routes[j].Source.ObjectMeta)
+	})
+
+	// winners holds the route that takes precedence for each matchKey.
+	winners := make(map[matchKey]*Route)
+
+	for _, r := range routes {
+		var shadowed []string
+		// a route attached to multiple listeners of the same protocol can have the same key more than once
+		seen := make(map[matchKey]struct{})
+		geo := getGeoMatchKey(r)
+
+		for _, name := range listenerNames {
+			l := listeners[name]
+			if _, attached := l.Routes[client.ObjectKeyFromObject(r.Source)]; !attached {
+				continue
+			}
+
+			for _, h := range findAcceptedHostnames(l.Source.Hostname, r.Source.Spec.Hostnames) {
+				for i, rule := range r.Source.Spec.Rules {
+					// the invalid rules are not configured, so they don't shadow other rules
+					if _, invalid := r.InvalidRules[i]; invalid {
+						continue
+					}
+
+					for j, m := range rule.Matches {
+						key := matchKey{
+							protocol: l.Source.Protocol,
+							hostname: h,
+							match:    createMatchKey(m),
+							geo:      geo,
+						}
+
+						if _, exist := seen[key]; exist {
+							continue
+						}
+						seen[key] = struct{}{}
+
+						winner, exist := winners[key]
+						if !exist {
+							winners[key] = r
+							continue
+						}
+
+						// the same matches of the routes with a valid weight share the requests instead of conflicting
+						if winner == r || (hasValidWeight(winner) && hasValidWeight(r)) {
+							continue
+						}
+
+						shadowed = append(shadowed, fmt.Sprintf("rule %d match %d for hostname %s by HTTPRoute %s",
+							i, j, h, client.ObjectKeyFromObject(winner.Source)))
+					}
+				}
+			}
+		}
+
+		if len(shadowed) > 0 {
+			r.Conditions = append(r.Conditions, conditions.NewRouteMatchesShadowed(
+				"Matches are shadowed by the same matches of the routes that take precedence: "+
+					strings.Join(shadowed, "; "),
+			))
+		}
+	}
+}
+
+// hasValidWeight returns true if the route has the WeightAnnotation with a valid value. The routes with an invalid
+// weight are not merged into the weighted splits, so their matches conflict.
+func hasValidWeight(r *Route) bool {
+	v, exists := r.Source.Annotations[WeightAnnotation]
+	if !exists {
+		return false
+	}
+
+	_, err := ParseRouteWeight(v)
+
+	return err == nil
+}
+
+// createMatchKey returns a string that is the same for the equivalent matches: the order of the header
+// and query param matches and the implied default types don't matter.
+func createMatchKey(m v1beta1.HTTPRouteMatch) string {
+	pathType := v1beta1.PathMatchPathPrefix
+	pathValue := defaultPathMatchValue
+
+	if m.Path != nil {
+		if m.Path.Type != nil {
+			pathType = *m.Path.Type
+		}
+		if m.Path.Value != nil {
+			pathValue = *m.Path.Value
+		}
+	}
+
+	headers := make([]string, 0, len(m.Headers))
+	for _, h := range m.Headers {
+		matchType := v1beta1.HeaderMatchExact
+		if h.Type != nil {
+			matchType = *h.Type
+		}
+		// header names are case-insensitive
+		headers = append(headers, string(matchType)+"/"+strings.ToLower(string(h.Name))+"/"+h.Value)
+	}
+	sort.Strings(headers)
+
+	params := make([]string, 0, len(m.QueryParams))
+	for _, p := range m.QueryParams {
+		matchType := v1beta1.QueryParamMatchExact
+		if p.Type != nil {
+			matchType = *p.Type
+		}
+		params = append(params, string(matchType)+"/"+p.Name+"/"+p.Value)
+	}
+	sort.Strings(params)
+
+	var method string
+	if m.Method != nil {
+		method = string(*m.Method)
+	}
+
+	// The parts are quoted, so that the separators don't occur in them. The key is built without reflection,
+	// because it is created for every match of every route.
+	b := make([]byte, 0, 64)
+	b = strconv.AppendQuote(b, string(pathType))
+	b = strconv.AppendQuote(b, pathValue)
+	b = appendQuotedList(b, headers)
+	b = appendQuotedList(b, params)
+	b = strconv.AppendQuote(b, method)
+
+	return string(b)
+}
+
+func appendQuotedList(b []byte, list []string) []byte {
+	b = append(b, '[')
+	for _, s := range list {
+		b = strconv.AppendQuote(b, s)
+	}
+
+	return append(b, ']')
+}
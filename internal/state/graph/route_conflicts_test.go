@@ -0,0 +1,310 @@
+package graph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/conditions"
+)
+
+func TestResolveRouteConflicts(t *testing.T) {
+	before := metav1.Now()
+	later := metav1.NewTime(before.Add(time.Second))
+	pathPrefix := v1beta1.PathMatchPathPrefix
+
+	createRoute := func(ns, name string, created metav1.Time, matches ...v1beta1.HTTPRouteMatch) *Route {
+		return &Route{
+			Source: &v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:         ns,
+					Name:              name,
+					CreationTimestamp: created,
+				},
+				Spec: v1beta1.HTTPRouteSpec{
+					Hostnames: []v1beta1.Hostname{"foo.example.com"},
+					Rules: []v1beta1.HTTPRouteRule{
+						{
+							Matches: matches,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	createListener := func(protocol v1beta1.ProtocolType, routes ...*Route) *Listener {
+		l := &Listener{
+			Source: v1beta1.Listener{
+				Protocol: protocol,
+			},
+			Routes: make(map[types.NamespacedName]*Route),
+			Valid:  true,
+		}
+
+		for _, r := range routes {
+			l.Routes[client.ObjectKeyFromObject(r.Source)] = r
+		}
+
+		return l
+	}
+
+	createPathMatch := func(path string) v1beta1.HTTPRouteMatch {
+		return v1beta1.HTTPRouteMatch{
+			Path: &v1beta1.HTTPPathMatch{
+				Type:  &pathPrefix,
+				Value: helpers.GetStringPointer(path),
+			},
+		}
+	}
+
+	headersMatch := v1beta1.HTTPRouteMatch{
+		Path: &v1beta1.HTTPPathMatch{
+			Value: helpers.GetStringPointer("/"),
+		},
+		Headers: []v1beta1.HTTPHeaderMatch{
+			{Name: "Version", Value: "2"},
+			{Name: "X-Env", Value: "canary", Type: helpers.GetHeaderMatchTypePointer(v1beta1.HeaderMatchExact)},
+		},
+	}
+
+	reorderedHeadersMatch := v1beta1.HTTPRouteMatch{
+		Path: &v1beta1.HTTPPathMatch{
+			Type:  &pathPrefix,
+			Value: helpers.GetStringPointer("/"),
+		},
+		Headers: []v1beta1.HTTPHeaderMatch{
+			{Name: "x-env", Value: "canary"},
+			{Name: "Version", Value: "2"},
+		},
+	}
+
+	createShadowedCond := func(msg string) []conditions.Condition {
+		return []conditions.Condition{
+			conditions.NewRouteMatchesShadowed(
+				"Matches are shadowed by the same matches of the routes that take precedence: " + msg,
+			),
+		}
+	}
+
+	tests := []struct {
+		createListeners func() (map[string]*Listener, map[string]*Route)
+		expConditions   map[string][]conditions.Condition
+		msg             string
+	}{
+		{
+			createListeners: func() (map[string]*Listener, map[string]*Route) {
+				hrOld := createRoute("test", "hr-z", before, createPathMatch("/coffee"))
+				hrNew := createRoute("test", "hr-a", later, createPathMatch("/coffee"), createPathMatch("/tea"))
+
+				return map[string]*Listener{
+					"listener-80-1": createListener(v1beta1.HTTPProtocolType, hrNew, hrOld),
+				}, map[string]*Route{
+					"hr-old": hrOld,
+					"hr-new": hrNew,
+				}
+			},
+			expConditions: map[string][]conditions.Condition{
+				"hr-new": createShadowedCond("rule 0 match 0 for hostname foo.example.com by HTTPRoute test/hr-z"),
+			},
+			msg: "oldest route wins",
+		},
+		{
+			createListeners: func() (map[string]*Listener, map[string]*Route) {
+				hrA := createRoute("test", "hr-a", before, createPathMatch("/coffee"))
+				hrB := createRoute("test", "hr-b", before, createPathMatch("/coffee"))
+				hrOtherNs := createRoute("other", "hr-c", before, createPathMatch("/coffee"))
+
+				return map[string]*Listener{
+					"listener-80-1": createListener(v1beta1.HTTPProtocolType, hrB, hrA, hrOtherNs),
+				}, map[string]*Route{
+					"hr-a":        hrA,
+					"hr-b":        hrB,
+					"hr-other-ns": hrOtherNs,
+				}
+			},
+			expConditions: map[string][]conditions.Condition{
+				"hr-a": createShadowedCond("rule 0 match 0 for hostname foo.example.com by HTTPRoute other/hr-c"),
+				"hr-b": createShadowedCond("rule 0 match 0 for hostname foo.example.com by HTTPRoute other/hr-c"),
+			},
+			msg: "same creation timestamp, namespace/name order wins",
+		},
+		{
+			createListeners: func() (map[string]*Listener, map[string]*Route) {
+				hrOld := createRoute("test", "hr-old", before, headersMatch)
+				hrNew := createRoute("test", "hr-new", later, reorderedHeadersMatch)
+
+				return map[string]*Listener{
+					"listener-80-1": createListener(v1beta1.HTTPProtocolType, hrOld, hrNew),
+				}, map[string]*Route{
+					"hr-old": hrOld,
+					"hr-new": hrNew,
+				}
+			},
+			expConditions: map[string][]conditions.Condition{
+				"hr-new": createShadowedCond("rule 0 match 0 for hostname foo.example.com by HTTPRoute test/hr-old"),
+			},
+			msg: "equivalent matches",
+		},
+		{
+			createListeners: func() (map[string]*Listener, map[string]*Route) {
+				hrOld := createRoute("test", "hr-old", before, createPathMatch("/coffee"), headersMatch)
+				hrNew := createRoute("test", "hr-new", later, createPathMatch("/tea"), createPathMatch("/"))
+
+				return map[string]*Listener{
+					"listener-80-1": createListener(v1beta1.HTTPProtocolType, hrOld, hrNew),
+				}, map[string]*Route{
+					"hr-old": hrOld,
+					"hr-new": hrNew,
+				}
+			},
+			msg: "different matches",
+		},
+		{
+			createListeners: func() (map[string]*Listener, map[string]*Route) {
+				hrOld := createRoute("test", "hr-old", before, createPathMatch("/coffee"))
+				hrNew := createRoute("test", "hr-new", later, createPathMatch("/coffee"))
+
+				return map[string]*Listener{
+					"listener-80-1":  createListener(v1beta1.HTTPProtocolType, hrOld),
+					"listener-443-1": createListener(v1beta1.HTTPSProtocolType, hrNew),
+				}, map[string]*Route{
+					"hr-old": hrOld,
+					"hr-new": hrNew,
+				}
+			},
+			msg: "different protocols",
+		},
+		{
+			createListeners: func() (map[string]*Listener, map[string]*Route) {
+				hrOld := createRoute("test", "hr-old", before, createPathMatch("/coffee"))
+				hrNew := createRoute("test", "hr-new", later, createPathMatch("/coffee"))
+
+				return map[string]*Listener{
+					"listener-80-1": createListener(v1beta1.HTTPProtocolType, hrOld, hrNew),
+					"listener-80-2": createListener(v1beta1.HTTPProtocolType, hrNew),
+				}, map[string]*Route{
+					"hr-old": hrOld,
+					"hr-new": hrNew,
+				}
+			},
+			expConditions: map[string][]conditions.Condition{
+				"hr-new": createShadowedCond("rule 0 match 0 for hostname foo.example.com by HTTPRoute test/hr-old"),
+			},
+			msg: "listeners of the same protocol",
+		},
+		{
+			createListeners: func() (map[string]*Listener, map[string]*Route) {
+				hrOld := createRoute("test", "hr-old", before, createPathMatch("/coffee"))
+				hrOld.Source.Annotations = map[string]string{WeightAnnotation: "80"}
+				hrNew := createRoute("test", "hr-new", later, createPathMatch("/coffee"))
+				hrNew.Source.Annotations = map[string]string{WeightAnnotation: "20"}
+
+				return map[string]*Listener{
+					"listener-80-1": createListener(v1beta1.HTTPProtocolType, hrOld, hrNew),
+				}, map[string]*Route{
+					"hr-old": hrOld,
+					"hr-new": hrNew,
+				}
+			},
+			msg: "weighted routes",
+		},
+		{
+			createListeners: func() (map[string]*Listener, map[string]*Route) {
+				hrOld := createRoute("test", "hr-old", before, createPathMatch("/coffee"))
+				hrOld.Source.Annotations = map[string]string{WeightAnnotation: "80"}
+				hrNew := createRoute("test", "hr-new", later, createPathMatch("/coffee"))
+				hrNew.Source.Annotations = map[string]string{WeightAnnotation: "1001"}
+
+				return map[string]*Listener{
+					"listener-80-1": createListener(v1beta1.HTTPProtocolType, hrOld, hrNew),
+				}, map[string]*Route{
+					"hr-old": hrOld,
+					"hr-new": hrNew,
+				}
+			},
+			expConditions: map[string][]conditions.Condition{
+				"hr-new": createShadowedCond("rule 0 match 0 for hostname foo.example.com by HTTPRoute test/hr-old"),
+			},
+			msg: "weighted route and route with invalid weight",
+		},
+		{
+			createListeners: func() (map[string]*Listener, map[string]*Route) {
+				hrOld := createRoute("test", "hr-old", before, createPathMatch("/coffee"))
+				hrNew := createRoute("test", "hr-new", later, createPathMatch("/coffee"))
+				hrNew.Source.Annotations = map[string]string{GeoMatchAnnotation: "continent=EU"}
+				hrNewer := createRoute("test", "hr-newer", later, createPathMatch("/coffee"))
+				hrNewer.Source.Annotations = map[string]string{GeoMatchAnnotation: "continent=EU,EU"}
+
+				return map[string]*Listener{
+					"listener-80-1": createListener(v1beta1.HTTPProtocolType, hrOld, hrNew, hrNewer),
+				}, map[string]*Route{
+					"hr-old":   hrOld,
+					"hr-new":   hrNew,
+					"hr-newer": hrNewer,
+				}
+			},
+			expConditions: map[string][]conditions.Condition{
+				"hr-newer": createShadowedCond("rule 0 match 0 for hostname foo.example.com by HTTPRoute test/hr-new"),
+			},
+			msg: "routes with geo matches",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			listeners, routes := test.createListeners()
+
+			resolveRouteConflicts(listeners)
+
+			for name, r := range routes {
+				if diff := cmp.Diff(test.expConditions[name], r.Conditions); diff != "" {
+					t.Errorf("resolveRouteConflicts() mismatch on conditions of %s (-want +got):\n%s", name, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateMatchKey(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	pathPrefix := v1beta1.PathMatchPathPrefix
+
+	implied := v1beta1.HTTPRouteMatch{
+		Path: &v1beta1.HTTPPathMatch{
+			Value: helpers.GetStringPointer("/"),
+		},
+		QueryParams: []v1beta1.HTTPQueryParamMatch{
+			{Name: "b", Value: "2"},
+			{Name: "a", Value: "1"},
+		},
+	}
+
+	explicit := v1beta1.HTTPRouteMatch{
+		Path: &v1beta1.HTTPPathMatch{
+			Type:  &pathPrefix,
+			Value: helpers.GetStringPointer("/"),
+		},
+		QueryParams: []v1beta1.HTTPQueryParamMatch{
+			{Name: "a", Value: "1", Type: helpers.GetQueryParamMatchTypePointer(v1beta1.QueryParamMatchExact)},
+			{Name: "b", Value: "2"},
+		},
+	}
+
+	withMethod := explicit
+	withMethod.Method = helpers.GetHTTPMethodPointer(v1beta1.HTTPMethodGet)
+
+	g.Expect(createMatchKey(implied)).To(Equal(createMatchKey(explicit)))
+	g.Expect(createMatchKey(withMethod)).ToNot(Equal(createMatchKey(explicit)))
+	g.Expect(createMatchKey(v1beta1.HTTPRouteMatch{})).To(Equal(createMatchKey(v1beta1.HTTPRouteMatch{
+		Path: &v1beta1.HTTPPathMatch{Value: helpers.GetStringPointer("/")},
+	})))
+}
@@ -0,0 +1,173 @@
+package graph
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/conditions"
+)
+
+var headerNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// validateRouteRules iterates over the routes and validates the rules. The routes are modified in place.
+// A rule with an invalid regular expression in a path, header or query param match is added to the InvalidRules
+// of the route, so that only that rule is not configured, and a condition that lists the errors is added
+// to the route.
+// A rule with a RequestHeaderModifier filter of a backendRef that cannot be configured in NGINX is not configured
+// either.
+// If conformance is true, the rules that use the features NGINX Kubernetes Gateway doesn't support, such as
+// the path match types other than PathPrefix, including Exact, and the header and query param match types other than
+// Exact, are not configured either. Otherwise, such features are ignored.
+func validateRouteRules(routes map[types.NamespacedName]*Route, conformance bool) {
+	for _, r := range routes {
+		var msgs []string
+
+		for i, rule := range r.Source.Spec.Rules {
+			ruleMsgs := validateRule(rule, conformance)
+			if len(ruleMsgs) == 0 {
+				continue
+			}
+
+			if r.InvalidRules == nil {
+				r.InvalidRules = make(map[int]struct{})
+			}
+			r.InvalidRules[i] = struct{}{}
+
+			for _, msg := range ruleMsgs {
+				msgs = append(msgs, fmt.Sprintf("rule %d %s", i, msg))
+			}
+		}
+
+		if len(msgs) > 0 {
+			r.Conditions = append(r.Conditions, conditions.NewRouteUnsupportedValue(
+				"Rules with unsupported values are not configured: "+strings.Join(msgs, "; "),
+			))
+		}
+	}
+}
+
+func validateRule(rule v1beta1.HTTPRouteRule, conformance bool) []string {
+	var msgs []string
+
+	for j, m := range rule.Matches {
+		if err := validateMatch(m); err != nil {
+			msgs = append(msgs, fmt.Sprintf("match %d: %v", j, err))
+			continue
+		}
+
+		if !conformance {
+			continue
+		}
+
+		if err := validateMatchSupported(m); err != nil {
+			msgs = append(msgs, fmt.Sprintf("match %d: %v", j, err))
+		}
+	}
+
+	if conformance {
+		for j, f := range rule.Filters {
+			if f.Type != v1beta1.HTTPRouteFilterRequestRedirect && f.Type != v1beta1.HTTPRouteFilterExtensionRef {
+				msgs = append(msgs, fmt.Sprintf("filter %d: unsupported type %s", j, f.Type))
+			}
+		}
+	}
+
+	for j, ref := range rule.BackendRefs {
+		for k, f := range ref.Filters {
+			if f.Type == v1beta1.HTTPRouteFilterRequestHeaderModifier && f.RequestHeaderModifier != nil {
+				if err := validateRequestHeaderModifier(*f.RequestHeaderModifier); err != nil {
+					msgs = append(msgs, fmt.Sprintf("backendRef %d filter %d: %v", j, k, err))
+				}
+				continue
+			}
+
+			if conformance {
+				msgs = append(msgs, fmt.Sprintf("backendRef %d filter %d: unsupported type %s", j, k, f.Type))
+			}
+		}
+	}
+
+	return msgs
+}
+
+// validateRequestHeaderModifier returns an error if a header of the RequestHeaderModifier of a backendRef
+// cannot be configured in NGINX. NGINX refers to the headers of the requests through the $http_<name> variables,
+// so the names can only include letters, digits, hyphens and underscores, and NGINX would interpret the text
+// after $ in a value as a variable.
+func validateRequestHeaderModifier(f v1beta1.HTTPHeaderFilter) error {
+	for _, h := range append(append([]v1beta1.HTTPHeader{}, f.Set...), f.Add...) {
+		if !headerNameRegexp.MatchString(string(h.Name)) {
+			return fmt.Errorf("unsupported header name %q; must only include letters, digits, - and _", h.Name)
+		}
+
+		if strings.Contains(h.Value, "$") {
+			return fmt.Errorf("the value of the header %s cannot include the $ character", h.Name)
+		}
+	}
+
+	for _, name := range f.Remove {
+		if !headerNameRegexp.MatchString(name) {
+			return fmt.Errorf("unsupported header name %q; must only include letters, digits, - and _", name)
+		}
+	}
+
+	return nil
+}
+
+func validateMatch(m v1beta1.HTTPRouteMatch) error {
+	if m.Path != nil && m.Path.Type != nil && *m.Path.Type == v1beta1.PathMatchRegularExpression &&
+		m.Path.Value != nil {
+		if _, err := regexp.Compile(*m.Path.Value); err != nil {
+			return fmt.Errorf("invalid regular expression %q of the path: %w", *m.Path.Value, err)
+		}
+	}
+
+	for _, h := range m.Headers {
+		if h.Type == nil || *h.Type != v1beta1.HeaderMatchRegularExpression {
+			continue
+		}
+
+		if _, err := regexp.Compile(h.Value); err != nil {
+			return fmt.Errorf("invalid regular expression %q of the header %s: %w", h.Value, h.Name, err)
+		}
+	}
+
+	for _, p := range m.QueryParams {
+		if p.Type == nil || *p.Type != v1beta1.QueryParamMatchRegularExpression {
+			continue
+		}
+
+		if _, err := regexp.Compile(p.Value); err != nil {
+			return fmt.Errorf("invalid regular expression %q of the query param %s: %w", p.Value, p.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// validateMatchSupported returns an error if the match uses a type of a path, header or query param match
+// that NGINX Kubernetes Gateway doesn't support: a path match type other than PathPrefix, including Exact,
+// or a header or query param match type other than Exact. A missing type means the default type, which is supported.
+func validateMatchSupported(m v1beta1.HTTPRouteMatch) error {
+	if m.Path != nil && m.Path.Type != nil && *m.Path.Type != v1beta1.PathMatchPathPrefix {
+		return fmt.Errorf("unsupported type %s of the path", *m.Path.Type)
+	}
+
+	for _, h := range m.Headers {
+		if h.Type != nil && *h.Type != v1beta1.HeaderMatchExact {
+			return fmt.Errorf("unsupported type %s of the header %s", *h.Type, h.Name)
+		}
+	}
+
+	for _, p := range m.QueryParams {
+		if p.Type != nil && *p.Type != v1beta1.QueryParamMatchExact {
+			return fmt.Errorf("unsupported type %s of the query param %s", *p.Type, p.Name)
+		}
+	}
+
+	return nil
+}
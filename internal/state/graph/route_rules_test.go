@@ -0,0 +1,270 @@
+package graph
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/conditions"
+)
+
+func TestValidateRouteRules(t *testing.T) {
+	pathRegex := v1beta1.PathMatchRegularExpression
+	pathPrefix := v1beta1.PathMatchPathPrefix
+
+	createRoute := func(matches ...v1beta1.HTTPRouteMatch) *Route {
+		rules := make([]v1beta1.HTTPRouteRule, 0, len(matches))
+		for _, m := range matches {
+			rules = append(rules, v1beta1.HTTPRouteRule{Matches: []v1beta1.HTTPRouteMatch{m}})
+		}
+
+		return &Route{
+			Source: &v1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "test",
+					Name:      "hr",
+				},
+				Spec: v1beta1.HTTPRouteSpec{
+					Rules: rules,
+				},
+			},
+		}
+	}
+
+	createPathMatch := func(pathType *v1beta1.PathMatchType, path string) v1beta1.HTTPRouteMatch {
+		return v1beta1.HTTPRouteMatch{
+			Path: &v1beta1.HTTPPathMatch{
+				Type:  pathType,
+				Value: helpers.GetStringPointer(path),
+			},
+		}
+	}
+
+	validRegex := createPathMatch(&pathRegex, "/coffee/[0-9]+")
+	invalidPathRegex := createPathMatch(&pathRegex, "/coffee/[0-9")
+	// not a regular expression, so it is not validated
+	prefix := createPathMatch(&pathPrefix, "/coffee/[0-9")
+
+	invalidHeaderRegex := createPathMatch(&pathPrefix, "/")
+	invalidHeaderRegex.Headers = []v1beta1.HTTPHeaderMatch{
+		{
+			Type:  helpers.GetHeaderMatchTypePointer(v1beta1.HeaderMatchRegularExpression),
+			Name:  "Version",
+			Value: "(v1",
+		},
+	}
+
+	invalidQueryParamRegex := createPathMatch(&pathPrefix, "/")
+	invalidQueryParamRegex.QueryParams = []v1beta1.HTTPQueryParamMatch{
+		{
+			Type:  helpers.GetQueryParamMatchTypePointer(v1beta1.QueryParamMatchRegularExpression),
+			Name:  "version",
+			Value: "v1)",
+		},
+	}
+
+	validHeaderRegex := createPathMatch(&pathPrefix, "/")
+	validHeaderRegex.Headers = []v1beta1.HTTPHeaderMatch{
+		{
+			Type:  helpers.GetHeaderMatchTypePointer(v1beta1.HeaderMatchRegularExpression),
+			Name:  "Version",
+			Value: "v[0-9]",
+		},
+	}
+
+	exactHeader := createPathMatch(nil, "/")
+	exactHeader.Headers = []v1beta1.HTTPHeaderMatch{
+		{
+			Name:  "Version",
+			Value: "v1",
+		},
+	}
+	exactHeader.QueryParams = []v1beta1.HTTPQueryParamMatch{
+		{
+			Type:  helpers.GetQueryParamMatchTypePointer(v1beta1.QueryParamMatchExact),
+			Name:  "version",
+			Value: "v1",
+		},
+	}
+
+	// createRouteWithUnsupportedFilters creates a route with a rule with supported values, and rules with
+	// an unsupported filter and a backendRef with a supported and an unsupported filter.
+	createRouteWithUnsupportedFilters := func() *Route {
+		r := createRoute(prefix, prefix, prefix)
+
+		r.Source.Spec.Rules[0].Filters = []v1beta1.HTTPRouteFilter{
+			{
+				Type:            v1beta1.HTTPRouteFilterRequestRedirect,
+				RequestRedirect: &v1beta1.HTTPRequestRedirectFilter{},
+			},
+		}
+		r.Source.Spec.Rules[1].Filters = []v1beta1.HTTPRouteFilter{
+			{
+				Type:                  v1beta1.HTTPRouteFilterRequestHeaderModifier,
+				RequestHeaderModifier: &v1beta1.HTTPHeaderFilter{},
+			},
+		}
+		r.Source.Spec.Rules[2].BackendRefs = []v1beta1.HTTPBackendRef{
+			{
+				Filters: []v1beta1.HTTPRouteFilter{
+					{
+						Type:                  v1beta1.HTTPRouteFilterRequestHeaderModifier,
+						RequestHeaderModifier: &v1beta1.HTTPHeaderFilter{},
+					},
+					{
+						Type:                   v1beta1.HTTPRouteFilterResponseHeaderModifier,
+						ResponseHeaderModifier: &v1beta1.HTTPHeaderFilter{},
+					},
+				},
+			},
+		}
+
+		return r
+	}
+
+	// createRouteWithHeaderModifier creates a route with a rule with a backendRef with a RequestHeaderModifier.
+	createRouteWithHeaderModifier := func(modifier v1beta1.HTTPHeaderFilter) *Route {
+		r := createRoute(prefix)
+
+		r.Source.Spec.Rules[0].BackendRefs = []v1beta1.HTTPBackendRef{
+			{
+				Filters: []v1beta1.HTTPRouteFilter{
+					{
+						Type:                  v1beta1.HTTPRouteFilterRequestHeaderModifier,
+						RequestHeaderModifier: &modifier,
+					},
+				},
+			},
+		}
+
+		return r
+	}
+
+	tests := []struct {
+		route           *Route
+		expInvalidRules map[int]struct{}
+		expConditions   []conditions.Condition
+		msg             string
+		conformance     bool
+	}{
+		{
+			route: createRoute(validRegex, prefix),
+			msg:   "valid matches",
+		},
+		{
+			route:           createRoute(validRegex, invalidPathRegex),
+			expInvalidRules: map[int]struct{}{1: {}},
+			expConditions: []conditions.Condition{
+				conditions.NewRouteUnsupportedValue(
+					"Rules with unsupported values are not configured: rule 1 match 0: invalid regular expression " +
+						`"/coffee/[0-9" of the path: error parsing regexp: missing closing ]: ` + "`[0-9`",
+				),
+			},
+			msg: "invalid path regular expression",
+		},
+		{
+			route:           createRoute(invalidHeaderRegex, validRegex, invalidQueryParamRegex),
+			expInvalidRules: map[int]struct{}{0: {}, 2: {}},
+			expConditions: []conditions.Condition{
+				conditions.NewRouteUnsupportedValue(
+					"Rules with unsupported values are not configured: rule 0 match 0: invalid regular expression " +
+						`"(v1" of the header Version: error parsing regexp: missing closing ): ` + "`(v1`; " +
+						`rule 2 match 0: invalid regular expression "v1)" of the query param version: ` +
+						"error parsing regexp: unexpected ): `v1)`",
+				),
+			},
+			msg: "invalid header and query param regular expressions",
+		},
+		{
+			route: createRoute(validRegex, validHeaderRegex),
+			msg:   "unsupported match types",
+		},
+		{
+			route:           createRoute(validRegex, validHeaderRegex, exactHeader, invalidPathRegex),
+			conformance:     true,
+			expInvalidRules: map[int]struct{}{0: {}, 1: {}, 3: {}},
+			expConditions: []conditions.Condition{
+				conditions.NewRouteUnsupportedValue(
+					"Rules with unsupported values are not configured: " +
+						"rule 0 match 0: unsupported type RegularExpression of the path; " +
+						"rule 1 match 0: unsupported type RegularExpression of the header Version; " +
+						`rule 3 match 0: invalid regular expression "/coffee/[0-9" of the path: ` +
+						"error parsing regexp: missing closing ]: `[0-9`",
+				),
+			},
+			msg: "unsupported match types in conformance mode",
+		},
+		{
+			route: createRouteWithUnsupportedFilters(),
+			msg:   "unsupported filters",
+		},
+		{
+			route:           createRouteWithUnsupportedFilters(),
+			conformance:     true,
+			expInvalidRules: map[int]struct{}{1: {}, 2: {}},
+			expConditions: []conditions.Condition{
+				conditions.NewRouteUnsupportedValue(
+					"Rules with unsupported values are not configured: " +
+						"rule 1 filter 0: unsupported type RequestHeaderModifier; " +
+						"rule 2 backendRef 0 filter 1: unsupported type ResponseHeaderModifier",
+				),
+			},
+			msg: "unsupported filters in conformance mode",
+		},
+		{
+			route: createRouteWithHeaderModifier(v1beta1.HTTPHeaderFilter{
+				Set:    []v1beta1.HTTPHeader{{Name: "X-Version", Value: "v2"}},
+				Add:    []v1beta1.HTTPHeader{{Name: "x_env", Value: "canary, test"}},
+				Remove: []string{"X-Debug"},
+			}),
+			conformance: true,
+			msg:         "valid backendRef header modifier",
+		},
+		{
+			route: createRouteWithHeaderModifier(v1beta1.HTTPHeaderFilter{
+				Set: []v1beta1.HTTPHeader{{Name: "X-Version", Value: "$host"}},
+			}),
+			expInvalidRules: map[int]struct{}{0: {}},
+			expConditions: []conditions.Condition{
+				conditions.NewRouteUnsupportedValue(
+					"Rules with unsupported values are not configured: " +
+						"rule 0 backendRef 0 filter 0: the value of the header X-Version cannot include the $ character",
+				),
+			},
+			msg: "backendRef header modifier with a variable in a value",
+		},
+		{
+			route: createRouteWithHeaderModifier(v1beta1.HTTPHeaderFilter{
+				Remove: []string{"X.Version"},
+			}),
+			expInvalidRules: map[int]struct{}{0: {}},
+			expConditions: []conditions.Condition{
+				conditions.NewRouteUnsupportedValue(
+					"Rules with unsupported values are not configured: " +
+						`rule 0 backendRef 0 filter 0: unsupported header name "X.Version"; ` +
+						"must only include letters, digits, - and _",
+				),
+			},
+			msg: "backendRef header modifier with an unsupported header name",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			routes := map[types.NamespacedName]*Route{
+				{Namespace: "test", Name: "hr"}: test.route,
+			}
+
+			validateRouteRules(routes, test.conformance)
+
+			g.Expect(test.route.InvalidRules).To(Equal(test.expInvalidRules))
+			g.Expect(test.route.Conditions).To(Equal(test.expConditions))
+		})
+	}
+}
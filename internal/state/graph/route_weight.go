@@ -0,0 +1,27 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// WeightAnnotation is the HTTPRoute annotation that configures the weight of the HTTPRoute in a weighted split of
+// the traffic across multiple HTTPRoutes. The value must be an integer in the range 0-1000. When the rules of multiple
+// HTTPRoutes with the annotation have the same match for the same hostname, NGINX splits the requests that match it
+// across the backends of all such rules in proportion to the weights of the HTTPRoutes, instead of sending them to
+// the HTTPRoute with the highest precedence. For example, the HTTPRoutes with the weights 90 and 10 get 90% and 10%
+// of the requests.
+const WeightAnnotation = "k8s-gateway.nginx.org/weight"
+
+// maxRouteWeight is the maximum value of the WeightAnnotation.
+const maxRouteWeight = 1000
+
+// ParseRouteWeight parses the value of the WeightAnnotation.
+func ParseRouteWeight(v string) (int32, error) {
+	weight, err := strconv.ParseInt(v, 10, 32)
+	if err != nil || weight < 0 || weight > maxRouteWeight {
+		return 0, fmt.Errorf("must be an integer in the range 0-%d", maxRouteWeight)
+	}
+
+	return int32(weight), nil
+}
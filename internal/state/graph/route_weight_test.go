@@ -0,0 +1,61 @@
+package graph
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseRouteWeight(t *testing.T) {
+	tests := []struct {
+		value     string
+		msg       string
+		expWeight int32
+		expErr    bool
+	}{
+		{
+			value:     "80",
+			expWeight: 80,
+			msg:       "valid weight",
+		},
+		{
+			value:     "0",
+			expWeight: 0,
+			msg:       "zero weight",
+		},
+		{
+			value:     "1000",
+			expWeight: 1000,
+			msg:       "max weight",
+		},
+		{
+			value:  "1001",
+			expErr: true,
+			msg:    "weight above max",
+		},
+		{
+			value:  "-1",
+			expErr: true,
+			msg:    "negative weight",
+		},
+		{
+			value:  "half",
+			expErr: true,
+			msg:    "not an integer",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			weight, err := ParseRouteWeight(test.value)
+			if test.expErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+			g.Expect(weight).To(Equal(test.expWeight))
+		})
+	}
+}
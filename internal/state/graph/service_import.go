@@ -0,0 +1,58 @@
+package graph
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/manager/index"
+)
+
+// ServiceImportBackendKind is the kind of the backendRefs that reference multi-cluster ServiceImports.
+var ServiceImportBackendKind = BackendKind{Group: "multicluster.x-k8s.io", Kind: "ServiceImport"}
+
+// serviceImportSuffix is the suffix of the names of the Services that represent ServiceImports.
+// The names of Services can't include dots, so such names never collide with the names of real Services.
+const serviceImportSuffix = ".serviceimport"
+
+// ServiceImportServiceName returns the name of the Service that represents the ServiceImport with the name.
+func ServiceImportServiceName(name string) string {
+	return name + serviceImportSuffix
+}
+
+// ResolveServiceImport is a BackendResolver for the backendRefs of ServiceImports. It resolves a backendRef
+// to a Service that represents the ServiceImport. The Service has the MulticlusterServiceNameLabel with the name
+// of the ServiceImport, so that its endpoints are resolved from the EndpointSlices of the imported endpoints
+// rather than from the EndpointSlices of a Service.
+func ResolveServiceImport(
+	ref v1beta1.BackendRef,
+	routeNamespace string,
+	_ map[types.NamespacedName]*v1.Service,
+) (*v1.Service, int32, error) {
+	err := validateBackendRefNamespaceAndPort(ref, routeNamespace)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	port := int32(*ref.Port)
+
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: routeNamespace,
+			Name:      ServiceImportServiceName(string(ref.Name)),
+			Labels: map[string]string{
+				index.MulticlusterServiceNameLabel: string(ref.Name),
+			},
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{
+				{
+					Port: port,
+				},
+			},
+		},
+	}
+
+	return svc, port, nil
+}
@@ -0,0 +1,92 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/manager/index"
+)
+
+func TestResolveServiceImport(t *testing.T) {
+	createRef := func(namespace *string, port *int32) v1beta1.BackendRef {
+		return v1beta1.BackendRef{
+			BackendObjectReference: v1beta1.BackendObjectReference{
+				Group:     (*v1beta1.Group)(helpers.GetStringPointer("multicluster.x-k8s.io")),
+				Kind:      (*v1beta1.Kind)(helpers.GetStringPointer("ServiceImport")),
+				Name:      "coffee",
+				Namespace: (*v1beta1.Namespace)(namespace),
+				Port:      (*v1beta1.PortNumber)(port),
+			},
+		}
+	}
+
+	expSvc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "coffee.serviceimport",
+			Labels:    map[string]string{index.MulticlusterServiceNameLabel: "coffee"},
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{Port: 80}},
+		},
+	}
+
+	tests := []struct {
+		expSvc  *v1.Service
+		msg     string
+		ref     v1beta1.BackendRef
+		expPort int32
+		expErr  bool
+	}{
+		{
+			ref:     createRef(nil, helpers.GetInt32Pointer(80)),
+			expSvc:  expSvc,
+			expPort: 80,
+			msg:     "same namespace",
+		},
+		{
+			ref:     createRef(helpers.GetStringPointer("test"), helpers.GetInt32Pointer(80)),
+			expSvc:  expSvc,
+			expPort: 80,
+			msg:     "explicit namespace",
+		},
+		{
+			ref:    createRef(helpers.GetStringPointer("other"), helpers.GetInt32Pointer(80)),
+			expErr: true,
+			msg:    "cross-namespace",
+		},
+		{
+			ref:    createRef(nil, nil),
+			expErr: true,
+			msg:    "missing port",
+		},
+	}
+
+	for _, test := range tests {
+		svc, port, err := ResolveServiceImport(test.ref, "test", nil)
+
+		if test.expErr {
+			if err == nil {
+				t.Errorf("ResolveServiceImport() didn't return error for case %q", test.msg)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("ResolveServiceImport() returned unexpected error %v for case %q", err, test.msg)
+		}
+
+		if diff := cmp.Diff(test.expSvc, svc); diff != "" {
+			t.Errorf("ResolveServiceImport() mismatch on Service for case %q (-want +got):\n%s", test.msg, diff)
+		}
+
+		if port != test.expPort {
+			t.Errorf("ResolveServiceImport() returned port %d but expected %d for case %q", port, test.expPort, test.msg)
+		}
+	}
+}
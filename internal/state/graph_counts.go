@@ -0,0 +1,40 @@
+package state
+
+import (
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/metrics"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
+)
+
+// buildGraphCounts counts the resources of the Graph that NGINX is configured for by the Configuration.
+// If the Configuration is empty, because the GatewayClass is invalid or the Gateway doesn't exist,
+// nothing is programmed.
+func buildGraphCounts(g *graph.Graph, conf dataplane.Configuration) metrics.GraphCounts {
+	var counts metrics.GraphCounts
+
+	if g.Gateway == nil || g.GatewayClass == nil || !g.GatewayClass.Valid {
+		return counts
+	}
+
+	counts.Gateways = 1
+
+	for _, l := range g.Gateway.Listeners {
+		if l.Valid && !l.Disabled {
+			counts.Listeners++
+		}
+	}
+
+	for _, r := range g.Routes {
+		if len(r.ValidSectionNameRefs) > 0 {
+			counts.HTTPRoutes++
+		}
+	}
+
+	for _, u := range conf.Upstreams {
+		if u.ErrorMsg == "" {
+			counts.Upstreams++
+		}
+	}
+
+	return counts
+}
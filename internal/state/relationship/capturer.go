@@ -8,6 +8,7 @@ import (
 	"sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/manager/index"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
 )
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . Capturer
@@ -15,12 +16,17 @@ import (
 // Capturer captures relationships between Kubernetes objects and can be queried for whether a relationship exists
 // for a given object.
 //
-// Currently, it only captures relationships between HTTPRoutes and Services and Services and EndpointSlices,
-// but it can be extended to capture additional relationships.
-// The relationships between HTTPRoutes -> Services are many to 1,
+// Currently, it only captures relationships between HTTPRoutes and Services, Gateways and Services,
+// Services and EndpointSlices, and Gateways and Secrets, but it can be extended to capture additional relationships.
+// The relationships between HTTPRoutes -> Services, Gateways -> Services and Gateways -> Secrets are many to 1,
 // so these relationships are tracked using a counter.
-// A Service relationship exists if at least one HTTPRoute references it.
-// An EndpointSlice relationship exists, if its Service owner is referenced by at least one HTTPRoute.
+// A Service relationship exists if at least one HTTPRoute references it or at least one Gateway references it
+// as a default backend.
+// An EndpointSlice relationship exists, if its Service owner is referenced by at least one HTTPRoute or Gateway.
+// If the ServiceImports are enabled, a ServiceImport referenced by an HTTPRoute is tracked as the Service that
+// represents it, which owns the EndpointSlices of the endpoints imported from other clusters.
+// A Secret relationship exists if at least one Gateway references it in the TLS configuration of a Listener,
+// either as a certificate or as the bundle of the CA certificates for verifying the client certificates.
 type Capturer interface {
 	Capture(obj client.Object)
 	Remove(resourceType client.Object, nsname types.NamespacedName)
@@ -30,8 +36,14 @@ type Capturer interface {
 type (
 	// routeToServicesMap maps HTTPRoute names to the set of Services it references.
 	routeToServicesMap map[types.NamespacedName]map[types.NamespacedName]struct{}
-	// serviceRefCountMap maps Service names to the number of HTTPRoutes that reference it.
+	// serviceRefCountMap maps Service names to the number of HTTPRoutes and Gateways that reference it.
 	serviceRefCountMap map[types.NamespacedName]int
+	// gatewayToServicesMap maps Gateway names to the set of Services it references as default backends.
+	gatewayToServicesMap map[types.NamespacedName]map[types.NamespacedName]struct{}
+	// gatewayToSecretsMap maps Gateway names to the set of Secrets it references.
+	gatewayToSecretsMap map[types.NamespacedName]map[types.NamespacedName]struct{}
+	// secretRefCountMap maps Secret names to the number of Gateways that reference it.
+	secretRefCountMap map[types.NamespacedName]int
 )
 
 // CapturerImpl implements the Capturer interface.
@@ -39,14 +51,25 @@ type CapturerImpl struct {
 	routesToServices    routeToServicesMap
 	serviceRefCount     serviceRefCountMap
 	endpointSliceOwners map[types.NamespacedName]types.NamespacedName
+	gatewaysToSecrets   gatewayToSecretsMap
+	secretRefCount      secretRefCountMap
+	gatewaysToServices  gatewayToServicesMap
+	// serviceImports enables the tracking of the ServiceImports and their EndpointSlices.
+	serviceImports bool
 }
 
 // NewCapturerImpl creates a new instance of CapturerImpl.
-func NewCapturerImpl() *CapturerImpl {
+// serviceImports enables the tracking of the ServiceImports referenced by the HTTPRoutes and of the EndpointSlices
+// of the endpoints imported from other clusters.
+func NewCapturerImpl(serviceImports bool) *CapturerImpl {
 	return &CapturerImpl{
+		serviceImports:      serviceImports,
 		routesToServices:    make(map[types.NamespacedName]map[types.NamespacedName]struct{}),
 		serviceRefCount:     make(map[types.NamespacedName]int),
 		endpointSliceOwners: make(map[types.NamespacedName]types.NamespacedName),
+		gatewaysToSecrets:   make(map[types.NamespacedName]map[types.NamespacedName]struct{}),
+		secretRefCount:      make(map[types.NamespacedName]int),
+		gatewaysToServices:  make(map[types.NamespacedName]map[types.NamespacedName]struct{}),
 	}
 }
 
@@ -55,8 +78,18 @@ func (c *CapturerImpl) Capture(obj client.Object) {
 	switch o := obj.(type) {
 	case *v1beta1.HTTPRoute:
 		c.upsertForRoute(o)
+	case *v1beta1.Gateway:
+		c.upsertForGateway(o)
 	case *discoveryV1.EndpointSlice:
 		svcName := index.GetServiceNameFromEndpointSlice(o)
+		if svcName == "" && c.serviceImports {
+			// the EndpointSlices of the endpoints imported from other clusters are owned by the Service
+			// that represents their ServiceImport.
+			if importName := index.GetServiceImportNameFromEndpointSlice(o); importName != "" {
+				svcName = graph.ServiceImportServiceName(importName)
+			}
+		}
+
 		if svcName != "" {
 			c.endpointSliceOwners[client.ObjectKeyFromObject(o)] = types.NamespacedName{
 				Namespace: o.Namespace,
@@ -71,6 +104,8 @@ func (c *CapturerImpl) Remove(resourceType client.Object, nsname types.Namespace
 	switch resourceType.(type) {
 	case *v1beta1.HTTPRoute:
 		c.deleteForRoute(nsname)
+	case *v1beta1.Gateway:
+		c.deleteForGateway(nsname)
 	case *discoveryV1.EndpointSlice:
 		delete(c.endpointSliceOwners, nsname)
 	}
@@ -84,6 +119,8 @@ func (c *CapturerImpl) Exists(resourceType client.Object, nsname types.Namespace
 	case *discoveryV1.EndpointSlice:
 		svcOwner, exists := c.endpointSliceOwners[nsname]
 		return exists && c.serviceRefCount[svcOwner] > 0
+	case *v1.Secret:
+		return c.secretRefCount[nsname] > 0
 	}
 
 	return false
@@ -94,21 +131,16 @@ func (c *CapturerImpl) GetRefCountForService(svcName types.NamespacedName) int {
 	return c.serviceRefCount[svcName]
 }
 
+// GetRefCountForSecret is used for unit testing purposes. It is not exposed through the Capturer interface.
+func (c *CapturerImpl) GetRefCountForSecret(secretName types.NamespacedName) int {
+	return c.secretRefCount[secretName]
+}
+
 func (c *CapturerImpl) upsertForRoute(route *v1beta1.HTTPRoute) {
 	oldServices := c.routesToServices[client.ObjectKeyFromObject(route)]
-	newServices := getBackendServiceNamesFromRoute(route)
+	newServices := getBackendServiceNamesFromRoute(route, c.serviceImports)
 
-	for svc := range oldServices {
-		if _, exist := newServices[svc]; !exist {
-			c.decrementRefCount(svc)
-		}
-	}
-
-	for svc := range newServices {
-		if _, exist := oldServices[svc]; !exist {
-			c.serviceRefCount[svc]++
-		}
-	}
+	updateRefCount(c.serviceRefCount, oldServices, newServices)
 
 	c.routesToServices[client.ObjectKeyFromObject(route)] = newServices
 }
@@ -117,30 +149,93 @@ func (c *CapturerImpl) deleteForRoute(routeName types.NamespacedName) {
 	services := c.routesToServices[routeName]
 
 	for svc := range services {
-		c.decrementRefCount(svc)
+		decrementRefCount(c.serviceRefCount, svc)
 	}
 
 	delete(c.routesToServices, routeName)
 }
 
-func (c *CapturerImpl) decrementRefCount(svcName types.NamespacedName) {
-	if count, exist := c.serviceRefCount[svcName]; exist {
+func (c *CapturerImpl) upsertForGateway(gw *v1beta1.Gateway) {
+	gwName := client.ObjectKeyFromObject(gw)
+
+	oldSecrets := c.gatewaysToSecrets[gwName]
+	newSecrets := getSecretNamesFromGateway(gw)
+
+	updateRefCount(c.secretRefCount, oldSecrets, newSecrets)
+
+	c.gatewaysToSecrets[gwName] = newSecrets
+
+	oldServices := c.gatewaysToServices[gwName]
+	newServices := getDefaultBackendServiceNamesFromGateway(gw)
+
+	updateRefCount(c.serviceRefCount, oldServices, newServices)
+
+	c.gatewaysToServices[gwName] = newServices
+}
+
+func (c *CapturerImpl) deleteForGateway(gwName types.NamespacedName) {
+	secrets := c.gatewaysToSecrets[gwName]
+
+	for secret := range secrets {
+		decrementRefCount(c.secretRefCount, secret)
+	}
+
+	delete(c.gatewaysToSecrets, gwName)
+
+	services := c.gatewaysToServices[gwName]
+
+	for svc := range services {
+		decrementRefCount(c.serviceRefCount, svc)
+	}
+
+	delete(c.gatewaysToServices, gwName)
+}
+
+// updateRefCount decrements the ref counts of the names that are only in oldNames and increments the ref counts
+// of the names that are only in newNames.
+func updateRefCount(refCount map[types.NamespacedName]int, oldNames, newNames map[types.NamespacedName]struct{}) {
+	for name := range oldNames {
+		if _, exist := newNames[name]; !exist {
+			decrementRefCount(refCount, name)
+		}
+	}
+
+	for name := range newNames {
+		if _, exist := oldNames[name]; !exist {
+			refCount[name]++
+		}
+	}
+}
+
+func decrementRefCount(refCount map[types.NamespacedName]int, name types.NamespacedName) {
+	if count, exist := refCount[name]; exist {
 		if count == 1 {
-			delete(c.serviceRefCount, svcName)
+			delete(refCount, name)
 
 			return
 		}
 
-		c.serviceRefCount[svcName]--
+		refCount[name]--
 	}
 }
 
-func getBackendServiceNamesFromRoute(hr *v1beta1.HTTPRoute) map[types.NamespacedName]struct{} {
+// getBackendServiceNamesFromRoute returns the names of the Services referenced by the backendRefs of the HTTPRoute.
+// If serviceImports is true, the ServiceImports are included as the Services that represent them.
+func getBackendServiceNamesFromRoute(
+	hr *v1beta1.HTTPRoute,
+	serviceImports bool,
+) map[types.NamespacedName]struct{} {
 	svcNames := make(map[types.NamespacedName]struct{})
 
 	for _, rule := range hr.Spec.Rules {
 		for _, ref := range rule.BackendRefs {
-			if ref.Kind != nil && *ref.Kind != "Service" {
+			name := string(ref.Name)
+
+			switch {
+			case ref.Kind == nil || *ref.Kind == "Service":
+			case serviceImports && *ref.Kind == v1beta1.Kind(graph.ServiceImportBackendKind.Kind):
+				name = graph.ServiceImportServiceName(name)
+			default:
 				continue
 			}
 
@@ -149,9 +244,64 @@ func getBackendServiceNamesFromRoute(hr *v1beta1.HTTPRoute) map[types.Namespaced
 				ns = string(*ref.Namespace)
 			}
 
-			svcNames[types.NamespacedName{Namespace: ns, Name: string(ref.Name)}] = struct{}{}
+			svcNames[types.NamespacedName{Namespace: ns, Name: name}] = struct{}{}
 		}
 	}
 
 	return svcNames
 }
+
+func getSecretNamesFromGateway(gw *v1beta1.Gateway) map[types.NamespacedName]struct{} {
+	secretNames := make(map[types.NamespacedName]struct{})
+
+	for _, l := range gw.Spec.Listeners {
+		if l.TLS == nil {
+			continue
+		}
+
+		for _, ref := range l.TLS.CertificateRefs {
+			if ref.Kind != nil && *ref.Kind != "Secret" {
+				continue
+			}
+
+			ns := gw.Namespace
+			if ref.Namespace != nil {
+				ns = string(*ref.Namespace)
+			}
+
+			secretNames[types.NamespacedName{Namespace: ns, Name: string(ref.Name)}] = struct{}{}
+		}
+
+		if name, exists := l.TLS.Options[graph.ClientCertificateTLSOption]; exists {
+			secretNames[types.NamespacedName{Namespace: gw.Namespace, Name: string(name)}] = struct{}{}
+		}
+	}
+
+	return secretNames
+}
+
+// getDefaultBackendServiceNamesFromGateway returns the names of the Services that the Gateway references as default
+// backends through the DefaultBackendAnnotation. If the annotation is invalid, it returns no names.
+func getDefaultBackendServiceNamesFromGateway(gw *v1beta1.Gateway) map[types.NamespacedName]struct{} {
+	svcNames := make(map[types.NamespacedName]struct{})
+
+	value, exists := gw.Annotations[graph.DefaultBackendAnnotation]
+	if !exists {
+		return svcNames
+	}
+
+	refs, err := graph.ParseDefaultBackendRefs(value)
+	if err != nil {
+		return svcNames
+	}
+
+	if refs.All != nil {
+		svcNames[types.NamespacedName{Namespace: gw.Namespace, Name: refs.All.Name}] = struct{}{}
+	}
+
+	for _, ref := range refs.Listeners {
+		svcNames[types.NamespacedName{Namespace: gw.Namespace, Name: ref.Name}] = struct{}{}
+	}
+
+	return svcNames
+}
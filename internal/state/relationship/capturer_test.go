@@ -11,6 +11,7 @@ import (
 
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/manager/index"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/relationship"
 )
 
@@ -77,7 +78,7 @@ var _ = Describe("Capturer", func() {
 
 	Describe("Capture service relationships for routes", func() {
 		BeforeEach(OncePerOrdered, func() {
-			capturer = relationship.NewCapturerImpl()
+			capturer = relationship.NewCapturerImpl(false)
 		})
 
 		assertServiceExists := func(svcName types.NamespacedName, exists bool, refCount int) {
@@ -251,7 +252,7 @@ var _ = Describe("Capturer", func() {
 			)
 
 			BeforeEach(OncePerOrdered, func() {
-				capturer = relationship.NewCapturerImpl()
+				capturer = relationship.NewCapturerImpl(false)
 			})
 
 			Describe("Normal cases", Ordered, func() {
@@ -313,22 +314,272 @@ var _ = Describe("Capturer", func() {
 				})
 			})
 		})
+		Describe("Capture ServiceImport relationships", func() {
+			var (
+				importRoute = createRoute("hr-import", []v1beta1.HTTPRouteRule{
+					{
+						BackendRefs: []v1beta1.HTTPBackendRef{
+							{
+								BackendRef: v1beta1.BackendRef{
+									BackendObjectReference: v1beta1.BackendObjectReference{
+										Group: (*v1beta1.Group)(helpers.GetStringPointer("multicluster.x-k8s.io")),
+										Kind:  (*v1beta1.Kind)(helpers.GetStringPointer("ServiceImport")),
+										Name:  "coffee",
+									},
+								},
+							},
+						},
+					},
+				})
+
+				importSlice = &discoveryV1.EndpointSlice{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "test",
+						Name:      "imported-es",
+						Labels:    map[string]string{index.MulticlusterServiceNameLabel: "coffee"},
+					},
+				}
+
+				importSvc = types.NamespacedName{
+					Namespace: "test",
+					Name:      graph.ServiceImportServiceName("coffee"),
+				}
+				importSliceName = types.NamespacedName{Namespace: importSlice.Namespace, Name: importSlice.Name}
+			)
+
+			It("reports the relationships if the ServiceImports are enabled", func() {
+				capturer = relationship.NewCapturerImpl(true)
+
+				capturer.Capture(importRoute)
+				capturer.Capture(importSlice)
+
+				Expect(capturer.Exists(&v1.Service{}, importSvc)).To(BeTrue())
+				Expect(capturer.Exists(&discoveryV1.EndpointSlice{}, importSliceName)).To(BeTrue())
+			})
+			It("does not report the relationships if the ServiceImports are disabled", func() {
+				capturer = relationship.NewCapturerImpl(false)
+
+				capturer.Capture(importRoute)
+				capturer.Capture(importSlice)
+
+				Expect(capturer.Exists(&v1.Service{}, importSvc)).To(BeFalse())
+				Expect(capturer.Exists(&discoveryV1.EndpointSlice{}, importSliceName)).To(BeFalse())
+			})
+		})
+		Describe("Capture default backend relationships for gateways", Ordered, func() {
+			createGateway := func(name string, defaultBackends string) *v1beta1.Gateway {
+				return &v1beta1.Gateway{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:   "test",
+						Name:        name,
+						Annotations: map[string]string{graph.DefaultBackendAnnotation: defaultBackends},
+					},
+				}
+			}
+
+			var (
+				gw1 = createGateway("gw1", "svc1:80,https=svc2:8443")
+				gw2 = createGateway("gw2", "svc1:80")
+
+				gw1Name = types.NamespacedName{Namespace: gw1.Namespace, Name: gw1.Name}
+				gw2Name = types.NamespacedName{Namespace: gw2.Namespace, Name: gw2.Name}
+
+				slice = &discoveryV1.EndpointSlice{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "test",
+						Name:      "es-svc2",
+						Labels:    map[string]string{index.KubernetesServiceNameLabel: "svc2"},
+					},
+				}
+				sliceName = types.NamespacedName{Namespace: slice.Namespace, Name: slice.Name}
+			)
+
+			assertServiceExists := func(svcName types.NamespacedName, exists bool, refCount int) {
+				ExpectWithOffset(1, capturer.Exists(&v1.Service{}, svcName)).To(Equal(exists))
+				ExpectWithOffset(1, capturer.GetRefCountForService(svcName)).To(Equal(refCount))
+			}
+
+			BeforeAll(func() {
+				capturer = relationship.NewCapturerImpl(false)
+				capturer.Capture(slice)
+			})
+
+			When("a gateway with default backends is captured", func() {
+				It("reports service and endpoint slice relationships", func() {
+					capturer.Capture(gw1)
+
+					assertServiceExists(svc1, true, 1)
+					assertServiceExists(svc2, true, 1)
+					Expect(capturer.Exists(&discoveryV1.EndpointSlice{}, sliceName)).To(BeTrue())
+				})
+			})
+			When("another gateway and a route that reference the same service are captured", func() {
+				It("reports all service relationships", func() {
+					capturer.Capture(gw2)
+					capturer.Capture(hr1)
+
+					assertServiceExists(svc1, true, 3)
+					assertServiceExists(svc2, true, 1)
+				})
+			})
+			When("a default backend is removed from a captured gateway", func() {
+				It("removes the correct service relationship", func() {
+					capturer.Capture(createGateway("gw1", "svc1:80"))
+
+					assertServiceExists(svc1, true, 3)
+					assertServiceExists(svc2, false, 0)
+					Expect(capturer.Exists(&discoveryV1.EndpointSlice{}, sliceName)).To(BeFalse())
+				})
+			})
+			When("the gateways are removed", func() {
+				It("reports the service relationship of the route", func() {
+					capturer.Remove(&v1beta1.Gateway{}, gw1Name)
+					capturer.Remove(&v1beta1.Gateway{}, gw2Name)
+
+					assertServiceExists(svc1, true, 1)
+				})
+			})
+			When("a gateway with an invalid annotation is captured", func() {
+				It("doesn't report service relationships", func() {
+					capturer.Capture(createGateway("gw1", "svc2"))
+
+					assertServiceExists(svc2, false, 0)
+				})
+			})
+		})
+		Describe("Capture secret relationships for gateways", Ordered, func() {
+			createGateway := func(name string, secretNames ...v1beta1.ObjectName) *v1beta1.Gateway {
+				refs := make([]v1beta1.SecretObjectReference, 0, len(secretNames))
+				for _, secretName := range secretNames {
+					refs = append(refs, v1beta1.SecretObjectReference{
+						Kind: (*v1beta1.Kind)(helpers.GetStringPointer("Secret")),
+						Name: secretName,
+					})
+				}
+
+				return &v1beta1.Gateway{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: name},
+					Spec: v1beta1.GatewaySpec{
+						Listeners: []v1beta1.Listener{
+							{
+								Name: "http",
+							},
+							{
+								Name: "https",
+								TLS:  &v1beta1.GatewayTLSConfig{CertificateRefs: refs},
+							},
+						},
+					},
+				}
+			}
+
+			var (
+				gw1 = createGateway("gw1", "secret1", "secret2")
+				gw2 = createGateway("gw2", "secret1")
+
+				gw1Name = types.NamespacedName{Namespace: gw1.Namespace, Name: gw1.Name}
+				gw2Name = types.NamespacedName{Namespace: gw2.Namespace, Name: gw2.Name}
+
+				secret1 = types.NamespacedName{Namespace: "test", Name: "secret1"}
+				secret2 = types.NamespacedName{Namespace: "test", Name: "secret2"}
+			)
+
+			assertSecretExists := func(secretName types.NamespacedName, exists bool, refCount int) {
+				ExpectWithOffset(1, capturer.Exists(&v1.Secret{}, secretName)).To(Equal(exists))
+				ExpectWithOffset(1, capturer.GetRefCountForSecret(secretName)).To(Equal(refCount))
+			}
+
+			BeforeAll(func() {
+				capturer = relationship.NewCapturerImpl(false)
+			})
+
+			When("a gateway with listeners that reference secrets is captured", func() {
+				It("reports secret relationships", func() {
+					capturer.Capture(gw1)
+
+					assertSecretExists(secret1, true, 1)
+					assertSecretExists(secret2, true, 1)
+				})
+			})
+			When("another gateway that references the same secret is captured", func() {
+				It("reports all secret relationships", func() {
+					capturer.Capture(gw2)
+
+					assertSecretExists(secret1, true, 2)
+					assertSecretExists(secret2, true, 1)
+				})
+			})
+			When("a secret is removed from a captured gateway", func() {
+				It("removes the correct secret relationship", func() {
+					capturer.Capture(createGateway("gw1", "secret1"))
+
+					assertSecretExists(secret1, true, 2)
+					assertSecretExists(secret2, false, 0)
+				})
+			})
+			When("a gateway is removed", func() {
+				It("reports remaining secret relationships", func() {
+					capturer.Remove(&v1beta1.Gateway{}, gw1Name)
+
+					assertSecretExists(secret1, true, 1)
+				})
+			})
+			When("the final gateway is removed", func() {
+				It("removes all secret relationships", func() {
+					capturer.Remove(&v1beta1.Gateway{}, gw2Name)
+
+					assertSecretExists(secret1, false, 0)
+					assertSecretExists(secret2, false, 0)
+				})
+			})
+		})
+		Describe("Capture client CA secret relationships for gateways", func() {
+			BeforeEach(func() {
+				capturer = relationship.NewCapturerImpl(false)
+			})
+
+			It("reports the relationship with the client CA secret", func() {
+				gw := &v1beta1.Gateway{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "gw"},
+					Spec: v1beta1.GatewaySpec{
+						Listeners: []v1beta1.Listener{
+							{
+								Name: "https",
+								TLS: &v1beta1.GatewayTLSConfig{
+									CertificateRefs: []v1beta1.SecretObjectReference{{Name: "secret"}},
+									Options: map[v1beta1.AnnotationKey]v1beta1.AnnotationValue{
+										graph.ClientCertificateTLSOption: "ca",
+									},
+								},
+							},
+						},
+					},
+				}
+				caSecret := types.NamespacedName{Namespace: "test", Name: "ca"}
+
+				capturer.Capture(gw)
+				Expect(capturer.Exists(&v1.Secret{}, caSecret)).To(BeTrue())
+
+				capturer.Remove(&v1beta1.Gateway{}, types.NamespacedName{Namespace: "test", Name: "gw"})
+				Expect(capturer.Exists(&v1.Secret{}, caSecret)).To(BeFalse())
+			})
+		})
 		Describe("Edge cases", func() {
 			BeforeEach(func() {
-				capturer = relationship.NewCapturerImpl()
+				capturer = relationship.NewCapturerImpl(false)
 			})
 			It("Capture does not panic when passed an unsupported resource type", func() {
 				Expect(func() {
-					capturer.Capture(&v1beta1.Gateway{})
+					capturer.Capture(&v1beta1.GatewayClass{})
 				}).ToNot(Panic())
 			})
 			It("Remove does not panic when passed an unsupported resource type", func() {
 				Expect(func() {
-					capturer.Remove(&v1beta1.Gateway{}, types.NamespacedName{})
+					capturer.Remove(&v1beta1.GatewayClass{}, types.NamespacedName{})
 				}).ToNot(Panic())
 			})
 			It("Exist returns false if passed an unsupported resource type", func() {
-				Expect(capturer.Exists(&v1beta1.Gateway{}, types.NamespacedName{})).To(BeFalse())
+				Expect(capturer.Exists(&v1beta1.GatewayClass{}, types.NamespacedName{})).To(BeFalse())
 			})
 		})
 	})
@@ -9,6 +9,7 @@ import (
 	"sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
 )
 
 func TestGetBackendServiceNamesFromRoute(t *testing.T) {
@@ -70,6 +71,15 @@ func TestGetBackendServiceNamesFromRoute(t *testing.T) {
 						},
 					),
 				},
+				{
+					BackendRefs: getModifiedRefs("service-import",
+						func(refs []v1beta1.HTTPBackendRef) []v1beta1.HTTPBackendRef {
+							refs[0].Group = (*v1beta1.Group)(helpers.GetStringPointer("multicluster.x-k8s.io"))
+							refs[0].Kind = (*v1beta1.Kind)(helpers.GetStringPointer("ServiceImport"))
+							return refs
+						},
+					),
+				},
 				{
 					BackendRefs: nil,
 				},
@@ -103,17 +113,126 @@ func TestGetBackendServiceNamesFromRoute(t *testing.T) {
 	}
 
 	expNames := map[types.NamespacedName]struct{}{
-		{Namespace: "test", Name: "svc1"}:               {},
-		{Namespace: "test", Name: "nil-namespace"}:      {},
-		{Namespace: "not-test", Name: "diff-namespace"}: {},
-		{Namespace: "test", Name: "svc2"}:               {},
-		{Namespace: "test", Name: "multiple-refs"}:      {},
-		{Namespace: "test", Name: "multiple-refs2"}:     {},
+		{Namespace: "test", Name: "svc1"}:                         {},
+		{Namespace: "test", Name: "nil-namespace"}:                {},
+		{Namespace: "not-test", Name: "diff-namespace"}:           {},
+		{Namespace: "test", Name: "service-import.serviceimport"}: {},
+		{Namespace: "test", Name: "svc2"}:                         {},
+		{Namespace: "test", Name: "multiple-refs"}:                {},
+		{Namespace: "test", Name: "multiple-refs2"}:               {},
 	}
-	names := getBackendServiceNamesFromRoute(hr)
+	names := getBackendServiceNamesFromRoute(hr, true)
 	if diff := cmp.Diff(expNames, names); diff != "" {
 		t.Errorf("getBackendServiceNamesFromRoute() mismatch (-want +got):\n%s", diff)
 	}
+
+	// without the ServiceImports
+
+	delete(expNames, types.NamespacedName{Namespace: "test", Name: "service-import.serviceimport"})
+	names = getBackendServiceNamesFromRoute(hr, false)
+	if diff := cmp.Diff(expNames, names); diff != "" {
+		t.Errorf("getBackendServiceNamesFromRoute() without ServiceImports mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGetSecretNamesFromGateway(t *testing.T) {
+	gw := &v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test"},
+		Spec: v1beta1.GatewaySpec{
+			Listeners: []v1beta1.Listener{
+				{
+					Name: "http",
+				},
+				{
+					Name: "https-1",
+					TLS: &v1beta1.GatewayTLSConfig{
+						CertificateRefs: []v1beta1.SecretObjectReference{
+							{
+								Kind: (*v1beta1.Kind)(helpers.GetStringPointer("Secret")),
+								Name: "secret1",
+							},
+							{
+								Kind:      (*v1beta1.Kind)(helpers.GetStringPointer("Secret")),
+								Name:      "diff-namespace",
+								Namespace: (*v1beta1.Namespace)(helpers.GetStringPointer("not-test")),
+							},
+							{
+								Kind: (*v1beta1.Kind)(helpers.GetStringPointer("Invalid")),
+								Name: "invalid-kind",
+							},
+						},
+					},
+				},
+				{
+					Name: "https-2",
+					TLS: &v1beta1.GatewayTLSConfig{
+						CertificateRefs: []v1beta1.SecretObjectReference{
+							{
+								Name: "secret1", // duplicate
+							},
+							{
+								Name: "nil-kind",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	expNames := map[types.NamespacedName]struct{}{
+		{Namespace: "test", Name: "secret1"}:            {},
+		{Namespace: "not-test", Name: "diff-namespace"}: {},
+		{Namespace: "test", Name: "nil-kind"}:           {},
+	}
+	names := getSecretNamesFromGateway(gw)
+	if diff := cmp.Diff(expNames, names); diff != "" {
+		t.Errorf("getSecretNamesFromGateway() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGetDefaultBackendServiceNamesFromGateway(t *testing.T) {
+	createGateway := func(annotations map[string]string) *v1beta1.Gateway {
+		return &v1beta1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Annotations: annotations},
+		}
+	}
+
+	tests := []struct {
+		gw       *v1beta1.Gateway
+		expNames map[types.NamespacedName]struct{}
+		msg      string
+	}{
+		{
+			gw:       createGateway(nil),
+			expNames: map[types.NamespacedName]struct{}{},
+			msg:      "no annotation",
+		},
+		{
+			gw: createGateway(map[string]string{
+				graph.DefaultBackendAnnotation: "svc1:80,http=svc2:80,https=svc1:443",
+			}),
+			expNames: map[types.NamespacedName]struct{}{
+				{Namespace: "test", Name: "svc1"}: {},
+				{Namespace: "test", Name: "svc2"}: {},
+			},
+			msg: "default backends",
+		},
+		{
+			gw:       createGateway(map[string]string{graph.DefaultBackendAnnotation: "svc1"}),
+			expNames: map[types.NamespacedName]struct{}{},
+			msg:      "invalid annotation",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			names := getDefaultBackendServiceNamesFromGateway(test.gw)
+			if diff := cmp.Diff(test.expNames, names); diff != "" {
+				t.Errorf("getDefaultBackendServiceNamesFromGateway() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
 }
 
 func TestCapturerImpl_DecrementRouteCount(t *testing.T) {
@@ -143,7 +262,7 @@ func TestCapturerImpl_DecrementRouteCount(t *testing.T) {
 		},
 	}
 
-	capturer := NewCapturerImpl()
+	capturer := NewCapturerImpl(false)
 	svc := types.NamespacedName{Namespace: "test", Name: "svc"}
 
 	for _, tc := range testcases {
@@ -151,7 +270,7 @@ func TestCapturerImpl_DecrementRouteCount(t *testing.T) {
 			capturer.serviceRefCount[svc] = tc.startingRefCount
 		}
 
-		capturer.decrementRefCount(svc)
+		decrementRefCount(capturer.serviceRefCount, svc)
 
 		count, exists := capturer.serviceRefCount[svc]
 		if tc.exists != exists {
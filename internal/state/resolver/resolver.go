@@ -31,11 +31,18 @@ type Endpoint struct {
 // ServiceResolverImpl implements ServiceResolver.
 type ServiceResolverImpl struct {
 	client client.Client
+	// serviceImports enables the resolution of the Services that represent multi-cluster ServiceImports.
+	serviceImports bool
 }
 
 // NewServiceResolverImpl creates a new instance of a ServiceResolverImpl.
-func NewServiceResolverImpl(client client.Client) *ServiceResolverImpl {
-	return &ServiceResolverImpl{client: client}
+// serviceImports enables the resolution of the Services with the index.MulticlusterServiceNameLabel to the endpoints
+// imported from other clusters. Otherwise, such Services are resolved as any other Service.
+func NewServiceResolverImpl(client client.Client, serviceImports bool) *ServiceResolverImpl {
+	return &ServiceResolverImpl{
+		client:         client,
+		serviceImports: serviceImports,
+	}
 }
 
 // Resolve resolves a Service and Port to a list of Endpoints.
@@ -45,6 +52,10 @@ func (e *ServiceResolverImpl) Resolve(ctx context.Context, svc *v1.Service, port
 		return nil, fmt.Errorf("cannot resolve a nil Service")
 	}
 
+	if importName, exists := svc.Labels[index.MulticlusterServiceNameLabel]; exists && e.serviceImports {
+		return e.resolveServiceImport(ctx, svc, importName)
+	}
+
 	// We list EndpointSlices using the Service Name Index Field we added as an index to the EndpointSlice cache.
 	// This allows us to perform a quick lookup of all EndpointSlices for a Service.
 	var endpointSliceList discoveryV1.EndpointSliceList
@@ -62,6 +73,66 @@ func (e *ServiceResolverImpl) Resolve(ctx context.Context, svc *v1.Service, port
 	return resolveEndpoints(svc, port, endpointSliceList, initEndpointSetWithCalculatedSize)
 }
 
+// resolveServiceImport resolves the Service that represents a multi-cluster ServiceImport to the endpoints
+// imported from other clusters.
+func (e *ServiceResolverImpl) resolveServiceImport(
+	ctx context.Context,
+	svc *v1.Service,
+	importName string,
+) ([]Endpoint, error) {
+	var endpointSliceList discoveryV1.EndpointSliceList
+	err := e.client.List(
+		ctx,
+		&endpointSliceList,
+		client.MatchingLabels{index.MulticlusterServiceNameLabel: importName},
+		client.InNamespace(svc.Namespace),
+	)
+
+	if err != nil || len(endpointSliceList.Items) == 0 {
+		return nil, fmt.Errorf("no imported endpoints found for ServiceImport %s/%s", svc.Namespace, importName)
+	}
+
+	return resolveImportedEndpoints(svc.Namespace, importName, endpointSliceList)
+}
+
+// resolveImportedEndpoints returns the ready endpoints of the EndpointSlices of a ServiceImport.
+// Only the ServiceImports with a single port are supported: the EndpointSlices with multiple ports are ignored,
+// because the ports of a ServiceImport can't be matched to the ports of its endpoints without the ServiceImport.
+func resolveImportedEndpoints(
+	namespace string,
+	importName string,
+	endpointSliceList discoveryV1.EndpointSliceList,
+) ([]Endpoint, error) {
+	endpointSet := make(map[Endpoint]struct{})
+
+	for _, eps := range endpointSliceList.Items {
+		if eps.AddressType != discoveryV1.AddressTypeIPv4 || len(eps.Ports) != 1 || eps.Ports[0].Port == nil {
+			continue
+		}
+
+		for _, endpoint := range eps.Endpoints {
+			if !endpointReady(endpoint) {
+				continue
+			}
+
+			for _, address := range endpoint.Addresses {
+				endpointSet[Endpoint{Address: address, Port: *eps.Ports[0].Port}] = struct{}{}
+			}
+		}
+	}
+
+	if len(endpointSet) == 0 {
+		return nil, fmt.Errorf("no valid imported endpoints found for ServiceImport %s/%s", namespace, importName)
+	}
+
+	endpoints := make([]Endpoint, 0, len(endpointSet))
+	for ep := range endpointSet {
+		endpoints = append(endpoints, ep)
+	}
+
+	return endpoints, nil
+}
+
 type initEndpointSetFunc func([]discoveryV1.EndpointSlice) map[Endpoint]struct{}
 
 func initEndpointSetWithCalculatedSize(endpointSlices []discoveryV1.EndpointSlice) map[Endpoint]struct{} {
@@ -191,7 +262,9 @@ func filterEndpointSliceList(
 // findPort locates the port in the slice of EndpointPort that matches the ServicePort name.
 // The Kubernetes EndpointSlice controller handles matching the TargetPort of a ServicePort to the container port of
 // an endpoint. All we have to do is find the port with the same name as the ServicePort.
-// If a ServicePort is unnamed, then the EndpointPort will also be unnamed (empty string).
+// If a ServicePort is unnamed, then the EndpointPort will also be unnamed (empty string or nil).
+// The returned port is the port of the endpoint (the TargetPort of the ServicePort resolved to the container port),
+// not the port of the ServicePort.
 //
 // If an EndpointPort port is nil -- indicating all ports are valid --
 // the default port for the ServicePort is returned.
@@ -205,7 +278,13 @@ func findPort(ports []discoveryV1.EndpointPort, svcPort v1.ServicePort) int32 {
 			return getDefaultPort(svcPort)
 		}
 
-		if p.Name != nil && *p.Name == portName {
+		// the name of an EndpointPort is optional, so a nil name is the same as an empty name.
+		name := ""
+		if p.Name != nil {
+			name = *p.Name
+		}
+
+		if name == portName {
 			return *p.Port
 		}
 	}
@@ -458,6 +458,20 @@ func TestFindPort(t *testing.T) {
 			},
 			expPort: 8080,
 		},
+		{
+			msg: "unnamed service port; nil endpoint port name",
+			ports: []discoveryV1.EndpointPort{
+				{
+					Name: nil,
+					Port: helpers.GetInt32Pointer(8080),
+				},
+			},
+			svcPort: v1.ServicePort{
+				Port:       80,
+				TargetPort: intstr.FromInt(8080),
+			},
+			expPort: 8080,
+		},
 	}
 	for _, tc := range testcases {
 		port := findPort(tc.ports, tc.svcPort)
@@ -473,6 +487,82 @@ func TestFindPort(t *testing.T) {
 	}
 }
 
+func TestResolveEndpointsTargetPort(t *testing.T) {
+	// The EndpointSlice controller resolves the TargetPort of a ServicePort to the container port of the endpoints.
+	// The Endpoints must use that port rather than the port of the ServicePort.
+	createEndpointSlice := func(name *string) discoveryV1.EndpointSlice {
+		return discoveryV1.EndpointSlice{
+			AddressType: discoveryV1.AddressTypeIPv4,
+			Endpoints: []discoveryV1.Endpoint{
+				{
+					Addresses:  []string{"10.0.0.1"},
+					Conditions: discoveryV1.EndpointConditions{Ready: helpers.GetBoolPointer(true)},
+				},
+			},
+			Ports: []discoveryV1.EndpointPort{
+				{
+					Name: name,
+					Port: helpers.GetInt32Pointer(8080),
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		svcPort       v1.ServicePort
+		endpointSlice discoveryV1.EndpointSlice
+		msg           string
+	}{
+		{
+			svcPort: v1.ServicePort{
+				Port:       80,
+				TargetPort: intstr.FromInt(8080),
+			},
+			endpointSlice: createEndpointSlice(helpers.GetStringPointer("")),
+			msg:           "numeric target port",
+		},
+		{
+			svcPort: v1.ServicePort{
+				Name:       svcPortName,
+				Port:       80,
+				TargetPort: intstr.FromString("http"),
+			},
+			endpointSlice: createEndpointSlice(&svcPortName),
+			msg:           "named target port",
+		},
+		{
+			svcPort: v1.ServicePort{
+				Port:       80,
+				TargetPort: intstr.FromInt(8080),
+			},
+			endpointSlice: createEndpointSlice(nil),
+			msg:           "numeric target port; nil endpoint port name",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			svc := &v1.Service{
+				Spec: v1.ServiceSpec{
+					Ports: []v1.ServicePort{test.svcPort},
+				},
+			}
+
+			endpoints, err := resolveEndpoints(
+				svc,
+				80,
+				discoveryV1.EndpointSliceList{Items: []discoveryV1.EndpointSlice{test.endpointSlice}},
+				initEndpointSetWithCalculatedSize,
+			)
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(endpoints).To(Equal([]Endpoint{{Address: "10.0.0.1", Port: 8080}}))
+		})
+	}
+}
+
 func TestCalculateReadyEndpoints(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -603,3 +693,85 @@ func bench(b *testing.B, svc *v1.Service, list discoveryV1.EndpointSliceList, in
 		}
 	}
 }
+
+func TestResolveImportedEndpoints(t *testing.T) {
+	createEndpointSlice := func(
+		addressType discoveryV1.AddressType,
+		ready bool,
+		ports ...discoveryV1.EndpointPort,
+	) discoveryV1.EndpointSlice {
+		return discoveryV1.EndpointSlice{
+			AddressType: addressType,
+			Endpoints: []discoveryV1.Endpoint{
+				{
+					Addresses:  []string{"10.1.0.1", "10.1.0.2"},
+					Conditions: discoveryV1.EndpointConditions{Ready: helpers.GetBoolPointer(ready)},
+				},
+			},
+			Ports: ports,
+		}
+	}
+
+	port8080 := discoveryV1.EndpointPort{Port: helpers.GetInt32Pointer(8080)}
+	port9090 := discoveryV1.EndpointPort{Port: helpers.GetInt32Pointer(9090)}
+
+	tests := []struct {
+		msg          string
+		slices       []discoveryV1.EndpointSlice
+		expEndpoints []Endpoint
+		expErr       bool
+	}{
+		{
+			slices: []discoveryV1.EndpointSlice{
+				createEndpointSlice(discoveryV1.AddressTypeIPv4, true, port8080),
+			},
+			expEndpoints: []Endpoint{
+				{Address: "10.1.0.1", Port: 8080},
+				{Address: "10.1.0.2", Port: 8080},
+			},
+			msg: "imported endpoints",
+		},
+		{
+			slices: []discoveryV1.EndpointSlice{
+				createEndpointSlice(discoveryV1.AddressTypeIPv4, true, port8080),
+				createEndpointSlice(discoveryV1.AddressTypeIPv4, true, port8080), // duplicate
+				createEndpointSlice(discoveryV1.AddressTypeIPv6, true, port8080),
+				createEndpointSlice(discoveryV1.AddressTypeIPv4, false, port9090),
+				createEndpointSlice(discoveryV1.AddressTypeIPv4, true, port8080, port9090),
+				createEndpointSlice(discoveryV1.AddressTypeIPv4, true, discoveryV1.EndpointPort{}),
+			},
+			expEndpoints: []Endpoint{
+				{Address: "10.1.0.1", Port: 8080},
+				{Address: "10.1.0.2", Port: 8080},
+			},
+			msg: "ignored endpoints",
+		},
+		{
+			slices: []discoveryV1.EndpointSlice{
+				createEndpointSlice(discoveryV1.AddressTypeIPv4, false, port8080),
+			},
+			expErr: true,
+			msg:    "no ready endpoints",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			endpoints, err := resolveImportedEndpoints(
+				"test",
+				"coffee",
+				discoveryV1.EndpointSliceList{Items: test.slices},
+			)
+
+			if test.expErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(endpoints).To(ConsistOf(test.expEndpoints))
+		})
+	}
+}
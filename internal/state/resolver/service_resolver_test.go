@@ -183,7 +183,7 @@ var _ = Describe("ServiceResolver", func() {
 			)
 			Expect(err).ToNot(HaveOccurred())
 
-			serviceResolver = resolver.NewServiceResolverImpl(fakeK8sClient)
+			serviceResolver = resolver.NewServiceResolverImpl(fakeK8sClient, false)
 		})
 		It("resolves a service for a given port", func() {
 			expectedEndpoints := []resolver.Endpoint{
@@ -247,4 +247,42 @@ var _ = Describe("ServiceResolver", func() {
 			Expect(endpoints).To(BeNil())
 		})
 	})
+	Describe("Resolve a service that represents a ServiceImport", func() {
+		var importSvc *v1.Service
+
+		BeforeEach(func() {
+			importSvc = svc.DeepCopy()
+			importSvc.Labels = map[string]string{index.MulticlusterServiceNameLabel: "coffee"}
+
+			importedSlice := createSlice(
+				"imported-slice",
+				[]string{"13.0.0.1"},
+				8080,
+				httpPortName,
+				discoveryV1.AddressTypeIPv4,
+			)
+			importedSlice.Labels = map[string]string{index.MulticlusterServiceNameLabel: "coffee"}
+
+			var err error
+			fakeK8sClient, err = createFakeK8sClient(slice1, importedSlice)
+			Expect(err).ToNot(HaveOccurred())
+		})
+		It("resolves the service to the imported endpoints if the ServiceImports are enabled", func() {
+			serviceResolver = resolver.NewServiceResolverImpl(fakeK8sClient, true)
+
+			endpoints, err := serviceResolver.Resolve(context.TODO(), importSvc, 80)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(endpoints).To(ConsistOf(resolver.Endpoint{Address: "13.0.0.1", Port: 8080}))
+		})
+		It("resolves the service to its own endpoints if the ServiceImports are disabled", func() {
+			serviceResolver = resolver.NewServiceResolverImpl(fakeK8sClient, false)
+
+			endpoints, err := serviceResolver.Resolve(context.TODO(), importSvc, 80)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(endpoints).To(ConsistOf(
+				resolver.Endpoint{Address: "9.0.0.1", Port: 8080},
+				resolver.Endpoint{Address: "9.0.0.2", Port: 8080},
+			))
+		})
+	})
 })
@@ -0,0 +1,60 @@
+package state
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/conditions"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
+)
+
+// RouteSnapshot is a snapshot of the status of an HTTPRoute computed during the latest processing of the changes.
+type RouteSnapshot struct {
+	// ParentStatuses holds the statuses of the parentRefs of the HTTPRoute, as they are reported in the status of
+	// the HTTPRoute.
+	ParentStatuses ParentStatuses
+	// BackendGroups holds the resolved backends of the rules of the HTTPRoute in the order of the rules.
+	BackendGroups []graph.BackendGroup
+	// ObservedGeneration is the generation of the HTTPRoute that the snapshot is computed for.
+	ObservedGeneration int64
+}
+
+// routeSnapshots holds the RouteSnapshots where the key is the namespaced name of an HTTPRoute.
+type routeSnapshots map[types.NamespacedName]RouteSnapshot
+
+// buildRouteSnapshots builds the RouteSnapshots of the HTTPRoutes of the Graph.
+// The snapshots don't share the slices with the Graph and the statuses, so that they stay consistent if those are
+// modified after the snapshots are built.
+func buildRouteSnapshots(g *graph.Graph, statuses HTTPRouteStatuses) routeSnapshots {
+	snapshots := make(routeSnapshots, len(g.Routes))
+
+	for nsname, r := range g.Routes {
+		status := statuses[nsname]
+
+		snapshot := RouteSnapshot{
+			ObservedGeneration: r.Source.Generation,
+		}
+
+		if len(status.ParentStatuses) > 0 {
+			snapshot.ParentStatuses = make(ParentStatuses, 0, len(status.ParentStatuses))
+
+			for _, ps := range status.ParentStatuses {
+				ps.Conditions = append([]conditions.Condition(nil), ps.Conditions...)
+				snapshot.ParentStatuses = append(snapshot.ParentStatuses, ps)
+			}
+		}
+
+		if len(r.BackendGroups) > 0 {
+			snapshot.BackendGroups = make([]graph.BackendGroup, 0, len(r.BackendGroups))
+
+			for _, group := range r.BackendGroups {
+				group.Errors = append([]string(nil), group.Errors...)
+				group.Backends = append([]graph.BackendRef(nil), group.Backends...)
+				snapshot.BackendGroups = append(snapshot.BackendGroups, group)
+			}
+		}
+
+		snapshots[nsname] = snapshot
+	}
+
+	return snapshots
+}
@@ -0,0 +1,89 @@
+package state
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/conditions"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
+)
+
+func TestBuildRouteSnapshots(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	hrNsName := types.NamespacedName{Namespace: "test", Name: "hr"}
+
+	group := graph.BackendGroup{
+		Source: hrNsName,
+		Errors: []string{"error"},
+		Backends: []graph.BackendRef{
+			{Name: "test_foo_80", Port: 80, Weight: 1, Valid: true},
+		},
+	}
+
+	gr := &graph.Graph{
+		Routes: map[types.NamespacedName]*graph.Route{
+			hrNsName: {
+				Source: &v1beta1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:  hrNsName.Namespace,
+						Name:       hrNsName.Name,
+						Generation: 2,
+					},
+				},
+				BackendGroups: []graph.BackendGroup{group},
+			},
+		},
+	}
+
+	parentStatuses := ParentStatuses{
+		{
+			GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway"},
+			SectionName:   "listener-80-1",
+			Conditions:    []conditions.Condition{conditions.NewDefaultRouteConditions()[0]},
+		},
+	}
+
+	statuses := HTTPRouteStatuses{
+		hrNsName: {
+			ObservedGeneration: 2,
+			ParentStatuses:     parentStatuses,
+		},
+	}
+
+	snapshots := buildRouteSnapshots(gr, statuses)
+
+	expected := routeSnapshots{
+		hrNsName: {
+			ParentStatuses: ParentStatuses{
+				{
+					GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway"},
+					SectionName:   "listener-80-1",
+					Conditions:    []conditions.Condition{conditions.NewDefaultRouteConditions()[0]},
+				},
+			},
+			BackendGroups: []graph.BackendGroup{
+				{
+					Source: hrNsName,
+					Errors: []string{"error"},
+					Backends: []graph.BackendRef{
+						{Name: "test_foo_80", Port: 80, Weight: 1, Valid: true},
+					},
+				},
+			},
+			ObservedGeneration: 2,
+		},
+	}
+	g.Expect(snapshots).To(Equal(expected))
+
+	// the snapshots are not affected by the changes of the Graph and the statuses
+	parentStatuses[0].Conditions[0] = conditions.NewTODO("changed")
+	gr.Routes[hrNsName].BackendGroups[0].Backends[0].Weight = 5
+	gr.Routes[hrNsName].BackendGroups[0].Errors[0] = "changed"
+
+	g.Expect(snapshots).To(Equal(expected))
+}
@@ -2,7 +2,10 @@ package secrets
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/rsa"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io/fs"
 	"os"
@@ -20,6 +23,21 @@ import (
 // tlsSecretFileMode defines the default file mode for files with TLS Secrets.
 const tlsSecretFileMode = 0o600
 
+// CACertKey is the key of the data field of a Secret that holds a bundle of PEM-encoded CA certificates.
+const CACertKey = "ca.crt"
+
+// KeyType is the type of the private key of a TLS Secret.
+type KeyType string
+
+const (
+	// KeyTypeRSA is the type of RSA private keys.
+	KeyTypeRSA KeyType = "RSA"
+	// KeyTypeECDSA is the type of ECDSA private keys.
+	KeyTypeECDSA KeyType = "ECDSA"
+	// KeyTypeUnknown is the type of private keys other than RSA and ECDSA.
+	KeyTypeUnknown KeyType = "Unknown"
+)
+
 // SecretStore stores secrets.
 type SecretStore interface {
 	// Upsert upserts the secret into the store.
@@ -38,8 +56,13 @@ type SecretStoreImpl struct {
 type Secret struct {
 	// Secret is the Kubernetes Secret object.
 	Secret *apiv1.Secret
+	// KeyType is the type of the private key of the Secret. It is empty if the Secret is invalid.
+	KeyType KeyType
 	// Valid is whether the Kubernetes Secret is valid.
 	Valid bool
+	// ValidCA is whether the Kubernetes Secret holds a valid bundle of CA certificates in the CACertKey data field.
+	// The Secret can be of any type.
+	ValidCA bool
 }
 
 func NewSecretStore() *SecretStoreImpl {
@@ -54,8 +77,13 @@ func (s SecretStoreImpl) Upsert(secret *apiv1.Secret) {
 		Name:      secret.Name,
 	}
 
-	valid := isSecretValid(secret)
-	s.secrets[nsname] = &Secret{Secret: secret, Valid: valid}
+	keyType, valid := validateSecret(secret)
+	s.secrets[nsname] = &Secret{
+		Secret:  secret,
+		Valid:   valid,
+		KeyType: keyType,
+		ValidCA: validateCASecret(secret),
+	}
 }
 
 func (s SecretStoreImpl) Delete(nsname types.NamespacedName) {
@@ -72,6 +100,14 @@ type SecretDiskMemoryManager interface {
 	// Returns the path to the secret if it exists.
 	// Returns an error if the secret does not exist in the secret store or the secret is invalid.
 	Request(nsname types.NamespacedName) (string, error)
+	// RequestCA marks the bundle of CA certificates of the secret as requested so that it can be written to disk
+	// before reloading NGINX.
+	// Returns the path to the bundle if it exists.
+	// Returns an error if the secret does not exist in the secret store or doesn't hold a valid bundle.
+	RequestCA(nsname types.NamespacedName) (string, error)
+	// GetKeyType returns the type of the private key of the secret.
+	// Returns an error if the secret does not exist in the secret store or the secret is invalid.
+	GetKeyType(nsname types.NamespacedName) (KeyType, error)
 	// WriteAllRequestedSecrets writes all requested secrets to disk.
 	WriteAllRequestedSecrets() error
 }
@@ -93,10 +129,11 @@ type FileManager interface {
 
 // FIXME(kate-osborn): Is it necessary to make this concurrent-safe?
 type SecretDiskMemoryManagerImpl struct {
-	requestedSecrets map[types.NamespacedName]requestedSecret
-	secretStore      SecretStore
-	fileManager      FileManager
-	secretDirectory  string
+	requestedSecrets   map[types.NamespacedName]requestedSecret
+	requestedCASecrets map[types.NamespacedName]requestedSecret
+	secretStore        SecretStore
+	fileManager        FileManager
+	secretDirectory    string
 }
 
 type requestedSecret struct {
@@ -121,10 +158,11 @@ func NewSecretDiskMemoryManager(
 	options ...SecretDiskMemoryManagerOption,
 ) *SecretDiskMemoryManagerImpl {
 	sm := &SecretDiskMemoryManagerImpl{
-		requestedSecrets: make(map[types.NamespacedName]requestedSecret),
-		secretStore:      secretStore,
-		secretDirectory:  secretDirectory,
-		fileManager:      newStdLibFileManager(),
+		requestedSecrets:   make(map[types.NamespacedName]requestedSecret),
+		requestedCASecrets: make(map[types.NamespacedName]requestedSecret),
+		secretStore:        secretStore,
+		secretDirectory:    secretDirectory,
+		fileManager:        newStdLibFileManager(),
 	}
 
 	for _, o := range options {
@@ -135,29 +173,71 @@ func NewSecretDiskMemoryManager(
 }
 
 func (s *SecretDiskMemoryManagerImpl) Request(nsname types.NamespacedName) (string, error) {
+	secret, err := s.getValidSecret(nsname)
+	if err != nil {
+		return "", err
+	}
+
+	ss := requestedSecret{
+		secret: secret.Secret,
+		path:   path.Join(s.secretDirectory, generateFilepathForSecret(nsname)),
+	}
+
+	s.requestedSecrets[nsname] = ss
+
+	return ss.path, nil
+}
+
+func (s *SecretDiskMemoryManagerImpl) RequestCA(nsname types.NamespacedName) (string, error) {
 	secret := s.secretStore.Get(nsname)
 	if secret == nil {
 		return "", fmt.Errorf("secret %s does not exist", nsname)
 	}
 
-	if !secret.Valid {
+	if !secret.ValidCA {
 		return "", fmt.Errorf(
-			"secret %s is not valid; must be of type %s and contain a valid X509 key pair",
+			"secret %s is not valid; must contain a bundle of PEM-encoded CA certificates in the %s field",
 			nsname,
-			apiv1.SecretTypeTLS,
+			CACertKey,
 		)
 	}
 
 	ss := requestedSecret{
 		secret: secret.Secret,
-		path:   path.Join(s.secretDirectory, generateFilepathForSecret(nsname)),
+		path:   path.Join(s.secretDirectory, generateFilepathForCASecret(nsname)),
 	}
 
-	s.requestedSecrets[nsname] = ss
+	s.requestedCASecrets[nsname] = ss
 
 	return ss.path, nil
 }
 
+func (s *SecretDiskMemoryManagerImpl) GetKeyType(nsname types.NamespacedName) (KeyType, error) {
+	secret, err := s.getValidSecret(nsname)
+	if err != nil {
+		return "", err
+	}
+
+	return secret.KeyType, nil
+}
+
+func (s *SecretDiskMemoryManagerImpl) getValidSecret(nsname types.NamespacedName) (*Secret, error) {
+	secret := s.secretStore.Get(nsname)
+	if secret == nil {
+		return nil, fmt.Errorf("secret %s does not exist", nsname)
+	}
+
+	if !secret.Valid {
+		return nil, fmt.Errorf(
+			"secret %s is not valid; must be of type %s and contain a valid X509 key pair",
+			nsname,
+			apiv1.SecretTypeTLS,
+		)
+	}
+
+	return secret, nil
+}
+
 func (s *SecretDiskMemoryManagerImpl) WriteAllRequestedSecrets() error {
 	// Remove all existing secrets from secrets directory
 	dir, err := s.fileManager.ReadDir(s.secretDirectory)
@@ -174,44 +254,77 @@ func (s *SecretDiskMemoryManagerImpl) WriteAllRequestedSecrets() error {
 
 	// Write all secrets to secrets directory
 	for nsname, ss := range s.requestedSecrets {
-
-		file, err := s.fileManager.Create(ss.path)
-		if err != nil {
-			return fmt.Errorf("failed to create file %s for secret %s: %w", ss.path, nsname, err)
-		}
-
-		if err = s.fileManager.Chmod(file, tlsSecretFileMode); err != nil {
-			return fmt.Errorf(
-				"failed to change mode of file %s for secret %s: %w",
-				ss.path,
-				nsname,
-				err,
-			)
+		if err := s.writeSecret(nsname, ss.path, generateCertAndKeyFileContent(ss.secret)); err != nil {
+			return err
 		}
+	}
 
-		contents := generateCertAndKeyFileContent(ss.secret)
-
-		err = s.fileManager.Write(file, contents)
-		if err != nil {
-			return fmt.Errorf("failed to write secret %s to file %s: %w", nsname, ss.path, err)
+	for nsname, ss := range s.requestedCASecrets {
+		if err := s.writeSecret(nsname, ss.path, ss.secret.Data[CACertKey]); err != nil {
+			return err
 		}
 	}
 
 	// reset stored secrets
 	s.requestedSecrets = make(map[types.NamespacedName]requestedSecret)
+	s.requestedCASecrets = make(map[types.NamespacedName]requestedSecret)
 
 	return nil
 }
 
-func isSecretValid(secret *apiv1.Secret) bool {
+func (s *SecretDiskMemoryManagerImpl) writeSecret(nsname types.NamespacedName, filepath string, contents []byte) error {
+	file, err := s.fileManager.Create(filepath)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s for secret %s: %w", filepath, nsname, err)
+	}
+
+	if err = s.fileManager.Chmod(file, tlsSecretFileMode); err != nil {
+		return fmt.Errorf(
+			"failed to change mode of file %s for secret %s: %w",
+			filepath,
+			nsname,
+			err,
+		)
+	}
+
+	err = s.fileManager.Write(file, contents)
+	if err != nil {
+		return fmt.Errorf("failed to write secret %s to file %s: %w", nsname, filepath, err)
+	}
+
+	return nil
+}
+
+// validateSecret returns the type of the private key of the secret and whether the secret is valid.
+func validateSecret(secret *apiv1.Secret) (KeyType, bool) {
 	if secret.Type != apiv1.SecretTypeTLS {
-		return false
+		return "", false
 	}
 
 	// A TLS Secret is guaranteed to have these data fields.
-	_, err := tls.X509KeyPair(secret.Data[apiv1.TLSCertKey], secret.Data[apiv1.TLSPrivateKeyKey])
+	cert, err := tls.X509KeyPair(secret.Data[apiv1.TLSCertKey], secret.Data[apiv1.TLSPrivateKeyKey])
+	if err != nil {
+		return "", false
+	}
 
-	return err == nil
+	switch cert.PrivateKey.(type) {
+	case *rsa.PrivateKey:
+		return KeyTypeRSA, true
+	case *ecdsa.PrivateKey:
+		return KeyTypeECDSA, true
+	default:
+		return KeyTypeUnknown, true
+	}
+}
+
+// validateCASecret returns whether the secret holds a valid bundle of PEM-encoded CA certificates.
+func validateCASecret(secret *apiv1.Secret) bool {
+	bundle, exists := secret.Data[CACertKey]
+	if !exists {
+		return false
+	}
+
+	return x509.NewCertPool().AppendCertsFromPEM(bundle)
 }
 
 func generateCertAndKeyFileContent(secret *apiv1.Secret) []byte {
@@ -227,3 +340,7 @@ func generateCertAndKeyFileContent(secret *apiv1.Secret) []byte {
 func generateFilepathForSecret(nsname types.NamespacedName) string {
 	return nsname.Namespace + "_" + nsname.Name
 }
+
+func generateFilepathForCASecret(nsname types.NamespacedName) string {
+	return generateFilepathForSecret(nsname) + "_ca"
+}
@@ -121,6 +121,16 @@ var (
 		},
 		Type: apiv1.SecretTypeDockercfg,
 	}
+	caSecret = &apiv1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "ca",
+		},
+		Data: map[string][]byte{
+			secrets.CACertKey: cert,
+		},
+		Type: apiv1.SecretTypeOpaque,
+	}
 	invalidSecretKey = &apiv1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "test",
@@ -187,6 +197,14 @@ var _ = Describe("SecretDiskMemoryManager", func() {
 			testRequest(secret2, expectedPath, false)
 		})
 
+		It("should return the key type of a valid secret", func() {
+			fakeStore.GetReturns(&secrets.Secret{Secret: secret1, Valid: true, KeyType: secrets.KeyTypeRSA})
+
+			keyType, err := memMgr.GetKeyType(types.NamespacedName{Namespace: secret1.Namespace, Name: secret1.Name})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(keyType).To(Equal(secrets.KeyTypeRSA))
+		})
+
 		It("request should return an error and empty path when secret is invalid", func() {
 			fakeStore.GetReturns(&secrets.Secret{Secret: invalidSecretType, Valid: false})
 
@@ -242,6 +260,37 @@ var _ = Describe("SecretDiskMemoryManager", func() {
 			})
 		})
 	})
+	Describe("Manages CA bundles on disk", func() {
+		It("should write the requested CA bundle", func() {
+			fakeStore.GetReturns(&secrets.Secret{Secret: caSecret, ValidCA: true})
+
+			actualPath, err := memMgr.RequestCA(types.NamespacedName{Namespace: "test", Name: "ca"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actualPath).To(Equal(path.Join(tmpSecretsDir, "test_ca_ca")))
+
+			Expect(memMgr.WriteAllRequestedSecrets()).To(Succeed())
+
+			contents, err := os.ReadFile(actualPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(Equal(cert))
+		})
+
+		It("should return an error and empty path when the secret doesn't hold a CA bundle", func() {
+			fakeStore.GetReturns(&secrets.Secret{Secret: secret1, Valid: true})
+
+			actualPath, err := memMgr.RequestCA(types.NamespacedName{Namespace: "test", Name: "secret1"})
+			Expect(err).To(HaveOccurred())
+			Expect(actualPath).To(BeEmpty())
+		})
+
+		It("should return an error and empty path when the secret does not exist", func() {
+			fakeStore.GetReturns(nil)
+
+			actualPath, err := memMgr.RequestCA(types.NamespacedName{Namespace: "test", Name: "ca"})
+			Expect(err).To(HaveOccurred())
+			Expect(actualPath).To(BeEmpty())
+		})
+	})
 	Describe("Write all requested secrets", func() {
 		var (
 			fakeFileManager *secretsfakes.FakeFileManager
@@ -318,8 +367,10 @@ var _ = Describe("SecretStore", func() {
 			actualSecret := store.Get(nsname)
 			if valid {
 				Expect(actualSecret.Valid).To(BeTrue())
+				Expect(actualSecret.KeyType).To(Equal(secrets.KeyTypeRSA))
 			} else {
 				Expect(actualSecret.Valid).To(BeFalse())
+				Expect(actualSecret.KeyType).To(BeEmpty())
 			}
 			Expect(actualSecret.Secret).To(Equal(s))
 		}
@@ -376,6 +427,13 @@ var _ = Describe("SecretStore", func() {
 
 			testDelete(nsname)
 		})
+		It("adds a secret with a CA bundle", func() {
+			store.Upsert(caSecret)
+
+			s := store.Get(types.NamespacedName{Namespace: "test", Name: "ca"})
+			Expect(s.ValidCA).To(BeTrue())
+			Expect(s.Valid).To(BeFalse())
+		})
 		It("does not panic when secret is deleted that does not exist", func() {
 			nsname := types.NamespacedName{Namespace: "test", Name: "dne"}
 
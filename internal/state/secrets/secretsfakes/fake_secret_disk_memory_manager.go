@@ -9,6 +9,19 @@ import (
 )
 
 type FakeSecretDiskMemoryManager struct {
+	GetKeyTypeStub        func(types.NamespacedName) (secrets.KeyType, error)
+	getKeyTypeMutex       sync.RWMutex
+	getKeyTypeArgsForCall []struct {
+		arg1 types.NamespacedName
+	}
+	getKeyTypeReturns struct {
+		result1 secrets.KeyType
+		result2 error
+	}
+	getKeyTypeReturnsOnCall map[int]struct {
+		result1 secrets.KeyType
+		result2 error
+	}
 	RequestStub        func(types.NamespacedName) (string, error)
 	requestMutex       sync.RWMutex
 	requestArgsForCall []struct {
@@ -22,6 +35,19 @@ type FakeSecretDiskMemoryManager struct {
 		result1 string
 		result2 error
 	}
+	RequestCAStub        func(types.NamespacedName) (string, error)
+	requestCAMutex       sync.RWMutex
+	requestCAArgsForCall []struct {
+		arg1 types.NamespacedName
+	}
+	requestCAReturns struct {
+		result1 string
+		result2 error
+	}
+	requestCAReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	WriteAllRequestedSecretsStub        func() error
 	writeAllRequestedSecretsMutex       sync.RWMutex
 	writeAllRequestedSecretsArgsForCall []struct {
@@ -36,6 +62,70 @@ type FakeSecretDiskMemoryManager struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeSecretDiskMemoryManager) GetKeyType(arg1 types.NamespacedName) (secrets.KeyType, error) {
+	fake.getKeyTypeMutex.Lock()
+	ret, specificReturn := fake.getKeyTypeReturnsOnCall[len(fake.getKeyTypeArgsForCall)]
+	fake.getKeyTypeArgsForCall = append(fake.getKeyTypeArgsForCall, struct {
+		arg1 types.NamespacedName
+	}{arg1})
+	stub := fake.GetKeyTypeStub
+	fakeReturns := fake.getKeyTypeReturns
+	fake.recordInvocation("GetKeyType", []interface{}{arg1})
+	fake.getKeyTypeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSecretDiskMemoryManager) GetKeyTypeCallCount() int {
+	fake.getKeyTypeMutex.RLock()
+	defer fake.getKeyTypeMutex.RUnlock()
+	return len(fake.getKeyTypeArgsForCall)
+}
+
+func (fake *FakeSecretDiskMemoryManager) GetKeyTypeCalls(stub func(types.NamespacedName) (secrets.KeyType, error)) {
+	fake.getKeyTypeMutex.Lock()
+	defer fake.getKeyTypeMutex.Unlock()
+	fake.GetKeyTypeStub = stub
+}
+
+func (fake *FakeSecretDiskMemoryManager) GetKeyTypeArgsForCall(i int) types.NamespacedName {
+	fake.getKeyTypeMutex.RLock()
+	defer fake.getKeyTypeMutex.RUnlock()
+	argsForCall := fake.getKeyTypeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSecretDiskMemoryManager) GetKeyTypeReturns(result1 secrets.KeyType, result2 error) {
+	fake.getKeyTypeMutex.Lock()
+	defer fake.getKeyTypeMutex.Unlock()
+	fake.GetKeyTypeStub = nil
+	fake.getKeyTypeReturns = struct {
+		result1 secrets.KeyType
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSecretDiskMemoryManager) GetKeyTypeReturnsOnCall(i int, result1 secrets.KeyType, result2 error) {
+	fake.getKeyTypeMutex.Lock()
+	defer fake.getKeyTypeMutex.Unlock()
+	fake.GetKeyTypeStub = nil
+	if fake.getKeyTypeReturnsOnCall == nil {
+		fake.getKeyTypeReturnsOnCall = make(map[int]struct {
+			result1 secrets.KeyType
+			result2 error
+		})
+	}
+	fake.getKeyTypeReturnsOnCall[i] = struct {
+		result1 secrets.KeyType
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeSecretDiskMemoryManager) Request(arg1 types.NamespacedName) (string, error) {
 	fake.requestMutex.Lock()
 	ret, specificReturn := fake.requestReturnsOnCall[len(fake.requestArgsForCall)]
@@ -100,6 +190,70 @@ func (fake *FakeSecretDiskMemoryManager) RequestReturnsOnCall(i int, result1 str
 	}{result1, result2}
 }
 
+func (fake *FakeSecretDiskMemoryManager) RequestCA(arg1 types.NamespacedName) (string, error) {
+	fake.requestCAMutex.Lock()
+	ret, specificReturn := fake.requestCAReturnsOnCall[len(fake.requestCAArgsForCall)]
+	fake.requestCAArgsForCall = append(fake.requestCAArgsForCall, struct {
+		arg1 types.NamespacedName
+	}{arg1})
+	stub := fake.RequestCAStub
+	fakeReturns := fake.requestCAReturns
+	fake.recordInvocation("RequestCA", []interface{}{arg1})
+	fake.requestCAMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSecretDiskMemoryManager) RequestCACallCount() int {
+	fake.requestCAMutex.RLock()
+	defer fake.requestCAMutex.RUnlock()
+	return len(fake.requestCAArgsForCall)
+}
+
+func (fake *FakeSecretDiskMemoryManager) RequestCACalls(stub func(types.NamespacedName) (string, error)) {
+	fake.requestCAMutex.Lock()
+	defer fake.requestCAMutex.Unlock()
+	fake.RequestCAStub = stub
+}
+
+func (fake *FakeSecretDiskMemoryManager) RequestCAArgsForCall(i int) types.NamespacedName {
+	fake.requestCAMutex.RLock()
+	defer fake.requestCAMutex.RUnlock()
+	argsForCall := fake.requestCAArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSecretDiskMemoryManager) RequestCAReturns(result1 string, result2 error) {
+	fake.requestCAMutex.Lock()
+	defer fake.requestCAMutex.Unlock()
+	fake.RequestCAStub = nil
+	fake.requestCAReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSecretDiskMemoryManager) RequestCAReturnsOnCall(i int, result1 string, result2 error) {
+	fake.requestCAMutex.Lock()
+	defer fake.requestCAMutex.Unlock()
+	fake.RequestCAStub = nil
+	if fake.requestCAReturnsOnCall == nil {
+		fake.requestCAReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.requestCAReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeSecretDiskMemoryManager) WriteAllRequestedSecrets() error {
 	fake.writeAllRequestedSecretsMutex.Lock()
 	ret, specificReturn := fake.writeAllRequestedSecretsReturnsOnCall[len(fake.writeAllRequestedSecretsArgsForCall)]
@@ -156,8 +310,12 @@ func (fake *FakeSecretDiskMemoryManager) WriteAllRequestedSecretsReturnsOnCall(i
 func (fake *FakeSecretDiskMemoryManager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.getKeyTypeMutex.RLock()
+	defer fake.getKeyTypeMutex.RUnlock()
 	fake.requestMutex.RLock()
 	defer fake.requestMutex.RUnlock()
+	fake.requestCAMutex.RLock()
+	defer fake.requestCAMutex.RUnlock()
 	fake.writeAllRequestedSecretsMutex.RLock()
 	defer fake.writeAllRequestedSecretsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -28,6 +28,8 @@ type GatewayStatus struct {
 	ListenerStatuses ListenerStatuses
 	// NsName is the namespaced name of the winning Gateway resource.
 	NsName types.NamespacedName
+	// Conditions is the list of conditions for the Gateway.
+	Conditions []conditions.Condition
 	// ObservedGeneration is the generation of the resource that was processed.
 	ObservedGeneration int64
 }
@@ -48,8 +50,8 @@ type ListenerStatus struct {
 	AttachedRoutes int32
 }
 
-// ParentStatuses holds the statuses of parents where the key is the section name in a parentRef.
-type ParentStatuses map[string]ParentStatus
+// ParentStatuses holds the statuses of the parentRefs of an HTTPRoute in the order of the parentRefs.
+type ParentStatuses []ParentStatus
 
 // HTTPRouteStatus holds the status-related information about an HTTPRoute resource.
 type HTTPRouteStatus struct {
@@ -61,6 +63,10 @@ type HTTPRouteStatus struct {
 
 // ParentStatus holds status-related information related to how the HTTPRoute binds to a specific parentRef.
 type ParentStatus struct {
+	// GatewayNsName is the namespaced name of the Gateway that the parentRef references.
+	GatewayNsName types.NamespacedName
+	// SectionName is the sectionName of the parentRef.
+	SectionName string
 	// Conditions is the list of conditions that are relevant to the parentRef.
 	Conditions []conditions.Condition
 }
@@ -131,27 +137,26 @@ func buildStatuses(graph *graph.Graph) Statuses {
 	}
 
 	for nsname, r := range graph.Routes {
-		parentStatuses := make(map[string]ParentStatus)
+		parentStatuses := make(ParentStatuses, 0, len(r.ParentRefs))
 
-		for ref := range r.ValidSectionNameRefs {
-			parentStatuses[ref] = ParentStatus{
-				Conditions: conditions.DeduplicateConditions(
-					buildBaseRouteConditions(gcValidAndExist),
-				),
-			}
-		}
-		for ref, cond := range r.InvalidSectionNameRefs {
+		for _, ref := range r.ParentRefs {
 			baseConds := buildBaseRouteConditions(gcValidAndExist)
 
 			// We add baseConds first, so that any additional conditions will override them, which is
 			// ensured by DeduplicateConditions.
-			conds := make([]conditions.Condition, 0, len(baseConds)+1)
+			conds := make([]conditions.Condition, 0, len(baseConds)+len(r.Conditions)+1)
 			conds = append(conds, baseConds...)
-			conds = append(conds, cond)
+			conds = append(conds, r.Conditions...)
 
-			parentStatuses[ref] = ParentStatus{
-				Conditions: conditions.DeduplicateConditions(conds),
+			if cond, invalid := getParentRefCondition(graph, r, ref); invalid {
+				conds = append(conds, cond)
 			}
+
+			parentStatuses = append(parentStatuses, ParentStatus{
+				GatewayNsName: ref.Gateway,
+				SectionName:   ref.SectionName,
+				Conditions:    conditions.DeduplicateConditions(conds),
+			})
 		}
 
 		statuses.HTTPRouteStatuses[nsname] = HTTPRouteStatus{
@@ -163,6 +168,27 @@ func buildStatuses(graph *graph.Graph) Statuses {
 	return statuses
 }
 
+// getParentRefCondition returns the condition that explains why the route is not accepted for the parentRef.
+// If the route is accepted, the invalid return value is false.
+func getParentRefCondition(
+	g *graph.Graph,
+	r *graph.Route,
+	ref graph.ParentRef,
+) (cond conditions.Condition, invalid bool) {
+	if g.Gateway != nil && ref.Gateway == client.ObjectKeyFromObject(g.Gateway.Source) {
+		cond, invalid = r.InvalidSectionNameRefs[ref.SectionName]
+		return cond, invalid
+	}
+
+	if _, ignored := g.IgnoredGateways[ref.Gateway]; ignored {
+		// FIXME(pleshakov): Add a proper condition.
+		// https://github.com/nginxinc/nginx-kubernetes-gateway/issues/306
+		return conditions.NewTODO("Gateway is ignored"), true
+	}
+
+	return conditions.NewRouteNoMatchingParent("Gateway is not found for this parent ref"), true
+}
+
 func buildBaseRouteConditions(gcValidAndExist bool) []conditions.Condition {
 	conds := conditions.NewDefaultRouteConditions()
 
@@ -19,6 +19,8 @@ func TestBuildStatuses(t *testing.T) {
 		Status: metav1.ConditionTrue,
 	}
 
+	extensionRefCondition := conditions.NewRouteExtensionRefNotFound("CORSPolicy test/cors not found")
+
 	listeners := map[string]*graph.Listener{
 		"listener-80-1": {
 			Valid: true,
@@ -41,19 +43,24 @@ func TestBuildStatuses(t *testing.T) {
 			InvalidSectionNameRefs: map[string]conditions.Condition{
 				"listener-80-2": invalidCondition,
 			},
+			ParentRefs: []graph.ParentRef{
+				{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway"}, SectionName: "listener-80-1"},
+				{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway"}, SectionName: "listener-80-2"},
+			},
 		},
 	}
 
-	routesAllRefsInvalid := map[types.NamespacedName]*graph.Route{
+	routesGatewayNotFound := map[types.NamespacedName]*graph.Route{
 		{Namespace: "test", Name: "hr-1"}: {
 			Source: &v1beta1.HTTPRoute{
 				ObjectMeta: metav1.ObjectMeta{
 					Generation: 4,
 				},
 			},
-			InvalidSectionNameRefs: map[string]conditions.Condition{
-				"listener-80-2": invalidCondition,
-				"listener-80-1": invalidCondition,
+			InvalidSectionNameRefs: map[string]conditions.Condition{},
+			ParentRefs: []graph.ParentRef{
+				{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway"}, SectionName: "listener-80-1"},
+				{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway"}, SectionName: "listener-80-2"},
 			},
 		},
 	}
@@ -117,11 +124,15 @@ func TestBuildStatuses(t *testing.T) {
 				HTTPRouteStatuses: map[types.NamespacedName]HTTPRouteStatus{
 					{Namespace: "test", Name: "hr-1"}: {
 						ObservedGeneration: 3,
-						ParentStatuses: map[string]ParentStatus{
-							"listener-80-1": {
-								Conditions: conditions.NewDefaultRouteConditions(),
+						ParentStatuses: []ParentStatus{
+							{
+								GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway"},
+								SectionName:   "listener-80-1",
+								Conditions:    conditions.NewDefaultRouteConditions(),
 							},
-							"listener-80-2": {
+							{
+								GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway"},
+								SectionName:   "listener-80-2",
 								Conditions: append(
 									conditions.NewDefaultRouteConditions(),
 									invalidCondition,
@@ -166,14 +177,18 @@ func TestBuildStatuses(t *testing.T) {
 				HTTPRouteStatuses: map[types.NamespacedName]HTTPRouteStatus{
 					{Namespace: "test", Name: "hr-1"}: {
 						ObservedGeneration: 3,
-						ParentStatuses: map[string]ParentStatus{
-							"listener-80-1": {
+						ParentStatuses: []ParentStatus{
+							{
+								GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway"},
+								SectionName:   "listener-80-1",
 								Conditions: append(
 									conditions.NewDefaultRouteConditions(),
 									conditions.NewTODO("GatewayClass is invalid or doesn't exist"),
 								),
 							},
-							"listener-80-2": {
+							{
+								GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway"},
+								SectionName:   "listener-80-2",
 								Conditions: append(
 									conditions.NewDefaultRouteConditions(),
 									conditions.NewTODO("GatewayClass is invalid or doesn't exist"),
@@ -229,14 +244,18 @@ func TestBuildStatuses(t *testing.T) {
 				HTTPRouteStatuses: map[types.NamespacedName]HTTPRouteStatus{
 					{Namespace: "test", Name: "hr-1"}: {
 						ObservedGeneration: 3,
-						ParentStatuses: map[string]ParentStatus{
-							"listener-80-1": {
+						ParentStatuses: []ParentStatus{
+							{
+								GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway"},
+								SectionName:   "listener-80-1",
 								Conditions: append(
 									conditions.NewDefaultRouteConditions(),
 									conditions.NewTODO("GatewayClass is invalid or doesn't exist"),
 								),
 							},
-							"listener-80-2": {
+							{
+								GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway"},
+								SectionName:   "listener-80-2",
 								Conditions: append(
 									conditions.NewDefaultRouteConditions(),
 									conditions.NewTODO("GatewayClass is invalid or doesn't exist"),
@@ -259,7 +278,7 @@ func TestBuildStatuses(t *testing.T) {
 				},
 				Gateway:         nil,
 				IgnoredGateways: nil,
-				Routes:          routesAllRefsInvalid,
+				Routes:          routesGatewayNotFound,
 			},
 			expected: Statuses{
 				GatewayClassStatus: &GatewayClassStatus{
@@ -271,16 +290,94 @@ func TestBuildStatuses(t *testing.T) {
 				HTTPRouteStatuses: map[types.NamespacedName]HTTPRouteStatus{
 					{Namespace: "test", Name: "hr-1"}: {
 						ObservedGeneration: 4,
-						ParentStatuses: map[string]ParentStatus{
-							"listener-80-1": {
+						ParentStatuses: []ParentStatus{
+							{
+								GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway"},
+								SectionName:   "listener-80-1",
+								Conditions: []conditions.Condition{
+									conditions.NewRouteNoMatchingParent("Gateway is not found for this parent ref"),
+								},
+							},
+							{
+								GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway"},
+								SectionName:   "listener-80-2",
+								Conditions: []conditions.Condition{
+									conditions.NewRouteNoMatchingParent("Gateway is not found for this parent ref"),
+								},
+							},
+						},
+					},
+				},
+			},
+			name: "gateway and ignored gateways don't exist",
+		},
+		{
+			graph: &graph.Graph{
+				GatewayClass: &graph.GatewayClass{
+					Source: &v1beta1.GatewayClass{
+						ObjectMeta: metav1.ObjectMeta{Generation: 1},
+					},
+					Valid: true,
+				},
+				Gateway: &graph.Gateway{
+					Source:    gw,
+					Listeners: listeners,
+				},
+				Routes: map[types.NamespacedName]*graph.Route{
+					{Namespace: "test", Name: "hr-1"}: {
+						Source: &v1beta1.HTTPRoute{
+							ObjectMeta: metav1.ObjectMeta{
+								Generation: 5,
+							},
+						},
+						ValidSectionNameRefs: map[string]struct{}{
+							"listener-80-1": {},
+						},
+						InvalidSectionNameRefs: map[string]conditions.Condition{
+							"listener-80-2": invalidCondition,
+						},
+						ParentRefs: []graph.ParentRef{
+							{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway"}, SectionName: "listener-80-1"},
+							{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway"}, SectionName: "listener-80-2"},
+						},
+						Conditions: []conditions.Condition{extensionRefCondition},
+					},
+				},
+			},
+			expected: Statuses{
+				GatewayClassStatus: &GatewayClassStatus{
+					Valid:              true,
+					ObservedGeneration: 1,
+				},
+				GatewayStatus: &GatewayStatus{
+					NsName: types.NamespacedName{Namespace: "test", Name: "gateway"},
+					ListenerStatuses: map[string]ListenerStatus{
+						"listener-80-1": {
+							AttachedRoutes: 1,
+							Conditions:     conditions.NewDefaultListenerConditions(),
+						},
+					},
+					ObservedGeneration: 2,
+				},
+				IgnoredGatewayStatuses: map[types.NamespacedName]IgnoredGatewayStatus{},
+				HTTPRouteStatuses: map[types.NamespacedName]HTTPRouteStatus{
+					{Namespace: "test", Name: "hr-1"}: {
+						ObservedGeneration: 5,
+						ParentStatuses: []ParentStatus{
+							{
+								GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway"},
+								SectionName:   "listener-80-1",
 								Conditions: append(
 									conditions.NewDefaultRouteConditions(),
-									invalidCondition,
+									extensionRefCondition,
 								),
 							},
-							"listener-80-2": {
+							{
+								GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway"},
+								SectionName:   "listener-80-2",
 								Conditions: append(
 									conditions.NewDefaultRouteConditions(),
+									extensionRefCondition,
 									invalidCondition,
 								),
 							},
@@ -288,7 +385,89 @@ func TestBuildStatuses(t *testing.T) {
 					},
 				},
 			},
-			name: "gateway and ignored gateways don't exist",
+			name: "route with unresolved extension ref",
+		},
+		{
+			graph: &graph.Graph{
+				GatewayClass: &graph.GatewayClass{
+					Source: &v1beta1.GatewayClass{
+						ObjectMeta: metav1.ObjectMeta{Generation: 1},
+					},
+					Valid: true,
+				},
+				Gateway: &graph.Gateway{
+					Source:    gw,
+					Listeners: listeners,
+				},
+				IgnoredGateways: map[types.NamespacedName]*v1beta1.Gateway{
+					{Namespace: "test", Name: "ignored-gateway"}: ignoredGw,
+				},
+				Routes: map[types.NamespacedName]*graph.Route{
+					{Namespace: "test", Name: "hr-1"}: {
+						Source: &v1beta1.HTTPRoute{
+							ObjectMeta: metav1.ObjectMeta{
+								Generation: 6,
+							},
+						},
+						ValidSectionNameRefs: map[string]struct{}{
+							"listener-80-1": {},
+						},
+						InvalidSectionNameRefs: map[string]conditions.Condition{},
+						ParentRefs: []graph.ParentRef{
+							{Gateway: types.NamespacedName{Namespace: "test", Name: "missing-gateway"}, SectionName: "listener-80-1"},
+							{Gateway: types.NamespacedName{Namespace: "test", Name: "ignored-gateway"}, SectionName: "listener-80-1"},
+							{Gateway: types.NamespacedName{Namespace: "test", Name: "gateway"}, SectionName: "listener-80-1"},
+						},
+					},
+				},
+			},
+			expected: Statuses{
+				GatewayClassStatus: &GatewayClassStatus{
+					Valid:              true,
+					ObservedGeneration: 1,
+				},
+				GatewayStatus: &GatewayStatus{
+					NsName: types.NamespacedName{Namespace: "test", Name: "gateway"},
+					ListenerStatuses: map[string]ListenerStatus{
+						"listener-80-1": {
+							AttachedRoutes: 1,
+							Conditions:     conditions.NewDefaultListenerConditions(),
+						},
+					},
+					ObservedGeneration: 2,
+				},
+				IgnoredGatewayStatuses: map[types.NamespacedName]IgnoredGatewayStatus{
+					{Namespace: "test", Name: "ignored-gateway"}: {ObservedGeneration: 1},
+				},
+				HTTPRouteStatuses: map[types.NamespacedName]HTTPRouteStatus{
+					{Namespace: "test", Name: "hr-1"}: {
+						ObservedGeneration: 6,
+						ParentStatuses: []ParentStatus{
+							{
+								GatewayNsName: types.NamespacedName{Namespace: "test", Name: "missing-gateway"},
+								SectionName:   "listener-80-1",
+								Conditions: []conditions.Condition{
+									conditions.NewRouteNoMatchingParent("Gateway is not found for this parent ref"),
+								},
+							},
+							{
+								GatewayNsName: types.NamespacedName{Namespace: "test", Name: "ignored-gateway"},
+								SectionName:   "listener-80-1",
+								Conditions: append(
+									conditions.NewDefaultRouteConditions(),
+									conditions.NewTODO("Gateway is ignored"),
+								),
+							},
+							{
+								GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway"},
+								SectionName:   "listener-80-1",
+								Conditions:    conditions.NewDefaultRouteConditions(),
+							},
+						},
+					},
+				},
+			},
+			name: "route with parentRefs to missing, ignored and winning gateways",
 		},
 	}
 
@@ -4,9 +4,15 @@ import (
 	"fmt"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/apis/v1alpha1"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/dataplane"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/state/graph"
 )
 
 // store contains the resources that represent the state of the Gateway.
@@ -15,20 +21,32 @@ type store struct {
 	gateways   map[types.NamespacedName]*v1beta1.Gateway
 	httpRoutes map[types.NamespacedName]*v1beta1.HTTPRoute
 	services   map[types.NamespacedName]*v1.Service
+	// corsPolicies holds the CORSPolicy resources, which HTTPRoutes reference through ExtensionRef filters.
+	corsPolicies map[types.NamespacedName]*v1alpha1.CORSPolicy
+	// directResponses holds the DirectResponse resources, which HTTPRoutes reference through ExtensionRef filters.
+	directResponses map[types.NamespacedName]*v1alpha1.DirectResponse
+	// namespaces holds the Namespace resources, whose labels the namespace selectors of the listeners match.
+	namespaces map[types.NamespacedName]*v1.Namespace
 
 	// changed tells if the store is changed.
 	// The store is considered changed if:
 	// (1) Any of its resources was deleted.
 	// (2) A new resource was upserted.
 	// (3) An existing resource with the updated Generation was upserted.
+	// (4) An existing Gateway with updated disabled Listeners or default backends was upserted.
+	// (5) An existing HTTPRoute with updated route option annotations was upserted.
+	// (6) A Namespace was upserted or deleted, and a namespace selector of a listener selects it differently.
 	changed bool
 }
 
 func newStore() *store {
 	return &store{
-		gateways:   make(map[types.NamespacedName]*v1beta1.Gateway),
-		httpRoutes: make(map[types.NamespacedName]*v1beta1.HTTPRoute),
-		services:   make(map[types.NamespacedName]*v1.Service),
+		gateways:        make(map[types.NamespacedName]*v1beta1.Gateway),
+		httpRoutes:      make(map[types.NamespacedName]*v1beta1.HTTPRoute),
+		services:        make(map[types.NamespacedName]*v1.Service),
+		corsPolicies:    make(map[types.NamespacedName]*v1alpha1.CORSPolicy),
+		directResponses: make(map[types.NamespacedName]*v1alpha1.DirectResponse),
+		namespaces:      make(map[types.NamespacedName]*v1.Namespace),
 	}
 }
 
@@ -53,8 +71,10 @@ func (s *store) captureGatewayChange(gw *v1beta1.Gateway) {
 	resourceChanged := true
 
 	// if the resource spec hasn't changed (its generation is the same), ignore the upsert
+	// Listeners are disabled, and default backends, rate limits, the default certificate and the maintenance mode
+	// are configured through annotations, which don't update the generation.
 	prev, exist := s.gateways[client.ObjectKeyFromObject(gw)]
-	if exist && gw.Generation == prev.Generation {
+	if exist && gw.Generation == prev.Generation && gatewayAnnotationsEqual(prev, gw) {
 		resourceChanged = false
 	}
 
@@ -63,11 +83,34 @@ func (s *store) captureGatewayChange(gw *v1beta1.Gateway) {
 	s.changed = s.changed || resourceChanged
 }
 
+// gatewayAnnotations are the Gateway annotations that configure the Gateway.
+var gatewayAnnotations = []string{
+	graph.DisabledListenersAnnotation,
+	graph.DefaultBackendAnnotation,
+	graph.RateLimitAnnotation,
+	graph.DefaultCertificateAnnotation,
+	dataplane.MaintenanceAnnotation,
+	dataplane.MaintenancePageAnnotation,
+	dataplane.ClientHeaderBufferSizeAnnotation,
+	dataplane.LargeClientHeaderBuffersAnnotation,
+}
+
+func gatewayAnnotationsEqual(prev, cur *v1beta1.Gateway) bool {
+	for _, a := range gatewayAnnotations {
+		if prev.Annotations[a] != cur.Annotations[a] {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (s *store) captureHTTPRouteChange(hr *v1beta1.HTTPRoute) {
 	resourceChanged := true
 	// if the resource spec hasn't changed (its generation is the same), ignore the upsert
+	// The route options are configured through annotations, which don't update the generation.
 	prev, exist := s.httpRoutes[client.ObjectKeyFromObject(hr)]
-	if exist && hr.Generation == prev.Generation {
+	if exist && hr.Generation == prev.Generation && routeOptionAnnotationsEqual(prev, hr) {
 		resourceChanged = false
 	}
 	s.httpRoutes[client.ObjectKeyFromObject(hr)] = hr
@@ -75,6 +118,31 @@ func (s *store) captureHTTPRouteChange(hr *v1beta1.HTTPRoute) {
 	s.changed = s.changed || resourceChanged
 }
 
+// routeOptionAnnotations are the HTTPRoute annotations that configure the route options.
+var routeOptionAnnotations = []string{
+	dataplane.StreamingAnnotation,
+	dataplane.PreserveHostAnnotation,
+	dataplane.RequestIDAnnotation,
+	dataplane.AccessLogAnnotation,
+	dataplane.AccessLogFormatAnnotation,
+	dataplane.SchemeAnnotation,
+	graph.GeoMatchAnnotation,
+	graph.WeightAnnotation,
+	dataplane.ProxyCacheValidAnnotation,
+	dataplane.ProxyCacheKeyAnnotation,
+	dataplane.ProxyCacheMaxSizeAnnotation,
+}
+
+func routeOptionAnnotationsEqual(prev, cur *v1beta1.HTTPRoute) bool {
+	for _, a := range routeOptionAnnotations {
+		if prev.Annotations[a] != cur.Annotations[a] {
+			return false
+		}
+	}
+
+	return true
+}
+
 // Service changes are treated differently than Gateway API resource changes in the following ways:
 // (1) We don't check generation here because services do not use generation, and Service Controller filters upsert
 // events based on the Service ports. This means we will only receive upsert events for Services with port changes.
@@ -83,3 +151,91 @@ func (s *store) captureHTTPRouteChange(hr *v1beta1.HTTPRoute) {
 func (s *store) captureServiceChange(svc *v1.Service) {
 	s.services[client.ObjectKeyFromObject(svc)] = svc
 }
+
+func (s *store) captureCORSPolicyChange(policy *v1alpha1.CORSPolicy) {
+	resourceChanged := true
+	// if the resource spec hasn't changed (its generation is the same), ignore the upsert
+	prev, exist := s.corsPolicies[client.ObjectKeyFromObject(policy)]
+	if exist && policy.Generation == prev.Generation {
+		resourceChanged = false
+	}
+	s.corsPolicies[client.ObjectKeyFromObject(policy)] = policy
+
+	s.changed = s.changed || resourceChanged
+}
+
+func (s *store) captureDirectResponseChange(response *v1alpha1.DirectResponse) {
+	resourceChanged := true
+	// if the resource spec hasn't changed (its generation is the same), ignore the upsert
+	prev, exist := s.directResponses[client.ObjectKeyFromObject(response)]
+	if exist && response.Generation == prev.Generation {
+		resourceChanged = false
+	}
+	s.directResponses[client.ObjectKeyFromObject(response)] = response
+
+	s.changed = s.changed || resourceChanged
+}
+
+// Namespaces don't have a generation, and only their labels matter: the namespace selectors of the listeners
+// match them. So a Namespace change only changes the store if a selector matches the previous and the current
+// state of the Namespace differently, which changes the routes that the listener allows.
+func (s *store) captureNamespaceChange(ns *v1.Namespace) {
+	key := client.ObjectKeyFromObject(ns)
+
+	prev, exist := s.namespaces[key]
+	s.namespaces[key] = ns
+
+	if exist && labels.Equals(prev.Labels, ns.Labels) {
+		return
+	}
+
+	s.changed = s.changed || s.namespaceSelectionChanged(prev, ns)
+}
+
+func (s *store) captureNamespaceDelete(nsname types.NamespacedName) {
+	prev, exist := s.namespaces[nsname]
+	if !exist {
+		return
+	}
+
+	delete(s.namespaces, nsname)
+
+	s.changed = s.changed || s.namespaceSelectionChanged(prev, nil)
+}
+
+// namespaceSelectionChanged returns true if a namespace selector of a listener of any Gateway selects
+// the previous and the current Namespace differently. A nil Namespace doesn't exist, so it is not selected.
+// Invalid selectors don't select any Namespaces.
+func (s *store) namespaceSelectionChanged(prev, cur *v1.Namespace) bool {
+	for _, gw := range s.gateways {
+		for _, l := range gw.Spec.Listeners {
+			if !hasNamespaceSelector(l) {
+				continue
+			}
+
+			selector, err := metav1.LabelSelectorAsSelector(l.AllowedRoutes.Namespaces.Selector)
+			if err != nil {
+				continue
+			}
+
+			prevSelected := prev != nil && selector.Matches(labels.Set(prev.Labels))
+			curSelected := cur != nil && selector.Matches(labels.Set(cur.Labels))
+
+			if prevSelected != curSelected {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func hasNamespaceSelector(l v1beta1.Listener) bool {
+	if l.AllowedRoutes == nil || l.AllowedRoutes.Namespaces == nil {
+		return false
+	}
+
+	ns := l.AllowedRoutes.Namespaces
+
+	return ns.From != nil && *ns.From == v1beta1.NamespacesFromSelector && ns.Selector != nil
+}
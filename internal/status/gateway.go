@@ -48,9 +48,15 @@ func prepareGatewayStatus(gatewayStatus state.GatewayStatus, transitionTime meta
 		})
 	}
 
+	// FIXME(pleshakov) Create the Gateway API conditions for the Gateway resource.
+	var conds []metav1.Condition
+	if len(gatewayStatus.Conditions) > 0 {
+		conds = convertConditions(gatewayStatus.Conditions, gatewayStatus.ObservedGeneration, transitionTime)
+	}
+
 	return v1beta1.GatewayStatus{
 		Listeners:  listenerStatuses,
-		Conditions: nil, // FIXME(pleshakov) Create conditions for the Gateway resource.
+		Conditions: conds,
 	}
 }
 
@@ -47,6 +47,26 @@ func TestPrepareGatewayStatus(t *testing.T) {
 	g.Expect(helpers.Diff(expected, result)).To(BeEmpty())
 }
 
+func TestPrepareGatewayStatusConditions(t *testing.T) {
+	status := state.GatewayStatus{
+		ListenerStatuses:   state.ListenerStatuses{},
+		Conditions:         CreateTestConditions(),
+		ObservedGeneration: 2,
+	}
+
+	transitionTime := metav1.NewTime(time.Now())
+
+	expected := v1beta1.GatewayStatus{
+		Listeners:  []v1beta1.ListenerStatus{},
+		Conditions: CreateExpectedAPIConditions(2, transitionTime),
+	}
+
+	g := NewGomegaWithT(t)
+
+	result := prepareGatewayStatus(status, transitionTime)
+	g.Expect(helpers.Diff(expected, result)).To(BeEmpty())
+}
+
 func TestPrepareIgnoredGatewayStatus(t *testing.T) {
 	status := state.IgnoredGatewayStatus{
 		ObservedGeneration: 1,
@@ -18,14 +18,20 @@ func TestPrepareHTTPRouteStatus(t *testing.T) {
 
 	status := state.HTTPRouteStatus{
 		ObservedGeneration: 1,
-		ParentStatuses: map[string]state.ParentStatus{
-			"parent": {
-				Conditions: CreateTestConditions(),
+		ParentStatuses: []state.ParentStatus{
+			{
+				GatewayNsName: types.NamespacedName{Namespace: "test", Name: "gateway"},
+				SectionName:   "parent",
+				Conditions:    CreateTestConditions(),
+			},
+			{
+				GatewayNsName: types.NamespacedName{Namespace: "other", Name: "missing-gateway"},
+				SectionName:   "parent",
+				Conditions:    CreateTestConditions(),
 			},
 		},
 	}
 
-	gwNsName := types.NamespacedName{Namespace: "test", Name: "gateway"}
 	gatewayCtlrName := "test.example.com"
 
 	transitionTime := metav1.NewTime(time.Now())
@@ -42,9 +48,18 @@ func TestPrepareHTTPRouteStatus(t *testing.T) {
 					ControllerName: v1beta1.GatewayController(gatewayCtlrName),
 					Conditions:     CreateExpectedAPIConditions(1, transitionTime),
 				},
+				{
+					ParentRef: v1beta1.ParentReference{
+						Namespace:   (*v1beta1.Namespace)(helpers.GetStringPointer("other")),
+						Name:        "missing-gateway",
+						SectionName: (*v1beta1.SectionName)(helpers.GetStringPointer("parent")),
+					},
+					ControllerName: v1beta1.GatewayController(gatewayCtlrName),
+					Conditions:     CreateExpectedAPIConditions(1, transitionTime),
+				},
 			},
 		},
 	}
-	result := prepareHTTPRouteStatus(status, gwNsName, gatewayCtlrName, transitionTime)
+	result := prepareHTTPRouteStatus(status, gatewayCtlrName, transitionTime)
 	g.Expect(helpers.Diff(expected, result)).To(BeEmpty())
 }
@@ -0,0 +1,133 @@
+package status
+
+import (
+	"context"
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// ReasonIgnored is used with the Accepted condition (false) of a GatewayClass, Gateway or HTTPRoute resource that
+// NGINX Gateway ignores because of the k8s-gateway.nginx.org/ignore annotation.
+const ReasonIgnored = "Ignored"
+
+// IgnoredStatusUpdater updates the statuses of the resources that NGINX Gateway ignores because of
+// the k8s-gateway.nginx.org/ignore annotation. Because NGINX Gateway handles such resources as if they didn't exist,
+// the Updater doesn't update their statuses.
+type IgnoredStatusUpdater struct {
+	cfg UpdaterConfig
+}
+
+// NewIgnoredStatusUpdater creates a new IgnoredStatusUpdater.
+func NewIgnoredStatusUpdater(cfg UpdaterConfig) *IgnoredStatusUpdater {
+	return &IgnoredStatusUpdater{
+		cfg: cfg,
+	}
+}
+
+// Update sets the Accepted condition (false) with ReasonIgnored and the message in the status of the resource:
+// the condition of a GatewayClass or a Gateway, or the condition of every parentRef of an HTTPRoute.
+// It doesn't make the API call if the status already has the condition for the generation of the resource,
+// so that the status updates don't make the controllers reconcile the resource again and again.
+// Update ignores the resources of other kinds.
+func (upd *IgnoredStatusUpdater) Update(ctx context.Context, obj client.Object, msg string) {
+	// The resource comes from the cache, so we must not change it.
+	obj = obj.DeepCopyObject().(client.Object)
+
+	cond := metav1.Condition{
+		Type:               string(v1beta1.GatewayConditionAccepted),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: obj.GetGeneration(),
+		LastTransitionTime: upd.cfg.Clock.Now(),
+		Reason:             ReasonIgnored,
+		Message:            msg,
+	}
+
+	var changed bool
+
+	switch o := obj.(type) {
+	case *v1beta1.GatewayClass:
+		changed = setIgnoredConditions(&o.Status.Conditions, cond)
+	case *v1beta1.Gateway:
+		changed = setIgnoredConditions(&o.Status.Conditions, cond) || len(o.Status.Listeners) > 0
+		o.Status.Listeners = nil
+	case *v1beta1.HTTPRoute:
+		changed = setIgnoredRouteParents(o, upd.cfg.GatewayCtlrName, cond)
+	default:
+		return
+	}
+
+	if !changed {
+		return
+	}
+
+	if err := upd.cfg.Client.Status().Update(ctx, obj); err != nil {
+		upd.cfg.Logger.Error(err, "Failed to update status of the ignored resource",
+			"namespace", obj.GetNamespace(),
+			"name", obj.GetName(),
+			"kind", reflect.TypeOf(obj).Elem().Name())
+	}
+}
+
+// setIgnoredConditions replaces the conditions with the condition. It returns false if the conditions already
+// consist of the condition, except for the LastTransitionTime.
+func setIgnoredConditions(conds *[]metav1.Condition, cond metav1.Condition) bool {
+	if len(*conds) == 1 && conditionsEqual((*conds)[0], cond) {
+		return false
+	}
+
+	*conds = []metav1.Condition{cond}
+
+	return true
+}
+
+// setIgnoredRouteParents replaces the parent statuses of the HTTPRoute that belong to the controller with a parent
+// status with the condition for every parentRef. The parent statuses of other controllers are preserved.
+// It returns false if the parent statuses already are such, except for the LastTransitionTime.
+func setIgnoredRouteParents(hr *v1beta1.HTTPRoute, ctlrName string, cond metav1.Condition) bool {
+	var current []v1beta1.RouteParentStatus
+	parents := make([]v1beta1.RouteParentStatus, 0, len(hr.Status.Parents)+len(hr.Spec.ParentRefs))
+
+	for _, ps := range hr.Status.Parents {
+		if string(ps.ControllerName) == ctlrName {
+			current = append(current, ps)
+			continue
+		}
+		parents = append(parents, ps)
+	}
+
+	changed := len(current) != len(hr.Spec.ParentRefs)
+
+	for i, ref := range hr.Spec.ParentRefs {
+		if ref.Namespace == nil {
+			ns := v1beta1.Namespace(hr.Namespace)
+			ref.Namespace = &ns
+		}
+
+		if !changed {
+			changed = !reflect.DeepEqual(current[i].ParentRef, ref) ||
+				len(current[i].Conditions) != 1 ||
+				!conditionsEqual(current[i].Conditions[0], cond)
+		}
+
+		parents = append(parents, v1beta1.RouteParentStatus{
+			ParentRef:      ref,
+			ControllerName: v1beta1.GatewayController(ctlrName),
+			Conditions:     []metav1.Condition{cond},
+		})
+	}
+
+	hr.Status.Parents = parents
+
+	return changed
+}
+
+func conditionsEqual(c1, c2 metav1.Condition) bool {
+	return c1.Type == c2.Type &&
+		c1.Status == c2.Status &&
+		c1.ObservedGeneration == c2.ObservedGeneration &&
+		c1.Reason == c2.Reason &&
+		c1.Message == c2.Message
+}
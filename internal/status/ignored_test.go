@@ -0,0 +1,243 @@
+package status_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/helpers"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/status"
+	"github.com/nginxinc/nginx-kubernetes-gateway/internal/status/statusfakes"
+)
+
+var _ = Describe("IgnoredStatusUpdater", func() {
+	const (
+		gatewayCtrlName = "test.example.com"
+		msg             = "ignored"
+	)
+
+	var (
+		updater       *status.IgnoredStatusUpdater
+		k8sClient     client.Client
+		fakeClockTime metav1.Time
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+
+		Expect(v1beta1.AddToScheme(scheme)).Should(Succeed())
+		Expect(apiv1.AddToScheme(scheme)).Should(Succeed())
+
+		k8sClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			Build()
+
+		fakeClockTime = metav1.NewTime(time.Now()).Rfc3339Copy()
+		fakeClock := &statusfakes.FakeClock{}
+		fakeClock.NowReturns(fakeClockTime)
+
+		updater = status.NewIgnoredStatusUpdater(status.UpdaterConfig{
+			GatewayCtlrName: gatewayCtrlName,
+			Client:          k8sClient,
+			Logger:          zap.New(),
+			Clock:           fakeClock,
+		})
+	})
+
+	expectedCondition := func(generation int64, transitionTime metav1.Time) metav1.Condition {
+		return metav1.Condition{
+			Type:               string(v1beta1.GatewayConditionAccepted),
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			LastTransitionTime: transitionTime,
+			Reason:             status.ReasonIgnored,
+			Message:            msg,
+		}
+	}
+
+	It("should set the condition of a Gateway", func() {
+		gw := &v1beta1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:  "test",
+				Name:       "gateway",
+				Generation: 2,
+			},
+			Status: v1beta1.GatewayStatus{
+				Listeners: []v1beta1.ListenerStatus{
+					{
+						Name: "http",
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), gw)).Should(Succeed())
+
+		updater.Update(context.Background(), gw, msg)
+
+		var latestGw v1beta1.Gateway
+		err := k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "test", Name: "gateway"}, &latestGw)
+		Expect(err).Should(Not(HaveOccurred()))
+
+		expectedStatus := v1beta1.GatewayStatus{
+			Conditions: []metav1.Condition{expectedCondition(2, fakeClockTime)},
+		}
+		Expect(helpers.Diff(expectedStatus, latestGw.Status)).To(BeEmpty())
+
+		// the resource from the cache is not changed
+		Expect(gw.Status.Conditions).To(BeEmpty())
+	})
+
+	It("should set the condition of a GatewayClass", func() {
+		gc := &v1beta1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "my-class",
+				Generation: 1,
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), gc)).Should(Succeed())
+
+		updater.Update(context.Background(), gc, msg)
+
+		var latestGc v1beta1.GatewayClass
+		err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "my-class"}, &latestGc)
+		Expect(err).Should(Not(HaveOccurred()))
+
+		expectedConds := []metav1.Condition{expectedCondition(1, fakeClockTime)}
+		Expect(helpers.Diff(expectedConds, latestGc.Status.Conditions)).To(BeEmpty())
+	})
+
+	It("should set the condition of every parentRef of an HTTPRoute", func() {
+		otherCtlrParent := v1beta1.RouteParentStatus{
+			ParentRef: v1beta1.ParentReference{
+				Name: "other-gateway",
+			},
+			ControllerName: "other.example.com",
+		}
+
+		hr := &v1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:  "test",
+				Name:       "route",
+				Generation: 3,
+			},
+			Spec: v1beta1.HTTPRouteSpec{
+				CommonRouteSpec: v1beta1.CommonRouteSpec{
+					ParentRefs: []v1beta1.ParentReference{
+						{
+							Name:        "gateway",
+							SectionName: (*v1beta1.SectionName)(helpers.GetStringPointer("http")),
+						},
+						{
+							Namespace: (*v1beta1.Namespace)(helpers.GetStringPointer("other")),
+							Name:      "other-gateway",
+						},
+					},
+				},
+			},
+			Status: v1beta1.HTTPRouteStatus{
+				RouteStatus: v1beta1.RouteStatus{
+					Parents: []v1beta1.RouteParentStatus{
+						{
+							ParentRef: v1beta1.ParentReference{
+								Name: "gateway",
+							},
+							ControllerName: gatewayCtrlName,
+						},
+						otherCtlrParent,
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), hr)).Should(Succeed())
+
+		updater.Update(context.Background(), hr, msg)
+
+		var latestHr v1beta1.HTTPRoute
+		err := k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "test", Name: "route"}, &latestHr)
+		Expect(err).Should(Not(HaveOccurred()))
+
+		expectedParents := []v1beta1.RouteParentStatus{
+			otherCtlrParent,
+			{
+				ParentRef: v1beta1.ParentReference{
+					Namespace:   (*v1beta1.Namespace)(helpers.GetStringPointer("test")),
+					Name:        "gateway",
+					SectionName: (*v1beta1.SectionName)(helpers.GetStringPointer("http")),
+				},
+				ControllerName: gatewayCtrlName,
+				Conditions:     []metav1.Condition{expectedCondition(3, fakeClockTime)},
+			},
+			{
+				ParentRef: v1beta1.ParentReference{
+					Namespace: (*v1beta1.Namespace)(helpers.GetStringPointer("other")),
+					Name:      "other-gateway",
+				},
+				ControllerName: gatewayCtrlName,
+				Conditions:     []metav1.Condition{expectedCondition(3, fakeClockTime)},
+			},
+		}
+		Expect(helpers.Diff(expectedParents, latestHr.Status.Parents)).To(BeEmpty())
+	})
+
+	It("should not update the status if it already has the condition", func() {
+		transitionTime := metav1.NewTime(fakeClockTime.Add(-time.Hour))
+
+		gw := &v1beta1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:  "test",
+				Name:       "gateway",
+				Generation: 2,
+			},
+			Status: v1beta1.GatewayStatus{
+				Conditions: []metav1.Condition{expectedCondition(2, transitionTime)},
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), gw)).Should(Succeed())
+
+		var createdGw v1beta1.Gateway
+		err := k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "test", Name: "gateway"}, &createdGw)
+		Expect(err).Should(Not(HaveOccurred()))
+
+		updater.Update(context.Background(), &createdGw, msg)
+
+		var latestGw v1beta1.Gateway
+		err = k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "test", Name: "gateway"}, &latestGw)
+		Expect(err).Should(Not(HaveOccurred()))
+
+		Expect(latestGw.ResourceVersion).To(Equal(createdGw.ResourceVersion))
+		expectedConds := []metav1.Condition{expectedCondition(2, transitionTime)}
+		Expect(helpers.Diff(expectedConds, latestGw.Status.Conditions)).To(BeEmpty())
+	})
+
+	It("should not update the status of a resource of another kind", func() {
+		svc := &apiv1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test",
+				Name:      "service",
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), svc)).Should(Succeed())
+
+		var createdSvc apiv1.Service
+		err := k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "test", Name: "service"}, &createdSvc)
+		Expect(err).Should(Not(HaveOccurred()))
+
+		updater.Update(context.Background(), &createdSvc, msg)
+
+		var latestSvc apiv1.Service
+		err = k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "test", Name: "service"}, &latestSvc)
+		Expect(err).Should(Not(HaveOccurred()))
+
+		Expect(latestSvc.ResourceVersion).To(Equal(createdSvc.ResourceVersion))
+	})
+})
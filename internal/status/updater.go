@@ -127,10 +127,8 @@ func (upd *updaterImpl) Update(ctx context.Context, statuses state.Statuses) {
 
 		upd.update(ctx, nsname, &v1beta1.HTTPRoute{}, func(object client.Object) {
 			hr := object.(*v1beta1.HTTPRoute)
-			// statuses.GatewayStatus is never nil when len(statuses.HTTPRouteStatuses) > 0
 			hr.Status = prepareHTTPRouteStatus(
 				rs,
-				statuses.GatewayStatus.NsName,
 				upd.cfg.GatewayCtlrName,
 				upd.cfg.Clock.Now(),
 			)